@@ -1,26 +1,53 @@
 package main
 
 import (
+	"context"
 	"e-procurement/internals/initializer"
+	"e-procurement/internals/migrations"
+	"e-procurement/pkg/config"
 	"fmt"
 	"net/http"
 	"os"
 )
 
 func main(){
-	app, err := initializer.InitializeApp()
+	cfg, err := config.Load()
 	if err != nil {
 		panic(err)
 	}
-	defer app.DB.Close()
-	var serverPort string
-	if serverPort = os.Getenv("PORT"); serverPort == "" {
-		serverPort = "8080" // Default port
+
+	// go run cmd/main.go migrate up|down [steps]|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	app, err := initializer.InitializeApp(cfg)
+	if err != nil {
+		panic(err)
 	}
+	defer app.Close()
 
-	fmt.Printf("Starting server on port %s...\n", serverPort)
-	if err := http.ListenAndServe(":"+serverPort, app.Router); err != nil {
+	fmt.Printf("Starting server on port %s (%s)...\n", cfg.Server.Port, cfg.App.Env)
+	if err := http.ListenAndServe(":"+cfg.Server.Port, app.Router); err != nil {
 		fmt.Printf("Failed to start server: %v\n", err)
 		panic(err)
 	}
-}
\ No newline at end of file
+}
+
+func runMigrate(cfg *config.Config, args []string) error {
+	db, err := initializer.ConnectDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := migrations.NewMigrator(db, cfg.Database.Schema)
+	if err != nil {
+		return err
+	}
+	return migrator.Run(context.Background(), args, os.Stdout)
+}
@@ -0,0 +1,73 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"e-procurement/pkg/validator"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type APIKeyHttp struct {
+	usecase usecases.APIKeyUseCase
+	validator *validator.CustomValidator
+}
+
+func NewAPIKeyHttp(u usecases.APIKeyUseCase) *APIKeyHttp {
+	return &APIKeyHttp{
+		usecase: u,
+		validator: validator.Getvalidator(),
+	}
+}
+
+// Create issues a new API key for the caller, the key is only shown in this response
+func (h *APIKeyHttp) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	key, err := h.usecase.Create(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "api key created, store the key safely, it is not shown again", key, nil)
+}
+
+// List returns the caller's API keys, or those of ?user_id= for admins
+func (h *APIKeyHttp) List(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.usecase.List(r.Context(), r.URL.Query().Get("user_id"))
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "api keys retrieved successfully", keys, nil)
+}
+
+// Revoke disables an API key immediately
+func (h *APIKeyHttp) Revoke(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid api key ID format")
+		return
+	}
+
+	if err := h.usecase.Revoke(r.Context(), id); err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "api key revoked successfully", nil, nil)
+}
@@ -0,0 +1,309 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"e-procurement/pkg/validator"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ApprovalHttp struct {
+	usecase   usecases.ApprovalUseCase
+	validator *validator.CustomValidator
+}
+
+func NewApprovalHttp(u usecases.ApprovalUseCase) *ApprovalHttp {
+	return &ApprovalHttp{
+		usecase:   u,
+		validator: validator.Getvalidator(),
+	}
+}
+
+// CreateRule stores a new approval rule
+func (h *ApprovalHttp) CreateRule(w http.ResponseWriter, r *http.Request) {
+	var req models.ApprovalRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rule, err := h.usecase.CreateRule(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "approval rule created successfully", rule, nil)
+}
+
+// ListRules returns the approval rules, filtered by ?document_type=
+func (h *ApprovalHttp) ListRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.usecase.ListRules(r.Context(), r.URL.Query().Get("document_type"))
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "approval rules retrieved successfully", rules, nil)
+}
+
+// GetRule returns a single approval rule with its steps
+func (h *ApprovalHttp) GetRule(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid approval rule ID format")
+		return
+	}
+
+	rule, err := h.usecase.GetRule(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "approval rule retrieved successfully", rule, nil)
+}
+
+// UpdateRule replaces an approval rule and its steps
+func (h *ApprovalHttp) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid approval rule ID format")
+		return
+	}
+
+	var req models.ApprovalRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rule, err := h.usecase.UpdateRule(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "approval rule updated successfully", rule, nil)
+}
+
+// DeleteRule removes an approval rule
+func (h *ApprovalHttp) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid approval rule ID format")
+		return
+	}
+
+	if err := h.usecase.DeleteRule(r.Context(), id); err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "approval rule deleted successfully", nil, nil)
+}
+
+// List returns a page of approval requests, filtered by ?document_type=, ?document_id= and ?status=
+func (h *ApprovalHttp) List(w http.ResponseWriter, r *http.Request) {
+	limit, page := pageParams(r)
+	query := r.URL.Query()
+	filter := models.ApprovalFilter{
+		DocumentType: query.Get("document_type"),
+		DocumentID:   query.Get("document_id"),
+		Status:       query.Get("status"),
+	}
+	if filter.DocumentID != "" && !h.validator.IsValidUUID(filter.DocumentID) {
+		response.Error(w, http.StatusBadRequest, "Invalid document ID format")
+		return
+	}
+
+	requests, count, err := h.usecase.List(r.Context(), filter, limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "approvals retrieved successfully", requests, pageMeta(limit, page, count))
+}
+
+// Pending returns a page of the approval steps waiting for the caller
+func (h *ApprovalHttp) Pending(w http.ResponseWriter, r *http.Request) {
+	limit, page := pageParams(r)
+
+	pending, count, err := h.usecase.Pending(r.Context(), limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "pending approvals retrieved successfully", pending, pageMeta(limit, page, count))
+}
+
+// Get returns a single approval request with its steps
+func (h *ApprovalHttp) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.approvalID(w, r)
+	if !ok {
+		return
+	}
+
+	request, err := h.usecase.Get(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "approval retrieved successfully", request, nil)
+}
+
+// Approve signs off the caller's step of an approval
+func (h *ApprovalHttp) Approve(w http.ResponseWriter, r *http.Request) {
+	id, req, ok := h.decision(w, r)
+	if !ok {
+		return
+	}
+
+	request, err := h.usecase.Approve(r.Context(), id, req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "approval step approved successfully", request, nil)
+}
+
+// Reject declines the document of an approval
+func (h *ApprovalHttp) Reject(w http.ResponseWriter, r *http.Request) {
+	id, req, ok := h.decision(w, r)
+	if !ok {
+		return
+	}
+
+	request, err := h.usecase.Reject(r.Context(), id, req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "approval rejected successfully", request, nil)
+}
+
+// Return sends the document of an approval back for revision
+func (h *ApprovalHttp) Return(w http.ResponseWriter, r *http.Request) {
+	id, req, ok := h.decision(w, r)
+	if !ok {
+		return
+	}
+
+	request, err := h.usecase.Return(r.Context(), id, req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "approval returned for revision successfully", request, nil)
+}
+
+// Complete applies a completed approval to its document again
+func (h *ApprovalHttp) Complete(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.approvalID(w, r)
+	if !ok {
+		return
+	}
+
+	request, err := h.usecase.Complete(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "approval applied to its document successfully", request, nil)
+}
+
+// Delegate hands the caller's approvals to another member for a period
+func (h *ApprovalHttp) Delegate(w http.ResponseWriter, r *http.Request) {
+	var req models.ApprovalDelegationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	delegation, err := h.usecase.Delegate(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "approval delegation created successfully", delegation, nil)
+}
+
+// ListDelegations returns the delegations given or received by the caller
+func (h *ApprovalHttp) ListDelegations(w http.ResponseWriter, r *http.Request) {
+	delegations, err := h.usecase.ListDelegations(r.Context())
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "approval delegations retrieved successfully", delegations, nil)
+}
+
+// RevokeDelegation ends a delegation given by the caller
+func (h *ApprovalHttp) RevokeDelegation(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid approval delegation ID format")
+		return
+	}
+
+	if err := h.usecase.RevokeDelegation(r.Context(), id); err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "approval delegation revoked successfully", nil, nil)
+}
+
+// approvalID reads the approval ID from the path, writing a 400 when it is malformed
+func (h *ApprovalHttp) approvalID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid approval ID format")
+		return "", false
+	}
+	return id, true
+}
+
+// decision reads the approval ID and the optional decision comment
+func (h *ApprovalHttp) decision(w http.ResponseWriter, r *http.Request) (string, *models.ApprovalDecisionRequest, bool) {
+	id, ok := h.approvalID(w, r)
+	if !ok {
+		return "", nil, false
+	}
+	var req models.ApprovalDecisionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return "", nil, false
+		}
+	}
+	return id, &req, true
+}
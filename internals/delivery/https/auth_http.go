@@ -6,6 +6,7 @@ import (
 	response "e-procurement/pkg/responses"
 	"e-procurement/pkg/validator"
 	"encoding/json"
+	"net"
 	"net/http"
 )
 
@@ -35,13 +36,18 @@ func (h *AuthHttp) Authentication(w http.ResponseWriter, r *http.Request){
 		return
 	}
 
-	user,token, err := h.usecase.Authenticate(r.Context(),&req)
+	result, err := h.usecase.Authenticate(r.Context(),&req, clientIP(r))
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
+		return
+	}
+	if result.Challenge != nil {
+		// second step through POST /auth/mfa/verify
+		response.Success(w, "two-factor authentication required", result.Challenge, nil)
 		return
 	}
 	
-	response.Success(w, "authenticated successfully", user,nil,token)
+	response.SuccessWithTokens(w, "authenticated successfully", result.User, result.Tokens.AccessToken, result.Tokens.RefreshToken, result.Tokens.ExpiresIn)
 }
 
 func (h *AuthHttp) Create(w http.ResponseWriter, r *http.Request) {
@@ -60,9 +66,84 @@ func (h *AuthHttp) Create(w http.ResponseWriter, r *http.Request) {
 	result, err := h.usecase.Create(r.Context(),&userReq)
 
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
 	response.Success(w, "User created successfully", result, nil)
-}
\ No newline at end of file
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token
+func (h *AuthHttp) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tokens, err := h.usecase.Refresh(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.SuccessWithTokens(w, "token refreshed successfully", nil, tokens.AccessToken, tokens.RefreshToken, tokens.ExpiresIn)
+}
+
+// SwitchOrganization issues a token pair for another organization of the caller
+func (h *AuthHttp) SwitchOrganization(w http.ResponseWriter, r *http.Request) {
+	var req models.SwitchOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.usecase.SwitchOrganization(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.SuccessWithTokens(w, "organization switched successfully", result.User, result.Tokens.AccessToken, result.Tokens.RefreshToken, result.Tokens.ExpiresIn)
+}
+
+// Logout revokes the refresh token family of the current login
+func (h *AuthHttp) Logout(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.usecase.Logout(r.Context(), &req); err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "logged out successfully", nil, nil)
+}
+
+// clientIP returns the address of the caller. RemoteAddr is rewritten from
+// X-Forwarded-For / X-Real-IP by the router when the server runs behind a trusted proxy.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
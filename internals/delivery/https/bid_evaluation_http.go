@@ -0,0 +1,184 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"e-procurement/pkg/validator"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type BidEvaluationHttp struct {
+	usecase   usecases.BidEvaluationUseCase
+	validator *validator.CustomValidator
+}
+
+func NewBidEvaluationHttp(u usecases.BidEvaluationUseCase) *BidEvaluationHttp {
+	return &BidEvaluationHttp{
+		usecase:   u,
+		validator: validator.Getvalidator(),
+	}
+}
+
+// Criteria returns the evaluation criteria of an RFQ
+func (h *BidEvaluationHttp) Criteria(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	criteria, err := h.usecase.Criteria(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "evaluation criteria retrieved successfully", criteria, nil)
+}
+
+// SetCriteria replaces the evaluation criteria of an RFQ
+func (h *BidEvaluationHttp) SetCriteria(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.EvaluationCriteriaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	criteria, err := h.usecase.SetCriteria(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "evaluation criteria updated successfully", criteria, nil)
+}
+
+// ScoreQuotation stores the vendor rating and technical score of a quotation
+func (h *BidEvaluationHttp) ScoreQuotation(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+	quotationID := chi.URLParam(r, "quotationID")
+	if !h.validator.IsValidUUID(quotationID) {
+		response.Error(w, http.StatusBadRequest, "Invalid quotation ID format")
+		return
+	}
+
+	var req models.QuotationScoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	score, err := h.usecase.ScoreQuotation(r.Context(), id, quotationID, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "quotation scored successfully", score, nil)
+}
+
+// Evaluate ranks the quotations of an RFQ, per whole RFQ or per line with ?mode=
+func (h *BidEvaluationHttp) Evaluate(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	evaluation, err := h.usecase.Evaluate(r.Context(), id, r.URL.Query().Get("mode"))
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "bid evaluation retrieved successfully", evaluation, nil)
+}
+
+// Award records the winners of an RFQ
+func (h *BidEvaluationHttp) Award(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.AwardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	award, err := h.usecase.Award(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "rfq awarded successfully", award, nil)
+}
+
+// GetAward returns the award decision of an RFQ
+func (h *BidEvaluationHttp) GetAward(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	award, err := h.usecase.GetAward(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "rfq award retrieved successfully", award, nil)
+}
+
+// CreatePurchaseOrders drafts the purchase orders of an RFQ award
+func (h *BidEvaluationHttp) CreatePurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	award, err := h.usecase.CreatePurchaseOrders(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase orders created successfully", award, nil)
+}
+
+// rfqID reads the RFQ ID from the path, writing a 400 when it is malformed
+func (h *BidEvaluationHttp) rfqID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid RFQ ID format")
+		return "", false
+	}
+	return id, true
+}
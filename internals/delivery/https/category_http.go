@@ -42,7 +42,7 @@ func (h *CategoryHttp) CreateCategory(w http.ResponseWriter, r *http.Request) {
 	// call usecase to create category
 	categoryResponse, err := h.categoryUsecase.CreateCategoryUsecase(r.Context(), &categoryReq)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -62,7 +62,7 @@ func (h *CategoryHttp) GetCategory(w http.ResponseWriter, r *http.Request) {
 
 	categories,count, err := h.categoryUsecase.GetAllCategoriesUsecase(r.Context(), limit, page)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 	meta := &response.Meta{
@@ -97,7 +97,7 @@ func (h *CategoryHttp) GetCategoryByID(w http.ResponseWriter, r *http.Request) {
 	}
 	category, err := h.categoryUsecase.GetCategoryByIDUsecase(r.Context(), id)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -141,7 +141,7 @@ func (h *CategoryHttp) UpdateCategory(w http.ResponseWriter, r *http.Request) {
 
 	categoryResponse, err := h.categoryUsecase.UpdateCategoryUsecase(r.Context(), id, &categoryReq)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -172,7 +172,7 @@ func (h *CategoryHttp) DeleteCategory(w http.ResponseWriter, r *http.Request) {
 
 	err := h.categoryUsecase.DeleteCategoryUsecase(r.Context(), id)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
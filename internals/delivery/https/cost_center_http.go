@@ -0,0 +1,120 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"e-procurement/pkg/validator"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type CostCenterHttp struct {
+	usecase   usecases.CostCenterUseCase
+	validator *validator.CustomValidator
+}
+
+func NewCostCenterHttp(u usecases.CostCenterUseCase) *CostCenterHttp {
+	return &CostCenterHttp{
+		usecase:   u,
+		validator: validator.Getvalidator(),
+	}
+}
+
+// Create stores a new cost center in the current organization
+func (h *CostCenterHttp) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCostCenterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	costCenter, err := h.usecase.Create(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "cost center created successfully", costCenter, nil)
+}
+
+// List returns a page of cost centers
+func (h *CostCenterHttp) List(w http.ResponseWriter, r *http.Request) {
+	limit, page := pageParams(r)
+
+	costCenters, count, err := h.usecase.List(r.Context(), limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "cost centers retrieved successfully", costCenters, pageMeta(limit, page, count))
+}
+
+// Get returns a single cost center
+func (h *CostCenterHttp) Get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid cost center ID format")
+		return
+	}
+
+	costCenter, err := h.usecase.Get(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "cost center retrieved successfully", costCenter, nil)
+}
+
+// Update changes a cost center
+func (h *CostCenterHttp) Update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid cost center ID format")
+		return
+	}
+
+	var req models.UpdateCostCenterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	costCenter, err := h.usecase.Update(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "cost center updated successfully", costCenter, nil)
+}
+
+// Delete removes an unused cost center
+func (h *CostCenterHttp) Delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid cost center ID format")
+		return
+	}
+
+	if err := h.usecase.Delete(r.Context(), id); err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "cost center deleted successfully", nil, nil)
+}
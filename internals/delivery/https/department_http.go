@@ -0,0 +1,184 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"e-procurement/pkg/validator"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type DepartmentHttp struct {
+	usecase   usecases.DepartmentUseCase
+	validator *validator.CustomValidator
+}
+
+func NewDepartmentHttp(u usecases.DepartmentUseCase) *DepartmentHttp {
+	return &DepartmentHttp{
+		usecase:   u,
+		validator: validator.Getvalidator(),
+	}
+}
+
+// Create stores a new department in the current organization
+func (h *DepartmentHttp) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.DepartmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	department, err := h.usecase.Create(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "department created successfully", department, nil)
+}
+
+// List returns every department, nested below their parents with ?view=tree
+func (h *DepartmentHttp) List(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("view") == "tree" {
+		tree, err := h.usecase.Tree(r.Context())
+		if err != nil {
+			response.FromError(w, err)
+			return
+		}
+		response.Success(w, "departments retrieved successfully", tree, nil)
+		return
+	}
+
+	departments, err := h.usecase.List(r.Context())
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "departments retrieved successfully", departments, nil)
+}
+
+// Get returns a single department
+func (h *DepartmentHttp) Get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid department ID format")
+		return
+	}
+
+	department, err := h.usecase.Get(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "department retrieved successfully", department, nil)
+}
+
+// Update replaces a department
+func (h *DepartmentHttp) Update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid department ID format")
+		return
+	}
+
+	var req models.DepartmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	department, err := h.usecase.Update(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "department updated successfully", department, nil)
+}
+
+// Delete removes a department without sub departments or members
+func (h *DepartmentHttp) Delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid department ID format")
+		return
+	}
+
+	if err := h.usecase.Delete(r.Context(), id); err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "department deleted successfully", nil, nil)
+}
+
+// GetAssignment returns the department of a member of the current organization
+func (h *DepartmentHttp) GetAssignment(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	if !h.validator.IsValidUUID(userID) {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	assignment, err := h.usecase.GetAssignment(r.Context(), userID)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "department assignment retrieved successfully", assignment, nil)
+}
+
+// Assign places a member of the current organization in a department
+func (h *DepartmentHttp) Assign(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	if !h.validator.IsValidUUID(userID) {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	var req models.AssignDepartmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	assignment, err := h.usecase.Assign(r.Context(), userID, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "department assigned successfully", assignment, nil)
+}
+
+// PurchaseDefaults returns the department and cost center purchases of the caller are charged to
+func (h *DepartmentHttp) PurchaseDefaults(w http.ResponseWriter, r *http.Request) {
+	defaults, err := h.usecase.MyPurchaseDefaults(r.Context())
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase defaults retrieved successfully", defaults, nil)
+}
@@ -0,0 +1,59 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"e-procurement/pkg/validator"
+	"encoding/json"
+	"net/http"
+)
+
+type EmailVerificationHttp struct {
+	usecase usecases.EmailVerificationUseCase
+	validator *validator.CustomValidator
+}
+
+func NewEmailVerificationHttp(u usecases.EmailVerificationUseCase) *EmailVerificationHttp {
+	return &EmailVerificationHttp{
+		usecase: u,
+		validator: validator.Getvalidator(),
+	}
+}
+
+// Verify handles the link from the verification email, the token comes as a query parameter
+func (h *EmailVerificationHttp) Verify(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		response.Error(w, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	if err := h.usecase.Verify(r.Context(), token); err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "email verified successfully", nil, nil)
+}
+
+// Resend sends the verification email again, it answers the same for unknown emails
+func (h *EmailVerificationHttp) Resend(w http.ResponseWriter, r *http.Request) {
+	var req models.ResendVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.usecase.Resend(r.Context(), &req); err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "if the email is registered and not verified yet, a verification link has been sent", nil, nil)
+}
@@ -0,0 +1,136 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"encoding/json"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"e-procurement/pkg/validator"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type GoodsReceiptHttp struct {
+	usecase   usecases.GoodsReceiptUseCase
+	validator *validator.CustomValidator
+}
+
+func NewGoodsReceiptHttp(u usecases.GoodsReceiptUseCase) *GoodsReceiptHttp {
+	return &GoodsReceiptHttp{
+		usecase:   u,
+		validator: validator.Getvalidator(),
+	}
+}
+
+// Record stores the goods received against a purchase order
+func (h *GoodsReceiptHttp) Record(w http.ResponseWriter, r *http.Request) {
+	purchaseOrderID, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.GoodsReceiptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	receipt, err := h.usecase.Record(r.Context(), purchaseOrderID, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "goods receipt recorded successfully", receipt, nil)
+}
+
+// ListByPurchaseOrder returns every goods receipt of a purchase order with its lines
+func (h *GoodsReceiptHttp) ListByPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	purchaseOrderID, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	receipts, err := h.usecase.ListByPurchaseOrder(r.Context(), purchaseOrderID)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "goods receipts retrieved successfully", receipts, nil)
+}
+
+// ReceiptSummary returns the received and outstanding quantity of every line of a purchase order
+func (h *GoodsReceiptHttp) ReceiptSummary(w http.ResponseWriter, r *http.Request) {
+	purchaseOrderID, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	summary, err := h.usecase.ReceiptSummary(r.Context(), purchaseOrderID)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "receipt summary retrieved successfully", summary, nil)
+}
+
+// List returns a page of goods receipts, filtered by ?purchase_order_id= and ?vendor_id=
+func (h *GoodsReceiptHttp) List(w http.ResponseWriter, r *http.Request) {
+	limit, page := pageParams(r)
+	query := r.URL.Query()
+	filter := models.GoodsReceiptFilter{
+		PurchaseOrderID: query.Get("purchase_order_id"),
+		VendorID:        query.Get("vendor_id"),
+	}
+	if filter.PurchaseOrderID != "" && !h.validator.IsValidUUID(filter.PurchaseOrderID) {
+		response.Error(w, http.StatusBadRequest, "Invalid purchase order ID format")
+		return
+	}
+	if filter.VendorID != "" && !h.validator.IsValidUUID(filter.VendorID) {
+		response.Error(w, http.StatusBadRequest, "Invalid vendor ID format")
+		return
+	}
+
+	receipts, count, err := h.usecase.List(r.Context(), filter, limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "goods receipts retrieved successfully", receipts, pageMeta(limit, page, count))
+}
+
+// Get returns a single goods receipt with its lines
+func (h *GoodsReceiptHttp) Get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid goods receipt ID format")
+		return
+	}
+
+	receipt, err := h.usecase.Get(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "goods receipt retrieved successfully", receipt, nil)
+}
+
+// purchaseOrderID reads the purchase order ID from the path, writing a 400 when it is malformed
+func (h *GoodsReceiptHttp) purchaseOrderID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid purchase order ID format")
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,231 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"encoding/json"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"e-procurement/pkg/validator"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type InvoiceHttp struct {
+	usecase   usecases.InvoiceUseCase
+	validator *validator.CustomValidator
+}
+
+func NewInvoiceHttp(u usecases.InvoiceUseCase) *InvoiceHttp {
+	return &InvoiceHttp{
+		usecase:   u,
+		validator: validator.Getvalidator(),
+	}
+}
+
+// List returns a page of invoices, filtered by ?status=, ?vendor_id= and ?purchase_order_id=
+func (h *InvoiceHttp) List(w http.ResponseWriter, r *http.Request) {
+	limit, page := pageParams(r)
+	query := r.URL.Query()
+	filter := models.InvoiceFilter{
+		Status:          query.Get("status"),
+		VendorID:        query.Get("vendor_id"),
+		PurchaseOrderID: query.Get("purchase_order_id"),
+	}
+	if filter.VendorID != "" && !h.validator.IsValidUUID(filter.VendorID) {
+		response.Error(w, http.StatusBadRequest, "Invalid vendor ID format")
+		return
+	}
+	if filter.PurchaseOrderID != "" && !h.validator.IsValidUUID(filter.PurchaseOrderID) {
+		response.Error(w, http.StatusBadRequest, "Invalid purchase order ID format")
+		return
+	}
+
+	invoices, count, err := h.usecase.List(r.Context(), filter, limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "invoices retrieved successfully", invoices, pageMeta(limit, page, count))
+}
+
+// Exceptions returns the queue of invoices with match exceptions waiting for review, oldest first
+func (h *InvoiceHttp) Exceptions(w http.ResponseWriter, r *http.Request) {
+	limit, page := pageParams(r)
+	invoices, count, err := h.usecase.Exceptions(r.Context(), limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "invoice exceptions retrieved successfully", invoices, pageMeta(limit, page, count))
+}
+
+// Get returns a single invoice with its lines and match result
+func (h *InvoiceHttp) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.invoiceID(w, r)
+	if !ok {
+		return
+	}
+
+	invoice, err := h.usecase.Get(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "invoice retrieved successfully", invoice, nil)
+}
+
+// Match runs the three-way match of an invoice again
+func (h *InvoiceHttp) Match(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.invoiceID(w, r)
+	if !ok {
+		return
+	}
+
+	invoice, err := h.usecase.Match(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "invoice matched successfully", invoice, nil)
+}
+
+// Approve releases an invoice for payment
+func (h *InvoiceHttp) Approve(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.invoiceID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.InvoiceApproveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	invoice, err := h.usecase.Approve(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "invoice approved successfully", invoice, nil)
+}
+
+// Hold parks an invoice with a reason
+func (h *InvoiceHttp) Hold(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.invoiceID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.InvoiceReasonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	invoice, err := h.usecase.Hold(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "invoice put on hold successfully", invoice, nil)
+}
+
+// Dispute sends an invoice back to the vendor with a reason
+func (h *InvoiceHttp) Dispute(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.invoiceID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.InvoiceReasonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	invoice, err := h.usecase.Dispute(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "invoice disputed successfully", invoice, nil)
+}
+
+// Submit stores an invoice of the caller's vendor
+func (h *InvoiceHttp) Submit(w http.ResponseWriter, r *http.Request) {
+	var req models.InvoiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	invoice, err := h.usecase.Submit(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "invoice submitted successfully", invoice, nil)
+}
+
+// VendorInvoices returns a page of the caller's vendor invoices, filtered by ?status=
+func (h *InvoiceHttp) VendorInvoices(w http.ResponseWriter, r *http.Request) {
+	limit, page := pageParams(r)
+	invoices, count, err := h.usecase.VendorInvoices(r.Context(), r.URL.Query().Get("status"), limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "invoices retrieved successfully", invoices, pageMeta(limit, page, count))
+}
+
+// VendorInvoice returns a single invoice of the caller's vendor
+func (h *InvoiceHttp) VendorInvoice(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.invoiceID(w, r)
+	if !ok {
+		return
+	}
+
+	invoice, err := h.usecase.VendorInvoice(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "invoice retrieved successfully", invoice, nil)
+}
+
+// invoiceID reads the invoice ID from the path, writing a 400 when it is malformed
+func (h *InvoiceHttp) invoiceID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid invoice ID format")
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,48 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"net/http"
+	"strconv"
+)
+
+type LoginAttemptHttp struct {
+	usecase usecases.LoginThrottle
+}
+
+func NewLoginAttemptHttp(u usecases.LoginThrottle) *LoginAttemptHttp {
+	return &LoginAttemptHttp{usecase: u}
+}
+
+// List returns the login audit trail, filtered by the email, ip and outcome query parameters
+func (h *LoginAttemptHttp) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10 // default limit
+	}
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1 // default page
+	}
+	filter := models.LoginAttemptFilter{
+		Email:     query.Get("email"),
+		IPAddress: query.Get("ip"),
+		Outcome:   query.Get("outcome"),
+	}
+
+	attempts, count, err := h.usecase.List(r.Context(), filter, limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	meta := &response.Meta{
+		Page:    page,
+		PerPage: limit,
+		HasMore: (page * limit) < count,
+	}
+	response.Success(w, "login attempts retrieved successfully", attempts, meta)
+}
@@ -0,0 +1,77 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"e-procurement/pkg/validator"
+	"encoding/json"
+	"net/http"
+)
+
+type MFAHttp struct {
+	usecase usecases.MFAUseCase
+	validator *validator.CustomValidator
+}
+
+func NewMFAHttp(u usecases.MFAUseCase) *MFAHttp {
+	return &MFAHttp{
+		usecase: u,
+		validator: validator.Getvalidator(),
+	}
+}
+
+// Enroll returns a new TOTP secret and its otpauth URI for the caller
+func (h *MFAHttp) Enroll(w http.ResponseWriter, r *http.Request) {
+	result, err := h.usecase.Enroll(r.Context())
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "scan the otpauth uri and confirm with a code", result, nil)
+}
+
+// Confirm activates the enrollment and returns the recovery codes with a new token pair
+func (h *MFAHttp) Confirm(w http.ResponseWriter, r *http.Request) {
+	var req models.MFAConfirmRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	codes, tokens, err := h.usecase.Confirm(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.SuccessWithTokens(w, "two-factor authentication enabled, store the recovery codes safely", codes, tokens.AccessToken, tokens.RefreshToken, tokens.ExpiresIn)
+}
+
+// Verify is the second login step for users with two-factor authentication
+func (h *MFAHttp) Verify(w http.ResponseWriter, r *http.Request) {
+	var req models.MFAVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.usecase.Verify(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.SuccessWithTokens(w, "authenticated successfully", result.User, result.Tokens.AccessToken, result.Tokens.RefreshToken, result.Tokens.ExpiresIn)
+}
@@ -0,0 +1,133 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"e-procurement/pkg/validator"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type OrganizationHttp struct {
+	usecase   usecases.OrganizationUseCase
+	validator *validator.CustomValidator
+}
+
+func NewOrganizationHttp(u usecases.OrganizationUseCase) *OrganizationHttp {
+	return &OrganizationHttp{
+		usecase:   u,
+		validator: validator.Getvalidator(),
+	}
+}
+
+// Create stores a new organization, the caller becomes its admin
+func (h *OrganizationHttp) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	org, err := h.usecase.Create(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "organization created, switch to it to start working there", org, nil)
+}
+
+// ListMine returns the organizations of the caller
+func (h *OrganizationHttp) ListMine(w http.ResponseWriter, r *http.Request) {
+	memberships, err := h.usecase.ListMine(r.Context())
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "organizations retrieved successfully", memberships, nil)
+}
+
+// ListMembers returns the members of the current organization
+func (h *OrganizationHttp) ListMembers(w http.ResponseWriter, r *http.Request) {
+	members, err := h.usecase.ListMembers(r.Context())
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "organization members retrieved successfully", members, nil)
+}
+
+// AddMember adds a registered user to the current organization
+func (h *OrganizationHttp) AddMember(w http.ResponseWriter, r *http.Request) {
+	var req models.AddOrganizationMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	membership, err := h.usecase.AddMember(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "member added successfully", membership, nil)
+}
+
+// UpdateMember changes the role of a member of the current organization
+func (h *OrganizationHttp) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	if !h.validator.IsValidUUID(userID) {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	var req models.UpdateOrganizationMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.usecase.UpdateMember(r.Context(), userID, &req); err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "member role updated successfully", nil, nil)
+}
+
+// RemoveMember removes a user from the current organization
+func (h *OrganizationHttp) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	if !h.validator.IsValidUUID(userID) {
+		response.Error(w, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	if err := h.usecase.RemoveMember(r.Context(), userID); err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "member removed successfully", nil, nil)
+}
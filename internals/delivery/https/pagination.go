@@ -0,0 +1,29 @@
+package https
+
+import (
+	response "e-procurement/pkg/responses"
+	"net/http"
+	"strconv"
+)
+
+// pageParams reads the limit and page query parameters of a list request
+func pageParams(r *http.Request) (int, int) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10 // default limit
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1 // default page
+	}
+	return limit, page
+}
+
+// pageMeta builds the pagination metadata of a list response
+func pageMeta(limit, page, count int) *response.Meta {
+	return &response.Meta{
+		Page:    page,
+		PerPage: limit,
+		HasMore: (page * limit) < count,
+	}
+}
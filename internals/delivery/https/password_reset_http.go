@@ -0,0 +1,64 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"e-procurement/pkg/validator"
+	"encoding/json"
+	"net/http"
+)
+
+type PasswordResetHttp struct {
+	usecase usecases.PasswordResetUseCase
+	validator *validator.CustomValidator
+}
+
+func NewPasswordResetHttp(u usecases.PasswordResetUseCase) *PasswordResetHttp {
+	return &PasswordResetHttp{
+		usecase: u,
+		validator: validator.Getvalidator(),
+	}
+}
+
+// ForgotPassword mails a reset link, it answers the same for unknown emails
+func (h *PasswordResetHttp) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.usecase.ForgotPassword(r.Context(), &req); err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "if the email is registered, a password reset link has been sent", nil, nil)
+}
+
+// ResetPassword sets a new password using the token from the reset email
+func (h *PasswordResetHttp) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.usecase.ResetPassword(r.Context(), &req); err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "password has been reset, please log in again", nil, nil)
+}
@@ -0,0 +1,218 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"encoding/json"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"e-procurement/pkg/validator"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type PaymentHttp struct {
+	usecase   usecases.PaymentUseCase
+	validator *validator.CustomValidator
+}
+
+func NewPaymentHttp(u usecases.PaymentUseCase) *PaymentHttp {
+	return &PaymentHttp{
+		usecase:   u,
+		validator: validator.Getvalidator(),
+	}
+}
+
+// Schedule returns a page of approved invoices waiting for payment, filtered by ?vendor_id= and ?due_by=,
+// discounts are worked out for the planned payment date ?as_of= (today when empty)
+func (h *PaymentHttp) Schedule(w http.ResponseWriter, r *http.Request) {
+	limit, page := pageParams(r)
+	query := r.URL.Query()
+	filter := models.PaymentScheduleFilter{VendorID: query.Get("vendor_id")}
+	if filter.VendorID != "" && !h.validator.IsValidUUID(filter.VendorID) {
+		response.Error(w, http.StatusBadRequest, "Invalid vendor ID format")
+		return
+	}
+	if dueBy := query.Get("due_by"); dueBy != "" {
+		date, err := time.Parse(time.DateOnly, dueBy)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "due_by must be formatted as YYYY-MM-DD")
+			return
+		}
+		filter.DueBy = &date
+	}
+	if asOf := query.Get("as_of"); asOf != "" {
+		date, err := time.Parse(time.DateOnly, asOf)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "as_of must be formatted as YYYY-MM-DD")
+			return
+		}
+		filter.AsOf = date
+	}
+
+	entries, count, err := h.usecase.Schedule(r.Context(), filter, limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "payment schedule retrieved successfully", entries, pageMeta(limit, page, count))
+}
+
+// CreateBatch collects approved invoices in a draft payment batch
+func (h *PaymentHttp) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	var req models.PaymentBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	batch, err := h.usecase.CreateBatch(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "payment batch created successfully", batch, nil)
+}
+
+// ListBatches returns a page of payment batches, filtered by ?status= and ?vendor_id=
+func (h *PaymentHttp) ListBatches(w http.ResponseWriter, r *http.Request) {
+	limit, page := pageParams(r)
+	query := r.URL.Query()
+	filter := models.PaymentBatchFilter{
+		Status:   query.Get("status"),
+		VendorID: query.Get("vendor_id"),
+	}
+	if filter.VendorID != "" && !h.validator.IsValidUUID(filter.VendorID) {
+		response.Error(w, http.StatusBadRequest, "Invalid vendor ID format")
+		return
+	}
+
+	batches, count, err := h.usecase.ListBatches(r.Context(), filter, limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "payment batches retrieved successfully", batches, pageMeta(limit, page, count))
+}
+
+// GetBatch returns a single payment batch with its invoices
+func (h *PaymentHttp) GetBatch(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.batchID(w, r)
+	if !ok {
+		return
+	}
+
+	batch, err := h.usecase.GetBatch(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "payment batch retrieved successfully", batch, nil)
+}
+
+// ExportBatch writes the bank file of a payment batch
+func (h *PaymentHttp) ExportBatch(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.batchID(w, r)
+	if !ok {
+		return
+	}
+
+	batch, err := h.usecase.ExportBatch(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "payment batch exported successfully", batch, nil)
+}
+
+// ConfirmBatch records that the bank paid a payment batch
+func (h *PaymentHttp) ConfirmBatch(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.batchID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.PaymentBatchConfirmRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	batch, err := h.usecase.ConfirmBatch(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "payment batch confirmed successfully", batch, nil)
+}
+
+// CancelBatch drops a payment batch that was not paid
+func (h *PaymentHttp) CancelBatch(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.batchID(w, r)
+	if !ok {
+		return
+	}
+
+	batch, err := h.usecase.CancelBatch(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "payment batch cancelled successfully", batch, nil)
+}
+
+// VendorRemittances returns a page of the remittance advice of the caller's vendor
+func (h *PaymentHttp) VendorRemittances(w http.ResponseWriter, r *http.Request) {
+	limit, page := pageParams(r)
+	remittances, count, err := h.usecase.VendorRemittances(r.Context(), limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "remittances retrieved successfully", remittances, pageMeta(limit, page, count))
+}
+
+// VendorRemittance returns the remittance advice of a paid batch for the caller's vendor
+func (h *PaymentHttp) VendorRemittance(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.batchID(w, r)
+	if !ok {
+		return
+	}
+
+	remittance, err := h.usecase.VendorRemittance(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "remittance retrieved successfully", remittance, nil)
+}
+
+// batchID reads the payment batch ID from the path, writing a 400 when it is malformed
+func (h *PaymentHttp) batchID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid payment batch ID format")
+		return "", false
+	}
+	return id, true
+}
@@ -34,7 +34,7 @@ func(h *ProductHttp) CreateProduct(w http.ResponseWriter, r *http.Request) {
 	// call usecase to create product
 	productResponse, err := h.productusecase.CreateProducUsecase(r.Context(), &productReq)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -55,7 +55,7 @@ func(h *ProductHttp) GetAllProducts(w http.ResponseWriter, r *http.Request) {
 	}
 	products, count, err := h.productusecase.GetAllProducts(r.Context(), limit, page)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -98,7 +98,7 @@ func(h *ProductHttp) GetProductsByCategory(w http.ResponseWriter, r *http.Reques
 
 	products, count, err := h.productusecase.GetProductsByCategory(r.Context(), category, limit, page)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -132,7 +132,7 @@ func(h *ProductHttp) GetProductByID(w http.ResponseWriter, r *http.Request) {
 
 	product, err := h.productusecase.GetProductByID(r.Context(), id)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -173,7 +173,7 @@ func(h *ProductHttp) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 
 	resp, err := h.productusecase.UpdateProduct(r.Context(), id, &product)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -201,7 +201,7 @@ func(h *ProductHttp) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 
 	err := h.productusecase.DeleteProduct(r.Context(), id)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -0,0 +1,342 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"encoding/json"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"e-procurement/pkg/validator"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type PurchaseOrderHttp struct {
+	usecase   usecases.PurchaseOrderUseCase
+	validator *validator.CustomValidator
+}
+
+func NewPurchaseOrderHttp(u usecases.PurchaseOrderUseCase) *PurchaseOrderHttp {
+	return &PurchaseOrderHttp{
+		usecase:   u,
+		validator: validator.Getvalidator(),
+	}
+}
+
+// Create stores a new draft purchase order
+func (h *PurchaseOrderHttp) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.PurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := h.usecase.Create(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase order created successfully", order, nil)
+}
+
+// List returns a page of purchase orders, filtered by ?status=, ?vendor_id= and ?award_id=
+func (h *PurchaseOrderHttp) List(w http.ResponseWriter, r *http.Request) {
+	limit, page := pageParams(r)
+	query := r.URL.Query()
+	filter := models.PurchaseOrderFilter{
+		Status:   query.Get("status"),
+		VendorID: query.Get("vendor_id"),
+		AwardID:  query.Get("award_id"),
+	}
+	if filter.VendorID != "" && !h.validator.IsValidUUID(filter.VendorID) {
+		response.Error(w, http.StatusBadRequest, "Invalid vendor ID format")
+		return
+	}
+	if filter.AwardID != "" && !h.validator.IsValidUUID(filter.AwardID) {
+		response.Error(w, http.StatusBadRequest, "Invalid award ID format")
+		return
+	}
+
+	orders, count, err := h.usecase.List(r.Context(), filter, limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase orders retrieved successfully", orders, pageMeta(limit, page, count))
+}
+
+// Get returns a single purchase order with its lines
+func (h *PurchaseOrderHttp) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.usecase.Get(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase order retrieved successfully", order, nil)
+}
+
+// Update replaces a draft purchase order
+func (h *PurchaseOrderHttp) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.PurchaseOrderDetails
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := h.usecase.Update(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase order updated successfully", order, nil)
+}
+
+// Submit sends a draft purchase order for approval
+func (h *PurchaseOrderHttp) Submit(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.usecase.Submit(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase order submitted successfully", order, nil)
+}
+
+// Send issues an approved purchase order to its vendor
+func (h *PurchaseOrderHttp) Send(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.usecase.Send(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase order sent successfully", order, nil)
+}
+
+// ChangeOrder revises an approved purchase order under a new revision
+func (h *PurchaseOrderHttp) ChangeOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.ChangeOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := h.usecase.ChangeOrder(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "change order created successfully", order, nil)
+}
+
+// Revisions returns the change order history of a purchase order
+func (h *PurchaseOrderHttp) Revisions(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.usecase.Revisions(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase order revisions retrieved successfully", revisions, nil)
+}
+
+// Close completes an acknowledged or partially received purchase order
+func (h *PurchaseOrderHttp) Close(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.usecase.Close(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase order closed successfully", order, nil)
+}
+
+// Cancel withdraws a purchase order nothing has been received on
+func (h *PurchaseOrderHttp) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.usecase.Cancel(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase order cancelled successfully", order, nil)
+}
+
+// Document returns the structured purchase order document
+func (h *PurchaseOrderHttp) Document(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	document, err := h.usecase.Document(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase order document retrieved successfully", document, nil)
+}
+
+// VendorOrders returns a page of the purchase orders sent to the caller's vendor, filtered by ?status=
+func (h *PurchaseOrderHttp) VendorOrders(w http.ResponseWriter, r *http.Request) {
+	limit, page := pageParams(r)
+	orders, count, err := h.usecase.VendorOrders(r.Context(), r.URL.Query().Get("status"), limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase orders retrieved successfully", orders, pageMeta(limit, page, count))
+}
+
+// VendorOrder returns a single purchase order sent to the caller's vendor
+func (h *PurchaseOrderHttp) VendorOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.usecase.VendorOrder(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase order retrieved successfully", order, nil)
+}
+
+// VendorDocument returns the document of a purchase order sent to the caller's vendor
+func (h *PurchaseOrderHttp) VendorDocument(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	document, err := h.usecase.VendorDocument(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase order document retrieved successfully", document, nil)
+}
+
+// Acknowledge accepts a sent purchase order on behalf of the caller's vendor
+func (h *PurchaseOrderHttp) Acknowledge(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.PurchaseOrderAcknowledgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := h.usecase.Acknowledge(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase order acknowledged successfully", order, nil)
+}
+
+// Reject declines a sent purchase order on behalf of the caller's vendor
+func (h *PurchaseOrderHttp) Reject(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.purchaseOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.PurchaseOrderRejectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := h.usecase.Reject(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "purchase order rejected successfully", order, nil)
+}
+
+// purchaseOrderID reads the purchase order ID from the path, writing a 400 when it is malformed
+func (h *PurchaseOrderHttp) purchaseOrderID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid purchase order ID format")
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,153 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"e-procurement/pkg/validator"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type RequisitionHttp struct {
+	usecase   usecases.RequisitionUseCase
+	validator *validator.CustomValidator
+}
+
+func NewRequisitionHttp(u usecases.RequisitionUseCase) *RequisitionHttp {
+	return &RequisitionHttp{
+		usecase:   u,
+		validator: validator.Getvalidator(),
+	}
+}
+
+// Create stores a new draft requisition raised by the caller
+func (h *RequisitionHttp) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.RequisitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	requisition, err := h.usecase.Create(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "requisition created successfully", requisition, nil)
+}
+
+// List returns a page of requisitions, filtered by ?status=, ?department_id= and ?mine=true
+func (h *RequisitionHttp) List(w http.ResponseWriter, r *http.Request) {
+	limit, page := pageParams(r)
+	query := r.URL.Query()
+	filter := models.RequisitionFilter{
+		Status:       query.Get("status"),
+		DepartmentID: query.Get("department_id"),
+	}
+	if filter.DepartmentID != "" && !h.validator.IsValidUUID(filter.DepartmentID) {
+		response.Error(w, http.StatusBadRequest, "Invalid department ID format")
+		return
+	}
+
+	requisitions, count, err := h.usecase.List(r.Context(), filter, query.Get("mine") == "true", limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "requisitions retrieved successfully", requisitions, pageMeta(limit, page, count))
+}
+
+// Get returns a single requisition with its lines
+func (h *RequisitionHttp) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.requisitionID(w, r)
+	if !ok {
+		return
+	}
+
+	requisition, err := h.usecase.Get(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "requisition retrieved successfully", requisition, nil)
+}
+
+// Update replaces a draft requisition of the caller
+func (h *RequisitionHttp) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.requisitionID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.RequisitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	requisition, err := h.usecase.Update(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "requisition updated successfully", requisition, nil)
+}
+
+// Submit sends a draft requisition for approval
+func (h *RequisitionHttp) Submit(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.requisitionID(w, r)
+	if !ok {
+		return
+	}
+
+	requisition, err := h.usecase.Submit(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "requisition submitted successfully", requisition, nil)
+}
+
+// Cancel withdraws a draft or submitted requisition
+func (h *RequisitionHttp) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.requisitionID(w, r)
+	if !ok {
+		return
+	}
+
+	requisition, err := h.usecase.Cancel(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "requisition cancelled successfully", requisition, nil)
+}
+
+// requisitionID reads the requisition ID from the path, writing a 400 when it is malformed
+func (h *RequisitionHttp) requisitionID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid requisition ID format")
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,273 @@
+package https
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/usecases"
+	response "e-procurement/pkg/responses"
+	"e-procurement/pkg/validator"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type RFQHttp struct {
+	usecase   usecases.RFQUseCase
+	validator *validator.CustomValidator
+}
+
+func NewRFQHttp(u usecases.RFQUseCase) *RFQHttp {
+	return &RFQHttp{
+		usecase:   u,
+		validator: validator.Getvalidator(),
+	}
+}
+
+// Create stores a new draft RFQ
+func (h *RFQHttp) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.RFQRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rfq, err := h.usecase.Create(r.Context(), &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "rfq created successfully", rfq, nil)
+}
+
+// List returns a page of RFQs, filtered by ?status=
+func (h *RFQHttp) List(w http.ResponseWriter, r *http.Request) {
+	limit, page := pageParams(r)
+	rfqs, count, err := h.usecase.List(r.Context(), r.URL.Query().Get("status"), limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "rfqs retrieved successfully", rfqs, pageMeta(limit, page, count))
+}
+
+// Get returns a single RFQ with its lines and invited vendors
+func (h *RFQHttp) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	rfq, err := h.usecase.Get(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "rfq retrieved successfully", rfq, nil)
+}
+
+// Update replaces a draft RFQ
+func (h *RFQHttp) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.RFQRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rfq, err := h.usecase.Update(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "rfq updated successfully", rfq, nil)
+}
+
+// Publish opens a draft RFQ to its invited vendors
+func (h *RFQHttp) Publish(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	rfq, err := h.usecase.Publish(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "rfq published successfully", rfq, nil)
+}
+
+// Cancel withdraws a draft or open RFQ
+func (h *RFQHttp) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	rfq, err := h.usecase.Cancel(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "rfq cancelled successfully", rfq, nil)
+}
+
+// Invite adds vendors to an RFQ still accepting quotations
+func (h *RFQHttp) Invite(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.RFQInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rfq, err := h.usecase.Invite(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "vendors invited successfully", rfq, nil)
+}
+
+// Quotations returns the quotations of an RFQ once its deadline has passed
+func (h *RFQHttp) Quotations(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	quotations, err := h.usecase.Quotations(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "quotations retrieved successfully", quotations, nil)
+}
+
+// Compare returns the quotations of an RFQ side by side per line
+func (h *RFQHttp) Compare(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	comparison, err := h.usecase.Compare(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "quotation comparison retrieved successfully", comparison, nil)
+}
+
+// Invitations returns a page of the RFQs the caller's vendor is invited to, filtered by ?status=
+func (h *RFQHttp) Invitations(w http.ResponseWriter, r *http.Request) {
+	limit, page := pageParams(r)
+	rfqs, count, err := h.usecase.Invitations(r.Context(), r.URL.Query().Get("status"), limit, page)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "rfq invitations retrieved successfully", rfqs, pageMeta(limit, page, count))
+}
+
+// Invitation returns a single RFQ the caller's vendor is invited to
+func (h *RFQHttp) Invitation(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	rfq, err := h.usecase.Invitation(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "rfq invitation retrieved successfully", rfq, nil)
+}
+
+// SubmitQuotation stores or replaces the quotation of the caller's vendor
+func (h *RFQHttp) SubmitQuotation(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.QuotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quotation, err := h.usecase.SubmitQuotation(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "quotation submitted successfully", quotation, nil)
+}
+
+// MyQuotation returns the quotation the caller's vendor submitted
+func (h *RFQHttp) MyQuotation(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.rfqID(w, r)
+	if !ok {
+		return
+	}
+
+	quotation, err := h.usecase.MyQuotation(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "quotation retrieved successfully", quotation, nil)
+}
+
+// rfqID reads the RFQ ID from the path, writing a 400 when it is malformed
+func (h *RFQHttp) rfqID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid RFQ ID format")
+		return "", false
+	}
+	return id, true
+}
@@ -36,7 +36,7 @@ func (h *UserHttp) GetUserByID(w http.ResponseWriter, r *http.Request) {
 	// Call usecase to get user by ID
 	userResponse, err := h.userUseCase.GetUserByID(r.Context(), userID)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -59,7 +59,7 @@ func (h *UserHttp) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	// Call usecase to update user
 	userResponse, err := h.userUseCase.UpdateUser(r.Context(), &userReq)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -79,7 +79,7 @@ func (h *UserHttp) DeleteUser(w http.ResponseWriter, r *http.Request) {
 
 	// Call usecase to delete user
 	if err := h.userUseCase.DeleteUser(r.Context(), userID); err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -101,9 +101,48 @@ func(h *UserHttp) ChangePassword(w http.ResponseWriter, r *http.Request) {
 
 	// Call usecase to change password
 	if err := h.userUseCase.UpdatePassword(r.Context(), &changePasswordReq); err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
 	response.Success(w, "Password changed successfully", nil, nil)
-}
\ No newline at end of file
+}
+
+// UpdateUserRole handles the HTTP request for admins to change a user's role.
+func (h *UserHttp) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	var roleReq models.UpdateUserRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&roleReq); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(roleReq); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userResponse, err := h.userUseCase.UpdateUserRole(r.Context(), &roleReq)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "User role updated successfully", userResponse, nil)
+}
+
+// RevokeSessions handles the HTTP request to log a user out of every session.
+// without the id query parameter the caller's own sessions are revoked.
+func (h *UserHttp) RevokeSessions(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("id")
+	if userID != "" && !h.validator.IsValidUUID(userID) {
+		response.Error(w, http.StatusBadRequest, "invalid user ID format")
+		return
+	}
+
+	if err := h.userUseCase.RevokeSessions(r.Context(), userID); err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "Sessions revoked successfully", nil, nil)
+}
@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+
+	"github.com/go-chi/chi/v5"
 )
 
 type VendorHttp struct {
@@ -47,7 +49,7 @@ func (h *VendorHttp) CreateVendor(w http.ResponseWriter, r *http.Request) {
 	// call usecase to create vendor
 	vendorResponse, err := h.vendorusecase.CreateVendorUsecase(r.Context(), &vendorReq)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -68,7 +70,7 @@ func (h *VendorHttp) GetAllVendors(w http.ResponseWriter, r *http.Request) {
 
 	vendors, count, err := h.vendorusecase.GetAllVendors(r.Context(), limit, page)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -103,7 +105,7 @@ func (h *VendorHttp) GetVendorByID(w http.ResponseWriter, r *http.Request) {
 
 	vendorResponse, err := h.vendorusecase.GetVendorByID(r.Context(), id)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -138,13 +140,56 @@ func (h *VendorHttp) UpdateVendor(w http.ResponseWriter, r *http.Request) {
 
 	vendorResponse, err := h.vendorusecase.UpdateVendor(r.Context(), vendorID, &vendorReq)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
 	response.Success(w, "Vendor updated successfully", vendorResponse, nil)
 }
 
+// ChangeBankAccount replaces the bank account of a vendor, the change is recorded with the old account
+func (h *VendorHttp) ChangeBankAccount(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid vendor ID format")
+		return
+	}
+	var req models.VendorBankAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.validator.Validate(req); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	change, err := h.vendorusecase.ChangeBankAccount(r.Context(), id, &req)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "Bank account changed successfully", change, nil)
+}
+
+// ListBankAccountChanges returns the old and new bank account of every change with who made it
+func (h *VendorHttp) ListBankAccountChanges(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.validator.IsValidUUID(id) {
+		response.Error(w, http.StatusBadRequest, "Invalid vendor ID format")
+		return
+	}
+
+	changes, err := h.vendorusecase.ListBankAccountChanges(r.Context(), id)
+	if err != nil {
+		response.FromError(w, err)
+		return
+	}
+
+	response.Success(w, "Bank account changes retrieved successfully", changes, nil)
+}
+
 func(h *VendorHttp) DeleteVendor(w http.ResponseWriter, r *http.Request) {
 	// get vendor id from url path
 	path := r.URL.Path
@@ -167,7 +212,7 @@ func(h *VendorHttp) DeleteVendor(w http.ResponseWriter, r *http.Request) {
 
 	err := h.vendorusecase.DeleteVendor(r.Context(), id)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.FromError(w, err)
 		return
 	}
 
@@ -5,86 +5,368 @@ import (
 	"e-procurement/internals/usecases"
 	"e-procurement/pkg/auth"
 	"e-procurement/pkg/chi_middlewar"
+	"e-procurement/pkg/rbac"
 
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 )
 
 type Router struct {
 	User    usecases.UserUseCase
 	Auth    usecases.AuthUseCase
+	PasswordReset usecases.PasswordResetUseCase
+	EmailVerification usecases.EmailVerificationUseCase
+	MFA     usecases.MFAUseCase
+	LoginThrottle usecases.LoginThrottle
+	APIKey  usecases.APIKeyUseCase
+	Organization usecases.OrganizationUseCase
+	Department usecases.DepartmentUseCase
+	CostCenter usecases.CostCenterUseCase
+	Requisition usecases.RequisitionUseCase
+	Approval usecases.ApprovalUseCase
+	RFQ usecases.RFQUseCase
+	BidEvaluation usecases.BidEvaluationUseCase
+	PurchaseOrder usecases.PurchaseOrderUseCase
+	GoodsReceipt usecases.GoodsReceiptUseCase
+	Invoice usecases.InvoiceUseCase
+	Payment usecases.PaymentUseCase
 	Vendor  usecases.VendorUseCase
 	Product usecases.ProductUseCase
 	Category usecases.CategoryUsecase
 	JWT *auth.JWT
+	Revocations auth.RevocationChecker
+	// roles that must enable two-factor authentication before calling protected routes
+	MFARequiredRoles []string
+	// rewrite RemoteAddr from X-Forwarded-For / X-Real-IP, only behind a trusted proxy
+	TrustProxy bool
 }
 
 // routes
 
-func registerAuthRoutes(r chi.Router, authHandler *https.AuthHttp) {
+func registerAuthRoutes(
+	r chi.Router,
+	authHandler *https.AuthHttp,
+	passwordResetHandler *https.PasswordResetHttp,
+	verificationHandler *https.EmailVerificationHttp,
+	mfaHandler *https.MFAHttp,
+	jwtMiddleware *auth.AuthHttp,
+) {
 	r.Post("/auth/login", authHandler.Authentication)
 	r.Post("/auth/register", authHandler.Create)
+	r.Post("/auth/refresh", authHandler.Refresh)
+	// the access token is optional on logout, when sent it is revoked too
+	r.With(jwtMiddleware.OptionalToken).Post("/auth/logout", authHandler.Logout)
+	r.Post("/auth/forgot-password", passwordResetHandler.ForgotPassword)
+	r.Post("/auth/reset-password", passwordResetHandler.ResetPassword)
+	r.Get("/auth/verify", verificationHandler.Verify)
+	r.Post("/auth/verify/resend", verificationHandler.Resend)
+	// second login step, authenticated by the mfa_token of /auth/login
+	r.Post("/auth/mfa/verify", mfaHandler.Verify)
+	// enrollment needs a login but not MFA, otherwise users forced to enroll never could
+	r.With(jwtMiddleware.VerifyToken, auth.RequireUser).Post("/auth/mfa/enroll", mfaHandler.Enroll)
+	r.With(jwtMiddleware.VerifyToken, auth.RequireUser).Post("/auth/mfa/confirm", mfaHandler.Confirm)
+	// not behind RequireMFA, the role (and so the MFA requirement) differs per organization
+	r.With(jwtMiddleware.VerifyToken, auth.RequireUser).Post("/auth/switch-organization", authHandler.SwitchOrganization)
 }
 
 func registerUserRoutes(r chi.Router, userHandler *https.UserHttp) {
-	r.Get("/user", userHandler.GetUserByID)
-	r.Put("/user", userHandler.UpdateUser)
-	r.Delete("/user", userHandler.DeleteUser)
-	r.Put("/user/password", userHandler.ChangePassword)
+	r.With(rbac.RequirePermission(rbac.PermUserRead)).Get("/user", userHandler.GetUserByID)
+	// updating own profile and password is allowed for every role, but not through an api key
+	self := r.With(auth.RequireUser)
+	self.Put("/user", userHandler.UpdateUser)
+	self.Put("/user/password", userHandler.ChangePassword)
+	// own sessions for every role, other users' sessions are checked against user:manage in the usecase
+	self.Delete("/user/sessions", userHandler.RevokeSessions)
+	r.With(rbac.RequirePermission(rbac.PermUserManage)).Delete("/user", userHandler.DeleteUser)
+	r.With(rbac.RequirePermission(rbac.PermUserManage)).Put("/user/role", userHandler.UpdateUserRole)
 }
 
 func registerProductRoutes(r chi.Router, productHandler *https.ProductHttp) {
-	r.Post("/vendor/product",productHandler.CreateProduct)
-	r.Get("/vendor/products", productHandler.GetAllProducts)
-	r.Get("/vendor/products/{id}", productHandler.GetProductByID)
-	r.Put("/vendor/products/{id}", productHandler.UpdateProduct)
-	r.Delete("/vendor/products/{id}", productHandler.DeleteProduct)
-	r.Get("/vendor/products/category/{categoryID}", productHandler.GetProductsByCategory)
+	read := r.With(rbac.RequirePermission(rbac.PermProductRead))
+	write := r.With(rbac.RequirePermission(rbac.PermProductWrite))
+	write.Post("/vendor/product",productHandler.CreateProduct)
+	read.Get("/vendor/products", productHandler.GetAllProducts)
+	read.Get("/vendor/products/{id}", productHandler.GetProductByID)
+	write.Put("/vendor/products/{id}", productHandler.UpdateProduct)
+	write.Delete("/vendor/products/{id}", productHandler.DeleteProduct)
+	read.Get("/vendor/products/category/{categoryID}", productHandler.GetProductsByCategory)
 }
 
 func registerCategoryRoutes(r chi.Router, categoryHandler *https.CategoryHttp) {
-	r.Post("/vendor/product_category", categoryHandler.CreateCategory)
-	r.Get("/vendor/product_categories", categoryHandler.GetCategory)
-	r.Get("/vendor/product_categories/{id}", categoryHandler.GetCategoryByID)
-	r.Put("/vendor/product_categories/{id}", categoryHandler.UpdateCategory)
-	r.Delete("/vendor/product_categories/{id}", categoryHandler.DeleteCategory)
+	read := r.With(rbac.RequirePermission(rbac.PermCategoryRead))
+	write := r.With(rbac.RequirePermission(rbac.PermCategoryWrite))
+	write.Post("/vendor/product_category", categoryHandler.CreateCategory)
+	read.Get("/vendor/product_categories", categoryHandler.GetCategory)
+	read.Get("/vendor/product_categories/{id}", categoryHandler.GetCategoryByID)
+	write.Put("/vendor/product_categories/{id}", categoryHandler.UpdateCategory)
+	write.Delete("/vendor/product_categories/{id}", categoryHandler.DeleteCategory)
 }
 
 func registerVendorRouters(r chi.Router, vendorHandler *https.VendorHttp) {
-	r.Post("/vendor", vendorHandler.CreateVendor)
-	r.Get("/vendor", vendorHandler.GetAllVendors)
-	r.Get("/vendor/{id}", vendorHandler.GetVendorByID)
-	r.Put("/vendor/{id}", vendorHandler.UpdateVendor)
-	r.Delete("/vendor/{id}", vendorHandler.DeleteVendor)
+	read := r.With(rbac.RequirePermission(rbac.PermVendorRead))
+	write := r.With(rbac.RequirePermission(rbac.PermVendorWrite))
+	write.Post("/vendor", vendorHandler.CreateVendor)
+	read.Get("/vendor", vendorHandler.GetAllVendors)
+	read.Get("/vendor/{id}", vendorHandler.GetVendorByID)
+	read.Get("/vendor/{id}/bank-account-changes", vendorHandler.ListBankAccountChanges)
+	// bank accounts are changed by those who pay the vendor
+	r.With(rbac.RequirePermission(rbac.PermPaymentWrite)).Put("/vendor/{id}/bank-account", vendorHandler.ChangeBankAccount)
+	write.Put("/vendor/{id}", vendorHandler.UpdateVendor)
+	write.Delete("/vendor/{id}", vendorHandler.DeleteVendor)
+}
+
+func registerAuditRoutes(r chi.Router, loginAttemptHandler *https.LoginAttemptHttp) {
+	read := r.With(rbac.RequirePermission(rbac.PermAuditRead))
+	read.Get("/audit/login-attempts", loginAttemptHandler.List)
+}
+
+// api keys are managed from a user session, other users' keys need user:manage in the usecase
+func registerAPIKeyRoutes(r chi.Router, apiKeyHandler *https.APIKeyHttp) {
+	manage := r.With(auth.RequireUser, rbac.RequirePermission(rbac.PermAPIKeyManage))
+	manage.Post("/api-keys", apiKeyHandler.Create)
+	manage.Get("/api-keys", apiKeyHandler.List)
+	manage.Delete("/api-keys/{id}", apiKeyHandler.Revoke)
+}
+
+// organizations of the caller, members are managed inside the organization of the token
+func registerOrganizationRoutes(r chi.Router, organizationHandler *https.OrganizationHttp) {
+	r.With(auth.RequireUser, rbac.RequirePermission(rbac.PermOrganizationCreate)).Post("/organizations", organizationHandler.Create)
+	r.Get("/organizations", organizationHandler.ListMine)
+	r.With(rbac.RequirePermission(rbac.PermUserRead)).Get("/organization/members", organizationHandler.ListMembers)
+	manage := r.With(rbac.RequirePermission(rbac.PermUserManage))
+	manage.Post("/organization/members", organizationHandler.AddMember)
+	manage.Put("/organization/members/{userID}", organizationHandler.UpdateMember)
+	manage.Delete("/organization/members/{userID}", organizationHandler.RemoveMember)
+}
+
+// departments and cost centers are internal to the buyer organization, vendors do not see them
+func registerDepartmentRoutes(r chi.Router, departmentHandler *https.DepartmentHttp, costCenterHandler *https.CostCenterHttp) {
+	read := r.With(rbac.RequirePermission(rbac.PermDepartmentRead))
+	write := r.With(rbac.RequirePermission(rbac.PermDepartmentWrite))
+	write.Post("/departments", departmentHandler.Create)
+	read.Get("/departments", departmentHandler.List)
+	read.Get("/departments/{id}", departmentHandler.Get)
+	write.Put("/departments/{id}", departmentHandler.Update)
+	write.Delete("/departments/{id}", departmentHandler.Delete)
+
+	write.Post("/cost-centers", costCenterHandler.Create)
+	read.Get("/cost-centers", costCenterHandler.List)
+	read.Get("/cost-centers/{id}", costCenterHandler.Get)
+	write.Put("/cost-centers/{id}", costCenterHandler.Update)
+	write.Delete("/cost-centers/{id}", costCenterHandler.Delete)
+
+	read.Get("/organization/members/{userID}/department", departmentHandler.GetAssignment)
+	r.With(rbac.RequirePermission(rbac.PermUserManage)).Put("/organization/members/{userID}/department", departmentHandler.Assign)
+	// every member can look up where their own purchases are charged to
+	r.With(auth.RequireUser).Get("/organization/purchase-defaults", departmentHandler.PurchaseDefaults)
+}
+
+// requisitions are raised by people, API keys may only read them; the approval
+// decision goes through the approval routes
+func registerRequisitionRoutes(r chi.Router, requisitionHandler *https.RequisitionHttp) {
+	read := r.With(rbac.RequirePermission(rbac.PermRequisitionRead))
+	write := r.With(auth.RequireUser, rbac.RequirePermission(rbac.PermRequisitionWrite))
+	write.Post("/requisitions", requisitionHandler.Create)
+	read.Get("/requisitions", requisitionHandler.List)
+	read.Get("/requisitions/{id}", requisitionHandler.Get)
+	write.Put("/requisitions/{id}", requisitionHandler.Update)
+	write.Post("/requisitions/{id}/submit", requisitionHandler.Submit)
+	write.Post("/requisitions/{id}/cancel", requisitionHandler.Cancel)
+}
+
+// acting on an approval is checked against the assigned approvers by the usecase,
+// so the decision routes only need a user behind the request
+func registerApprovalRoutes(r chi.Router, approvalHandler *https.ApprovalHttp) {
+	read := r.With(rbac.RequirePermission(rbac.PermApprovalRead))
+	write := r.With(rbac.RequirePermission(rbac.PermApprovalRuleWrite))
+	self := r.With(auth.RequireUser)
+	write.Post("/approval-rules", approvalHandler.CreateRule)
+	read.Get("/approval-rules", approvalHandler.ListRules)
+	read.Get("/approval-rules/{id}", approvalHandler.GetRule)
+	write.Put("/approval-rules/{id}", approvalHandler.UpdateRule)
+	write.Delete("/approval-rules/{id}", approvalHandler.DeleteRule)
+
+	read.Get("/approvals", approvalHandler.List)
+	self.Get("/approvals/pending", approvalHandler.Pending)
+	read.Get("/approvals/{id}", approvalHandler.Get)
+	self.Post("/approvals/{id}/approve", approvalHandler.Approve)
+	self.Post("/approvals/{id}/reject", approvalHandler.Reject)
+	self.Post("/approvals/{id}/return", approvalHandler.Return)
+	write.Post("/approvals/{id}/complete", approvalHandler.Complete)
+
+	self.Post("/approval-delegations", approvalHandler.Delegate)
+	self.Get("/approval-delegations", approvalHandler.ListDelegations)
+	self.Delete("/approval-delegations/{id}", approvalHandler.RevokeDelegation)
+}
+
+// buyers prepare RFQs and compare the quotations once the deadline has passed, invited
+// vendors answer through the invitation routes which only show their own side
+func registerRFQRoutes(r chi.Router, rfqHandler *https.RFQHttp) {
+	read := r.With(rbac.RequirePermission(rbac.PermRFQRead))
+	write := r.With(auth.RequireUser, rbac.RequirePermission(rbac.PermRFQWrite))
+	write.Post("/rfqs", rfqHandler.Create)
+	read.Get("/rfqs", rfqHandler.List)
+	read.Get("/rfqs/{id}", rfqHandler.Get)
+	write.Put("/rfqs/{id}", rfqHandler.Update)
+	write.Post("/rfqs/{id}/publish", rfqHandler.Publish)
+	write.Post("/rfqs/{id}/cancel", rfqHandler.Cancel)
+	write.Post("/rfqs/{id}/vendors", rfqHandler.Invite)
+	read.Get("/rfqs/{id}/quotations", rfqHandler.Quotations)
+	read.Get("/rfqs/{id}/comparison", rfqHandler.Compare)
+
+	vendor := r.With(auth.RequireUser, rbac.RequirePermission(rbac.PermQuotationSubmit))
+	vendor.Get("/rfq-invitations", rfqHandler.Invitations)
+	vendor.Get("/rfq-invitations/{id}", rfqHandler.Invitation)
+	vendor.Get("/rfq-invitations/{id}/quotation", rfqHandler.MyQuotation)
+	vendor.Put("/rfq-invitations/{id}/quotation", rfqHandler.SubmitQuotation)
+}
+
+// quotations are scored and awarded once unsealed, the award may draft the purchase orders
+func registerBidEvaluationRoutes(r chi.Router, evaluationHandler *https.BidEvaluationHttp) {
+	read := r.With(rbac.RequirePermission(rbac.PermRFQRead))
+	write := r.With(auth.RequireUser, rbac.RequirePermission(rbac.PermRFQWrite))
+	read.Get("/rfqs/{id}/evaluation-criteria", evaluationHandler.Criteria)
+	write.Put("/rfqs/{id}/evaluation-criteria", evaluationHandler.SetCriteria)
+	write.Put("/rfqs/{id}/quotations/{quotationID}/score", evaluationHandler.ScoreQuotation)
+	read.Get("/rfqs/{id}/evaluation", evaluationHandler.Evaluate)
+	write.Post("/rfqs/{id}/award", evaluationHandler.Award)
+	read.Get("/rfqs/{id}/award", evaluationHandler.GetAward)
+	write.Post("/rfqs/{id}/award/purchase-orders", evaluationHandler.CreatePurchaseOrders)
+}
+
+// purchase orders go through approval before they are sent, the vendor user answers them
+// under /vendor-purchase-orders
+func registerPurchaseOrderRoutes(r chi.Router, purchaseOrderHandler *https.PurchaseOrderHttp) {
+	read := r.With(rbac.RequirePermission(rbac.PermPurchaseOrderRead))
+	write := r.With(auth.RequireUser, rbac.RequirePermission(rbac.PermPurchaseOrderWrite))
+	write.Post("/purchase-orders", purchaseOrderHandler.Create)
+	read.Get("/purchase-orders", purchaseOrderHandler.List)
+	read.Get("/purchase-orders/{id}", purchaseOrderHandler.Get)
+	write.Put("/purchase-orders/{id}", purchaseOrderHandler.Update)
+	write.Post("/purchase-orders/{id}/submit", purchaseOrderHandler.Submit)
+	write.Post("/purchase-orders/{id}/send", purchaseOrderHandler.Send)
+	write.Post("/purchase-orders/{id}/change-orders", purchaseOrderHandler.ChangeOrder)
+	read.Get("/purchase-orders/{id}/revisions", purchaseOrderHandler.Revisions)
+	write.Post("/purchase-orders/{id}/close", purchaseOrderHandler.Close)
+	write.Post("/purchase-orders/{id}/cancel", purchaseOrderHandler.Cancel)
+	read.Get("/purchase-orders/{id}/document", purchaseOrderHandler.Document)
+
+	vendor := r.With(auth.RequireUser, rbac.RequirePermission(rbac.PermPurchaseOrderRespond))
+	vendor.Get("/vendor-purchase-orders", purchaseOrderHandler.VendorOrders)
+	vendor.Get("/vendor-purchase-orders/{id}", purchaseOrderHandler.VendorOrder)
+	vendor.Get("/vendor-purchase-orders/{id}/document", purchaseOrderHandler.VendorDocument)
+	vendor.Post("/vendor-purchase-orders/{id}/acknowledge", purchaseOrderHandler.Acknowledge)
+	vendor.Post("/vendor-purchase-orders/{id}/reject", purchaseOrderHandler.Reject)
+}
+
+func registerGoodsReceiptRoutes(r chi.Router, goodsReceiptHandler *https.GoodsReceiptHttp) {
+	read := r.With(rbac.RequirePermission(rbac.PermGoodsReceiptRead))
+	write := r.With(auth.RequireUser, rbac.RequirePermission(rbac.PermGoodsReceiptWrite))
+	write.Post("/purchase-orders/{id}/receipts", goodsReceiptHandler.Record)
+	read.Get("/purchase-orders/{id}/receipts", goodsReceiptHandler.ListByPurchaseOrder)
+	read.Get("/purchase-orders/{id}/receipt-summary", goodsReceiptHandler.ReceiptSummary)
+	read.Get("/goods-receipts", goodsReceiptHandler.List)
+	read.Get("/goods-receipts/{id}", goodsReceiptHandler.Get)
+}
+
+func registerInvoiceRoutes(r chi.Router, invoiceHandler *https.InvoiceHttp) {
+	read := r.With(rbac.RequirePermission(rbac.PermInvoiceRead))
+	write := r.With(auth.RequireUser, rbac.RequirePermission(rbac.PermInvoiceWrite))
+	read.Get("/invoices", invoiceHandler.List)
+	read.Get("/invoices/exceptions", invoiceHandler.Exceptions)
+	read.Get("/invoices/{id}", invoiceHandler.Get)
+	write.Post("/invoices/{id}/match", invoiceHandler.Match)
+	write.Post("/invoices/{id}/approve", invoiceHandler.Approve)
+	write.Post("/invoices/{id}/hold", invoiceHandler.Hold)
+	write.Post("/invoices/{id}/dispute", invoiceHandler.Dispute)
+
+	vendor := r.With(auth.RequireUser, rbac.RequirePermission(rbac.PermInvoiceSubmit))
+	vendor.Post("/vendor-invoices", invoiceHandler.Submit)
+	vendor.Get("/vendor-invoices", invoiceHandler.VendorInvoices)
+	vendor.Get("/vendor-invoices/{id}", invoiceHandler.VendorInvoice)
+}
+
+func registerPaymentRoutes(r chi.Router, paymentHandler *https.PaymentHttp) {
+	read := r.With(rbac.RequirePermission(rbac.PermPaymentRead))
+	write := r.With(auth.RequireUser, rbac.RequirePermission(rbac.PermPaymentWrite))
+	read.Get("/payments/schedule", paymentHandler.Schedule)
+	read.Get("/payment-batches", paymentHandler.ListBatches)
+	read.Get("/payment-batches/{id}", paymentHandler.GetBatch)
+	write.Post("/payment-batches", paymentHandler.CreateBatch)
+	write.Post("/payment-batches/{id}/export", paymentHandler.ExportBatch)
+	write.Post("/payment-batches/{id}/confirm", paymentHandler.ConfirmBatch)
+	write.Post("/payment-batches/{id}/cancel", paymentHandler.CancelBatch)
+
+	vendor := r.With(auth.RequireUser, rbac.RequirePermission(rbac.PermRemittanceRead))
+	vendor.Get("/vendor-remittances", paymentHandler.VendorRemittances)
+	vendor.Get("/vendor-remittances/{id}", paymentHandler.VendorRemittance)
 }
 
 func NewRouter(r *Router) http.Handler {
 	router := chi.NewRouter()
+	if r.TrustProxy {
+		// the login throttle keys on the client IP
+		router.Use(middleware.RealIP)
+	}
 	// setting body is json by default
 	router.Use(chi_middlewar.JSONContentTypeMiddleware)
 	// Middleware can be added here if needed
-	jwtMiddleware := auth.NewAuthMiddleware(r.JWT)
+	// accepts `Authorization: Bearer <jwt>` and `Authorization: ApiKey <key>`
+	jwtMiddleware := auth.NewAuthMiddleware(r.JWT, r.Revocations, &r.APIKey)
+	mfaRequiredRoles := r.MFARequiredRoles
 
 	authHandler := https.NewAuthHttp(r.Auth)
+	passwordResetHandler := https.NewPasswordResetHttp(r.PasswordReset)
+	verificationHandler := https.NewEmailVerificationHttp(r.EmailVerification)
+	mfaHandler := https.NewMFAHttp(r.MFA)
+	loginAttemptHandler := https.NewLoginAttemptHttp(r.LoginThrottle)
+	apiKeyHandler := https.NewAPIKeyHttp(r.APIKey)
+	organizationHandler := https.NewOrganizationHttp(r.Organization)
+	departmentHandler := https.NewDepartmentHttp(r.Department)
+	costCenterHandler := https.NewCostCenterHttp(r.CostCenter)
+	requisitionHandler := https.NewRequisitionHttp(r.Requisition)
+	approvalHandler := https.NewApprovalHttp(r.Approval)
+	rfqHandler := https.NewRFQHttp(r.RFQ)
+	evaluationHandler := https.NewBidEvaluationHttp(r.BidEvaluation)
+	purchaseOrderHandler := https.NewPurchaseOrderHttp(r.PurchaseOrder)
+	goodsReceiptHandler := https.NewGoodsReceiptHttp(r.GoodsReceipt)
+	invoiceHandler := https.NewInvoiceHttp(r.Invoice)
+	paymentHandler := https.NewPaymentHttp(r.Payment)
 	userHandler := https.NewUserHttp(r.User)
 	productHandler := https.NewProductHttp(r.Product)
 	categoryHandler := https.NewCategoryHttp(r.Category)
 	vendorHandler := https.NewVendortHttp(r.Vendor)
+	// public keys for services verifying our access tokens, empty when signing with HMAC
+	router.Get("/.well-known/jwks.json", r.JWT.JWKSHandler)
 	router.Route("/api/v1/", func(r chi.Router) {
 		// public routes
 		r.Get("/hallo", func(w http.ResponseWriter, r *http.Request) {
 			w.Write([]byte("Hello, World!"))
 		})
-		registerAuthRoutes(r, authHandler)
+		registerAuthRoutes(r, authHandler, passwordResetHandler, verificationHandler, mfaHandler, jwtMiddleware)
 
 		// protected routes
 		r.Group(func(protected chi.Router) {
 			protected.Use(jwtMiddleware.VerifyToken)
+			protected.Use(auth.RequireMFA(mfaRequiredRoles))
 			registerUserRoutes(protected, userHandler)
 			registerProductRoutes(protected,productHandler)
 			registerCategoryRoutes(protected, categoryHandler)
 			registerVendorRouters(protected, vendorHandler)
+			registerAuditRoutes(protected, loginAttemptHandler)
+			registerAPIKeyRoutes(protected, apiKeyHandler)
+			registerOrganizationRoutes(protected, organizationHandler)
+			registerDepartmentRoutes(protected, departmentHandler, costCenterHandler)
+			registerRequisitionRoutes(protected, requisitionHandler)
+			registerApprovalRoutes(protected, approvalHandler)
+			registerRFQRoutes(protected, rfqHandler)
+			registerBidEvaluationRoutes(protected, evaluationHandler)
+			registerPurchaseOrderRoutes(protected, purchaseOrderHandler)
+			registerGoodsReceiptRoutes(protected, goodsReceiptHandler)
+			registerInvoiceRoutes(protected, invoiceHandler)
+			registerPaymentRoutes(protected, paymentHandler)
 		})
 	})
 	return router
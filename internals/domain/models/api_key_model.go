@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// APIKey - kredensial untuk integrasi antar sistem (ERP), bertindak sebagai user pemiliknya
+// dengan permission yang dibatasi oleh Scopes. secret hanya disimpan dalam bentuk hash
+type APIKey struct {
+	ID             string     `json:"id"`
+	UserID         string     `json:"user_id"`
+	OrganizationID string     `json:"organization_id"`
+	Name           string     `json:"name"`
+	Prefix         string     `json:"prefix"`
+	KeyHash        string     `json:"-"`
+	Scopes         []string   `json:"scopes"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyRequest - untuk membuat api key, tanpa expires_at berlaku selama masa maksimal
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreateAPIKeyResponse - api key baru, Key hanya ditampilkan sekali
+type CreateAPIKeyResponse struct {
+	*APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,207 @@
+package models
+
+import "time"
+
+// jenis dokumen yang melewati approval
+const (
+	ApprovalDocumentRequisition      = "requisition"
+	ApprovalDocumentPurchaseOrder    = "purchase_order"
+	ApprovalDocumentVendorOnboarding = "vendor_onboarding"
+)
+
+// jenis approver pada langkah aturan, department_manager diganti manager departemen dokumen saat diajukan
+const (
+	ApproverTypeUser              = "user"
+	ApproverTypeRole              = "role"
+	ApproverTypeDepartmentManager = "department_manager"
+)
+
+// status permintaan approval
+const (
+	ApprovalStatusPending   = "pending"
+	ApprovalStatusApproved  = "approved"
+	ApprovalStatusRejected  = "rejected"
+	ApprovalStatusReturned  = "returned"
+	ApprovalStatusCancelled = "cancelled"
+)
+
+// status langkah approval, langkah tahap berikutnya menunggu (waiting) sampai tahap aktif selesai
+const (
+	ApprovalStepWaiting  = "waiting"
+	ApprovalStepPending  = "pending"
+	ApprovalStepApproved = "approved"
+	ApprovalStepRejected = "rejected"
+	ApprovalStepReturned = "returned"
+	ApprovalStepSkipped  = "skipped"
+)
+
+// ApprovalRule - aturan approval per jenis dokumen. Semua aturan aktif yang kondisinya cocok
+// ikut menyumbang langkah; kondisi kosong berarti berlaku untuk semua
+type ApprovalRule struct {
+	ID             string              `json:"id"`
+	OrganizationID string              `json:"organization_id"`
+	DocumentType   string              `json:"document_type"`
+	Name           string              `json:"name"`
+	Priority       int                 `json:"priority"`
+	AmountAbove    *float64            `json:"amount_above"`
+	AmountUpTo     *float64            `json:"amount_up_to"`
+	CategoryID     *string             `json:"category_id"`
+	DepartmentID   *string             `json:"department_id"`
+	Active         bool                `json:"active"`
+	Steps          []*ApprovalRuleStep `json:"steps"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+// ApprovalRuleStep - satu approver pada aturan. Langkah dengan level sama berjalan paralel,
+// level berikutnya menunggu level sebelumnya selesai
+type ApprovalRuleStep struct {
+	ID             string  `json:"id"`
+	Level          int     `json:"level"`
+	Name           string  `json:"name"`
+	ApproverType   string  `json:"approver_type"`
+	ApproverRole   string  `json:"approver_role,omitempty"`
+	ApproverUserID *string `json:"approver_user_id"`
+}
+
+// ApprovalRuleRequest - untuk membuat atau mengubah aturan approval
+type ApprovalRuleRequest struct {
+	DocumentType string                     `json:"document_type" validate:"required,oneof=requisition purchase_order vendor_onboarding"`
+	Name         string                     `json:"name" validate:"required,max=255"`
+	Priority     int                        `json:"priority"`
+	AmountAbove  *float64                   `json:"amount_above" validate:"omitempty,gte=0"`
+	AmountUpTo   *float64                   `json:"amount_up_to" validate:"omitempty,gte=0"`
+	CategoryID   *string                    `json:"category_id" validate:"omitempty,uuid"`
+	DepartmentID *string                    `json:"department_id" validate:"omitempty,uuid"`
+	Active       *bool                      `json:"active"`
+	Steps        []*ApprovalRuleStepRequest `json:"steps" validate:"required,min=1,max=20,dive,required"`
+}
+
+// ApprovalRuleStepRequest - approver_role wajib untuk tipe role, approver_user_id untuk tipe user
+type ApprovalRuleStepRequest struct {
+	Level          int     `json:"level" validate:"required,gte=1,lte=20"`
+	Name           string  `json:"name" validate:"required,max=255"`
+	ApproverType   string  `json:"approver_type" validate:"required,oneof=user role department_manager"`
+	ApproverRole   string  `json:"approver_role" validate:"required_if=ApproverType role,omitempty,oneof=admin procurement_officer approver auditor"`
+	ApproverUserID *string `json:"approver_user_id" validate:"required_if=ApproverType user,omitempty,uuid"`
+}
+
+// ApprovalSubject - dokumen yang diajukan ke mesin approval beserta data untuk mencocokkan aturan
+type ApprovalSubject struct {
+	DocumentType string
+	DocumentID   string
+	Title        string
+	Amount       float64
+	DepartmentID *string
+	CategoryIDs  []string
+	RequestedBy  string
+}
+
+// ApprovalRequest - satu putaran approval sebuah dokumen. Dokumen yang dikembalikan untuk
+// revisi lalu diajukan ulang mendapat permintaan baru, yang lama tetap sebagai riwayat
+type ApprovalRequest struct {
+	ID             string          `json:"id"`
+	OrganizationID string          `json:"organization_id"`
+	DocumentType   string          `json:"document_type"`
+	DocumentID     string          `json:"document_id"`
+	Title          string          `json:"title"`
+	Amount         float64         `json:"amount"`
+	DepartmentID   *string         `json:"department_id"`
+	RequestedBy    string          `json:"requested_by"`
+	Status         string          `json:"status"`
+	CurrentStage   int             `json:"current_stage"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	CompletedAt    *time.Time      `json:"completed_at"`
+	Steps          []*ApprovalStep `json:"steps,omitempty"`
+}
+
+// ApprovalStep - langkah approval yang sudah diturunkan dari aturan, approver berupa user atau role
+type ApprovalStep struct {
+	ID             string     `json:"id"`
+	Stage          int        `json:"stage"`
+	RuleID         *string    `json:"rule_id"`
+	Name           string     `json:"name"`
+	ApproverRole   string     `json:"approver_role,omitempty"`
+	ApproverUserID *string    `json:"approver_user_id"`
+	Status         string     `json:"status"`
+	ActedBy        *string    `json:"acted_by"`
+	OnBehalfOf     *string    `json:"on_behalf_of"`
+	Comment        string     `json:"comment"`
+	ActedAt        *time.Time `json:"acted_at"`
+}
+
+// ApprovalStepDecision - keputusan atas satu langkah, OnBehalfOf diisi saat bertindak sebagai delegasi
+type ApprovalStepDecision struct {
+	Status     string
+	ActedBy    string
+	OnBehalfOf *string
+	Comment    string
+	ActedAt    time.Time
+}
+
+// ApprovalTransition - perpindahan tahap atau penyelesaian permintaan approval.
+// Status selain pending menyelesaikan permintaan dan melewati (skip) langkah yang tersisa
+type ApprovalTransition struct {
+	Status      string
+	Stage       int
+	CompletedAt *time.Time
+}
+
+// ApprovalDecisionRequest - komentar keputusan, wajib untuk reject dan return
+type ApprovalDecisionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// ApprovalFilter - filter riwayat permintaan approval, field kosong diabaikan
+type ApprovalFilter struct {
+	DocumentType string
+	DocumentID   string
+	Status       string
+}
+
+// PendingApprovalFilter - langkah pending yang bisa ditindak user: milik UserIDs (dirinya dan
+// yang mendelegasikan kepadanya), role-nya atau DelegatedRoles, kecuali permintaan yang ia ajukan sendiri dan
+// permintaan yang sudah ia putuskan (ExcludeActor), atau sudah diputuskan oleh pemilik langkah
+// yang didelegasikan, karena satu orang hanya boleh memutuskan satu langkah per permintaan
+type PendingApprovalFilter struct {
+	UserIDs          []string
+	Role             string
+	DelegatedRoles   []DelegatedRole
+	ExcludeRequester string
+	ExcludeActor     string
+}
+
+// DelegatedRole - role anggota yang sedang mendelegasikan approval-nya, langkah untuk role
+// tersebut juga bisa ditindak delegasinya atas nama anggota itu, kecuali pada dokumen yang
+// ia ajukan sendiri atau permintaan yang sudah ia putuskan
+type DelegatedRole struct {
+	DelegatorID string
+	Role        string
+}
+
+// PendingApproval - langkah yang menunggu keputusan user beserta permintaannya
+type PendingApproval struct {
+	Request *ApprovalRequest `json:"request"`
+	Step    *ApprovalStep    `json:"step"`
+}
+
+// ApprovalDelegation - pelimpahan wewenang approval selama periode tertentu, misalnya saat cuti
+type ApprovalDelegation struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id"`
+	DelegatorID    string    `json:"delegator_id"`
+	DelegateID     string    `json:"delegate_id"`
+	StartsAt       time.Time `json:"starts_at"`
+	EndsAt         time.Time `json:"ends_at"`
+	Reason         string    `json:"reason"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ApprovalDelegationRequest - untuk melimpahkan approval milik sendiri ke anggota lain
+type ApprovalDelegationRequest struct {
+	DelegateID string    `json:"delegate_id" validate:"required,uuid"`
+	StartsAt   time.Time `json:"starts_at" validate:"required"`
+	EndsAt     time.Time `json:"ends_at" validate:"required,gtfield=StartsAt"`
+	Reason     string    `json:"reason" validate:"max=255"`
+}
@@ -0,0 +1,121 @@
+package models
+
+import "time"
+
+// cara award RFQ: seluruh RFQ ke satu vendor atau per baris ke vendor berbeda
+const (
+	AwardModeRFQ  = "rfq"
+	AwardModeLine = "line"
+)
+
+// EvaluationCriteria - bobot kriteria penilaian penawaran per RFQ dalam persen, jumlahnya 100.
+// RFQ tanpa kriteria dinilai dari harga saja
+type EvaluationCriteria struct {
+	RFQID           string    `json:"rfq_id"`
+	PriceWeight     float64   `json:"price_weight"`
+	LeadTimeWeight  float64   `json:"lead_time_weight"`
+	RatingWeight    float64   `json:"rating_weight"`
+	TechnicalWeight float64   `json:"technical_weight"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// EvaluationCriteriaRequest - untuk mengatur bobot kriteria penilaian RFQ
+type EvaluationCriteriaRequest struct {
+	PriceWeight     float64 `json:"price_weight" validate:"gte=0,lte=100"`
+	LeadTimeWeight  float64 `json:"lead_time_weight" validate:"gte=0,lte=100"`
+	RatingWeight    float64 `json:"rating_weight" validate:"gte=0,lte=100"`
+	TechnicalWeight float64 `json:"technical_weight" validate:"gte=0,lte=100"`
+}
+
+// QuotationScore - nilai yang diberikan pembeli untuk satu penawaran: rating vendor (0-5) dan nilai teknis (0-100)
+type QuotationScore struct {
+	QuotationID    string    `json:"quotation_id"`
+	VendorRating   float64   `json:"vendor_rating"`
+	TechnicalScore float64   `json:"technical_score"`
+	Notes          string    `json:"notes"`
+	ScoredBy       *string   `json:"scored_by"`
+	ScoredAt       time.Time `json:"scored_at"`
+}
+
+// QuotationScoreRequest - untuk menilai satu penawaran
+type QuotationScoreRequest struct {
+	VendorRating   float64 `json:"vendor_rating" validate:"gte=0,lte=5"`
+	TechnicalScore float64 `json:"technical_score" validate:"gte=0,lte=100"`
+	Notes          string  `json:"notes"`
+}
+
+// BidEvaluation - hasil penilaian penawaran. Mode rfq menilai total penawaran lengkap (Vendors),
+// mode line menilai penawaran setiap baris (Lines)
+type BidEvaluation struct {
+	RFQID    string              `json:"rfq_id"`
+	Mode     string              `json:"mode"`
+	Criteria *EvaluationCriteria `json:"criteria"`
+	Vendors  []*OfferEvaluation  `json:"vendors,omitempty"`
+	Lines    []*LineEvaluation   `json:"lines,omitempty"`
+}
+
+// LineEvaluation - penilaian semua penawaran untuk satu baris RFQ
+type LineEvaluation struct {
+	Line   *RFQLine           `json:"line"`
+	Offers []*OfferEvaluation `json:"offers"`
+}
+
+// OfferEvaluation - nilai satu penawaran per kriteria (0-100) dan nilai akhir berbobot.
+// Harga dan lead time dinormalisasi terhadap yang terbaik: termurah dan tercepat bernilai 100
+type OfferEvaluation struct {
+	QuotationID    string  `json:"quotation_id"`
+	VendorID       string  `json:"vendor_id"`
+	VendorName     string  `json:"vendor_name"`
+	Amount         float64 `json:"amount"`
+	LeadTimeDays   int     `json:"lead_time_days"`
+	PriceScore     float64 `json:"price_score"`
+	LeadTimeScore  float64 `json:"lead_time_score"`
+	RatingScore    float64 `json:"rating_score"`
+	TechnicalScore float64 `json:"technical_score"`
+	Score          float64 `json:"score"`
+	Rank           int     `json:"rank"`
+}
+
+// RFQAward - keputusan pemenang RFQ beserta alasannya, satu RFQ hanya bisa di-award sekali.
+// PurchaseOrders berisi draft PO yang sudah dibuat dari award ini
+type RFQAward struct {
+	ID             string           `json:"id"`
+	OrganizationID string           `json:"organization_id"`
+	RFQID          string           `json:"rfq_id"`
+	Mode           string           `json:"mode"`
+	Justification  string           `json:"justification"`
+	TotalAmount    float64          `json:"total_amount"`
+	AwardedBy      string           `json:"awarded_by"`
+	AwardedAt      time.Time        `json:"awarded_at"`
+	Lines          []*RFQAwardLine  `json:"lines,omitempty"`
+	PurchaseOrders []*PurchaseOrder `json:"purchase_orders,omitempty"`
+}
+
+// RFQAwardLine - baris RFQ yang dimenangkan vendor, dengan harga dan nilai penilaian saat award
+type RFQAwardLine struct {
+	ID          string  `json:"id"`
+	RFQLineID   string  `json:"rfq_line_id"`
+	QuotationID string  `json:"quotation_id"`
+	VendorID    string  `json:"vendor_id"`
+	VendorName  string  `json:"vendor_name"`
+	Quantity    float64 `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Amount      float64 `json:"amount"`
+	Score       float64 `json:"score"`
+}
+
+// AwardRequest - vendor_id wajib untuk mode rfq, lines wajib untuk mode line.
+// create_purchase_orders langsung membuat draft PO untuk setiap vendor pemenang
+type AwardRequest struct {
+	Mode                 string              `json:"mode" validate:"required,oneof=rfq line"`
+	VendorID             string              `json:"vendor_id" validate:"required_if=Mode rfq,omitempty,uuid"`
+	Lines                []*AwardLineRequest `json:"lines" validate:"required_if=Mode line,max=200,dive,required"`
+	Justification        string              `json:"justification" validate:"required"`
+	CreatePurchaseOrders bool                `json:"create_purchase_orders"`
+}
+
+// AwardLineRequest - pemenang satu baris RFQ pada award per baris
+type AwardLineRequest struct {
+	RFQLineID string `json:"rfq_line_id" validate:"required,uuid"`
+	VendorID  string `json:"vendor_id" validate:"required,uuid"`
+}
@@ -25,6 +25,7 @@ type Category struct {
 	ID			string
 	Name        string
 	Description string
+	OrganizationID string
 	CreatedAt   time.Time
 	UpdatedAt	time.Time
 }
\ No newline at end of file
@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// CostCenter - pusat biaya tempat pembelian dibebankan, cost center nonaktif tidak bisa dipakai lagi
+type CostCenter struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id"`
+	Code           string    `json:"code"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	Active         bool      `json:"active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// CreateCostCenterRequest - untuk membuat cost center di organisasi aktif
+type CreateCostCenterRequest struct {
+	Code        string `json:"code" validate:"required,max=50"`
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description"`
+}
+
+// UpdateCostCenterRequest - untuk mengubah cost center, active kosong berarti tidak berubah
+type UpdateCostCenterRequest struct {
+	Code        string `json:"code" validate:"required,max=50"`
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description"`
+	Active      *bool  `json:"active"`
+}
+
+// Department - departemen organisasi pembeli, bertingkat lewat ParentID
+type Department struct {
+	ID                  string    `json:"id"`
+	OrganizationID      string    `json:"organization_id"`
+	Code                string    `json:"code"`
+	Name                string    `json:"name"`
+	ParentID            *string   `json:"parent_id"`
+	ManagerID           *string   `json:"manager_id"`
+	DefaultCostCenterID *string   `json:"default_cost_center_id"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// DepartmentNode - departemen beserta sub departemennya untuk tampilan hirarki
+type DepartmentNode struct {
+	*Department
+	Children []*DepartmentNode `json:"children"`
+}
+
+// DepartmentRequest - untuk membuat atau mengubah departemen, manager harus anggota organisasi
+type DepartmentRequest struct {
+	Code                string  `json:"code" validate:"required,max=50"`
+	Name                string  `json:"name" validate:"required,max=255"`
+	ParentID            *string `json:"parent_id" validate:"omitempty,uuid"`
+	ManagerID           *string `json:"manager_id" validate:"omitempty,uuid"`
+	DefaultCostCenterID *string `json:"default_cost_center_id" validate:"omitempty,uuid"`
+}
+
+// DepartmentAssignment - departemen anggota organisasi, CostCenterID menggantikan default departemen
+type DepartmentAssignment struct {
+	UserID       string  `json:"user_id"`
+	DepartmentID *string `json:"department_id"`
+	CostCenterID *string `json:"cost_center_id"`
+}
+
+// AssignDepartmentRequest - untuk memasukkan anggota ke departemen, null untuk melepas
+type AssignDepartmentRequest struct {
+	DepartmentID *string `json:"department_id" validate:"omitempty,uuid"`
+	CostCenterID *string `json:"cost_center_id" validate:"omitempty,uuid"`
+}
+
+// PurchaseDefaults - departemen dan cost center yang dipakai saat user membuat permintaan pembelian
+type PurchaseDefaults struct {
+	DepartmentID   *string `json:"department_id"`
+	DepartmentName string  `json:"department_name,omitempty"`
+	CostCenterID   *string `json:"cost_center_id"`
+	CostCenterCode string  `json:"cost_center_code,omitempty"`
+}
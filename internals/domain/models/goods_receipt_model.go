@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// GoodsReceipt - penerimaan barang dari vendor atas satu purchase order. Satu PO dapat diterima
+// bertahap dalam beberapa penerimaan
+type GoodsReceipt struct {
+	ID                  string              `json:"id"`
+	OrganizationID      string              `json:"organization_id"`
+	PurchaseOrderID     string              `json:"purchase_order_id"`
+	PurchaseOrderNumber string              `json:"purchase_order_number"`
+	VendorID            string              `json:"vendor_id"`
+	VendorName          string              `json:"vendor_name"`
+	DeliveryNote        string              `json:"delivery_note"`
+	ReceivedAt          time.Time           `json:"received_at"`
+	Notes               string              `json:"notes"`
+	ReceivedBy          string              `json:"received_by"`
+	CreatedAt           time.Time           `json:"created_at"`
+	Lines               []*GoodsReceiptLine `json:"lines,omitempty"`
+}
+
+// GoodsReceiptLine - jumlah yang datang untuk satu baris PO beserta hasil inspeksinya.
+// ReceivedQuantity = AcceptedQuantity + RejectedQuantity
+type GoodsReceiptLine struct {
+	ID                  string  `json:"id"`
+	PurchaseOrderLineID string  `json:"purchase_order_line_id"`
+	LineNo              int     `json:"line_no"`
+	Description         string  `json:"description"`
+	Unit                string  `json:"unit"`
+	ReceivedQuantity    float64 `json:"received_quantity"`
+	AcceptedQuantity    float64 `json:"accepted_quantity"`
+	RejectedQuantity    float64 `json:"rejected_quantity"`
+	InspectionNotes     string  `json:"inspection_notes"`
+}
+
+// GoodsReceiptRequest - untuk mencatat penerimaan barang. received_at berformat YYYY-MM-DD,
+// kosong berarti hari ini. delivery_note adalah nomor surat jalan vendor
+type GoodsReceiptRequest struct {
+	DeliveryNote string                     `json:"delivery_note" validate:"max=100"`
+	ReceivedAt   string                     `json:"received_at" validate:"omitempty,datetime=2006-01-02"`
+	Notes        string                     `json:"notes"`
+	Lines        []*GoodsReceiptLineRequest `json:"lines" validate:"required,min=1,max=200,dive,required"`
+}
+
+// GoodsReceiptLineRequest - barang yang datang untuk satu baris PO, jumlah yang diterima
+// adalah received_quantity dikurangi rejected_quantity
+type GoodsReceiptLineRequest struct {
+	PurchaseOrderLineID string  `json:"purchase_order_line_id" validate:"required,uuid"`
+	ReceivedQuantity    float64 `json:"received_quantity" validate:"required,gt=0"`
+	RejectedQuantity    float64 `json:"rejected_quantity" validate:"gte=0,ltefield=ReceivedQuantity"`
+	InspectionNotes     string  `json:"inspection_notes"`
+}
+
+// GoodsReceiptFilter - filter daftar penerimaan barang, field kosong diabaikan
+type GoodsReceiptFilter struct {
+	PurchaseOrderID string
+	VendorID        string
+}
+
+// ReceiptSummaryLine - rekap penerimaan satu baris PO dari semua penerimaan.
+// OutstandingQuantity adalah jumlah yang belum diterima, tidak pernah negatif
+type ReceiptSummaryLine struct {
+	PurchaseOrderLineID string  `json:"purchase_order_line_id"`
+	LineNo              int     `json:"line_no"`
+	Description         string  `json:"description"`
+	Unit                string  `json:"unit"`
+	OrderedQuantity     float64 `json:"ordered_quantity"`
+	ReceivedQuantity    float64 `json:"received_quantity"`
+	AcceptedQuantity    float64 `json:"accepted_quantity"`
+	RejectedQuantity    float64 `json:"rejected_quantity"`
+	OutstandingQuantity float64 `json:"outstanding_quantity"`
+}
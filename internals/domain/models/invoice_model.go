@@ -0,0 +1,138 @@
+package models
+
+import "time"
+
+// status invoice. Invoice yang baru diajukan langsung dicocokkan (three-way match) dan menjadi matched
+// atau exception, lalu direview finance: approved, on_hold (ditahan) atau disputed (dikembalikan ke vendor).
+// Invoice approved masuk payment batch (scheduled) dan menjadi paid setelah batch dibayar
+const (
+	InvoiceStatusMatched   = "matched"
+	InvoiceStatusException = "exception"
+	InvoiceStatusOnHold    = "on_hold"
+	InvoiceStatusApproved  = "approved"
+	InvoiceStatusDisputed  = "disputed"
+	InvoiceStatusScheduled = "scheduled"
+	InvoiceStatusPaid      = "paid"
+)
+
+// hasil three-way match invoice dan baris invoice
+const (
+	MatchStatusMatched   = "matched"
+	MatchStatusException = "exception"
+)
+
+// jenis selisih pada baris invoice yang gagal dicocokkan
+const (
+	// harga satuan invoice melebihi harga PO di luar toleransi
+	MatchExceptionPriceVariance = "price_variance"
+	// tarif pajak invoice berbeda dengan tarif pajak baris PO
+	MatchExceptionTaxRateVariance = "tax_rate_variance"
+	// jumlah yang ditagih (termasuk invoice sebelumnya) melebihi jumlah barang yang diterima
+	MatchExceptionQuantityNotReceived = "quantity_not_received"
+)
+
+// Invoice - tagihan vendor atas satu purchase order. InvoiceNumber adalah nomor dari vendor,
+// unik per vendor. MatchStatus adalah hasil three-way match terakhir. PaymentTerms, DueDate, DiscountDate
+// dan DiscountAmount diisi saat invoice disetujui
+type Invoice struct {
+	ID                  string         `json:"id"`
+	OrganizationID      string         `json:"organization_id"`
+	VendorID            string         `json:"vendor_id"`
+	VendorName          string         `json:"vendor_name"`
+	PurchaseOrderID     string         `json:"purchase_order_id"`
+	PurchaseOrderNumber string         `json:"purchase_order_number"`
+	InvoiceNumber       string         `json:"invoice_number"`
+	InvoiceDate         time.Time      `json:"invoice_date"`
+	Status              string         `json:"status"`
+	MatchStatus         string         `json:"match_status"`
+	Subtotal            float64        `json:"subtotal"`
+	TaxAmount           float64        `json:"tax_amount"`
+	TotalAmount         float64        `json:"total_amount"`
+	Notes               string         `json:"notes"`
+	SubmittedBy         string         `json:"submitted_by"`
+	MatchedAt           time.Time      `json:"matched_at"`
+	ReviewedBy          *string        `json:"reviewed_by"`
+	ReviewedAt          *time.Time     `json:"reviewed_at"`
+	ReviewNote          string         `json:"review_note"`
+	PaymentTerms        string         `json:"payment_terms"`
+	DueDate             *time.Time     `json:"due_date"`
+	DiscountDate        *time.Time     `json:"discount_date"`
+	DiscountAmount      float64        `json:"discount_amount"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+	Lines               []*InvoiceLine `json:"lines,omitempty"`
+}
+
+// InvoiceLine - baris tagihan untuk satu baris PO beserta hasil pencocokannya. OrderUnitPrice, OrderTaxRate,
+// AcceptedQuantity dan PreviouslyInvoiced adalah nilai PO, penerimaan barang dan invoice lain saat dicocokkan
+type InvoiceLine struct {
+	ID                  string   `json:"id"`
+	LineNo              int      `json:"line_no"`
+	PurchaseOrderLineID string   `json:"purchase_order_line_id"`
+	Description         string   `json:"description"`
+	Unit                string   `json:"unit"`
+	Quantity            float64  `json:"quantity"`
+	UnitPrice           float64  `json:"unit_price"`
+	Amount              float64  `json:"amount"`
+	TaxRate             float64  `json:"tax_rate"`
+	TaxAmount           float64  `json:"tax_amount"`
+	OrderUnitPrice      float64  `json:"order_unit_price"`
+	OrderTaxRate        float64  `json:"order_tax_rate"`
+	AcceptedQuantity    float64  `json:"accepted_quantity"`
+	PreviouslyInvoiced  float64  `json:"previously_invoiced"`
+	MatchStatus         string   `json:"match_status"`
+	MatchExceptions     []string `json:"match_exceptions"`
+}
+
+// InvoiceRequest - invoice yang diajukan vendor untuk PO yang sudah dikirim kepadanya.
+// invoice_date berformat YYYY-MM-DD
+type InvoiceRequest struct {
+	PurchaseOrderID string                `json:"purchase_order_id" validate:"required,uuid"`
+	InvoiceNumber   string                `json:"invoice_number" validate:"required,max=50"`
+	InvoiceDate     string                `json:"invoice_date" validate:"required,datetime=2006-01-02"`
+	Notes           string                `json:"notes"`
+	Lines           []*InvoiceLineRequest `json:"lines" validate:"required,min=1,max=200,dive,required"`
+}
+
+// InvoiceLineRequest - jumlah dan harga yang ditagih untuk satu baris PO, tax_rate dalam persen
+type InvoiceLineRequest struct {
+	PurchaseOrderLineID string  `json:"purchase_order_line_id" validate:"required,uuid"`
+	Quantity            float64 `json:"quantity" validate:"required,gt=0"`
+	UnitPrice           float64 `json:"unit_price" validate:"gte=0"`
+	TaxRate             float64 `json:"tax_rate" validate:"gte=0,lte=100"`
+}
+
+// InvoiceApproveRequest - catatan persetujuan, wajib bila invoice disetujui walaupun hasil match exception
+type InvoiceApproveRequest struct {
+	Note string `json:"note"`
+}
+
+// InvoiceReasonRequest - alasan menahan atau menyengketakan invoice
+type InvoiceReasonRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// InvoiceFilter - filter daftar invoice, field kosong diabaikan. OldestFirst mengurutkan
+// dari yang terlama seperti antrean, ByDueDate mengurutkan dari jatuh tempo terdekat.
+// DueBy menyaring invoice yang jatuh tempo paling lambat tanggal tersebut
+type InvoiceFilter struct {
+	Status          string
+	VendorID        string
+	PurchaseOrderID string
+	DueBy           *time.Time
+	OldestFirst     bool
+	ByDueDate       bool
+}
+
+// InvoiceReview - keputusan finance atas invoice. Jadwal pembayaran (PaymentTerms, DueDate,
+// DiscountDate, DiscountAmount) hanya diisi saat invoice disetujui
+type InvoiceReview struct {
+	Status         string
+	ReviewedBy     string
+	ReviewedAt     time.Time
+	Note           string
+	PaymentTerms   string
+	DueDate        *time.Time
+	DiscountDate   *time.Time
+	DiscountAmount float64
+}
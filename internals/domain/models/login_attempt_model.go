@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// hasil percobaan login yang dicatat di login_attempts
+const (
+	LoginOutcomeSuccess            = "success"
+	LoginOutcomeInvalidCredentials = "invalid_credentials"
+	// ditolak karena akun terkunci sementara
+	LoginOutcomeLocked = "locked"
+	// ditolak karena backoff akun atau batas percobaan per IP
+	LoginOutcomeThrottled = "throttled"
+)
+
+// LoginAttempt - satu percobaan login, untuk throttle dan audit
+type LoginAttempt struct {
+	ID        int64     `json:"id"`
+	Email     string    `json:"email"`
+	IPAddress string    `json:"ip_address"`
+	UserID    *string   `json:"user_id"`
+	Outcome   string    `json:"outcome"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoginFailureStats - jumlah login gagal dan waktu gagal terakhir
+type LoginFailureStats struct {
+	Count         int
+	LastFailureAt time.Time
+}
+
+// LoginAttemptFilter - filter daftar audit login, field kosong diabaikan
+type LoginAttemptFilter struct {
+	Email     string
+	IPAddress string
+	Outcome   string
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// UserMFA - pendaftaran TOTP user, secret tersimpan terenkripsi
+type UserMFA struct {
+	UserID       string
+	Secret       string
+	EnabledAt    *time.Time
+	LastUsedStep int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// MFAEnrollResponse - secret dan URI otpauth untuk aplikasi authenticator
+type MFAEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// MFAConfirmRequest - kode TOTP pertama untuk mengaktifkan MFA
+type MFAConfirmRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// MFAConfirmResponse - recovery code hanya ditampilkan sekali
+type MFAConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFAVerifyRequest - langkah kedua login, isi code atau recovery_code
+type MFAVerifyRequest struct {
+	MFAToken     string `json:"mfa_token" validate:"required"`
+	Code         string `json:"code" validate:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code"`
+}
+
+// MFAChallengeResponse - langkah pertama login untuk user dengan MFA aktif
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int64  `json:"expires_in"` // challenge lifetime in seconds
+}
+
+// LoginResult - hasil login: user dan token, atau challenge MFA jika user memakai TOTP
+type LoginResult struct {
+	User      *UserResponse
+	Tokens    *TokenPair
+	Challenge *MFAChallengeResponse
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Organization - organisasi pembeli (tenant), data vendor, kategori dan produk terpisah per organisasi
+type Organization struct {
+	ID        string    `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OrganizationMembership - organisasi yang diikuti user beserta role-nya di organisasi tersebut
+type OrganizationMembership struct {
+	OrganizationID   string    `json:"organization_id"`
+	OrganizationCode string    `json:"organization_code"`
+	OrganizationName string    `json:"organization_name"`
+	Role             string    `json:"role"`
+	JoinedAt         time.Time `json:"joined_at"`
+}
+
+// OrganizationMember - anggota organisasi
+type OrganizationMember struct {
+	UserID       string    `json:"user_id"`
+	UserName     string    `json:"user_name"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	DepartmentID *string   `json:"department_id"`
+	CostCenterID *string   `json:"cost_center_id"`
+	JoinedAt     time.Time `json:"joined_at"`
+}
+
+// CreateOrganizationRequest - untuk membuat organisasi, pembuatnya menjadi admin organisasi.
+// code berupa huruf kecil, angka dan tanda hubung
+type CreateOrganizationRequest struct {
+	Code string `json:"code" validate:"required,min=2,max=50"`
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// AddOrganizationMemberRequest - untuk menambahkan user terdaftar ke organisasi aktif
+type AddOrganizationMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=admin procurement_officer vendor approver auditor warehouse finance"`
+}
+
+// UpdateOrganizationMemberRequest - untuk mengubah role anggota di organisasi aktif
+type UpdateOrganizationMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=admin procurement_officer vendor approver auditor warehouse finance"`
+}
+
+// SwitchOrganizationRequest - untuk pindah ke organisasi lain yang diikuti user
+type SwitchOrganizationRequest struct {
+	OrganizationID string `json:"organization_id" validate:"required,uuid"`
+}
@@ -0,0 +1,143 @@
+package models
+
+import "time"
+
+// status payment batch. Batch draft diekspor menjadi file bank (exported), lalu dikonfirmasi
+// setelah bank memproses pembayaran (paid). Batch draft atau exported dapat dibatalkan (cancelled)
+const (
+	PaymentBatchStatusDraft     = "draft"
+	PaymentBatchStatusExported  = "exported"
+	PaymentBatchStatusPaid      = "paid"
+	PaymentBatchStatusCancelled = "cancelled"
+)
+
+// PaymentBatchNumberFormat - format nomor payment batch dari nomor urut per organisasi
+const PaymentBatchNumberFormat = "PAY-%06d"
+
+// PaymentTerms - syarat pembayaran, "Net 30" berarti dibayar paling lambat 30 hari setelah tanggal invoice,
+// "2/10 Net 30" memberi diskon 2% bila dibayar dalam 10 hari
+type PaymentTerms struct {
+	DiscountRate float64 `json:"discount_rate"`
+	DiscountDays int     `json:"discount_days"`
+	NetDays      int     `json:"net_days"`
+}
+
+// PaymentScheduleEntry - invoice approved yang menunggu dibayar beserta jatuh tempo dan diskon pembayaran
+// awal. DiscountAvailable, AmountPayable, DaysUntilDue dan Overdue dihitung terhadap tanggal AsOf
+type PaymentScheduleEntry struct {
+	InvoiceID           string     `json:"invoice_id"`
+	InvoiceNumber       string     `json:"invoice_number"`
+	InvoiceDate         time.Time  `json:"invoice_date"`
+	VendorID            string     `json:"vendor_id"`
+	VendorName          string     `json:"vendor_name"`
+	PurchaseOrderID     string     `json:"purchase_order_id"`
+	PurchaseOrderNumber string     `json:"purchase_order_number"`
+	PaymentTerms        string     `json:"payment_terms"`
+	TotalAmount         float64    `json:"total_amount"`
+	DueDate             *time.Time `json:"due_date"`
+	DiscountDate        *time.Time `json:"discount_date"`
+	DiscountAmount      float64    `json:"discount_amount"`
+	AsOf                time.Time  `json:"as_of"`
+	DiscountAvailable   bool       `json:"discount_available"`
+	AmountPayable       float64    `json:"amount_payable"`
+	DaysUntilDue        int        `json:"days_until_due"`
+	Overdue             bool       `json:"overdue"`
+}
+
+// PaymentScheduleFilter - filter jadwal pembayaran, field kosong diabaikan. AsOf adalah tanggal
+// pembayaran yang direncanakan, default hari ini
+type PaymentScheduleFilter struct {
+	VendorID string
+	DueBy    *time.Time
+	AsOf     time.Time
+}
+
+// PaymentBatch - kumpulan invoice approved yang dibayar pada tanggal yang sama. Diskon pembayaran
+// awal diambil untuk invoice yang batas diskonnya belum lewat pada PaymentDate
+type PaymentBatch struct {
+	ID               string              `json:"id"`
+	OrganizationID   string              `json:"organization_id"`
+	Number           string              `json:"number"`
+	Status           string              `json:"status"`
+	PaymentDate      time.Time           `json:"payment_date"`
+	InvoiceCount     int                 `json:"invoice_count"`
+	TotalInvoiced    float64             `json:"total_invoiced"`
+	TotalDiscount    float64             `json:"total_discount"`
+	TotalAmount      float64             `json:"total_amount"`
+	Notes            string              `json:"notes"`
+	CreatedBy        string              `json:"created_by"`
+	ExportFile       string              `json:"export_file"`
+	ExportedAt       *time.Time          `json:"exported_at"`
+	ExportedBy       *string             `json:"exported_by"`
+	PaymentReference string              `json:"payment_reference"`
+	PaidAt           *time.Time          `json:"paid_at"`
+	PaidBy           *string             `json:"paid_by"`
+	CancelledAt      *time.Time          `json:"cancelled_at"`
+	CreatedAt        time.Time           `json:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at"`
+	Items            []*PaymentBatchItem `json:"items,omitempty"`
+}
+
+// PaymentBatchItem - satu invoice dalam payment batch, PaymentAmount = InvoiceAmount - DiscountAmount
+type PaymentBatchItem struct {
+	ID                  string     `json:"id"`
+	InvoiceID           string     `json:"invoice_id"`
+	InvoiceNumber       string     `json:"invoice_number"`
+	InvoiceDate         time.Time  `json:"invoice_date"`
+	DueDate             *time.Time `json:"due_date"`
+	VendorID            string     `json:"vendor_id"`
+	VendorName          string     `json:"vendor_name"`
+	PurchaseOrderNumber string     `json:"purchase_order_number"`
+	InvoiceAmount       float64    `json:"invoice_amount"`
+	DiscountAmount      float64    `json:"discount_amount"`
+	PaymentAmount       float64    `json:"payment_amount"`
+}
+
+// PaymentBatchRequest - invoice approved yang akan dibayar, payment_date berformat YYYY-MM-DD
+type PaymentBatchRequest struct {
+	PaymentDate string   `json:"payment_date" validate:"required,datetime=2006-01-02"`
+	InvoiceIDs  []string `json:"invoice_ids" validate:"required,min=1,max=200,dive,uuid"`
+	Notes       string   `json:"notes"`
+}
+
+// PaymentBatchConfirmRequest - referensi pembayaran dari bank, misalnya nomor transaksi
+type PaymentBatchConfirmRequest struct {
+	Reference string `json:"reference" validate:"required,max=100"`
+}
+
+// PaymentBatchFilter - filter daftar payment batch, field kosong diabaikan. VendorID menyaring
+// batch yang memuat invoice vendor tersebut
+type PaymentBatchFilter struct {
+	Status   string
+	VendorID string
+}
+
+// PaymentBatchTransition - perubahan status payment batch, field kosong tidak diubah.
+// InvoiceStatus adalah status baru invoice dalam batch, kosong bila tidak berubah
+type PaymentBatchTransition struct {
+	Status           string
+	ExportFile       *string
+	ExportedAt       *time.Time
+	ExportedBy       *string
+	PaymentReference *string
+	PaidAt           *time.Time
+	PaidBy           *string
+	CancelledAt      *time.Time
+	InvoiceStatus    string
+}
+
+// Remittance - remittance advice untuk vendor, bagian payment batch yang sudah dibayar
+// berisi invoice vendor tersebut
+type Remittance struct {
+	PaymentBatchID   string              `json:"payment_batch_id"`
+	Number           string              `json:"number"`
+	PaymentDate      time.Time           `json:"payment_date"`
+	PaidAt           *time.Time          `json:"paid_at"`
+	PaymentReference string              `json:"payment_reference"`
+	VendorID         string              `json:"vendor_id"`
+	VendorName       string              `json:"vendor_name"`
+	TotalInvoiced    float64             `json:"total_invoiced"`
+	TotalDiscount    float64             `json:"total_discount"`
+	TotalAmount      float64             `json:"total_amount"`
+	Items            []*PaymentBatchItem `json:"items"`
+}
@@ -62,6 +62,7 @@ type Product struct {
 	ProductCategoryName 	string
 	VendorID           		string
 	VendorName         		string
+	OrganizationID     		string
 	CreatedAt          		time.Time 
 	UpdatedAt		   		time.Time 	
 }
\ No newline at end of file
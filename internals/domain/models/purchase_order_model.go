@@ -0,0 +1,181 @@
+package models
+
+import "time"
+
+// status purchase order. PO draft diajukan ke approval (submitted), setelah approved dikirim ke vendor (sent)
+// lalu diterima (acknowledged) atau ditolak vendor (rejected). PO yang ditolak approver juga menjadi rejected
+const (
+	PurchaseOrderStatusDraft             = "draft"
+	PurchaseOrderStatusSubmitted         = "submitted"
+	PurchaseOrderStatusApproved          = "approved"
+	PurchaseOrderStatusSent              = "sent"
+	PurchaseOrderStatusAcknowledged      = "acknowledged"
+	PurchaseOrderStatusRejected          = "rejected"
+	PurchaseOrderStatusPartiallyReceived = "partially_received"
+	PurchaseOrderStatusClosed            = "closed"
+	PurchaseOrderStatusCancelled         = "cancelled"
+)
+
+// PurchaseOrderNumberFormat - format nomor PO dari nomor urut per organisasi
+const PurchaseOrderNumberFormat = "PO-%06d"
+
+// PurchaseOrder - pesanan pembelian ke satu vendor. Nomor PO berurutan per organisasi,
+// Revision bertambah setiap change order
+type PurchaseOrder struct {
+	ID              string               `json:"id"`
+	OrganizationID  string               `json:"organization_id"`
+	Number          string               `json:"number"`
+	Revision        int                  `json:"revision"`
+	VendorID        string               `json:"vendor_id"`
+	VendorName      string               `json:"vendor_name"`
+	RFQID           *string              `json:"rfq_id"`
+	AwardID         *string              `json:"award_id"`
+	Status          string               `json:"status"`
+	ShipTo          string               `json:"ship_to"`
+	PaymentTerms    string               `json:"payment_terms"`
+	DeliveryDate    *time.Time           `json:"delivery_date"`
+	Notes           string               `json:"notes"`
+	Subtotal        float64              `json:"subtotal"`
+	TaxAmount       float64              `json:"tax_amount"`
+	TotalAmount     float64              `json:"total_amount"`
+	CreatedBy       string               `json:"created_by"`
+	SubmittedAt     *time.Time           `json:"submitted_at"`
+	ApprovedAt      *time.Time           `json:"approved_at"`
+	ApprovedBy      *string              `json:"approved_by"`
+	SentAt          *time.Time           `json:"sent_at"`
+	AcknowledgedAt  *time.Time           `json:"acknowledged_at"`
+	VendorNote      string               `json:"vendor_note"`
+	RejectedAt      *time.Time           `json:"rejected_at"`
+	RejectionReason string               `json:"rejection_reason"`
+	ClosedAt        *time.Time           `json:"closed_at"`
+	CancelledAt     *time.Time           `json:"cancelled_at"`
+	CreatedAt       time.Time            `json:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at"`
+	Lines           []*PurchaseOrderLine `json:"lines,omitempty"`
+}
+
+// PurchaseOrderLine - baris pesanan, produk katalog atau barang bebas (ProductID kosong).
+// Amount belum termasuk pajak, pajak baris = Amount × TaxRate / 100
+type PurchaseOrderLine struct {
+	ID          string  `json:"id"`
+	LineNo      int     `json:"line_no"`
+	ProductID   *string `json:"product_id"`
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	Unit        string  `json:"unit"`
+	UnitPrice   float64 `json:"unit_price"`
+	Amount      float64 `json:"amount"`
+	TaxRate     float64 `json:"tax_rate"`
+	TaxAmount   float64 `json:"tax_amount"`
+}
+
+// PurchaseOrderRequest - untuk membuat draft PO ke vendor
+type PurchaseOrderRequest struct {
+	VendorID string `json:"vendor_id" validate:"required,uuid"`
+	PurchaseOrderDetails
+}
+
+// PurchaseOrderDetails - isi PO yang dapat diubah: pada draft langsung, setelah approved lewat change order.
+// delivery_date berformat YYYY-MM-DD
+type PurchaseOrderDetails struct {
+	ShipTo       string                      `json:"ship_to" validate:"required"`
+	PaymentTerms string                      `json:"payment_terms" validate:"max=50"`
+	DeliveryDate string                      `json:"delivery_date" validate:"omitempty,datetime=2006-01-02"`
+	Notes        string                      `json:"notes"`
+	Lines        []*PurchaseOrderLineRequest `json:"lines" validate:"required,min=1,max=200,dive,required"`
+}
+
+// PurchaseOrderLineRequest - baris PO, description wajib untuk barang tanpa product_id.
+// unit_price kosong pada produk katalog diisi harga produk, tax_rate dalam persen
+type PurchaseOrderLineRequest struct {
+	ProductID   *string `json:"product_id" validate:"omitempty,uuid"`
+	Description string  `json:"description" validate:"required_without=ProductID"`
+	Quantity    float64 `json:"quantity" validate:"required,gt=0"`
+	Unit        string  `json:"unit" validate:"omitempty,max=20"`
+	UnitPrice   float64 `json:"unit_price" validate:"gte=0"`
+	TaxRate     float64 `json:"tax_rate" validate:"gte=0,lte=100"`
+}
+
+// ChangeOrderRequest - perubahan PO yang sudah disetujui, PO kembali ke draft dengan revisi baru
+type ChangeOrderRequest struct {
+	Reason string `json:"reason" validate:"required"`
+	PurchaseOrderDetails
+}
+
+// PurchaseOrderRejectRequest - alasan vendor menolak PO
+type PurchaseOrderRejectRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// PurchaseOrderAcknowledgeRequest - catatan opsional vendor saat menerima PO
+type PurchaseOrderAcknowledgeRequest struct {
+	Note string `json:"note"`
+}
+
+// PurchaseOrderFilter - filter daftar purchase order, field kosong diabaikan.
+// SentOnly hanya menampilkan PO yang sudah pernah dikirim ke vendor
+type PurchaseOrderFilter struct {
+	Status   string
+	VendorID string
+	AwardID  string
+	SentOnly bool
+}
+
+// PurchaseOrderTransition - perubahan status purchase order, field kosong tidak diubah
+type PurchaseOrderTransition struct {
+	Status          string
+	SubmittedAt     *time.Time
+	ApprovedAt      *time.Time
+	ApprovedBy      *string
+	SentAt          *time.Time
+	AcknowledgedAt  *time.Time
+	VendorNote      *string
+	RejectedAt      *time.Time
+	RejectionReason *string
+	ClosedAt        *time.Time
+	CancelledAt     *time.Time
+}
+
+// PurchaseOrderRevision - riwayat change order, Snapshot berisi PO sebelum diubah
+type PurchaseOrderRevision struct {
+	ID              string         `json:"id"`
+	PurchaseOrderID string         `json:"purchase_order_id"`
+	Revision        int            `json:"revision"`
+	Reason          string         `json:"reason"`
+	ChangedBy       *string        `json:"changed_by"`
+	ChangedAt       time.Time      `json:"changed_at"`
+	Snapshot        *PurchaseOrder `json:"snapshot"`
+}
+
+// PurchaseOrderDocument - dokumen PO terstruktur untuk dicetak atau dikirim ke sistem vendor
+type PurchaseOrderDocument struct {
+	Number       string                   `json:"number"`
+	Revision     int                      `json:"revision"`
+	Status       string                   `json:"status"`
+	IssueDate    time.Time                `json:"issue_date"`
+	Buyer        PurchaseOrderParty       `json:"buyer"`
+	Vendor       PurchaseOrderParty       `json:"vendor"`
+	ShipTo       string                   `json:"ship_to"`
+	PaymentTerms string                   `json:"payment_terms"`
+	DeliveryDate *time.Time               `json:"delivery_date"`
+	Notes        string                   `json:"notes"`
+	Lines        []*PurchaseOrderLine     `json:"lines"`
+	Taxes        []*PurchaseOrderTaxTotal `json:"taxes"`
+	Subtotal     float64                  `json:"subtotal"`
+	TaxAmount    float64                  `json:"tax_amount"`
+	TotalAmount  float64                  `json:"total_amount"`
+}
+
+// PurchaseOrderParty - pihak pembeli atau vendor pada dokumen PO
+type PurchaseOrderParty struct {
+	ID   string `json:"id"`
+	Code string `json:"code,omitempty"`
+	Name string `json:"name"`
+}
+
+// PurchaseOrderTaxTotal - rekap pajak per tarif pada dokumen PO
+type PurchaseOrderTaxTotal struct {
+	TaxRate       float64 `json:"tax_rate"`
+	TaxableAmount float64 `json:"taxable_amount"`
+	TaxAmount     float64 `json:"tax_amount"`
+}
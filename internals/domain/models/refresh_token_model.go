@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// RefreshToken - refresh token tersimpan (hanya hash), satu family per login
+type RefreshToken struct {
+	ID     string
+	UserID string
+	// organization the tokens of the family act in
+	OrganizationID string
+	FamilyID       string
+	TokenHash      string
+	ExpiresAt      time.Time
+	RevokedAt      *time.Time
+	CreatedAt      time.Time
+}
+
+// TokenPair - access token dan refresh token hasil login / refresh
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64 // access token lifetime in seconds
+}
+
+// RefreshTokenRequest - untuk refresh dan logout
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
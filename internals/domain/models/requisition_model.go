@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// status permintaan pembelian
+const (
+	RequisitionStatusDraft     = "draft"
+	RequisitionStatusSubmitted = "submitted"
+	RequisitionStatusApproved  = "approved"
+	RequisitionStatusRejected  = "rejected"
+	RequisitionStatusCancelled = "cancelled"
+)
+
+// PurchaseRequisition - permintaan pembelian barang oleh karyawan, dibebankan ke departemen dan cost center
+type PurchaseRequisition struct {
+	ID              string                     `json:"id"`
+	OrganizationID  string                     `json:"organization_id"`
+	RequesterID     string                     `json:"requester_id"`
+	DepartmentID    *string                    `json:"department_id"`
+	CostCenterID    *string                    `json:"cost_center_id"`
+	Title           string                     `json:"title"`
+	Justification   string                     `json:"justification"`
+	Status          string                     `json:"status"`
+	TotalAmount     float64                    `json:"total_amount"`
+	SubmittedAt     *time.Time                 `json:"submitted_at"`
+	DecidedAt       *time.Time                 `json:"decided_at"`
+	DecidedBy       *string                    `json:"decided_by"`
+	DecisionComment string                     `json:"decision_comment"`
+	CreatedAt       time.Time                  `json:"created_at"`
+	UpdatedAt       time.Time                  `json:"updated_at"`
+	Lines           []*PurchaseRequisitionLine `json:"lines,omitempty"`
+}
+
+// PurchaseRequisitionLine - satu baris barang, produk katalog atau barang bebas (ProductID kosong)
+type PurchaseRequisitionLine struct {
+	ID          string    `json:"id"`
+	LineNo      int       `json:"line_no"`
+	ProductID   *string   `json:"product_id"`
+	Description string    `json:"description"`
+	Quantity    float64   `json:"quantity"`
+	Unit        string    `json:"unit"`
+	UnitPrice   float64   `json:"unit_price"`
+	Amount      float64   `json:"amount"`
+	NeededBy    time.Time `json:"needed_by"`
+}
+
+// RequisitionRequest - untuk membuat atau mengubah draft permintaan pembelian.
+// department_id dan cost_center_id kosong diisi dari default pembelian user
+type RequisitionRequest struct {
+	Title         string                    `json:"title" validate:"required,max=255"`
+	Justification string                    `json:"justification" validate:"required"`
+	DepartmentID  *string                   `json:"department_id" validate:"omitempty,uuid"`
+	CostCenterID  *string                   `json:"cost_center_id" validate:"omitempty,uuid"`
+	Lines         []*RequisitionLineRequest `json:"lines" validate:"required,min=1,max=200,dive,required"`
+}
+
+// RequisitionLineRequest - baris permintaan, description wajib untuk barang tanpa product_id.
+// unit_price kosong pada produk katalog diisi harga produk. needed_by berformat YYYY-MM-DD
+type RequisitionLineRequest struct {
+	ProductID   *string `json:"product_id" validate:"omitempty,uuid"`
+	Description string  `json:"description" validate:"required_without=ProductID"`
+	Quantity    float64 `json:"quantity" validate:"required,gt=0"`
+	Unit        string  `json:"unit" validate:"omitempty,max=20"`
+	UnitPrice   float64 `json:"unit_price" validate:"gte=0"`
+	NeededBy    string  `json:"needed_by" validate:"required,datetime=2006-01-02"`
+}
+
+// RequisitionFilter - filter daftar permintaan pembelian, field kosong diabaikan
+type RequisitionFilter struct {
+	Status       string
+	RequesterID  string
+	DepartmentID string
+}
+
+// RequisitionTransition - perubahan status permintaan pembelian
+type RequisitionTransition struct {
+	Status          string
+	SubmittedAt     *time.Time
+	DecidedAt       *time.Time
+	DecidedBy       *string
+	DecisionComment string
+}
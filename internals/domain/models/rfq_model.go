@@ -0,0 +1,163 @@
+package models
+
+import "time"
+
+// status RFQ, RFQ open menerima penawaran sampai response_deadline lalu di-award
+const (
+	RFQStatusDraft     = "draft"
+	RFQStatusOpen      = "open"
+	RFQStatusAwarded   = "awarded"
+	RFQStatusCancelled = "cancelled"
+)
+
+// RFQ - permintaan penawaran harga (request for quotation) ke vendor yang diundang.
+// Penawaran tersegel (Sealed) sampai response_deadline lewat, baru setelah itu bisa dibandingkan
+type RFQ struct {
+	ID               string       `json:"id"`
+	OrganizationID   string       `json:"organization_id"`
+	RequisitionID    *string      `json:"requisition_id"`
+	Title            string       `json:"title"`
+	Description      string       `json:"description"`
+	Status           string       `json:"status"`
+	ResponseDeadline time.Time    `json:"response_deadline"`
+	CreatedBy        string       `json:"created_by"`
+	PublishedAt      *time.Time   `json:"published_at"`
+	Sealed           bool         `json:"sealed"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+	Lines            []*RFQLine   `json:"lines,omitempty"`
+	Vendors          []*RFQVendor `json:"vendors,omitempty"`
+}
+
+// RFQLine - barang yang dimintakan harganya, produk katalog atau barang bebas (ProductID kosong)
+type RFQLine struct {
+	ID          string     `json:"id"`
+	LineNo      int        `json:"line_no"`
+	ProductID   *string    `json:"product_id"`
+	Description string     `json:"description"`
+	Quantity    float64    `json:"quantity"`
+	Unit        string     `json:"unit"`
+	NeededBy    *time.Time `json:"needed_by"`
+}
+
+// RFQVendor - vendor yang diundang mengajukan penawaran
+type RFQVendor struct {
+	VendorID   string    `json:"vendor_id"`
+	VendorName string    `json:"vendor_name"`
+	InvitedAt  time.Time `json:"invited_at"`
+}
+
+// RFQRequest - untuk membuat atau mengubah draft RFQ. Baris kosong disalin dari
+// permintaan pembelian (requisition_id) yang sudah disetujui
+type RFQRequest struct {
+	Title            string            `json:"title" validate:"required,max=255"`
+	Description      string            `json:"description"`
+	RequisitionID    *string           `json:"requisition_id" validate:"omitempty,uuid"`
+	ResponseDeadline time.Time         `json:"response_deadline" validate:"required"`
+	Lines            []*RFQLineRequest `json:"lines" validate:"max=200,dive,required"`
+	VendorIDs        []string          `json:"vendor_ids" validate:"max=100,dive,uuid"`
+}
+
+// RFQLineRequest - baris RFQ, description wajib untuk barang tanpa product_id. needed_by berformat YYYY-MM-DD
+type RFQLineRequest struct {
+	ProductID   *string `json:"product_id" validate:"omitempty,uuid"`
+	Description string  `json:"description" validate:"required_without=ProductID"`
+	Quantity    float64 `json:"quantity" validate:"required,gt=0"`
+	Unit        string  `json:"unit" validate:"omitempty,max=20"`
+	NeededBy    string  `json:"needed_by" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// RFQInviteRequest - mengundang vendor tambahan ke RFQ
+type RFQInviteRequest struct {
+	VendorIDs []string `json:"vendor_ids" validate:"required,min=1,max=100,dive,uuid"`
+}
+
+// RFQFilter - filter daftar RFQ, field kosong diabaikan. VendorID membatasi ke RFQ yang sudah
+// dipublikasikan (bukan draft) dan mengundang vendor tersebut
+type RFQFilter struct {
+	Status   string
+	VendorID string
+}
+
+// RFQTransition - perubahan status RFQ
+type RFQTransition struct {
+	Status      string
+	PublishedAt *time.Time
+}
+
+// Quotation - penawaran satu vendor untuk satu RFQ, diajukan ulang menggantikan penawaran sebelumnya
+type Quotation struct {
+	ID             string           `json:"id"`
+	OrganizationID string           `json:"organization_id"`
+	RFQID          string           `json:"rfq_id"`
+	VendorID       string           `json:"vendor_id"`
+	VendorName     string           `json:"vendor_name"`
+	Notes          string           `json:"notes"`
+	TotalAmount    float64          `json:"total_amount"`
+	SubmittedAt    time.Time        `json:"submitted_at"`
+	CreatedAt      time.Time        `json:"created_at"`
+	Lines          []*QuotationLine `json:"lines,omitempty"`
+}
+
+// QuotationLine - harga, lead time dan masa berlaku penawaran untuk satu baris RFQ
+type QuotationLine struct {
+	ID           string    `json:"id"`
+	RFQLineID    string    `json:"rfq_line_id"`
+	UnitPrice    float64   `json:"unit_price"`
+	Amount       float64   `json:"amount"`
+	LeadTimeDays int       `json:"lead_time_days"`
+	ValidUntil   time.Time `json:"valid_until"`
+}
+
+// QuotationRequest - penawaran vendor, baris RFQ yang tidak ditawar boleh dilewati
+type QuotationRequest struct {
+	Notes string                  `json:"notes"`
+	Lines []*QuotationLineRequest `json:"lines" validate:"required,min=1,max=200,dive,required"`
+}
+
+// QuotationLineRequest - valid_until berformat YYYY-MM-DD
+type QuotationLineRequest struct {
+	RFQLineID    string  `json:"rfq_line_id" validate:"required,uuid"`
+	UnitPrice    float64 `json:"unit_price" validate:"required,gt=0"`
+	LeadTimeDays int     `json:"lead_time_days" validate:"gte=0,lte=3650"`
+	ValidUntil   string  `json:"valid_until" validate:"required,datetime=2006-01-02"`
+}
+
+// QuotationComparison - perbandingan penawaran setelah batas waktu, vendor diurutkan dari
+// penawaran lengkap termurah, penawaran per baris diurutkan dari harga termurah
+type QuotationComparison struct {
+	RFQ     *RFQ                `json:"rfq"`
+	Vendors []*QuotationSummary `json:"vendors"`
+	Lines   []*ComparisonLine   `json:"lines"`
+}
+
+// QuotationSummary - ringkasan penawaran satu vendor, Complete berarti semua baris RFQ ditawar
+type QuotationSummary struct {
+	QuotationID string    `json:"quotation_id"`
+	VendorID    string    `json:"vendor_id"`
+	VendorName  string    `json:"vendor_name"`
+	TotalAmount float64   `json:"total_amount"`
+	LinesQuoted int       `json:"lines_quoted"`
+	Complete    bool      `json:"complete"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// ComparisonLine - penawaran semua vendor untuk satu baris RFQ
+type ComparisonLine struct {
+	Line   *RFQLine           `json:"line"`
+	Offers []*ComparisonOffer `json:"offers"`
+}
+
+// ComparisonOffer - penawaran satu vendor pada satu baris, Lowest dan Fastest menandai harga
+// termurah dan lead time tercepat di baris tersebut
+type ComparisonOffer struct {
+	QuotationID  string    `json:"quotation_id"`
+	VendorID     string    `json:"vendor_id"`
+	VendorName   string    `json:"vendor_name"`
+	UnitPrice    float64   `json:"unit_price"`
+	Amount       float64   `json:"amount"`
+	LeadTimeDays int       `json:"lead_time_days"`
+	ValidUntil   time.Time `json:"valid_until"`
+	Lowest       bool      `json:"lowest"`
+	Fastest      bool      `json:"fastest"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// RevokedToken - access token yang dicabut sebelum kadaluarsa, dicari berdasarkan claim jti
+type RevokedToken struct {
+	TokenID   string
+	UserID    string
+	ExpiresAt time.Time
+	RevokedAt time.Time
+}
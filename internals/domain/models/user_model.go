@@ -6,14 +6,16 @@ import "time"
 type CreateUserRequest struct {
     UserName    string `json:"user_name" validate:"required,min=3,max=20"`
     Email       string `json:"email" validate:"email,required"`
-    Password    string `json:"password" validate:"required,min=6,max=50"`
+    Password    string `json:"password" validate:"required"` // checked by the password policy
+    // organization joined on registration, the configured default organization when empty
+    OrganizationCode string `json:"organization_code" validate:"omitempty,max=50"`
 }
 
 // UpdateUserRequest - untuk update operation dengan pointer
 type UpdateUserRequest struct {
     UserName    string `json:"user_name" validate:"omitempty,min=3,max=20"`
     Email       string `json:"email" validate:"omitempty,email"`
-    Password    string `json:"password" validate:"omitempty,min=6,max=50"`
+    Password    string `json:"password"` // checked by the password policy
 }
 type UpdateUserResponse struct {
     ID          string    `json:"id"`
@@ -28,6 +30,9 @@ type UserResponse struct {
     UserName    string    `json:"user_name"`
     Email       string    `json:"email"`
     Role        string    `json:"role"`
+    // organization the role applies to
+    OrganizationID string `json:"organization_id,omitempty"`
+    EmailVerifiedAt *time.Time `json:"email_verified_at"`
     CreatedAt   time.Time `json:"created_at"`
     UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -36,12 +41,20 @@ type UserResponse struct {
 type LoginRequest struct {
     Email       string `json:"email" validate:"email,required"`
     Password    string `json:"password" validate:"required"`
+    // organization to log in to, the oldest membership when empty
+    OrganizationID string `json:"organization_id" validate:"omitempty,uuid"`
+}
+
+// UpdateUserRoleRequest - untuk admin mengubah role user di organisasi aktif
+type UpdateUserRoleRequest struct {
+    UserID  string `json:"user_id" validate:"required,uuid"`
+    Role    string `json:"role" validate:"required,oneof=admin procurement_officer vendor approver auditor warehouse finance"`
 }
 
 // ChangePasswordRequest - untuk change password
 type ChangePasswordRequest struct {
     OldPassword     string `json:"old_password" validate:"required"`
-    NewPassword     string `json:"new_password" validate:"required,min=6,max=50"`
+    NewPassword     string `json:"new_password" validate:"required"` // checked by the password policy
 }
 
 
@@ -52,6 +65,13 @@ type User struct {
     Email     string   
     Password  string    
     Role      string
+    // nil until the user opens the verification link
+    EmailVerifiedAt *time.Time
     CreatedAt time.Time 
     UpdatedAt time.Time
 }
+
+// ResendVerificationRequest - untuk mengirim ulang email verifikasi
+type ResendVerificationRequest struct {
+    Email       string `json:"email" validate:"required,email"`
+}
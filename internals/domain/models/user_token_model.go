@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// tujuan token yang dikirim lewat email
+const (
+	UserTokenPurposePasswordReset     = "password_reset"
+	UserTokenPurposeEmailVerification = "email_verification"
+)
+
+// UserToken - token sekali pakai yang dikirim ke email user (hanya hash yang disimpan)
+type UserToken struct {
+	ID        string
+	UserID    string
+	Purpose   string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// ForgotPasswordRequest - untuk meminta link reset password
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest - untuk mengganti password dengan token dari email
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"` // checked by the password policy
+}
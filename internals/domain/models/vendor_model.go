@@ -4,13 +4,30 @@ import (
 	"time"
 )
 
+// status onboarding vendor, vendor baru menunggu approval vendor_onboarding sebelum bisa
+// diundang ke RFQ atau menerima PO
+const (
+	VendorStatusPending  = "pending"
+	VendorStatusApproved = "approved"
+	VendorStatusRejected = "rejected"
+	VendorStatusReturned = "returned"
+)
+
 // Vendor represents a vendor in the system.
 type Vendor struct {
 	ID        		string 
 	VendorName 		string
 	Description 	string
+	// syarat pembayaran default untuk PO dan invoice vendor, misalnya "Net 30" atau "2/10 Net 30"
+	PaymentTerms 	string
+	// rekening tujuan transfer pembayaran, vendor tanpa rekening tidak bisa dibayar lewat file bank
+	BankCode 		string
+	BankAccountNumber string
+	BankAccountHolder string
+	Status 			string
 	UserID    		string
 	UserName 		string
+	OrganizationID 	string
 	CreatedAt 		time.Time
 	UpdatedAt 		time.Time
 }
@@ -18,6 +35,10 @@ type Vendor struct {
 type CreateVendorRequest struct {
 	VendorName 		string `json:"vendor_name" validate:"required"`
 	Description 	string `json:"description" validate:"required"`
+	PaymentTerms 	string `json:"payment_terms" validate:"max=50"`
+	BankCode 		string `json:"bank_code" validate:"omitempty,max=20"`
+	BankAccountNumber string `json:"bank_account_number" validate:"omitempty,number,max=34"`
+	BankAccountHolder string `json:"bank_account_holder" validate:"max=100"`
 	// UserID     		string `json:"user_id" validate:"required,uuid"`
 }
 
@@ -25,6 +46,11 @@ type CreateVendorResponse struct {
 	ID          	string    `json:"id"`
 	VendorName  	string    `json:"vendor_name"`
 	Description 	string    `json:"description"`
+	PaymentTerms 	string    `json:"payment_terms"`
+	BankCode 		string    `json:"bank_code"`
+	BankAccountNumber string    `json:"bank_account_number"`
+	BankAccountHolder string    `json:"bank_account_holder"`
+	Status 			string    `json:"status"`
 	UserID      	string    `json:"user_id"`
 	CreatedAt   	time.Time `json:"created_at"`
 	UpdatedAt   	time.Time `json:"updated_at"`
@@ -33,24 +59,61 @@ type CreateVendorResponse struct {
 type UpdateVendorRequest struct {
 	VendorName 		string `json:"vendor_name" validate:"required"`
 	Description 	string `json:"description" validate:"required"`
-	UserID    		string `json:"user_id" validate:"required,uuid"`
+	PaymentTerms 	string `json:"payment_terms" validate:"max=50"`
+	BankCode 		string `json:"bank_code" validate:"omitempty,max=20"`
+	BankAccountNumber string `json:"bank_account_number" validate:"omitempty,number,max=34"`
+	BankAccountHolder string `json:"bank_account_holder" validate:"max=100"`
+	UserID    		string `json:"user_id" validate:"omitempty,uuid"`
 }
 
 type UpdateVendorResponse struct {
 	ID          	string    `json:"id"`
 	VendorName  	string    `json:"vendor_name"`
 	Description 	string    `json:"description"`
+	PaymentTerms 	string    `json:"payment_terms"`
+	BankCode 		string    `json:"bank_code"`
+	BankAccountNumber string    `json:"bank_account_number"`
+	BankAccountHolder string    `json:"bank_account_holder"`
+	Status 			string    `json:"status"`
 	UserID      	string    `json:"user_id"`
 	CreatedAt   	time.Time `json:"created_at"`
 	UpdatedAt   	time.Time `json:"updated_at"`
 }
 
+// VendorResponse - rekening bank hanya diisi untuk pemilik vendor, admin dan finance
 type VendorResponse struct {
 	ID          	string    `json:"id"`
 	VendorName  	string    `json:"vendor_name"`
 	Description 	string    `json:"description"`
+	PaymentTerms 	string    `json:"payment_terms"`
+	BankCode 		string    `json:"bank_code,omitempty"`
+	BankAccountNumber string    `json:"bank_account_number,omitempty"`
+	BankAccountHolder string    `json:"bank_account_holder,omitempty"`
+	Status 			string    `json:"status"`
 	UserID      	string    `json:"user_id"`
 	UserName    	string    `json:"user_name"`
 	CreatedAt   	time.Time `json:"created_at"`
 	UpdatedAt   	time.Time `json:"updated_at"`
-}
\ No newline at end of file
+}
+
+// VendorBankAccountRequest - rekening bank baru vendor, diganti oleh admin atau finance
+type VendorBankAccountRequest struct {
+	BankCode          string `json:"bank_code" validate:"required,max=20"`
+	BankAccountNumber string `json:"bank_account_number" validate:"required,number,max=34"`
+	BankAccountHolder string `json:"bank_account_holder" validate:"required,max=100"`
+}
+
+// VendorBankAccountChange - riwayat perubahan rekening bank vendor, rekening lama dan baru
+// beserta user yang mengubahnya
+type VendorBankAccountChange struct {
+	ID                   string    `json:"id"`
+	VendorID             string    `json:"vendor_id"`
+	OldBankCode          string    `json:"old_bank_code"`
+	OldBankAccountNumber string    `json:"old_bank_account_number"`
+	OldBankAccountHolder string    `json:"old_bank_account_holder"`
+	BankCode             string    `json:"bank_code"`
+	BankAccountNumber    string    `json:"bank_account_number"`
+	BankAccountHolder    string    `json:"bank_account_holder"`
+	ChangedBy            *string   `json:"changed_by"`
+	ChangedAt            time.Time `json:"changed_at"`
+}
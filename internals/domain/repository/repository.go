@@ -0,0 +1,380 @@
+package repository
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"time"
+)
+
+// UserRepository is the storage contract used by the user and auth usecases
+type UserRepository interface {
+	GetAll(ctx context.Context, limit, offset int) ([]models.UserResponse, error)
+	GetUserByID(ctx context.Context, id string) (*models.User, error)
+	Create(ctx context.Context, user *models.CreateUserRequest) (*models.User, error)
+	UpdateUser(ctx context.Context, id string, user *models.UpdateUserRequest) (*models.UserResponse, error)
+	Delete(ctx context.Context, id string) error
+	Authenticate(ctx context.Context, email string) (*models.User, error)
+	IsUserExists(ctx context.Context, email string) (bool, error)
+	GetTotalCount(ctx context.Context) (int64, error)
+	UpdatePassword(ctx context.Context, id string, newPassword string) error
+	// ReplacePasswordHash swaps the stored hash only while it still equals oldHash,
+	// it returns false when the password changed in the meantime
+	ReplacePasswordHash(ctx context.Context, id, oldHash, newHash string) (bool, error)
+	MarkEmailVerified(ctx context.Context, id string) error
+}
+
+// VendorRepository is the storage contract used by the vendor and product usecases
+type VendorRepository interface {
+	CreateVendor(ctx context.Context, userId string, vendorModel *models.CreateVendorRequest) (*models.Vendor, error)
+	GetAllVendors(ctx context.Context, limit, offset int) ([]*models.Vendor, error)
+	GetVendorByID(ctx context.Context, id string) (*models.Vendor, error)
+	// UpdateVendor changes the profile of the vendor, the bank account is changed through ChangeBankAccount
+	UpdateVendor(ctx context.Context, vendorID string, vendorModel *models.UpdateVendorRequest) (*models.Vendor, error)
+	DeleteVendor(ctx context.Context, id string) error
+	CountVendors(ctx context.Context) (int, error)
+	GetVendorByUserID(ctx context.Context, userID string) (*models.Vendor, error)
+	// UpdateVendorStatus moves the vendor from status from to status, it returns false when
+	// the vendor is no longer in status from
+	UpdateVendorStatus(ctx context.Context, id, from, status string) (bool, error)
+	// ChangeBankAccount replaces the bank account of the vendor and records the change in one
+	// transaction. It returns false when the account is no longer the old account of the change
+	ChangeBankAccount(ctx context.Context, change *models.VendorBankAccountChange) (bool, error)
+	// ListBankAccountChanges returns the bank account changes of a vendor, oldest first
+	ListBankAccountChanges(ctx context.Context, vendorID string) ([]*models.VendorBankAccountChange, error)
+}
+
+// ProductRepository is the storage contract used by the product usecase
+type ProductRepository interface {
+	CreateProduct(ctx context.Context, product *models.CreateProductRequest) (*models.Product, error)
+	GetAllProducts(ctx context.Context, limit, offset int) ([]*models.Product, error)
+	GetProductByID(ctx context.Context, id string) (*models.Product, error)
+	UpdateProduct(ctx context.Context, id string, product *models.UpdateProductRequest) (*models.Product, error)
+	DeleteProduct(ctx context.Context, id string) error
+	GetProductsByCategory(ctx context.Context, category string, limit, offset int) ([]*models.Product, error)
+	CountProducts(ctx context.Context) (int, error)
+}
+
+// CategoryRepository is the storage contract used by the category usecase
+type CategoryRepository interface {
+	CreateCategory(ctx context.Context, category *models.CreateCategoryRequest) (*models.Category, error)
+	GetAllCategories(ctx context.Context, limit, offset int) ([]*models.Category, error)
+	GetCategoryByID(ctx context.Context, id string) (*models.Category, error)
+	UpdateCategory(ctx context.Context, id string, category *models.UpdateCategoryRequest) (*models.Category, error)
+	DeleteCategory(ctx context.Context, id string) error
+	CountAllCategories(ctx context.Context) (int, error)
+}
+
+// RefreshTokenRepository stores hashed refresh tokens grouped by token family
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) (*models.RefreshToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	// Revoke marks a single token revoked, it returns false when the token was already revoked
+	Revoke(ctx context.Context, id string) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID string) error
+}
+
+// TokenRevocationRepository stores revoked access tokens and the per-user cut-off
+// used to revoke every session of a user at once
+type TokenRevocationRepository interface {
+	// RevokeToken stores a revoked token, revoking the same token twice is not an error
+	RevokeToken(ctx context.Context, token *models.RevokedToken) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+	// RevokeAllForUser rejects every token of the user issued at or before `before`
+	RevokeAllForUser(ctx context.Context, userID string, before time.Time) error
+	// GetRevokedBefore returns nil when the sessions of the user were never revoked
+	GetRevokedBefore(ctx context.Context, userID string) (*time.Time, error)
+}
+
+// UserTokenRepository stores hashed single-use tokens mailed to users
+type UserTokenRepository interface {
+	Create(ctx context.Context, token *models.UserToken) (*models.UserToken, error)
+	// GetByHash returns nil when no token of that purpose matches the hash
+	GetByHash(ctx context.Context, purpose, tokenHash string) (*models.UserToken, error)
+	// MarkUsed consumes an unused token, it returns false when the token was already used
+	MarkUsed(ctx context.Context, id string) (bool, error)
+	// InvalidateForUser consumes every unused token of the user for that purpose
+	InvalidateForUser(ctx context.Context, userID, purpose string) error
+}
+
+// RateLimitRepository records requests counted by the rate limiters
+type RateLimitRepository interface {
+	Record(ctx context.Context, scope, subject string) error
+	CountSince(ctx context.Context, scope, subject string, since time.Time) (int, error)
+}
+
+// MFARepository stores TOTP enrollments and hashed recovery codes
+type MFARepository interface {
+	// GetByUserID returns nil when the user never started an enrollment
+	GetByUserID(ctx context.Context, userID string) (*models.UserMFA, error)
+	// SavePending stores a new secret waiting for confirmation, replacing a previous pending one
+	SavePending(ctx context.Context, userID, secret string) error
+	// Enable activates the enrollment and replaces the recovery codes
+	Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error
+	// UseStep records an accepted time step, it returns false for a step not newer than the last one
+	UseStep(ctx context.Context, userID string, step int64) (bool, error)
+	// UseRecoveryCode consumes an unused recovery code, it returns false when none matches
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+}
+
+// LoginAttemptRepository records password logins for the login throttle and audit
+type LoginAttemptRepository interface {
+	Record(ctx context.Context, attempt *models.LoginAttempt) error
+	// AccountFailures counts invalid_credentials attempts of an email since `since`
+	// and after its latest successful login
+	AccountFailures(ctx context.Context, email string, since time.Time) (*models.LoginFailureStats, error)
+	// IPFailures counts invalid_credentials attempts from an IP address since `since`
+	IPFailures(ctx context.Context, ipAddress string, since time.Time) (*models.LoginFailureStats, error)
+	List(ctx context.Context, filter models.LoginAttemptFilter, limit, offset int) ([]*models.LoginAttempt, int, error)
+}
+
+// PasswordHistoryRepository keeps the hashes of previous passwords
+type PasswordHistoryRepository interface {
+	// Add stores a password hash and drops the oldest entries beyond the newest `keep`
+	Add(ctx context.Context, userID, passwordHash string, keep int) error
+	// Recent returns up to `limit` hashes, newest first
+	Recent(ctx context.Context, userID string, limit int) ([]string, error)
+}
+// APIKeyRepository stores API keys, only the hash of the secret is persisted
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) (*models.APIKey, error)
+	// GetByPrefix returns nil when no key has that prefix, it is the only lookup not scoped by tenant
+	GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	// GetByID returns nil when the key does not exist in the tenant
+	GetByID(ctx context.Context, id string) (*models.APIKey, error)
+	// ListByUser returns the keys of the user in the tenant
+	ListByUser(ctx context.Context, userID string) ([]*models.APIKey, error)
+	// Revoke marks an active key revoked, it returns false when the key was already revoked
+	Revoke(ctx context.Context, id string) (bool, error)
+	// Touch records when the key was last used
+	Touch(ctx context.Context, id string, usedAt time.Time) error
+}
+
+// OrganizationRepository stores organizations and the per-organization roles of users
+type OrganizationRepository interface {
+	// Create stores an organization, ownerID (when not empty) joins it as admin in the same transaction
+	Create(ctx context.Context, org *models.CreateOrganizationRequest, ownerID string) (*models.Organization, error)
+	// GetByID and GetByCode return nil when the organization does not exist
+	GetByID(ctx context.Context, id string) (*models.Organization, error)
+	GetByCode(ctx context.Context, code string) (*models.Organization, error)
+	// ListForUser returns the memberships of a user, oldest first
+	ListForUser(ctx context.Context, userID string) ([]*models.OrganizationMembership, error)
+	// GetMembership returns nil when the user is not a member of the organization
+	GetMembership(ctx context.Context, organizationID, userID string) (*models.OrganizationMembership, error)
+	ListMembers(ctx context.Context, organizationID string) ([]*models.OrganizationMember, error)
+	AddMember(ctx context.Context, organizationID, userID, role string) error
+	UpdateMemberRole(ctx context.Context, organizationID, userID, role string) error
+	RemoveMember(ctx context.Context, organizationID, userID string) error
+}
+
+// CostCenterRepository stores the cost centers of the tenant
+type CostCenterRepository interface {
+	Create(ctx context.Context, costCenter *models.CreateCostCenterRequest) (*models.CostCenter, error)
+	// GetByID returns nil when the cost center does not exist in the tenant
+	GetByID(ctx context.Context, id string) (*models.CostCenter, error)
+	List(ctx context.Context, limit, offset int) ([]*models.CostCenter, error)
+	Count(ctx context.Context) (int, error)
+	Update(ctx context.Context, id string, costCenter *models.UpdateCostCenterRequest) (*models.CostCenter, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// DepartmentRepository stores the department tree of the tenant and the department of each member
+type DepartmentRepository interface {
+	Create(ctx context.Context, department *models.DepartmentRequest) (*models.Department, error)
+	// GetByID returns nil when the department does not exist in the tenant
+	GetByID(ctx context.Context, id string) (*models.Department, error)
+	// List returns every department of the tenant, ordered by code
+	List(ctx context.Context) ([]*models.Department, error)
+	Update(ctx context.Context, id string, department *models.DepartmentRequest) (*models.Department, error)
+	Delete(ctx context.Context, id string) error
+	// GetAssignment returns nil when the user is not a member of the tenant
+	GetAssignment(ctx context.Context, userID string) (*models.DepartmentAssignment, error)
+	Assign(ctx context.Context, assignment *models.DepartmentAssignment) error
+}
+
+// RequisitionRepository stores purchase requisitions of the tenant with their lines
+type RequisitionRepository interface {
+	// Create stores the header and lines in one transaction
+	Create(ctx context.Context, requisition *models.PurchaseRequisition) (*models.PurchaseRequisition, error)
+	// GetByID returns nil when the requisition does not exist in the tenant, lines included
+	GetByID(ctx context.Context, id string) (*models.PurchaseRequisition, error)
+	// List returns headers without lines, newest first, with the total count of the filter
+	List(ctx context.Context, filter models.RequisitionFilter, limit, offset int) ([]*models.PurchaseRequisition, int, error)
+	// UpdateDraft replaces the header fields and lines of a draft, it returns false
+	// when the requisition is no longer a draft
+	UpdateDraft(ctx context.Context, requisition *models.PurchaseRequisition) (bool, error)
+	// Transition changes the status of a requisition still in status `from`, it returns
+	// false when the status changed in the meantime
+	Transition(ctx context.Context, id, from string, transition *models.RequisitionTransition) (bool, error)
+}
+
+// ApprovalRuleRepository stores the approval rules of the tenant with their steps
+type ApprovalRuleRepository interface {
+	// Create stores the rule and its steps in one transaction
+	Create(ctx context.Context, rule *models.ApprovalRule) (*models.ApprovalRule, error)
+	// GetByID returns nil when the rule does not exist in the tenant, steps included
+	GetByID(ctx context.Context, id string) (*models.ApprovalRule, error)
+	// List returns the rules of a document type (every type when empty) with their steps,
+	// ordered by priority then name
+	List(ctx context.Context, documentType string) ([]*models.ApprovalRule, error)
+	// Update replaces the rule fields and its steps
+	Update(ctx context.Context, rule *models.ApprovalRule) (*models.ApprovalRule, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// ApprovalRepository stores the approval requests of the tenant and the decisions on their steps
+type ApprovalRepository interface {
+	// Create stores the request and its steps in one transaction, it reports a conflict
+	// when the document already has a pending request
+	Create(ctx context.Context, request *models.ApprovalRequest) (*models.ApprovalRequest, error)
+	// GetByID returns nil when the request does not exist in the tenant, steps included
+	GetByID(ctx context.Context, id string) (*models.ApprovalRequest, error)
+	// GetPending returns the pending request of a document, nil when there is none
+	GetPending(ctx context.Context, documentType, documentID string) (*models.ApprovalRequest, error)
+	// List returns requests with their steps, newest first, with the total count of the filter
+	List(ctx context.Context, filter models.ApprovalFilter, limit, offset int) ([]*models.ApprovalRequest, int, error)
+	// ListPending returns the pending steps the filter may act on, oldest request first
+	ListPending(ctx context.Context, filter models.PendingApprovalFilter, limit, offset int) ([]*models.PendingApproval, int, error)
+	// Decide records the decision on a step still pending, it returns false when the
+	// step was decided in the meantime or when the actor, or the member they act for,
+	// already decided a step of the request
+	Decide(ctx context.Context, stepID string, decision *models.ApprovalStepDecision) (bool, error)
+	// Advance moves a pending request still at stage `fromStage`: a pending transition
+	// activates the steps of the new stage, any other status completes the request and
+	// skips its remaining steps. It returns false when the request moved in the meantime
+	Advance(ctx context.Context, requestID string, fromStage int, transition *models.ApprovalTransition) (bool, error)
+}
+
+// ApprovalDelegationRepository stores the approval delegations of the tenant
+type ApprovalDelegationRepository interface {
+	Create(ctx context.Context, delegation *models.ApprovalDelegation) (*models.ApprovalDelegation, error)
+	// List returns the delegations given or received by the user, newest first
+	List(ctx context.Context, userID string) ([]*models.ApprovalDelegation, error)
+	// Delete removes a delegation given by delegatorID
+	Delete(ctx context.Context, id, delegatorID string) error
+	// ActiveDelegators returns the users who delegated their approvals to delegateID at the given time
+	ActiveDelegators(ctx context.Context, delegateID string, at time.Time) ([]string, error)
+}
+
+// RFQRepository stores the requests for quotation of the tenant with their lines and invited vendors
+type RFQRepository interface {
+	// Create stores the header, lines and invited vendors in one transaction
+	Create(ctx context.Context, rfq *models.RFQ) (*models.RFQ, error)
+	// GetByID returns nil when the RFQ does not exist in the tenant, lines and vendors included
+	GetByID(ctx context.Context, id string) (*models.RFQ, error)
+	// List returns headers without lines and vendors, newest first, with the total count of the filter
+	List(ctx context.Context, filter models.RFQFilter, limit, offset int) ([]*models.RFQ, int, error)
+	// UpdateDraft replaces the header fields, lines and invited vendors of a draft, it returns
+	// false when the RFQ is no longer a draft
+	UpdateDraft(ctx context.Context, rfq *models.RFQ) (bool, error)
+	// Transition changes the status of an RFQ still in status `from`, it returns
+	// false when the status changed in the meantime
+	Transition(ctx context.Context, id, from string, transition *models.RFQTransition) (bool, error)
+	// InviteVendors adds vendors to the RFQ, vendors already invited are left as they are
+	InviteVendors(ctx context.Context, id string, vendorIDs []string) error
+}
+
+// QuotationRepository stores the quotations vendors submit for RFQs
+type QuotationRepository interface {
+	// Save stores the quotation of the vendor, replacing an earlier one, while the RFQ is open
+	// and its deadline has not passed. It returns false when the RFQ no longer accepts quotations
+	Save(ctx context.Context, quotation *models.Quotation) (bool, error)
+	// GetByVendor returns the quotation of a vendor for an RFQ with its lines, nil when there is none
+	GetByVendor(ctx context.Context, rfqID, vendorID string) (*models.Quotation, error)
+	// ListByRFQ returns every quotation of an RFQ with its lines
+	ListByRFQ(ctx context.Context, rfqID string) ([]*models.Quotation, error)
+}
+
+// BidEvaluationRepository stores the evaluation criteria of RFQs and the buyer scores of quotations
+type BidEvaluationRepository interface {
+	// GetCriteria returns nil when the RFQ has no criteria yet
+	GetCriteria(ctx context.Context, rfqID string) (*models.EvaluationCriteria, error)
+	// SaveCriteria stores or replaces the criteria of an RFQ
+	SaveCriteria(ctx context.Context, criteria *models.EvaluationCriteria) (*models.EvaluationCriteria, error)
+	// SaveScore stores or replaces the score of a quotation
+	SaveScore(ctx context.Context, score *models.QuotationScore) (*models.QuotationScore, error)
+	// ListScores returns the scores of the quotations of an RFQ
+	ListScores(ctx context.Context, rfqID string) ([]*models.QuotationScore, error)
+}
+
+// RFQAwardRepository stores the award decisions of RFQs
+type RFQAwardRepository interface {
+	// Create stores the award with its lines and moves the RFQ from open to awarded in one
+	// transaction, it returns false when the RFQ is no longer open
+	Create(ctx context.Context, award *models.RFQAward) (bool, error)
+	// GetByRFQ returns the award of an RFQ with its lines, nil when the RFQ is not awarded
+	GetByRFQ(ctx context.Context, rfqID string) (*models.RFQAward, error)
+}
+
+// PurchaseOrderRepository stores the purchase orders of the tenant with their lines and change orders
+type PurchaseOrderRepository interface {
+	// Create assigns the next PO number of the organization and stores the header and lines in
+	// one transaction, it reports a conflict when the award already has an order for the vendor
+	Create(ctx context.Context, order *models.PurchaseOrder) (*models.PurchaseOrder, error)
+	// GetByID returns nil when the order does not exist in the tenant, lines included
+	GetByID(ctx context.Context, id string) (*models.PurchaseOrder, error)
+	// List returns headers without lines, newest first, with the total count of the filter
+	List(ctx context.Context, filter models.PurchaseOrderFilter, limit, offset int) ([]*models.PurchaseOrder, int, error)
+	// UpdateDraft replaces the header fields and lines of a draft, it returns false when the
+	// order is no longer a draft
+	UpdateDraft(ctx context.Context, order *models.PurchaseOrder) (bool, error)
+	// Transition changes the status of an order still in status `from`, it returns
+	// false when the status changed in the meantime
+	Transition(ctx context.Context, id, from string, transition *models.PurchaseOrderTransition) (bool, error)
+	// Revise records the revision, replaces the header fields and lines and moves the order from
+	// `from` back to draft under the next revision number in one transaction. It returns false
+	// when the status changed in the meantime or a goods receipt or invoice refers to the order
+	Revise(ctx context.Context, order *models.PurchaseOrder, revision *models.PurchaseOrderRevision, from string) (bool, error)
+	// CountDocuments returns how many goods receipts and invoices refer to the order
+	CountDocuments(ctx context.Context, id string) (receipts int, invoices int, err error)
+	// ListRevisions returns the change orders of an order, oldest first
+	ListRevisions(ctx context.Context, id string) ([]*models.PurchaseOrderRevision, error)
+}
+
+// GoodsReceiptRepository stores the goods received against purchase orders with their inspection result
+type GoodsReceiptRepository interface {
+	// Create stores the receipt and its lines and applies the transition to the purchase order in one
+	// transaction. It returns false when the order changed since it was read as `order`, the receipt
+	// was then checked against stale quantities
+	Create(ctx context.Context, receipt *models.GoodsReceipt, order *models.PurchaseOrder, transition *models.PurchaseOrderTransition) (bool, error)
+	// GetByID returns nil when the receipt does not exist in the tenant, lines included
+	GetByID(ctx context.Context, id string) (*models.GoodsReceipt, error)
+	// List returns headers without lines, newest first, with the total count of the filter
+	List(ctx context.Context, filter models.GoodsReceiptFilter, limit, offset int) ([]*models.GoodsReceipt, int, error)
+	// ListByPurchaseOrder returns every receipt of an order with its lines, oldest first
+	ListByPurchaseOrder(ctx context.Context, purchaseOrderID string) ([]*models.GoodsReceipt, error)
+}
+
+// InvoiceRepository stores vendor invoices with the result of their three-way match
+type InvoiceRepository interface {
+	// Create stores the invoice and its lines as given and sets its ID, the invoice number is unique
+	// per vendor. The quantities invoiced on the order are compared with the previously invoiced
+	// quantities of the lines while the order is locked, it returns false when they changed since the match
+	Create(ctx context.Context, invoice *models.Invoice) (bool, error)
+	// GetByID returns nil when the invoice does not exist in the tenant, lines included
+	GetByID(ctx context.Context, id string) (*models.Invoice, error)
+	// List returns headers without lines with the total count of the filter
+	List(ctx context.Context, filter models.InvoiceFilter, limit, offset int) ([]*models.Invoice, int, error)
+	// InvoicedQuantities returns the quantity invoiced per purchase order line over the invoices
+	// of the order that are not disputed, leaving out the invoice excludeID
+	InvoicedQuantities(ctx context.Context, purchaseOrderID, excludeID string) (map[string]float64, error)
+	// UpdateMatch stores a new match result of the invoice and its lines, the status moves along.
+	// It returns false when the invoice is not in status `from` (anymore)
+	UpdateMatch(ctx context.Context, invoice *models.Invoice, from string) (bool, error)
+	// Review records the decision of finance, false when the invoice is not in status `from` (anymore)
+	Review(ctx context.Context, id, from string, review *models.InvoiceReview) (bool, error)
+}
+
+// PaymentBatchRepository stores payment batches with the invoices they pay
+type PaymentBatchRepository interface {
+	// Create numbers and stores the batch with its items and moves the invoices from approved to
+	// scheduled in one transaction. It returns false when an invoice is not approved anymore
+	Create(ctx context.Context, batch *models.PaymentBatch) (bool, error)
+	// GetByID returns nil when the batch does not exist in the tenant, items included
+	GetByID(ctx context.Context, id string) (*models.PaymentBatch, error)
+	// List returns headers without items, newest first, with the total count of the filter
+	List(ctx context.Context, filter models.PaymentBatchFilter, limit, offset int) ([]*models.PaymentBatch, int, error)
+	// Transition applies the transition to a batch in status `from` and moves its invoices to the
+	// invoice status of the transition, false when the batch is not in status `from` (anymore)
+	Transition(ctx context.Context, id, from string, transition *models.PaymentBatchTransition) (bool, error)
+}
@@ -1,14 +1,26 @@
 package initializer
 
 import (
+	"context"
 	"database/sql"
 	"e-procurement/internals/delivery/routers"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
 	"e-procurement/internals/repositories"
+	"e-procurement/internals/repositories/memory"
 	"e-procurement/internals/usecases"
 	"e-procurement/pkg/auth"
+	"e-procurement/pkg/bankfile"
+	"e-procurement/pkg/config"
 	"e-procurement/pkg/connections"
+	"e-procurement/pkg/encripted"
+	"e-procurement/pkg/mailer"
+	"e-procurement/pkg/password"
+	"e-procurement/pkg/rbac"
+	"fmt"
+	"log"
 	"net/http"
-	"time"
+	"os"
 
 	_ "github.com/lib/pq"
 )
@@ -18,50 +30,389 @@ type App struct {
 	DB     *sql.DB
 }
 
+// Close releases the database connection when the app runs against postgres
+func (a *App) Close() error {
+	if a.DB == nil {
+		return nil
+	}
+	return a.DB.Close()
+}
+
+// repositorySet groups the repositories injected into the usecases
+type repositorySet struct {
+	User            repository.UserRepository
+	Vendor          repository.VendorRepository
+	Product         repository.ProductRepository
+	Category        repository.CategoryRepository
+	RefreshToken    repository.RefreshTokenRepository
+	Revocation      repository.TokenRevocationRepository
+	UserToken       repository.UserTokenRepository
+	RateLimit       repository.RateLimitRepository
+	MFA             repository.MFARepository
+	LoginAttempt    repository.LoginAttemptRepository
+	PasswordHistory repository.PasswordHistoryRepository
+	APIKey          repository.APIKeyRepository
+	Organization    repository.OrganizationRepository
+	Department      repository.DepartmentRepository
+	CostCenter      repository.CostCenterRepository
+	Requisition     repository.RequisitionRepository
+	ApprovalRule    repository.ApprovalRuleRepository
+	Approval        repository.ApprovalRepository
+	Delegation      repository.ApprovalDelegationRepository
+	RFQ             repository.RFQRepository
+	Quotation       repository.QuotationRepository
+	BidEvaluation   repository.BidEvaluationRepository
+	Award           repository.RFQAwardRepository
+	PurchaseOrder   repository.PurchaseOrderRepository
+	GoodsReceipt    repository.GoodsReceiptRepository
+	Invoice         repository.InvoiceRepository
+	PaymentBatch    repository.PaymentBatchRepository
+}
+
+func newPostgresRepositories(db *sql.DB) *repositorySet {
+	return &repositorySet{
+		User:            repositories.NewUserRepository(db),
+		Vendor:          repositories.NewVendorRepository(db),
+		Product:         repositories.NewProductUseCase(db),
+		Category:        repositories.NewCategoryRepository(db),
+		RefreshToken:    repositories.NewRefreshTokenRepository(db),
+		Revocation:      repositories.NewTokenRevocationRepository(db),
+		UserToken:       repositories.NewUserTokenRepository(db),
+		RateLimit:       repositories.NewRateLimitRepository(db),
+		MFA:             repositories.NewMFARepository(db),
+		LoginAttempt:    repositories.NewLoginAttemptRepository(db),
+		PasswordHistory: repositories.NewPasswordHistoryRepository(db),
+		APIKey:          repositories.NewAPIKeyRepository(db),
+		Organization:    repositories.NewOrganizationRepository(db),
+		Department:      repositories.NewDepartmentRepository(db),
+		CostCenter:      repositories.NewCostCenterRepository(db),
+		Requisition:     repositories.NewRequisitionRepository(db),
+		ApprovalRule:    repositories.NewApprovalRuleRepository(db),
+		Approval:        repositories.NewApprovalRepository(db),
+		Delegation:      repositories.NewApprovalDelegationRepository(db),
+		RFQ:             repositories.NewRFQRepository(db),
+		Quotation:       repositories.NewQuotationRepository(db),
+		BidEvaluation:   repositories.NewBidEvaluationRepository(db),
+		Award:           repositories.NewRFQAwardRepository(db),
+		PurchaseOrder:   repositories.NewPurchaseOrderRepository(db),
+		GoodsReceipt:    repositories.NewGoodsReceiptRepository(db),
+		Invoice:         repositories.NewInvoiceRepository(db),
+		PaymentBatch:    repositories.NewPaymentBatchRepository(db),
+	}
+}
+
+func newMemoryRepositories() *repositorySet {
+	store := memory.NewStore()
+	return &repositorySet{
+		User:            memory.NewUserRepository(store),
+		Vendor:          memory.NewVendorRepository(store),
+		Product:         memory.NewProductRepository(store),
+		Category:        memory.NewCategoryRepository(store),
+		RefreshToken:    memory.NewRefreshTokenRepository(store),
+		Revocation:      memory.NewTokenRevocationRepository(store),
+		UserToken:       memory.NewUserTokenRepository(store),
+		RateLimit:       memory.NewRateLimitRepository(store),
+		MFA:             memory.NewMFARepository(store),
+		LoginAttempt:    memory.NewLoginAttemptRepository(store),
+		PasswordHistory: memory.NewPasswordHistoryRepository(store),
+		APIKey:          memory.NewAPIKeyRepository(store),
+		Organization:    memory.NewOrganizationRepository(store),
+		Department:      memory.NewDepartmentRepository(store),
+		CostCenter:      memory.NewCostCenterRepository(store),
+		Requisition:     memory.NewRequisitionRepository(store),
+		ApprovalRule:    memory.NewApprovalRuleRepository(store),
+		Approval:        memory.NewApprovalRepository(store),
+		Delegation:      memory.NewApprovalDelegationRepository(store),
+		RFQ:             memory.NewRFQRepository(store),
+		Quotation:       memory.NewQuotationRepository(store),
+		BidEvaluation:   memory.NewBidEvaluationRepository(store),
+		Award:           memory.NewRFQAwardRepository(store),
+		PurchaseOrder:   memory.NewPurchaseOrderRepository(store),
+		GoodsReceipt:    memory.NewGoodsReceiptRepository(store),
+		Invoice:         memory.NewInvoiceRepository(store),
+		PaymentBatch:    memory.NewPaymentBatchRepository(store),
+	}
+}
+
+
+// newPasswordManager builds the password policy and the hasher used for new passwords
+func newPasswordManager(cfg *config.Config, repos *repositorySet) *usecases.PasswordManager {
+	hasher := encripted.NewEncriptedWithOptions(encripted.Options{
+		Algorithm:  cfg.Password.HashAlgorithm,
+		BcryptCost: cfg.Password.BcryptCost,
+		Argon2: encripted.Argon2Params{
+			Memory:      uint32(cfg.Password.Argon2Memory),
+			Iterations:  uint32(cfg.Password.Argon2Iterations),
+			Parallelism: uint8(cfg.Password.Argon2Parallelism),
+		},
+	})
+	policy := password.Policy{
+		MinLength:     cfg.Password.MinLength,
+		MaxLength:     cfg.Password.MaxLength,
+		RequireUpper:  cfg.Password.RequireUpper,
+		RequireLower:  cfg.Password.RequireLower,
+		RequireDigit:  cfg.Password.RequireDigit,
+		RequireSymbol: cfg.Password.RequireSymbol,
+	}
+	return usecases.NewPasswordManager(repos.User, repos.PasswordHistory, hasher, policy, cfg.Password.HistorySize)
+}
+
+// ensureDefaultOrganization creates the organization self-registered users join,
+// the migration seeds it for postgres but a fresh in-memory store starts empty
+func ensureDefaultOrganization(ctx context.Context, cfg *config.Config, repos *repositorySet) error {
+	org, err := repos.Organization.GetByCode(ctx, cfg.Tenancy.DefaultOrganization)
+	if err != nil {
+		return fmt.Errorf("failed to get default organization: %w", err)
+	}
+	if org != nil {
+		return nil
+	}
+	_, err = repos.Organization.Create(ctx, &models.CreateOrganizationRequest{
+		Code: cfg.Tenancy.DefaultOrganization,
+		Name: cfg.Tenancy.DefaultOrganizationName,
+	}, "")
+	if err != nil {
+		return fmt.Errorf("failed to create default organization: %w", err)
+	}
+	log.Printf("Created default organization %q", cfg.Tenancy.DefaultOrganization)
+	return nil
+}
+
+// ensureBootstrapAdmin gives a fresh deployment (or the in-memory demo) its first admin: the configured user is
+// created verified and joins the default organization as admin. an existing user keeps their password and is only
+// promoted when their email is verified, so nobody can claim the address by registering it first
+func ensureBootstrapAdmin(ctx context.Context, cfg *config.Config, repos *repositorySet, passwords *usecases.PasswordManager) error {
+	if cfg.Tenancy.BootstrapAdminEmail == "" {
+		return nil
+	}
+	org, err := repos.Organization.GetByCode(ctx, cfg.Tenancy.DefaultOrganization)
+	if err != nil {
+		return fmt.Errorf("failed to get default organization: %w", err)
+	}
+	if org == nil {
+		return fmt.Errorf("default organization %q does not exist", cfg.Tenancy.DefaultOrganization)
+	}
+
+	user, err := repos.User.Authenticate(ctx, cfg.Tenancy.BootstrapAdminEmail)
+	if err != nil {
+		return fmt.Errorf("failed to get bootstrap admin: %w", err)
+	}
+	if user == nil {
+		hashed, err := passwords.Prepare(ctx, "", cfg.Tenancy.BootstrapAdminPassword, cfg.Tenancy.BootstrapAdminUserName, cfg.Tenancy.BootstrapAdminEmail)
+		if err != nil {
+			return fmt.Errorf("tenancy.bootstrap_admin_password: %w", err)
+		}
+		user, err = repos.User.Create(ctx, &models.CreateUserRequest{
+			UserName: cfg.Tenancy.BootstrapAdminUserName,
+			Email:    cfg.Tenancy.BootstrapAdminEmail,
+			Password: hashed,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create bootstrap admin: %w", err)
+		}
+		if err := passwords.Remember(ctx, user.ID, hashed); err != nil {
+			return err
+		}
+		if err := repos.User.MarkEmailVerified(ctx, user.ID); err != nil {
+			return fmt.Errorf("failed to verify bootstrap admin: %w", err)
+		}
+		if err := repos.Organization.AddMember(ctx, org.ID, user.ID, rbac.RoleAdmin); err != nil {
+			return fmt.Errorf("failed to add bootstrap admin to organization: %w", err)
+		}
+		log.Printf("Created bootstrap admin %s", user.Email)
+		return nil
+	}
+
+	if user.EmailVerifiedAt == nil {
+		log.Printf("Bootstrap admin %s has not verified their email, role left unchanged", user.Email)
+		return nil
+	}
+	membership, err := repos.Organization.GetMembership(ctx, org.ID, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get bootstrap admin membership: %w", err)
+	}
+	switch {
+	case membership == nil:
+		err = repos.Organization.AddMember(ctx, org.ID, user.ID, rbac.RoleAdmin)
+	case membership.Role != rbac.RoleAdmin:
+		err = repos.Organization.UpdateMemberRole(ctx, org.ID, user.ID, rbac.RoleAdmin)
+	default:
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to make bootstrap admin an admin: %w", err)
+	}
+	log.Printf("Made %s admin of organization %q", user.Email, cfg.Tenancy.DefaultOrganization)
+	return nil
+}
 
-func InitializeApp() (*App, error) {
+// ConnectDatabase opens the database connection described by the configuration
+func ConnectDatabase(cfg *config.Config) (*sql.DB, error) {
 	dbConfig := connections.DBConfig{
-		Driver:          	"postgres",
-		Host: 		 	 	"localhost",
-		Port: 		 	 	"5432",
-		User: 				"postgres",
-		Password: 			"1234",
-		DBName: 			"e_procurement",
-		Schema: 			"e_procurement",
-		MaxOpenConns:    	10,
-		MaxIdleConns:    	5,
-		ConnMaxLifetime: 	5 * time.Minute,
-	}
-
-	db, err := connections.ConnectDB(dbConfig)
+		Driver:          	cfg.Database.Driver,
+		Host: 		 	 	cfg.Database.Host,
+		Port: 		 	 	cfg.Database.Port,
+		User: 				cfg.Database.User,
+		Password: 			cfg.Database.Password,
+		DBName: 			cfg.Database.Name,
+		Schema: 			cfg.Database.Schema,
+		SSLMode: 			cfg.Database.SSLMode,
+		MaxOpenConns:    	cfg.Database.MaxOpenConns,
+		MaxIdleConns:    	cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: 	cfg.Database.ConnMaxLifetime,
+	}
+	return connections.ConnectDB(dbConfig)
+}
+
+// newJWT builds the token signer from the configured algorithm, asymmetric keys are read from PEM files
+func newJWT(cfg *config.Config) (*auth.JWT, error) {
+	if cfg.JWT.Algorithm == config.JWTAlgorithmHS256 {
+		return auth.NewJWT(cfg.JWT.Secret, cfg.JWT.AccessTokenTTL), nil
+	}
+
+	signingKey, err := auth.LoadKeyFile(cfg.JWT.SigningKeyID, cfg.JWT.SigningKeyFile)
 	if err != nil {
 		return nil, err
 	}
+	if signingKey.Algorithm() != cfg.JWT.Algorithm {
+		return nil, fmt.Errorf("jwt signing key %s is a %s key, expected %s", signingKey.ID, signingKey.Algorithm(), cfg.JWT.Algorithm)
+	}
+
+	var verificationKeys []*auth.Key
+	for _, keyCfg := range cfg.JWT.VerificationKeys {
+		key, err := auth.LoadKeyFile(keyCfg.ID, keyCfg.File)
+		if err != nil {
+			return nil, err
+		}
+		verificationKeys = append(verificationKeys, key)
+	}
+	return auth.NewJWTWithKeys(signingKey, verificationKeys, cfg.JWT.AccessTokenTTL)
+}
+
+// newMailer builds the mailer selected by mail.driver
+func newMailer(cfg *config.Config) (mailer.Mailer, error) {
+	switch cfg.Mail.Driver {
+	case config.MailDriverFile:
+		return mailer.NewFileMailer(cfg.Mail.Dir, cfg.Mail.From)
+	case config.MailDriverSMTP:
+		return mailer.NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From), nil
+	default:
+		return mailer.NewLogMailer(os.Stdout), nil
+	}
+}
+
+func InitializeApp(cfg *config.Config) (*App, error) {
+	var db *sql.DB
+	var repos *repositorySet
+	if cfg.App.Storage == config.StorageMemory {
+		log.Println("Using in-memory storage, data is lost on restart")
+		repos = newMemoryRepositories()
+	} else {
+		var err error
+		db, err = ConnectDatabase(cfg)
+		if err != nil {
+			return nil, err
+		}
+		repos = newPostgresRepositories(db)
+	}
+	if err := ensureDefaultOrganization(context.Background(), cfg, repos); err != nil {
+		return nil, err
+	}
 	
 	// initialize jwt
-	jwtSecret := "secreate"
-	JWT:=auth.NewJWT(jwtSecret)
-	// if os.Getenv("SECRET_KE")
-
-	// intial repositories
-	categoryRepo := repositories.NewCategoryRepository(db)
-	userRepo := repositories.NewUserRepository(db)
-	productRepo := repositories.NewProductUseCase(db)
-	vendorRepo := repositories.NewVendorRepository(db)
+	JWT, err := newJWT(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	mail, err := newMailer(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	mfaCipher, err := encripted.NewCipher(cfg.Auth.MFAEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultPaymentTerms, err := usecases.ParsePaymentTerms(cfg.Payment.DefaultTerms)
+	if err != nil {
+		return nil, fmt.Errorf("payment.default_terms: %w", err)
+	}
+	bankExporter, err := bankfile.NewFileExporter(cfg.Payment.ExportDir)
+	if err != nil {
+		return nil, err
+	}
+
 	// intial usecases
-	authUseCase := usecases.NewAuthUseCase(userRepo,JWT)
-	productUsecase := usecases.NewProductUsecase(productRepo,vendorRepo)
-	categoryUsecase := usecases.NewCategoryUsecase(categoryRepo)
-	vendorUseCase := usecases.NewVendorUseCase(vendorRepo,userRepo)
-	userUseCase := usecases.NewUserUseCase(userRepo)
+	passwordManager := newPasswordManager(cfg, repos)
+	if err := ensureBootstrapAdmin(context.Background(), cfg, repos, passwordManager); err != nil {
+		return nil, err
+	}
+	revocationUseCase := usecases.NewTokenRevocationUseCase(repos.Revocation,repos.RefreshToken,cfg.JWT.RevocationCacheTTL)
+	verificationLimiter := usecases.NewRateLimiter(repos.RateLimit,usecases.RateLimitScopeEmailVerification,cfg.Auth.EmailVerificationMaxRequests,cfg.Auth.EmailVerificationWindow)
+	verificationUseCase := usecases.NewEmailVerificationUseCase(repos.User,repos.UserToken,verificationLimiter,mail,cfg.Auth.EmailVerificationURL,cfg.Auth.EmailVerificationTTL)
+	loginThrottle := usecases.NewLoginThrottle(repos.LoginAttempt, usecases.LoginThrottlePolicy{
+		Window:          cfg.Auth.LoginFailureWindow,
+		BackoffAfter:    cfg.Auth.LoginBackoffAfter,
+		BackoffBase:     cfg.Auth.LoginBackoffBase,
+		BackoffMax:      cfg.Auth.LoginBackoffMax,
+		LockoutAfter:    cfg.Auth.LoginLockoutAfter,
+		LockoutDuration: cfg.Auth.LoginLockoutDuration,
+		MaxIPFailures:   cfg.Auth.LoginMaxIPFailures,
+	})
+	authUseCase := usecases.NewAuthUseCase(repos.User,repos.Organization,repos.RefreshToken,repos.MFA,loginThrottle,passwordManager,revocationUseCase,verificationUseCase,JWT,cfg.JWT.RefreshTokenTTL,cfg.Auth.MFAChallengeTTL,cfg.Tenancy.DefaultOrganization)
+	mfaLimiter := usecases.NewRateLimiter(repos.RateLimit,usecases.RateLimitScopeMFA,cfg.Auth.MFAMaxAttempts,cfg.Auth.MFAAttemptWindow)
+	mfaUseCase := usecases.NewMFAUseCase(repos.MFA,repos.User,authUseCase,revocationUseCase,mfaLimiter,mfaCipher,cfg.Auth.MFAIssuer)
+	apiKeyUseCase := usecases.NewAPIKeyUseCase(repos.APIKey,repos.Organization,cfg.Auth.APIKeyMaxTTL)
+	productUsecase := usecases.NewProductUsecase(repos.Product,repos.Vendor)
+	categoryUsecase := usecases.NewCategoryUsecase(repos.Category)
+	userUseCase := usecases.NewUserUseCase(repos.User,repos.Organization,revocationUseCase,verificationUseCase,passwordManager)
+	organizationUseCase := usecases.NewOrganizationUseCase(repos.Organization,repos.User,revocationUseCase)
+	departmentUseCase := usecases.NewDepartmentUseCase(repos.Department,repos.CostCenter)
+	costCenterUseCase := usecases.NewCostCenterUseCase(repos.CostCenter)
+	approvalUseCase := usecases.NewApprovalUseCase(repos.ApprovalRule,repos.Approval,repos.Delegation,repos.Organization,departmentUseCase)
+	requisitionUseCase := usecases.NewRequisitionUseCase(repos.Requisition,repos.Product,departmentUseCase,approvalUseCase)
+	// documents going through approval are told about the outcome
+	approvalUseCase.Register(models.ApprovalDocumentRequisition,requisitionUseCase)
+	rfqUseCase := usecases.NewRFQUseCase(repos.RFQ,repos.Quotation,repos.Requisition,repos.Product,repos.Vendor)
+	purchaseOrderUseCase := usecases.NewPurchaseOrderUseCase(repos.PurchaseOrder,repos.Product,repos.Vendor,repos.Organization,approvalUseCase)
+	approvalUseCase.Register(models.ApprovalDocumentPurchaseOrder,purchaseOrderUseCase)
+	vendorUseCase := usecases.NewVendorUseCase(repos.Vendor,repos.User,approvalUseCase)
+	approvalUseCase.Register(models.ApprovalDocumentVendorOnboarding,vendorUseCase)
+	goodsReceiptUseCase := usecases.NewGoodsReceiptUseCase(repos.GoodsReceipt,purchaseOrderUseCase,cfg.Procurement.OverReceiptTolerance)
+	invoiceUseCase := usecases.NewInvoiceUseCase(repos.Invoice,repos.Vendor,purchaseOrderUseCase,goodsReceiptUseCase,cfg.Procurement.InvoicePriceTolerance,cfg.Procurement.InvoiceQuantityTolerance,defaultPaymentTerms)
+	paymentUseCase := usecases.NewPaymentUseCase(repos.PaymentBatch,invoiceUseCase,bankExporter)
+	bidEvaluationUseCase := usecases.NewBidEvaluationUseCase(rfqUseCase,purchaseOrderUseCase,repos.BidEvaluation,repos.Award)
+	passwordResetLimiter := usecases.NewRateLimiter(repos.RateLimit,usecases.RateLimitScopePasswordReset,cfg.Auth.PasswordResetMaxRequests,cfg.Auth.PasswordResetWindow)
+	passwordResetUseCase := usecases.NewPasswordResetUseCase(repos.User,repos.UserToken,revocationUseCase,passwordResetLimiter,passwordManager,mail,cfg.Auth.PasswordResetURL,cfg.Auth.PasswordResetTTL)
 	// inital routers
 	r := routers.Router{
 		User:   *userUseCase,
 		Auth: *authUseCase,
+		PasswordReset: *passwordResetUseCase,
+		EmailVerification: *verificationUseCase,
+		MFA: *mfaUseCase,
+		LoginThrottle: *loginThrottle,
+		APIKey: *apiKeyUseCase,
+		Organization: *organizationUseCase,
+		Department: *departmentUseCase,
+		CostCenter: *costCenterUseCase,
+		Requisition: *requisitionUseCase,
+		Approval: *approvalUseCase,
+		RFQ: *rfqUseCase,
+		BidEvaluation: *bidEvaluationUseCase,
+		PurchaseOrder: *purchaseOrderUseCase,
+		GoodsReceipt: *goodsReceiptUseCase,
+		Invoice: *invoiceUseCase,
+		Payment: *paymentUseCase,
 		Vendor: *vendorUseCase,
 		Product: *productUsecase,
 		Category: *categoryUsecase,
 		JWT: JWT,
+		Revocations: revocationUseCase,
+		MFARequiredRoles: cfg.Auth.MFARequiredRoles,
+		TrustProxy: cfg.Server.TrustProxy,
 	}
 	routers := routers.NewRouter(&r)
 	app := &App{
@@ -70,4 +421,4 @@ func InitializeApp() (*App, error) {
 	}
 	return app, nil
 
-}
\ No newline at end of file
+}
@@ -0,0 +1,12 @@
+CREATE TABLE categories (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    category_name   VARCHAR(255) NOT NULL,
+    descriptions    TEXT         NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT categories_category_name_key UNIQUE (category_name)
+);
+
+CREATE TRIGGER categories_set_updated_at
+    BEFORE UPDATE ON categories
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
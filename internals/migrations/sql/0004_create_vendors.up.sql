@@ -0,0 +1,14 @@
+CREATE TABLE vendors (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    vendor_name  VARCHAR(255) NOT NULL,
+    description  TEXT         NOT NULL DEFAULT '',
+    user_id      UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    -- one vendor profile per user (see VendorUseCase.CreateVendorUsecase)
+    CONSTRAINT vendors_user_id_key UNIQUE (user_id)
+);
+
+CREATE TRIGGER vendors_set_updated_at
+    BEFORE UPDATE ON vendors
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,17 @@
+CREATE TABLE products (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_name         VARCHAR(255)   NOT NULL,
+    product_price        NUMERIC(18, 2) NOT NULL CHECK (product_price > 0),
+    product_description  TEXT           NOT NULL DEFAULT '',
+    product_category     UUID           NOT NULL REFERENCES categories (id) ON DELETE RESTRICT,
+    vendor_id            UUID           NOT NULL REFERENCES vendors (id) ON DELETE CASCADE,
+    created_at           TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX products_product_category_idx ON products (product_category);
+CREATE INDEX products_vendor_id_idx ON products (vendor_id);
+
+CREATE TRIGGER products_set_updated_at
+    BEFORE UPDATE ON products
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
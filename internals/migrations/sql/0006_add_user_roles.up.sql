@@ -0,0 +1,6 @@
+-- roles are defined in pkg/rbac
+UPDATE users SET role = 'vendor' WHERE role NOT IN ('admin', 'procurement_officer', 'vendor', 'approver', 'auditor');
+
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'vendor';
+ALTER TABLE users ADD CONSTRAINT users_role_check
+    CHECK (role IN ('admin', 'procurement_officer', 'vendor', 'approver', 'auditor'));
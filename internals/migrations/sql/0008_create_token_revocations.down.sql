@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_session_revocations;
+DROP TABLE IF EXISTS revoked_tokens;
@@ -0,0 +1,16 @@
+-- access tokens revoked before they expire, looked up by the jti claim
+CREATE TABLE revoked_tokens (
+    jti         UUID PRIMARY KEY,
+    user_id     UUID        NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    revoked_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX revoked_tokens_expires_at_idx ON revoked_tokens (expires_at);
+
+-- every access token of the user issued at or before revoked_before is rejected.
+-- no foreign key on purpose: the row must outlive a deleted user.
+CREATE TABLE user_session_revocations (
+    user_id         UUID PRIMARY KEY,
+    revoked_before  TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS rate_limit_events;
+DROP TABLE IF EXISTS user_tokens;
@@ -0,0 +1,24 @@
+-- single-use tokens mailed to users, only the SHA-256 hash of the token is stored
+CREATE TABLE user_tokens (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose     VARCHAR(32) NOT NULL,
+    token_hash  CHAR(64)    NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    used_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT user_tokens_token_hash_key UNIQUE (token_hash),
+    CONSTRAINT user_tokens_purpose_check CHECK (purpose IN ('password_reset'))
+);
+
+CREATE INDEX user_tokens_user_id_purpose_idx ON user_tokens (user_id, purpose);
+
+-- requests counted by the rate limiters, subject is e.g. a normalized email
+CREATE TABLE rate_limit_events (
+    id          BIGSERIAL PRIMARY KEY,
+    scope       VARCHAR(64)  NOT NULL,
+    subject     VARCHAR(255) NOT NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX rate_limit_events_lookup_idx ON rate_limit_events (scope, subject, created_at);
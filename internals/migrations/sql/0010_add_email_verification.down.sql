@@ -0,0 +1,6 @@
+DELETE FROM user_tokens WHERE purpose = 'email_verification';
+ALTER TABLE user_tokens DROP CONSTRAINT user_tokens_purpose_check;
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_purpose_check
+    CHECK (purpose IN ('password_reset'));
+
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
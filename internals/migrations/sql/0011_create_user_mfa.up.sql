@@ -0,0 +1,24 @@
+-- TOTP enrollment, the secret is encrypted by the application (AES-GCM)
+CREATE TABLE user_mfa (
+    user_id         UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret          TEXT        NOT NULL,
+    -- NULL while the enrollment is waiting for its first code
+    enabled_at      TIMESTAMPTZ,
+    -- last accepted time step, older or equal steps are replays
+    last_used_step  BIGINT      NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER user_mfa_set_updated_at
+    BEFORE UPDATE ON user_mfa
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE mfa_recovery_codes (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash   CHAR(64)    NOT NULL,
+    used_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT mfa_recovery_codes_user_code_key UNIQUE (user_id, code_hash)
+);
@@ -0,0 +1,16 @@
+-- every password login attempt, read by the login throttle and kept for audit.
+-- email is stored lowercased and also for unknown accounts, so lockouts do not reveal
+-- whether an account exists
+CREATE TABLE login_attempts (
+    id          BIGSERIAL PRIMARY KEY,
+    email       VARCHAR(255) NOT NULL,
+    ip_address  VARCHAR(64)  NOT NULL,
+    user_id     UUID REFERENCES users (id) ON DELETE SET NULL,
+    outcome     VARCHAR(32)  NOT NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT login_attempts_outcome_check
+        CHECK (outcome IN ('success', 'invalid_credentials', 'locked', 'throttled'))
+);
+
+CREATE INDEX login_attempts_email_idx ON login_attempts (email, created_at);
+CREATE INDEX login_attempts_ip_address_idx ON login_attempts (ip_address, created_at);
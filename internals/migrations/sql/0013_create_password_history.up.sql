@@ -0,0 +1,13 @@
+-- hashes of the passwords a user had, checked so recent passwords are not reused
+CREATE TABLE password_history (
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    password_hash  VARCHAR(255) NOT NULL,
+    created_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX password_history_user_id_idx ON password_history (user_id, created_at DESC);
+
+-- the current password is the first history entry of existing users
+INSERT INTO password_history (user_id, password_hash, created_at)
+SELECT id, password, updated_at FROM users;
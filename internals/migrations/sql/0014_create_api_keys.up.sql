@@ -0,0 +1,17 @@
+CREATE TABLE api_keys (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- the key acts as this user, limited to its scopes
+    user_id       UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name          VARCHAR(100) NOT NULL,
+    -- public part of the key used for the lookup, the secret is only stored hashed
+    prefix        VARCHAR(16)  NOT NULL,
+    key_hash      CHAR(64)     NOT NULL,
+    scopes        TEXT[]       NOT NULL,
+    expires_at    TIMESTAMPTZ  NOT NULL,
+    last_used_at  TIMESTAMPTZ,
+    revoked_at    TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT api_keys_prefix_key UNIQUE (prefix)
+);
+
+CREATE INDEX api_keys_user_id_idx ON api_keys (user_id);
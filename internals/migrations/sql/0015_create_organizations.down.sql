@@ -0,0 +1,24 @@
+ALTER TABLE products DROP CONSTRAINT products_organization_vendor_fkey;
+ALTER TABLE products DROP CONSTRAINT products_organization_category_fkey;
+ALTER TABLE products ADD CONSTRAINT products_vendor_id_fkey
+    FOREIGN KEY (vendor_id) REFERENCES vendors (id) ON DELETE CASCADE;
+ALTER TABLE products ADD CONSTRAINT products_product_category_fkey
+    FOREIGN KEY (product_category) REFERENCES categories (id) ON DELETE RESTRICT;
+ALTER TABLE vendors DROP CONSTRAINT vendors_organization_member_fkey;
+
+-- the per-organization copies of a name or vendor profile cannot be kept apart any more
+ALTER TABLE vendors DROP CONSTRAINT vendors_organization_id_id_key;
+ALTER TABLE vendors DROP CONSTRAINT vendors_organization_user_key;
+ALTER TABLE vendors ADD CONSTRAINT vendors_user_id_key UNIQUE (user_id);
+ALTER TABLE categories DROP CONSTRAINT categories_organization_id_id_key;
+ALTER TABLE categories DROP CONSTRAINT categories_organization_name_key;
+ALTER TABLE categories ADD CONSTRAINT categories_category_name_key UNIQUE (category_name);
+
+ALTER TABLE refresh_tokens DROP COLUMN organization_id;
+ALTER TABLE api_keys DROP COLUMN organization_id;
+ALTER TABLE products DROP COLUMN organization_id;
+ALTER TABLE vendors DROP COLUMN organization_id;
+ALTER TABLE categories DROP COLUMN organization_id;
+
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
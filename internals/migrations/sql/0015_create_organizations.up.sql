@@ -0,0 +1,73 @@
+-- buyer tenants, every vendor, category, product and api key belongs to exactly one
+CREATE TABLE organizations (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code        VARCHAR(50)  NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT organizations_code_key UNIQUE (code)
+);
+
+CREATE TRIGGER organizations_set_updated_at
+    BEFORE UPDATE ON organizations
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- users are global, their role is given per organization
+CREATE TABLE organization_members (
+    organization_id  UUID        NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id          UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role             VARCHAR(50) NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id),
+    CONSTRAINT organization_members_role_check
+        CHECK (role IN ('admin', 'procurement_officer', 'vendor', 'approver', 'auditor'))
+);
+
+CREATE INDEX organization_members_user_id_idx ON organization_members (user_id);
+
+-- existing data moves into a default organization, existing users keep their role there
+INSERT INTO organizations (code, name) VALUES ('default', 'Default Organization');
+
+INSERT INTO organization_members (organization_id, user_id, role, created_at)
+SELECT o.id, u.id, u.role, u.created_at
+FROM users u CROSS JOIN organizations o
+WHERE o.code = 'default';
+
+ALTER TABLE categories ADD COLUMN organization_id UUID REFERENCES organizations (id) ON DELETE CASCADE;
+ALTER TABLE vendors ADD COLUMN organization_id UUID REFERENCES organizations (id) ON DELETE CASCADE;
+ALTER TABLE products ADD COLUMN organization_id UUID REFERENCES organizations (id) ON DELETE CASCADE;
+ALTER TABLE api_keys ADD COLUMN organization_id UUID REFERENCES organizations (id) ON DELETE CASCADE;
+ALTER TABLE refresh_tokens ADD COLUMN organization_id UUID REFERENCES organizations (id) ON DELETE CASCADE;
+
+UPDATE categories SET organization_id = (SELECT id FROM organizations WHERE code = 'default');
+UPDATE vendors SET organization_id = (SELECT id FROM organizations WHERE code = 'default');
+UPDATE products SET organization_id = (SELECT id FROM organizations WHERE code = 'default');
+UPDATE api_keys SET organization_id = (SELECT id FROM organizations WHERE code = 'default');
+UPDATE refresh_tokens SET organization_id = (SELECT id FROM organizations WHERE code = 'default');
+
+ALTER TABLE categories ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE vendors ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE products ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE api_keys ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN organization_id SET NOT NULL;
+
+-- names and vendor profiles are unique per organization
+ALTER TABLE categories DROP CONSTRAINT categories_category_name_key;
+ALTER TABLE categories ADD CONSTRAINT categories_organization_name_key UNIQUE (organization_id, category_name);
+ALTER TABLE categories ADD CONSTRAINT categories_organization_id_id_key UNIQUE (organization_id, id);
+ALTER TABLE vendors DROP CONSTRAINT vendors_user_id_key;
+ALTER TABLE vendors ADD CONSTRAINT vendors_organization_user_key UNIQUE (organization_id, user_id);
+ALTER TABLE vendors ADD CONSTRAINT vendors_organization_id_id_key UNIQUE (organization_id, id);
+
+-- references never cross organizations, the vendor user has to be a member
+ALTER TABLE vendors ADD CONSTRAINT vendors_organization_member_fkey
+    FOREIGN KEY (organization_id, user_id) REFERENCES organization_members (organization_id, user_id);
+ALTER TABLE products DROP CONSTRAINT products_product_category_fkey;
+ALTER TABLE products DROP CONSTRAINT products_vendor_id_fkey;
+ALTER TABLE products ADD CONSTRAINT products_organization_category_fkey
+    FOREIGN KEY (organization_id, product_category) REFERENCES categories (organization_id, id) ON DELETE RESTRICT;
+ALTER TABLE products ADD CONSTRAINT products_organization_vendor_fkey
+    FOREIGN KEY (organization_id, vendor_id) REFERENCES vendors (organization_id, id) ON DELETE CASCADE;
+
+CREATE INDEX categories_organization_id_idx ON categories (organization_id);
+CREATE INDEX products_organization_id_idx ON products (organization_id);
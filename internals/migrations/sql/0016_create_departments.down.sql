@@ -0,0 +1,5 @@
+ALTER TABLE organization_members DROP COLUMN cost_center_id;
+ALTER TABLE organization_members DROP COLUMN department_id;
+
+DROP TABLE IF EXISTS departments;
+DROP TABLE IF EXISTS cost_centers;
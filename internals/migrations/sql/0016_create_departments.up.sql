@@ -0,0 +1,53 @@
+-- cost centers purchases are charged to, codes follow the finance chart of accounts
+CREATE TABLE cost_centers (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID         NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    code             VARCHAR(50)  NOT NULL,
+    name             VARCHAR(255) NOT NULL,
+    description      TEXT         NOT NULL DEFAULT '',
+    active           BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT cost_centers_organization_code_key UNIQUE (organization_id, code),
+    CONSTRAINT cost_centers_organization_id_id_key UNIQUE (organization_id, id)
+);
+
+CREATE TRIGGER cost_centers_set_updated_at
+    BEFORE UPDATE ON cost_centers
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- departments form a tree per organization through parent_id
+CREATE TABLE departments (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id         UUID         NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    code                    VARCHAR(50)  NOT NULL,
+    name                    VARCHAR(255) NOT NULL,
+    parent_id               UUID,
+    manager_id              UUID,
+    default_cost_center_id  UUID,
+    created_at              TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT departments_organization_code_key UNIQUE (organization_id, code),
+    CONSTRAINT departments_organization_id_id_key UNIQUE (organization_id, id),
+    CONSTRAINT departments_parent_check CHECK (parent_id <> id),
+    CONSTRAINT departments_parent_fkey
+        FOREIGN KEY (organization_id, parent_id) REFERENCES departments (organization_id, id),
+    CONSTRAINT departments_manager_fkey
+        FOREIGN KEY (organization_id, manager_id) REFERENCES organization_members (organization_id, user_id),
+    CONSTRAINT departments_default_cost_center_fkey
+        FOREIGN KEY (organization_id, default_cost_center_id) REFERENCES cost_centers (organization_id, id)
+);
+
+CREATE INDEX departments_parent_id_idx ON departments (parent_id);
+
+CREATE TRIGGER departments_set_updated_at
+    BEFORE UPDATE ON departments
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- a member belongs to at most one department, cost_center_id overrides the department default
+ALTER TABLE organization_members ADD COLUMN department_id UUID;
+ALTER TABLE organization_members ADD COLUMN cost_center_id UUID;
+ALTER TABLE organization_members ADD CONSTRAINT organization_members_department_fkey
+    FOREIGN KEY (organization_id, department_id) REFERENCES departments (organization_id, id);
+ALTER TABLE organization_members ADD CONSTRAINT organization_members_cost_center_fkey
+    FOREIGN KEY (organization_id, cost_center_id) REFERENCES cost_centers (organization_id, id);
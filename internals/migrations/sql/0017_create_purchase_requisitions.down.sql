@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS purchase_requisition_lines;
+DROP TABLE IF EXISTS purchase_requisitions;
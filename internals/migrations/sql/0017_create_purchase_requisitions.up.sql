@@ -0,0 +1,49 @@
+-- an employee's request for goods, approved requisitions are sourced through RFQs and purchase orders
+CREATE TABLE purchase_requisitions (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID           NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    requester_id     UUID           NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+    department_id    UUID,
+    cost_center_id   UUID,
+    title            VARCHAR(255)   NOT NULL,
+    justification    TEXT           NOT NULL,
+    status           VARCHAR(20)    NOT NULL DEFAULT 'draft',
+    total_amount     NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    submitted_at     TIMESTAMPTZ,
+    decided_at       TIMESTAMPTZ,
+    decided_by       UUID REFERENCES users (id) ON DELETE SET NULL,
+    decision_comment TEXT           NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    CONSTRAINT purchase_requisitions_organization_id_id_key UNIQUE (organization_id, id),
+    CONSTRAINT purchase_requisitions_status_check
+        CHECK (status IN ('draft', 'submitted', 'approved', 'rejected', 'cancelled')),
+    CONSTRAINT purchase_requisitions_department_fkey
+        FOREIGN KEY (organization_id, department_id) REFERENCES departments (organization_id, id),
+    CONSTRAINT purchase_requisitions_cost_center_fkey
+        FOREIGN KEY (organization_id, cost_center_id) REFERENCES cost_centers (organization_id, id)
+);
+
+CREATE INDEX purchase_requisitions_organization_status_idx ON purchase_requisitions (organization_id, status);
+CREATE INDEX purchase_requisitions_requester_id_idx ON purchase_requisitions (requester_id);
+
+CREATE TRIGGER purchase_requisitions_set_updated_at
+    BEFORE UPDATE ON purchase_requisitions
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- product_id is optional for free-text items, the description is always kept so
+-- a line stays readable after its product is removed from the catalog
+CREATE TABLE purchase_requisition_lines (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    requisition_id  UUID           NOT NULL REFERENCES purchase_requisitions (id) ON DELETE CASCADE,
+    line_no         INTEGER        NOT NULL,
+    product_id      UUID REFERENCES products (id) ON DELETE SET NULL,
+    description     TEXT           NOT NULL,
+    quantity        NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    unit            VARCHAR(20)    NOT NULL DEFAULT 'pcs',
+    unit_price      NUMERIC(18, 2) NOT NULL CHECK (unit_price >= 0),
+    needed_by       DATE           NOT NULL,
+    CONSTRAINT purchase_requisition_lines_line_no_key UNIQUE (requisition_id, line_no)
+);
+
+CREATE INDEX purchase_requisition_lines_product_id_idx ON purchase_requisition_lines (product_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS approval_delegations;
+DROP TABLE IF EXISTS approval_request_steps;
+DROP TABLE IF EXISTS approval_requests;
+DROP TABLE IF EXISTS approval_rule_steps;
+DROP TABLE IF EXISTS approval_rules;
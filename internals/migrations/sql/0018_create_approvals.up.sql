@@ -0,0 +1,115 @@
+-- approval rules per document type, every active rule whose conditions match a
+-- document contributes its steps; empty conditions match every document
+CREATE TABLE approval_rules (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID           NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    document_type    VARCHAR(30)    NOT NULL,
+    name             VARCHAR(255)   NOT NULL,
+    priority         INTEGER        NOT NULL DEFAULT 0,
+    amount_above     NUMERIC(18, 2),
+    amount_up_to     NUMERIC(18, 2),
+    category_id      UUID REFERENCES categories (id) ON DELETE RESTRICT,
+    department_id    UUID,
+    active           BOOLEAN        NOT NULL DEFAULT TRUE,
+    created_at       TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    CONSTRAINT approval_rules_document_type_check
+        CHECK (document_type IN ('requisition', 'purchase_order', 'vendor_onboarding')),
+    CONSTRAINT approval_rules_amount_check CHECK (amount_up_to IS NULL OR amount_above IS NULL OR amount_up_to > amount_above),
+    CONSTRAINT approval_rules_department_fkey
+        FOREIGN KEY (organization_id, department_id) REFERENCES departments (organization_id, id)
+);
+
+CREATE INDEX approval_rules_organization_document_type_idx ON approval_rules (organization_id, document_type);
+
+CREATE TRIGGER approval_rules_set_updated_at
+    BEFORE UPDATE ON approval_rules
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- steps sharing a level run in parallel, levels run one after another
+CREATE TABLE approval_rule_steps (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    rule_id           UUID         NOT NULL REFERENCES approval_rules (id) ON DELETE CASCADE,
+    level             INTEGER      NOT NULL CHECK (level >= 1),
+    name              VARCHAR(255) NOT NULL,
+    approver_type     VARCHAR(30)  NOT NULL,
+    approver_role     VARCHAR(30)  NOT NULL DEFAULT '',
+    approver_user_id  UUID REFERENCES users (id) ON DELETE RESTRICT,
+    CONSTRAINT approval_rule_steps_approver_type_check
+        CHECK (approver_type IN ('user', 'role', 'department_manager'))
+);
+
+CREATE INDEX approval_rule_steps_rule_id_idx ON approval_rule_steps (rule_id);
+
+-- one approval round of a document, a document returned for revision gets a new
+-- request when it is submitted again; the steps are copied from the matching rules
+-- so later rule changes do not affect running approvals
+CREATE TABLE approval_requests (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID           NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    document_type    VARCHAR(30)    NOT NULL,
+    document_id      UUID           NOT NULL,
+    title            VARCHAR(255)   NOT NULL DEFAULT '',
+    amount           NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    department_id    UUID,
+    requested_by     UUID           NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+    status           VARCHAR(20)    NOT NULL DEFAULT 'pending',
+    current_stage    INTEGER        NOT NULL DEFAULT 1,
+    created_at       TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    completed_at     TIMESTAMPTZ,
+    CONSTRAINT approval_requests_status_check
+        CHECK (status IN ('pending', 'approved', 'rejected', 'returned', 'cancelled'))
+);
+
+-- a document has at most one running approval
+CREATE UNIQUE INDEX approval_requests_pending_document_key
+    ON approval_requests (organization_id, document_type, document_id) WHERE status = 'pending';
+CREATE INDEX approval_requests_document_idx ON approval_requests (organization_id, document_type, document_id);
+
+CREATE TRIGGER approval_requests_set_updated_at
+    BEFORE UPDATE ON approval_requests
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE approval_request_steps (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    request_id        UUID         NOT NULL REFERENCES approval_requests (id) ON DELETE CASCADE,
+    stage             INTEGER      NOT NULL CHECK (stage >= 1),
+    rule_id           UUID REFERENCES approval_rules (id) ON DELETE SET NULL,
+    name              VARCHAR(255) NOT NULL,
+    approver_role     VARCHAR(30)  NOT NULL DEFAULT '',
+    approver_user_id  UUID REFERENCES users (id) ON DELETE RESTRICT,
+    status            VARCHAR(20)  NOT NULL,
+    acted_by          UUID REFERENCES users (id) ON DELETE SET NULL,
+    on_behalf_of      UUID REFERENCES users (id) ON DELETE SET NULL,
+    comment           TEXT         NOT NULL DEFAULT '',
+    acted_at          TIMESTAMPTZ,
+    CONSTRAINT approval_request_steps_status_check
+        CHECK (status IN ('waiting', 'pending', 'approved', 'rejected', 'returned', 'skipped')),
+    CONSTRAINT approval_request_steps_approver_check
+        CHECK (approver_user_id IS NOT NULL OR approver_role <> '')
+);
+
+CREATE INDEX approval_request_steps_request_id_idx ON approval_request_steps (request_id);
+CREATE INDEX approval_request_steps_pending_user_idx ON approval_request_steps (approver_user_id) WHERE status = 'pending';
+CREATE INDEX approval_request_steps_pending_role_idx ON approval_request_steps (approver_role) WHERE status = 'pending';
+
+-- a member hands their approvals to another member for a period, e.g. while on leave
+CREATE TABLE approval_delegations (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID         NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    delegator_id     UUID         NOT NULL,
+    delegate_id      UUID         NOT NULL,
+    starts_at        TIMESTAMPTZ  NOT NULL,
+    ends_at          TIMESTAMPTZ  NOT NULL,
+    reason           VARCHAR(255) NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT approval_delegations_period_check CHECK (ends_at > starts_at),
+    CONSTRAINT approval_delegations_self_check CHECK (delegator_id <> delegate_id),
+    CONSTRAINT approval_delegations_delegator_fkey
+        FOREIGN KEY (organization_id, delegator_id) REFERENCES organization_members (organization_id, user_id) ON DELETE CASCADE,
+    CONSTRAINT approval_delegations_delegate_fkey
+        FOREIGN KEY (organization_id, delegate_id) REFERENCES organization_members (organization_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX approval_delegations_delegate_idx ON approval_delegations (organization_id, delegate_id, ends_at);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS quotation_lines;
+DROP TABLE IF EXISTS quotations;
+DROP TABLE IF EXISTS rfq_vendors;
+DROP TABLE IF EXISTS rfq_lines;
+DROP TABLE IF EXISTS rfqs;
@@ -0,0 +1,85 @@
+-- requests for quotation sent to invited vendors, their quotations stay sealed
+-- until response_deadline has passed
+CREATE TABLE rfqs (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id    UUID         NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    requisition_id     UUID,
+    title              VARCHAR(255) NOT NULL,
+    description        TEXT         NOT NULL DEFAULT '',
+    status             VARCHAR(20)  NOT NULL DEFAULT 'draft',
+    response_deadline  TIMESTAMPTZ  NOT NULL,
+    created_by         UUID         NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+    published_at       TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT rfqs_organization_id_id_key UNIQUE (organization_id, id),
+    CONSTRAINT rfqs_status_check CHECK (status IN ('draft', 'open', 'cancelled')),
+    CONSTRAINT rfqs_requisition_fkey
+        FOREIGN KEY (organization_id, requisition_id) REFERENCES purchase_requisitions (organization_id, id)
+);
+
+CREATE INDEX rfqs_organization_status_idx ON rfqs (organization_id, status);
+
+CREATE TRIGGER rfqs_set_updated_at
+    BEFORE UPDATE ON rfqs
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE rfq_lines (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    rfq_id       UUID           NOT NULL REFERENCES rfqs (id) ON DELETE CASCADE,
+    line_no      INTEGER        NOT NULL,
+    product_id   UUID REFERENCES products (id) ON DELETE SET NULL,
+    description  TEXT           NOT NULL,
+    quantity     NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    unit         VARCHAR(20)    NOT NULL DEFAULT 'pcs',
+    needed_by    DATE,
+    CONSTRAINT rfq_lines_line_no_key UNIQUE (rfq_id, line_no)
+);
+
+CREATE INDEX rfq_lines_product_id_idx ON rfq_lines (product_id);
+
+-- invited vendors, a vendor with invitations cannot be deleted
+CREATE TABLE rfq_vendors (
+    organization_id  UUID        NOT NULL,
+    rfq_id           UUID        NOT NULL,
+    vendor_id        UUID        NOT NULL,
+    invited_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (rfq_id, vendor_id),
+    CONSTRAINT rfq_vendors_rfq_fkey
+        FOREIGN KEY (organization_id, rfq_id) REFERENCES rfqs (organization_id, id) ON DELETE CASCADE,
+    CONSTRAINT rfq_vendors_vendor_fkey
+        FOREIGN KEY (organization_id, vendor_id) REFERENCES vendors (organization_id, id) ON DELETE RESTRICT
+);
+
+CREATE INDEX rfq_vendors_vendor_id_idx ON rfq_vendors (vendor_id);
+
+-- one quotation per invited vendor, resubmitting before the deadline replaces it
+CREATE TABLE quotations (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID           NOT NULL,
+    rfq_id           UUID           NOT NULL,
+    vendor_id        UUID           NOT NULL,
+    notes            TEXT           NOT NULL DEFAULT '',
+    total_amount     NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    submitted_at     TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    created_at       TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    CONSTRAINT quotations_rfq_vendor_key UNIQUE (rfq_id, vendor_id),
+    CONSTRAINT quotations_organization_id_id_key UNIQUE (organization_id, id),
+    CONSTRAINT quotations_invitation_fkey
+        FOREIGN KEY (rfq_id, vendor_id) REFERENCES rfq_vendors (rfq_id, vendor_id) ON DELETE CASCADE,
+    CONSTRAINT quotations_rfq_fkey
+        FOREIGN KEY (organization_id, rfq_id) REFERENCES rfqs (organization_id, id) ON DELETE CASCADE
+);
+
+CREATE TABLE quotation_lines (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    quotation_id    UUID           NOT NULL REFERENCES quotations (id) ON DELETE CASCADE,
+    rfq_line_id     UUID           NOT NULL REFERENCES rfq_lines (id) ON DELETE CASCADE,
+    unit_price      NUMERIC(18, 2) NOT NULL CHECK (unit_price >= 0),
+    amount          NUMERIC(18, 2) NOT NULL CHECK (amount >= 0),
+    lead_time_days  INTEGER        NOT NULL DEFAULT 0 CHECK (lead_time_days >= 0),
+    valid_until     DATE           NOT NULL,
+    CONSTRAINT quotation_lines_rfq_line_key UNIQUE (quotation_id, rfq_line_id)
+);
+
+CREATE INDEX quotation_lines_rfq_line_id_idx ON quotation_lines (rfq_line_id);
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS purchase_order_lines;
+DROP TABLE IF EXISTS purchase_orders;
+DROP TABLE IF EXISTS rfq_award_lines;
+DROP TABLE IF EXISTS rfq_awards;
+DROP TABLE IF EXISTS quotation_scores;
+DROP TABLE IF EXISTS rfq_evaluation_criteria;
+
+UPDATE rfqs SET status = 'cancelled' WHERE status = 'awarded';
+ALTER TABLE rfqs DROP CONSTRAINT rfqs_status_check;
+ALTER TABLE rfqs ADD CONSTRAINT rfqs_status_check CHECK (status IN ('draft', 'open', 'cancelled'));
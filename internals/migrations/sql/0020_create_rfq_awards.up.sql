@@ -0,0 +1,106 @@
+ALTER TABLE rfqs DROP CONSTRAINT rfqs_status_check;
+ALTER TABLE rfqs ADD CONSTRAINT rfqs_status_check CHECK (status IN ('draft', 'open', 'awarded', 'cancelled'));
+
+-- weights of the evaluation criteria in percent, an RFQ without a row is evaluated on price only
+CREATE TABLE rfq_evaluation_criteria (
+    rfq_id            UUID PRIMARY KEY REFERENCES rfqs (id) ON DELETE CASCADE,
+    price_weight      NUMERIC(5, 2) NOT NULL DEFAULT 100,
+    lead_time_weight  NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    rating_weight     NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    technical_weight  NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    updated_at        TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    CONSTRAINT rfq_evaluation_criteria_weights_check
+        CHECK (price_weight + lead_time_weight + rating_weight + technical_weight = 100)
+);
+
+CREATE TRIGGER rfq_evaluation_criteria_set_updated_at
+    BEFORE UPDATE ON rfq_evaluation_criteria
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- buyer scores of a quotation for the criteria prices cannot express
+CREATE TABLE quotation_scores (
+    quotation_id     UUID PRIMARY KEY REFERENCES quotations (id) ON DELETE CASCADE,
+    vendor_rating    NUMERIC(3, 2) NOT NULL DEFAULT 0 CHECK (vendor_rating BETWEEN 0 AND 5),
+    technical_score  NUMERIC(5, 2) NOT NULL DEFAULT 0 CHECK (technical_score BETWEEN 0 AND 100),
+    notes            TEXT          NOT NULL DEFAULT '',
+    scored_by        UUID REFERENCES users (id) ON DELETE SET NULL,
+    scored_at        TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+-- award decision of an RFQ, an RFQ is awarded at most once
+CREATE TABLE rfq_awards (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID           NOT NULL,
+    rfq_id           UUID           NOT NULL,
+    mode             VARCHAR(10)    NOT NULL,
+    justification    TEXT           NOT NULL,
+    total_amount     NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    awarded_by       UUID           NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+    awarded_at       TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    CONSTRAINT rfq_awards_rfq_id_key UNIQUE (rfq_id),
+    CONSTRAINT rfq_awards_organization_id_id_key UNIQUE (organization_id, id),
+    CONSTRAINT rfq_awards_mode_check CHECK (mode IN ('rfq', 'line')),
+    CONSTRAINT rfq_awards_rfq_fkey
+        FOREIGN KEY (organization_id, rfq_id) REFERENCES rfqs (organization_id, id) ON DELETE CASCADE
+);
+
+-- price and score of each awarded line as they were at award time
+CREATE TABLE rfq_award_lines (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    award_id      UUID           NOT NULL REFERENCES rfq_awards (id) ON DELETE CASCADE,
+    rfq_line_id   UUID           NOT NULL REFERENCES rfq_lines (id) ON DELETE CASCADE,
+    quotation_id  UUID           NOT NULL REFERENCES quotations (id) ON DELETE RESTRICT,
+    vendor_id     UUID           NOT NULL REFERENCES vendors (id) ON DELETE RESTRICT,
+    quantity      NUMERIC(18, 3) NOT NULL,
+    unit_price    NUMERIC(18, 2) NOT NULL,
+    amount        NUMERIC(18, 2) NOT NULL,
+    score         NUMERIC(5, 2)  NOT NULL DEFAULT 0,
+    CONSTRAINT rfq_award_lines_rfq_line_key UNIQUE (award_id, rfq_line_id)
+);
+
+CREATE INDEX rfq_award_lines_vendor_id_idx ON rfq_award_lines (vendor_id);
+
+-- purchase orders, a vendor with orders cannot be deleted
+CREATE TABLE purchase_orders (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id  UUID           NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    vendor_id        UUID           NOT NULL,
+    rfq_id           UUID,
+    award_id         UUID,
+    status           VARCHAR(20)    NOT NULL DEFAULT 'draft',
+    total_amount     NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    created_by       UUID           NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+    created_at       TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    CONSTRAINT purchase_orders_organization_id_id_key UNIQUE (organization_id, id),
+    CONSTRAINT purchase_orders_award_vendor_key UNIQUE (award_id, vendor_id),
+    CONSTRAINT purchase_orders_status_check CHECK (status IN ('draft')),
+    CONSTRAINT purchase_orders_vendor_fkey
+        FOREIGN KEY (organization_id, vendor_id) REFERENCES vendors (organization_id, id) ON DELETE RESTRICT,
+    CONSTRAINT purchase_orders_rfq_fkey
+        FOREIGN KEY (organization_id, rfq_id) REFERENCES rfqs (organization_id, id),
+    CONSTRAINT purchase_orders_award_fkey
+        FOREIGN KEY (organization_id, award_id) REFERENCES rfq_awards (organization_id, id)
+);
+
+CREATE INDEX purchase_orders_organization_status_idx ON purchase_orders (organization_id, status);
+CREATE INDEX purchase_orders_vendor_id_idx ON purchase_orders (vendor_id);
+
+CREATE TRIGGER purchase_orders_set_updated_at
+    BEFORE UPDATE ON purchase_orders
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE purchase_order_lines (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    purchase_order_id  UUID           NOT NULL REFERENCES purchase_orders (id) ON DELETE CASCADE,
+    line_no            INTEGER        NOT NULL,
+    product_id         UUID REFERENCES products (id) ON DELETE SET NULL,
+    description        TEXT           NOT NULL,
+    quantity           NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    unit               VARCHAR(20)    NOT NULL DEFAULT 'pcs',
+    unit_price         NUMERIC(18, 2) NOT NULL CHECK (unit_price >= 0),
+    amount             NUMERIC(18, 2) NOT NULL CHECK (amount >= 0),
+    CONSTRAINT purchase_order_lines_line_no_key UNIQUE (purchase_order_id, line_no)
+);
+
+CREATE INDEX purchase_order_lines_product_id_idx ON purchase_order_lines (product_id);
@@ -0,0 +1,31 @@
+DROP TABLE IF EXISTS purchase_order_revisions;
+
+ALTER TABLE purchase_order_lines
+    DROP COLUMN IF EXISTS tax_rate,
+    DROP COLUMN IF EXISTS tax_amount;
+
+UPDATE purchase_orders SET status = 'draft';
+ALTER TABLE purchase_orders DROP CONSTRAINT purchase_orders_status_check;
+ALTER TABLE purchase_orders ADD CONSTRAINT purchase_orders_status_check CHECK (status IN ('draft'));
+ALTER TABLE purchase_orders DROP CONSTRAINT IF EXISTS purchase_orders_number_key;
+ALTER TABLE purchase_orders
+    DROP COLUMN IF EXISTS po_number,
+    DROP COLUMN IF EXISTS revision,
+    DROP COLUMN IF EXISTS ship_to,
+    DROP COLUMN IF EXISTS payment_terms,
+    DROP COLUMN IF EXISTS delivery_date,
+    DROP COLUMN IF EXISTS notes,
+    DROP COLUMN IF EXISTS subtotal,
+    DROP COLUMN IF EXISTS tax_amount,
+    DROP COLUMN IF EXISTS submitted_at,
+    DROP COLUMN IF EXISTS approved_at,
+    DROP COLUMN IF EXISTS approved_by,
+    DROP COLUMN IF EXISTS sent_at,
+    DROP COLUMN IF EXISTS acknowledged_at,
+    DROP COLUMN IF EXISTS vendor_note,
+    DROP COLUMN IF EXISTS rejected_at,
+    DROP COLUMN IF EXISTS rejection_reason,
+    DROP COLUMN IF EXISTS closed_at,
+    DROP COLUMN IF EXISTS cancelled_at;
+
+DROP TABLE IF EXISTS purchase_order_sequences;
@@ -0,0 +1,61 @@
+-- last purchase order number handed out per organization
+CREATE TABLE purchase_order_sequences (
+    organization_id  UUID PRIMARY KEY REFERENCES organizations (id) ON DELETE CASCADE,
+    last_number      INTEGER NOT NULL DEFAULT 0
+);
+
+ALTER TABLE purchase_orders
+    ADD COLUMN po_number        VARCHAR(30),
+    ADD COLUMN revision         INTEGER        NOT NULL DEFAULT 0,
+    ADD COLUMN ship_to          TEXT           NOT NULL DEFAULT '',
+    ADD COLUMN payment_terms    VARCHAR(50)    NOT NULL DEFAULT '',
+    ADD COLUMN delivery_date    DATE,
+    ADD COLUMN notes            TEXT           NOT NULL DEFAULT '',
+    ADD COLUMN subtotal         NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN tax_amount       NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN submitted_at     TIMESTAMPTZ,
+    ADD COLUMN approved_at      TIMESTAMPTZ,
+    ADD COLUMN approved_by      UUID REFERENCES users (id) ON DELETE SET NULL,
+    ADD COLUMN sent_at          TIMESTAMPTZ,
+    ADD COLUMN acknowledged_at  TIMESTAMPTZ,
+    ADD COLUMN vendor_note      TEXT           NOT NULL DEFAULT '',
+    ADD COLUMN rejected_at      TIMESTAMPTZ,
+    ADD COLUMN rejection_reason TEXT           NOT NULL DEFAULT '',
+    ADD COLUMN closed_at        TIMESTAMPTZ,
+    ADD COLUMN cancelled_at     TIMESTAMPTZ;
+
+-- number the orders drafted from awards before numbering existed
+UPDATE purchase_orders po
+SET po_number = 'PO-' || LPAD(numbered.n::text, 6, '0'),
+    subtotal  = po.total_amount
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY organization_id ORDER BY created_at, id) AS n
+    FROM purchase_orders
+) numbered
+WHERE numbered.id = po.id;
+
+INSERT INTO purchase_order_sequences (organization_id, last_number)
+SELECT organization_id, COUNT(*) FROM purchase_orders GROUP BY organization_id;
+
+ALTER TABLE purchase_orders ALTER COLUMN po_number SET NOT NULL;
+ALTER TABLE purchase_orders ADD CONSTRAINT purchase_orders_number_key UNIQUE (organization_id, po_number);
+ALTER TABLE purchase_orders DROP CONSTRAINT purchase_orders_status_check;
+ALTER TABLE purchase_orders ADD CONSTRAINT purchase_orders_status_check CHECK (status IN (
+    'draft', 'submitted', 'approved', 'sent', 'acknowledged', 'rejected', 'partially_received', 'closed', 'cancelled'
+));
+
+ALTER TABLE purchase_order_lines
+    ADD COLUMN tax_rate   NUMERIC(5, 2)  NOT NULL DEFAULT 0 CHECK (tax_rate BETWEEN 0 AND 100),
+    ADD COLUMN tax_amount NUMERIC(18, 2) NOT NULL DEFAULT 0 CHECK (tax_amount >= 0);
+
+-- change orders, each row keeps the order as it was before the change
+CREATE TABLE purchase_order_revisions (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    purchase_order_id  UUID        NOT NULL REFERENCES purchase_orders (id) ON DELETE CASCADE,
+    revision           INTEGER     NOT NULL,
+    reason             TEXT        NOT NULL,
+    snapshot           JSONB       NOT NULL,
+    changed_by         UUID REFERENCES users (id) ON DELETE SET NULL,
+    changed_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT purchase_order_revisions_revision_key UNIQUE (purchase_order_id, revision)
+);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS goods_receipt_lines;
+DROP TABLE IF EXISTS goods_receipts;
+
+UPDATE organization_members SET role = 'vendor' WHERE role = 'warehouse';
+ALTER TABLE organization_members DROP CONSTRAINT organization_members_role_check;
+ALTER TABLE organization_members ADD CONSTRAINT organization_members_role_check
+    CHECK (role IN ('admin', 'procurement_officer', 'vendor', 'approver', 'auditor'));
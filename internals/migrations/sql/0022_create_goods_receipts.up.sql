@@ -0,0 +1,34 @@
+-- warehouse staff record the goods received against purchase orders
+ALTER TABLE organization_members DROP CONSTRAINT organization_members_role_check;
+ALTER TABLE organization_members ADD CONSTRAINT organization_members_role_check
+    CHECK (role IN ('admin', 'procurement_officer', 'vendor', 'approver', 'auditor', 'warehouse'));
+
+-- goods receipts, a purchase order with receipts cannot be deleted
+CREATE TABLE goods_receipts (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id    UUID         NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    purchase_order_id  UUID         NOT NULL,
+    delivery_note      VARCHAR(100) NOT NULL DEFAULT '',
+    received_at        DATE         NOT NULL,
+    notes              TEXT         NOT NULL DEFAULT '',
+    received_by        UUID         NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+    created_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT goods_receipts_purchase_order_fkey
+        FOREIGN KEY (organization_id, purchase_order_id) REFERENCES purchase_orders (organization_id, id) ON DELETE RESTRICT
+);
+
+CREATE INDEX goods_receipts_purchase_order_id_idx ON goods_receipts (purchase_order_id);
+
+CREATE TABLE goods_receipt_lines (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    goods_receipt_id        UUID           NOT NULL REFERENCES goods_receipts (id) ON DELETE CASCADE,
+    purchase_order_line_id  UUID           NOT NULL REFERENCES purchase_order_lines (id) ON DELETE RESTRICT,
+    received_quantity       NUMERIC(18, 3) NOT NULL CHECK (received_quantity > 0),
+    accepted_quantity       NUMERIC(18, 3) NOT NULL CHECK (accepted_quantity >= 0),
+    rejected_quantity       NUMERIC(18, 3) NOT NULL CHECK (rejected_quantity >= 0),
+    inspection_notes        TEXT           NOT NULL DEFAULT '',
+    CONSTRAINT goods_receipt_lines_quantity_check CHECK (accepted_quantity + rejected_quantity = received_quantity),
+    CONSTRAINT goods_receipt_lines_line_key UNIQUE (goods_receipt_id, purchase_order_line_id)
+);
+
+CREATE INDEX goods_receipt_lines_purchase_order_line_id_idx ON goods_receipt_lines (purchase_order_line_id);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS invoice_lines;
+DROP TABLE IF EXISTS invoices;
+
+UPDATE organization_members SET role = 'vendor' WHERE role = 'finance';
+ALTER TABLE organization_members DROP CONSTRAINT organization_members_role_check;
+ALTER TABLE organization_members ADD CONSTRAINT organization_members_role_check
+    CHECK (role IN ('admin', 'procurement_officer', 'vendor', 'approver', 'auditor', 'warehouse'));
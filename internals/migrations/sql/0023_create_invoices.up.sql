@@ -0,0 +1,65 @@
+-- finance staff match vendor invoices before payment
+ALTER TABLE organization_members DROP CONSTRAINT organization_members_role_check;
+ALTER TABLE organization_members ADD CONSTRAINT organization_members_role_check
+    CHECK (role IN ('admin', 'procurement_officer', 'vendor', 'approver', 'auditor', 'warehouse', 'finance'));
+
+-- vendor invoices against one purchase order, a vendor or an order with invoices cannot be deleted.
+-- status is the review state, match_status the outcome of the last three-way match
+CREATE TABLE invoices (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id    UUID           NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    vendor_id          UUID           NOT NULL,
+    purchase_order_id  UUID           NOT NULL,
+    invoice_number     VARCHAR(50)    NOT NULL,
+    invoice_date       DATE           NOT NULL,
+    status             VARCHAR(20)    NOT NULL,
+    match_status       VARCHAR(20)    NOT NULL,
+    subtotal           NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    tax_amount         NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    total_amount       NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    notes              TEXT           NOT NULL DEFAULT '',
+    submitted_by       UUID           NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+    matched_at         TIMESTAMPTZ    NOT NULL,
+    reviewed_by        UUID REFERENCES users (id) ON DELETE SET NULL,
+    reviewed_at        TIMESTAMPTZ,
+    review_note        TEXT           NOT NULL DEFAULT '',
+    created_at         TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    CONSTRAINT invoices_vendor_number_key UNIQUE (vendor_id, invoice_number),
+    CONSTRAINT invoices_status_check CHECK (status IN ('matched', 'exception', 'on_hold', 'approved', 'disputed')),
+    CONSTRAINT invoices_match_status_check CHECK (match_status IN ('matched', 'exception')),
+    CONSTRAINT invoices_vendor_fkey
+        FOREIGN KEY (organization_id, vendor_id) REFERENCES vendors (organization_id, id) ON DELETE RESTRICT,
+    CONSTRAINT invoices_purchase_order_fkey
+        FOREIGN KEY (organization_id, purchase_order_id) REFERENCES purchase_orders (organization_id, id) ON DELETE RESTRICT
+);
+
+CREATE INDEX invoices_organization_status_idx ON invoices (organization_id, status);
+CREATE INDEX invoices_purchase_order_id_idx ON invoices (purchase_order_id);
+
+CREATE TRIGGER invoices_set_updated_at
+    BEFORE UPDATE ON invoices
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- invoice lines with the PO price and quantities they were matched against
+CREATE TABLE invoice_lines (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    invoice_id              UUID           NOT NULL REFERENCES invoices (id) ON DELETE CASCADE,
+    line_no                 INTEGER        NOT NULL,
+    purchase_order_line_id  UUID           NOT NULL REFERENCES purchase_order_lines (id) ON DELETE RESTRICT,
+    quantity                NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    unit_price              NUMERIC(18, 2) NOT NULL CHECK (unit_price >= 0),
+    amount                  NUMERIC(18, 2) NOT NULL,
+    tax_rate                NUMERIC(5, 2)  NOT NULL DEFAULT 0,
+    tax_amount              NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    order_unit_price        NUMERIC(18, 2) NOT NULL,
+    order_tax_rate          NUMERIC(5, 2)  NOT NULL,
+    accepted_quantity       NUMERIC(18, 3) NOT NULL,
+    previously_invoiced     NUMERIC(18, 3) NOT NULL DEFAULT 0,
+    match_status            VARCHAR(20)    NOT NULL,
+    match_exceptions        TEXT[]         NOT NULL DEFAULT '{}',
+    CONSTRAINT invoice_lines_match_status_check CHECK (match_status IN ('matched', 'exception')),
+    CONSTRAINT invoice_lines_line_key UNIQUE (invoice_id, purchase_order_line_id)
+);
+
+CREATE INDEX invoice_lines_purchase_order_line_id_idx ON invoice_lines (purchase_order_line_id);
@@ -0,0 +1,25 @@
+-- scheduled and paid invoices would lose their payment history and go back to approved, where they could be
+-- paid twice, so the rollback refuses to run while any exist
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM invoices WHERE status IN ('scheduled', 'paid')) THEN
+        RAISE EXCEPTION 'cannot roll back payments: invoices are scheduled or paid';
+    END IF;
+END $$;
+
+DROP TABLE IF EXISTS payment_batch_items;
+DROP TABLE IF EXISTS payment_batches;
+DROP TABLE IF EXISTS payment_batch_sequences;
+
+DROP INDEX IF EXISTS invoices_organization_due_date_idx;
+ALTER TABLE invoices DROP CONSTRAINT invoices_status_check;
+ALTER TABLE invoices ADD CONSTRAINT invoices_status_check
+    CHECK (status IN ('matched', 'exception', 'on_hold', 'approved', 'disputed'));
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS payment_terms,
+    DROP COLUMN IF EXISTS due_date,
+    DROP COLUMN IF EXISTS discount_date,
+    DROP COLUMN IF EXISTS discount_amount;
+
+ALTER TABLE vendors DROP COLUMN IF EXISTS payment_terms;
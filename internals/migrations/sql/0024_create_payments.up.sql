@@ -0,0 +1,72 @@
+-- payment terms agreed with the vendor, used when an order or invoice has none of its own
+ALTER TABLE vendors ADD COLUMN payment_terms VARCHAR(50) NOT NULL DEFAULT '';
+
+-- approved invoices get their payment schedule, scheduled invoices are in a payment batch
+ALTER TABLE invoices
+    ADD COLUMN payment_terms    VARCHAR(50)    NOT NULL DEFAULT '',
+    ADD COLUMN due_date         DATE,
+    ADD COLUMN discount_date    DATE,
+    ADD COLUMN discount_amount  NUMERIC(18, 2) NOT NULL DEFAULT 0 CHECK (discount_amount >= 0);
+
+-- invoices approved before payment terms existed are due 30 days after their invoice date
+UPDATE invoices SET payment_terms = 'Net 30', due_date = invoice_date + 30 WHERE status = 'approved';
+
+ALTER TABLE invoices DROP CONSTRAINT invoices_status_check;
+ALTER TABLE invoices ADD CONSTRAINT invoices_status_check CHECK (status IN (
+    'matched', 'exception', 'on_hold', 'approved', 'disputed', 'scheduled', 'paid'
+));
+
+CREATE INDEX invoices_organization_due_date_idx ON invoices (organization_id, due_date) WHERE status = 'approved';
+
+-- last payment batch number handed out per organization
+CREATE TABLE payment_batch_sequences (
+    organization_id  UUID PRIMARY KEY REFERENCES organizations (id) ON DELETE CASCADE,
+    last_number      INTEGER NOT NULL DEFAULT 0
+);
+
+-- approved invoices paid together on one payment date, exported to the bank as one file
+CREATE TABLE payment_batches (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id    UUID           NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    batch_number       VARCHAR(30)    NOT NULL,
+    status             VARCHAR(20)    NOT NULL,
+    payment_date       DATE           NOT NULL,
+    invoice_count      INTEGER        NOT NULL DEFAULT 0,
+    total_invoiced     NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    total_discount     NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    total_amount       NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    notes              TEXT           NOT NULL DEFAULT '',
+    created_by         UUID           NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+    export_file        TEXT           NOT NULL DEFAULT '',
+    exported_at        TIMESTAMPTZ,
+    exported_by        UUID REFERENCES users (id) ON DELETE SET NULL,
+    payment_reference  VARCHAR(100)   NOT NULL DEFAULT '',
+    paid_at            TIMESTAMPTZ,
+    paid_by            UUID REFERENCES users (id) ON DELETE SET NULL,
+    cancelled_at       TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    CONSTRAINT payment_batches_number_key UNIQUE (organization_id, batch_number),
+    CONSTRAINT payment_batches_status_check CHECK (status IN ('draft', 'exported', 'paid', 'cancelled'))
+);
+
+CREATE INDEX payment_batches_organization_status_idx ON payment_batches (organization_id, status);
+
+CREATE TRIGGER payment_batches_set_updated_at
+    BEFORE UPDATE ON payment_batches
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- invoices of a batch with the early payment discount taken, an invoice with payments cannot be deleted
+CREATE TABLE payment_batch_items (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_batch_id  UUID           NOT NULL REFERENCES payment_batches (id) ON DELETE CASCADE,
+    invoice_id        UUID           NOT NULL REFERENCES invoices (id) ON DELETE RESTRICT,
+    vendor_id         UUID           NOT NULL REFERENCES vendors (id) ON DELETE RESTRICT,
+    invoice_amount    NUMERIC(18, 2) NOT NULL,
+    discount_amount   NUMERIC(18, 2) NOT NULL DEFAULT 0 CHECK (discount_amount >= 0),
+    payment_amount    NUMERIC(18, 2) NOT NULL,
+    CONSTRAINT payment_batch_items_invoice_key UNIQUE (payment_batch_id, invoice_id)
+);
+
+CREATE INDEX payment_batch_items_invoice_id_idx ON payment_batch_items (invoice_id);
+CREATE INDEX payment_batch_items_vendor_id_idx ON payment_batch_items (vendor_id);
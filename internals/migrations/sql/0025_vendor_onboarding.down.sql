@@ -0,0 +1 @@
+ALTER TABLE vendors DROP COLUMN IF EXISTS status;
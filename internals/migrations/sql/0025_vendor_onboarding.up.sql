@@ -0,0 +1,5 @@
+-- vendors registered from now on wait for the vendor onboarding approval, vendors that
+-- already trade with the organization are approved
+ALTER TABLE vendors ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'approved'
+    CONSTRAINT vendors_status_check CHECK (status IN ('pending', 'approved', 'rejected', 'returned'));
+ALTER TABLE vendors ALTER COLUMN status SET DEFAULT 'pending';
@@ -0,0 +1,4 @@
+ALTER TABLE vendors
+    DROP COLUMN IF EXISTS bank_code,
+    DROP COLUMN IF EXISTS bank_account_number,
+    DROP COLUMN IF EXISTS bank_account_holder;
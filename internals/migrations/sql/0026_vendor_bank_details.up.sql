@@ -0,0 +1,5 @@
+-- the account payment batches transfer to, vendors without one cannot be paid by bank file
+ALTER TABLE vendors
+    ADD COLUMN bank_code           VARCHAR(20)  NOT NULL DEFAULT '',
+    ADD COLUMN bank_account_number VARCHAR(34)  NOT NULL DEFAULT '',
+    ADD COLUMN bank_account_holder VARCHAR(100) NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS vendor_bank_account_changes;
@@ -0,0 +1,15 @@
+-- every change of a vendor bank account, with the account before and after it and who changed it
+CREATE TABLE vendor_bank_account_changes (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    vendor_id               UUID         NOT NULL REFERENCES vendors (id) ON DELETE CASCADE,
+    old_bank_code           VARCHAR(20)  NOT NULL,
+    old_bank_account_number VARCHAR(34)  NOT NULL,
+    old_bank_account_holder VARCHAR(100) NOT NULL,
+    bank_code               VARCHAR(20)  NOT NULL,
+    bank_account_number     VARCHAR(34)  NOT NULL,
+    bank_account_holder     VARCHAR(100) NOT NULL,
+    changed_by              UUID REFERENCES users (id) ON DELETE SET NULL,
+    changed_at              TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX vendor_bank_account_changes_vendor_id_idx ON vendor_bank_account_changes (vendor_id);
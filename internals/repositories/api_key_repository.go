@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+type APIKeyRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.APIKeyRepository = (*APIKeyRepository)(nil)
+
+var apiKeyColumns = []string{"id", "user_id", "organization_id", "name", "prefix", "key_hash", "scopes", "expires_at", "last_used_at", "revoked_at", "created_at"}
+
+// NewAPIKeyRepository creates a new instance of APIKeyRepository with the provided database connection.
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create New API Key
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		key: API key to store, only the hash of the secret is persisted.
+// returns:
+// 		APIKey: the stored API key.
+// 		errors: if any occurred during the operation.
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) (*models.APIKey, error) {
+	query := r.SQLBuilder.
+		Insert("api_keys").
+		Columns("user_id", "organization_id", "name", "prefix", "key_hash", "scopes", "expires_at").
+		Values(key.UserID, key.OrganizationID, key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes), key.ExpiresAt).
+		Suffix("RETURNING " + strings.Join(apiKeyColumns, ", "))
+
+	created, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "api_key")
+	}
+	return created, nil
+}
+
+// Method to Get API Key By Prefix
+// It returns nil when no key has the prefix. The lookup is not scoped by tenant,
+// the key itself decides the organization of the request.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		prefix: public part of the key sent by the client.
+// returns:
+// 		APIKey: the stored API key.
+// 		errors: if any occurred during the operation.
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	return r.getOne(ctx, sq.Eq{"prefix": prefix})
+}
+
+// Method to Get API Key By ID
+// It returns nil when the key does not exist in the organization of the request.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the API key.
+// returns:
+// 		APIKey: the stored API key.
+// 		errors: if any occurred during the operation.
+func (r *APIKeyRepository) GetByID(ctx context.Context, id string) (*models.APIKey, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.getOne(ctx, sq.Eq{"id": id, "organization_id": orgID})
+}
+
+// Method to List the API Keys of a user in the organization of the request, newest first
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		userID: owner of the keys.
+// returns:
+// 		[]APIKey: the keys of the user, revoked and expired ones included.
+// 		errors: if any occurred during the operation.
+func (r *APIKeyRepository) ListByUser(ctx context.Context, userID string) ([]*models.APIKey, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(apiKeyColumns...).
+		From("api_keys").
+		Where(sq.Eq{"user_id": userID, "organization_id": orgID}).
+		OrderBy("created_at DESC")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "api_key")
+	}
+	defer rows.Close()
+
+	var keys []*models.APIKey
+	for rows.Next() {
+		key, err := r.scan(rows)
+		if err != nil {
+			return nil, translateError(err, "api_key")
+		}
+		keys = append(keys, key)
+	}
+	return keys, translateError(rows.Err(), "api_key")
+}
+
+// Method to Revoke an API Key
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the API key.
+// returns:
+// 		bool: true when this call revoked the key.
+// 		errors: if any occurred during the operation.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id string) (bool, error) {
+	query := r.SQLBuilder.
+		Update("api_keys").
+		Set("revoked_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "revoked_at": nil})
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "api_key")
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// Method to record the last use of an API Key
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the API key.
+// 		usedAt: time of the request authenticated by the key.
+// returns:
+// 		errors: if any occurred during the operation.
+func (r *APIKeyRepository) Touch(ctx context.Context, id string, usedAt time.Time) error {
+	query := r.SQLBuilder.
+		Update("api_keys").
+		Set("last_used_at", usedAt).
+		Where(sq.Eq{"id": id})
+
+	_, err := query.RunWith(r.db).ExecContext(ctx)
+	return translateError(err, "api_key")
+}
+
+func (r *APIKeyRepository) getOne(ctx context.Context, where sq.Eq) (*models.APIKey, error) {
+	query := r.SQLBuilder.
+		Select(apiKeyColumns...).
+		From("api_keys").
+		Where(where)
+
+	key, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "api_key")
+	}
+	return key, nil
+}
+
+func (r *APIKeyRepository) scan(row sq.RowScanner) (*models.APIKey, error) {
+	var key models.APIKey
+	var lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.OrganizationID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		pq.Array(&key.Scopes),
+		&key.ExpiresAt,
+		&lastUsedAt,
+		&revokedAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return &key, nil
+}
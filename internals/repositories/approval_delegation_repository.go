@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const approvalDelegationColumns = "id, organization_id, delegator_id, delegate_id, starts_at, ends_at, reason, created_at"
+
+type ApprovalDelegationRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.ApprovalDelegationRepository = (*ApprovalDelegationRepository)(nil)
+
+// NewApprovalDelegationRepository creates a new instance of ApprovalDelegationRepository with the provided database connection.
+func NewApprovalDelegationRepository(db *sql.DB) *ApprovalDelegationRepository {
+	return &ApprovalDelegationRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create New Approval Delegation in the tenant
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		delegation: delegator, delegate and the period of the delegation.
+// returns:
+// 		ApprovalDelegation: the stored delegation.
+// 		errors: invalid reference when either user is not a member of the tenant.
+func (r *ApprovalDelegationRepository) Create(ctx context.Context, delegation *models.ApprovalDelegation) (*models.ApprovalDelegation, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Insert("approval_delegations").
+		Columns("organization_id", "delegator_id", "delegate_id", "starts_at", "ends_at", "reason").
+		Values(orgID, delegation.DelegatorID, delegation.DelegateID, delegation.StartsAt, delegation.EndsAt, delegation.Reason).
+		Suffix("RETURNING " + approvalDelegationColumns)
+
+	created, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "approval_delegation")
+	}
+	return created, nil
+}
+
+// Method to List the Approval Delegations given or received by a user
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		userID: the delegator or delegate.
+// returns:
+// 		[]ApprovalDelegation: the delegations, newest first.
+// 		errors: if any occurred during the operation.
+func (r *ApprovalDelegationRepository) List(ctx context.Context, userID string) ([]*models.ApprovalDelegation, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(approvalDelegationColumns).
+		From("approval_delegations").
+		Where(sq.Eq{"organization_id": orgID}).
+		Where(sq.Or{sq.Eq{"delegator_id": userID}, sq.Eq{"delegate_id": userID}}).
+		OrderBy("created_at DESC")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "approval_delegation")
+	}
+	defer rows.Close()
+
+	delegations := []*models.ApprovalDelegation{}
+	for rows.Next() {
+		delegation, err := r.scan(rows)
+		if err != nil {
+			return nil, translateError(err, "approval_delegation")
+		}
+		delegations = append(delegations, delegation)
+	}
+	return delegations, translateError(rows.Err(), "approval_delegation")
+}
+
+// Method to Delete an Approval Delegation given by the delegator
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the delegation.
+// 		delegatorID: the user who gave the delegation.
+// returns:
+// 		errors: not found when the delegation does not exist or was given by someone else.
+func (r *ApprovalDelegationRepository) Delete(ctx context.Context, id, delegatorID string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	query := r.SQLBuilder.
+		Delete("approval_delegations").
+		Where(sq.Eq{"id": id, "organization_id": orgID, "delegator_id": delegatorID})
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return translateError(err, "approval_delegation")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return translateError(sql.ErrNoRows, "approval_delegation")
+	}
+	return nil
+}
+
+// Method to get the users who delegated their approvals to a user
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		delegateID: the user acting on behalf of others.
+// 		at: the delegations running at this time are returned.
+// returns:
+// 		[]string: IDs of the delegators.
+// 		errors: if any occurred during the operation.
+func (r *ApprovalDelegationRepository) ActiveDelegators(ctx context.Context, delegateID string, at time.Time) ([]string, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select("DISTINCT delegator_id").
+		From("approval_delegations").
+		Where(sq.Eq{"organization_id": orgID, "delegate_id": delegateID}).
+		Where(sq.LtOrEq{"starts_at": at}).
+		Where(sq.Gt{"ends_at": at})
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "approval_delegation")
+	}
+	defer rows.Close()
+
+	var delegators []string
+	for rows.Next() {
+		var delegatorID string
+		if err := rows.Scan(&delegatorID); err != nil {
+			return nil, translateError(err, "approval_delegation")
+		}
+		delegators = append(delegators, delegatorID)
+	}
+	return delegators, translateError(rows.Err(), "approval_delegation")
+}
+
+func (r *ApprovalDelegationRepository) scan(row sq.RowScanner) (*models.ApprovalDelegation, error) {
+	var delegation models.ApprovalDelegation
+	err := row.Scan(
+		&delegation.ID,
+		&delegation.OrganizationID,
+		&delegation.DelegatorID,
+		&delegation.DelegateID,
+		&delegation.StartsAt,
+		&delegation.EndsAt,
+		&delegation.Reason,
+		&delegation.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &delegation, nil
+}
@@ -0,0 +1,497 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+const (
+	approvalRequestColumns = "id, organization_id, document_type, document_id, title, amount, department_id, " +
+		"requested_by, status, current_stage, created_at, updated_at, completed_at"
+	// the same columns qualified for joins with the steps
+	approvalRequestJoinColumns = "r.id, r.organization_id, r.document_type, r.document_id, r.title, r.amount, r.department_id, " +
+		"r.requested_by, r.status, r.current_stage, r.created_at, r.updated_at, r.completed_at"
+	approvalStepColumns = "s.id, s.stage, s.rule_id, s.name, s.approver_role, s.approver_user_id, s.status, " +
+		"s.acted_by, s.on_behalf_of, s.comment, s.acted_at"
+)
+
+type ApprovalRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.ApprovalRepository = (*ApprovalRepository)(nil)
+
+// NewApprovalRepository creates a new instance of ApprovalRepository with the provided database connection.
+func NewApprovalRepository(db *sql.DB) *ApprovalRepository {
+	return &ApprovalRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create New Approval Request with its steps
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		request: the document summary and the steps copied from the matching rules.
+// returns:
+// 		ApprovalRequest: the stored request with its steps.
+// 		errors: conflict when the document already has a pending request.
+func (r *ApprovalRepository) Create(ctx context.Context, request *models.ApprovalRequest) (*models.ApprovalRequest, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := r.SQLBuilder.
+		Insert("approval_requests").
+		Columns("organization_id", "document_type", "document_id", "title", "amount", "department_id",
+			"requested_by", "status", "current_stage").
+		Values(orgID, request.DocumentType, request.DocumentID, request.Title, request.Amount, request.DepartmentID,
+			request.RequestedBy, request.Status, request.CurrentStage).
+		Suffix("RETURNING " + approvalRequestColumns)
+
+	created, err := r.scan(query.RunWith(tx).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "approval")
+	}
+
+	for _, step := range request.Steps {
+		insert := r.SQLBuilder.
+			Insert("approval_request_steps").
+			Columns("request_id", "stage", "rule_id", "name", "approver_role", "approver_user_id", "status").
+			Values(created.ID, step.Stage, step.RuleID, step.Name, step.ApproverRole, step.ApproverUserID, step.Status).
+			Suffix("RETURNING id")
+
+		copied := *step
+		if err := insert.RunWith(tx).QueryRowContext(ctx).Scan(&copied.ID); err != nil {
+			return nil, translateError(err, "approval")
+		}
+		created.Steps = append(created.Steps, &copied)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Method to Get Approval Request By ID with its steps
+// It returns nil when the request does not exist in the tenant.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the request.
+// returns:
+// 		ApprovalRequest: the request with its steps ordered by stage.
+// 		errors: if any occurred during the operation.
+func (r *ApprovalRepository) GetByID(ctx context.Context, id string) (*models.ApprovalRequest, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.get(ctx, sq.Eq{"id": id, "organization_id": orgID})
+}
+
+// Method to Get the pending Approval Request of a document
+// It returns nil when the document has no pending request.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		documentType: type of the document, e.g. requisition.
+// 		documentID: ID of the document.
+// returns:
+// 		ApprovalRequest: the pending request with its steps.
+// 		errors: if any occurred during the operation.
+func (r *ApprovalRepository) GetPending(ctx context.Context, documentType, documentID string) (*models.ApprovalRequest, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.get(ctx, sq.Eq{
+		"organization_id": orgID,
+		"document_type":   documentType,
+		"document_id":     documentID,
+		"status":          models.ApprovalStatusPending,
+	})
+}
+
+// Method to List Approval Requests of the tenant with their steps, newest first
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		filter: optional document type, document and status.
+// 		limit: maximum number of requests to return.
+// 		offset: number of requests to skip.
+// returns:
+// 		[]ApprovalRequest: the requests with their steps.
+// 		int: total number of requests matching the filter.
+// 		errors: if any occurred during the operation.
+func (r *ApprovalRepository) List(ctx context.Context, filter models.ApprovalFilter, limit, offset int) ([]*models.ApprovalRequest, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	where := sq.Eq{"organization_id": orgID}
+	if filter.DocumentType != "" {
+		where["document_type"] = filter.DocumentType
+	}
+	if filter.DocumentID != "" {
+		where["document_id"] = filter.DocumentID
+	}
+	if filter.Status != "" {
+		where["status"] = filter.Status
+	}
+
+	var count int
+	countQuery := r.SQLBuilder.Select("COUNT(*)").From("approval_requests").Where(where)
+	if err := countQuery.RunWith(r.db).QueryRowContext(ctx).Scan(&count); err != nil {
+		return nil, 0, translateError(err, "approval")
+	}
+
+	query := r.SQLBuilder.
+		Select(approvalRequestColumns).
+		From("approval_requests").
+		Where(where).
+		OrderBy("created_at DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, 0, translateError(err, "approval")
+	}
+	defer rows.Close()
+
+	var requests []*models.ApprovalRequest
+	for rows.Next() {
+		request, err := r.scan(rows)
+		if err != nil {
+			return nil, 0, translateError(err, "approval")
+		}
+		requests = append(requests, request)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, translateError(err, "approval")
+	}
+	for _, request := range requests {
+		if request.Steps, err = r.steps(ctx, request.ID); err != nil {
+			return nil, 0, err
+		}
+	}
+	return requests, count, nil
+}
+
+// Method to List the pending Approval Steps a user may act on
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		filter: the users whose steps are included, the role, the requester and the actor to leave out.
+// 		limit: maximum number of steps to return.
+// 		offset: number of steps to skip.
+// returns:
+// 		[]PendingApproval: the steps with their requests (without steps), oldest request first.
+// 		int: total number of pending steps matching the filter.
+// 		errors: if any occurred during the operation.
+func (r *ApprovalRepository) ListPending(ctx context.Context, filter models.PendingApprovalFilter, limit, offset int) ([]*models.PendingApproval, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	assigned := sq.Or{
+		sq.Eq{"s.approver_user_id": filter.UserIDs},
+		sq.Eq{"s.approver_user_id": nil, "s.approver_role": filter.Role},
+	}
+	for _, role := range filter.DelegatedRoles {
+		// steps for the role of a delegator who neither requested the document nor decided a step of it
+		assigned = append(assigned, sq.And{
+			sq.Eq{"s.approver_user_id": nil, "s.approver_role": role.Role},
+			sq.NotEq{"r.requested_by": role.DelegatorID},
+			sq.Expr(`NOT EXISTS (SELECT 1 FROM approval_request_steps d WHERE d.request_id = r.id
+				AND (d.acted_by = ? OR d.on_behalf_of = ?))`, role.DelegatorID, role.DelegatorID),
+		})
+	}
+	where := sq.And{
+		sq.Eq{"r.organization_id": orgID, "r.status": models.ApprovalStatusPending, "s.status": models.ApprovalStepPending},
+		assigned,
+	}
+	if filter.ExcludeRequester != "" {
+		where = append(where, sq.NotEq{"r.requested_by": filter.ExcludeRequester})
+	}
+	if filter.ExcludeActor != "" {
+		// neither the user nor the member owning a delegated step decided a step of the request yet
+		where = append(where, sq.Expr(`NOT EXISTS (SELECT 1 FROM approval_request_steps d WHERE d.request_id = r.id
+			AND (d.acted_by = ? OR d.on_behalf_of = ? OR d.acted_by = s.approver_user_id OR d.on_behalf_of = s.approver_user_id))`,
+			filter.ExcludeActor, filter.ExcludeActor))
+	}
+
+	var count int
+	countQuery := r.SQLBuilder.
+		Select("COUNT(*)").
+		From("approval_request_steps s").
+		Join("approval_requests r ON r.id = s.request_id").
+		Where(where)
+	if err := countQuery.RunWith(r.db).QueryRowContext(ctx).Scan(&count); err != nil {
+		return nil, 0, translateError(err, "approval")
+	}
+
+	query := r.SQLBuilder.
+		Select(approvalRequestJoinColumns + ", " + approvalStepColumns).
+		From("approval_request_steps s").
+		Join("approval_requests r ON r.id = s.request_id").
+		Where(where).
+		OrderBy("r.created_at", "s.stage", "s.name").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, 0, translateError(err, "approval")
+	}
+	defer rows.Close()
+
+	var pending []*models.PendingApproval
+	for rows.Next() {
+		var request models.ApprovalRequest
+		var step models.ApprovalStep
+		err := rows.Scan(
+			&request.ID,
+			&request.OrganizationID,
+			&request.DocumentType,
+			&request.DocumentID,
+			&request.Title,
+			&request.Amount,
+			&request.DepartmentID,
+			&request.RequestedBy,
+			&request.Status,
+			&request.CurrentStage,
+			&request.CreatedAt,
+			&request.UpdatedAt,
+			&request.CompletedAt,
+			&step.ID,
+			&step.Stage,
+			&step.RuleID,
+			&step.Name,
+			&step.ApproverRole,
+			&step.ApproverUserID,
+			&step.Status,
+			&step.ActedBy,
+			&step.OnBehalfOf,
+			&step.Comment,
+			&step.ActedAt,
+		)
+		if err != nil {
+			return nil, 0, translateError(err, "approval")
+		}
+		pending = append(pending, &models.PendingApproval{Request: &request, Step: &step})
+	}
+	return pending, count, translateError(rows.Err(), "approval")
+}
+
+// Method to Decide a pending Approval Step
+// The request is locked while deciding, so two decisions of the same person on parallel
+// steps cannot both pass the one-person-one-step check.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		stepID: ID of the step.
+// 		decision: the new step status, the actor and the comment.
+// returns:
+// 		bool: false when the step is not pending (anymore), or the actor or the member they act
+// 		for already decided a step of the request.
+// 		errors: if any occurred during the operation.
+func (r *ApprovalRepository) Decide(ctx context.Context, stepID string, decision *models.ApprovalStepDecision) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var requestID string
+	err = r.SQLBuilder.
+		Select("r.id").
+		From("approval_requests r").
+		Join("approval_request_steps s ON s.request_id = r.id").
+		Where(sq.Eq{"s.id": stepID, "r.organization_id": orgID}).
+		Suffix("FOR UPDATE OF r").
+		RunWith(tx).QueryRowContext(ctx).Scan(&requestID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, translateError(err, "approval")
+	}
+
+	actors := []string{decision.ActedBy}
+	if decision.OnBehalfOf != nil {
+		actors = append(actors, *decision.OnBehalfOf)
+	}
+	query := r.SQLBuilder.
+		Update("approval_request_steps").
+		Set("status", decision.Status).
+		Set("acted_by", decision.ActedBy).
+		Set("on_behalf_of", decision.OnBehalfOf).
+		Set("comment", decision.Comment).
+		Set("acted_at", decision.ActedAt).
+		Where(sq.Eq{"id": stepID, "request_id": requestID, "status": models.ApprovalStepPending}).
+		Where(sq.Expr(`NOT EXISTS (SELECT 1 FROM approval_request_steps d WHERE d.request_id = ?
+			AND (d.acted_by = ANY(?::uuid[]) OR d.on_behalf_of = ANY(?::uuid[])))`, requestID, pq.Array(actors), pq.Array(actors)))
+
+	result, err := query.RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "approval")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+	return true, tx.Commit()
+}
+
+// Method to Advance a pending Approval Request
+// A pending transition moves the request to the next stage and activates its steps,
+// any other status completes the request and skips the steps nobody acted on.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		requestID: ID of the request.
+// 		fromStage: the stage the request is expected to be at.
+// 		transition: the new status and stage.
+// returns:
+// 		bool: false when the request is not pending at fromStage (anymore).
+// 		errors: if any occurred during the operation.
+func (r *ApprovalRepository) Advance(ctx context.Context, requestID string, fromStage int, transition *models.ApprovalTransition) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := r.SQLBuilder.
+		Update("approval_requests").
+		Set("status", transition.Status).
+		Set("current_stage", transition.Stage).
+		Set("completed_at", transition.CompletedAt).
+		Where(sq.Eq{
+			"id":              requestID,
+			"organization_id": orgID,
+			"status":          models.ApprovalStatusPending,
+			"current_stage":   fromStage,
+		})
+
+	result, err := query.RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "approval")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+
+	steps := r.SQLBuilder.Update("approval_request_steps")
+	if transition.Status == models.ApprovalStatusPending {
+		steps = steps.
+			Set("status", models.ApprovalStepPending).
+			Where(sq.Eq{"request_id": requestID, "stage": transition.Stage, "status": models.ApprovalStepWaiting})
+	} else {
+		steps = steps.
+			Set("status", models.ApprovalStepSkipped).
+			Where(sq.Eq{"request_id": requestID, "status": []string{models.ApprovalStepWaiting, models.ApprovalStepPending}})
+	}
+	if _, err := steps.RunWith(tx).ExecContext(ctx); err != nil {
+		return false, translateError(err, "approval")
+	}
+
+	return true, tx.Commit()
+}
+
+// get returns the first request matching where with its steps, nil when there is none
+func (r *ApprovalRepository) get(ctx context.Context, where sq.Eq) (*models.ApprovalRequest, error) {
+	query := r.SQLBuilder.
+		Select(approvalRequestColumns).
+		From("approval_requests").
+		Where(where)
+
+	request, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "approval")
+	}
+	if request.Steps, err = r.steps(ctx, request.ID); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// steps returns the steps of a request ordered by stage
+func (r *ApprovalRepository) steps(ctx context.Context, requestID string) ([]*models.ApprovalStep, error) {
+	query := r.SQLBuilder.
+		Select(approvalStepColumns).
+		From("approval_request_steps s").
+		Where(sq.Eq{"s.request_id": requestID}).
+		OrderBy("s.stage", "s.name")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "approval")
+	}
+	defer rows.Close()
+
+	var steps []*models.ApprovalStep
+	for rows.Next() {
+		var step models.ApprovalStep
+		err := rows.Scan(
+			&step.ID,
+			&step.Stage,
+			&step.RuleID,
+			&step.Name,
+			&step.ApproverRole,
+			&step.ApproverUserID,
+			&step.Status,
+			&step.ActedBy,
+			&step.OnBehalfOf,
+			&step.Comment,
+			&step.ActedAt,
+		)
+		if err != nil {
+			return nil, translateError(err, "approval")
+		}
+		steps = append(steps, &step)
+	}
+	return steps, translateError(rows.Err(), "approval")
+}
+
+func (r *ApprovalRepository) scan(row sq.RowScanner) (*models.ApprovalRequest, error) {
+	var request models.ApprovalRequest
+	err := row.Scan(
+		&request.ID,
+		&request.OrganizationID,
+		&request.DocumentType,
+		&request.DocumentID,
+		&request.Title,
+		&request.Amount,
+		&request.DepartmentID,
+		&request.RequestedBy,
+		&request.Status,
+		&request.CurrentStage,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+		&request.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
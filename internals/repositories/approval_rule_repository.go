@@ -0,0 +1,310 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const approvalRuleColumns = "id, organization_id, document_type, name, priority, amount_above, amount_up_to, " +
+	"category_id, department_id, active, created_at, updated_at"
+
+type ApprovalRuleRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.ApprovalRuleRepository = (*ApprovalRuleRepository)(nil)
+
+// NewApprovalRuleRepository creates a new instance of ApprovalRuleRepository with the provided database connection.
+func NewApprovalRuleRepository(db *sql.DB) *ApprovalRuleRepository {
+	return &ApprovalRuleRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create New Approval Rule with its steps
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		rule: conditions and steps of the rule.
+// returns:
+// 		ApprovalRule: the stored rule with its steps.
+// 		errors: invalid reference when the category, department or approver does not exist.
+func (r *ApprovalRuleRepository) Create(ctx context.Context, rule *models.ApprovalRule) (*models.ApprovalRule, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := r.SQLBuilder.
+		Insert("approval_rules").
+		Columns("organization_id", "document_type", "name", "priority", "amount_above", "amount_up_to",
+			"category_id", "department_id", "active").
+		Values(orgID, rule.DocumentType, rule.Name, rule.Priority, rule.AmountAbove, rule.AmountUpTo,
+			rule.CategoryID, rule.DepartmentID, rule.Active).
+		Suffix("RETURNING " + approvalRuleColumns)
+
+	created, err := r.scan(query.RunWith(tx).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "approval_rule")
+	}
+	if created.Steps, err = r.insertSteps(ctx, tx, created.ID, rule.Steps); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Method to Get Approval Rule By ID with its steps
+// It returns nil when the rule does not exist in the tenant.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the rule.
+// returns:
+// 		ApprovalRule: the rule with its steps ordered by level.
+// 		errors: if any occurred during the operation.
+func (r *ApprovalRuleRepository) GetByID(ctx context.Context, id string) (*models.ApprovalRule, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(approvalRuleColumns).
+		From("approval_rules").
+		Where(sq.Eq{"id": id, "organization_id": orgID})
+
+	rule, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "approval_rule")
+	}
+	if err := r.loadSteps(ctx, []*models.ApprovalRule{rule}); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Method to List Approval Rules of the tenant with their steps
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		documentType: only rules of this document type, every type when empty.
+// returns:
+// 		[]ApprovalRule: the rules ordered by priority then name.
+// 		errors: if any occurred during the operation.
+func (r *ApprovalRuleRepository) List(ctx context.Context, documentType string) ([]*models.ApprovalRule, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	where := sq.Eq{"organization_id": orgID}
+	if documentType != "" {
+		where["document_type"] = documentType
+	}
+	query := r.SQLBuilder.
+		Select(approvalRuleColumns).
+		From("approval_rules").
+		Where(where).
+		OrderBy("priority", "name")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "approval_rule")
+	}
+	defer rows.Close()
+
+	rules := []*models.ApprovalRule{}
+	for rows.Next() {
+		rule, err := r.scan(rows)
+		if err != nil {
+			return nil, translateError(err, "approval_rule")
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err, "approval_rule")
+	}
+	if err := r.loadSteps(ctx, rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Method to Update an Approval Rule
+// The rule fields are replaced and the steps are rewritten in one transaction,
+// approvals already running keep the steps they were started with.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		rule: the rule with its new values and steps.
+// returns:
+// 		ApprovalRule: the updated rule with its steps.
+// 		errors: not found when the rule does not exist in the tenant.
+func (r *ApprovalRuleRepository) Update(ctx context.Context, rule *models.ApprovalRule) (*models.ApprovalRule, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := r.SQLBuilder.
+		Update("approval_rules").
+		Set("document_type", rule.DocumentType).
+		Set("name", rule.Name).
+		Set("priority", rule.Priority).
+		Set("amount_above", rule.AmountAbove).
+		Set("amount_up_to", rule.AmountUpTo).
+		Set("category_id", rule.CategoryID).
+		Set("department_id", rule.DepartmentID).
+		Set("active", rule.Active).
+		Where(sq.Eq{"id": rule.ID, "organization_id": orgID}).
+		Suffix("RETURNING " + approvalRuleColumns)
+
+	updated, err := r.scan(query.RunWith(tx).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "approval_rule")
+	}
+
+	_, err = r.SQLBuilder.
+		Delete("approval_rule_steps").
+		Where(sq.Eq{"rule_id": rule.ID}).
+		RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "approval_rule")
+	}
+	if updated.Steps, err = r.insertSteps(ctx, tx, rule.ID, rule.Steps); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Method to Delete an Approval Rule
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the rule.
+// returns:
+// 		errors: not found when the rule does not exist in the tenant.
+func (r *ApprovalRuleRepository) Delete(ctx context.Context, id string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	query := r.SQLBuilder.
+		Delete("approval_rules").
+		Where(sq.Eq{"id": id, "organization_id": orgID})
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return translateError(err, "approval_rule")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return translateError(sql.ErrNoRows, "approval_rule")
+	}
+	return nil
+}
+
+// insertSteps stores the steps of a rule in the given order
+func (r *ApprovalRuleRepository) insertSteps(ctx context.Context, tx *sql.Tx, ruleID string, steps []*models.ApprovalRuleStep) ([]*models.ApprovalRuleStep, error) {
+	stored := make([]*models.ApprovalRuleStep, 0, len(steps))
+	for _, step := range steps {
+		query := r.SQLBuilder.
+			Insert("approval_rule_steps").
+			Columns("rule_id", "level", "name", "approver_type", "approver_role", "approver_user_id").
+			Values(ruleID, step.Level, step.Name, step.ApproverType, step.ApproverRole, step.ApproverUserID).
+			Suffix("RETURNING id")
+
+		copied := *step
+		if err := query.RunWith(tx).QueryRowContext(ctx).Scan(&copied.ID); err != nil {
+			return nil, translateError(err, "approval_rule")
+		}
+		stored = append(stored, &copied)
+	}
+	return stored, nil
+}
+
+// loadSteps attaches the steps of every rule, ordered by level
+func (r *ApprovalRuleRepository) loadSteps(ctx context.Context, rules []*models.ApprovalRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	byID := make(map[string]*models.ApprovalRule, len(rules))
+	ids := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		rule.Steps = []*models.ApprovalRuleStep{}
+		byID[rule.ID] = rule
+		ids = append(ids, rule.ID)
+	}
+
+	query := r.SQLBuilder.
+		Select("id", "rule_id", "level", "name", "approver_type", "approver_role", "approver_user_id").
+		From("approval_rule_steps").
+		Where(sq.Eq{"rule_id": ids}).
+		OrderBy("level", "name")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return translateError(err, "approval_rule")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var step models.ApprovalRuleStep
+		var ruleID string
+		err := rows.Scan(
+			&step.ID,
+			&ruleID,
+			&step.Level,
+			&step.Name,
+			&step.ApproverType,
+			&step.ApproverRole,
+			&step.ApproverUserID,
+		)
+		if err != nil {
+			return translateError(err, "approval_rule")
+		}
+		byID[ruleID].Steps = append(byID[ruleID].Steps, &step)
+	}
+	return translateError(rows.Err(), "approval_rule")
+}
+
+func (r *ApprovalRuleRepository) scan(row sq.RowScanner) (*models.ApprovalRule, error) {
+	var rule models.ApprovalRule
+	err := row.Scan(
+		&rule.ID,
+		&rule.OrganizationID,
+		&rule.DocumentType,
+		&rule.Name,
+		&rule.Priority,
+		&rule.AmountAbove,
+		&rule.AmountUpTo,
+		&rule.CategoryID,
+		&rule.DepartmentID,
+		&rule.Active,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const (
+	evaluationCriteriaColumns = "rfq_id, price_weight, lead_time_weight, rating_weight, technical_weight, updated_at"
+	quotationScoreColumns     = "quotation_id, vendor_rating, technical_score, notes, scored_by, scored_at"
+)
+
+type BidEvaluationRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.BidEvaluationRepository = (*BidEvaluationRepository)(nil)
+
+// NewBidEvaluationRepository creates a new instance of BidEvaluationRepository with the provided database connection.
+func NewBidEvaluationRepository(db *sql.DB) *BidEvaluationRepository {
+	return &BidEvaluationRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Get the EvaluationCriteria of an RFQ
+// It returns nil when the RFQ has no criteria yet.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		rfqID: ID of the RFQ.
+// returns:
+// 		EvaluationCriteria: the weights of the criteria.
+// 		errors: if any occurred during the operation.
+func (r *BidEvaluationRepository) GetCriteria(ctx context.Context, rfqID string) (*models.EvaluationCriteria, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(evaluationCriteriaColumns).
+		From("rfq_evaluation_criteria").
+		Where(sq.Eq{"rfq_id": rfqID}).
+		Where("rfq_id IN (SELECT id FROM rfqs WHERE organization_id = ?)", orgID)
+
+	criteria, err := r.scanCriteria(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "evaluation_criteria")
+	}
+	return criteria, nil
+}
+
+// Method to Save the EvaluationCriteria of an RFQ, replacing earlier criteria
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		criteria: the RFQ and the weights of its criteria.
+// returns:
+// 		EvaluationCriteria: the stored criteria.
+// 		errors: not found when the RFQ does not exist in the tenant.
+func (r *BidEvaluationRepository) SaveCriteria(ctx context.Context, criteria *models.EvaluationCriteria) (*models.EvaluationCriteria, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	source := r.SQLBuilder.
+		Select("id").
+		Column("?::numeric, ?::numeric, ?::numeric, ?::numeric",
+			criteria.PriceWeight, criteria.LeadTimeWeight, criteria.RatingWeight, criteria.TechnicalWeight).
+		From("rfqs").
+		Where(sq.Eq{"id": criteria.RFQID, "organization_id": orgID})
+
+	query := r.SQLBuilder.
+		Insert("rfq_evaluation_criteria").
+		Columns("rfq_id", "price_weight", "lead_time_weight", "rating_weight", "technical_weight").
+		Select(source).
+		Suffix("ON CONFLICT (rfq_id) DO UPDATE SET price_weight = EXCLUDED.price_weight, " +
+			"lead_time_weight = EXCLUDED.lead_time_weight, rating_weight = EXCLUDED.rating_weight, " +
+			"technical_weight = EXCLUDED.technical_weight RETURNING " + evaluationCriteriaColumns)
+
+	saved, err := r.scanCriteria(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "evaluation_criteria")
+	}
+	return saved, nil
+}
+
+// Method to Save the QuotationScore of a quotation, replacing an earlier score
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		score: the quotation, its vendor rating and technical score.
+// returns:
+// 		QuotationScore: the stored score.
+// 		errors: not found when the quotation does not exist in the tenant.
+func (r *BidEvaluationRepository) SaveScore(ctx context.Context, score *models.QuotationScore) (*models.QuotationScore, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	source := r.SQLBuilder.
+		Select("id").
+		Column("?::numeric, ?::numeric, ?::text, ?::uuid", score.VendorRating, score.TechnicalScore, score.Notes, score.ScoredBy).
+		From("quotations").
+		Where(sq.Eq{"id": score.QuotationID, "organization_id": orgID})
+
+	query := r.SQLBuilder.
+		Insert("quotation_scores").
+		Columns("quotation_id", "vendor_rating", "technical_score", "notes", "scored_by").
+		Select(source).
+		Suffix("ON CONFLICT (quotation_id) DO UPDATE SET vendor_rating = EXCLUDED.vendor_rating, " +
+			"technical_score = EXCLUDED.technical_score, notes = EXCLUDED.notes, " +
+			"scored_by = EXCLUDED.scored_by, scored_at = NOW() RETURNING " + quotationScoreColumns)
+
+	saved, err := r.scanScore(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "quotation_score")
+	}
+	return saved, nil
+}
+
+// Method to List the QuotationScores of the quotations of an RFQ
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		rfqID: ID of the RFQ.
+// returns:
+// 		[]QuotationScore: the scores, quotations without a score are left out.
+// 		errors: if any occurred during the operation.
+func (r *BidEvaluationRepository) ListScores(ctx context.Context, rfqID string) ([]*models.QuotationScore, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(quotationScoreColumns).
+		From("quotation_scores").
+		Where("quotation_id IN (SELECT id FROM quotations WHERE rfq_id = ? AND organization_id = ?)", rfqID, orgID)
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "quotation_score")
+	}
+	defer rows.Close()
+
+	scores := []*models.QuotationScore{}
+	for rows.Next() {
+		score, err := r.scanScore(rows)
+		if err != nil {
+			return nil, translateError(err, "quotation_score")
+		}
+		scores = append(scores, score)
+	}
+	return scores, translateError(rows.Err(), "quotation_score")
+}
+
+func (r *BidEvaluationRepository) scanCriteria(row sq.RowScanner) (*models.EvaluationCriteria, error) {
+	var criteria models.EvaluationCriteria
+	err := row.Scan(
+		&criteria.RFQID,
+		&criteria.PriceWeight,
+		&criteria.LeadTimeWeight,
+		&criteria.RatingWeight,
+		&criteria.TechnicalWeight,
+		&criteria.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &criteria, nil
+}
+
+func (r *BidEvaluationRepository) scanScore(row sq.RowScanner) (*models.QuotationScore, error) {
+	var score models.QuotationScore
+	err := row.Scan(
+		&score.QuotationID,
+		&score.VendorRating,
+		&score.TechnicalScore,
+		&score.Notes,
+		&score.ScoredBy,
+		&score.ScoredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &score, nil
+}
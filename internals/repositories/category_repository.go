@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
 
 	sq "github.com/Masterminds/squirrel"
 )
@@ -13,6 +14,8 @@ type CategoryRepository struct {
 	SQLBuilder sq.StatementBuilderType
 }
 
+var _ repository.CategoryRepository = (*CategoryRepository)(nil)
+
 // NewCategoryRepository creates a new instance of CategoryRepository with the provided database connection.
 func NewCategoryRepository(db *sql.DB) *CategoryRepository {
 	return &CategoryRepository{
@@ -29,22 +32,27 @@ func NewCategoryRepository(db *sql.DB) *CategoryRepository {
 // 		*models.Category: pointer to the created category model
 // 		error: error if any occurred during the operation
 func(c *CategoryRepository) CreateCategory(ctx context.Context, category *models.CreateCategoryRequest) (*models.Category, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := c.SQLBuilder.
 		Insert("categories").
-		Columns("category_name", "descriptions").
-		Values(category.Name,category.Description).
-		Suffix("RETURNING id, category_name, descriptions, created_at, updated_at")
+		Columns("category_name", "descriptions", "organization_id").
+		Values(category.Name,category.Description, orgID).
+		Suffix("RETURNING id, category_name, descriptions, organization_id, created_at, updated_at")
 
 	row := query.RunWith(c.db).QueryRowContext(ctx)
 	var categoryResponse models.Category
-	err := row.Scan(
+	err = row.Scan(
 		&categoryResponse.ID,
 		&categoryResponse.Name,
 		&categoryResponse.Description,
+		&categoryResponse.OrganizationID,
 		&categoryResponse.CreatedAt,
 		&categoryResponse.UpdatedAt,)
 	if err != nil {
-		return nil,err
+		return nil, translateError(err, "category")
 	}
 	return &categoryResponse,nil
 }
@@ -56,16 +64,22 @@ func(c *CategoryRepository) CreateCategory(ctx context.Context, category *models
 // 		[]*models.Category: slice of pointers to Category models
 // 		error: error if any occurred during the operation
 func(c *CategoryRepository) GetAllCategories(ctx context.Context,limit,offset int) ([]*models.Category, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := c.SQLBuilder.
 		Select("id", "category_name", "descriptions", "created_at", "updated_at").
 		From("categories").
+		Where(sq.Eq{"organization_id": orgID}).
+		OrderBy("created_at").
 		Limit(uint64(limit)).
 		Offset(uint64(offset))
 
 
 	rows, err := query.RunWith(c.db).QueryContext(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "category")
 	}
 	defer rows.Close()
 
@@ -79,12 +93,12 @@ func(c *CategoryRepository) GetAllCategories(ctx context.Context,limit,offset in
 			&category.CreatedAt,
 			&category.UpdatedAt,
 		); err != nil {
-			return nil, err
+			return nil, translateError(err, "category")
 		}
 		categories = append(categories, &category)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, translateError(err, "category")
 	}
 	return categories, nil
 }
@@ -97,15 +111,19 @@ func(c *CategoryRepository) GetAllCategories(ctx context.Context,limit,offset in
 // 		*models.Category: pointer to the Category model if found
 // 		error: error if any occurred during the operation
 func(c *CategoryRepository) GetCategoryByID(ctx context.Context, id string) (*models.Category, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := c.SQLBuilder.
 		Select("id", "category_name", "descriptions", "created_at", "updated_at").
 		From("categories").
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "organization_id": orgID}).
 		Limit(1)
 
 	row := query.RunWith(c.db).QueryRowContext(ctx)
 	var category models.Category
-	err := row.Scan(
+	err = row.Scan(
 		&category.ID,
 		&category.Name,
 		&category.Description,
@@ -116,7 +134,7 @@ func(c *CategoryRepository) GetCategoryByID(ctx context.Context, id string) (*mo
 		if err == sql.ErrNoRows {
 			return nil, nil // No category found
 		}
-		return nil, err
+		return nil, translateError(err, "category")
 	}
 	return &category, nil
 }
@@ -129,19 +147,24 @@ func(c *CategoryRepository) GetCategoryByID(ctx context.Context, id string) (*mo
 // 		*models.Category: pointer to the updated Category model
 // 		error: error if any occurred during the operation
 func(c *CategoryRepository) UpdateCategory(ctx context.Context,id string, category *models.UpdateCategoryRequest) (*models.Category, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := c.SQLBuilder.
 		Update("categories").
 		Set("category_name", category.Name).
 		Set("descriptions", category.Description).
-		Where(sq.Eq{"id": id}).
-		Suffix("RETURNING id, category_name, descriptions, created_at, updated_at")
+		Where(sq.Eq{"id": id, "organization_id": orgID}).
+		Suffix("RETURNING id, category_name, descriptions, organization_id, created_at, updated_at")
 
 	row := query.RunWith(c.db).QueryRowContext(ctx)
 	var updatedCategory models.Category
-	err := row.Scan(
+	err = row.Scan(
 		&updatedCategory.ID,
 		&updatedCategory.Name,
 		&updatedCategory.Description,
+		&updatedCategory.OrganizationID,
 		&updatedCategory.CreatedAt,
 		&updatedCategory.UpdatedAt,
 	)
@@ -149,7 +172,7 @@ func(c *CategoryRepository) UpdateCategory(ctx context.Context,id string, catego
 		if err == sql.ErrNoRows {
 			return nil, nil // No category found to update
 		}
-		return nil, err
+		return nil, translateError(err, "category")
 	}
 	return &updatedCategory, nil
 }
@@ -161,34 +184,43 @@ func(c *CategoryRepository) UpdateCategory(ctx context.Context,id string, catego
 // returns:
 // 		error: error if any occurred during the operation
 func(c *CategoryRepository) DeleteCategory(ctx context.Context, id string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
 	query := c.SQLBuilder.
 		Delete("categories").
-		Where(sq.Eq{"id": id})
+		Where(sq.Eq{"id": id, "organization_id": orgID})
 
 	result, err := query.RunWith(c.db).ExecContext(ctx)
 	if err != nil {
-		return err
+		return translateError(err, "category")
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return translateError(err, "category")
 	}
 	if rowsAffected == 0 {
-		return sql.ErrNoRows // No category found to delete
+		return translateError(sql.ErrNoRows, "category")
 	}
 	return nil
 }
 
 func(c *CategoryRepository) CountAllCategories(ctx context.Context) (int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
 	query := c.SQLBuilder.
 		Select("COUNT(*)").
-		From("categories")
+		From("categories").
+		Where(sq.Eq{"organization_id": orgID})
 
 	row := query.RunWith(c.db).QueryRowContext(ctx)
 	var count int
 	if err := row.Scan(&count); err != nil {
-		return 0, err
+		return 0, translateError(err, "category")
 	}
 	return count, nil
 }
\ No newline at end of file
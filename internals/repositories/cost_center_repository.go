@@ -0,0 +1,217 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const costCenterColumns = "id, organization_id, code, name, description, active, created_at, updated_at"
+
+type CostCenterRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.CostCenterRepository = (*CostCenterRepository)(nil)
+
+// NewCostCenterRepository creates a new instance of CostCenterRepository with the provided database connection.
+func NewCostCenterRepository(db *sql.DB) *CostCenterRepository {
+	return &CostCenterRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create New Cost Center in the tenant
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		costCenter: code, name and description of the cost center.
+// returns:
+// 		CostCenter: the stored cost center.
+// 		errors: conflict when the code is already used in the tenant.
+func (r *CostCenterRepository) Create(ctx context.Context, costCenter *models.CreateCostCenterRequest) (*models.CostCenter, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Insert("cost_centers").
+		Columns("organization_id", "code", "name", "description").
+		Values(orgID, costCenter.Code, costCenter.Name, costCenter.Description).
+		Suffix("RETURNING " + costCenterColumns)
+
+	created, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "cost_center")
+	}
+	return created, nil
+}
+
+// Method to Get Cost Center By ID
+// It returns nil when the cost center does not exist in the tenant.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the cost center.
+// returns:
+// 		CostCenter: the stored cost center.
+// 		errors: if any occurred during the operation.
+func (r *CostCenterRepository) GetByID(ctx context.Context, id string) (*models.CostCenter, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(costCenterColumns).
+		From("cost_centers").
+		Where(sq.Eq{"id": id, "organization_id": orgID})
+
+	costCenter, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "cost_center")
+	}
+	return costCenter, nil
+}
+
+// Method to List the Cost Centers of the tenant ordered by code
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		limit: maximum number of cost centers to return.
+// 		offset: number of cost centers to skip.
+// returns:
+// 		[]CostCenter: the cost centers.
+// 		errors: if any occurred during the operation.
+func (r *CostCenterRepository) List(ctx context.Context, limit, offset int) ([]*models.CostCenter, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(costCenterColumns).
+		From("cost_centers").
+		Where(sq.Eq{"organization_id": orgID}).
+		OrderBy("code").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "cost_center")
+	}
+	defer rows.Close()
+
+	var costCenters []*models.CostCenter
+	for rows.Next() {
+		costCenter, err := r.scan(rows)
+		if err != nil {
+			return nil, translateError(err, "cost_center")
+		}
+		costCenters = append(costCenters, costCenter)
+	}
+	return costCenters, translateError(rows.Err(), "cost_center")
+}
+
+// Method to Count the Cost Centers of the tenant
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// returns:
+// 		int: total number of cost centers.
+// 		errors: if any occurred during the operation.
+func (r *CostCenterRepository) Count(ctx context.Context) (int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+	query := r.SQLBuilder.
+		Select("COUNT(*)").
+		From("cost_centers").
+		Where(sq.Eq{"organization_id": orgID})
+
+	var count int
+	if err := query.RunWith(r.db).QueryRowContext(ctx).Scan(&count); err != nil {
+		return 0, translateError(err, "cost_center")
+	}
+	return count, nil
+}
+
+// Method to Update a Cost Center
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the cost center.
+// 		costCenter: the new values, a nil Active keeps the current state.
+// returns:
+// 		CostCenter: the updated cost center.
+// 		errors: not found when the cost center does not exist in the tenant.
+func (r *CostCenterRepository) Update(ctx context.Context, id string, costCenter *models.UpdateCostCenterRequest) (*models.CostCenter, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Update("cost_centers").
+		Set("code", costCenter.Code).
+		Set("name", costCenter.Name).
+		Set("description", costCenter.Description).
+		Where(sq.Eq{"id": id, "organization_id": orgID}).
+		Suffix("RETURNING " + costCenterColumns)
+	if costCenter.Active != nil {
+		query = query.Set("active", *costCenter.Active)
+	}
+
+	updated, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "cost_center")
+	}
+	return updated, nil
+}
+
+// Method to Delete a Cost Center
+// Cost centers still used by departments or members cannot be deleted, deactivate them instead.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the cost center.
+// returns:
+// 		errors: not found when the cost center does not exist in the tenant.
+func (r *CostCenterRepository) Delete(ctx context.Context, id string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	query := r.SQLBuilder.
+		Delete("cost_centers").
+		Where(sq.Eq{"id": id, "organization_id": orgID})
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return translateError(err, "cost_center")
+	}
+	affected, _ := result.RowsAffected()
+	if affected == 0 {
+		return translateError(sql.ErrNoRows, "cost_center")
+	}
+	return nil
+}
+
+func (r *CostCenterRepository) scan(row sq.RowScanner) (*models.CostCenter, error) {
+	var costCenter models.CostCenter
+	err := row.Scan(
+		&costCenter.ID,
+		&costCenter.OrganizationID,
+		&costCenter.Code,
+		&costCenter.Name,
+		&costCenter.Description,
+		&costCenter.Active,
+		&costCenter.CreatedAt,
+		&costCenter.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &costCenter, nil
+}
@@ -0,0 +1,250 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const departmentColumns = "id, organization_id, code, name, parent_id, manager_id, default_cost_center_id, created_at, updated_at"
+
+type DepartmentRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.DepartmentRepository = (*DepartmentRepository)(nil)
+
+// NewDepartmentRepository creates a new instance of DepartmentRepository with the provided database connection.
+func NewDepartmentRepository(db *sql.DB) *DepartmentRepository {
+	return &DepartmentRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create New Department in the tenant
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		department: code, name, parent, manager and default cost center of the department.
+// returns:
+// 		Department: the stored department.
+// 		errors: conflict when the code is already used, invalid reference when the
+// 		parent, manager or cost center is not part of the tenant.
+func (r *DepartmentRepository) Create(ctx context.Context, department *models.DepartmentRequest) (*models.Department, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Insert("departments").
+		Columns("organization_id", "code", "name", "parent_id", "manager_id", "default_cost_center_id").
+		Values(orgID, department.Code, department.Name, department.ParentID, department.ManagerID, department.DefaultCostCenterID).
+		Suffix("RETURNING " + departmentColumns)
+
+	created, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "department")
+	}
+	return created, nil
+}
+
+// Method to Get Department By ID
+// It returns nil when the department does not exist in the tenant.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the department.
+// returns:
+// 		Department: the stored department.
+// 		errors: if any occurred during the operation.
+func (r *DepartmentRepository) GetByID(ctx context.Context, id string) (*models.Department, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(departmentColumns).
+		From("departments").
+		Where(sq.Eq{"id": id, "organization_id": orgID})
+
+	department, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "department")
+	}
+	return department, nil
+}
+
+// Method to List every Department of the tenant ordered by code
+// The whole tree is returned at once, organizations have at most a few hundred departments.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// returns:
+// 		[]Department: the departments.
+// 		errors: if any occurred during the operation.
+func (r *DepartmentRepository) List(ctx context.Context) ([]*models.Department, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(departmentColumns).
+		From("departments").
+		Where(sq.Eq{"organization_id": orgID}).
+		OrderBy("code")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "department")
+	}
+	defer rows.Close()
+
+	var departments []*models.Department
+	for rows.Next() {
+		department, err := r.scan(rows)
+		if err != nil {
+			return nil, translateError(err, "department")
+		}
+		departments = append(departments, department)
+	}
+	return departments, translateError(rows.Err(), "department")
+}
+
+// Method to Update a Department
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the department.
+// 		department: the new values, nil references are cleared.
+// returns:
+// 		Department: the updated department.
+// 		errors: not found when the department does not exist in the tenant.
+func (r *DepartmentRepository) Update(ctx context.Context, id string, department *models.DepartmentRequest) (*models.Department, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Update("departments").
+		Set("code", department.Code).
+		Set("name", department.Name).
+		Set("parent_id", department.ParentID).
+		Set("manager_id", department.ManagerID).
+		Set("default_cost_center_id", department.DefaultCostCenterID).
+		Where(sq.Eq{"id": id, "organization_id": orgID}).
+		Suffix("RETURNING " + departmentColumns)
+
+	updated, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "department")
+	}
+	return updated, nil
+}
+
+// Method to Delete a Department
+// Departments with sub departments or members cannot be deleted.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the department.
+// returns:
+// 		errors: not found when the department does not exist in the tenant.
+func (r *DepartmentRepository) Delete(ctx context.Context, id string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	query := r.SQLBuilder.
+		Delete("departments").
+		Where(sq.Eq{"id": id, "organization_id": orgID})
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return translateError(err, "department")
+	}
+	affected, _ := result.RowsAffected()
+	if affected == 0 {
+		return translateError(sql.ErrNoRows, "department")
+	}
+	return nil
+}
+
+// Method to Get the Department Assignment of a member of the tenant
+// It returns nil when the user is not a member.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		userID: ID of the member.
+// returns:
+// 		DepartmentAssignment: department and cost center override of the member.
+// 		errors: if any occurred during the operation.
+func (r *DepartmentRepository) GetAssignment(ctx context.Context, userID string) (*models.DepartmentAssignment, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select("user_id", "department_id", "cost_center_id").
+		From("organization_members").
+		Where(sq.Eq{"organization_id": orgID, "user_id": userID})
+
+	var assignment models.DepartmentAssignment
+	err = query.RunWith(r.db).QueryRowContext(ctx).Scan(&assignment.UserID, &assignment.DepartmentID, &assignment.CostCenterID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "organization_member")
+	}
+	return &assignment, nil
+}
+
+// Method to Assign a member of the tenant to a department
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		assignment: the member with the department and cost center override, nil clears them.
+// returns:
+// 		errors: not found when the user is not a member, invalid reference when the
+// 		department or cost center is not part of the tenant.
+func (r *DepartmentRepository) Assign(ctx context.Context, assignment *models.DepartmentAssignment) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	query := r.SQLBuilder.
+		Update("organization_members").
+		Set("department_id", assignment.DepartmentID).
+		Set("cost_center_id", assignment.CostCenterID).
+		Where(sq.Eq{"organization_id": orgID, "user_id": assignment.UserID})
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return translateError(err, "organization_member")
+	}
+	affected, _ := result.RowsAffected()
+	if affected == 0 {
+		return translateError(sql.ErrNoRows, "organization_member")
+	}
+	return nil
+}
+
+func (r *DepartmentRepository) scan(row sq.RowScanner) (*models.Department, error) {
+	var department models.Department
+	err := row.Scan(
+		&department.ID,
+		&department.OrganizationID,
+		&department.Code,
+		&department.Name,
+		&department.ParentID,
+		&department.ManagerID,
+		&department.DefaultCostCenterID,
+		&department.CreatedAt,
+		&department.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &department, nil
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"database/sql"
+	"e-procurement/pkg/apperror"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// postgres error codes translated into domain errors
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+	pqNotNullViolation    = "23502"
+	pqCheckViolation      = "23514"
+	pqInvalidTextInput    = "22P02"
+)
+
+// translateError converts sql.ErrNoRows and pq constraint violations into domain errors
+// so usecases and handlers never have to know about the database driver.
+// entity is the singular resource name used to build error codes, e.g. "product".
+func translateError(err error, entity string) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperror.NotFound(entity+"_not_found", entity+" not found").WithCause(err)
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	switch pqErr.Code {
+	case pqUniqueViolation:
+		return apperror.Conflict(entity+"_already_exists", entity+" already exists").WithCause(err)
+	case pqForeignKeyViolation:
+		return apperror.Validation(entity+"_invalid_reference",
+			fmt.Sprintf("%s references a record that does not exist or is still in use", entity)).WithCause(err)
+	case pqNotNullViolation, pqCheckViolation, pqInvalidTextInput:
+		return apperror.Validation(entity+"_invalid", "invalid "+entity+" data").WithCause(err)
+	}
+	return err
+}
@@ -0,0 +1,289 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const goodsReceiptColumns = "gr.id, gr.organization_id, gr.purchase_order_id, po.po_number, po.vendor_id, v.vendor_name, " +
+	"gr.delivery_note, gr.received_at, gr.notes, gr.received_by, gr.created_at"
+
+type GoodsReceiptRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.GoodsReceiptRepository = (*GoodsReceiptRepository)(nil)
+
+// NewGoodsReceiptRepository creates a new instance of GoodsReceiptRepository with the provided database connection.
+func NewGoodsReceiptRepository(db *sql.DB) *GoodsReceiptRepository {
+	return &GoodsReceiptRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create New GoodsReceipt with its lines
+// The purchase order is transitioned in the same transaction, only when it still has the status and
+// update time it was read with, so two receipts recorded at once cannot both pass the quantity checks.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		receipt: header and lines, the accepted quantities are stored as given.
+// 		order: the purchase order the receipt was checked against.
+// 		transition: the status the order moves to after the receipt.
+// returns:
+// 		bool: false when the order changed since it was read.
+// 		errors: invalid reference when the order, a line or the user does not exist.
+func (r *GoodsReceiptRepository) Create(ctx context.Context, receipt *models.GoodsReceipt, order *models.PurchaseOrder, transition *models.PurchaseOrderTransition) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := setTransition(r.SQLBuilder.Update("purchase_orders"), transition).
+		Where(sq.Eq{"id": order.ID, "organization_id": orgID, "status": order.Status, "updated_at": order.UpdatedAt}).
+		RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "purchase_order")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+
+	err = r.SQLBuilder.
+		Insert("goods_receipts").
+		Columns("organization_id", "purchase_order_id", "delivery_note", "received_at", "notes", "received_by").
+		Values(orgID, order.ID, receipt.DeliveryNote, receipt.ReceivedAt, receipt.Notes, receipt.ReceivedBy).
+		Suffix("RETURNING id, created_at").
+		RunWith(tx).QueryRowContext(ctx).Scan(&receipt.ID, &receipt.CreatedAt)
+	if err != nil {
+		return false, translateError(err, "goods_receipt")
+	}
+	for _, line := range receipt.Lines {
+		err := r.SQLBuilder.
+			Insert("goods_receipt_lines").
+			Columns("goods_receipt_id", "purchase_order_line_id", "received_quantity", "accepted_quantity",
+				"rejected_quantity", "inspection_notes").
+			Values(receipt.ID, line.PurchaseOrderLineID, line.ReceivedQuantity, line.AcceptedQuantity,
+				line.RejectedQuantity, line.InspectionNotes).
+			Suffix("RETURNING id").
+			RunWith(tx).QueryRowContext(ctx).Scan(&line.ID)
+		if err != nil {
+			return false, translateError(err, "goods_receipt")
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// Method to Get GoodsReceipt By ID with its lines
+// It returns nil when the receipt does not exist in the tenant.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the goods receipt.
+// returns:
+// 		GoodsReceipt: the receipt with its lines ordered by PO line number.
+// 		errors: if any occurred during the operation.
+func (r *GoodsReceiptRepository) GetByID(ctx context.Context, id string) (*models.GoodsReceipt, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(goodsReceiptColumns).
+		From("goods_receipts gr").
+		Join("purchase_orders po ON po.id = gr.purchase_order_id").
+		Join("vendors v ON v.id = po.vendor_id").
+		Where(sq.Eq{"gr.id": id, "gr.organization_id": orgID})
+
+	receipt, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "goods_receipt")
+	}
+	if err := r.attachLines(ctx, []*models.GoodsReceipt{receipt}); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// Method to List GoodsReceipts of the tenant, newest first
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		filter: optional purchase order and vendor.
+// 		limit: maximum number of receipts to return.
+// 		offset: number of receipts to skip.
+// returns:
+// 		[]GoodsReceipt: the receipt headers without lines.
+// 		int: total number of receipts matching the filter.
+// 		errors: if any occurred during the operation.
+func (r *GoodsReceiptRepository) List(ctx context.Context, filter models.GoodsReceiptFilter, limit, offset int) ([]*models.GoodsReceipt, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	where := sq.Eq{"gr.organization_id": orgID}
+	if filter.PurchaseOrderID != "" {
+		where["gr.purchase_order_id"] = filter.PurchaseOrderID
+	}
+	if filter.VendorID != "" {
+		where["po.vendor_id"] = filter.VendorID
+	}
+
+	var count int
+	countQuery := r.SQLBuilder.
+		Select("COUNT(*)").
+		From("goods_receipts gr").
+		Join("purchase_orders po ON po.id = gr.purchase_order_id").
+		Where(where)
+	if err := countQuery.RunWith(r.db).QueryRowContext(ctx).Scan(&count); err != nil {
+		return nil, 0, translateError(err, "goods_receipt")
+	}
+
+	query := r.SQLBuilder.
+		Select(goodsReceiptColumns).
+		From("goods_receipts gr").
+		Join("purchase_orders po ON po.id = gr.purchase_order_id").
+		Join("vendors v ON v.id = po.vendor_id").
+		Where(where).
+		OrderBy("gr.received_at DESC", "gr.created_at DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	receipts, err := r.list(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return receipts, count, nil
+}
+
+// Method to List every GoodsReceipt of a purchase order with its lines, oldest first
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		purchaseOrderID: ID of the purchase order.
+// returns:
+// 		[]GoodsReceipt: the receipts with their lines.
+// 		errors: if any occurred during the operation.
+func (r *GoodsReceiptRepository) ListByPurchaseOrder(ctx context.Context, purchaseOrderID string) ([]*models.GoodsReceipt, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(goodsReceiptColumns).
+		From("goods_receipts gr").
+		Join("purchase_orders po ON po.id = gr.purchase_order_id").
+		Join("vendors v ON v.id = po.vendor_id").
+		Where(sq.Eq{"gr.purchase_order_id": purchaseOrderID, "gr.organization_id": orgID}).
+		OrderBy("gr.received_at", "gr.created_at")
+
+	receipts, err := r.list(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachLines(ctx, receipts); err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+// list runs a receipt header query
+func (r *GoodsReceiptRepository) list(ctx context.Context, query sq.SelectBuilder) ([]*models.GoodsReceipt, error) {
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "goods_receipt")
+	}
+	defer rows.Close()
+
+	var receipts []*models.GoodsReceipt
+	for rows.Next() {
+		receipt, err := r.scan(rows)
+		if err != nil {
+			return nil, translateError(err, "goods_receipt")
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts, translateError(rows.Err(), "goods_receipt")
+}
+
+// attachLines loads the lines of the receipts in one query, with the line number, description
+// and unit of the purchase order line they were received against
+func (r *GoodsReceiptRepository) attachLines(ctx context.Context, receipts []*models.GoodsReceipt) error {
+	if len(receipts) == 0 {
+		return nil
+	}
+	byID := make(map[string]*models.GoodsReceipt, len(receipts))
+	ids := make([]string, 0, len(receipts))
+	for _, receipt := range receipts {
+		byID[receipt.ID] = receipt
+		ids = append(ids, receipt.ID)
+	}
+	query := r.SQLBuilder.
+		Select("grl.goods_receipt_id", "grl.id", "grl.purchase_order_line_id", "pol.line_no", "pol.description", "pol.unit",
+			"grl.received_quantity", "grl.accepted_quantity", "grl.rejected_quantity", "grl.inspection_notes").
+		From("goods_receipt_lines grl").
+		Join("purchase_order_lines pol ON pol.id = grl.purchase_order_line_id").
+		Where(sq.Eq{"grl.goods_receipt_id": ids}).
+		OrderBy("pol.line_no")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return translateError(err, "goods_receipt")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var receiptID string
+		var line models.GoodsReceiptLine
+		err := rows.Scan(
+			&receiptID,
+			&line.ID,
+			&line.PurchaseOrderLineID,
+			&line.LineNo,
+			&line.Description,
+			&line.Unit,
+			&line.ReceivedQuantity,
+			&line.AcceptedQuantity,
+			&line.RejectedQuantity,
+			&line.InspectionNotes,
+		)
+		if err != nil {
+			return translateError(err, "goods_receipt")
+		}
+		receipt := byID[receiptID]
+		receipt.Lines = append(receipt.Lines, &line)
+	}
+	return translateError(rows.Err(), "goods_receipt")
+}
+
+func (r *GoodsReceiptRepository) scan(row sq.RowScanner) (*models.GoodsReceipt, error) {
+	var receipt models.GoodsReceipt
+	err := row.Scan(
+		&receipt.ID,
+		&receipt.OrganizationID,
+		&receipt.PurchaseOrderID,
+		&receipt.PurchaseOrderNumber,
+		&receipt.VendorID,
+		&receipt.VendorName,
+		&receipt.DeliveryNote,
+		&receipt.ReceivedAt,
+		&receipt.Notes,
+		&receipt.ReceivedBy,
+		&receipt.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
@@ -0,0 +1,445 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"math"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+const invoiceColumns = "inv.id, inv.organization_id, inv.vendor_id, v.vendor_name, inv.purchase_order_id, po.po_number, " +
+	"inv.invoice_number, inv.invoice_date, inv.status, inv.match_status, inv.subtotal, inv.tax_amount, inv.total_amount, " +
+	"inv.notes, inv.submitted_by, inv.matched_at, inv.reviewed_by, inv.reviewed_at, inv.review_note, inv.payment_terms, " +
+	"inv.due_date, inv.discount_date, inv.discount_amount, inv.created_at, inv.updated_at"
+
+type InvoiceRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.InvoiceRepository = (*InvoiceRepository)(nil)
+
+// NewInvoiceRepository creates a new instance of InvoiceRepository with the provided database connection.
+func NewInvoiceRepository(db *sql.DB) *InvoiceRepository {
+	return &InvoiceRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create New Invoice with its lines
+// The purchase order row is locked in the same transaction and the quantities already invoiced
+// on the order are read again under the lock, so two invoices submitted at once cannot both be
+// matched against the quantities of neither.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		invoice: header and matched lines, the status and totals are stored as given. Its ID is set.
+// returns:
+// 		bool: false when the quantities invoiced on the order differ from the previously invoiced
+// 		quantities of the lines, the invoice was then matched against stale quantities.
+// 		errors: conflict when the vendor already submitted the invoice number,
+// 		invalid reference when the vendor, order or an order line does not exist.
+func (r *InvoiceRepository) Create(ctx context.Context, invoice *models.Invoice) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var orderID string
+	err = r.SQLBuilder.
+		Select("id").
+		From("purchase_orders").
+		Where(sq.Eq{"id": invoice.PurchaseOrderID, "organization_id": orgID}).
+		Suffix("FOR UPDATE").
+		RunWith(tx).QueryRowContext(ctx).Scan(&orderID)
+	// a missing order is reported by the foreign key of the insert
+	if err != nil && err != sql.ErrNoRows {
+		return false, translateError(err, "invoice")
+	}
+	invoiced, err := r.invoicedQuantities(ctx, tx, orgID, invoice.PurchaseOrderID, "")
+	if err != nil {
+		return false, err
+	}
+	if !sameQuantities(invoice, invoiced) {
+		return false, nil
+	}
+
+	var invoiceID string
+	err = r.SQLBuilder.
+		Insert("invoices").
+		Columns("organization_id", "vendor_id", "purchase_order_id", "invoice_number", "invoice_date", "status",
+			"match_status", "subtotal", "tax_amount", "total_amount", "notes", "submitted_by", "matched_at").
+		Values(orgID, invoice.VendorID, invoice.PurchaseOrderID, invoice.InvoiceNumber, invoice.InvoiceDate, invoice.Status,
+			invoice.MatchStatus, invoice.Subtotal, invoice.TaxAmount, invoice.TotalAmount, invoice.Notes, invoice.SubmittedBy,
+			invoice.MatchedAt).
+		Suffix("RETURNING id").
+		RunWith(tx).QueryRowContext(ctx).Scan(&invoiceID)
+	if err != nil {
+		return false, translateError(err, "invoice")
+	}
+	for _, line := range invoice.Lines {
+		query := r.SQLBuilder.
+			Insert("invoice_lines").
+			Columns("invoice_id", "line_no", "purchase_order_line_id", "quantity", "unit_price", "amount", "tax_rate",
+				"tax_amount", "order_unit_price", "order_tax_rate", "accepted_quantity", "previously_invoiced",
+				"match_status", "match_exceptions").
+			Values(invoiceID, line.LineNo, line.PurchaseOrderLineID, line.Quantity, line.UnitPrice, line.Amount, line.TaxRate,
+				line.TaxAmount, line.OrderUnitPrice, line.OrderTaxRate, line.AcceptedQuantity, line.PreviouslyInvoiced,
+				line.MatchStatus, pq.Array(line.MatchExceptions))
+
+		if _, err := query.RunWith(tx).ExecContext(ctx); err != nil {
+			return false, translateError(err, "invoice")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	invoice.ID = invoiceID
+	return true, nil
+}
+
+// Method to Get Invoice By ID with its lines
+// It returns nil when the invoice does not exist in the tenant.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the invoice.
+// returns:
+// 		Invoice: the invoice with its lines ordered by line number.
+// 		errors: if any occurred during the operation.
+func (r *InvoiceRepository) GetByID(ctx context.Context, id string) (*models.Invoice, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(invoiceColumns).
+		From("invoices inv").
+		Join("vendors v ON v.id = inv.vendor_id").
+		Join("purchase_orders po ON po.id = inv.purchase_order_id").
+		Where(sq.Eq{"inv.id": id, "inv.organization_id": orgID})
+
+	invoice, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "invoice")
+	}
+	if invoice.Lines, err = r.lines(ctx, id); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// Method to List Invoices of the tenant, newest first unless the filter asks for the oldest first
+// or the earliest due date first
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		filter: optional status, vendor, purchase order and latest due date.
+// 		limit: maximum number of invoices to return.
+// 		offset: number of invoices to skip.
+// returns:
+// 		[]Invoice: the invoice headers without lines.
+// 		int: total number of invoices matching the filter.
+// 		errors: if any occurred during the operation.
+func (r *InvoiceRepository) List(ctx context.Context, filter models.InvoiceFilter, limit, offset int) ([]*models.Invoice, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	where := sq.Eq{"inv.organization_id": orgID}
+	if filter.Status != "" {
+		where["inv.status"] = filter.Status
+	}
+	if filter.VendorID != "" {
+		where["inv.vendor_id"] = filter.VendorID
+	}
+	if filter.PurchaseOrderID != "" {
+		where["inv.purchase_order_id"] = filter.PurchaseOrderID
+	}
+	conditions := sq.And{where}
+	if filter.DueBy != nil {
+		conditions = append(conditions, sq.LtOrEq{"inv.due_date": *filter.DueBy})
+	}
+	order := "inv.created_at DESC"
+	switch {
+	case filter.ByDueDate:
+		order = "inv.due_date, inv.created_at"
+	case filter.OldestFirst:
+		order = "inv.created_at"
+	}
+
+	var count int
+	countQuery := r.SQLBuilder.Select("COUNT(*)").From("invoices inv").Where(conditions)
+	if err := countQuery.RunWith(r.db).QueryRowContext(ctx).Scan(&count); err != nil {
+		return nil, 0, translateError(err, "invoice")
+	}
+
+	query := r.SQLBuilder.
+		Select(invoiceColumns).
+		From("invoices inv").
+		Join("vendors v ON v.id = inv.vendor_id").
+		Join("purchase_orders po ON po.id = inv.purchase_order_id").
+		Where(conditions).
+		OrderBy(order).
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, 0, translateError(err, "invoice")
+	}
+	defer rows.Close()
+
+	var invoices []*models.Invoice
+	for rows.Next() {
+		invoice, err := r.scan(rows)
+		if err != nil {
+			return nil, 0, translateError(err, "invoice")
+		}
+		invoices = append(invoices, invoice)
+	}
+	return invoices, count, translateError(rows.Err(), "invoice")
+}
+
+// Method to get the InvoicedQuantities of the lines of a purchase order
+// Disputed invoices are left out, the vendor replaces them with a new invoice.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		purchaseOrderID: ID of the purchase order.
+// 		excludeID: ID of an invoice to leave out, empty for none.
+// returns:
+// 		map[string]float64: invoiced quantity keyed by purchase order line ID.
+// 		errors: if any occurred during the operation.
+func (r *InvoiceRepository) InvoicedQuantities(ctx context.Context, purchaseOrderID, excludeID string) (map[string]float64, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.invoicedQuantities(ctx, r.db, orgID, purchaseOrderID, excludeID)
+}
+
+// invoicedQuantities sums the invoiced quantity per purchase order line through runner, the database or a transaction
+func (r *InvoiceRepository) invoicedQuantities(ctx context.Context, runner sq.BaseRunner, orgID, purchaseOrderID, excludeID string) (map[string]float64, error) {
+	conditions := sq.And{
+		sq.Eq{"inv.purchase_order_id": purchaseOrderID, "inv.organization_id": orgID},
+		sq.NotEq{"inv.status": models.InvoiceStatusDisputed},
+	}
+	if excludeID != "" {
+		conditions = append(conditions, sq.NotEq{"inv.id": excludeID})
+	}
+	query := r.SQLBuilder.
+		Select("il.purchase_order_line_id", "SUM(il.quantity)").
+		From("invoice_lines il").
+		Join("invoices inv ON inv.id = il.invoice_id").
+		Where(conditions).
+		GroupBy("il.purchase_order_line_id")
+
+	rows, err := query.RunWith(runner).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "invoice")
+	}
+	defer rows.Close()
+
+	quantities := map[string]float64{}
+	for rows.Next() {
+		var lineID string
+		var quantity float64
+		if err := rows.Scan(&lineID, &quantity); err != nil {
+			return nil, translateError(err, "invoice")
+		}
+		quantities[lineID] = quantity
+	}
+	return quantities, translateError(rows.Err(), "invoice")
+}
+
+// Method to UpdateMatch of an Invoice
+// The match status, the matched values of every line and the new status are stored in one transaction.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		invoice: the invoice with its new match result, lines are matched by ID.
+// 		from: the status the invoice is expected to be in.
+// returns:
+// 		bool: false when the invoice is not in status `from` (anymore).
+// 		errors: if any occurred during the operation.
+func (r *InvoiceRepository) UpdateMatch(ctx context.Context, invoice *models.Invoice, from string) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := r.SQLBuilder.
+		Update("invoices").
+		Set("status", invoice.Status).
+		Set("match_status", invoice.MatchStatus).
+		Set("matched_at", invoice.MatchedAt).
+		Where(sq.Eq{"id": invoice.ID, "organization_id": orgID, "status": from}).
+		RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "invoice")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+	for _, line := range invoice.Lines {
+		query := r.SQLBuilder.
+			Update("invoice_lines").
+			Set("order_unit_price", line.OrderUnitPrice).
+			Set("order_tax_rate", line.OrderTaxRate).
+			Set("accepted_quantity", line.AcceptedQuantity).
+			Set("previously_invoiced", line.PreviouslyInvoiced).
+			Set("match_status", line.MatchStatus).
+			Set("match_exceptions", pq.Array(line.MatchExceptions)).
+			Where(sq.Eq{"id": line.ID, "invoice_id": invoice.ID})
+
+		if _, err := query.RunWith(tx).ExecContext(ctx); err != nil {
+			return false, translateError(err, "invoice")
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// Method to Review an Invoice
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the invoice.
+// 		from: the status the invoice is expected to be in.
+// 		review: the new status with the reviewer, time and note, approvals carry the payment schedule.
+// returns:
+// 		bool: false when the invoice is not in status `from` (anymore).
+// 		errors: if any occurred during the operation.
+func (r *InvoiceRepository) Review(ctx context.Context, id, from string, review *models.InvoiceReview) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	query := r.SQLBuilder.
+		Update("invoices").
+		Set("status", review.Status).
+		Set("reviewed_by", review.ReviewedBy).
+		Set("reviewed_at", review.ReviewedAt).
+		Set("review_note", review.Note).
+		Set("payment_terms", review.PaymentTerms).
+		Set("due_date", review.DueDate).
+		Set("discount_date", review.DiscountDate).
+		Set("discount_amount", review.DiscountAmount).
+		Where(sq.Eq{"id": id, "organization_id": orgID, "status": from})
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "invoice")
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// lines returns the lines of an invoice ordered by line number, joined with the PO line description and unit
+func (r *InvoiceRepository) lines(ctx context.Context, invoiceID string) ([]*models.InvoiceLine, error) {
+	query := r.SQLBuilder.
+		Select("il.id", "il.line_no", "il.purchase_order_line_id", "pol.description", "pol.unit", "il.quantity",
+			"il.unit_price", "il.amount", "il.tax_rate", "il.tax_amount", "il.order_unit_price", "il.order_tax_rate",
+			"il.accepted_quantity", "il.previously_invoiced", "il.match_status", "il.match_exceptions").
+		From("invoice_lines il").
+		Join("purchase_order_lines pol ON pol.id = il.purchase_order_line_id").
+		Where(sq.Eq{"il.invoice_id": invoiceID}).
+		OrderBy("il.line_no")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "invoice")
+	}
+	defer rows.Close()
+
+	var lines []*models.InvoiceLine
+	for rows.Next() {
+		var line models.InvoiceLine
+		err := rows.Scan(
+			&line.ID,
+			&line.LineNo,
+			&line.PurchaseOrderLineID,
+			&line.Description,
+			&line.Unit,
+			&line.Quantity,
+			&line.UnitPrice,
+			&line.Amount,
+			&line.TaxRate,
+			&line.TaxAmount,
+			&line.OrderUnitPrice,
+			&line.OrderTaxRate,
+			&line.AcceptedQuantity,
+			&line.PreviouslyInvoiced,
+			&line.MatchStatus,
+			pq.Array(&line.MatchExceptions),
+		)
+		if err != nil {
+			return nil, translateError(err, "invoice")
+		}
+		if line.MatchExceptions == nil {
+			line.MatchExceptions = []string{}
+		}
+		lines = append(lines, &line)
+	}
+	return lines, translateError(rows.Err(), "invoice")
+}
+
+// sameQuantities reports whether the previously invoiced quantities the lines were matched
+// against are still the quantities invoiced on the order
+func sameQuantities(invoice *models.Invoice, invoiced map[string]float64) bool {
+	for _, line := range invoice.Lines {
+		if math.Abs(invoiced[line.PurchaseOrderLineID]-line.PreviouslyInvoiced) > 0.0005 {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *InvoiceRepository) scan(row sq.RowScanner) (*models.Invoice, error) {
+	var invoice models.Invoice
+	err := row.Scan(
+		&invoice.ID,
+		&invoice.OrganizationID,
+		&invoice.VendorID,
+		&invoice.VendorName,
+		&invoice.PurchaseOrderID,
+		&invoice.PurchaseOrderNumber,
+		&invoice.InvoiceNumber,
+		&invoice.InvoiceDate,
+		&invoice.Status,
+		&invoice.MatchStatus,
+		&invoice.Subtotal,
+		&invoice.TaxAmount,
+		&invoice.TotalAmount,
+		&invoice.Notes,
+		&invoice.SubmittedBy,
+		&invoice.MatchedAt,
+		&invoice.ReviewedBy,
+		&invoice.ReviewedAt,
+		&invoice.ReviewNote,
+		&invoice.PaymentTerms,
+		&invoice.DueDate,
+		&invoice.DiscountDate,
+		&invoice.DiscountAmount,
+		&invoice.CreatedAt,
+		&invoice.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
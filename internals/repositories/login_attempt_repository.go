@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type LoginAttemptRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.LoginAttemptRepository = (*LoginAttemptRepository)(nil)
+
+// NewLoginAttemptRepository creates a new instance of LoginAttemptRepository with the provided database connection.
+func NewLoginAttemptRepository(db *sql.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Record a login attempt
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		attempt: the attempt, email must already be normalized.
+// returns:
+// 		errors: if any occurred during the operation.
+func (r *LoginAttemptRepository) Record(ctx context.Context, attempt *models.LoginAttempt) error {
+	query := r.SQLBuilder.
+		Insert("login_attempts").
+		Columns("email", "ip_address", "user_id", "outcome").
+		Values(attempt.Email, attempt.IPAddress, attempt.UserID, attempt.Outcome)
+
+	_, err := query.RunWith(r.db).ExecContext(ctx)
+	return translateError(err, "login_attempt")
+}
+
+// Method to Count the failed logins of an account
+// Only failures after the latest successful login count, a successful login resets the backoff.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		email: normalized email of the attempts.
+// 		since: start of the window.
+// returns:
+// 		LoginFailureStats: number of failures and the time of the last one.
+// 		errors: if any occurred during the operation.
+func (r *LoginAttemptRepository) AccountFailures(ctx context.Context, email string, since time.Time) (*models.LoginFailureStats, error) {
+	query := r.SQLBuilder.
+		Select("COUNT(*)", "MAX(created_at)").
+		From("login_attempts").
+		Where(sq.Eq{"email": email, "outcome": models.LoginOutcomeInvalidCredentials}).
+		Where(sq.GtOrEq{"created_at": since}).
+		Where(sq.Expr("created_at > COALESCE((SELECT MAX(created_at) FROM login_attempts WHERE email = ? AND outcome = ?), '-infinity')",
+			email, models.LoginOutcomeSuccess))
+
+	return r.scanStats(query.RunWith(r.db).QueryRowContext(ctx))
+}
+
+// Method to Count the failed logins from an IP address
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		ipAddress: client IP address.
+// 		since: start of the window.
+// returns:
+// 		LoginFailureStats: number of failures and the time of the last one.
+// 		errors: if any occurred during the operation.
+func (r *LoginAttemptRepository) IPFailures(ctx context.Context, ipAddress string, since time.Time) (*models.LoginFailureStats, error) {
+	query := r.SQLBuilder.
+		Select("COUNT(*)", "MAX(created_at)").
+		From("login_attempts").
+		Where(sq.Eq{"ip_address": ipAddress, "outcome": models.LoginOutcomeInvalidCredentials}).
+		Where(sq.GtOrEq{"created_at": since})
+
+	return r.scanStats(query.RunWith(r.db).QueryRowContext(ctx))
+}
+
+// Method to List login attempts for audit, newest first
+// Only attempts on accounts that are members of the organization of the request are listed.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		filter: optional email, IP address and outcome.
+// 		limit: maximum number of attempts to return.
+// 		offset: number of attempts to skip.
+// returns:
+// 		[]LoginAttempt: the attempts of the page.
+// 		int: total number of attempts matching the filter.
+// 		errors: if any occurred during the operation.
+func (r *LoginAttemptRepository) List(ctx context.Context, filter models.LoginAttemptFilter, limit, offset int) ([]*models.LoginAttempt, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	members := sq.Expr("user_id IN (SELECT user_id FROM organization_members WHERE organization_id = ?)", orgID)
+	conditions := sq.Eq{}
+	if filter.Email != "" {
+		conditions["email"] = filter.Email
+	}
+	if filter.IPAddress != "" {
+		conditions["ip_address"] = filter.IPAddress
+	}
+	if filter.Outcome != "" {
+		conditions["outcome"] = filter.Outcome
+	}
+
+	var count int
+	err = r.SQLBuilder.
+		Select("COUNT(*)").
+		From("login_attempts").
+		Where(conditions).
+		Where(members).
+		RunWith(r.db).QueryRowContext(ctx).Scan(&count)
+	if err != nil {
+		return nil, 0, translateError(err, "login_attempt")
+	}
+
+	rows, err := r.SQLBuilder.
+		Select("id", "email", "ip_address", "user_id", "outcome", "created_at").
+		From("login_attempts").
+		Where(conditions).
+		Where(members).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, 0, translateError(err, "login_attempt")
+	}
+	defer rows.Close()
+
+	var attempts []*models.LoginAttempt
+	for rows.Next() {
+		var attempt models.LoginAttempt
+		var userID sql.NullString
+		if err := rows.Scan(
+			&attempt.ID,
+			&attempt.Email,
+			&attempt.IPAddress,
+			&userID,
+			&attempt.Outcome,
+			&attempt.CreatedAt,
+		); err != nil {
+			return nil, 0, translateError(err, "login_attempt")
+		}
+		if userID.Valid {
+			attempt.UserID = &userID.String
+		}
+		attempts = append(attempts, &attempt)
+	}
+	return attempts, count, translateError(rows.Err(), "login_attempt")
+}
+
+func (r *LoginAttemptRepository) scanStats(row sq.RowScanner) (*models.LoginFailureStats, error) {
+	var stats models.LoginFailureStats
+	var last sql.NullTime
+	if err := row.Scan(&stats.Count, &last); err != nil {
+		return nil, translateError(err, "login_attempt")
+	}
+	stats.LastFailureAt = last.Time
+	return &stats, nil
+}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"sort"
+	"time"
+)
+
+type APIKeyRepository struct {
+	store *Store
+}
+
+var _ repository.APIKeyRepository = (*APIKeyRepository)(nil)
+
+// NewAPIKeyRepository creates an in-memory API key repository backed by the given store
+func NewAPIKeyRepository(store *Store) *APIKeyRepository {
+	return &APIKeyRepository{store: store}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) (*models.APIKey, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[key.UserID]; !ok {
+		return nil, invalidReference("api_key")
+	}
+	if _, ok := r.store.organizations[key.OrganizationID]; !ok {
+		return nil, invalidReference("api_key")
+	}
+	for _, existing := range r.store.apiKeys {
+		if existing.Prefix == key.Prefix {
+			return nil, conflict("api_key")
+		}
+	}
+
+	created := *key
+	created.ID = newID()
+	created.Scopes = append([]string(nil), key.Scopes...)
+	created.LastUsedAt = nil
+	created.RevokedAt = nil
+	created.CreatedAt = r.store.now()
+	r.store.apiKeys[created.ID] = &created
+
+	return copyAPIKey(&created), nil
+}
+
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, key := range r.store.apiKeys {
+		if key.Prefix == prefix {
+			return copyAPIKey(key), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *APIKeyRepository) GetByID(ctx context.Context, id string) (*models.APIKey, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	key, ok := r.store.apiKeys[id]
+	if !ok || key.OrganizationID != orgID {
+		return nil, nil
+	}
+	return copyAPIKey(key), nil
+}
+
+func (r *APIKeyRepository) ListByUser(ctx context.Context, userID string) ([]*models.APIKey, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var keys []*models.APIKey
+	for _, key := range r.store.apiKeys {
+		if key.UserID == userID && key.OrganizationID == orgID {
+			keys = append(keys, copyAPIKey(key))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, id string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key, ok := r.store.apiKeys[id]
+	if !ok || key.RevokedAt != nil {
+		return false, nil
+	}
+	now := r.store.now()
+	key.RevokedAt = &now
+	return true, nil
+}
+
+func (r *APIKeyRepository) Touch(ctx context.Context, id string, usedAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if key, ok := r.store.apiKeys[id]; ok {
+		key.LastUsedAt = &usedAt
+	}
+	return nil
+}
+
+func copyAPIKey(key *models.APIKey) *models.APIKey {
+	copied := *key
+	copied.Scopes = append([]string(nil), key.Scopes...)
+	return &copied
+}
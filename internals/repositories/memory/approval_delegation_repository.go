@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"sort"
+	"time"
+)
+
+type ApprovalDelegationRepository struct {
+	store *Store
+}
+
+var _ repository.ApprovalDelegationRepository = (*ApprovalDelegationRepository)(nil)
+
+// NewApprovalDelegationRepository creates an in-memory approval delegation repository backed by the given store
+func NewApprovalDelegationRepository(store *Store) *ApprovalDelegationRepository {
+	return &ApprovalDelegationRepository{store: store}
+}
+
+func (r *ApprovalDelegationRepository) Create(ctx context.Context, delegation *models.ApprovalDelegation) (*models.ApprovalDelegation, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// mirror approval_delegations_period_check and approval_delegations_self_check
+	if !delegation.EndsAt.After(delegation.StartsAt) || delegation.DelegatorID == delegation.DelegateID {
+		return nil, invalid("approval_delegation")
+	}
+	// mirror approval_delegations_delegator_fkey and approval_delegations_delegate_fkey
+	if _, ok := r.store.members[orgID][delegation.DelegatorID]; !ok {
+		return nil, invalidReference("approval_delegation")
+	}
+	if _, ok := r.store.members[orgID][delegation.DelegateID]; !ok {
+		return nil, invalidReference("approval_delegation")
+	}
+
+	created := *delegation
+	created.ID = newID()
+	created.OrganizationID = orgID
+	created.CreatedAt = r.store.now()
+	r.store.approvalDelegations[created.ID] = &created
+
+	copied := created
+	return &copied, nil
+}
+
+func (r *ApprovalDelegationRepository) List(ctx context.Context, userID string) ([]*models.ApprovalDelegation, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	delegations := []*models.ApprovalDelegation{}
+	for _, delegation := range r.store.approvalDelegations {
+		if delegation.OrganizationID != orgID || (delegation.DelegatorID != userID && delegation.DelegateID != userID) {
+			continue
+		}
+		copied := *delegation
+		delegations = append(delegations, &copied)
+	}
+	sort.Slice(delegations, func(i, j int) bool { return delegations[i].CreatedAt.After(delegations[j].CreatedAt) })
+	return delegations, nil
+}
+
+func (r *ApprovalDelegationRepository) Delete(ctx context.Context, id, delegatorID string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delegation, ok := r.store.approvalDelegations[id]
+	if !ok || delegation.OrganizationID != orgID || delegation.DelegatorID != delegatorID {
+		return notFound("approval_delegation")
+	}
+	delete(r.store.approvalDelegations, id)
+	return nil
+}
+
+func (r *ApprovalDelegationRepository) ActiveDelegators(ctx context.Context, delegateID string, at time.Time) ([]string, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	seen := map[string]bool{}
+	var delegators []string
+	for _, delegation := range r.store.approvalDelegations {
+		if delegation.OrganizationID != orgID || delegation.DelegateID != delegateID ||
+			at.Before(delegation.StartsAt) || !at.Before(delegation.EndsAt) || seen[delegation.DelegatorID] {
+			continue
+		}
+		seen[delegation.DelegatorID] = true
+		delegators = append(delegators, delegation.DelegatorID)
+	}
+	return delegators, nil
+}
@@ -0,0 +1,300 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"sort"
+)
+
+type ApprovalRepository struct {
+	store *Store
+}
+
+var _ repository.ApprovalRepository = (*ApprovalRepository)(nil)
+
+// NewApprovalRepository creates an in-memory approval request repository backed by the given store
+func NewApprovalRepository(store *Store) *ApprovalRepository {
+	return &ApprovalRepository{store: store}
+}
+
+func (r *ApprovalRepository) Create(ctx context.Context, request *models.ApprovalRequest) (*models.ApprovalRequest, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// mirror approval_requests_pending_document_key
+	if request.Status == models.ApprovalStatusPending && r.pending(orgID, request.DocumentType, request.DocumentID) != nil {
+		return nil, conflict("approval")
+	}
+	// mirror the user references of the request and its steps
+	if _, ok := r.store.users[request.RequestedBy]; !ok {
+		return nil, invalidReference("approval")
+	}
+	for _, step := range request.Steps {
+		if step.ApproverUserID == nil && step.ApproverRole == "" {
+			return nil, invalid("approval")
+		}
+		if step.ApproverUserID != nil {
+			if _, ok := r.store.users[*step.ApproverUserID]; !ok {
+				return nil, invalidReference("approval")
+			}
+		}
+	}
+
+	now := r.store.now()
+	created := copyApproval(request)
+	created.ID = newID()
+	created.OrganizationID = orgID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	for _, step := range created.Steps {
+		step.ID = newID()
+	}
+	sortApprovalSteps(created.Steps)
+	r.store.approvals[created.ID] = created
+
+	return copyApproval(created), nil
+}
+
+func (r *ApprovalRepository) GetByID(ctx context.Context, id string) (*models.ApprovalRequest, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	request, ok := r.store.approvals[id]
+	if !ok || request.OrganizationID != orgID {
+		return nil, nil
+	}
+	return copyApproval(request), nil
+}
+
+func (r *ApprovalRepository) GetPending(ctx context.Context, documentType, documentID string) (*models.ApprovalRequest, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	request := r.pending(orgID, documentType, documentID)
+	if request == nil {
+		return nil, nil
+	}
+	return copyApproval(request), nil
+}
+
+func (r *ApprovalRepository) List(ctx context.Context, filter models.ApprovalFilter, limit, offset int) ([]*models.ApprovalRequest, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var requests []*models.ApprovalRequest
+	for _, request := range r.store.approvals {
+		if request.OrganizationID != orgID ||
+			(filter.DocumentType != "" && request.DocumentType != filter.DocumentType) ||
+			(filter.DocumentID != "" && request.DocumentID != filter.DocumentID) ||
+			(filter.Status != "" && request.Status != filter.Status) {
+			continue
+		}
+		requests = append(requests, copyApproval(request))
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].CreatedAt.After(requests[j].CreatedAt) })
+	return paginate(requests, limit, offset), len(requests), nil
+}
+
+func (r *ApprovalRepository) ListPending(ctx context.Context, filter models.PendingApprovalFilter, limit, offset int) ([]*models.PendingApproval, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	userIDs := make(map[string]bool, len(filter.UserIDs))
+	for _, userID := range filter.UserIDs {
+		userIDs[userID] = true
+	}
+
+	var pending []*models.PendingApproval
+	for _, request := range r.store.approvals {
+		if request.OrganizationID != orgID || request.Status != models.ApprovalStatusPending ||
+			(filter.ExcludeRequester != "" && request.RequestedBy == filter.ExcludeRequester) {
+			continue
+		}
+		for _, step := range request.Steps {
+			if step.Status != models.ApprovalStepPending {
+				continue
+			}
+			if filter.ExcludeActor != "" && decidedBy(request, filter.ExcludeActor, step.ApproverUserID) {
+				continue
+			}
+			if (step.ApproverUserID != nil && userIDs[*step.ApproverUserID]) ||
+				(step.ApproverUserID == nil && step.ApproverRole == filter.Role) ||
+				delegatedRoleStep(request, step, filter.DelegatedRoles) {
+				header := copyApproval(request)
+				header.Steps = nil
+				stepCopy := copyApprovalStep(step)
+				pending = append(pending, &models.PendingApproval{Request: header, Step: stepCopy})
+			}
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		if !pending[i].Request.CreatedAt.Equal(pending[j].Request.CreatedAt) {
+			return pending[i].Request.CreatedAt.Before(pending[j].Request.CreatedAt)
+		}
+		if pending[i].Step.Stage != pending[j].Step.Stage {
+			return pending[i].Step.Stage < pending[j].Step.Stage
+		}
+		return pending[i].Step.Name < pending[j].Step.Name
+	})
+	return paginate(pending, limit, offset), len(pending), nil
+}
+
+// delegatedRoleStep reports whether step is for the role of a delegator who may still act on the request
+func delegatedRoleStep(request *models.ApprovalRequest, step *models.ApprovalStep, delegated []models.DelegatedRole) bool {
+	if step.ApproverUserID != nil {
+		return false
+	}
+	for _, role := range delegated {
+		if step.ApproverRole == role.Role && request.RequestedBy != role.DelegatorID && !decidedBy(request, role.DelegatorID, nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// decidedBy reports whether the user, or the approver the step is assigned to, decided a step of the request
+func decidedBy(request *models.ApprovalRequest, userID string, approverUserID *string) bool {
+	for _, step := range request.Steps {
+		for _, actor := range []*string{step.ActedBy, step.OnBehalfOf} {
+			if actor != nil && (*actor == userID || (approverUserID != nil && *actor == *approverUserID)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *ApprovalRepository) Decide(ctx context.Context, stepID string, decision *models.ApprovalStepDecision) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, request := range r.store.approvals {
+		if request.OrganizationID != orgID {
+			continue
+		}
+		for _, step := range request.Steps {
+			if step.ID != stepID {
+				continue
+			}
+			if step.Status != models.ApprovalStepPending || decidedBy(request, decision.ActedBy, decision.OnBehalfOf) {
+				return false, nil
+			}
+			actedBy := decision.ActedBy
+			actedAt := decision.ActedAt
+			step.Status = decision.Status
+			step.ActedBy = &actedBy
+			step.OnBehalfOf = copyString(decision.OnBehalfOf)
+			step.Comment = decision.Comment
+			step.ActedAt = &actedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *ApprovalRepository) Advance(ctx context.Context, requestID string, fromStage int, transition *models.ApprovalTransition) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	request, ok := r.store.approvals[requestID]
+	if !ok || request.OrganizationID != orgID ||
+		request.Status != models.ApprovalStatusPending || request.CurrentStage != fromStage {
+		return false, nil
+	}
+	request.Status = transition.Status
+	request.CurrentStage = transition.Stage
+	if transition.CompletedAt != nil {
+		completedAt := *transition.CompletedAt
+		request.CompletedAt = &completedAt
+	}
+	request.UpdatedAt = r.store.now()
+
+	for _, step := range request.Steps {
+		switch {
+		case transition.Status == models.ApprovalStatusPending:
+			if step.Stage == transition.Stage && step.Status == models.ApprovalStepWaiting {
+				step.Status = models.ApprovalStepPending
+			}
+		case step.Status == models.ApprovalStepWaiting || step.Status == models.ApprovalStepPending:
+			step.Status = models.ApprovalStepSkipped
+		}
+	}
+	return true, nil
+}
+
+// pending returns the pending request of a document, the caller holds the lock
+func (r *ApprovalRepository) pending(orgID, documentType, documentID string) *models.ApprovalRequest {
+	for _, request := range r.store.approvals {
+		if request.OrganizationID == orgID && request.DocumentType == documentType &&
+			request.DocumentID == documentID && request.Status == models.ApprovalStatusPending {
+			return request
+		}
+	}
+	return nil
+}
+
+// sortApprovalSteps orders steps by stage then name like the postgres queries
+func sortApprovalSteps(steps []*models.ApprovalStep) {
+	sort.SliceStable(steps, func(i, j int) bool {
+		if steps[i].Stage != steps[j].Stage {
+			return steps[i].Stage < steps[j].Stage
+		}
+		return steps[i].Name < steps[j].Name
+	})
+}
+
+func copyApproval(request *models.ApprovalRequest) *models.ApprovalRequest {
+	copied := *request
+	copied.DepartmentID = copyString(request.DepartmentID)
+	if request.CompletedAt != nil {
+		completedAt := *request.CompletedAt
+		copied.CompletedAt = &completedAt
+	}
+	copied.Steps = make([]*models.ApprovalStep, 0, len(request.Steps))
+	for _, step := range request.Steps {
+		copied.Steps = append(copied.Steps, copyApprovalStep(step))
+	}
+	return &copied
+}
+
+func copyApprovalStep(step *models.ApprovalStep) *models.ApprovalStep {
+	copied := *step
+	copied.RuleID = copyString(step.RuleID)
+	copied.ApproverUserID = copyString(step.ApproverUserID)
+	copied.ActedBy = copyString(step.ActedBy)
+	copied.OnBehalfOf = copyString(step.OnBehalfOf)
+	if step.ActedAt != nil {
+		actedAt := *step.ActedAt
+		copied.ActedAt = &actedAt
+	}
+	return &copied
+}
@@ -0,0 +1,195 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"sort"
+)
+
+type ApprovalRuleRepository struct {
+	store *Store
+}
+
+var _ repository.ApprovalRuleRepository = (*ApprovalRuleRepository)(nil)
+
+// NewApprovalRuleRepository creates an in-memory approval rule repository backed by the given store
+func NewApprovalRuleRepository(store *Store) *ApprovalRuleRepository {
+	return &ApprovalRuleRepository{store: store}
+}
+
+// check mirrors the constraints of approval_rules and its steps, the caller holds the lock
+func (r *ApprovalRuleRepository) check(orgID string, rule *models.ApprovalRule) error {
+	if rule.AmountAbove != nil && rule.AmountUpTo != nil && *rule.AmountUpTo <= *rule.AmountAbove {
+		return invalid("approval_rule")
+	}
+	if rule.CategoryID != nil {
+		if _, ok := r.store.categories[*rule.CategoryID]; !ok {
+			return invalidReference("approval_rule")
+		}
+	}
+	if rule.DepartmentID != nil {
+		department, ok := r.store.departments[*rule.DepartmentID]
+		if !ok || department.OrganizationID != orgID {
+			return invalidReference("approval_rule")
+		}
+	}
+	for _, step := range rule.Steps {
+		if step.ApproverUserID == nil {
+			continue
+		}
+		if _, ok := r.store.users[*step.ApproverUserID]; !ok {
+			return invalidReference("approval_rule")
+		}
+	}
+	return nil
+}
+
+func (r *ApprovalRuleRepository) Create(ctx context.Context, rule *models.ApprovalRule) (*models.ApprovalRule, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.check(orgID, rule); err != nil {
+		return nil, err
+	}
+
+	now := r.store.now()
+	created := copyApprovalRule(rule)
+	created.ID = newID()
+	created.OrganizationID = orgID
+	created.Steps = newRuleSteps(rule.Steps)
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	r.store.approvalRules[created.ID] = created
+
+	return copyApprovalRule(created), nil
+}
+
+func (r *ApprovalRuleRepository) GetByID(ctx context.Context, id string) (*models.ApprovalRule, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rule, ok := r.store.approvalRules[id]
+	if !ok || rule.OrganizationID != orgID {
+		return nil, nil
+	}
+	return copyApprovalRule(rule), nil
+}
+
+func (r *ApprovalRuleRepository) List(ctx context.Context, documentType string) ([]*models.ApprovalRule, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rules := []*models.ApprovalRule{}
+	for _, rule := range r.store.approvalRules {
+		if rule.OrganizationID != orgID || (documentType != "" && rule.DocumentType != documentType) {
+			continue
+		}
+		rules = append(rules, copyApprovalRule(rule))
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority < rules[j].Priority
+		}
+		return rules[i].Name < rules[j].Name
+	})
+	return rules, nil
+}
+
+func (r *ApprovalRuleRepository) Update(ctx context.Context, rule *models.ApprovalRule) (*models.ApprovalRule, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.approvalRules[rule.ID]
+	if !ok || existing.OrganizationID != orgID {
+		return nil, notFound("approval_rule")
+	}
+	if err := r.check(orgID, rule); err != nil {
+		return nil, err
+	}
+	existing.DocumentType = rule.DocumentType
+	existing.Name = rule.Name
+	existing.Priority = rule.Priority
+	existing.AmountAbove = copyFloat(rule.AmountAbove)
+	existing.AmountUpTo = copyFloat(rule.AmountUpTo)
+	existing.CategoryID = copyString(rule.CategoryID)
+	existing.DepartmentID = copyString(rule.DepartmentID)
+	existing.Active = rule.Active
+	existing.Steps = newRuleSteps(rule.Steps)
+	existing.UpdatedAt = r.store.now()
+
+	return copyApprovalRule(existing), nil
+}
+
+func (r *ApprovalRuleRepository) Delete(ctx context.Context, id string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	rule, ok := r.store.approvalRules[id]
+	if !ok || rule.OrganizationID != orgID {
+		return notFound("approval_rule")
+	}
+	delete(r.store.approvalRules, id)
+	// mirror ON DELETE SET NULL on approval_request_steps.rule_id
+	for _, request := range r.store.approvals {
+		for _, step := range request.Steps {
+			if step.RuleID != nil && *step.RuleID == id {
+				step.RuleID = nil
+			}
+		}
+	}
+	return nil
+}
+
+// newRuleSteps copies the steps with new IDs, ordered by level like the postgres query
+func newRuleSteps(steps []*models.ApprovalRuleStep) []*models.ApprovalRuleStep {
+	copied := make([]*models.ApprovalRuleStep, 0, len(steps))
+	for _, step := range steps {
+		stepCopy := *step
+		stepCopy.ID = newID()
+		stepCopy.ApproverUserID = copyString(step.ApproverUserID)
+		copied = append(copied, &stepCopy)
+	}
+	sort.SliceStable(copied, func(i, j int) bool {
+		if copied[i].Level != copied[j].Level {
+			return copied[i].Level < copied[j].Level
+		}
+		return copied[i].Name < copied[j].Name
+	})
+	return copied
+}
+
+func copyApprovalRule(rule *models.ApprovalRule) *models.ApprovalRule {
+	copied := *rule
+	copied.AmountAbove = copyFloat(rule.AmountAbove)
+	copied.AmountUpTo = copyFloat(rule.AmountUpTo)
+	copied.CategoryID = copyString(rule.CategoryID)
+	copied.DepartmentID = copyString(rule.DepartmentID)
+	copied.Steps = make([]*models.ApprovalRuleStep, 0, len(rule.Steps))
+	for _, step := range rule.Steps {
+		stepCopy := *step
+		stepCopy.ApproverUserID = copyString(step.ApproverUserID)
+		copied.Steps = append(copied.Steps, &stepCopy)
+	}
+	return &copied
+}
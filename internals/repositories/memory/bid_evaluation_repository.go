@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+)
+
+type BidEvaluationRepository struct {
+	store *Store
+}
+
+var _ repository.BidEvaluationRepository = (*BidEvaluationRepository)(nil)
+
+// NewBidEvaluationRepository creates an in-memory bid evaluation repository backed by the given store
+func NewBidEvaluationRepository(store *Store) *BidEvaluationRepository {
+	return &BidEvaluationRepository{store: store}
+}
+
+func (r *BidEvaluationRepository) GetCriteria(ctx context.Context, rfqID string) (*models.EvaluationCriteria, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rfq, ok := r.store.rfqs[rfqID]
+	criteria, found := r.store.evaluationCriteria[rfqID]
+	if !ok || rfq.OrganizationID != orgID || !found {
+		return nil, nil
+	}
+	copied := *criteria
+	return &copied, nil
+}
+
+func (r *BidEvaluationRepository) SaveCriteria(ctx context.Context, criteria *models.EvaluationCriteria) (*models.EvaluationCriteria, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if rfq, ok := r.store.rfqs[criteria.RFQID]; !ok || rfq.OrganizationID != orgID {
+		return nil, notFound("evaluation_criteria")
+	}
+	// mirror rfq_evaluation_criteria_weights_check
+	if criteria.PriceWeight+criteria.LeadTimeWeight+criteria.RatingWeight+criteria.TechnicalWeight != 100 {
+		return nil, invalid("evaluation_criteria")
+	}
+	saved := *criteria
+	saved.UpdatedAt = r.store.now()
+	r.store.evaluationCriteria[saved.RFQID] = &saved
+
+	copied := saved
+	return &copied, nil
+}
+
+func (r *BidEvaluationRepository) SaveScore(ctx context.Context, score *models.QuotationScore) (*models.QuotationScore, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if quotation, ok := r.store.quotations[score.QuotationID]; !ok || quotation.OrganizationID != orgID {
+		return nil, notFound("quotation_score")
+	}
+	if score.ScoredBy != nil {
+		if _, ok := r.store.users[*score.ScoredBy]; !ok {
+			return nil, invalidReference("quotation_score")
+		}
+	}
+	saved := *score
+	saved.ScoredBy = copyString(score.ScoredBy)
+	saved.ScoredAt = r.store.now()
+	r.store.quotationScores[saved.QuotationID] = &saved
+
+	copied := saved
+	copied.ScoredBy = copyString(saved.ScoredBy)
+	return &copied, nil
+}
+
+func (r *BidEvaluationRepository) ListScores(ctx context.Context, rfqID string) ([]*models.QuotationScore, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	scores := []*models.QuotationScore{}
+	for quotationID, score := range r.store.quotationScores {
+		quotation, ok := r.store.quotations[quotationID]
+		if !ok || quotation.OrganizationID != orgID || quotation.RFQID != rfqID {
+			continue
+		}
+		copied := *score
+		copied.ScoredBy = copyString(score.ScoredBy)
+		scores = append(scores, &copied)
+	}
+	return scores, nil
+}
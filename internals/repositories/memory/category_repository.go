@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"sort"
+)
+
+type CategoryRepository struct {
+	store *Store
+}
+
+var _ repository.CategoryRepository = (*CategoryRepository)(nil)
+
+// NewCategoryRepository creates an in-memory category repository backed by the given store
+func NewCategoryRepository(store *Store) *CategoryRepository {
+	return &CategoryRepository{store: store}
+}
+
+// get returns the category when it belongs to the organization, the caller holds the lock
+func (c *CategoryRepository) get(orgID, id string) (*models.Category, bool) {
+	category, ok := c.store.categories[id]
+	if !ok || category.OrganizationID != orgID {
+		return nil, false
+	}
+	return category, true
+}
+
+func (c *CategoryRepository) CreateCategory(ctx context.Context, category *models.CreateCategoryRequest) (*models.Category, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	for _, existing := range c.store.categories {
+		if existing.OrganizationID == orgID && existing.Name == category.Name {
+			return nil, conflict("category")
+		}
+	}
+
+	now := c.store.now()
+	created := &models.Category{
+		ID:             newID(),
+		Name:           category.Name,
+		Description:    category.Description,
+		OrganizationID: orgID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	c.store.categories[created.ID] = created
+
+	copied := *created
+	return &copied, nil
+}
+
+func (c *CategoryRepository) GetAllCategories(ctx context.Context, limit, offset int) ([]*models.Category, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	categories := make([]*models.Category, 0, len(c.store.categories))
+	for _, category := range c.store.categories {
+		if category.OrganizationID != orgID {
+			continue
+		}
+		copied := *category
+		categories = append(categories, &copied)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].CreatedAt.Before(categories[j].CreatedAt) })
+	return paginate(categories, limit, offset), nil
+}
+
+func (c *CategoryRepository) GetCategoryByID(ctx context.Context, id string) (*models.Category, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	category, ok := c.get(orgID, id)
+	if !ok {
+		return nil, nil
+	}
+	copied := *category
+	return &copied, nil
+}
+
+func (c *CategoryRepository) UpdateCategory(ctx context.Context, id string, category *models.UpdateCategoryRequest) (*models.Category, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	existing, ok := c.get(orgID, id)
+	if !ok {
+		return nil, nil
+	}
+	for _, other := range c.store.categories {
+		if other.ID != id && other.OrganizationID == orgID && other.Name == category.Name {
+			return nil, conflict("category")
+		}
+	}
+	existing.Name = category.Name
+	existing.Description = category.Description
+	existing.UpdatedAt = c.store.now()
+
+	copied := *existing
+	return &copied, nil
+}
+
+func (c *CategoryRepository) DeleteCategory(ctx context.Context, id string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	if _, ok := c.get(orgID, id); !ok {
+		return notFound("category")
+	}
+	// mirror ON DELETE RESTRICT on products.product_category and approval_rules.category_id
+	for _, product := range c.store.products {
+		if product.ProductCategoryID == id {
+			return invalidReference("category")
+		}
+	}
+	for _, rule := range c.store.approvalRules {
+		if rule.CategoryID != nil && *rule.CategoryID == id {
+			return invalidReference("category")
+		}
+	}
+	delete(c.store.categories, id)
+	return nil
+}
+
+func (c *CategoryRepository) CountAllCategories(ctx context.Context) (int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	count := 0
+	for _, category := range c.store.categories {
+		if category.OrganizationID == orgID {
+			count++
+		}
+	}
+	return count, nil
+}
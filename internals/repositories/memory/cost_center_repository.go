@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"sort"
+)
+
+type CostCenterRepository struct {
+	store *Store
+}
+
+var _ repository.CostCenterRepository = (*CostCenterRepository)(nil)
+
+// NewCostCenterRepository creates an in-memory cost center repository backed by the given store
+func NewCostCenterRepository(store *Store) *CostCenterRepository {
+	return &CostCenterRepository{store: store}
+}
+
+// get returns the cost center when it belongs to the organization, the caller holds the lock
+func (r *CostCenterRepository) get(orgID, id string) (*models.CostCenter, bool) {
+	costCenter, ok := r.store.costCenters[id]
+	if !ok || costCenter.OrganizationID != orgID {
+		return nil, false
+	}
+	return costCenter, true
+}
+
+// codeTaken mirrors cost_centers_organization_code_key, the caller holds the lock
+func (r *CostCenterRepository) codeTaken(orgID, id, code string) bool {
+	for _, other := range r.store.costCenters {
+		if other.ID != id && other.OrganizationID == orgID && other.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *CostCenterRepository) Create(ctx context.Context, costCenter *models.CreateCostCenterRequest) (*models.CostCenter, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.codeTaken(orgID, "", costCenter.Code) {
+		return nil, conflict("cost_center")
+	}
+
+	now := r.store.now()
+	created := &models.CostCenter{
+		ID:             newID(),
+		OrganizationID: orgID,
+		Code:           costCenter.Code,
+		Name:           costCenter.Name,
+		Description:    costCenter.Description,
+		Active:         true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	r.store.costCenters[created.ID] = created
+
+	copied := *created
+	return &copied, nil
+}
+
+func (r *CostCenterRepository) GetByID(ctx context.Context, id string) (*models.CostCenter, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	costCenter, ok := r.get(orgID, id)
+	if !ok {
+		return nil, nil
+	}
+	copied := *costCenter
+	return &copied, nil
+}
+
+func (r *CostCenterRepository) List(ctx context.Context, limit, offset int) ([]*models.CostCenter, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var costCenters []*models.CostCenter
+	for _, costCenter := range r.store.costCenters {
+		if costCenter.OrganizationID != orgID {
+			continue
+		}
+		copied := *costCenter
+		costCenters = append(costCenters, &copied)
+	}
+	sort.Slice(costCenters, func(i, j int) bool { return costCenters[i].Code < costCenters[j].Code })
+	return paginate(costCenters, limit, offset), nil
+}
+
+func (r *CostCenterRepository) Count(ctx context.Context) (int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count := 0
+	for _, costCenter := range r.store.costCenters {
+		if costCenter.OrganizationID == orgID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *CostCenterRepository) Update(ctx context.Context, id string, costCenter *models.UpdateCostCenterRequest) (*models.CostCenter, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.get(orgID, id)
+	if !ok {
+		return nil, notFound("cost_center")
+	}
+	if r.codeTaken(orgID, id, costCenter.Code) {
+		return nil, conflict("cost_center")
+	}
+	existing.Code = costCenter.Code
+	existing.Name = costCenter.Name
+	existing.Description = costCenter.Description
+	if costCenter.Active != nil {
+		existing.Active = *costCenter.Active
+	}
+	existing.UpdatedAt = r.store.now()
+
+	copied := *existing
+	return &copied, nil
+}
+
+func (r *CostCenterRepository) Delete(ctx context.Context, id string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.get(orgID, id); !ok {
+		return notFound("cost_center")
+	}
+	// mirror the cost center references of departments, members and requisitions
+	for _, department := range r.store.departments {
+		if department.DefaultCostCenterID != nil && *department.DefaultCostCenterID == id {
+			return invalidReference("cost_center")
+		}
+	}
+	for _, m := range r.store.members[orgID] {
+		if m.costCenterID != nil && *m.costCenterID == id {
+			return invalidReference("cost_center")
+		}
+	}
+	for _, requisition := range r.store.requisitions {
+		if requisition.CostCenterID != nil && *requisition.CostCenterID == id {
+			return invalidReference("cost_center")
+		}
+	}
+	delete(r.store.costCenters, id)
+	return nil
+}
@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"sort"
+)
+
+type DepartmentRepository struct {
+	store *Store
+}
+
+var _ repository.DepartmentRepository = (*DepartmentRepository)(nil)
+
+// NewDepartmentRepository creates an in-memory department repository backed by the given store
+func NewDepartmentRepository(store *Store) *DepartmentRepository {
+	return &DepartmentRepository{store: store}
+}
+
+// get returns the department when it belongs to the organization, the caller holds the lock
+func (r *DepartmentRepository) get(orgID, id string) (*models.Department, bool) {
+	department, ok := r.store.departments[id]
+	if !ok || department.OrganizationID != orgID {
+		return nil, false
+	}
+	return department, true
+}
+
+// check mirrors the unique code and the foreign keys of the departments table, the caller holds the lock
+func (r *DepartmentRepository) check(orgID, id string, department *models.DepartmentRequest) error {
+	for _, other := range r.store.departments {
+		if other.ID != id && other.OrganizationID == orgID && other.Code == department.Code {
+			return conflict("department")
+		}
+	}
+	if department.ParentID != nil {
+		if _, ok := r.get(orgID, *department.ParentID); !ok {
+			return invalidReference("department")
+		}
+		if *department.ParentID == id {
+			return invalid("department")
+		}
+	}
+	if department.ManagerID != nil {
+		if _, ok := r.store.members[orgID][*department.ManagerID]; !ok {
+			return invalidReference("department")
+		}
+	}
+	if department.DefaultCostCenterID != nil {
+		costCenter, ok := r.store.costCenters[*department.DefaultCostCenterID]
+		if !ok || costCenter.OrganizationID != orgID {
+			return invalidReference("department")
+		}
+	}
+	return nil
+}
+
+func (r *DepartmentRepository) Create(ctx context.Context, department *models.DepartmentRequest) (*models.Department, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.check(orgID, "", department); err != nil {
+		return nil, err
+	}
+
+	now := r.store.now()
+	created := &models.Department{
+		ID:                  newID(),
+		OrganizationID:      orgID,
+		Code:                department.Code,
+		Name:                department.Name,
+		ParentID:            copyString(department.ParentID),
+		ManagerID:           copyString(department.ManagerID),
+		DefaultCostCenterID: copyString(department.DefaultCostCenterID),
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	r.store.departments[created.ID] = created
+
+	return copyDepartment(created), nil
+}
+
+func (r *DepartmentRepository) GetByID(ctx context.Context, id string) (*models.Department, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	department, ok := r.get(orgID, id)
+	if !ok {
+		return nil, nil
+	}
+	return copyDepartment(department), nil
+}
+
+func (r *DepartmentRepository) List(ctx context.Context) ([]*models.Department, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var departments []*models.Department
+	for _, department := range r.store.departments {
+		if department.OrganizationID == orgID {
+			departments = append(departments, copyDepartment(department))
+		}
+	}
+	sort.Slice(departments, func(i, j int) bool { return departments[i].Code < departments[j].Code })
+	return departments, nil
+}
+
+func (r *DepartmentRepository) Update(ctx context.Context, id string, department *models.DepartmentRequest) (*models.Department, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.get(orgID, id)
+	if !ok {
+		return nil, notFound("department")
+	}
+	if err := r.check(orgID, id, department); err != nil {
+		return nil, err
+	}
+	existing.Code = department.Code
+	existing.Name = department.Name
+	existing.ParentID = copyString(department.ParentID)
+	existing.ManagerID = copyString(department.ManagerID)
+	existing.DefaultCostCenterID = copyString(department.DefaultCostCenterID)
+	existing.UpdatedAt = r.store.now()
+
+	return copyDepartment(existing), nil
+}
+
+func (r *DepartmentRepository) Delete(ctx context.Context, id string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.get(orgID, id); !ok {
+		return notFound("department")
+	}
+	// mirror departments_parent_fkey and the department references of members, requisitions and approval rules
+	for _, child := range r.store.departments {
+		if child.ParentID != nil && *child.ParentID == id {
+			return invalidReference("department")
+		}
+	}
+	for _, m := range r.store.members[orgID] {
+		if m.departmentID != nil && *m.departmentID == id {
+			return invalidReference("department")
+		}
+	}
+	for _, requisition := range r.store.requisitions {
+		if requisition.DepartmentID != nil && *requisition.DepartmentID == id {
+			return invalidReference("department")
+		}
+	}
+	for _, rule := range r.store.approvalRules {
+		if rule.DepartmentID != nil && *rule.DepartmentID == id {
+			return invalidReference("department")
+		}
+	}
+	delete(r.store.departments, id)
+	return nil
+}
+
+func (r *DepartmentRepository) GetAssignment(ctx context.Context, userID string) (*models.DepartmentAssignment, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	m, ok := r.store.members[orgID][userID]
+	if !ok {
+		return nil, nil
+	}
+	return &models.DepartmentAssignment{
+		UserID:       userID,
+		DepartmentID: copyString(m.departmentID),
+		CostCenterID: copyString(m.costCenterID),
+	}, nil
+}
+
+func (r *DepartmentRepository) Assign(ctx context.Context, assignment *models.DepartmentAssignment) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	m, ok := r.store.members[orgID][assignment.UserID]
+	if !ok {
+		return notFound("organization_member")
+	}
+	if assignment.DepartmentID != nil {
+		if _, ok := r.get(orgID, *assignment.DepartmentID); !ok {
+			return invalidReference("organization_member")
+		}
+	}
+	if assignment.CostCenterID != nil {
+		costCenter, ok := r.store.costCenters[*assignment.CostCenterID]
+		if !ok || costCenter.OrganizationID != orgID {
+			return invalidReference("organization_member")
+		}
+	}
+	m.departmentID = copyString(assignment.DepartmentID)
+	m.costCenterID = copyString(assignment.CostCenterID)
+	return nil
+}
+
+func copyDepartment(department *models.Department) *models.Department {
+	copied := *department
+	copied.ParentID = copyString(department.ParentID)
+	copied.ManagerID = copyString(department.ManagerID)
+	copied.DefaultCostCenterID = copyString(department.DefaultCostCenterID)
+	return &copied
+}
@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"sort"
+)
+
+type GoodsReceiptRepository struct {
+	store *Store
+}
+
+var _ repository.GoodsReceiptRepository = (*GoodsReceiptRepository)(nil)
+
+// NewGoodsReceiptRepository creates an in-memory goods receipt repository backed by the given store
+func NewGoodsReceiptRepository(store *Store) *GoodsReceiptRepository {
+	return &GoodsReceiptRepository{store: store}
+}
+
+func (r *GoodsReceiptRepository) Create(ctx context.Context, receipt *models.GoodsReceipt, order *models.PurchaseOrder, transition *models.PurchaseOrderTransition) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.purchaseOrders[order.ID]
+	if !ok || existing.OrganizationID != orgID || existing.Status != order.Status || !existing.UpdatedAt.Equal(order.UpdatedAt) {
+		return false, nil
+	}
+	// mirror the foreign keys of goods_receipts and goods_receipt_lines
+	if _, ok := r.store.users[receipt.ReceivedBy]; !ok {
+		return false, invalidReference("goods_receipt")
+	}
+	lines := make(map[string]bool, len(existing.Lines))
+	for _, line := range existing.Lines {
+		lines[line.ID] = true
+	}
+	for _, line := range receipt.Lines {
+		if !lines[line.PurchaseOrderLineID] {
+			return false, invalidReference("goods_receipt")
+		}
+	}
+
+	created := copyGoodsReceipt(receipt)
+	created.ID = newID()
+	created.OrganizationID = orgID
+	created.PurchaseOrderID = order.ID
+	created.CreatedAt = r.store.now()
+	for _, line := range created.Lines {
+		line.ID = newID()
+	}
+	r.store.goodsReceipts[created.ID] = created
+	r.store.applyTransition(existing, transition)
+
+	receipt.ID = created.ID
+	receipt.CreatedAt = created.CreatedAt
+	for i, line := range created.Lines {
+		receipt.Lines[i].ID = line.ID
+	}
+	return true, nil
+}
+
+func (r *GoodsReceiptRepository) GetByID(ctx context.Context, id string) (*models.GoodsReceipt, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	receipt, ok := r.store.goodsReceipts[id]
+	if !ok || receipt.OrganizationID != orgID {
+		return nil, nil
+	}
+	return r.joined(receipt), nil
+}
+
+func (r *GoodsReceiptRepository) List(ctx context.Context, filter models.GoodsReceiptFilter, limit, offset int) ([]*models.GoodsReceipt, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var receipts []*models.GoodsReceipt
+	for _, receipt := range r.store.goodsReceipts {
+		if receipt.OrganizationID != orgID ||
+			(filter.PurchaseOrderID != "" && receipt.PurchaseOrderID != filter.PurchaseOrderID) {
+			continue
+		}
+		header := r.joined(receipt)
+		if filter.VendorID != "" && header.VendorID != filter.VendorID {
+			continue
+		}
+		header.Lines = nil
+		receipts = append(receipts, header)
+	}
+	sort.Slice(receipts, func(i, j int) bool { return receivedBefore(receipts[j], receipts[i]) })
+	return paginate(receipts, limit, offset), len(receipts), nil
+}
+
+func (r *GoodsReceiptRepository) ListByPurchaseOrder(ctx context.Context, purchaseOrderID string) ([]*models.GoodsReceipt, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var receipts []*models.GoodsReceipt
+	for _, receipt := range r.store.goodsReceipts {
+		if receipt.OrganizationID == orgID && receipt.PurchaseOrderID == purchaseOrderID {
+			receipts = append(receipts, r.joined(receipt))
+		}
+	}
+	sort.Slice(receipts, func(i, j int) bool { return receivedBefore(receipts[i], receipts[j]) })
+	return receipts, nil
+}
+
+// joined returns a copy of the receipt joined with its purchase order, vendor and PO lines,
+// lines ordered by PO line number. The caller holds the lock
+func (r *GoodsReceiptRepository) joined(receipt *models.GoodsReceipt) *models.GoodsReceipt {
+	copied := copyGoodsReceipt(receipt)
+	order, ok := r.store.purchaseOrders[receipt.PurchaseOrderID]
+	if !ok {
+		return copied
+	}
+	copied.PurchaseOrderNumber = order.Number
+	copied.VendorID = order.VendorID
+	if vendor, ok := r.store.vendors[order.VendorID]; ok {
+		copied.VendorName = vendor.VendorName
+	}
+	for _, line := range copied.Lines {
+		for _, orderLine := range order.Lines {
+			if orderLine.ID == line.PurchaseOrderLineID {
+				line.LineNo = orderLine.LineNo
+				line.Description = orderLine.Description
+				line.Unit = orderLine.Unit
+			}
+		}
+	}
+	sort.Slice(copied.Lines, func(i, j int) bool { return copied.Lines[i].LineNo < copied.Lines[j].LineNo })
+	return copied
+}
+
+// receivedBefore orders receipts by receipt date then by the time they were recorded
+func receivedBefore(a, b *models.GoodsReceipt) bool {
+	if !a.ReceivedAt.Equal(b.ReceivedAt) {
+		return a.ReceivedAt.Before(b.ReceivedAt)
+	}
+	return a.CreatedAt.Before(b.CreatedAt)
+}
+
+func copyGoodsReceipt(receipt *models.GoodsReceipt) *models.GoodsReceipt {
+	copied := *receipt
+	copied.Lines = make([]*models.GoodsReceiptLine, 0, len(receipt.Lines))
+	for _, line := range receipt.Lines {
+		copiedLine := *line
+		copied.Lines = append(copied.Lines, &copiedLine)
+	}
+	return &copied
+}
@@ -0,0 +1,266 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"math"
+	"sort"
+	"time"
+)
+
+type InvoiceRepository struct {
+	store *Store
+}
+
+var _ repository.InvoiceRepository = (*InvoiceRepository)(nil)
+
+// NewInvoiceRepository creates an in-memory invoice repository backed by the given store
+func NewInvoiceRepository(store *Store) *InvoiceRepository {
+	return &InvoiceRepository{store: store}
+}
+
+// check mirrors the foreign keys and unique keys of invoices and its lines, the caller holds the lock
+func (r *InvoiceRepository) check(orgID string, invoice *models.Invoice) error {
+	if _, ok := r.store.users[invoice.SubmittedBy]; !ok {
+		return invalidReference("invoice")
+	}
+	if vendor, ok := r.store.vendors[invoice.VendorID]; !ok || vendor.OrganizationID != orgID {
+		return invalidReference("invoice")
+	}
+	order, ok := r.store.purchaseOrders[invoice.PurchaseOrderID]
+	if !ok || order.OrganizationID != orgID {
+		return invalidReference("invoice")
+	}
+	for _, existing := range r.store.invoices {
+		if existing.VendorID == invoice.VendorID && existing.InvoiceNumber == invoice.InvoiceNumber {
+			return conflict("invoice")
+		}
+	}
+	for _, line := range invoice.Lines {
+		if r.store.purchaseOrderLine(line.PurchaseOrderLineID) == nil {
+			return invalidReference("invoice")
+		}
+	}
+	return nil
+}
+
+func (r *InvoiceRepository) Create(ctx context.Context, invoice *models.Invoice) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.check(orgID, invoice); err != nil {
+		return false, err
+	}
+	// the store lock stands in for the purchase order row lock
+	if !sameQuantities(invoice, r.invoicedQuantities(orgID, invoice.PurchaseOrderID, "")) {
+		return false, nil
+	}
+
+	now := r.store.now()
+	created := copyInvoice(invoice)
+	created.ID = newID()
+	created.OrganizationID = orgID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	for _, line := range created.Lines {
+		line.ID = newID()
+	}
+	r.store.invoices[created.ID] = created
+
+	invoice.ID = created.ID
+	return true, nil
+}
+
+func (r *InvoiceRepository) GetByID(ctx context.Context, id string) (*models.Invoice, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	invoice, ok := r.store.invoices[id]
+	if !ok || invoice.OrganizationID != orgID {
+		return nil, nil
+	}
+	return r.joined(invoice), nil
+}
+
+func (r *InvoiceRepository) List(ctx context.Context, filter models.InvoiceFilter, limit, offset int) ([]*models.Invoice, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var invoices []*models.Invoice
+	for _, invoice := range r.store.invoices {
+		if invoice.OrganizationID != orgID ||
+			(filter.Status != "" && invoice.Status != filter.Status) ||
+			(filter.VendorID != "" && invoice.VendorID != filter.VendorID) ||
+			(filter.PurchaseOrderID != "" && invoice.PurchaseOrderID != filter.PurchaseOrderID) {
+			continue
+		}
+		header := r.joined(invoice)
+		header.Lines = nil
+		invoices = append(invoices, header)
+	}
+	sort.Slice(invoices, func(i, j int) bool {
+		if filter.ByDueDate {
+			left, right := dueDate(invoices[i]), dueDate(invoices[j])
+			if !left.Equal(right) {
+				// invoices without a due date sort last, as NULLs do in postgres
+				return !left.IsZero() && (right.IsZero() || left.Before(right))
+			}
+		}
+		if filter.OldestFirst || filter.ByDueDate {
+			return invoices[i].CreatedAt.Before(invoices[j].CreatedAt)
+		}
+		return invoices[i].CreatedAt.After(invoices[j].CreatedAt)
+	})
+	return paginate(invoices, limit, offset), len(invoices), nil
+}
+
+func (r *InvoiceRepository) InvoicedQuantities(ctx context.Context, purchaseOrderID, excludeID string) (map[string]float64, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.invoicedQuantities(orgID, purchaseOrderID, excludeID), nil
+}
+
+// invoicedQuantities sums the quantity per order line over the invoices of the order that are
+// not disputed, the caller holds the lock
+func (r *InvoiceRepository) invoicedQuantities(orgID, purchaseOrderID, excludeID string) map[string]float64 {
+	quantities := map[string]float64{}
+	for _, invoice := range r.store.invoices {
+		if invoice.OrganizationID != orgID || invoice.PurchaseOrderID != purchaseOrderID ||
+			invoice.Status == models.InvoiceStatusDisputed || invoice.ID == excludeID {
+			continue
+		}
+		for _, line := range invoice.Lines {
+			quantities[line.PurchaseOrderLineID] += line.Quantity
+		}
+	}
+	return quantities
+}
+
+// sameQuantities reports whether the previously invoiced quantities the lines were matched
+// against are still the quantities invoiced on the order
+func sameQuantities(invoice *models.Invoice, invoiced map[string]float64) bool {
+	for _, line := range invoice.Lines {
+		if math.Abs(invoiced[line.PurchaseOrderLineID]-line.PreviouslyInvoiced) > 0.0005 {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *InvoiceRepository) UpdateMatch(ctx context.Context, invoice *models.Invoice, from string) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.invoices[invoice.ID]
+	if !ok || existing.OrganizationID != orgID || existing.Status != from {
+		return false, nil
+	}
+	existing.Status = invoice.Status
+	existing.MatchStatus = invoice.MatchStatus
+	existing.MatchedAt = invoice.MatchedAt
+	for _, line := range existing.Lines {
+		for _, matched := range invoice.Lines {
+			if matched.ID != line.ID {
+				continue
+			}
+			line.OrderUnitPrice = matched.OrderUnitPrice
+			line.OrderTaxRate = matched.OrderTaxRate
+			line.AcceptedQuantity = matched.AcceptedQuantity
+			line.PreviouslyInvoiced = matched.PreviouslyInvoiced
+			line.MatchStatus = matched.MatchStatus
+			line.MatchExceptions = append([]string{}, matched.MatchExceptions...)
+		}
+	}
+	existing.UpdatedAt = r.store.now()
+	return true, nil
+}
+
+func (r *InvoiceRepository) Review(ctx context.Context, id, from string, review *models.InvoiceReview) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	invoice, ok := r.store.invoices[id]
+	if !ok || invoice.OrganizationID != orgID || invoice.Status != from {
+		return false, nil
+	}
+	reviewedBy := review.ReviewedBy
+	reviewedAt := review.ReviewedAt
+	invoice.Status = review.Status
+	invoice.ReviewedBy = &reviewedBy
+	invoice.ReviewedAt = &reviewedAt
+	invoice.ReviewNote = review.Note
+	invoice.PaymentTerms = review.PaymentTerms
+	invoice.DueDate = copyTime(review.DueDate)
+	invoice.DiscountDate = copyTime(review.DiscountDate)
+	invoice.DiscountAmount = review.DiscountAmount
+	invoice.UpdatedAt = r.store.now()
+	return true, nil
+}
+
+// joined returns a copy of the invoice joined with its vendor, purchase order and PO lines, the caller holds the lock
+func (r *InvoiceRepository) joined(invoice *models.Invoice) *models.Invoice {
+	copied := copyInvoice(invoice)
+	if vendor, ok := r.store.vendors[invoice.VendorID]; ok {
+		copied.VendorName = vendor.VendorName
+	}
+	if order, ok := r.store.purchaseOrders[invoice.PurchaseOrderID]; ok {
+		copied.PurchaseOrderNumber = order.Number
+	}
+	for _, line := range copied.Lines {
+		if orderLine := r.store.purchaseOrderLine(line.PurchaseOrderLineID); orderLine != nil {
+			line.Description = orderLine.Description
+			line.Unit = orderLine.Unit
+		}
+	}
+	sort.Slice(copied.Lines, func(i, j int) bool { return copied.Lines[i].LineNo < copied.Lines[j].LineNo })
+	return copied
+}
+
+func copyInvoice(invoice *models.Invoice) *models.Invoice {
+	copied := *invoice
+	copied.ReviewedBy = copyString(invoice.ReviewedBy)
+	copied.ReviewedAt = copyTime(invoice.ReviewedAt)
+	copied.DueDate = copyTime(invoice.DueDate)
+	copied.DiscountDate = copyTime(invoice.DiscountDate)
+	copied.Lines = make([]*models.InvoiceLine, 0, len(invoice.Lines))
+	for _, line := range invoice.Lines {
+		copiedLine := *line
+		copiedLine.MatchExceptions = append([]string{}, line.MatchExceptions...)
+		copied.Lines = append(copied.Lines, &copiedLine)
+	}
+	return &copied
+}
+
+// dueDate returns the due date of an invoice, the zero time when it has none
+func dueDate(invoice *models.Invoice) time.Time {
+	if invoice.DueDate == nil {
+		return time.Time{}
+	}
+	return *invoice.DueDate
+}
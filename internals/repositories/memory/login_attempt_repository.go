@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"time"
+)
+
+type LoginAttemptRepository struct {
+	store *Store
+}
+
+var _ repository.LoginAttemptRepository = (*LoginAttemptRepository)(nil)
+
+// NewLoginAttemptRepository creates an in-memory login attempt repository backed by the given store
+func NewLoginAttemptRepository(store *Store) *LoginAttemptRepository {
+	return &LoginAttemptRepository{store: store}
+}
+
+func (r *LoginAttemptRepository) Record(ctx context.Context, attempt *models.LoginAttempt) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if attempt.UserID != nil {
+		if _, ok := r.store.users[*attempt.UserID]; !ok {
+			return invalidReference("login_attempt")
+		}
+	}
+	created := *attempt
+	r.store.loginAttemptSeq++
+	created.ID = r.store.loginAttemptSeq
+	created.CreatedAt = r.store.now()
+	r.store.loginAttempts = append(r.store.loginAttempts, &created)
+	return nil
+}
+
+func (r *LoginAttemptRepository) AccountFailures(ctx context.Context, email string, since time.Time) (*models.LoginFailureStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// attempts are appended in time order, walk back until the latest success
+	stats := &models.LoginFailureStats{}
+	for i := len(r.store.loginAttempts) - 1; i >= 0; i-- {
+		attempt := r.store.loginAttempts[i]
+		if attempt.Email != email {
+			continue
+		}
+		if attempt.Outcome == models.LoginOutcomeSuccess || attempt.CreatedAt.Before(since) {
+			break
+		}
+		countFailure(stats, attempt)
+	}
+	return stats, nil
+}
+
+func (r *LoginAttemptRepository) IPFailures(ctx context.Context, ipAddress string, since time.Time) (*models.LoginFailureStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stats := &models.LoginFailureStats{}
+	for _, attempt := range r.store.loginAttempts {
+		if attempt.IPAddress == ipAddress && !attempt.CreatedAt.Before(since) {
+			countFailure(stats, attempt)
+		}
+	}
+	return stats, nil
+}
+
+func countFailure(stats *models.LoginFailureStats, attempt *models.LoginAttempt) {
+	if attempt.Outcome != models.LoginOutcomeInvalidCredentials {
+		return
+	}
+	stats.Count++
+	if attempt.CreatedAt.After(stats.LastFailureAt) {
+		stats.LastFailureAt = attempt.CreatedAt
+	}
+}
+
+func (r *LoginAttemptRepository) List(ctx context.Context, filter models.LoginAttemptFilter, limit, offset int) ([]*models.LoginAttempt, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	members := r.store.members[orgID]
+
+	var matched []*models.LoginAttempt
+	for i := len(r.store.loginAttempts) - 1; i >= 0; i-- {
+		attempt := r.store.loginAttempts[i]
+		if attempt.UserID == nil {
+			continue
+		}
+		if _, ok := members[*attempt.UserID]; !ok {
+			continue
+		}
+		if (filter.Email != "" && attempt.Email != filter.Email) ||
+			(filter.IPAddress != "" && attempt.IPAddress != filter.IPAddress) ||
+			(filter.Outcome != "" && attempt.Outcome != filter.Outcome) {
+			continue
+		}
+		copied := *attempt
+		matched = append(matched, &copied)
+	}
+	return paginate(matched, limit, offset), len(matched), nil
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+)
+
+// recoveryCode mirrors a row of mfa_recovery_codes
+type recoveryCode struct {
+	hash string
+	used bool
+}
+
+type MFARepository struct {
+	store *Store
+}
+
+var _ repository.MFARepository = (*MFARepository)(nil)
+
+// NewMFARepository creates an in-memory MFA repository backed by the given store
+func NewMFARepository(store *Store) *MFARepository {
+	return &MFARepository{store: store}
+}
+
+func (r *MFARepository) GetByUserID(ctx context.Context, userID string) (*models.UserMFA, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	mfa, ok := r.store.mfa[userID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *mfa
+	return &copied, nil
+}
+
+func (r *MFARepository) SavePending(ctx context.Context, userID, secret string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[userID]; !ok {
+		return invalidReference("mfa")
+	}
+	now := r.store.now()
+	existing, ok := r.store.mfa[userID]
+	if !ok {
+		r.store.mfa[userID] = &models.UserMFA{
+			UserID:    userID,
+			Secret:    secret,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		return nil
+	}
+	if existing.EnabledAt != nil {
+		return apperror.Conflict("mfa_already_enabled", "two-factor authentication is already enabled")
+	}
+	existing.Secret = secret
+	existing.LastUsedStep = 0
+	existing.UpdatedAt = now
+	return nil
+}
+
+func (r *MFARepository) Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	mfa, ok := r.store.mfa[userID]
+	if !ok || mfa.EnabledAt != nil {
+		return notFound("mfa")
+	}
+	now := r.store.now()
+	mfa.EnabledAt = &now
+	mfa.LastUsedStep = step
+	mfa.UpdatedAt = now
+
+	codes := make([]*recoveryCode, 0, len(recoveryCodeHashes))
+	for _, hash := range recoveryCodeHashes {
+		codes = append(codes, &recoveryCode{hash: hash})
+	}
+	r.store.recoveryCodes[userID] = codes
+	return nil
+}
+
+func (r *MFARepository) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	mfa, ok := r.store.mfa[userID]
+	if !ok || mfa.LastUsedStep >= step {
+		return false, nil
+	}
+	mfa.LastUsedStep = step
+	mfa.UpdatedAt = r.store.now()
+	return true, nil
+}
+
+func (r *MFARepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, code := range r.store.recoveryCodes[userID] {
+		if code.hash == codeHash && !code.used {
+			code.used = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
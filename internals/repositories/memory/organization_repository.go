@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/rbac"
+	"sort"
+	"time"
+)
+
+type member struct {
+	role         string
+	departmentID *string
+	costCenterID *string
+	joinedAt     time.Time
+}
+
+type OrganizationRepository struct {
+	store *Store
+}
+
+var _ repository.OrganizationRepository = (*OrganizationRepository)(nil)
+
+// NewOrganizationRepository creates an in-memory organization repository backed by the given store
+func NewOrganizationRepository(store *Store) *OrganizationRepository {
+	return &OrganizationRepository{store: store}
+}
+
+func (r *OrganizationRepository) Create(ctx context.Context, org *models.CreateOrganizationRequest, ownerID string) (*models.Organization, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.organizations {
+		if existing.Code == org.Code {
+			return nil, conflict("organization")
+		}
+	}
+	if _, ok := r.store.users[ownerID]; ownerID != "" && !ok {
+		return nil, invalidReference("organization")
+	}
+
+	now := r.store.now()
+	created := &models.Organization{
+		ID:        newID(),
+		Code:      org.Code,
+		Name:      org.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	r.store.organizations[created.ID] = created
+	r.store.members[created.ID] = map[string]*member{}
+	if ownerID != "" {
+		r.store.members[created.ID][ownerID] = &member{role: rbac.RoleAdmin, joinedAt: now}
+	}
+
+	copied := *created
+	return &copied, nil
+}
+
+func (r *OrganizationRepository) GetByID(ctx context.Context, id string) (*models.Organization, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	org, ok := r.store.organizations[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *org
+	return &copied, nil
+}
+
+func (r *OrganizationRepository) GetByCode(ctx context.Context, code string) (*models.Organization, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, org := range r.store.organizations {
+		if org.Code == code {
+			copied := *org
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *OrganizationRepository) ListForUser(ctx context.Context, userID string) ([]*models.OrganizationMembership, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var memberships []*models.OrganizationMembership
+	for orgID, members := range r.store.members {
+		if m, ok := members[userID]; ok {
+			memberships = append(memberships, r.membership(orgID, m))
+		}
+	}
+	sort.Slice(memberships, func(i, j int) bool { return memberships[i].JoinedAt.Before(memberships[j].JoinedAt) })
+	return memberships, nil
+}
+
+func (r *OrganizationRepository) GetMembership(ctx context.Context, organizationID, userID string) (*models.OrganizationMembership, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	m, ok := r.store.members[organizationID][userID]
+	if !ok {
+		return nil, nil
+	}
+	return r.membership(organizationID, m), nil
+}
+
+func (r *OrganizationRepository) ListMembers(ctx context.Context, organizationID string) ([]*models.OrganizationMember, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var members []*models.OrganizationMember
+	for userID, m := range r.store.members[organizationID] {
+		user := r.store.users[userID]
+		members = append(members, &models.OrganizationMember{
+			UserID:       userID,
+			UserName:     user.UserName,
+			Email:        user.Email,
+			Role:         m.role,
+			DepartmentID: m.departmentID,
+			CostCenterID: m.costCenterID,
+			JoinedAt:     m.joinedAt,
+		})
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].JoinedAt.Before(members[j].JoinedAt) })
+	return members, nil
+}
+
+func (r *OrganizationRepository) AddMember(ctx context.Context, organizationID, userID, role string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	members, ok := r.store.members[organizationID]
+	if _, userExists := r.store.users[userID]; !ok || !userExists {
+		return invalidReference("organization_member")
+	}
+	if _, ok := members[userID]; ok {
+		return conflict("organization_member")
+	}
+	members[userID] = &member{role: role, joinedAt: r.store.now()}
+	return nil
+}
+
+func (r *OrganizationRepository) UpdateMemberRole(ctx context.Context, organizationID, userID, role string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	m, ok := r.store.members[organizationID][userID]
+	if !ok {
+		return notFound("organization_member")
+	}
+	m.role = role
+	return nil
+}
+
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, organizationID, userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.members[organizationID][userID]; !ok {
+		return notFound("organization_member")
+	}
+	// mirror vendors_organization_member_fkey, a vendor owner stays a member
+	for _, vendor := range r.store.vendors {
+		if vendor.OrganizationID == organizationID && vendor.UserID == userID {
+			return invalidReference("organization_member")
+		}
+	}
+	// mirror departments_manager_fkey
+	for _, department := range r.store.departments {
+		if department.OrganizationID == organizationID && department.ManagerID != nil && *department.ManagerID == userID {
+			return invalidReference("organization_member")
+		}
+	}
+	delete(r.store.members[organizationID], userID)
+	r.store.deleteDelegations(organizationID, userID)
+	return nil
+}
+
+// membership builds the membership view of a member, the caller holds the lock
+func (r *OrganizationRepository) membership(organizationID string, m *member) *models.OrganizationMembership {
+	org := r.store.organizations[organizationID]
+	return &models.OrganizationMembership{
+		OrganizationID:   org.ID,
+		OrganizationCode: org.Code,
+		OrganizationName: org.Name,
+		Role:             m.role,
+		JoinedAt:         m.joinedAt,
+	}
+}
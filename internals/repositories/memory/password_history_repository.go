@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/repository"
+)
+
+type PasswordHistoryRepository struct {
+	store *Store
+}
+
+var _ repository.PasswordHistoryRepository = (*PasswordHistoryRepository)(nil)
+
+// NewPasswordHistoryRepository creates an in-memory password history repository backed by the given store
+func NewPasswordHistoryRepository(store *Store) *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{store: store}
+}
+
+func (r *PasswordHistoryRepository) Add(ctx context.Context, userID, passwordHash string, keep int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[userID]; !ok {
+		return invalidReference("password_history")
+	}
+	// newest first
+	history := append([]string{passwordHash}, r.store.passwordHistory[userID]...)
+	if len(history) > keep {
+		history = history[:keep]
+	}
+	r.store.passwordHistory[userID] = history
+	return nil
+}
+
+func (r *PasswordHistoryRepository) Recent(ctx context.Context, userID string, limit int) ([]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return append([]string(nil), paginate(r.store.passwordHistory[userID], limit, 0)...), nil
+}
@@ -0,0 +1,190 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"fmt"
+	"sort"
+)
+
+type PaymentBatchRepository struct {
+	store *Store
+}
+
+var _ repository.PaymentBatchRepository = (*PaymentBatchRepository)(nil)
+
+// NewPaymentBatchRepository creates an in-memory payment batch repository backed by the given store
+func NewPaymentBatchRepository(store *Store) *PaymentBatchRepository {
+	return &PaymentBatchRepository{store: store}
+}
+
+func (r *PaymentBatchRepository) Create(ctx context.Context, batch *models.PaymentBatch) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[batch.CreatedBy]; !ok {
+		return false, invalidReference("payment_batch")
+	}
+	for _, item := range batch.Items {
+		invoice, ok := r.store.invoices[item.InvoiceID]
+		if !ok || invoice.OrganizationID != orgID || invoice.Status != models.InvoiceStatusApproved {
+			return false, nil
+		}
+		// mirror payment_batch_items_vendor_id_fkey
+		if _, ok := r.store.vendors[item.VendorID]; !ok {
+			return false, invalidReference("payment_batch")
+		}
+	}
+
+	now := r.store.now()
+	r.store.paymentBatchNumbers[orgID]++
+	batch.ID = newID()
+	batch.OrganizationID = orgID
+	batch.Number = fmt.Sprintf(models.PaymentBatchNumberFormat, r.store.paymentBatchNumbers[orgID])
+	batch.CreatedAt = now
+	batch.UpdatedAt = now
+	for _, item := range batch.Items {
+		item.ID = newID()
+		invoice := r.store.invoices[item.InvoiceID]
+		invoice.Status = models.InvoiceStatusScheduled
+		invoice.UpdatedAt = now
+	}
+	r.store.paymentBatches[batch.ID] = copyPaymentBatch(batch)
+	return true, nil
+}
+
+func (r *PaymentBatchRepository) GetByID(ctx context.Context, id string) (*models.PaymentBatch, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	batch, ok := r.store.paymentBatches[id]
+	if !ok || batch.OrganizationID != orgID {
+		return nil, nil
+	}
+	return r.joined(batch), nil
+}
+
+func (r *PaymentBatchRepository) List(ctx context.Context, filter models.PaymentBatchFilter, limit, offset int) ([]*models.PaymentBatch, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var batches []*models.PaymentBatch
+	for _, batch := range r.store.paymentBatches {
+		if batch.OrganizationID != orgID || (filter.Status != "" && batch.Status != filter.Status) ||
+			(filter.VendorID != "" && !paysVendor(batch, filter.VendorID)) {
+			continue
+		}
+		header := copyPaymentBatch(batch)
+		header.Items = nil
+		batches = append(batches, header)
+	}
+	sort.Slice(batches, func(i, j int) bool { return batches[i].CreatedAt.After(batches[j].CreatedAt) })
+	return paginate(batches, limit, offset), len(batches), nil
+}
+
+func (r *PaymentBatchRepository) Transition(ctx context.Context, id, from string, transition *models.PaymentBatchTransition) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	batch, ok := r.store.paymentBatches[id]
+	if !ok || batch.OrganizationID != orgID || batch.Status != from {
+		return false, nil
+	}
+	now := r.store.now()
+	batch.Status = transition.Status
+	if transition.ExportFile != nil {
+		batch.ExportFile = *transition.ExportFile
+	}
+	if transition.ExportedAt != nil {
+		batch.ExportedAt = copyTime(transition.ExportedAt)
+		batch.ExportedBy = copyString(transition.ExportedBy)
+	}
+	if transition.PaymentReference != nil {
+		batch.PaymentReference = *transition.PaymentReference
+	}
+	if transition.PaidAt != nil {
+		batch.PaidAt = copyTime(transition.PaidAt)
+		batch.PaidBy = copyString(transition.PaidBy)
+	}
+	if transition.CancelledAt != nil {
+		batch.CancelledAt = copyTime(transition.CancelledAt)
+	}
+	batch.UpdatedAt = now
+	if transition.InvoiceStatus != "" {
+		for _, item := range batch.Items {
+			if invoice, ok := r.store.invoices[item.InvoiceID]; ok {
+				invoice.Status = transition.InvoiceStatus
+				invoice.UpdatedAt = now
+			}
+		}
+	}
+	return true, nil
+}
+
+// joined returns a copy of the batch with its items joined with their invoice and vendor, the caller holds the lock
+func (r *PaymentBatchRepository) joined(batch *models.PaymentBatch) *models.PaymentBatch {
+	copied := copyPaymentBatch(batch)
+	for _, item := range copied.Items {
+		if invoice, ok := r.store.invoices[item.InvoiceID]; ok {
+			item.InvoiceNumber = invoice.InvoiceNumber
+			item.InvoiceDate = invoice.InvoiceDate
+			item.DueDate = copyTime(invoice.DueDate)
+			if order, ok := r.store.purchaseOrders[invoice.PurchaseOrderID]; ok {
+				item.PurchaseOrderNumber = order.Number
+			}
+		}
+		if vendor, ok := r.store.vendors[item.VendorID]; ok {
+			item.VendorName = vendor.VendorName
+		}
+	}
+	sort.Slice(copied.Items, func(i, j int) bool {
+		if copied.Items[i].VendorName != copied.Items[j].VendorName {
+			return copied.Items[i].VendorName < copied.Items[j].VendorName
+		}
+		return copied.Items[i].InvoiceNumber < copied.Items[j].InvoiceNumber
+	})
+	return copied
+}
+
+// paysVendor reports whether the batch has an item of the vendor
+func paysVendor(batch *models.PaymentBatch, vendorID string) bool {
+	for _, item := range batch.Items {
+		if item.VendorID == vendorID {
+			return true
+		}
+	}
+	return false
+}
+
+func copyPaymentBatch(batch *models.PaymentBatch) *models.PaymentBatch {
+	copied := *batch
+	copied.ExportedAt = copyTime(batch.ExportedAt)
+	copied.ExportedBy = copyString(batch.ExportedBy)
+	copied.PaidAt = copyTime(batch.PaidAt)
+	copied.PaidBy = copyString(batch.PaidBy)
+	copied.CancelledAt = copyTime(batch.CancelledAt)
+	copied.Items = make([]*models.PaymentBatchItem, 0, len(batch.Items))
+	for _, item := range batch.Items {
+		copiedItem := *item
+		copiedItem.DueDate = copyTime(item.DueDate)
+		copied.Items = append(copied.Items, &copiedItem)
+	}
+	return &copied
+}
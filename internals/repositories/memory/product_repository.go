@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"fmt"
+	"sort"
+)
+
+type ProductRepository struct {
+	store *Store
+}
+
+var _ repository.ProductRepository = (*ProductRepository)(nil)
+
+// NewProductRepository creates an in-memory product repository backed by the given store
+func NewProductRepository(store *Store) *ProductRepository {
+	return &ProductRepository{store: store}
+}
+
+// withNames returns a copy of the product joined with its category and vendor names
+func (p *ProductRepository) withNames(product *models.Product) *models.Product {
+	copied := *product
+	if category, ok := p.store.categories[product.ProductCategoryID]; ok {
+		copied.ProductCategoryName = category.Name
+	}
+	if vendor, ok := p.store.vendors[product.VendorID]; ok {
+		copied.VendorName = vendor.VendorName
+	}
+	return &copied
+}
+
+// get returns the product when it belongs to the organization, the caller holds the lock
+func (p *ProductRepository) get(orgID, id string) (*models.Product, bool) {
+	product, ok := p.store.products[id]
+	if !ok || product.OrganizationID != orgID {
+		return nil, false
+	}
+	return product, true
+}
+
+// inTenant mirrors the composite foreign keys, category and vendor must belong to the organization
+func (p *ProductRepository) inTenant(orgID, categoryID, vendorID string) bool {
+	category, ok := p.store.categories[categoryID]
+	if !ok || category.OrganizationID != orgID {
+		return false
+	}
+	vendor, ok := p.store.vendors[vendorID]
+	return ok && vendor.OrganizationID == orgID
+}
+
+func (p *ProductRepository) CreateProduct(ctx context.Context, product *models.CreateProductRequest) (*models.Product, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	if !p.inTenant(orgID, product.ProductCategoryID, product.VendorID) {
+		return nil, invalidReference("product")
+	}
+
+	now := p.store.now()
+	created := &models.Product{
+		ID:                 newID(),
+		ProductName:        product.ProductName,
+		ProductPrice:       product.ProductPrice,
+		ProductDescription: product.ProductDescription,
+		ProductCategoryID:  product.ProductCategoryID,
+		VendorID:           product.VendorID,
+		OrganizationID:     orgID,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	p.store.products[created.ID] = created
+
+	copied := *created
+	return &copied, nil
+}
+
+func (p *ProductRepository) GetAllProducts(ctx context.Context, limit, offset int) ([]*models.Product, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.store.mu.RLock()
+	defer p.store.mu.RUnlock()
+
+	products := make([]*models.Product, 0, len(p.store.products))
+	for _, product := range p.store.products {
+		if product.OrganizationID == orgID {
+			products = append(products, p.withNames(product))
+		}
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].CreatedAt.Before(products[j].CreatedAt) })
+	return paginate(products, limit, offset), nil
+}
+
+func (p *ProductRepository) GetProductByID(ctx context.Context, id string) (*models.Product, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.store.mu.RLock()
+	defer p.store.mu.RUnlock()
+
+	product, ok := p.get(orgID, id)
+	if !ok {
+		return nil, fmt.Errorf("failed to get product by ID: %w", notFound("product"))
+	}
+	return p.withNames(product), nil
+}
+
+func (p *ProductRepository) UpdateProduct(ctx context.Context, id string, product *models.UpdateProductRequest) (*models.Product, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	existing, ok := p.get(orgID, id)
+	if !ok {
+		return nil, notFound("product")
+	}
+	if !p.inTenant(orgID, product.ProductCategoryID, existing.VendorID) {
+		return nil, invalidReference("product")
+	}
+	existing.ProductName = product.ProductName
+	existing.ProductPrice = product.ProductPrice
+	existing.ProductDescription = product.ProductDescription
+	existing.ProductCategoryID = product.ProductCategoryID
+	existing.UpdatedAt = p.store.now()
+
+	copied := *existing
+	return &copied, nil
+}
+
+func (p *ProductRepository) DeleteProduct(ctx context.Context, id string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	if _, ok := p.get(orgID, id); !ok {
+		return notFound("product")
+	}
+	p.store.deleteProduct(id)
+	return nil
+}
+
+func (p *ProductRepository) GetProductsByCategory(ctx context.Context, category string, limit, offset int) ([]*models.Product, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.store.mu.RLock()
+	defer p.store.mu.RUnlock()
+
+	var products []*models.Product
+	for _, product := range p.store.products {
+		if product.OrganizationID != orgID {
+			continue
+		}
+		joined := p.withNames(product)
+		if joined.ProductCategoryName != category {
+			continue
+		}
+		// the postgres query does not select vendor columns for this listing
+		joined.VendorID, joined.VendorName = "", ""
+		products = append(products, joined)
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].CreatedAt.After(products[j].CreatedAt) })
+	return paginate(products, limit, offset), nil
+}
+
+func (p *ProductRepository) CountProducts(ctx context.Context) (int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+	p.store.mu.RLock()
+	defer p.store.mu.RUnlock()
+
+	count := 0
+	for _, product := range p.store.products {
+		if product.OrganizationID == orgID {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,344 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"fmt"
+	"sort"
+)
+
+type PurchaseOrderRepository struct {
+	store *Store
+}
+
+var _ repository.PurchaseOrderRepository = (*PurchaseOrderRepository)(nil)
+
+// NewPurchaseOrderRepository creates an in-memory purchase order repository backed by the given store
+func NewPurchaseOrderRepository(store *Store) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{store: store}
+}
+
+// check mirrors the foreign keys and unique keys of purchase_orders and its lines, the caller holds the lock
+func (r *PurchaseOrderRepository) check(orgID string, order *models.PurchaseOrder) error {
+	if _, ok := r.store.users[order.CreatedBy]; !ok {
+		return invalidReference("purchase_order")
+	}
+	if vendor, ok := r.store.vendors[order.VendorID]; !ok || vendor.OrganizationID != orgID {
+		return invalidReference("purchase_order")
+	}
+	if order.RFQID != nil {
+		if rfq, ok := r.store.rfqs[*order.RFQID]; !ok || rfq.OrganizationID != orgID {
+			return invalidReference("purchase_order")
+		}
+	}
+	if order.AwardID != nil {
+		if award, ok := r.store.awards[*order.AwardID]; !ok || award.OrganizationID != orgID {
+			return invalidReference("purchase_order")
+		}
+		for _, existing := range r.store.purchaseOrders {
+			if existing.AwardID != nil && *existing.AwardID == *order.AwardID && existing.VendorID == order.VendorID {
+				return conflict("purchase_order")
+			}
+		}
+	}
+	return r.checkLines(order.Lines)
+}
+
+func (r *PurchaseOrderRepository) Create(ctx context.Context, order *models.PurchaseOrder) (*models.PurchaseOrder, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.check(orgID, order); err != nil {
+		return nil, err
+	}
+
+	now := r.store.now()
+	r.store.purchaseOrderNumbers[orgID]++
+	created := copyPurchaseOrder(order)
+	created.ID = newID()
+	created.OrganizationID = orgID
+	created.Number = fmt.Sprintf(models.PurchaseOrderNumberFormat, r.store.purchaseOrderNumbers[orgID])
+	created.Revision = 0
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	for i, line := range created.Lines {
+		line.ID = newID()
+		line.LineNo = i + 1
+	}
+	r.store.purchaseOrders[created.ID] = created
+
+	return r.withVendorName(created), nil
+}
+
+func (r *PurchaseOrderRepository) GetByID(ctx context.Context, id string) (*models.PurchaseOrder, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	order, ok := r.store.purchaseOrders[id]
+	if !ok || order.OrganizationID != orgID {
+		return nil, nil
+	}
+	return r.withVendorName(order), nil
+}
+
+func (r *PurchaseOrderRepository) List(ctx context.Context, filter models.PurchaseOrderFilter, limit, offset int) ([]*models.PurchaseOrder, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var orders []*models.PurchaseOrder
+	for _, order := range r.store.purchaseOrders {
+		if order.OrganizationID != orgID ||
+			(filter.Status != "" && order.Status != filter.Status) ||
+			(filter.VendorID != "" && order.VendorID != filter.VendorID) ||
+			(filter.AwardID != "" && (order.AwardID == nil || *order.AwardID != filter.AwardID)) ||
+			(filter.SentOnly && order.SentAt == nil) {
+			continue
+		}
+		header := r.withVendorName(order)
+		header.Lines = nil
+		orders = append(orders, header)
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].CreatedAt.After(orders[j].CreatedAt) })
+	return paginate(orders, limit, offset), len(orders), nil
+}
+
+func (r *PurchaseOrderRepository) UpdateDraft(ctx context.Context, order *models.PurchaseOrder) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.purchaseOrders[order.ID]
+	if !ok || existing.OrganizationID != orgID || existing.Status != models.PurchaseOrderStatusDraft {
+		return false, nil
+	}
+	if err := r.checkLines(order.Lines); err != nil {
+		return false, err
+	}
+	r.setDetails(existing, order)
+	return true, nil
+}
+
+func (r *PurchaseOrderRepository) Transition(ctx context.Context, id, from string, transition *models.PurchaseOrderTransition) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	order, ok := r.store.purchaseOrders[id]
+	if !ok || order.OrganizationID != orgID || order.Status != from {
+		return false, nil
+	}
+	r.store.applyTransition(order, transition)
+	return true, nil
+}
+
+func (r *PurchaseOrderRepository) Revise(ctx context.Context, order *models.PurchaseOrder, revision *models.PurchaseOrderRevision, from string) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.purchaseOrders[order.ID]
+	if !ok || existing.OrganizationID != orgID || existing.Status != from {
+		return false, nil
+	}
+	if receipts, invoices := r.countDocuments(order.ID); receipts > 0 || invoices > 0 {
+		return false, nil
+	}
+	if err := r.checkLines(order.Lines); err != nil {
+		return false, err
+	}
+	r.setDetails(existing, order)
+	existing.Status = models.PurchaseOrderStatusDraft
+	existing.Revision++
+	existing.SubmittedAt = nil
+	existing.ApprovedAt = nil
+	existing.ApprovedBy = nil
+	existing.SentAt = nil
+	existing.AcknowledgedAt = nil
+	existing.VendorNote = ""
+	existing.RejectedAt = nil
+	existing.RejectionReason = ""
+
+	r.store.purchaseOrderRevisions = append(r.store.purchaseOrderRevisions, &models.PurchaseOrderRevision{
+		ID:              newID(),
+		PurchaseOrderID: order.ID,
+		Revision:        existing.Revision,
+		Reason:          revision.Reason,
+		ChangedBy:       copyString(revision.ChangedBy),
+		ChangedAt:       existing.UpdatedAt,
+		Snapshot:        copyPurchaseOrder(revision.Snapshot),
+	})
+	return true, nil
+}
+
+func (r *PurchaseOrderRepository) CountDocuments(ctx context.Context, id string) (int, int, error) {
+	if _, err := tenant(ctx); err != nil {
+		return 0, 0, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	receipts, invoices := r.countDocuments(id)
+	return receipts, invoices, nil
+}
+
+// countDocuments counts the goods receipts and invoices of an order, the caller holds the lock
+func (r *PurchaseOrderRepository) countDocuments(id string) (int, int) {
+	var receipts, invoices int
+	for _, receipt := range r.store.goodsReceipts {
+		if receipt.PurchaseOrderID == id {
+			receipts++
+		}
+	}
+	for _, invoice := range r.store.invoices {
+		if invoice.PurchaseOrderID == id {
+			invoices++
+		}
+	}
+	return receipts, invoices
+}
+
+func (r *PurchaseOrderRepository) ListRevisions(ctx context.Context, id string) ([]*models.PurchaseOrderRevision, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	order, ok := r.store.purchaseOrders[id]
+	if !ok || order.OrganizationID != orgID {
+		return nil, nil
+	}
+	var revisions []*models.PurchaseOrderRevision
+	for _, revision := range r.store.purchaseOrderRevisions {
+		if revision.PurchaseOrderID != id {
+			continue
+		}
+		copied := *revision
+		copied.ChangedBy = copyString(revision.ChangedBy)
+		copied.Snapshot = copyPurchaseOrder(revision.Snapshot)
+		revisions = append(revisions, &copied)
+	}
+	return revisions, nil
+}
+
+// applyTransition sets the status of a stored order and the fields the transition carries, the caller holds the lock
+func (s *Store) applyTransition(order *models.PurchaseOrder, transition *models.PurchaseOrderTransition) {
+	order.Status = transition.Status
+	if transition.SubmittedAt != nil {
+		order.SubmittedAt = copyTime(transition.SubmittedAt)
+	}
+	if transition.ApprovedAt != nil {
+		order.ApprovedAt = copyTime(transition.ApprovedAt)
+		order.ApprovedBy = copyString(transition.ApprovedBy)
+	}
+	if transition.SentAt != nil {
+		order.SentAt = copyTime(transition.SentAt)
+	}
+	if transition.AcknowledgedAt != nil {
+		order.AcknowledgedAt = copyTime(transition.AcknowledgedAt)
+	}
+	if transition.VendorNote != nil {
+		order.VendorNote = *transition.VendorNote
+	}
+	if transition.RejectedAt != nil {
+		order.RejectedAt = copyTime(transition.RejectedAt)
+	}
+	if transition.RejectionReason != nil {
+		order.RejectionReason = *transition.RejectionReason
+	}
+	if transition.ClosedAt != nil {
+		order.ClosedAt = copyTime(transition.ClosedAt)
+	}
+	if transition.CancelledAt != nil {
+		order.CancelledAt = copyTime(transition.CancelledAt)
+	}
+	order.UpdatedAt = s.now()
+}
+
+// checkLines mirrors the product foreign key of purchase_order_lines, the caller holds the lock
+func (r *PurchaseOrderRepository) checkLines(lines []*models.PurchaseOrderLine) error {
+	for _, line := range lines {
+		if line.ProductID == nil {
+			continue
+		}
+		if _, ok := r.store.products[*line.ProductID]; !ok {
+			return invalidReference("purchase_order")
+		}
+	}
+	return nil
+}
+
+// setDetails replaces the changeable header fields and lines of a stored order, the caller holds the lock
+func (r *PurchaseOrderRepository) setDetails(existing, order *models.PurchaseOrder) {
+	changed := copyPurchaseOrder(order)
+	existing.ShipTo = changed.ShipTo
+	existing.PaymentTerms = changed.PaymentTerms
+	existing.DeliveryDate = changed.DeliveryDate
+	existing.Notes = changed.Notes
+	existing.Subtotal = changed.Subtotal
+	existing.TaxAmount = changed.TaxAmount
+	existing.TotalAmount = changed.TotalAmount
+	existing.Lines = changed.Lines
+	for i, line := range existing.Lines {
+		line.ID = newID()
+		line.LineNo = i + 1
+	}
+	existing.UpdatedAt = r.store.now()
+}
+
+// withVendorName returns a copy of the order joined with its vendor name, the caller holds the lock
+func (r *PurchaseOrderRepository) withVendorName(order *models.PurchaseOrder) *models.PurchaseOrder {
+	copied := copyPurchaseOrder(order)
+	if vendor, ok := r.store.vendors[order.VendorID]; ok {
+		copied.VendorName = vendor.VendorName
+	}
+	return copied
+}
+
+func copyPurchaseOrder(order *models.PurchaseOrder) *models.PurchaseOrder {
+	if order == nil {
+		return nil
+	}
+	copied := *order
+	copied.RFQID = copyString(order.RFQID)
+	copied.AwardID = copyString(order.AwardID)
+	copied.ApprovedBy = copyString(order.ApprovedBy)
+	copied.DeliveryDate = copyTime(order.DeliveryDate)
+	copied.SubmittedAt = copyTime(order.SubmittedAt)
+	copied.ApprovedAt = copyTime(order.ApprovedAt)
+	copied.SentAt = copyTime(order.SentAt)
+	copied.AcknowledgedAt = copyTime(order.AcknowledgedAt)
+	copied.RejectedAt = copyTime(order.RejectedAt)
+	copied.ClosedAt = copyTime(order.ClosedAt)
+	copied.CancelledAt = copyTime(order.CancelledAt)
+	copied.Lines = make([]*models.PurchaseOrderLine, 0, len(order.Lines))
+	for _, line := range order.Lines {
+		copiedLine := *line
+		copiedLine.ProductID = copyString(line.ProductID)
+		copied.Lines = append(copied.Lines, &copiedLine)
+	}
+	return &copied
+}
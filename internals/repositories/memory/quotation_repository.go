@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"sort"
+)
+
+type QuotationRepository struct {
+	store *Store
+}
+
+var _ repository.QuotationRepository = (*QuotationRepository)(nil)
+
+// NewQuotationRepository creates an in-memory quotation repository backed by the given store
+func NewQuotationRepository(store *Store) *QuotationRepository {
+	return &QuotationRepository{store: store}
+}
+
+func (r *QuotationRepository) Save(ctx context.Context, quotation *models.Quotation) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := r.store.now()
+	rfq, ok := r.store.rfqs[quotation.RFQID]
+	if !ok || rfq.OrganizationID != orgID || rfq.Status != models.RFQStatusOpen || !rfq.ResponseDeadline.After(now) {
+		return false, nil
+	}
+	// mirror quotations_invitation_fkey and the rfq_line_id reference of its lines
+	if !invited(rfq, quotation.VendorID) {
+		return false, invalidReference("quotation")
+	}
+	for _, line := range quotation.Lines {
+		if !hasRFQLine(rfq, line.RFQLineID) {
+			return false, invalidReference("quotation")
+		}
+	}
+
+	saved := copyQuotation(quotation)
+	saved.ID = newID()
+	saved.CreatedAt = now
+	for id, existing := range r.store.quotations {
+		if existing.RFQID == quotation.RFQID && existing.VendorID == quotation.VendorID {
+			saved.ID = id
+			saved.CreatedAt = existing.CreatedAt
+		}
+	}
+	saved.OrganizationID = orgID
+	saved.SubmittedAt = now
+	for _, line := range saved.Lines {
+		line.ID = newID()
+	}
+	r.store.quotations[saved.ID] = saved
+	return true, nil
+}
+
+func (r *QuotationRepository) GetByVendor(ctx context.Context, rfqID, vendorID string) (*models.Quotation, error) {
+	quotations, err := r.list(ctx, func(quotation *models.Quotation) bool {
+		return quotation.RFQID == rfqID && quotation.VendorID == vendorID
+	})
+	if err != nil || len(quotations) == 0 {
+		return nil, err
+	}
+	return quotations[0], nil
+}
+
+func (r *QuotationRepository) ListByRFQ(ctx context.Context, rfqID string) ([]*models.Quotation, error) {
+	return r.list(ctx, func(quotation *models.Quotation) bool { return quotation.RFQID == rfqID })
+}
+
+// list returns copies of the quotations of the tenant matching the predicate, joined with
+// the vendor name and with their lines in RFQ line order
+func (r *QuotationRepository) list(ctx context.Context, match func(*models.Quotation) bool) ([]*models.Quotation, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	quotations := []*models.Quotation{}
+	for _, quotation := range r.store.quotations {
+		if quotation.OrganizationID != orgID || !match(quotation) {
+			continue
+		}
+		copied := copyQuotation(quotation)
+		if vendor, ok := r.store.vendors[quotation.VendorID]; ok {
+			copied.VendorName = vendor.VendorName
+		}
+		if rfq, ok := r.store.rfqs[quotation.RFQID]; ok {
+			lineNo := map[string]int{}
+			for _, line := range rfq.Lines {
+				lineNo[line.ID] = line.LineNo
+			}
+			sort.Slice(copied.Lines, func(i, j int) bool {
+				return lineNo[copied.Lines[i].RFQLineID] < lineNo[copied.Lines[j].RFQLineID]
+			})
+		}
+		quotations = append(quotations, copied)
+	}
+	sort.Slice(quotations, func(i, j int) bool { return quotations[i].SubmittedAt.Before(quotations[j].SubmittedAt) })
+	return quotations, nil
+}
+
+// hasRFQLine reports whether the line is part of the RFQ
+func hasRFQLine(rfq *models.RFQ, lineID string) bool {
+	for _, line := range rfq.Lines {
+		if line.ID == lineID {
+			return true
+		}
+	}
+	return false
+}
+
+func copyQuotation(quotation *models.Quotation) *models.Quotation {
+	copied := *quotation
+	copied.Lines = make([]*models.QuotationLine, 0, len(quotation.Lines))
+	for _, line := range quotation.Lines {
+		copiedLine := *line
+		copied.Lines = append(copied.Lines, &copiedLine)
+	}
+	return &copied
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/repository"
+	"time"
+)
+
+type rateLimitEvent struct {
+	scope     string
+	subject   string
+	createdAt time.Time
+}
+
+type RateLimitRepository struct {
+	store *Store
+}
+
+var _ repository.RateLimitRepository = (*RateLimitRepository)(nil)
+
+// NewRateLimitRepository creates an in-memory rate limit repository backed by the given store
+func NewRateLimitRepository(store *Store) *RateLimitRepository {
+	return &RateLimitRepository{store: store}
+}
+
+func (r *RateLimitRepository) Record(ctx context.Context, scope, subject string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.rateLimitEvents = append(r.store.rateLimitEvents, rateLimitEvent{
+		scope:     scope,
+		subject:   subject,
+		createdAt: r.store.now(),
+	})
+	return nil
+}
+
+func (r *RateLimitRepository) CountSince(ctx context.Context, scope, subject string, since time.Time) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count := 0
+	for _, event := range r.store.rateLimitEvents {
+		if event.scope == scope && event.subject == subject && !event.createdAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
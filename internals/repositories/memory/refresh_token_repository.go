@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+)
+
+type RefreshTokenRepository struct {
+	store *Store
+}
+
+var _ repository.RefreshTokenRepository = (*RefreshTokenRepository)(nil)
+
+// NewRefreshTokenRepository creates an in-memory refresh token repository backed by the given store
+func NewRefreshTokenRepository(store *Store) *RefreshTokenRepository {
+	return &RefreshTokenRepository{store: store}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) (*models.RefreshToken, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[token.UserID]; !ok {
+		return nil, invalidReference("refresh_token")
+	}
+	if _, ok := r.store.organizations[token.OrganizationID]; !ok {
+		return nil, invalidReference("refresh_token")
+	}
+	for _, existing := range r.store.refreshTokens {
+		if existing.TokenHash == token.TokenHash {
+			return nil, conflict("refresh_token")
+		}
+	}
+
+	created := *token
+	created.ID = newID()
+	created.RevokedAt = nil
+	created.CreatedAt = r.store.now()
+	r.store.refreshTokens[created.ID] = &created
+
+	copied := created
+	return &copied, nil
+}
+
+func (r *RefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, token := range r.store.refreshTokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, id string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	token, ok := r.store.refreshTokens[id]
+	if !ok || token.RevokedAt != nil {
+		return false, nil
+	}
+	now := r.store.now()
+	token.RevokedAt = &now
+	return true, nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := r.store.now()
+	for _, token := range r.store.refreshTokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			revokedAt := now
+			token.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := r.store.now()
+	for _, token := range r.store.refreshTokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			revokedAt := now
+			token.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"sort"
+)
+
+type RequisitionRepository struct {
+	store *Store
+}
+
+var _ repository.RequisitionRepository = (*RequisitionRepository)(nil)
+
+// NewRequisitionRepository creates an in-memory purchase requisition repository backed by the given store
+func NewRequisitionRepository(store *Store) *RequisitionRepository {
+	return &RequisitionRepository{store: store}
+}
+
+// check mirrors the foreign keys of purchase_requisitions and its lines, the caller holds the lock
+func (r *RequisitionRepository) check(orgID string, requisition *models.PurchaseRequisition) error {
+	if _, ok := r.store.users[requisition.RequesterID]; !ok {
+		return invalidReference("requisition")
+	}
+	if requisition.DepartmentID != nil {
+		department, ok := r.store.departments[*requisition.DepartmentID]
+		if !ok || department.OrganizationID != orgID {
+			return invalidReference("requisition")
+		}
+	}
+	if requisition.CostCenterID != nil {
+		costCenter, ok := r.store.costCenters[*requisition.CostCenterID]
+		if !ok || costCenter.OrganizationID != orgID {
+			return invalidReference("requisition")
+		}
+	}
+	for _, line := range requisition.Lines {
+		if line.ProductID == nil {
+			continue
+		}
+		if _, ok := r.store.products[*line.ProductID]; !ok {
+			return invalidReference("requisition")
+		}
+	}
+	return nil
+}
+
+func (r *RequisitionRepository) Create(ctx context.Context, requisition *models.PurchaseRequisition) (*models.PurchaseRequisition, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.check(orgID, requisition); err != nil {
+		return nil, err
+	}
+
+	now := r.store.now()
+	created := copyRequisition(requisition)
+	created.ID = newID()
+	created.OrganizationID = orgID
+	created.Lines = numberLines(requisition.Lines)
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	r.store.requisitions[created.ID] = created
+
+	return copyRequisition(created), nil
+}
+
+func (r *RequisitionRepository) GetByID(ctx context.Context, id string) (*models.PurchaseRequisition, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	requisition, ok := r.store.requisitions[id]
+	if !ok || requisition.OrganizationID != orgID {
+		return nil, nil
+	}
+	return copyRequisition(requisition), nil
+}
+
+func (r *RequisitionRepository) List(ctx context.Context, filter models.RequisitionFilter, limit, offset int) ([]*models.PurchaseRequisition, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var requisitions []*models.PurchaseRequisition
+	for _, requisition := range r.store.requisitions {
+		if requisition.OrganizationID != orgID ||
+			(filter.Status != "" && requisition.Status != filter.Status) ||
+			(filter.RequesterID != "" && requisition.RequesterID != filter.RequesterID) ||
+			(filter.DepartmentID != "" && (requisition.DepartmentID == nil || *requisition.DepartmentID != filter.DepartmentID)) {
+			continue
+		}
+		header := copyRequisition(requisition)
+		header.Lines = nil
+		requisitions = append(requisitions, header)
+	}
+	sort.Slice(requisitions, func(i, j int) bool { return requisitions[i].CreatedAt.After(requisitions[j].CreatedAt) })
+	return paginate(requisitions, limit, offset), len(requisitions), nil
+}
+
+func (r *RequisitionRepository) UpdateDraft(ctx context.Context, requisition *models.PurchaseRequisition) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.requisitions[requisition.ID]
+	if !ok || existing.OrganizationID != orgID || existing.Status != models.RequisitionStatusDraft {
+		return false, nil
+	}
+	if err := r.check(orgID, requisition); err != nil {
+		return false, err
+	}
+	existing.DepartmentID = copyString(requisition.DepartmentID)
+	existing.CostCenterID = copyString(requisition.CostCenterID)
+	existing.Title = requisition.Title
+	existing.Justification = requisition.Justification
+	existing.TotalAmount = requisition.TotalAmount
+	existing.Lines = numberLines(requisition.Lines)
+	existing.UpdatedAt = r.store.now()
+	return true, nil
+}
+
+func (r *RequisitionRepository) Transition(ctx context.Context, id, from string, transition *models.RequisitionTransition) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.requisitions[id]
+	if !ok || existing.OrganizationID != orgID || existing.Status != from {
+		return false, nil
+	}
+	existing.Status = transition.Status
+	if transition.SubmittedAt != nil {
+		submittedAt := *transition.SubmittedAt
+		existing.SubmittedAt = &submittedAt
+	}
+	if transition.DecidedAt != nil {
+		decidedAt := *transition.DecidedAt
+		existing.DecidedAt = &decidedAt
+		existing.DecidedBy = copyString(transition.DecidedBy)
+		existing.DecisionComment = transition.DecisionComment
+	}
+	existing.UpdatedAt = r.store.now()
+	return true, nil
+}
+
+// numberLines copies the lines with new IDs and line numbers from 1
+func numberLines(lines []*models.PurchaseRequisitionLine) []*models.PurchaseRequisitionLine {
+	numbered := make([]*models.PurchaseRequisitionLine, 0, len(lines))
+	for i, line := range lines {
+		copied := *line
+		copied.ID = newID()
+		copied.LineNo = i + 1
+		copied.ProductID = copyString(line.ProductID)
+		copied.Amount = line.Quantity * line.UnitPrice
+		numbered = append(numbered, &copied)
+	}
+	return numbered
+}
+
+func copyRequisition(requisition *models.PurchaseRequisition) *models.PurchaseRequisition {
+	copied := *requisition
+	copied.DepartmentID = copyString(requisition.DepartmentID)
+	copied.CostCenterID = copyString(requisition.CostCenterID)
+	copied.DecidedBy = copyString(requisition.DecidedBy)
+	if requisition.SubmittedAt != nil {
+		submittedAt := *requisition.SubmittedAt
+		copied.SubmittedAt = &submittedAt
+	}
+	if requisition.DecidedAt != nil {
+		decidedAt := *requisition.DecidedAt
+		copied.DecidedAt = &decidedAt
+	}
+	copied.Lines = make([]*models.PurchaseRequisitionLine, 0, len(requisition.Lines))
+	for _, line := range requisition.Lines {
+		copiedLine := *line
+		copiedLine.ProductID = copyString(line.ProductID)
+		copied.Lines = append(copied.Lines, &copiedLine)
+	}
+	return &copied
+}
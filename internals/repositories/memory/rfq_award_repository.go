@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"sort"
+)
+
+type RFQAwardRepository struct {
+	store *Store
+}
+
+var _ repository.RFQAwardRepository = (*RFQAwardRepository)(nil)
+
+// NewRFQAwardRepository creates an in-memory RFQ award repository backed by the given store
+func NewRFQAwardRepository(store *Store) *RFQAwardRepository {
+	return &RFQAwardRepository{store: store}
+}
+
+func (r *RFQAwardRepository) Create(ctx context.Context, award *models.RFQAward) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	rfq, ok := r.store.rfqs[award.RFQID]
+	if !ok || rfq.OrganizationID != orgID || rfq.Status != models.RFQStatusOpen {
+		return false, nil
+	}
+	// mirror the references of rfq_awards and its lines
+	if _, ok := r.store.users[award.AwardedBy]; !ok {
+		return false, invalidReference("rfq_award")
+	}
+	for _, line := range award.Lines {
+		quotation, ok := r.store.quotations[line.QuotationID]
+		if !ok || !hasRFQLine(rfq, line.RFQLineID) {
+			return false, invalidReference("rfq_award")
+		}
+		if _, ok := r.store.vendors[line.VendorID]; !ok || quotation.VendorID != line.VendorID {
+			return false, invalidReference("rfq_award")
+		}
+	}
+
+	now := r.store.now()
+	created := copyAward(award)
+	created.ID = newID()
+	created.OrganizationID = orgID
+	created.AwardedAt = now
+	for _, line := range created.Lines {
+		line.ID = newID()
+	}
+	r.store.awards[created.ID] = created
+	rfq.Status = models.RFQStatusAwarded
+	rfq.UpdatedAt = now
+	return true, nil
+}
+
+func (r *RFQAwardRepository) GetByRFQ(ctx context.Context, rfqID string) (*models.RFQAward, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, award := range r.store.awards {
+		if award.RFQID != rfqID || award.OrganizationID != orgID {
+			continue
+		}
+		copied := copyAward(award)
+		lineNo := map[string]int{}
+		if rfq, ok := r.store.rfqs[rfqID]; ok {
+			for _, line := range rfq.Lines {
+				lineNo[line.ID] = line.LineNo
+			}
+		}
+		for _, line := range copied.Lines {
+			if vendor, ok := r.store.vendors[line.VendorID]; ok {
+				line.VendorName = vendor.VendorName
+			}
+		}
+		sort.Slice(copied.Lines, func(i, j int) bool {
+			return lineNo[copied.Lines[i].RFQLineID] < lineNo[copied.Lines[j].RFQLineID]
+		})
+		return copied, nil
+	}
+	return nil, nil
+}
+
+func copyAward(award *models.RFQAward) *models.RFQAward {
+	copied := *award
+	copied.Lines = make([]*models.RFQAwardLine, 0, len(award.Lines))
+	for _, line := range award.Lines {
+		copiedLine := *line
+		copied.Lines = append(copied.Lines, &copiedLine)
+	}
+	return &copied
+}
@@ -0,0 +1,268 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"sort"
+	"time"
+)
+
+type RFQRepository struct {
+	store *Store
+}
+
+var _ repository.RFQRepository = (*RFQRepository)(nil)
+
+// NewRFQRepository creates an in-memory RFQ repository backed by the given store
+func NewRFQRepository(store *Store) *RFQRepository {
+	return &RFQRepository{store: store}
+}
+
+// check mirrors the foreign keys of rfqs and its lines, the caller holds the lock
+func (r *RFQRepository) check(orgID string, rfq *models.RFQ) error {
+	if _, ok := r.store.users[rfq.CreatedBy]; !ok {
+		return invalidReference("rfq")
+	}
+	if rfq.RequisitionID != nil {
+		requisition, ok := r.store.requisitions[*rfq.RequisitionID]
+		if !ok || requisition.OrganizationID != orgID {
+			return invalidReference("rfq")
+		}
+	}
+	for _, line := range rfq.Lines {
+		if line.ProductID == nil {
+			continue
+		}
+		if _, ok := r.store.products[*line.ProductID]; !ok {
+			return invalidReference("rfq")
+		}
+	}
+	return r.checkVendors(orgID, vendorIDs(rfq.Vendors))
+}
+
+// checkVendors mirrors rfq_vendors_vendor_fkey, the caller holds the lock
+func (r *RFQRepository) checkVendors(orgID string, ids []string) error {
+	for _, id := range ids {
+		vendor, ok := r.store.vendors[id]
+		if !ok || vendor.OrganizationID != orgID {
+			return invalidReference("rfq")
+		}
+	}
+	return nil
+}
+
+func (r *RFQRepository) Create(ctx context.Context, rfq *models.RFQ) (*models.RFQ, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.check(orgID, rfq); err != nil {
+		return nil, err
+	}
+
+	now := r.store.now()
+	created := copyRFQ(rfq)
+	created.ID = newID()
+	created.OrganizationID = orgID
+	created.Lines = numberRFQLines(rfq.Lines)
+	created.Vendors = invite(nil, vendorIDs(rfq.Vendors), now)
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	r.store.rfqs[created.ID] = created
+
+	return r.withVendorNames(created), nil
+}
+
+func (r *RFQRepository) GetByID(ctx context.Context, id string) (*models.RFQ, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rfq, ok := r.store.rfqs[id]
+	if !ok || rfq.OrganizationID != orgID {
+		return nil, nil
+	}
+	return r.withVendorNames(rfq), nil
+}
+
+func (r *RFQRepository) List(ctx context.Context, filter models.RFQFilter, limit, offset int) ([]*models.RFQ, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var rfqs []*models.RFQ
+	for _, rfq := range r.store.rfqs {
+		if rfq.OrganizationID != orgID ||
+			(filter.Status != "" && rfq.Status != filter.Status) ||
+			(filter.VendorID != "" && (rfq.Status == models.RFQStatusDraft || !invited(rfq, filter.VendorID))) {
+			continue
+		}
+		header := copyRFQ(rfq)
+		header.Lines = nil
+		header.Vendors = nil
+		rfqs = append(rfqs, header)
+	}
+	sort.Slice(rfqs, func(i, j int) bool { return rfqs[i].CreatedAt.After(rfqs[j].CreatedAt) })
+	return paginate(rfqs, limit, offset), len(rfqs), nil
+}
+
+func (r *RFQRepository) UpdateDraft(ctx context.Context, rfq *models.RFQ) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.rfqs[rfq.ID]
+	if !ok || existing.OrganizationID != orgID || existing.Status != models.RFQStatusDraft {
+		return false, nil
+	}
+	check := *rfq
+	check.CreatedBy = existing.CreatedBy
+	if err := r.check(orgID, &check); err != nil {
+		return false, err
+	}
+	now := r.store.now()
+	existing.RequisitionID = copyString(rfq.RequisitionID)
+	existing.Title = rfq.Title
+	existing.Description = rfq.Description
+	existing.ResponseDeadline = rfq.ResponseDeadline
+	existing.Lines = numberRFQLines(rfq.Lines)
+	existing.Vendors = invite(nil, vendorIDs(rfq.Vendors), now)
+	existing.UpdatedAt = now
+	return true, nil
+}
+
+func (r *RFQRepository) Transition(ctx context.Context, id, from string, transition *models.RFQTransition) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.rfqs[id]
+	if !ok || existing.OrganizationID != orgID || existing.Status != from {
+		return false, nil
+	}
+	existing.Status = transition.Status
+	if transition.PublishedAt != nil {
+		publishedAt := *transition.PublishedAt
+		existing.PublishedAt = &publishedAt
+	}
+	existing.UpdatedAt = r.store.now()
+	return true, nil
+}
+
+func (r *RFQRepository) InviteVendors(ctx context.Context, id string, ids []string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.rfqs[id]
+	if !ok || existing.OrganizationID != orgID {
+		return invalidReference("rfq")
+	}
+	if err := r.checkVendors(orgID, ids); err != nil {
+		return err
+	}
+	existing.Vendors = invite(existing.Vendors, ids, r.store.now())
+	return nil
+}
+
+// withVendorNames returns a copy of the RFQ joined with the names of its vendors
+// ordered by name, the caller holds the lock
+func (r *RFQRepository) withVendorNames(rfq *models.RFQ) *models.RFQ {
+	copied := copyRFQ(rfq)
+	for _, vendor := range copied.Vendors {
+		if stored, ok := r.store.vendors[vendor.VendorID]; ok {
+			vendor.VendorName = stored.VendorName
+		}
+	}
+	sort.Slice(copied.Vendors, func(i, j int) bool { return copied.Vendors[i].VendorName < copied.Vendors[j].VendorName })
+	return copied
+}
+
+// invited reports whether the vendor is invited to the RFQ
+func invited(rfq *models.RFQ, vendorID string) bool {
+	for _, vendor := range rfq.Vendors {
+		if vendor.VendorID == vendorID {
+			return true
+		}
+	}
+	return false
+}
+
+// invite adds the vendors not invited yet
+func invite(vendors []*models.RFQVendor, ids []string, at time.Time) []*models.RFQVendor {
+	for _, id := range ids {
+		if !invited(&models.RFQ{Vendors: vendors}, id) {
+			vendors = append(vendors, &models.RFQVendor{VendorID: id, InvitedAt: at})
+		}
+	}
+	return vendors
+}
+
+// vendorIDs returns the IDs of the invited vendors
+func vendorIDs(vendors []*models.RFQVendor) []string {
+	ids := make([]string, 0, len(vendors))
+	for _, vendor := range vendors {
+		ids = append(ids, vendor.VendorID)
+	}
+	return ids
+}
+
+// numberRFQLines copies the lines with new IDs and line numbers from 1
+func numberRFQLines(lines []*models.RFQLine) []*models.RFQLine {
+	numbered := make([]*models.RFQLine, 0, len(lines))
+	for i, line := range lines {
+		copied := copyRFQLine(line)
+		copied.ID = newID()
+		copied.LineNo = i + 1
+		numbered = append(numbered, copied)
+	}
+	return numbered
+}
+
+func copyRFQLine(line *models.RFQLine) *models.RFQLine {
+	copied := *line
+	copied.ProductID = copyString(line.ProductID)
+	if line.NeededBy != nil {
+		neededBy := *line.NeededBy
+		copied.NeededBy = &neededBy
+	}
+	return &copied
+}
+
+func copyRFQ(rfq *models.RFQ) *models.RFQ {
+	copied := *rfq
+	copied.RequisitionID = copyString(rfq.RequisitionID)
+	if rfq.PublishedAt != nil {
+		publishedAt := *rfq.PublishedAt
+		copied.PublishedAt = &publishedAt
+	}
+	copied.Lines = make([]*models.RFQLine, 0, len(rfq.Lines))
+	for _, line := range rfq.Lines {
+		copied.Lines = append(copied.Lines, copyRFQLine(line))
+	}
+	copied.Vendors = make([]*models.RFQVendor, 0, len(rfq.Vendors))
+	for _, vendor := range rfq.Vendors {
+		copiedVendor := *vendor
+		copied.Vendors = append(copied.Vendors, &copiedVendor)
+	}
+	return &copied
+}
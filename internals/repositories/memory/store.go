@@ -0,0 +1,279 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/pkg/apperror"
+	customContext "e-procurement/pkg/context"
+	"e-procurement/pkg/identifier"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Store keeps every in-memory table behind a single lock so repositories
+// can resolve joins (vendor user name, product category name) the same way
+// the postgres repositories do.
+type Store struct {
+	mu         sync.RWMutex
+	users      map[string]*models.User
+	vendors    map[string]*models.Vendor
+	products   map[string]*models.Product
+	categories map[string]*models.Category
+	// vendor bank account changes in insertion order
+	vendorBankAccountChanges []*models.VendorBankAccountChange
+	// refresh tokens keyed by ID
+	refreshTokens map[string]*models.RefreshToken
+	// revoked access tokens keyed by jti, session cut-offs keyed by user ID
+	revokedTokens      map[string]*models.RevokedToken
+	sessionRevocations map[string]time.Time
+	// single-use mailed tokens keyed by ID
+	userTokens      map[string]*models.UserToken
+	rateLimitEvents []rateLimitEvent
+	// TOTP enrollments and recovery codes keyed by user ID
+	mfa           map[string]*models.UserMFA
+	recoveryCodes map[string][]*recoveryCode
+	// login attempts in insertion order, IDs come from loginAttemptSeq
+	loginAttempts   []*models.LoginAttempt
+	loginAttemptSeq int64
+	// password hashes keyed by user ID, newest first
+	passwordHistory map[string][]string
+	// API keys keyed by ID
+	apiKeys map[string]*models.APIKey
+	// organizations keyed by ID, members keyed by organization ID then user ID
+	organizations map[string]*models.Organization
+	members       map[string]map[string]*member
+	// departments and cost centers keyed by ID
+	departments map[string]*models.Department
+	costCenters map[string]*models.CostCenter
+	// purchase requisitions keyed by ID, lines are kept on the requisition
+	requisitions map[string]*models.PurchaseRequisition
+	// approval rules, approval requests and delegations keyed by ID, steps are kept on their parent
+	approvalRules       map[string]*models.ApprovalRule
+	approvals           map[string]*models.ApprovalRequest
+	approvalDelegations map[string]*models.ApprovalDelegation
+	// RFQs keyed by ID with their lines and invited vendors, quotations keyed by ID with their lines
+	rfqs       map[string]*models.RFQ
+	quotations map[string]*models.Quotation
+	// evaluation criteria keyed by RFQ ID, quotation scores keyed by quotation ID,
+	// awards keyed by ID with their lines
+	evaluationCriteria map[string]*models.EvaluationCriteria
+	quotationScores    map[string]*models.QuotationScore
+	awards             map[string]*models.RFQAward
+	// purchase orders keyed by ID, lines are kept on the order, last PO number keyed by
+	// organization ID and change orders in insertion order
+	purchaseOrders         map[string]*models.PurchaseOrder
+	purchaseOrderNumbers   map[string]int
+	purchaseOrderRevisions []*models.PurchaseOrderRevision
+	// goods receipts keyed by ID, lines are kept on the receipt
+	goodsReceipts map[string]*models.GoodsReceipt
+	// invoices keyed by ID, lines are kept on the invoice
+	invoices map[string]*models.Invoice
+	// payment batches keyed by ID, items are kept on the batch, last batch number keyed by organization ID
+	paymentBatches      map[string]*models.PaymentBatch
+	paymentBatchNumbers map[string]int
+	now                 func() time.Time
+}
+
+// NewStore creates an empty in-memory store
+func NewStore() *Store {
+	return &Store{
+		users:      map[string]*models.User{},
+		vendors:    map[string]*models.Vendor{},
+		products:   map[string]*models.Product{},
+		categories: map[string]*models.Category{},
+
+		refreshTokens:        map[string]*models.RefreshToken{},
+		revokedTokens:        map[string]*models.RevokedToken{},
+		sessionRevocations:   map[string]time.Time{},
+		userTokens:           map[string]*models.UserToken{},
+		mfa:                  map[string]*models.UserMFA{},
+		recoveryCodes:        map[string][]*recoveryCode{},
+		passwordHistory:      map[string][]string{},
+		apiKeys:              map[string]*models.APIKey{},
+		organizations:        map[string]*models.Organization{},
+		members:              map[string]map[string]*member{},
+		departments:          map[string]*models.Department{},
+		costCenters:          map[string]*models.CostCenter{},
+		requisitions:         map[string]*models.PurchaseRequisition{},
+		approvalRules:        map[string]*models.ApprovalRule{},
+		approvals:            map[string]*models.ApprovalRequest{},
+		approvalDelegations:  map[string]*models.ApprovalDelegation{},
+		rfqs:                 map[string]*models.RFQ{},
+		quotations:           map[string]*models.Quotation{},
+		evaluationCriteria:   map[string]*models.EvaluationCriteria{},
+		quotationScores:      map[string]*models.QuotationScore{},
+		awards:               map[string]*models.RFQAward{},
+		purchaseOrders:       map[string]*models.PurchaseOrder{},
+		purchaseOrderNumbers: map[string]int{},
+		goodsReceipts:        map[string]*models.GoodsReceipt{},
+		invoices:             map[string]*models.Invoice{},
+		paymentBatches:       map[string]*models.PaymentBatch{},
+		paymentBatchNumbers:  map[string]int{},
+		now:                  time.Now,
+	}
+}
+
+// tenant returns the organization of the request, tenant owned rows are filtered by it
+func tenant(ctx context.Context) (string, error) {
+	return customContext.GetTenantFromContext(ctx)
+}
+
+// deleteProduct removes a product, mirroring ON DELETE SET NULL on the
+// product references of requisition, RFQ and purchase order lines. The caller holds the lock
+func (s *Store) deleteProduct(id string) {
+	delete(s.products, id)
+	for _, requisition := range s.requisitions {
+		for _, line := range requisition.Lines {
+			if line.ProductID != nil && *line.ProductID == id {
+				line.ProductID = nil
+			}
+		}
+	}
+	for _, rfq := range s.rfqs {
+		for _, line := range rfq.Lines {
+			if line.ProductID != nil && *line.ProductID == id {
+				line.ProductID = nil
+			}
+		}
+	}
+	for _, order := range s.purchaseOrders {
+		for _, line := range order.Lines {
+			if line.ProductID != nil && *line.ProductID == id {
+				line.ProductID = nil
+			}
+		}
+	}
+}
+
+// vendorInUse reports whether the vendor is invited to an RFQ or has purchase orders or invoices, mirroring
+// ON DELETE RESTRICT on rfq_vendors.vendor_id, purchase_orders.vendor_id and invoices.vendor_id. The caller holds the lock
+func (s *Store) vendorInUse(vendorID string) bool {
+	for _, rfq := range s.rfqs {
+		if invited(rfq, vendorID) {
+			return true
+		}
+	}
+	for _, order := range s.purchaseOrders {
+		if order.VendorID == vendorID {
+			return true
+		}
+	}
+	for _, invoice := range s.invoices {
+		if invoice.VendorID == vendorID {
+			return true
+		}
+	}
+	return false
+}
+
+// purchaseOrderLine returns the stored purchase order line with the given ID, nil when there is none.
+// The caller holds the lock
+func (s *Store) purchaseOrderLine(id string) *models.PurchaseOrderLine {
+	for _, order := range s.purchaseOrders {
+		for _, line := range order.Lines {
+			if line.ID == id {
+				return line
+			}
+		}
+	}
+	return nil
+}
+
+// approver reports whether the user is an approver or requester in a rule or approval,
+// mirroring ON DELETE RESTRICT on those references. The caller holds the lock
+func (s *Store) approver(userID string) bool {
+	for _, rule := range s.approvalRules {
+		for _, step := range rule.Steps {
+			if step.ApproverUserID != nil && *step.ApproverUserID == userID {
+				return true
+			}
+		}
+	}
+	for _, request := range s.approvals {
+		if request.RequestedBy == userID {
+			return true
+		}
+		for _, step := range request.Steps {
+			if step.ApproverUserID != nil && *step.ApproverUserID == userID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// deleteDelegations removes the delegations given or received by a member, mirroring
+// ON DELETE CASCADE on approval_delegations. The caller holds the lock
+func (s *Store) deleteDelegations(organizationID, userID string) {
+	for id, delegation := range s.approvalDelegations {
+		if delegation.OrganizationID == organizationID &&
+			(delegation.DelegatorID == userID || delegation.DelegateID == userID) {
+			delete(s.approvalDelegations, id)
+		}
+	}
+}
+
+// newID generates a UUID the same way the postgres column default does
+func newID() string {
+	return identifier.NewUUID()
+}
+
+// paginate returns the [offset, offset+limit) window of a slice
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	end := offset + limit
+	if end > len(items) || limit <= 0 {
+		end = len(items)
+	}
+	return items[offset:end]
+}
+
+// the helpers below build the same domain errors the postgres repositories
+// produce through translateError
+
+func notFound(entity string) error {
+	return apperror.NotFound(entity+"_not_found", entity+" not found")
+}
+
+func conflict(entity string) error {
+	return apperror.Conflict(entity+"_already_exists", entity+" already exists")
+}
+
+func invalidReference(entity string) error {
+	return apperror.Validation(entity+"_invalid_reference",
+		fmt.Sprintf("%s references a record that does not exist or is still in use", entity))
+}
+
+func invalid(entity string) error {
+	return apperror.Validation(entity+"_invalid", "invalid "+entity+" data")
+}
+
+// copyFloat copies an optional value so callers never share pointers with the store
+func copyFloat(value *float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+// copyTime copies an optional value so callers never share pointers with the store
+func copyTime(value *time.Time) *time.Time {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+// copyString copies an optional value so callers never share pointers with the store
+func copyString(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"time"
+)
+
+type TokenRevocationRepository struct {
+	store *Store
+}
+
+var _ repository.TokenRevocationRepository = (*TokenRevocationRepository)(nil)
+
+// NewTokenRevocationRepository creates an in-memory token revocation repository backed by the given store
+func NewTokenRevocationRepository(store *Store) *TokenRevocationRepository {
+	return &TokenRevocationRepository{store: store}
+}
+
+func (r *TokenRevocationRepository) RevokeToken(ctx context.Context, token *models.RevokedToken) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.revokedTokens[token.TokenID]; ok {
+		return nil
+	}
+	revoked := *token
+	revoked.RevokedAt = r.store.now()
+	r.store.revokedTokens[revoked.TokenID] = &revoked
+	return nil
+}
+
+func (r *TokenRevocationRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	_, ok := r.store.revokedTokens[tokenID]
+	return ok, nil
+}
+
+func (r *TokenRevocationRepository) RevokeAllForUser(ctx context.Context, userID string, before time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if current, ok := r.store.sessionRevocations[userID]; ok && current.After(before) {
+		return nil
+	}
+	r.store.sessionRevocations[userID] = before
+	return nil
+}
+
+func (r *TokenRevocationRepository) GetRevokedBefore(ctx context.Context, userID string) (*time.Time, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	revokedBefore, ok := r.store.sessionRevocations[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &revokedBefore, nil
+}
@@ -0,0 +1,336 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/rbac"
+	"sort"
+)
+
+type UserRepository struct {
+	store *Store
+}
+
+var _ repository.UserRepository = (*UserRepository)(nil)
+
+// NewUserRepository creates an in-memory user repository backed by the given store
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+func (r *UserRepository) GetAll(ctx context.Context, limit, offset int) ([]models.UserResponse, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	members := r.store.members[orgID]
+	users := make([]models.UserResponse, 0, len(members))
+	for userID, m := range members {
+		user := r.store.users[userID]
+		users = append(users, models.UserResponse{
+			ID:              user.ID,
+			UserName:        user.UserName,
+			Email:           user.Email,
+			Role:            m.role,
+			OrganizationID:  orgID,
+			EmailVerifiedAt: user.EmailVerifiedAt,
+			CreatedAt:       user.CreatedAt,
+			UpdatedAt:       user.UpdatedAt,
+		})
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].CreatedAt.Before(users[j].CreatedAt) })
+	return paginate(users, limit, offset), nil
+}
+
+func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.CreateUserRequest) (*models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.users {
+		if existing.Email == user.Email {
+			return nil, conflict("user")
+		}
+	}
+
+	now := r.store.now()
+	created := &models.User{
+		ID:        newID(),
+		UserName:  user.UserName,
+		Email:     user.Email,
+		Password:  user.Password,
+		Role:      rbac.DefaultRole,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	r.store.users[created.ID] = created
+
+	copied := *created
+	copied.Password = ""
+	return &copied, nil
+}
+
+func (r *UserRepository) UpdateUser(ctx context.Context, id string, user *models.UpdateUserRequest) (*models.UserResponse, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.users[id]
+	if !ok {
+		return nil, nil
+	}
+	existing.UserName = user.UserName
+	if existing.Email != user.Email {
+		existing.EmailVerifiedAt = nil
+	}
+	existing.Email = user.Email
+	existing.Password = user.Password
+	existing.UpdatedAt = r.store.now()
+
+	return &models.UserResponse{
+		ID:              existing.ID,
+		UserName:        existing.UserName,
+		Email:           existing.Email,
+		Role:            existing.Role,
+		EmailVerifiedAt: existing.EmailVerifiedAt,
+		CreatedAt:       existing.CreatedAt,
+		UpdatedAt:       existing.UpdatedAt,
+	}, nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[id]; !ok {
+		return notFound("user")
+	}
+	// mirror departments_manager_fkey and ON DELETE RESTRICT on purchase_requisitions.requester_id
+	for _, department := range r.store.departments {
+		if department.ManagerID != nil && *department.ManagerID == id {
+			return invalidReference("user")
+		}
+	}
+	for _, requisition := range r.store.requisitions {
+		if requisition.RequesterID == id {
+			return invalidReference("user")
+		}
+	}
+	if r.store.approver(id) {
+		return invalidReference("user")
+	}
+	// mirror ON DELETE RESTRICT on rfqs.created_by, rfq_awards.awarded_by, purchase_orders.created_by,
+	// goods_receipts.received_by, invoices.submitted_by, payment_batches.created_by and on the vendor references
+	// reached through the vendors the user's deletion cascades to
+	for _, rfq := range r.store.rfqs {
+		if rfq.CreatedBy == id {
+			return invalidReference("user")
+		}
+	}
+	for _, award := range r.store.awards {
+		if award.AwardedBy == id {
+			return invalidReference("user")
+		}
+	}
+	for _, order := range r.store.purchaseOrders {
+		if order.CreatedBy == id {
+			return invalidReference("user")
+		}
+	}
+	for _, receipt := range r.store.goodsReceipts {
+		if receipt.ReceivedBy == id {
+			return invalidReference("user")
+		}
+	}
+	for _, invoice := range r.store.invoices {
+		if invoice.SubmittedBy == id {
+			return invalidReference("user")
+		}
+	}
+	for _, batch := range r.store.paymentBatches {
+		if batch.CreatedBy == id {
+			return invalidReference("user")
+		}
+	}
+	for vendorID, vendor := range r.store.vendors {
+		if vendor.UserID == id && r.store.vendorInUse(vendorID) {
+			return invalidReference("user")
+		}
+	}
+	delete(r.store.users, id)
+	// mirror ON DELETE SET NULL on quotation_scores.scored_by, purchase_orders.approved_by,
+	// purchase_order_revisions.changed_by, invoices.reviewed_by, payment_batches.exported_by and payment_batches.paid_by
+	for _, score := range r.store.quotationScores {
+		if score.ScoredBy != nil && *score.ScoredBy == id {
+			score.ScoredBy = nil
+		}
+	}
+	for _, order := range r.store.purchaseOrders {
+		if order.ApprovedBy != nil && *order.ApprovedBy == id {
+			order.ApprovedBy = nil
+		}
+	}
+	for _, revision := range r.store.purchaseOrderRevisions {
+		if revision.ChangedBy != nil && *revision.ChangedBy == id {
+			revision.ChangedBy = nil
+		}
+	}
+	for _, invoice := range r.store.invoices {
+		if invoice.ReviewedBy != nil && *invoice.ReviewedBy == id {
+			invoice.ReviewedBy = nil
+		}
+	}
+	for _, batch := range r.store.paymentBatches {
+		if batch.ExportedBy != nil && *batch.ExportedBy == id {
+			batch.ExportedBy = nil
+		}
+		if batch.PaidBy != nil && *batch.PaidBy == id {
+			batch.PaidBy = nil
+		}
+	}
+	for tokenID, token := range r.store.refreshTokens {
+		if token.UserID == id {
+			delete(r.store.refreshTokens, tokenID)
+		}
+	}
+	for tokenID, token := range r.store.userTokens {
+		if token.UserID == id {
+			delete(r.store.userTokens, tokenID)
+		}
+	}
+	delete(r.store.mfa, id)
+	delete(r.store.passwordHistory, id)
+	for keyID, key := range r.store.apiKeys {
+		if key.UserID == id {
+			delete(r.store.apiKeys, keyID)
+		}
+	}
+	// login_attempts.user_id is ON DELETE SET NULL, the audit trail stays
+	for _, attempt := range r.store.loginAttempts {
+		if attempt.UserID != nil && *attempt.UserID == id {
+			attempt.UserID = nil
+		}
+	}
+	for _, requisition := range r.store.requisitions {
+		if requisition.DecidedBy != nil && *requisition.DecidedBy == id {
+			requisition.DecidedBy = nil
+		}
+	}
+	// approval_request_steps.acted_by and on_behalf_of are ON DELETE SET NULL
+	for _, request := range r.store.approvals {
+		for _, step := range request.Steps {
+			if step.ActedBy != nil && *step.ActedBy == id {
+				step.ActedBy = nil
+			}
+			if step.OnBehalfOf != nil && *step.OnBehalfOf == id {
+				step.OnBehalfOf = nil
+			}
+		}
+	}
+	delete(r.store.recoveryCodes, id)
+	for orgID, members := range r.store.members {
+		delete(members, id)
+		r.store.deleteDelegations(orgID, id)
+	}
+
+	// mirror ON DELETE CASCADE on vendors.user_id and products.vendor_id
+	for vendorID, vendor := range r.store.vendors {
+		if vendor.UserID != id {
+			continue
+		}
+		delete(r.store.vendors, vendorID)
+		for productID, product := range r.store.products {
+			if product.VendorID == vendorID {
+				r.store.deleteProduct(productID)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *UserRepository) Authenticate(ctx context.Context, email string) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *UserRepository) IsUserExists(ctx context.Context, email string) (bool, error) {
+	user, err := r.Authenticate(ctx, email)
+	if err != nil {
+		return false, err
+	}
+	return user != nil, nil
+}
+
+func (r *UserRepository) GetTotalCount(ctx context.Context) (int64, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return int64(len(r.store.members[orgID])), nil
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, id string, newPassword string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return notFound("user")
+	}
+	user.Password = newPassword
+	user.UpdatedAt = r.store.now()
+	return nil
+}
+
+func (r *UserRepository) ReplacePasswordHash(ctx context.Context, id, oldHash, newHash string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok || user.Password != oldHash {
+		return false, nil
+	}
+	user.Password = newHash
+	user.UpdatedAt = r.store.now()
+	return true, nil
+}
+
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return notFound("user")
+	}
+	if user.EmailVerifiedAt == nil {
+		now := r.store.now()
+		user.EmailVerifiedAt = &now
+	}
+	return nil
+}
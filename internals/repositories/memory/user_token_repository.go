@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+)
+
+type UserTokenRepository struct {
+	store *Store
+}
+
+var _ repository.UserTokenRepository = (*UserTokenRepository)(nil)
+
+// NewUserTokenRepository creates an in-memory user token repository backed by the given store
+func NewUserTokenRepository(store *Store) *UserTokenRepository {
+	return &UserTokenRepository{store: store}
+}
+
+func (r *UserTokenRepository) Create(ctx context.Context, token *models.UserToken) (*models.UserToken, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[token.UserID]; !ok {
+		return nil, invalidReference("user_token")
+	}
+	for _, existing := range r.store.userTokens {
+		if existing.TokenHash == token.TokenHash {
+			return nil, conflict("user_token")
+		}
+	}
+
+	created := *token
+	created.ID = newID()
+	created.UsedAt = nil
+	created.CreatedAt = r.store.now()
+	r.store.userTokens[created.ID] = &created
+
+	copied := created
+	return &copied, nil
+}
+
+func (r *UserTokenRepository) GetByHash(ctx context.Context, purpose, tokenHash string) (*models.UserToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, token := range r.store.userTokens {
+		if token.TokenHash == tokenHash && token.Purpose == purpose {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *UserTokenRepository) MarkUsed(ctx context.Context, id string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	token, ok := r.store.userTokens[id]
+	if !ok || token.UsedAt != nil {
+		return false, nil
+	}
+	now := r.store.now()
+	token.UsedAt = &now
+	return true, nil
+}
+
+func (r *UserTokenRepository) InvalidateForUser(ctx context.Context, userID, purpose string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := r.store.now()
+	for _, token := range r.store.userTokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt == nil {
+			usedAt := now
+			token.UsedAt = &usedAt
+		}
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+package memory
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"sort"
+)
+
+type VendorRepository struct {
+	store *Store
+}
+
+var _ repository.VendorRepository = (*VendorRepository)(nil)
+
+// NewVendorRepository creates an in-memory vendor repository backed by the given store
+func NewVendorRepository(store *Store) *VendorRepository {
+	return &VendorRepository{store: store}
+}
+
+// withUserName returns a copy of the vendor joined with its owner's user name
+func (v *VendorRepository) withUserName(vendor *models.Vendor) *models.Vendor {
+	copied := *vendor
+	if user, ok := v.store.users[vendor.UserID]; ok {
+		copied.UserName = user.UserName
+	}
+	return &copied
+}
+
+// get returns the vendor when it belongs to the organization, the caller holds the lock
+func (v *VendorRepository) get(orgID, id string) (*models.Vendor, bool) {
+	vendor, ok := v.store.vendors[id]
+	if !ok || vendor.OrganizationID != orgID {
+		return nil, false
+	}
+	return vendor, true
+}
+
+// checkOwner mirrors vendors_organization_member_fkey and the one profile per user and organization rule
+func (v *VendorRepository) checkOwner(orgID, vendorID, userID string) error {
+	if _, ok := v.store.members[orgID][userID]; !ok {
+		return invalidReference("vendor")
+	}
+	for _, existing := range v.store.vendors {
+		if existing.ID != vendorID && existing.OrganizationID == orgID && existing.UserID == userID {
+			return conflict("vendor")
+		}
+	}
+	return nil
+}
+
+func (v *VendorRepository) CreateVendor(ctx context.Context, userId string, vendorModel *models.CreateVendorRequest) (*models.Vendor, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.store.mu.Lock()
+	defer v.store.mu.Unlock()
+
+	if err := v.checkOwner(orgID, "", userId); err != nil {
+		return nil, err
+	}
+
+	now := v.store.now()
+	vendor := &models.Vendor{
+		ID:                newID(),
+		VendorName:        vendorModel.VendorName,
+		Description:       vendorModel.Description,
+		PaymentTerms:      vendorModel.PaymentTerms,
+		BankCode:          vendorModel.BankCode,
+		BankAccountNumber: vendorModel.BankAccountNumber,
+		BankAccountHolder: vendorModel.BankAccountHolder,
+		Status:            models.VendorStatusPending,
+		UserID:            userId,
+		OrganizationID:    orgID,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	v.store.vendors[vendor.ID] = vendor
+
+	copied := *vendor
+	return &copied, nil
+}
+
+func (v *VendorRepository) GetAllVendors(ctx context.Context, limit, offset int) ([]*models.Vendor, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.store.mu.RLock()
+	defer v.store.mu.RUnlock()
+
+	vendors := make([]*models.Vendor, 0, len(v.store.vendors))
+	for _, vendor := range v.store.vendors {
+		if vendor.OrganizationID == orgID {
+			vendors = append(vendors, v.withUserName(vendor))
+		}
+	}
+	sort.Slice(vendors, func(i, j int) bool { return vendors[i].CreatedAt.After(vendors[j].CreatedAt) })
+	return paginate(vendors, limit, offset), nil
+}
+
+func (v *VendorRepository) GetVendorByID(ctx context.Context, id string) (*models.Vendor, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.store.mu.RLock()
+	defer v.store.mu.RUnlock()
+
+	vendor, ok := v.get(orgID, id)
+	if !ok {
+		return nil, notFound("vendor")
+	}
+	return v.withUserName(vendor), nil
+}
+
+func (v *VendorRepository) UpdateVendor(ctx context.Context, vendorID string, vendorModel *models.UpdateVendorRequest) (*models.Vendor, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.store.mu.Lock()
+	defer v.store.mu.Unlock()
+
+	vendor, ok := v.get(orgID, vendorID)
+	if !ok {
+		return nil, notFound("vendor")
+	}
+	if err := v.checkOwner(orgID, vendorID, vendorModel.UserID); err != nil {
+		return nil, err
+	}
+	vendor.VendorName = vendorModel.VendorName
+	vendor.Description = vendorModel.Description
+	vendor.PaymentTerms = vendorModel.PaymentTerms
+	vendor.UserID = vendorModel.UserID
+	vendor.UpdatedAt = v.store.now()
+
+	copied := *vendor
+	return &copied, nil
+}
+
+func (v *VendorRepository) DeleteVendor(ctx context.Context, id string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	v.store.mu.Lock()
+	defer v.store.mu.Unlock()
+
+	if _, ok := v.get(orgID, id); !ok {
+		return notFound("vendor")
+	}
+	if v.store.vendorInUse(id) {
+		return invalidReference("vendor")
+	}
+	delete(v.store.vendors, id)
+	changes := v.store.vendorBankAccountChanges[:0]
+	for _, change := range v.store.vendorBankAccountChanges {
+		if change.VendorID != id {
+			changes = append(changes, change)
+		}
+	}
+	v.store.vendorBankAccountChanges = changes
+	for productID, product := range v.store.products {
+		if product.VendorID == id {
+			v.store.deleteProduct(productID)
+		}
+	}
+	return nil
+}
+
+func (v *VendorRepository) CountVendors(ctx context.Context) (int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+	v.store.mu.RLock()
+	defer v.store.mu.RUnlock()
+
+	count := 0
+	for _, vendor := range v.store.vendors {
+		if vendor.OrganizationID == orgID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (v *VendorRepository) GetVendorByUserID(ctx context.Context, userID string) (*models.Vendor, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.store.mu.RLock()
+	defer v.store.mu.RUnlock()
+
+	for _, vendor := range v.store.vendors {
+		if vendor.OrganizationID == orgID && vendor.UserID == userID {
+			return v.withUserName(vendor), nil
+		}
+	}
+	return nil, nil
+}
+
+func (v *VendorRepository) UpdateVendorStatus(ctx context.Context, id, from, status string) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	v.store.mu.Lock()
+	defer v.store.mu.Unlock()
+
+	vendor, ok := v.get(orgID, id)
+	if !ok || vendor.Status != from {
+		return false, nil
+	}
+	vendor.Status = status
+	vendor.UpdatedAt = v.store.now()
+	return true, nil
+}
+
+func (v *VendorRepository) ChangeBankAccount(ctx context.Context, change *models.VendorBankAccountChange) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	v.store.mu.Lock()
+	defer v.store.mu.Unlock()
+
+	vendor, ok := v.get(orgID, change.VendorID)
+	if !ok || vendor.BankCode != change.OldBankCode || vendor.BankAccountNumber != change.OldBankAccountNumber ||
+		vendor.BankAccountHolder != change.OldBankAccountHolder {
+		return false, nil
+	}
+	vendor.BankCode = change.BankCode
+	vendor.BankAccountNumber = change.BankAccountNumber
+	vendor.BankAccountHolder = change.BankAccountHolder
+	vendor.UpdatedAt = v.store.now()
+
+	change.ID = newID()
+	change.ChangedAt = v.store.now()
+	recorded := *change
+	v.store.vendorBankAccountChanges = append(v.store.vendorBankAccountChanges, &recorded)
+	return true, nil
+}
+
+func (v *VendorRepository) ListBankAccountChanges(ctx context.Context, vendorID string) ([]*models.VendorBankAccountChange, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.store.mu.RLock()
+	defer v.store.mu.RUnlock()
+
+	if _, ok := v.get(orgID, vendorID); !ok {
+		return nil, nil
+	}
+	var changes []*models.VendorBankAccountChange
+	for _, change := range v.store.vendorBankAccountChanges {
+		if change.VendorID == vendorID {
+			copied := *change
+			changes = append(changes, &copied)
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type MFARepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.MFARepository = (*MFARepository)(nil)
+
+// NewMFARepository creates a new instance of MFARepository with the provided database connection.
+func NewMFARepository(db *sql.DB) *MFARepository {
+	return &MFARepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Get the MFA enrollment of a user
+// It returns nil when the user never started an enrollment.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		userID: ID of the user.
+// returns:
+// 		UserMFA: the enrollment, with the secret still encrypted.
+// 		errors: if any occurred during the operation.
+func (r *MFARepository) GetByUserID(ctx context.Context, userID string) (*models.UserMFA, error) {
+	query := r.SQLBuilder.
+		Select("user_id", "secret", "enabled_at", "last_used_step", "created_at", "updated_at").
+		From("user_mfa").
+		Where(sq.Eq{"user_id": userID})
+
+	var mfa models.UserMFA
+	var enabledAt sql.NullTime
+	err := query.RunWith(r.db).QueryRowContext(ctx).Scan(
+		&mfa.UserID,
+		&mfa.Secret,
+		&enabledAt,
+		&mfa.LastUsedStep,
+		&mfa.CreatedAt,
+		&mfa.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "mfa")
+	}
+	if enabledAt.Valid {
+		mfa.EnabledAt = &enabledAt.Time
+	}
+	return &mfa, nil
+}
+
+// Method to Save a pending MFA secret
+// An enabled enrollment is never overwritten, it has to be disabled first.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		userID: ID of the user.
+// 		secret: encrypted TOTP secret.
+// returns:
+// 		errors: conflict when MFA is already enabled, or any other error of the operation.
+func (r *MFARepository) SavePending(ctx context.Context, userID, secret string) error {
+	query := r.SQLBuilder.
+		Insert("user_mfa").
+		Columns("user_id", "secret").
+		Values(userID, secret).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_used_step = 0 WHERE user_mfa.enabled_at IS NULL")
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return translateError(err, "mfa")
+	}
+	affected, _ := result.RowsAffected()
+	if affected == 0 {
+		return apperror.Conflict("mfa_already_enabled", "two-factor authentication is already enabled")
+	}
+	return nil
+}
+
+// Method to Enable MFA for a user
+// The enrollment is activated and the recovery codes are replaced in a single transaction.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		userID: ID of the user.
+// 		step: TOTP time step of the confirmation code, it cannot be used again.
+// 		recoveryCodeHashes: SHA-256 hashes of the new recovery codes.
+// returns:
+// 		errors: not found when no pending enrollment exists, or any other error of the operation.
+func (r *MFARepository) Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return translateError(err, "mfa")
+	}
+	defer tx.Rollback()
+
+	result, err := r.SQLBuilder.
+		Update("user_mfa").
+		Set("enabled_at", sq.Expr("NOW()")).
+		Set("last_used_step", step).
+		Where(sq.Eq{"user_id": userID, "enabled_at": nil}).
+		RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return translateError(err, "mfa")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return translateError(sql.ErrNoRows, "mfa")
+	}
+
+	_, err = r.SQLBuilder.
+		Delete("mfa_recovery_codes").
+		Where(sq.Eq{"user_id": userID}).
+		RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return translateError(err, "mfa_recovery_code")
+	}
+	if len(recoveryCodeHashes) > 0 {
+		insert := r.SQLBuilder.Insert("mfa_recovery_codes").Columns("user_id", "code_hash")
+		for _, hash := range recoveryCodeHashes {
+			insert = insert.Values(userID, hash)
+		}
+		if _, err := insert.RunWith(tx).ExecContext(ctx); err != nil {
+			return translateError(err, "mfa_recovery_code")
+		}
+	}
+	return translateError(tx.Commit(), "mfa")
+}
+
+// Method to Use a TOTP time step
+// The update only succeeds for a step newer than the last accepted one, so a code cannot be replayed.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		userID: ID of the user.
+// 		step: TOTP time step of the accepted code.
+// returns:
+// 		bool: true when the step was not used before.
+// 		errors: if any occurred during the operation.
+func (r *MFARepository) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	query := r.SQLBuilder.
+		Update("user_mfa").
+		Set("last_used_step", step).
+		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Lt{"last_used_step": step})
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "mfa")
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// Method to Use a recovery code
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		userID: ID of the user.
+// 		codeHash: SHA-256 hash of the recovery code.
+// returns:
+// 		bool: true when an unused code matched and was consumed.
+// 		errors: if any occurred during the operation.
+func (r *MFARepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	query := r.SQLBuilder.
+		Update("mfa_recovery_codes").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": userID, "code_hash": codeHash, "used_at": nil})
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "mfa_recovery_code")
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
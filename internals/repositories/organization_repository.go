@@ -0,0 +1,300 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/rbac"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type OrganizationRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.OrganizationRepository = (*OrganizationRepository)(nil)
+
+// NewOrganizationRepository creates a new instance of OrganizationRepository with the provided database connection.
+func NewOrganizationRepository(db *sql.DB) *OrganizationRepository {
+	return &OrganizationRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create New Organization
+// The owner joins the organization as admin in the same transaction.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		org: code and name of the organization.
+// 		ownerID: user joining as admin, empty to create an organization without members.
+// returns:
+// 		Organization: the stored organization.
+// 		errors: if any occurred during the operation.
+func (r *OrganizationRepository) Create(ctx context.Context, org *models.CreateOrganizationRequest, ownerID string) (*models.Organization, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := r.SQLBuilder.
+		Insert("organizations").
+		Columns("code", "name").
+		Values(org.Code, org.Name).
+		Suffix("RETURNING id, code, name, created_at, updated_at")
+
+	created, err := r.scan(query.RunWith(tx).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "organization")
+	}
+
+	if ownerID != "" {
+		_, err = r.SQLBuilder.
+			Insert("organization_members").
+			Columns("organization_id", "user_id", "role").
+			Values(created.ID, ownerID, rbac.RoleAdmin).
+			RunWith(tx).ExecContext(ctx)
+		if err != nil {
+			return nil, translateError(err, "organization")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Method to Get Organization By ID
+// It returns nil when the organization does not exist.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the organization.
+// returns:
+// 		Organization: the stored organization.
+// 		errors: if any occurred during the operation.
+func (r *OrganizationRepository) GetByID(ctx context.Context, id string) (*models.Organization, error) {
+	return r.getOne(ctx, sq.Eq{"id": id})
+}
+
+// Method to Get Organization By Code
+// It returns nil when the organization does not exist.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		code: unique code of the organization.
+// returns:
+// 		Organization: the stored organization.
+// 		errors: if any occurred during the operation.
+func (r *OrganizationRepository) GetByCode(ctx context.Context, code string) (*models.Organization, error) {
+	return r.getOne(ctx, sq.Eq{"code": code})
+}
+
+// Method to List the Organizations a user belongs to, oldest membership first
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		userID: the member.
+// returns:
+// 		[]OrganizationMembership: the organizations with the role of the user in each.
+// 		errors: if any occurred during the operation.
+func (r *OrganizationRepository) ListForUser(ctx context.Context, userID string) ([]*models.OrganizationMembership, error) {
+	query := r.membershipQuery().
+		Where(sq.Eq{"m.user_id": userID}).
+		OrderBy("m.created_at")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "organization_member")
+	}
+	defer rows.Close()
+
+	var memberships []*models.OrganizationMembership
+	for rows.Next() {
+		membership, err := r.scanMembership(rows)
+		if err != nil {
+			return nil, translateError(err, "organization_member")
+		}
+		memberships = append(memberships, membership)
+	}
+	return memberships, translateError(rows.Err(), "organization_member")
+}
+
+// Method to Get the Membership of a user in an organization
+// It returns nil when the user is not a member.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		organizationID: ID of the organization.
+// 		userID: ID of the user.
+// returns:
+// 		OrganizationMembership: the organization with the role of the user.
+// 		errors: if any occurred during the operation.
+func (r *OrganizationRepository) GetMembership(ctx context.Context, organizationID, userID string) (*models.OrganizationMembership, error) {
+	query := r.membershipQuery().
+		Where(sq.Eq{"m.organization_id": organizationID, "m.user_id": userID})
+
+	membership, err := r.scanMembership(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "organization_member")
+	}
+	return membership, nil
+}
+
+// Method to List the Members of an organization, oldest first
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		organizationID: ID of the organization.
+// returns:
+// 		[]OrganizationMember: the users with their role in the organization.
+// 		errors: if any occurred during the operation.
+func (r *OrganizationRepository) ListMembers(ctx context.Context, organizationID string) ([]*models.OrganizationMember, error) {
+	query := r.SQLBuilder.
+		Select("u.id", "u.user_name", "u.email", "m.role", "m.department_id", "m.cost_center_id", "m.created_at").
+		From("organization_members m").
+		Join("users u ON u.id = m.user_id").
+		Where(sq.Eq{"m.organization_id": organizationID}).
+		OrderBy("m.created_at")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "organization_member")
+	}
+	defer rows.Close()
+
+	var members []*models.OrganizationMember
+	for rows.Next() {
+		var member models.OrganizationMember
+		err := rows.Scan(
+			&member.UserID,
+			&member.UserName,
+			&member.Email,
+			&member.Role,
+			&member.DepartmentID,
+			&member.CostCenterID,
+			&member.JoinedAt,
+		)
+		if err != nil {
+			return nil, translateError(err, "organization_member")
+		}
+		members = append(members, &member)
+	}
+	return members, translateError(rows.Err(), "organization_member")
+}
+
+// Method to Add a user to an organization
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		organizationID: ID of the organization.
+// 		userID: ID of the user.
+// 		role: role of the user inside the organization.
+// returns:
+// 		errors: conflict when the user already is a member.
+func (r *OrganizationRepository) AddMember(ctx context.Context, organizationID, userID, role string) error {
+	query := r.SQLBuilder.
+		Insert("organization_members").
+		Columns("organization_id", "user_id", "role").
+		Values(organizationID, userID, role)
+
+	_, err := query.RunWith(r.db).ExecContext(ctx)
+	return translateError(err, "organization_member")
+}
+
+// Method to Update the role of a member
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		organizationID: ID of the organization.
+// 		userID: ID of the member.
+// 		role: the new role.
+// returns:
+// 		errors: not found when the user is not a member.
+func (r *OrganizationRepository) UpdateMemberRole(ctx context.Context, organizationID, userID, role string) error {
+	query := r.SQLBuilder.
+		Update("organization_members").
+		Set("role", role).
+		Where(sq.Eq{"organization_id": organizationID, "user_id": userID})
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return translateError(err, "organization_member")
+	}
+	affected, _ := result.RowsAffected()
+	if affected == 0 {
+		return translateError(sql.ErrNoRows, "organization_member")
+	}
+	return nil
+}
+
+// Method to Remove a member from an organization
+// A user owning a vendor profile or managing a department in the organization cannot be removed.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		organizationID: ID of the organization.
+// 		userID: ID of the member.
+// returns:
+// 		errors: not found when the user is not a member.
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, organizationID, userID string) error {
+	query := r.SQLBuilder.
+		Delete("organization_members").
+		Where(sq.Eq{"organization_id": organizationID, "user_id": userID})
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return translateError(err, "organization_member")
+	}
+	affected, _ := result.RowsAffected()
+	if affected == 0 {
+		return translateError(sql.ErrNoRows, "organization_member")
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) getOne(ctx context.Context, where sq.Eq) (*models.Organization, error) {
+	query := r.SQLBuilder.
+		Select("id", "code", "name", "created_at", "updated_at").
+		From("organizations").
+		Where(where)
+
+	org, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "organization")
+	}
+	return org, nil
+}
+
+func (r *OrganizationRepository) membershipQuery() sq.SelectBuilder {
+	return r.SQLBuilder.
+		Select("o.id", "o.code", "o.name", "m.role", "m.created_at").
+		From("organization_members m").
+		Join("organizations o ON o.id = m.organization_id")
+}
+
+func (r *OrganizationRepository) scan(row sq.RowScanner) (*models.Organization, error) {
+	var org models.Organization
+	if err := row.Scan(&org.ID, &org.Code, &org.Name, &org.CreatedAt, &org.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *OrganizationRepository) scanMembership(row sq.RowScanner) (*models.OrganizationMembership, error) {
+	var membership models.OrganizationMembership
+	err := row.Scan(
+		&membership.OrganizationID,
+		&membership.OrganizationCode,
+		&membership.OrganizationName,
+		&membership.Role,
+		&membership.JoinedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/repository"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type PasswordHistoryRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.PasswordHistoryRepository = (*PasswordHistoryRepository)(nil)
+
+// NewPasswordHistoryRepository creates a new instance of PasswordHistoryRepository with the provided database connection.
+func NewPasswordHistoryRepository(db *sql.DB) *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Add a password hash to the history of a user
+// The insert and the pruning of old entries run in one transaction.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		userID: owner of the password.
+// 		passwordHash: hash of the new password.
+// 		keep: number of newest entries to keep.
+// returns:
+// 		errors: if any occurred during the operation.
+func (r *PasswordHistoryRepository) Add(ctx context.Context, userID, passwordHash string, keep int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return translateError(err, "password_history")
+	}
+	defer tx.Rollback()
+
+	_, err = r.SQLBuilder.
+		Insert("password_history").
+		Columns("user_id", "password_hash").
+		Values(userID, passwordHash).
+		RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return translateError(err, "password_history")
+	}
+
+	_, err = r.SQLBuilder.
+		Delete("password_history").
+		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Expr("id NOT IN (SELECT id FROM password_history WHERE user_id = ? ORDER BY created_at DESC, id DESC LIMIT ?)", userID, keep)).
+		RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return translateError(err, "password_history")
+	}
+	return translateError(tx.Commit(), "password_history")
+}
+
+// Method to Get the recent password hashes of a user
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		userID: owner of the passwords.
+// 		limit: maximum number of hashes to return.
+// returns:
+// 		[]string: password hashes, newest first.
+// 		errors: if any occurred during the operation.
+func (r *PasswordHistoryRepository) Recent(ctx context.Context, userID string, limit int) ([]string, error) {
+	rows, err := r.SQLBuilder.
+		Select("password_hash").
+		From("password_history").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(limit)).
+		RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "password_history")
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, translateError(err, "password_history")
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, translateError(rows.Err(), "password_history")
+}
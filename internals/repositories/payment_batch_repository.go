@@ -0,0 +1,324 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const paymentBatchColumns = "pb.id, pb.organization_id, pb.batch_number, pb.status, pb.payment_date, pb.invoice_count, " +
+	"pb.total_invoiced, pb.total_discount, pb.total_amount, pb.notes, pb.created_by, pb.export_file, pb.exported_at, " +
+	"pb.exported_by, pb.payment_reference, pb.paid_at, pb.paid_by, pb.cancelled_at, pb.created_at, pb.updated_at"
+
+type PaymentBatchRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.PaymentBatchRepository = (*PaymentBatchRepository)(nil)
+
+// NewPaymentBatchRepository creates a new instance of PaymentBatchRepository with the provided database connection.
+func NewPaymentBatchRepository(db *sql.DB) *PaymentBatchRepository {
+	return &PaymentBatchRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create New PaymentBatch with its items
+// The invoices move from approved to scheduled in the same transaction, so an invoice cannot end up
+// in two batches or be paid after it was disputed.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		batch: header and items, the ID, number and creation time are set on it.
+// returns:
+// 		bool: false when an invoice of the batch is not approved anymore.
+// 		errors: invalid reference when an invoice, vendor or the user does not exist.
+func (r *PaymentBatchRepository) Create(ctx context.Context, batch *models.PaymentBatch) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	invoiceIDs := make([]string, 0, len(batch.Items))
+	for _, item := range batch.Items {
+		invoiceIDs = append(invoiceIDs, item.InvoiceID)
+	}
+	result, err := r.SQLBuilder.
+		Update("invoices").
+		Set("status", models.InvoiceStatusScheduled).
+		Where(sq.Eq{"id": invoiceIDs, "organization_id": orgID, "status": models.InvoiceStatusApproved}).
+		RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "payment_batch")
+	}
+	if affected, _ := result.RowsAffected(); affected != int64(len(invoiceIDs)) {
+		return false, nil
+	}
+
+	var number int
+	err = r.SQLBuilder.
+		Insert("payment_batch_sequences").
+		Columns("organization_id", "last_number").
+		Values(orgID, 1).
+		Suffix("ON CONFLICT (organization_id) DO UPDATE SET last_number = payment_batch_sequences.last_number + 1 RETURNING last_number").
+		RunWith(tx).QueryRowContext(ctx).Scan(&number)
+	if err != nil {
+		return false, translateError(err, "payment_batch")
+	}
+
+	batch.Number = fmt.Sprintf(models.PaymentBatchNumberFormat, number)
+	err = r.SQLBuilder.
+		Insert("payment_batches").
+		Columns("organization_id", "batch_number", "status", "payment_date", "invoice_count", "total_invoiced",
+			"total_discount", "total_amount", "notes", "created_by").
+		Values(orgID, batch.Number, batch.Status, batch.PaymentDate, batch.InvoiceCount, batch.TotalInvoiced,
+			batch.TotalDiscount, batch.TotalAmount, batch.Notes, batch.CreatedBy).
+		Suffix("RETURNING id, created_at").
+		RunWith(tx).QueryRowContext(ctx).Scan(&batch.ID, &batch.CreatedAt)
+	if err != nil {
+		return false, translateError(err, "payment_batch")
+	}
+	for _, item := range batch.Items {
+		err := r.SQLBuilder.
+			Insert("payment_batch_items").
+			Columns("payment_batch_id", "invoice_id", "vendor_id", "invoice_amount", "discount_amount", "payment_amount").
+			Values(batch.ID, item.InvoiceID, item.VendorID, item.InvoiceAmount, item.DiscountAmount, item.PaymentAmount).
+			Suffix("RETURNING id").
+			RunWith(tx).QueryRowContext(ctx).Scan(&item.ID)
+		if err != nil {
+			return false, translateError(err, "payment_batch")
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// Method to Get PaymentBatch By ID with its items
+// It returns nil when the batch does not exist in the tenant.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the payment batch.
+// returns:
+// 		PaymentBatch: the batch with its items ordered by vendor and invoice number.
+// 		errors: if any occurred during the operation.
+func (r *PaymentBatchRepository) GetByID(ctx context.Context, id string) (*models.PaymentBatch, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(paymentBatchColumns).
+		From("payment_batches pb").
+		Where(sq.Eq{"pb.id": id, "pb.organization_id": orgID})
+
+	batch, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "payment_batch")
+	}
+	if batch.Items, err = r.items(ctx, id); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// Method to List PaymentBatches of the tenant, newest first
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		filter: optional status and vendor paid in the batch.
+// 		limit: maximum number of batches to return.
+// 		offset: number of batches to skip.
+// returns:
+// 		[]PaymentBatch: the batch headers without items.
+// 		int: total number of batches matching the filter.
+// 		errors: if any occurred during the operation.
+func (r *PaymentBatchRepository) List(ctx context.Context, filter models.PaymentBatchFilter, limit, offset int) ([]*models.PaymentBatch, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	conditions := sq.And{sq.Eq{"pb.organization_id": orgID}}
+	if filter.Status != "" {
+		conditions = append(conditions, sq.Eq{"pb.status": filter.Status})
+	}
+	if filter.VendorID != "" {
+		conditions = append(conditions, sq.Expr(
+			"EXISTS (SELECT 1 FROM payment_batch_items pbi WHERE pbi.payment_batch_id = pb.id AND pbi.vendor_id = ?)",
+			filter.VendorID))
+	}
+
+	var count int
+	countQuery := r.SQLBuilder.Select("COUNT(*)").From("payment_batches pb").Where(conditions)
+	if err := countQuery.RunWith(r.db).QueryRowContext(ctx).Scan(&count); err != nil {
+		return nil, 0, translateError(err, "payment_batch")
+	}
+
+	query := r.SQLBuilder.
+		Select(paymentBatchColumns).
+		From("payment_batches pb").
+		Where(conditions).
+		OrderBy("pb.created_at DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, 0, translateError(err, "payment_batch")
+	}
+	defer rows.Close()
+
+	var batches []*models.PaymentBatch
+	for rows.Next() {
+		batch, err := r.scan(rows)
+		if err != nil {
+			return nil, 0, translateError(err, "payment_batch")
+		}
+		batches = append(batches, batch)
+	}
+	return batches, count, translateError(rows.Err(), "payment_batch")
+}
+
+// Method to Transition a PaymentBatch
+// The invoices of the batch move along in the same transaction when the transition names an invoice status.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the payment batch.
+// 		from: the status the batch is expected to be in.
+// 		transition: the new status and the fields that come with it.
+// returns:
+// 		bool: false when the batch is not in status `from` (anymore).
+// 		errors: if any occurred during the operation.
+func (r *PaymentBatchRepository) Transition(ctx context.Context, id, from string, transition *models.PaymentBatchTransition) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := r.SQLBuilder.Update("payment_batches").Set("status", transition.Status)
+	if transition.ExportFile != nil {
+		query = query.Set("export_file", *transition.ExportFile)
+	}
+	if transition.ExportedAt != nil {
+		query = query.Set("exported_at", transition.ExportedAt).Set("exported_by", transition.ExportedBy)
+	}
+	if transition.PaymentReference != nil {
+		query = query.Set("payment_reference", *transition.PaymentReference)
+	}
+	if transition.PaidAt != nil {
+		query = query.Set("paid_at", transition.PaidAt).Set("paid_by", transition.PaidBy)
+	}
+	if transition.CancelledAt != nil {
+		query = query.Set("cancelled_at", transition.CancelledAt)
+	}
+	result, err := query.
+		Where(sq.Eq{"id": id, "organization_id": orgID, "status": from}).
+		RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "payment_batch")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+
+	if transition.InvoiceStatus != "" {
+		_, err := r.SQLBuilder.
+			Update("invoices").
+			Set("status", transition.InvoiceStatus).
+			Where(sq.Expr("id IN (SELECT invoice_id FROM payment_batch_items WHERE payment_batch_id = ?)", id)).
+			RunWith(tx).ExecContext(ctx)
+		if err != nil {
+			return false, translateError(err, "payment_batch")
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// items returns the items of a batch joined with their invoice and vendor, ordered by vendor and invoice number
+func (r *PaymentBatchRepository) items(ctx context.Context, batchID string) ([]*models.PaymentBatchItem, error) {
+	query := r.SQLBuilder.
+		Select("pbi.id", "pbi.invoice_id", "inv.invoice_number", "inv.invoice_date", "inv.due_date", "pbi.vendor_id",
+			"v.vendor_name", "po.po_number", "pbi.invoice_amount", "pbi.discount_amount", "pbi.payment_amount").
+		From("payment_batch_items pbi").
+		Join("invoices inv ON inv.id = pbi.invoice_id").
+		Join("vendors v ON v.id = pbi.vendor_id").
+		Join("purchase_orders po ON po.id = inv.purchase_order_id").
+		Where(sq.Eq{"pbi.payment_batch_id": batchID}).
+		OrderBy("v.vendor_name", "inv.invoice_number")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "payment_batch")
+	}
+	defer rows.Close()
+
+	var items []*models.PaymentBatchItem
+	for rows.Next() {
+		var item models.PaymentBatchItem
+		err := rows.Scan(
+			&item.ID,
+			&item.InvoiceID,
+			&item.InvoiceNumber,
+			&item.InvoiceDate,
+			&item.DueDate,
+			&item.VendorID,
+			&item.VendorName,
+			&item.PurchaseOrderNumber,
+			&item.InvoiceAmount,
+			&item.DiscountAmount,
+			&item.PaymentAmount,
+		)
+		if err != nil {
+			return nil, translateError(err, "payment_batch")
+		}
+		items = append(items, &item)
+	}
+	return items, translateError(rows.Err(), "payment_batch")
+}
+
+func (r *PaymentBatchRepository) scan(row sq.RowScanner) (*models.PaymentBatch, error) {
+	var batch models.PaymentBatch
+	err := row.Scan(
+		&batch.ID,
+		&batch.OrganizationID,
+		&batch.Number,
+		&batch.Status,
+		&batch.PaymentDate,
+		&batch.InvoiceCount,
+		&batch.TotalInvoiced,
+		&batch.TotalDiscount,
+		&batch.TotalAmount,
+		&batch.Notes,
+		&batch.CreatedBy,
+		&batch.ExportFile,
+		&batch.ExportedAt,
+		&batch.ExportedBy,
+		&batch.PaymentReference,
+		&batch.PaidAt,
+		&batch.PaidBy,
+		&batch.CancelledAt,
+		&batch.CreatedAt,
+		&batch.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
 	"fmt"
 
 	sq "github.com/Masterminds/squirrel"
@@ -14,6 +15,8 @@ type ProductRepository struct {
 	SQLBuilder 	sq.StatementBuilderType
 }
 
+var _ repository.ProductRepository = (*ProductRepository)(nil)
+
 // NewProductUseCase creates a new instance of ProductUseCase with the provided database connection.
 func NewProductUseCase(db *sql.DB) *ProductRepository {
 	return &ProductRepository{
@@ -33,26 +36,31 @@ func NewProductUseCase(db *sql.DB) *ProductRepository {
 // 		ProductResponse: a ProductResponse model containing the details of the created product.
 // 		errors: if any occurred during the operation.
 func(p *ProductRepository) CreateProduct(ctx context.Context, product *models.CreateProductRequest ) (*models.Product, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := p.SQLBuilder.
 		Insert("products").
-		Columns("product_name", "product_price", "product_description", "product_category","vendor_id").
-		Values(product.ProductName, product.ProductPrice, product.ProductDescription, product.ProductCategoryID, product.VendorID).
-		Suffix("RETURNING id, product_name, product_price, product_description, product_category, vendor_id, created_at, updated_at")
+		Columns("product_name", "product_price", "product_description", "product_category","vendor_id", "organization_id").
+		Values(product.ProductName, product.ProductPrice, product.ProductDescription, product.ProductCategoryID, product.VendorID, orgID).
+		Suffix("RETURNING id, product_name, product_price, product_description, product_category, vendor_id, organization_id, created_at, updated_at")
 	row := query.RunWith(p.db).QueryRowContext(ctx)
 
 	var productResponse models.Product
-	err := row.Scan(
+	err = row.Scan(
 		&productResponse.ID,
 		&productResponse.ProductName,
 		&productResponse.ProductPrice,
 		&productResponse.ProductDescription,
 		&productResponse.ProductCategoryID,
 		&productResponse.VendorID,
+		&productResponse.OrganizationID,
 		&productResponse.CreatedAt,
 		&productResponse.UpdatedAt,
 	)
 	if err != nil {
-		return nil,err
+		return nil, translateError(err, "product")
 	}
 
 	return &productResponse, nil
@@ -68,6 +76,10 @@ func(p *ProductRepository) CreateProduct(ctx context.Context, product *models.Cr
 // 		[]ProductResponse: a slice of ProductResponse models containing the details of all products.
 // 		errors: if any occurred during the operation.
 func (p *ProductRepository) GetAllProducts(ctx context.Context, limit, offset int) ([]*models.Product, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
     query := p.SQLBuilder.
         Select(
             "p.id",
@@ -81,15 +93,17 @@ func (p *ProductRepository) GetAllProducts(ctx context.Context, limit, offset in
             "p.created_at",
             "p.updated_at",
         ).
-        From("e_procurement.products p").
-        LeftJoin("e_procurement.categories c ON p.product_category = c.id").
-		LeftJoin("e_procurement.vendors v ON p.vendor_id = v.id").
+        From("products p").
+        LeftJoin("categories c ON p.product_category = c.id").
+		LeftJoin("vendors v ON p.vendor_id = v.id").
+        Where(sq.Eq{"p.organization_id": orgID}).
+        OrderBy("p.created_at").
         Limit(uint64(limit)).
         Offset(uint64(offset))
 
     rows, err := query.RunWith(p.db).QueryContext(ctx)
     if err != nil {
-        return nil, err
+        return nil, translateError(err, "product")
     }
     defer rows.Close()
 
@@ -108,7 +122,7 @@ func (p *ProductRepository) GetAllProducts(ctx context.Context, limit, offset in
             &product.CreatedAt,
             &product.UpdatedAt,
         ); err != nil {
-            return nil, err
+            return nil, translateError(err, "product")
         }
         products = append(products, &product)
     }
@@ -125,6 +139,10 @@ func (p *ProductRepository) GetAllProducts(ctx context.Context, limit, offset in
 // 		ProductResponse: a ProductResponse model containing the details of the product.	
 // 		errors: if any occurred during the operation.
 func (p *ProductRepository) GetProductByID(ctx context.Context, id string) (*models.Product, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
     query := p.SQLBuilder.
         Select(
             "p.id",
@@ -141,12 +159,12 @@ func (p *ProductRepository) GetProductByID(ctx context.Context, id string) (*mod
         From("products p").
         LeftJoin("categories c ON p.product_category = c.id").
 		LeftJoin("vendors v ON p.vendor_id = v.id").
-        Where(sq.Eq{"p.id": id})
+        Where(sq.Eq{"p.id": id, "p.organization_id": orgID})
 
     row := query.RunWith(p.db).QueryRowContext(ctx)
 
     var product models.Product
-    err := row.Scan(
+    err = row.Scan(
         &product.ID,
         &product.ProductName,
         &product.ProductPrice,
@@ -159,7 +177,7 @@ func (p *ProductRepository) GetProductByID(ctx context.Context, id string) (*mod
         &product.UpdatedAt,
     )
     if err != nil {
-        return nil, fmt.Errorf("failed to get product by ID: %w", err)
+        return nil, fmt.Errorf("failed to get product by ID: %w", translateError(err, "product"))
     }
 
     return &product, nil
@@ -175,30 +193,35 @@ func (p *ProductRepository) GetProductByID(ctx context.Context, id string) (*mod
 // 		ProductResponse: a ProductResponse model containing the updated details of the product.
 // 		errors: if any occurred during the operation.
 func(p *ProductRepository) UpdateProduct(ctx context.Context, id string, product *models.UpdateProductRequest) (*models.Product, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := p.SQLBuilder.
 		Update("products").
 		Set("product_name", product.ProductName).
 		Set("product_price", product.ProductPrice).
 		Set("product_description", product.ProductDescription).
 		Set("product_category", product.ProductCategoryID).
-		Where(sq.Eq{"id": id}).
-		Suffix("RETURNING id, product_name, product_price, product_description, product_category, vendor_id, created_at, updated_at")
+		Where(sq.Eq{"id": id, "organization_id": orgID}).
+		Suffix("RETURNING id, product_name, product_price, product_description, product_category, vendor_id, organization_id, created_at, updated_at")
 
 	row := query.RunWith(p.db).QueryRowContext(ctx)
 
 	var updatedProduct models.Product
-	err := row.Scan(
+	err = row.Scan(
 		&updatedProduct.ID,
 		&updatedProduct.ProductName,
 		&updatedProduct.ProductPrice,
 		&updatedProduct.ProductDescription,
 		&updatedProduct.ProductCategoryID,
 		&updatedProduct.VendorID,
+		&updatedProduct.OrganizationID,
 		&updatedProduct.CreatedAt,
 		&updatedProduct.UpdatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "product")
 	}
 
 	return &updatedProduct, nil
@@ -207,12 +230,20 @@ func(p *ProductRepository) UpdateProduct(ctx context.Context, id string, product
 // Method to Delete Product by ID
 // It returns an error if any occurred during the operation.
 func(p *ProductRepository) DeleteProduct(ctx context.Context, id string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
 	query := p.SQLBuilder.
 		Delete("products").
-		Where(sq.Eq{"id": id})
-	_, err := query.RunWith(p.db).ExecContext(ctx)
+		Where(sq.Eq{"id": id, "organization_id": orgID})
+	result, err := query.RunWith(p.db).ExecContext(ctx)
 	if err != nil {
-		return err
+		return translateError(err, "product")
+	}
+	affected, _ := result.RowsAffected()
+	if affected == 0 {
+		return translateError(sql.ErrNoRows, "product")
 	}
 	return nil
 }
@@ -227,6 +258,10 @@ func(p *ProductRepository) DeleteProduct(ctx context.Context, id string) error {
 // 		the specified category.
 // 		errors: if any occurred during the operation.
 func(p *ProductRepository) GetProductsByCategory(ctx context.Context, category string, limit, offset int) ([]*models.Product, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := p.SQLBuilder.
 		 Select(
             "p.id",
@@ -238,16 +273,16 @@ func(p *ProductRepository) GetProductsByCategory(ctx context.Context, category s
             "p.created_at",
             "p.updated_at",
         ).
-        From("e_procurement.products p").
-        Join("e_procurement.categories c ON p.product_category = c.id").
+        From("products p").
+        Join("categories c ON p.product_category = c.id").
         OrderBy("p.created_at DESC").
-		Where(sq.Eq{"c.category_name": category}).
+		Where(sq.Eq{"c.category_name": category, "p.organization_id": orgID}).
 		Limit(uint64(limit)).
 		Offset(uint64(offset))
 
 	rows, err := query.RunWith(p.db).QueryContext(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "product")
 	}
 	defer rows.Close()
 
@@ -263,7 +298,7 @@ func(p *ProductRepository) GetProductsByCategory(ctx context.Context, category s
 			&product.CreatedAt,
 			&product.UpdatedAt,
 		); err != nil {
-			return nil, err
+			return nil, translateError(err, "product")
 		}
 		products = append(products, &product)
 	}
@@ -279,16 +314,21 @@ func(p *ProductRepository) GetProductsByCategory(ctx context.Context, category s
 // returns:
 // 		int: total number of products.
 func(p *ProductRepository) CountProducts(ctx context.Context) (int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
 	query := p.SQLBuilder.
 		Select("COUNT(*)").
-		From("products")
+		From("products").
+		Where(sq.Eq{"organization_id": orgID})
 
 	row := query.RunWith(p.db).QueryRowContext(ctx)
 
 	var count int
-	err := row.Scan(&count)
+	err = row.Scan(&count)
 	if err != nil {
-		return 0, err
+		return 0, translateError(err, "product")
 	}
 
 	return count, nil
@@ -0,0 +1,556 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"encoding/json"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const purchaseOrderColumns = "po.id, po.organization_id, po.po_number, po.revision, po.vendor_id, v.vendor_name, po.rfq_id, " +
+	"po.award_id, po.status, po.ship_to, po.payment_terms, po.delivery_date, po.notes, po.subtotal, po.tax_amount, " +
+	"po.total_amount, po.created_by, po.submitted_at, po.approved_at, po.approved_by, po.sent_at, po.acknowledged_at, " +
+	"po.vendor_note, po.rejected_at, po.rejection_reason, po.closed_at, po.cancelled_at, po.created_at, po.updated_at"
+
+type PurchaseOrderRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.PurchaseOrderRepository = (*PurchaseOrderRepository)(nil)
+
+// NewPurchaseOrderRepository creates a new instance of PurchaseOrderRepository with the provided database connection.
+func NewPurchaseOrderRepository(db *sql.DB) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create New PurchaseOrder with its lines
+// The PO number is the next number of the organization sequence, taken in the same transaction.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		order: header and lines, the status and totals are stored as given.
+// returns:
+// 		PurchaseOrder: the stored order with its number and lines.
+// 		errors: conflict when the award already has an order for the vendor,
+// 		invalid reference when the vendor, RFQ, award or a product does not exist.
+func (r *PurchaseOrderRepository) Create(ctx context.Context, order *models.PurchaseOrder) (*models.PurchaseOrder, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var number int
+	err = r.SQLBuilder.
+		Insert("purchase_order_sequences").
+		Columns("organization_id", "last_number").
+		Values(orgID, 1).
+		Suffix("ON CONFLICT (organization_id) DO UPDATE SET last_number = purchase_order_sequences.last_number + 1 RETURNING last_number").
+		RunWith(tx).QueryRowContext(ctx).Scan(&number)
+	if err != nil {
+		return nil, translateError(err, "purchase_order")
+	}
+
+	var orderID string
+	err = r.SQLBuilder.
+		Insert("purchase_orders").
+		Columns("organization_id", "po_number", "vendor_id", "rfq_id", "award_id", "status", "ship_to", "payment_terms",
+			"delivery_date", "notes", "subtotal", "tax_amount", "total_amount", "created_by").
+		Values(orgID, fmt.Sprintf(models.PurchaseOrderNumberFormat, number), order.VendorID, order.RFQID, order.AwardID,
+			order.Status, order.ShipTo, order.PaymentTerms, order.DeliveryDate, order.Notes, order.Subtotal,
+			order.TaxAmount, order.TotalAmount, order.CreatedBy).
+		Suffix("RETURNING id").
+		RunWith(tx).QueryRowContext(ctx).Scan(&orderID)
+	if err != nil {
+		return nil, translateError(err, "purchase_order")
+	}
+	if err := r.insertLines(ctx, tx, orderID, order.Lines); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, orderID)
+}
+
+// Method to Get PurchaseOrder By ID with its lines
+// It returns nil when the order does not exist in the tenant.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the purchase order.
+// returns:
+// 		PurchaseOrder: the order with its lines ordered by line number.
+// 		errors: if any occurred during the operation.
+func (r *PurchaseOrderRepository) GetByID(ctx context.Context, id string) (*models.PurchaseOrder, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(purchaseOrderColumns).
+		From("purchase_orders po").
+		Join("vendors v ON v.id = po.vendor_id").
+		Where(sq.Eq{"po.id": id, "po.organization_id": orgID})
+
+	order, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "purchase_order")
+	}
+	if order.Lines, err = r.lines(ctx, id); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Method to List PurchaseOrders of the tenant, newest first
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		filter: optional status, vendor and award, SentOnly keeps the orders sent to the vendor.
+// 		limit: maximum number of orders to return.
+// 		offset: number of orders to skip.
+// returns:
+// 		[]PurchaseOrder: the order headers without lines.
+// 		int: total number of orders matching the filter.
+// 		errors: if any occurred during the operation.
+func (r *PurchaseOrderRepository) List(ctx context.Context, filter models.PurchaseOrderFilter, limit, offset int) ([]*models.PurchaseOrder, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	where := sq.Eq{"po.organization_id": orgID}
+	if filter.Status != "" {
+		where["po.status"] = filter.Status
+	}
+	if filter.VendorID != "" {
+		where["po.vendor_id"] = filter.VendorID
+	}
+	if filter.AwardID != "" {
+		where["po.award_id"] = filter.AwardID
+	}
+	conditions := sq.And{where}
+	if filter.SentOnly {
+		conditions = append(conditions, sq.NotEq{"po.sent_at": nil})
+	}
+
+	var count int
+	countQuery := r.SQLBuilder.Select("COUNT(*)").From("purchase_orders po").Where(conditions)
+	if err := countQuery.RunWith(r.db).QueryRowContext(ctx).Scan(&count); err != nil {
+		return nil, 0, translateError(err, "purchase_order")
+	}
+
+	query := r.SQLBuilder.
+		Select(purchaseOrderColumns).
+		From("purchase_orders po").
+		Join("vendors v ON v.id = po.vendor_id").
+		Where(conditions).
+		OrderBy("po.created_at DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, 0, translateError(err, "purchase_order")
+	}
+	defer rows.Close()
+
+	var orders []*models.PurchaseOrder
+	for rows.Next() {
+		order, err := r.scan(rows)
+		if err != nil {
+			return nil, 0, translateError(err, "purchase_order")
+		}
+		orders = append(orders, order)
+	}
+	return orders, count, translateError(rows.Err(), "purchase_order")
+}
+
+// Method to Update a draft PurchaseOrder
+// The header fields are replaced and the lines are rewritten in one transaction.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		order: the order with its new values and lines.
+// returns:
+// 		bool: false when the order is not a draft (anymore).
+// 		errors: invalid reference when the vendor or a product does not exist.
+func (r *PurchaseOrderRepository) UpdateDraft(ctx context.Context, order *models.PurchaseOrder) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := r.updateDetails(order).
+		Where(sq.Eq{"id": order.ID, "organization_id": orgID, "status": models.PurchaseOrderStatusDraft})
+
+	result, err := query.RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "purchase_order")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+	if err := r.replaceLines(ctx, tx, order.ID, order.Lines); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// Method to Transition a PurchaseOrder to another status
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the purchase order.
+// 		from: the status the order is expected to be in.
+// 		transition: the new status with the timestamps and notes of the step.
+// returns:
+// 		bool: false when the order is not in status `from` (anymore).
+// 		errors: if any occurred during the operation.
+func (r *PurchaseOrderRepository) Transition(ctx context.Context, id, from string, transition *models.PurchaseOrderTransition) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	query := setTransition(r.SQLBuilder.Update("purchase_orders"), transition).
+		Where(sq.Eq{"id": id, "organization_id": orgID, "status": from})
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "purchase_order")
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// Method to Revise a PurchaseOrder through a change order
+// The order moves back to draft under the next revision, the approval, dispatch and vendor
+// response of the previous revision are cleared and the revision is recorded with its snapshot.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		order: the order with its new values and lines.
+// 		revision: reason, author and snapshot of the order before the change.
+// 		from: the status the order is expected to be in.
+// returns:
+// 		bool: false when the order is not in status `from` (anymore) or a goods receipt or invoice refers to it.
+// 		errors: invalid reference when a product does not exist.
+func (r *PurchaseOrderRepository) Revise(ctx context.Context, order *models.PurchaseOrder, revision *models.PurchaseOrderRevision, from string) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	snapshot, err := json.Marshal(revision.Snapshot)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// the lock keeps receipts and invoices from being recorded against the lines being replaced
+	var orderID string
+	err = r.SQLBuilder.
+		Select("id").
+		From("purchase_orders").
+		Where(sq.Eq{"id": order.ID, "organization_id": orgID}).
+		Suffix("FOR UPDATE").
+		RunWith(tx).QueryRowContext(ctx).Scan(&orderID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, translateError(err, "purchase_order")
+	}
+	receipts, invoices, err := r.countDocuments(ctx, tx, orgID, order.ID)
+	if err != nil {
+		return false, err
+	}
+	if receipts > 0 || invoices > 0 {
+		return false, nil
+	}
+
+	var number int
+	err = r.updateDetails(order).
+		Set("status", models.PurchaseOrderStatusDraft).
+		Set("revision", sq.Expr("revision + 1")).
+		Set("submitted_at", nil).
+		Set("approved_at", nil).
+		Set("approved_by", nil).
+		Set("sent_at", nil).
+		Set("acknowledged_at", nil).
+		Set("vendor_note", "").
+		Set("rejected_at", nil).
+		Set("rejection_reason", "").
+		Where(sq.Eq{"id": order.ID, "organization_id": orgID, "status": from}).
+		Suffix("RETURNING revision").
+		RunWith(tx).QueryRowContext(ctx).Scan(&number)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, translateError(err, "purchase_order")
+	}
+	if err := r.replaceLines(ctx, tx, order.ID, order.Lines); err != nil {
+		return false, err
+	}
+
+	query := r.SQLBuilder.
+		Insert("purchase_order_revisions").
+		Columns("purchase_order_id", "revision", "reason", "snapshot", "changed_by").
+		Values(order.ID, number, revision.Reason, string(snapshot), revision.ChangedBy)
+
+	if _, err := query.RunWith(tx).ExecContext(ctx); err != nil {
+		return false, translateError(err, "purchase_order")
+	}
+
+	return true, tx.Commit()
+}
+
+// Method to Count the goods receipts and invoices of a PurchaseOrder
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the purchase order.
+// returns:
+// 		int: the number of goods receipts recorded against the order.
+// 		int: the number of invoices submitted against the order.
+// 		errors: if any occurred during the operation.
+func (r *PurchaseOrderRepository) CountDocuments(ctx context.Context, id string) (int, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return r.countDocuments(ctx, r.db, orgID, id)
+}
+
+// Method to List the change orders of a PurchaseOrder, oldest first
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the purchase order.
+// returns:
+// 		[]PurchaseOrderRevision: the revisions with the snapshot of the order before each change.
+// 		errors: if any occurred during the operation.
+func (r *PurchaseOrderRepository) ListRevisions(ctx context.Context, id string) ([]*models.PurchaseOrderRevision, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select("rev.id", "rev.purchase_order_id", "rev.revision", "rev.reason", "rev.snapshot", "rev.changed_by", "rev.changed_at").
+		From("purchase_order_revisions rev").
+		Join("purchase_orders po ON po.id = rev.purchase_order_id").
+		Where(sq.Eq{"rev.purchase_order_id": id, "po.organization_id": orgID}).
+		OrderBy("rev.revision")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "purchase_order")
+	}
+	defer rows.Close()
+
+	var revisions []*models.PurchaseOrderRevision
+	for rows.Next() {
+		var revision models.PurchaseOrderRevision
+		var snapshot []byte
+		err := rows.Scan(
+			&revision.ID,
+			&revision.PurchaseOrderID,
+			&revision.Revision,
+			&revision.Reason,
+			&snapshot,
+			&revision.ChangedBy,
+			&revision.ChangedAt,
+		)
+		if err != nil {
+			return nil, translateError(err, "purchase_order")
+		}
+		if err := json.Unmarshal(snapshot, &revision.Snapshot); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &revision)
+	}
+	return revisions, translateError(rows.Err(), "purchase_order")
+}
+
+// setTransition sets the status of a purchase order update and the fields the transition carries
+func setTransition(query sq.UpdateBuilder, transition *models.PurchaseOrderTransition) sq.UpdateBuilder {
+	query = query.Set("status", transition.Status)
+	if transition.SubmittedAt != nil {
+		query = query.Set("submitted_at", transition.SubmittedAt)
+	}
+	if transition.ApprovedAt != nil {
+		query = query.Set("approved_at", transition.ApprovedAt).Set("approved_by", transition.ApprovedBy)
+	}
+	if transition.SentAt != nil {
+		query = query.Set("sent_at", transition.SentAt)
+	}
+	if transition.AcknowledgedAt != nil {
+		query = query.Set("acknowledged_at", transition.AcknowledgedAt)
+	}
+	if transition.VendorNote != nil {
+		query = query.Set("vendor_note", *transition.VendorNote)
+	}
+	if transition.RejectedAt != nil {
+		query = query.Set("rejected_at", transition.RejectedAt)
+	}
+	if transition.RejectionReason != nil {
+		query = query.Set("rejection_reason", *transition.RejectionReason)
+	}
+	if transition.ClosedAt != nil {
+		query = query.Set("closed_at", transition.ClosedAt)
+	}
+	if transition.CancelledAt != nil {
+		query = query.Set("cancelled_at", transition.CancelledAt)
+	}
+	return query
+}
+
+// updateDetails sets the changeable header fields of an order
+func (r *PurchaseOrderRepository) updateDetails(order *models.PurchaseOrder) sq.UpdateBuilder {
+	return r.SQLBuilder.
+		Update("purchase_orders").
+		Set("ship_to", order.ShipTo).
+		Set("payment_terms", order.PaymentTerms).
+		Set("delivery_date", order.DeliveryDate).
+		Set("notes", order.Notes).
+		Set("subtotal", order.Subtotal).
+		Set("tax_amount", order.TaxAmount).
+		Set("total_amount", order.TotalAmount)
+}
+
+// countDocuments counts the goods receipts and invoices of an order through runner
+func (r *PurchaseOrderRepository) countDocuments(ctx context.Context, runner sq.BaseRunner, orgID, id string) (int, int, error) {
+	var receipts, invoices int
+	err := r.SQLBuilder.
+		Select().
+		Column(sq.Expr("(SELECT COUNT(*) FROM goods_receipts WHERE organization_id = ? AND purchase_order_id = ?)", orgID, id)).
+		Column(sq.Expr("(SELECT COUNT(*) FROM invoices WHERE organization_id = ? AND purchase_order_id = ?)", orgID, id)).
+		RunWith(runner).QueryRowContext(ctx).Scan(&receipts, &invoices)
+	if err != nil {
+		return 0, 0, translateError(err, "purchase_order")
+	}
+	return receipts, invoices, nil
+}
+
+// replaceLines rewrites the lines of an order
+func (r *PurchaseOrderRepository) replaceLines(ctx context.Context, tx *sql.Tx, orderID string, lines []*models.PurchaseOrderLine) error {
+	_, err := r.SQLBuilder.
+		Delete("purchase_order_lines").
+		Where(sq.Eq{"purchase_order_id": orderID}).
+		RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return translateError(err, "purchase_order")
+	}
+	return r.insertLines(ctx, tx, orderID, lines)
+}
+
+// insertLines stores the lines of an order numbered from 1
+func (r *PurchaseOrderRepository) insertLines(ctx context.Context, tx *sql.Tx, orderID string, lines []*models.PurchaseOrderLine) error {
+	for i, line := range lines {
+		query := r.SQLBuilder.
+			Insert("purchase_order_lines").
+			Columns("purchase_order_id", "line_no", "product_id", "description", "quantity", "unit", "unit_price", "amount",
+				"tax_rate", "tax_amount").
+			Values(orderID, i+1, line.ProductID, line.Description, line.Quantity, line.Unit, line.UnitPrice, line.Amount,
+				line.TaxRate, line.TaxAmount)
+
+		if _, err := query.RunWith(tx).ExecContext(ctx); err != nil {
+			return translateError(err, "purchase_order")
+		}
+	}
+	return nil
+}
+
+// lines returns the lines of an order ordered by line number
+func (r *PurchaseOrderRepository) lines(ctx context.Context, orderID string) ([]*models.PurchaseOrderLine, error) {
+	query := r.SQLBuilder.
+		Select("id", "line_no", "product_id", "description", "quantity", "unit", "unit_price", "amount", "tax_rate", "tax_amount").
+		From("purchase_order_lines").
+		Where(sq.Eq{"purchase_order_id": orderID}).
+		OrderBy("line_no")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "purchase_order")
+	}
+	defer rows.Close()
+
+	var lines []*models.PurchaseOrderLine
+	for rows.Next() {
+		var line models.PurchaseOrderLine
+		err := rows.Scan(
+			&line.ID,
+			&line.LineNo,
+			&line.ProductID,
+			&line.Description,
+			&line.Quantity,
+			&line.Unit,
+			&line.UnitPrice,
+			&line.Amount,
+			&line.TaxRate,
+			&line.TaxAmount,
+		)
+		if err != nil {
+			return nil, translateError(err, "purchase_order")
+		}
+		lines = append(lines, &line)
+	}
+	return lines, translateError(rows.Err(), "purchase_order")
+}
+
+func (r *PurchaseOrderRepository) scan(row sq.RowScanner) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := row.Scan(
+		&order.ID,
+		&order.OrganizationID,
+		&order.Number,
+		&order.Revision,
+		&order.VendorID,
+		&order.VendorName,
+		&order.RFQID,
+		&order.AwardID,
+		&order.Status,
+		&order.ShipTo,
+		&order.PaymentTerms,
+		&order.DeliveryDate,
+		&order.Notes,
+		&order.Subtotal,
+		&order.TaxAmount,
+		&order.TotalAmount,
+		&order.CreatedBy,
+		&order.SubmittedAt,
+		&order.ApprovedAt,
+		&order.ApprovedBy,
+		&order.SentAt,
+		&order.AcknowledgedAt,
+		&order.VendorNote,
+		&order.RejectedAt,
+		&order.RejectionReason,
+		&order.ClosedAt,
+		&order.CancelledAt,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const quotationColumns = "q.id, q.organization_id, q.rfq_id, q.vendor_id, v.vendor_name, q.notes, q.total_amount, " +
+	"q.submitted_at, q.created_at"
+
+type QuotationRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.QuotationRepository = (*QuotationRepository)(nil)
+
+// NewQuotationRepository creates a new instance of QuotationRepository with the provided database connection.
+func NewQuotationRepository(db *sql.DB) *QuotationRepository {
+	return &QuotationRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Save the Quotation of a vendor for an RFQ
+// The RFQ row is locked so the quotation cannot slip in after it closed, an earlier
+// quotation of the vendor is replaced together with its lines.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		quotation: the RFQ, vendor and quoted lines.
+// returns:
+// 		bool: false when the RFQ is not open or its deadline has passed.
+// 		errors: invalid reference when the vendor is not invited or a line is not part of the RFQ.
+func (r *QuotationRepository) Save(ctx context.Context, quotation *models.Quotation) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var open bool
+	err = r.SQLBuilder.
+		Select().
+		Column("status = ? AND response_deadline > NOW()", models.RFQStatusOpen).
+		From("rfqs").
+		Where(sq.Eq{"id": quotation.RFQID, "organization_id": orgID}).
+		Suffix("FOR SHARE").
+		RunWith(tx).QueryRowContext(ctx).Scan(&open)
+	if err != nil && err != sql.ErrNoRows {
+		return false, translateError(err, "quotation")
+	}
+	if !open {
+		return false, nil
+	}
+
+	var quotationID string
+	err = r.SQLBuilder.
+		Insert("quotations").
+		Columns("organization_id", "rfq_id", "vendor_id", "notes", "total_amount").
+		Values(orgID, quotation.RFQID, quotation.VendorID, quotation.Notes, quotation.TotalAmount).
+		Suffix("ON CONFLICT (rfq_id, vendor_id) DO UPDATE SET notes = EXCLUDED.notes, " +
+			"total_amount = EXCLUDED.total_amount, submitted_at = NOW() RETURNING id").
+		RunWith(tx).QueryRowContext(ctx).Scan(&quotationID)
+	if err != nil {
+		return false, translateError(err, "quotation")
+	}
+
+	_, err = r.SQLBuilder.
+		Delete("quotation_lines").
+		Where(sq.Eq{"quotation_id": quotationID}).
+		RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "quotation")
+	}
+	for _, line := range quotation.Lines {
+		query := r.SQLBuilder.
+			Insert("quotation_lines").
+			Columns("quotation_id", "rfq_line_id", "unit_price", "amount", "lead_time_days", "valid_until").
+			Values(quotationID, line.RFQLineID, line.UnitPrice, line.Amount, line.LeadTimeDays, line.ValidUntil)
+
+		if _, err := query.RunWith(tx).ExecContext(ctx); err != nil {
+			return false, translateError(err, "quotation")
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// Method to Get the Quotation of a vendor for an RFQ with its lines
+// It returns nil when the vendor has not submitted a quotation.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		rfqID: ID of the RFQ.
+// 		vendorID: ID of the vendor.
+// returns:
+// 		Quotation: the quotation with its lines.
+// 		errors: if any occurred during the operation.
+func (r *QuotationRepository) GetByVendor(ctx context.Context, rfqID, vendorID string) (*models.Quotation, error) {
+	quotations, err := r.list(ctx, sq.Eq{"q.rfq_id": rfqID, "q.vendor_id": vendorID})
+	if err != nil || len(quotations) == 0 {
+		return nil, err
+	}
+	return quotations[0], nil
+}
+
+// Method to List the Quotations of an RFQ with their lines
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		rfqID: ID of the RFQ.
+// returns:
+// 		[]Quotation: the quotations ordered by submission time.
+// 		errors: if any occurred during the operation.
+func (r *QuotationRepository) ListByRFQ(ctx context.Context, rfqID string) ([]*models.Quotation, error) {
+	return r.list(ctx, sq.Eq{"q.rfq_id": rfqID})
+}
+
+// list returns the quotations of the tenant matching where, lines included
+func (r *QuotationRepository) list(ctx context.Context, where sq.Eq) ([]*models.Quotation, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	where["q.organization_id"] = orgID
+	query := r.SQLBuilder.
+		Select(quotationColumns).
+		From("quotations q").
+		Join("vendors v ON v.id = q.vendor_id").
+		Where(where).
+		OrderBy("q.submitted_at")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "quotation")
+	}
+	defer rows.Close()
+
+	quotations := []*models.Quotation{}
+	byID := map[string]*models.Quotation{}
+	for rows.Next() {
+		var quotation models.Quotation
+		err := rows.Scan(
+			&quotation.ID,
+			&quotation.OrganizationID,
+			&quotation.RFQID,
+			&quotation.VendorID,
+			&quotation.VendorName,
+			&quotation.Notes,
+			&quotation.TotalAmount,
+			&quotation.SubmittedAt,
+			&quotation.CreatedAt,
+		)
+		if err != nil {
+			return nil, translateError(err, "quotation")
+		}
+		quotations = append(quotations, &quotation)
+		byID[quotation.ID] = &quotation
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err, "quotation")
+	}
+	if len(quotations) == 0 {
+		return quotations, nil
+	}
+
+	ids := make([]string, 0, len(quotations))
+	for _, quotation := range quotations {
+		ids = append(ids, quotation.ID)
+	}
+	lines := r.SQLBuilder.
+		Select("ql.id", "ql.quotation_id", "ql.rfq_line_id", "ql.unit_price", "ql.amount", "ql.lead_time_days", "ql.valid_until").
+		From("quotation_lines ql").
+		Join("rfq_lines rl ON rl.id = ql.rfq_line_id").
+		Where(sq.Eq{"ql.quotation_id": ids}).
+		OrderBy("rl.line_no")
+
+	lineRows, err := lines.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "quotation")
+	}
+	defer lineRows.Close()
+
+	for lineRows.Next() {
+		var line models.QuotationLine
+		var quotationID string
+		err := lineRows.Scan(
+			&line.ID,
+			&quotationID,
+			&line.RFQLineID,
+			&line.UnitPrice,
+			&line.Amount,
+			&line.LeadTimeDays,
+			&line.ValidUntil,
+		)
+		if err != nil {
+			return nil, translateError(err, "quotation")
+		}
+		byID[quotationID].Lines = append(byID[quotationID].Lines, &line)
+	}
+	return quotations, translateError(lineRows.Err(), "quotation")
+}
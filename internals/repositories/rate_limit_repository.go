@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/repository"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type RateLimitRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.RateLimitRepository = (*RateLimitRepository)(nil)
+
+// NewRateLimitRepository creates a new instance of RateLimitRepository with the provided database connection.
+func NewRateLimitRepository(db *sql.DB) *RateLimitRepository {
+	return &RateLimitRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Record a rate limited request
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		scope: the limited action, e.g. password_reset.
+// 		subject: who is limited, e.g. a normalized email.
+// returns:
+// 		errors: if any occurred during the operation.
+func (r *RateLimitRepository) Record(ctx context.Context, scope, subject string) error {
+	query := r.SQLBuilder.
+		Insert("rate_limit_events").
+		Columns("scope", "subject").
+		Values(scope, subject)
+
+	_, err := query.RunWith(r.db).ExecContext(ctx)
+	return translateError(err, "rate_limit_event")
+}
+
+// Method to Count the requests of a subject since a point in time
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		scope: the limited action.
+// 		subject: who is limited.
+// 		since: start of the window.
+// returns:
+// 		int: number of recorded requests in the window.
+// 		errors: if any occurred during the operation.
+func (r *RateLimitRepository) CountSince(ctx context.Context, scope, subject string, since time.Time) (int, error) {
+	query := r.SQLBuilder.
+		Select("COUNT(*)").
+		From("rate_limit_events").
+		Where(sq.Eq{"scope": scope, "subject": subject}).
+		Where(sq.GtOrEq{"created_at": since})
+
+	var count int
+	if err := query.RunWith(r.db).QueryRowContext(ctx).Scan(&count); err != nil {
+		return 0, translateError(err, "rate_limit_event")
+	}
+	return count, nil
+}
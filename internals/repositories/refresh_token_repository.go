@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type RefreshTokenRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.RefreshTokenRepository = (*RefreshTokenRepository)(nil)
+
+// NewRefreshTokenRepository creates a new instance of RefreshTokenRepository with the provided database connection.
+func NewRefreshTokenRepository(db *sql.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create New Refresh Token
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		token: refresh token to store, only the hash of the token is persisted.
+// returns:
+// 		RefreshToken: the stored refresh token.
+// 		errors: if any occurred during the operation.
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) (*models.RefreshToken, error) {
+	query := r.SQLBuilder.
+		Insert("refresh_tokens").
+		Columns("user_id", "organization_id", "family_id", "token_hash", "expires_at").
+		Values(token.UserID, token.OrganizationID, token.FamilyID, token.TokenHash, token.ExpiresAt).
+		Suffix("RETURNING id, user_id, organization_id, family_id, token_hash, expires_at, revoked_at, created_at")
+
+	created, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "refresh_token")
+	}
+	return created, nil
+}
+
+// Method to Get Refresh Token By Hash
+// It returns nil when no token matches the hash.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		tokenHash: SHA-256 hash of the refresh token.
+// returns:
+// 		RefreshToken: the stored refresh token.
+// 		errors: if any occurred during the operation.
+func (r *RefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	query := r.SQLBuilder.
+		Select("id", "user_id", "organization_id", "family_id", "token_hash", "expires_at", "revoked_at", "created_at").
+		From("refresh_tokens").
+		Where(sq.Eq{"token_hash": tokenHash}).
+		Limit(1)
+
+	token, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "refresh_token")
+	}
+	return token, nil
+}
+
+// Method to Revoke a Refresh Token
+// It only revokes tokens that are still active, so concurrent rotations of the same token
+// can be detected by the caller.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the refresh token.
+// returns:
+// 		bool: true when this call revoked the token.
+// 		errors: if any occurred during the operation.
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, id string) (bool, error) {
+	query := r.SQLBuilder.
+		Update("refresh_tokens").
+		Set("revoked_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "revoked_at": nil})
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "refresh_token")
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// Method to Revoke every token of a family
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		familyID: family shared by the tokens rotated from a single login.
+// returns:
+// 		errors: if any occurred during the operation.
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	query := r.SQLBuilder.
+		Update("refresh_tokens").
+		Set("revoked_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"family_id": familyID, "revoked_at": nil})
+
+	_, err := query.RunWith(r.db).ExecContext(ctx)
+	return translateError(err, "refresh_token")
+}
+
+// Method to Revoke every token of a user
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		userID: owner of the tokens.
+// returns:
+// 		errors: if any occurred during the operation.
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	query := r.SQLBuilder.
+		Update("refresh_tokens").
+		Set("revoked_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": userID, "revoked_at": nil})
+
+	_, err := query.RunWith(r.db).ExecContext(ctx)
+	return translateError(err, "refresh_token")
+}
+
+func (r *RefreshTokenRepository) scan(row sq.RowScanner) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	var revokedAt sql.NullTime
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.OrganizationID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&revokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return &token, nil
+}
@@ -0,0 +1,315 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const requisitionColumns = "id, organization_id, requester_id, department_id, cost_center_id, title, justification, " +
+	"status, total_amount, submitted_at, decided_at, decided_by, decision_comment, created_at, updated_at"
+
+type RequisitionRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.RequisitionRepository = (*RequisitionRepository)(nil)
+
+// NewRequisitionRepository creates a new instance of RequisitionRepository with the provided database connection.
+func NewRequisitionRepository(db *sql.DB) *RequisitionRepository {
+	return &RequisitionRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create New Purchase Requisition with its lines
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		requisition: header and lines, the status and total are stored as given.
+// returns:
+// 		PurchaseRequisition: the stored requisition with its lines.
+// 		errors: invalid reference when the department, cost center or product does not exist.
+func (r *RequisitionRepository) Create(ctx context.Context, requisition *models.PurchaseRequisition) (*models.PurchaseRequisition, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := r.SQLBuilder.
+		Insert("purchase_requisitions").
+		Columns("organization_id", "requester_id", "department_id", "cost_center_id", "title", "justification", "status", "total_amount").
+		Values(orgID, requisition.RequesterID, requisition.DepartmentID, requisition.CostCenterID,
+			requisition.Title, requisition.Justification, requisition.Status, requisition.TotalAmount).
+		Suffix("RETURNING " + requisitionColumns)
+
+	created, err := r.scan(query.RunWith(tx).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "requisition")
+	}
+	if created.Lines, err = r.insertLines(ctx, tx, created.ID, requisition.Lines); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Method to Get Purchase Requisition By ID with its lines
+// It returns nil when the requisition does not exist in the tenant.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the requisition.
+// returns:
+// 		PurchaseRequisition: the requisition with its lines ordered by line number.
+// 		errors: if any occurred during the operation.
+func (r *RequisitionRepository) GetByID(ctx context.Context, id string) (*models.PurchaseRequisition, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(requisitionColumns).
+		From("purchase_requisitions").
+		Where(sq.Eq{"id": id, "organization_id": orgID})
+
+	requisition, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "requisition")
+	}
+
+	lines := r.SQLBuilder.
+		Select("id", "line_no", "product_id", "description", "quantity", "unit", "unit_price", "needed_by").
+		From("purchase_requisition_lines").
+		Where(sq.Eq{"requisition_id": id}).
+		OrderBy("line_no")
+
+	rows, err := lines.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "requisition")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line models.PurchaseRequisitionLine
+		err := rows.Scan(
+			&line.ID,
+			&line.LineNo,
+			&line.ProductID,
+			&line.Description,
+			&line.Quantity,
+			&line.Unit,
+			&line.UnitPrice,
+			&line.NeededBy,
+		)
+		if err != nil {
+			return nil, translateError(err, "requisition")
+		}
+		line.Amount = line.Quantity * line.UnitPrice
+		requisition.Lines = append(requisition.Lines, &line)
+	}
+	return requisition, translateError(rows.Err(), "requisition")
+}
+
+// Method to List Purchase Requisitions of the tenant, newest first
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		filter: optional status, requester and department.
+// 		limit: maximum number of requisitions to return.
+// 		offset: number of requisitions to skip.
+// returns:
+// 		[]PurchaseRequisition: the requisition headers without lines.
+// 		int: total number of requisitions matching the filter.
+// 		errors: if any occurred during the operation.
+func (r *RequisitionRepository) List(ctx context.Context, filter models.RequisitionFilter, limit, offset int) ([]*models.PurchaseRequisition, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	where := sq.Eq{"organization_id": orgID}
+	if filter.Status != "" {
+		where["status"] = filter.Status
+	}
+	if filter.RequesterID != "" {
+		where["requester_id"] = filter.RequesterID
+	}
+	if filter.DepartmentID != "" {
+		where["department_id"] = filter.DepartmentID
+	}
+
+	var count int
+	countQuery := r.SQLBuilder.Select("COUNT(*)").From("purchase_requisitions").Where(where)
+	if err := countQuery.RunWith(r.db).QueryRowContext(ctx).Scan(&count); err != nil {
+		return nil, 0, translateError(err, "requisition")
+	}
+
+	query := r.SQLBuilder.
+		Select(requisitionColumns).
+		From("purchase_requisitions").
+		Where(where).
+		OrderBy("created_at DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, 0, translateError(err, "requisition")
+	}
+	defer rows.Close()
+
+	var requisitions []*models.PurchaseRequisition
+	for rows.Next() {
+		requisition, err := r.scan(rows)
+		if err != nil {
+			return nil, 0, translateError(err, "requisition")
+		}
+		requisitions = append(requisitions, requisition)
+	}
+	return requisitions, count, translateError(rows.Err(), "requisition")
+}
+
+// Method to Update a draft Purchase Requisition
+// The header fields are replaced and the lines are rewritten in one transaction.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		requisition: the requisition with its new values and lines.
+// returns:
+// 		bool: false when the requisition is not a draft (anymore).
+// 		errors: invalid reference when the department, cost center or product does not exist.
+func (r *RequisitionRepository) UpdateDraft(ctx context.Context, requisition *models.PurchaseRequisition) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := r.SQLBuilder.
+		Update("purchase_requisitions").
+		Set("department_id", requisition.DepartmentID).
+		Set("cost_center_id", requisition.CostCenterID).
+		Set("title", requisition.Title).
+		Set("justification", requisition.Justification).
+		Set("total_amount", requisition.TotalAmount).
+		Where(sq.Eq{"id": requisition.ID, "organization_id": orgID, "status": models.RequisitionStatusDraft})
+
+	result, err := query.RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "requisition")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+
+	_, err = r.SQLBuilder.
+		Delete("purchase_requisition_lines").
+		Where(sq.Eq{"requisition_id": requisition.ID}).
+		RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "requisition")
+	}
+	if _, err := r.insertLines(ctx, tx, requisition.ID, requisition.Lines); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// Method to Transition a Purchase Requisition to another status
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the requisition.
+// 		from: the status the requisition is expected to be in.
+// 		transition: the new status with the submission or decision details.
+// returns:
+// 		bool: false when the requisition is not in status `from` (anymore).
+// 		errors: if any occurred during the operation.
+func (r *RequisitionRepository) Transition(ctx context.Context, id, from string, transition *models.RequisitionTransition) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	query := r.SQLBuilder.
+		Update("purchase_requisitions").
+		Set("status", transition.Status).
+		Where(sq.Eq{"id": id, "organization_id": orgID, "status": from})
+	if transition.SubmittedAt != nil {
+		query = query.Set("submitted_at", transition.SubmittedAt)
+	}
+	if transition.DecidedAt != nil {
+		query = query.
+			Set("decided_at", transition.DecidedAt).
+			Set("decided_by", transition.DecidedBy).
+			Set("decision_comment", transition.DecisionComment)
+	}
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "requisition")
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// insertLines stores the lines of a requisition numbered from 1
+func (r *RequisitionRepository) insertLines(ctx context.Context, tx *sql.Tx, requisitionID string, lines []*models.PurchaseRequisitionLine) ([]*models.PurchaseRequisitionLine, error) {
+	stored := make([]*models.PurchaseRequisitionLine, 0, len(lines))
+	for i, line := range lines {
+		query := r.SQLBuilder.
+			Insert("purchase_requisition_lines").
+			Columns("requisition_id", "line_no", "product_id", "description", "quantity", "unit", "unit_price", "needed_by").
+			Values(requisitionID, i+1, line.ProductID, line.Description, line.Quantity, line.Unit, line.UnitPrice, line.NeededBy).
+			Suffix("RETURNING id")
+
+		copied := *line
+		copied.LineNo = i + 1
+		copied.Amount = line.Quantity * line.UnitPrice
+		if err := query.RunWith(tx).QueryRowContext(ctx).Scan(&copied.ID); err != nil {
+			return nil, translateError(err, "requisition")
+		}
+		stored = append(stored, &copied)
+	}
+	return stored, nil
+}
+
+func (r *RequisitionRepository) scan(row sq.RowScanner) (*models.PurchaseRequisition, error) {
+	var requisition models.PurchaseRequisition
+	err := row.Scan(
+		&requisition.ID,
+		&requisition.OrganizationID,
+		&requisition.RequesterID,
+		&requisition.DepartmentID,
+		&requisition.CostCenterID,
+		&requisition.Title,
+		&requisition.Justification,
+		&requisition.Status,
+		&requisition.TotalAmount,
+		&requisition.SubmittedAt,
+		&requisition.DecidedAt,
+		&requisition.DecidedBy,
+		&requisition.DecisionComment,
+		&requisition.CreatedAt,
+		&requisition.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &requisition, nil
+}
@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const rfqAwardColumns = "id, organization_id, rfq_id, mode, justification, total_amount, awarded_by, awarded_at"
+
+type RFQAwardRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.RFQAwardRepository = (*RFQAwardRepository)(nil)
+
+// NewRFQAwardRepository creates a new instance of RFQAwardRepository with the provided database connection.
+func NewRFQAwardRepository(db *sql.DB) *RFQAwardRepository {
+	return &RFQAwardRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create the RFQAward of an RFQ with its lines
+// The RFQ moves from open to awarded in the same transaction, so an RFQ is awarded once.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		award: the decision and the awarded lines.
+// returns:
+// 		bool: false when the RFQ is not open (anymore).
+// 		errors: invalid reference when a line, quotation or vendor does not exist.
+func (r *RFQAwardRepository) Create(ctx context.Context, award *models.RFQAward) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := r.SQLBuilder.
+		Update("rfqs").
+		Set("status", models.RFQStatusAwarded).
+		Where(sq.Eq{"id": award.RFQID, "organization_id": orgID, "status": models.RFQStatusOpen}).
+		RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "rfq_award")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+
+	var awardID string
+	err = r.SQLBuilder.
+		Insert("rfq_awards").
+		Columns("organization_id", "rfq_id", "mode", "justification", "total_amount", "awarded_by").
+		Values(orgID, award.RFQID, award.Mode, award.Justification, award.TotalAmount, award.AwardedBy).
+		Suffix("RETURNING id").
+		RunWith(tx).QueryRowContext(ctx).Scan(&awardID)
+	if err != nil {
+		return false, translateError(err, "rfq_award")
+	}
+	for _, line := range award.Lines {
+		query := r.SQLBuilder.
+			Insert("rfq_award_lines").
+			Columns("award_id", "rfq_line_id", "quotation_id", "vendor_id", "quantity", "unit_price", "amount", "score").
+			Values(awardID, line.RFQLineID, line.QuotationID, line.VendorID, line.Quantity, line.UnitPrice, line.Amount, line.Score)
+
+		if _, err := query.RunWith(tx).ExecContext(ctx); err != nil {
+			return false, translateError(err, "rfq_award")
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// Method to Get the RFQAward of an RFQ with its lines
+// It returns nil when the RFQ is not awarded.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		rfqID: ID of the RFQ.
+// returns:
+// 		RFQAward: the award with its lines in RFQ line order.
+// 		errors: if any occurred during the operation.
+func (r *RFQAwardRepository) GetByRFQ(ctx context.Context, rfqID string) (*models.RFQAward, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(rfqAwardColumns).
+		From("rfq_awards").
+		Where(sq.Eq{"rfq_id": rfqID, "organization_id": orgID})
+
+	award, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "rfq_award")
+	}
+	if award.Lines, err = r.lines(ctx, award.ID); err != nil {
+		return nil, err
+	}
+	return award, nil
+}
+
+// lines returns the lines of an award ordered by RFQ line number
+func (r *RFQAwardRepository) lines(ctx context.Context, awardID string) ([]*models.RFQAwardLine, error) {
+	query := r.SQLBuilder.
+		Select("al.id", "al.rfq_line_id", "al.quotation_id", "al.vendor_id", "v.vendor_name",
+			"al.quantity", "al.unit_price", "al.amount", "al.score").
+		From("rfq_award_lines al").
+		Join("rfq_lines rl ON rl.id = al.rfq_line_id").
+		Join("vendors v ON v.id = al.vendor_id").
+		Where(sq.Eq{"al.award_id": awardID}).
+		OrderBy("rl.line_no")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "rfq_award")
+	}
+	defer rows.Close()
+
+	var lines []*models.RFQAwardLine
+	for rows.Next() {
+		var line models.RFQAwardLine
+		err := rows.Scan(
+			&line.ID,
+			&line.RFQLineID,
+			&line.QuotationID,
+			&line.VendorID,
+			&line.VendorName,
+			&line.Quantity,
+			&line.UnitPrice,
+			&line.Amount,
+			&line.Score,
+		)
+		if err != nil {
+			return nil, translateError(err, "rfq_award")
+		}
+		lines = append(lines, &line)
+	}
+	return lines, translateError(rows.Err(), "rfq_award")
+}
+
+func (r *RFQAwardRepository) scan(row sq.RowScanner) (*models.RFQAward, error) {
+	var award models.RFQAward
+	err := row.Scan(
+		&award.ID,
+		&award.OrganizationID,
+		&award.RFQID,
+		&award.Mode,
+		&award.Justification,
+		&award.TotalAmount,
+		&award.AwardedBy,
+		&award.AwardedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &award, nil
+}
@@ -0,0 +1,389 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const rfqColumns = "id, organization_id, requisition_id, title, description, status, response_deadline, " +
+	"created_by, published_at, created_at, updated_at"
+
+type RFQRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.RFQRepository = (*RFQRepository)(nil)
+
+// NewRFQRepository creates a new instance of RFQRepository with the provided database connection.
+func NewRFQRepository(db *sql.DB) *RFQRepository {
+	return &RFQRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create New RFQ with its lines and invited vendors
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		rfq: header, lines and invited vendors, the status is stored as given.
+// returns:
+// 		RFQ: the stored RFQ with its lines and vendors.
+// 		errors: invalid reference when the requisition, product or a vendor does not exist.
+func (r *RFQRepository) Create(ctx context.Context, rfq *models.RFQ) (*models.RFQ, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := r.SQLBuilder.
+		Insert("rfqs").
+		Columns("organization_id", "requisition_id", "title", "description", "status", "response_deadline", "created_by").
+		Values(orgID, rfq.RequisitionID, rfq.Title, rfq.Description, rfq.Status, rfq.ResponseDeadline, rfq.CreatedBy).
+		Suffix("RETURNING " + rfqColumns)
+
+	created, err := r.scan(query.RunWith(tx).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "rfq")
+	}
+	if err := r.insertLines(ctx, tx, created.ID, rfq.Lines); err != nil {
+		return nil, err
+	}
+	if err := r.insertVendors(ctx, tx, orgID, created.ID, vendorIDs(rfq.Vendors)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, created.ID)
+}
+
+// Method to Get RFQ By ID with its lines and invited vendors
+// It returns nil when the RFQ does not exist in the tenant.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the RFQ.
+// returns:
+// 		RFQ: the RFQ with its lines ordered by line number and its vendors ordered by name.
+// 		errors: if any occurred during the operation.
+func (r *RFQRepository) GetByID(ctx context.Context, id string) (*models.RFQ, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := r.SQLBuilder.
+		Select(rfqColumns).
+		From("rfqs").
+		Where(sq.Eq{"id": id, "organization_id": orgID})
+
+	rfq, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "rfq")
+	}
+	if rfq.Lines, err = r.lines(ctx, id); err != nil {
+		return nil, err
+	}
+	if rfq.Vendors, err = r.vendors(ctx, id); err != nil {
+		return nil, err
+	}
+	return rfq, nil
+}
+
+// Method to List RFQs of the tenant, newest first
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		filter: optional status and invited vendor, drafts are left out for a vendor.
+// 		limit: maximum number of RFQs to return.
+// 		offset: number of RFQs to skip.
+// returns:
+// 		[]RFQ: the RFQ headers without lines and vendors.
+// 		int: total number of RFQs matching the filter.
+// 		errors: if any occurred during the operation.
+func (r *RFQRepository) List(ctx context.Context, filter models.RFQFilter, limit, offset int) ([]*models.RFQ, int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	where := sq.And{sq.Eq{"organization_id": orgID}}
+	if filter.Status != "" {
+		where = append(where, sq.Eq{"status": filter.Status})
+	}
+	if filter.VendorID != "" {
+		where = append(where,
+			sq.NotEq{"status": models.RFQStatusDraft},
+			sq.Expr("id IN (SELECT rfq_id FROM rfq_vendors WHERE vendor_id = ?)", filter.VendorID))
+	}
+
+	var count int
+	countQuery := r.SQLBuilder.Select("COUNT(*)").From("rfqs").Where(where)
+	if err := countQuery.RunWith(r.db).QueryRowContext(ctx).Scan(&count); err != nil {
+		return nil, 0, translateError(err, "rfq")
+	}
+
+	query := r.SQLBuilder.
+		Select(rfqColumns).
+		From("rfqs").
+		Where(where).
+		OrderBy("created_at DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, 0, translateError(err, "rfq")
+	}
+	defer rows.Close()
+
+	var rfqs []*models.RFQ
+	for rows.Next() {
+		rfq, err := r.scan(rows)
+		if err != nil {
+			return nil, 0, translateError(err, "rfq")
+		}
+		rfqs = append(rfqs, rfq)
+	}
+	return rfqs, count, translateError(rows.Err(), "rfq")
+}
+
+// Method to Update a draft RFQ
+// The header fields are replaced and the lines and vendors are rewritten in one transaction.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		rfq: the RFQ with its new values, lines and vendors.
+// returns:
+// 		bool: false when the RFQ is not a draft (anymore).
+// 		errors: invalid reference when the requisition, product or a vendor does not exist.
+func (r *RFQRepository) UpdateDraft(ctx context.Context, rfq *models.RFQ) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := r.SQLBuilder.
+		Update("rfqs").
+		Set("requisition_id", rfq.RequisitionID).
+		Set("title", rfq.Title).
+		Set("description", rfq.Description).
+		Set("response_deadline", rfq.ResponseDeadline).
+		Where(sq.Eq{"id": rfq.ID, "organization_id": orgID, "status": models.RFQStatusDraft})
+
+	result, err := query.RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "rfq")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+
+	// a draft has no quotations yet, so its lines and vendors can be rewritten
+	for _, table := range []string{"rfq_lines", "rfq_vendors"} {
+		_, err = r.SQLBuilder.
+			Delete(table).
+			Where(sq.Eq{"rfq_id": rfq.ID}).
+			RunWith(tx).ExecContext(ctx)
+		if err != nil {
+			return false, translateError(err, "rfq")
+		}
+	}
+	if err := r.insertLines(ctx, tx, rfq.ID, rfq.Lines); err != nil {
+		return false, err
+	}
+	if err := r.insertVendors(ctx, tx, orgID, rfq.ID, vendorIDs(rfq.Vendors)); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// Method to Transition an RFQ to another status
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the RFQ.
+// 		from: the status the RFQ is expected to be in.
+// 		transition: the new status with the publication time.
+// returns:
+// 		bool: false when the RFQ is not in status `from` (anymore).
+// 		errors: if any occurred during the operation.
+func (r *RFQRepository) Transition(ctx context.Context, id, from string, transition *models.RFQTransition) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	query := r.SQLBuilder.
+		Update("rfqs").
+		Set("status", transition.Status).
+		Where(sq.Eq{"id": id, "organization_id": orgID, "status": from})
+	if transition.PublishedAt != nil {
+		query = query.Set("published_at", transition.PublishedAt)
+	}
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "rfq")
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// Method to Invite more vendors to an RFQ
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the RFQ.
+// 		vendorIDs: the vendors to invite, vendors already invited are skipped.
+// returns:
+// 		errors: invalid reference when a vendor does not exist in the tenant.
+func (r *RFQRepository) InviteVendors(ctx context.Context, id string, vendorIDs []string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.insertVendors(ctx, tx, orgID, id, vendorIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertLines stores the lines of an RFQ numbered from 1
+func (r *RFQRepository) insertLines(ctx context.Context, tx *sql.Tx, rfqID string, lines []*models.RFQLine) error {
+	for i, line := range lines {
+		query := r.SQLBuilder.
+			Insert("rfq_lines").
+			Columns("rfq_id", "line_no", "product_id", "description", "quantity", "unit", "needed_by").
+			Values(rfqID, i+1, line.ProductID, line.Description, line.Quantity, line.Unit, line.NeededBy)
+
+		if _, err := query.RunWith(tx).ExecContext(ctx); err != nil {
+			return translateError(err, "rfq")
+		}
+	}
+	return nil
+}
+
+// insertVendors invites the vendors, vendors already invited are skipped
+func (r *RFQRepository) insertVendors(ctx context.Context, tx *sql.Tx, orgID, rfqID string, vendorIDs []string) error {
+	for _, vendorID := range vendorIDs {
+		query := r.SQLBuilder.
+			Insert("rfq_vendors").
+			Columns("organization_id", "rfq_id", "vendor_id").
+			Values(orgID, rfqID, vendorID).
+			Suffix("ON CONFLICT (rfq_id, vendor_id) DO NOTHING")
+
+		if _, err := query.RunWith(tx).ExecContext(ctx); err != nil {
+			return translateError(err, "rfq")
+		}
+	}
+	return nil
+}
+
+// lines returns the lines of an RFQ ordered by line number
+func (r *RFQRepository) lines(ctx context.Context, rfqID string) ([]*models.RFQLine, error) {
+	query := r.SQLBuilder.
+		Select("id", "line_no", "product_id", "description", "quantity", "unit", "needed_by").
+		From("rfq_lines").
+		Where(sq.Eq{"rfq_id": rfqID}).
+		OrderBy("line_no")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "rfq")
+	}
+	defer rows.Close()
+
+	var lines []*models.RFQLine
+	for rows.Next() {
+		var line models.RFQLine
+		err := rows.Scan(
+			&line.ID,
+			&line.LineNo,
+			&line.ProductID,
+			&line.Description,
+			&line.Quantity,
+			&line.Unit,
+			&line.NeededBy,
+		)
+		if err != nil {
+			return nil, translateError(err, "rfq")
+		}
+		lines = append(lines, &line)
+	}
+	return lines, translateError(rows.Err(), "rfq")
+}
+
+// vendors returns the invited vendors of an RFQ ordered by name
+func (r *RFQRepository) vendors(ctx context.Context, rfqID string) ([]*models.RFQVendor, error) {
+	query := r.SQLBuilder.
+		Select("rv.vendor_id", "v.vendor_name", "rv.invited_at").
+		From("rfq_vendors rv").
+		Join("vendors v ON v.id = rv.vendor_id").
+		Where(sq.Eq{"rv.rfq_id": rfqID}).
+		OrderBy("v.vendor_name")
+
+	rows, err := query.RunWith(r.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "rfq")
+	}
+	defer rows.Close()
+
+	var vendors []*models.RFQVendor
+	for rows.Next() {
+		var vendor models.RFQVendor
+		if err := rows.Scan(&vendor.VendorID, &vendor.VendorName, &vendor.InvitedAt); err != nil {
+			return nil, translateError(err, "rfq")
+		}
+		vendors = append(vendors, &vendor)
+	}
+	return vendors, translateError(rows.Err(), "rfq")
+}
+
+func (r *RFQRepository) scan(row sq.RowScanner) (*models.RFQ, error) {
+	var rfq models.RFQ
+	err := row.Scan(
+		&rfq.ID,
+		&rfq.OrganizationID,
+		&rfq.RequisitionID,
+		&rfq.Title,
+		&rfq.Description,
+		&rfq.Status,
+		&rfq.ResponseDeadline,
+		&rfq.CreatedBy,
+		&rfq.PublishedAt,
+		&rfq.CreatedAt,
+		&rfq.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rfq, nil
+}
+
+// vendorIDs returns the IDs of the invited vendors
+func vendorIDs(vendors []*models.RFQVendor) []string {
+	ids := make([]string, 0, len(vendors))
+	for _, vendor := range vendors {
+		ids = append(ids, vendor.VendorID)
+	}
+	return ids
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+	customContext "e-procurement/pkg/context"
+)
+
+// tenant returns the organization of the request, every query on tenant owned
+// tables filters on it so one organization never reads another's rows.
+func tenant(ctx context.Context) (string, error) {
+	return customContext.GetTenantFromContext(ctx)
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type TokenRevocationRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.TokenRevocationRepository = (*TokenRevocationRepository)(nil)
+
+// NewTokenRevocationRepository creates a new instance of TokenRevocationRepository with the provided database connection.
+func NewTokenRevocationRepository(db *sql.DB) *TokenRevocationRepository {
+	return &TokenRevocationRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Revoke an Access Token
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		token: the revoked token, identified by its jti claim.
+// returns:
+// 		errors: if any occurred during the operation.
+func (r *TokenRevocationRepository) RevokeToken(ctx context.Context, token *models.RevokedToken) error {
+	query := r.SQLBuilder.
+		Insert("revoked_tokens").
+		Columns("jti", "user_id", "expires_at").
+		Values(token.TokenID, token.UserID, token.ExpiresAt).
+		Suffix("ON CONFLICT (jti) DO NOTHING")
+
+	_, err := query.RunWith(r.db).ExecContext(ctx)
+	return translateError(err, "revoked_token")
+}
+
+// Method to Check whether an Access Token is revoked
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		tokenID: jti claim of the token.
+// returns:
+// 		bool: true when the token was revoked.
+// 		errors: if any occurred during the operation.
+func (r *TokenRevocationRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	query := r.SQLBuilder.
+		Select("1").
+		Prefix("SELECT EXISTS (").
+		From("revoked_tokens").
+		Where(sq.Eq{"jti": tokenID}).
+		Suffix(")")
+
+	var revoked bool
+	if err := query.RunWith(r.db).QueryRowContext(ctx).Scan(&revoked); err != nil {
+		return false, translateError(err, "revoked_token")
+	}
+	return revoked, nil
+}
+
+// Method to Revoke every session of a user
+// The cut-off only moves forward, an older timestamp never re-enables tokens.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		userID: owner of the sessions.
+// 		before: tokens issued at or before this time are rejected.
+// returns:
+// 		errors: if any occurred during the operation.
+func (r *TokenRevocationRepository) RevokeAllForUser(ctx context.Context, userID string, before time.Time) error {
+	query := r.SQLBuilder.
+		Insert("user_session_revocations").
+		Columns("user_id", "revoked_before").
+		Values(userID, before).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET revoked_before = " +
+			"GREATEST(user_session_revocations.revoked_before, EXCLUDED.revoked_before)")
+
+	_, err := query.RunWith(r.db).ExecContext(ctx)
+	return translateError(err, "session_revocation")
+}
+
+// Method to Get the session cut-off of a user
+// It returns nil when the sessions of the user were never revoked.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		userID: owner of the sessions.
+// returns:
+// 		time: tokens issued at or before this time are rejected.
+// 		errors: if any occurred during the operation.
+func (r *TokenRevocationRepository) GetRevokedBefore(ctx context.Context, userID string) (*time.Time, error) {
+	query := r.SQLBuilder.
+		Select("revoked_before").
+		From("user_session_revocations").
+		Where(sq.Eq{"user_id": userID})
+
+	var revokedBefore time.Time
+	err := query.RunWith(r.db).QueryRowContext(ctx).Scan(&revokedBefore)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "session_revocation")
+	}
+	return &revokedBefore, nil
+}
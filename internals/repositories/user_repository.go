@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
 
 	sq "github.com/Masterminds/squirrel"
 )
@@ -13,6 +14,8 @@ type UserRepository struct {
 	SQKBuilder sq.StatementBuilderType
 }
 
+var _ repository.UserRepository = (*UserRepository)(nil)
+
 func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{
 		db:         db,
@@ -23,6 +26,7 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 
 	// Method to fetch all users from the database.
 	// It returns a slice of UserResponse modelss containing user details.
+	// Only members of the organization of the request are returned, with their role in it.
 	// 
 	// parameters:
 	// 		ctx: context for request-scoped values and cancellation.
@@ -33,30 +37,37 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	// 		UserResponse: a slice of UserResponse containing user details.
 	//  	error: if any occurred during the operation.
 func (r *UserRepository) GetAll(ctx context.Context, limit, offset int) ([]models.UserResponse, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := r.SQKBuilder.
-		Select("id, user_name, email, role, created_at, updated_at").
-		From("users").
+		Select("u.id, u.user_name, u.email, m.role, m.organization_id, u.email_verified_at, u.created_at, u.updated_at").
+		From("users u").
+		Join("organization_members m ON m.user_id = u.id").
+		Where(sq.Eq{"m.organization_id": orgID}).
+		OrderBy("u.created_at").
 		Limit(uint64(limit)).
 		Offset(uint64(offset))
 
 	// Execute query
 	rows, err := query.RunWith(r.db).QueryContext(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "user")
 	}
 	defer rows.Close()
 
 	var users []models.UserResponse
 	for rows.Next() {
 		var user models.UserResponse
-		if err := rows.Scan(&user.ID, &user.UserName, &user.Email,&user.Role, &user.CreatedAt, &user.UpdatedAt); err != nil {
-			return nil, err
+		if err := rows.Scan(&user.ID, &user.UserName, &user.Email,&user.Role, &user.OrganizationID, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return nil, translateError(err, "user")
 		}
 		users = append(users, user)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, translateError(err, "user")
 	}
 	
 	return users, nil
@@ -75,21 +86,21 @@ func (r *UserRepository) GetAll(ctx context.Context, limit, offset int) ([]model
 	// 		error : if any occurred during the operation.
 func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*models.User, error) {
 	query := r.SQKBuilder.
-		Select("id, user_name, email, password, role").
-		From("e_procurement.users").
+		Select("id, user_name, email, password, role, email_verified_at").
+		From("users").
 		Where(sq.Eq{"id": id}).
 		Limit(1)
 
 	row := query.RunWith(r.db).QueryRowContext(ctx)
 	var user models.User
 
-	err := row.Scan(&user.ID, &user.UserName, &user.Email, &user.Password, &user.Role)
+	err := row.Scan(&user.ID, &user.UserName, &user.Email, &user.Password, &user.Role, &user.EmailVerifiedAt)
 	if err != nil {
 		
 		if err == sql.ErrNoRows {
 			return nil, nil // No user found, return nil
 		}
-		return nil, err // Return error if any other issue occurred
+		return nil, translateError(err, "user") // Return error if any other issue occurred
 	}
 	
 	return &user, nil
@@ -118,7 +129,7 @@ func (r *UserRepository) Create(ctx context.Context, user *models.CreateUserRequ
 	var userResponse models.User
 	err := row.Scan(&userResponse.ID, &userResponse.UserName, &userResponse.Email,&userResponse.Role, &userResponse.CreatedAt, &userResponse.UpdatedAt)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "user")
 	}
 	
 	return &userResponse, nil
@@ -140,18 +151,20 @@ func (r *UserRepository) UpdateUser(ctx context.Context, id string, user *models
 		Set("user_name", user.UserName).
 		Set("email", user.Email).
 		Set("password", user.Password).
+		// a new email address has to be verified again, SET sees the old email
+		Set("email_verified_at", sq.Expr("CASE WHEN email = ? THEN email_verified_at END", user.Email)).
 		Where(sq.Eq{"id": id}).
-		Suffix("RETURNING id, user_name, email, role, created_at, updated_at")
+		Suffix("RETURNING id, user_name, email, role, email_verified_at, created_at, updated_at")
 
 	row := query.RunWith(r.db).QueryRowContext(ctx)
 
 	var resp models.UserResponse
-	err := row.Scan(&resp.ID, &resp.UserName, &resp.Email,&resp.Role, &resp.CreatedAt, &resp.UpdatedAt)
+	err := row.Scan(&resp.ID, &resp.UserName, &resp.Email,&resp.Role, &resp.EmailVerifiedAt, &resp.CreatedAt, &resp.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // No user found, return nil
 		}
-		return nil, err
+		return nil, translateError(err, "user")
 	}
 	
 	return &resp, nil
@@ -168,7 +181,7 @@ func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	query := r.SQKBuilder.Delete("users").Where(sq.Eq{"id": id})
 	result, err := query.RunWith(r.db).ExecContext(ctx)
 	if err != nil {
-		return err
+		return translateError(err, "user")
 	}
 
 	// Get affected rows count
@@ -176,7 +189,7 @@ func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	
 	
 	if affected == 0 {
-		return sql.ErrNoRows // No user found to delete
+		return translateError(sql.ErrNoRows, "user")
 	}
 	
 	return nil
@@ -192,20 +205,20 @@ func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	//  	error: an error if any occurred during the operation.
 func (r *UserRepository) Authenticate(ctx context.Context, email string) (*models.User, error) {
 	query := r.SQKBuilder.
-		Select("id, user_name, email, password, role").
-		From("e_procurement.users").
+		Select("id, user_name, email, password, role, email_verified_at").
+		From("users").
 		Where(sq.Eq{"email": email}).
 		Limit(1)
 
 	row := query.RunWith(r.db).QueryRowContext(ctx)
 
 	var user models.User
-	err := row.Scan(&user.ID, &user.UserName, &user.Email, &user.Password, &user.Role)
+	err := row.Scan(&user.ID, &user.UserName, &user.Email, &user.Password, &user.Role, &user.EmailVerifiedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
-		return nil, err
+		return nil, translateError(err, "user")
 	}
 
 	return &user, nil
@@ -235,31 +248,35 @@ func (r *UserRepository) IsUserExists(ctx context.Context, email string) (bool,
 		return false, nil
 	}
 	if err != nil {
-		return false, err
+		return false, translateError(err, "user")
 	}
 	return true, nil
 }
 
 	// Method to get the total count of users in the database.
-	// It queries the "users" table to count the total number of users.
+	// It counts the members of the organization of the request.
 	// parameters:
 	// 		ctx: context for request-scoped values and cancellation.
 	// returns:
 	// 		int64: the total count of users.
 	// 		error: error if any occurred during the operation.
 func (r *UserRepository) GetTotalCount(ctx context.Context) (int64, error) {
-
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
 
 	query := r.SQKBuilder.
 		Select("COUNT(*)").
-		From("users")
+		From("organization_members").
+		Where(sq.Eq{"organization_id": orgID})
 
 	row := query.RunWith(r.db).QueryRowContext(ctx)
 
 	var count int64
-	err := row.Scan(&count)
+	err = row.Scan(&count)
 	if err != nil {
-		return 0, err
+		return 0, translateError(err, "user")
 	}
 
 	return count, nil
@@ -281,13 +298,63 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, id string, newPassw
 
 	result, err := query.RunWith(r.db).ExecContext(ctx)
 	if err != nil {
-		return err
+		return translateError(err, "user")
 	}
 	// Get affected rows count
 	affected, _ := result.RowsAffected()
 	if affected == 0 {
-		return sql.ErrNoRows // No user found to update
+		return translateError(sql.ErrNoRows, "user")
+	}
+
+	return nil
+}
+
+// ReplacePasswordHash replaces the password hash of a user while the stored hash is still oldHash,
+// so upgrading a hash on login never overwrites a password changed at the same time.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: the unique identifier of the user.
+// 		oldHash: the hash the password was verified against.
+// 		newHash: the upgraded hash of the same password.
+// returns:
+// 		bool: false when the stored hash is no longer oldHash.
+// 		error: an error if any occurred during the operation.
+func (r *UserRepository) ReplacePasswordHash(ctx context.Context, id, oldHash, newHash string) (bool, error) {
+	query := r.SQKBuilder.
+		Update("users").
+		Set("password", newHash).
+		Where(sq.Eq{"id": id, "password": oldHash})
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "user")
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
 	}
+	return affected > 0, nil
+}
+
+// MarkEmailVerified records that the user opened the verification link.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: the unique identifier of the user.
+// returns:
+// 		error: an error if any occurred during the operation.
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id string) error {
+	query := r.SQKBuilder.
+		Update("users").
+		Set("email_verified_at", sq.Expr("COALESCE(email_verified_at, NOW())")).
+		Where(sq.Eq{"id": id})
 
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return translateError(err, "user")
+	}
+	affected, _ := result.RowsAffected()
+	if affected == 0 {
+		return translateError(sql.ErrNoRows, "user")
+	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type UserTokenRepository struct {
+	db         *sql.DB
+	SQLBuilder sq.StatementBuilderType
+}
+
+var _ repository.UserTokenRepository = (*UserTokenRepository)(nil)
+
+// NewUserTokenRepository creates a new instance of UserTokenRepository with the provided database connection.
+func NewUserTokenRepository(db *sql.DB) *UserTokenRepository {
+	return &UserTokenRepository{
+		db:         db,
+		SQLBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Method to Create New User Token
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		token: token to store, only the hash of the token is persisted.
+// returns:
+// 		UserToken: the stored token.
+// 		errors: if any occurred during the operation.
+func (r *UserTokenRepository) Create(ctx context.Context, token *models.UserToken) (*models.UserToken, error) {
+	query := r.SQLBuilder.
+		Insert("user_tokens").
+		Columns("user_id", "purpose", "token_hash", "expires_at").
+		Values(token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt).
+		Suffix("RETURNING id, user_id, purpose, token_hash, expires_at, used_at, created_at")
+
+	created, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		return nil, translateError(err, "user_token")
+	}
+	return created, nil
+}
+
+// Method to Get User Token By Hash
+// It returns nil when no token of the purpose matches the hash.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		purpose: what the token was issued for, e.g. password_reset.
+// 		tokenHash: SHA-256 hash of the token.
+// returns:
+// 		UserToken: the stored token.
+// 		errors: if any occurred during the operation.
+func (r *UserTokenRepository) GetByHash(ctx context.Context, purpose, tokenHash string) (*models.UserToken, error) {
+	query := r.SQLBuilder.
+		Select("id", "user_id", "purpose", "token_hash", "expires_at", "used_at", "created_at").
+		From("user_tokens").
+		Where(sq.Eq{"token_hash": tokenHash, "purpose": purpose}).
+		Limit(1)
+
+	token, err := r.scan(query.RunWith(r.db).QueryRowContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, translateError(err, "user_token")
+	}
+	return token, nil
+}
+
+// Method to Mark a User Token used
+// Only unused tokens are updated, so two concurrent uses of the same token cannot both succeed.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the token.
+// returns:
+// 		bool: true when this call consumed the token.
+// 		errors: if any occurred during the operation.
+func (r *UserTokenRepository) MarkUsed(ctx context.Context, id string) (bool, error) {
+	query := r.SQLBuilder.
+		Update("user_tokens").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "used_at": nil})
+
+	result, err := query.RunWith(r.db).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "user_token")
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// Method to Invalidate every unused token of a user for a purpose
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		userID: owner of the tokens.
+// 		purpose: what the tokens were issued for.
+// returns:
+// 		errors: if any occurred during the operation.
+func (r *UserTokenRepository) InvalidateForUser(ctx context.Context, userID, purpose string) error {
+	query := r.SQLBuilder.
+		Update("user_tokens").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": userID, "purpose": purpose, "used_at": nil})
+
+	_, err := query.RunWith(r.db).ExecContext(ctx)
+	return translateError(err, "user_token")
+}
+
+func (r *UserTokenRepository) scan(row sq.RowScanner) (*models.UserToken, error) {
+	var token models.UserToken
+	var usedAt sql.NullTime
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Purpose,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&usedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+	return &token, nil
+}
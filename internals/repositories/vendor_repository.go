@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
 
 	sq "github.com/Masterminds/squirrel"
 )
@@ -13,6 +14,8 @@ type VendorRepository struct {
 	SQLBuilder sq.StatementBuilderType
 }
 
+var _ repository.VendorRepository = (*VendorRepository)(nil)
+
 // NewVendorRepository creates a new instance of VendorRepository with the provided database connection.
 func NewVendorRepository(db *sql.DB) *VendorRepository {
 	return &VendorRepository{
@@ -30,25 +33,35 @@ func NewVendorRepository(db *sql.DB) *VendorRepository {
 // 		VendorResponse: a VendorResponse model containing the details of the created vendor.
 // 		errors: if any occurred during the operation.
 func (v *VendorRepository) CreateVendor(ctx context.Context, userId string, vendorModel *models.CreateVendorRequest) (*models.Vendor, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := v.SQLBuilder.
 		Insert("vendors").
-		Columns("vendor_name", "description", "user_id").
-		Values(vendorModel.VendorName, vendorModel.Description, userId).
-		Suffix("RETURNING id, vendor_name, description, user_id, created_at, updated_at")
+		Columns("vendor_name", "description", "payment_terms", "bank_code", "bank_account_number", "bank_account_holder", "status", "user_id", "organization_id").
+		Values(vendorModel.VendorName, vendorModel.Description, vendorModel.PaymentTerms, vendorModel.BankCode, vendorModel.BankAccountNumber, vendorModel.BankAccountHolder, models.VendorStatusPending, userId, orgID).
+		Suffix("RETURNING id, vendor_name, description, payment_terms, bank_code, bank_account_number, bank_account_holder, status, user_id, organization_id, created_at, updated_at")
 
 	row := query.RunWith(v.db).QueryRowContext(ctx)
 
 	var vendorResponse models.Vendor
-	err := row.Scan(
+	err = row.Scan(
 		&vendorResponse.ID,
 		&vendorResponse.VendorName,
 		&vendorResponse.Description,
+		&vendorResponse.PaymentTerms,
+		&vendorResponse.BankCode,
+		&vendorResponse.BankAccountNumber,
+		&vendorResponse.BankAccountHolder,
+		&vendorResponse.Status,
 		&vendorResponse.UserID,
+		&vendorResponse.OrganizationID,
 		&vendorResponse.CreatedAt,
 		&vendorResponse.UpdatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "vendor")
 	}
 
 	return &vendorResponse, nil
@@ -61,18 +74,28 @@ func (v *VendorRepository) CreateVendor(ctx context.Context, userId string, vend
 // 		ctx: context for request-scoped values and cancellation.
 // 		limit: number of vendors to retrieve.
 func (v *VendorRepository) GetAllVendors(ctx context.Context, limit, offset int) ([]*models.Vendor, error) {    
+    orgID, err := tenant(ctx)
+    if err != nil {
+        return nil, err
+    }
     query := v.SQLBuilder.
         Select(
             "v.id",
             "v.vendor_name",
             "v.description",
+            "v.payment_terms",
+            "v.bank_code",
+            "v.bank_account_number",
+            "v.bank_account_holder",
+            "v.status",
             "v.user_id",
             "u.user_name",
             "v.created_at",
             "v.updated_at",
         ).
-        From("e_procurement.vendors v").
-        LeftJoin("e_procurement.users u ON v.user_id = u.id").
+        From("vendors v").
+        LeftJoin("users u ON v.user_id = u.id").
+        Where(sq.Eq{"v.organization_id": orgID}).
         OrderBy("v.created_at DESC").
         Limit(uint64(limit)).
         Offset(uint64(offset))
@@ -82,7 +105,7 @@ func (v *VendorRepository) GetAllVendors(ctx context.Context, limit, offset int)
         if err == sql.ErrNoRows {
             return nil, nil
         }
-        return nil, err
+        return nil, translateError(err, "vendor")
     }
     defer rows.Close()
     
@@ -93,19 +116,24 @@ func (v *VendorRepository) GetAllVendors(ctx context.Context, limit, offset int)
             &vendor.ID,
             &vendor.VendorName,
             &vendor.Description,
+            &vendor.PaymentTerms,
+            &vendor.BankCode,
+            &vendor.BankAccountNumber,
+            &vendor.BankAccountHolder,
+            &vendor.Status,
             &vendor.UserID,
             &vendor.UserName,
             &vendor.CreatedAt,
             &vendor.UpdatedAt,
         )
         if err != nil {
-            return nil, err
+            return nil, translateError(err, "vendor")
         }
         vendors = append(vendors, &vendor)
     }
     
     if err = rows.Err(); err != nil {
-        return nil, err
+        return nil, translateError(err, "vendor")
     }
     
     return vendors, nil
@@ -119,34 +147,48 @@ func (v *VendorRepository) GetAllVendors(ctx context.Context, limit, offset int)
 // returns:
 // 		VendorResponse: a VendorResponse model containing the details of the vendor.
 func (v *VendorRepository) GetVendorByID(ctx context.Context, id string) (*models.Vendor, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := v.SQLBuilder.
 		Select(
 			"v.id", 
 			"v.vendor_name", 
 			"v.description", 
+			"v.payment_terms",
+			"v.bank_code",
+			"v.bank_account_number",
+			"v.bank_account_holder",
+			"v.status",
 			"v.user_id", 
 			"u.user_name",
 			"v.created_at", 
 			"v.updated_at",
 			).
-		From("e_procurement.vendors v").
-		LeftJoin("e_procurement.users u ON v.user_id = u.id").
-		Where(sq.Eq{"v.id": id})
+		From("vendors v").
+		LeftJoin("users u ON v.user_id = u.id").
+		Where(sq.Eq{"v.id": id, "v.organization_id": orgID})
 
 	row := query.RunWith(v.db).QueryRowContext(ctx)
 
 	var vendor models.Vendor
-	err := row.Scan(
+	err = row.Scan(
 		&vendor.ID,
 		&vendor.VendorName,
 		&vendor.Description,
+		&vendor.PaymentTerms,
+		&vendor.BankCode,
+		&vendor.BankAccountNumber,
+		&vendor.BankAccountHolder,
+		&vendor.Status,
 		&vendor.UserID,
 		&vendor.UserName,
 		&vendor.CreatedAt,
 		&vendor.UpdatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "vendor")
 	}
 
 	return &vendor, nil
@@ -154,6 +196,7 @@ func (v *VendorRepository) GetVendorByID(ctx context.Context, id string) (*model
 
 // Method to Update Vendor
 // It returns a VendorResponse model containing the updated details of the vendor.
+// The bank account is left as it is, it is changed through ChangeBankAccount.
 // parameters:
 // 		ctx: context for request-scoped values and cancellation.
 // 		vendorModel: vendor model containing updated vendor details.
@@ -161,27 +204,38 @@ func (v *VendorRepository) GetVendorByID(ctx context.Context, id string) (*model
 // 		VendorResponse: a VendorResponse model containing the updated details of the vendor.
 // 		errors: if any occurred during the operation.
 func (v *VendorRepository) UpdateVendor(ctx context.Context,vendorID string, vendorModel *models.UpdateVendorRequest) (*models.Vendor, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := v.SQLBuilder.
 		Update("vendors").
 		Set("vendor_name", vendorModel.VendorName).
 		Set("description", vendorModel.Description).
+		Set("payment_terms", vendorModel.PaymentTerms).
 		Set("user_id", vendorModel.UserID).
-		Where(sq.Eq{"id": vendorID}).
-		Suffix("RETURNING id, vendor_name, description, user_id, created_at, updated_at")
+		Where(sq.Eq{"id": vendorID, "organization_id": orgID}).
+		Suffix("RETURNING id, vendor_name, description, payment_terms, bank_code, bank_account_number, bank_account_holder, status, user_id, organization_id, created_at, updated_at")
 
 	row := query.RunWith(v.db).QueryRowContext(ctx)
 
 	var vendorResponse models.Vendor
-	err := row.Scan(
+	err = row.Scan(
 		&vendorResponse.ID,
 		&vendorResponse.VendorName,
 		&vendorResponse.Description,
+		&vendorResponse.PaymentTerms,
+		&vendorResponse.BankCode,
+		&vendorResponse.BankAccountNumber,
+		&vendorResponse.BankAccountHolder,
+		&vendorResponse.Status,
 		&vendorResponse.UserID,
+		&vendorResponse.OrganizationID,
 		&vendorResponse.CreatedAt,
 		&vendorResponse.UpdatedAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "vendor")
 	}
 
 	return &vendorResponse, nil
@@ -195,12 +249,20 @@ func (v *VendorRepository) UpdateVendor(ctx context.Context,vendorID string, ven
 // returns:
 // 		errors: if any occurred during the operation.
 func (v *VendorRepository) DeleteVendor(ctx context.Context, id string) error {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
 	query := v.SQLBuilder.
 		Delete("vendors").
-		Where(sq.Eq{"id": id})
-	_, err := query.RunWith(v.db).ExecContext(ctx)
+		Where(sq.Eq{"id": id, "organization_id": orgID})
+	result, err := query.RunWith(v.db).ExecContext(ctx)
 	if err != nil {
-		return err
+		return translateError(err, "vendor")
+	}
+	affected, _ := result.RowsAffected()
+	if affected == 0 {
+		return translateError(sql.ErrNoRows, "vendor")
 	}
 	return nil
 }
@@ -208,16 +270,21 @@ func (v *VendorRepository) DeleteVendor(ctx context.Context, id string) error {
 // Method to Count Total Vendors
 // It returns the total count of vendors in the database.
 func (v *VendorRepository) CountVendors(ctx context.Context) (int, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
 	query := v.SQLBuilder.
 		Select("COUNT(*)").
-		From("vendors")
+		From("vendors").
+		Where(sq.Eq{"organization_id": orgID})
 
 	row := query.RunWith(v.db).QueryRowContext(ctx)
 
 	var count int
-	err := row.Scan(&count)
+	err = row.Scan(&count)
 	if err != nil {
-		return 0, err
+		return 0, translateError(err, "vendor")
 	}
 
 	return count, nil
@@ -232,27 +299,41 @@ func (v *VendorRepository) CountVendors(ctx context.Context) (int, error) {
 // 		VendorResponse: a VendorResponse model containing the details of the vendor.
 // 		errors: if any occurred during the operation.
 func(v *VendorRepository) GetVendorByUserID(ctx context.Context, userID string) (*models.Vendor, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := v.SQLBuilder.
 		Select(
 			"v.id", 
 			"v.vendor_name", 
 			"v.description", 
+			"v.payment_terms",
+			"v.bank_code",
+			"v.bank_account_number",
+			"v.bank_account_holder",
+			"v.status",
 			"v.user_id", 
 			"u.user_name",
 			"v.created_at", 
 			"v.updated_at",
 			).
-		From("e_procurement.vendors v").
-		LeftJoin("e_procurement.users u ON v.user_id = u.id").
-		Where(sq.Eq{"v.user_id": userID})
+		From("vendors v").
+		LeftJoin("users u ON v.user_id = u.id").
+		Where(sq.Eq{"v.user_id": userID, "v.organization_id": orgID})
 
 	row := query.RunWith(v.db).QueryRowContext(ctx)
 
 	var vendor models.Vendor
-	err := row.Scan(
+	err = row.Scan(
 		&vendor.ID,
 		&vendor.VendorName,
 		&vendor.Description,
+		&vendor.PaymentTerms,
+		&vendor.BankCode,
+		&vendor.BankAccountNumber,
+		&vendor.BankAccountHolder,
+		&vendor.Status,
 		&vendor.UserID,
 		&vendor.UserName,
 		&vendor.CreatedAt,
@@ -262,8 +343,142 @@ func(v *VendorRepository) GetVendorByUserID(ctx context.Context, userID string)
 		if err == sql.ErrNoRows {
 			return nil, nil // No vendor found for the user ID
 		}
-		return nil, err
+		return nil, translateError(err, "vendor")
 	}
 
 	return &vendor, nil
-}
\ No newline at end of file
+}
+
+// Method to Update Vendor Status
+// It moves the vendor to a new onboarding status while it still has the expected one.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		id: ID of the vendor to update.
+// 		from: status the vendor must have.
+// 		status: the new status.
+// returns:
+// 		bool: false when the vendor does not exist or no longer has status from.
+// 		errors: if any occurred during the operation.
+func (v *VendorRepository) UpdateVendorStatus(ctx context.Context, id, from, status string) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	result, err := v.SQLBuilder.
+		Update("vendors").
+		Set("status", status).
+		Where(sq.Eq{"id": id, "organization_id": orgID, "status": from}).
+		RunWith(v.db).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "vendor")
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, translateError(err, "vendor")
+	}
+	return affected > 0, nil
+}
+
+// Method to Change the bank account of a Vendor
+// The account is replaced only while it still is the old account of the change, the change is
+// recorded in the same transaction.
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		change: the vendor, its current and new account and the user changing it.
+// returns:
+// 		bool: false when the vendor does not exist or its account is no longer the old account.
+// 		errors: if any occurred during the operation.
+func (v *VendorRepository) ChangeBankAccount(ctx context.Context, change *models.VendorBankAccountChange) (bool, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := v.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := v.SQLBuilder.
+		Update("vendors").
+		Set("bank_code", change.BankCode).
+		Set("bank_account_number", change.BankAccountNumber).
+		Set("bank_account_holder", change.BankAccountHolder).
+		Where(sq.Eq{
+			"id":                  change.VendorID,
+			"organization_id":     orgID,
+			"bank_code":           change.OldBankCode,
+			"bank_account_number": change.OldBankAccountNumber,
+			"bank_account_holder": change.OldBankAccountHolder,
+		}).
+		RunWith(tx).ExecContext(ctx)
+	if err != nil {
+		return false, translateError(err, "vendor")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+
+	err = v.SQLBuilder.
+		Insert("vendor_bank_account_changes").
+		Columns("vendor_id", "old_bank_code", "old_bank_account_number", "old_bank_account_holder",
+			"bank_code", "bank_account_number", "bank_account_holder", "changed_by").
+		Values(change.VendorID, change.OldBankCode, change.OldBankAccountNumber, change.OldBankAccountHolder,
+			change.BankCode, change.BankAccountNumber, change.BankAccountHolder, change.ChangedBy).
+		Suffix("RETURNING id, changed_at").
+		RunWith(tx).QueryRowContext(ctx).Scan(&change.ID, &change.ChangedAt)
+	if err != nil {
+		return false, translateError(err, "vendor")
+	}
+
+	return true, tx.Commit()
+}
+
+// Method to List the bank account changes of a Vendor, oldest first
+// parameters:
+// 		ctx: context for request-scoped values and cancellation.
+// 		vendorID: ID of the vendor.
+// returns:
+// 		[]VendorBankAccountChange: the changes with the account before and after each one.
+// 		errors: if any occurred during the operation.
+func (v *VendorRepository) ListBankAccountChanges(ctx context.Context, vendorID string) ([]*models.VendorBankAccountChange, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := v.SQLBuilder.
+		Select("c.id, c.vendor_id, c.old_bank_code, c.old_bank_account_number, c.old_bank_account_holder, " +
+			"c.bank_code, c.bank_account_number, c.bank_account_holder, c.changed_by, c.changed_at").
+		From("vendor_bank_account_changes c").
+		Join("vendors v ON v.id = c.vendor_id").
+		Where(sq.Eq{"c.vendor_id": vendorID, "v.organization_id": orgID}).
+		OrderBy("c.changed_at", "c.id")
+
+	rows, err := query.RunWith(v.db).QueryContext(ctx)
+	if err != nil {
+		return nil, translateError(err, "vendor")
+	}
+	defer rows.Close()
+
+	var changes []*models.VendorBankAccountChange
+	for rows.Next() {
+		var change models.VendorBankAccountChange
+		err := rows.Scan(
+			&change.ID,
+			&change.VendorID,
+			&change.OldBankCode,
+			&change.OldBankAccountNumber,
+			&change.OldBankAccountHolder,
+			&change.BankCode,
+			&change.BankAccountNumber,
+			&change.BankAccountHolder,
+			&change.ChangedBy,
+			&change.ChangedAt,
+		)
+		if err != nil {
+			return nil, translateError(err, "vendor")
+		}
+		changes = append(changes, &change)
+	}
+	return changes, translateError(rows.Err(), "vendor")
+}
@@ -0,0 +1,190 @@
+package usecases
+
+import (
+	"context"
+	"crypto/subtle"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	"e-procurement/pkg/auth"
+	customContext "e-procurement/pkg/context"
+	"e-procurement/pkg/rbac"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+)
+
+// last_used_at is only written when the stored value is older than this,
+// so a busy integration does not update the row on every request
+const apiKeyTouchInterval = time.Minute
+
+var errInvalidAPIKey = apperror.Unauthorized("invalid_api_key", "api key is invalid, expired or revoked")
+
+// APIKeyUseCase manages the API keys used by other systems (ERP) and resolves the
+// service principal of a key for the auth middleware
+type APIKeyUseCase struct {
+	apiKeyRepository       repository.APIKeyRepository
+	organizationRepository repository.OrganizationRepository
+	// longest lifetime of a key, also used when no expiry is requested
+	maxTTL time.Duration
+}
+
+var _ auth.APIKeyAuthenticator = (*APIKeyUseCase)(nil)
+
+func NewAPIKeyUseCase(
+	apiKeyRepo repository.APIKeyRepository,
+	organizationRepo repository.OrganizationRepository,
+	maxTTL time.Duration,
+	) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		apiKeyRepository:       apiKeyRepo,
+		organizationRepository: organizationRepo,
+		maxTTL:                 maxTTL,
+	}
+}
+
+// Create issues a key owned by the caller in the organization of the request. scopes are
+// permissions the caller's role grants, the plain key is only returned here.
+func (u *APIKeyUseCase) Create(ctx context.Context, req *models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	position, err := customContext.GetPositionFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantID, err := customContext.GetTenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make([]string, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		// keys cannot manage keys, those routes need a user session anyway
+		if scope == rbac.PermAPIKeyManage || !rbac.IsValidPermission(scope) || !rbac.HasPermission(position, scope) {
+			return nil, apperror.Validation("invalid_scope", fmt.Sprintf("scope %q is unknown or not granted to role %s", scope, position))
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(u.maxTTL)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) || req.ExpiresAt.After(expiresAt) {
+			return nil, apperror.Validation("invalid_expiry", fmt.Sprintf("expires_at must be in the future and within %s", u.maxTTL))
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	key, prefix, secretHash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("error generating api key: %w", err)
+	}
+	created, err := u.apiKeyRepository.Create(ctx, &models.APIKey{
+		UserID:         userID,
+		OrganizationID: tenantID,
+		Name:           req.Name,
+		Prefix:         prefix,
+		KeyHash:        secretHash,
+		Scopes:         scopes,
+		ExpiresAt:      expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.CreateAPIKeyResponse{APIKey: created, Key: key}, nil
+}
+
+// List returns the keys of a user, the caller's own when userID is empty.
+// listing another user's keys needs user:manage.
+func (u *APIKeyUseCase) List(ctx context.Context, userID string) ([]*models.APIKey, error) {
+	userID, err := u.resolveOwner(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return u.apiKeyRepository.ListByUser(ctx, userID)
+}
+
+// Revoke disables a key of the caller, or of any user with user:manage
+func (u *APIKeyUseCase) Revoke(ctx context.Context, id string) error {
+	key, err := u.apiKeyRepository.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get api key: %w", err)
+	}
+	if key == nil {
+		return apperror.NotFound("api_key_not_found", fmt.Sprintf("api key with ID %s not found", id))
+	}
+	if _, err := u.resolveOwner(ctx, key.UserID); err != nil {
+		return err
+	}
+	// revoking twice is not an error
+	if _, err := u.apiKeyRepository.Revoke(ctx, id); err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return nil
+}
+
+// AuthenticateAPIKey resolves the service principal of a key, it acts as the key owner
+// with the owner's current role in the organization of the key
+func (u *APIKeyUseCase) AuthenticateAPIKey(ctx context.Context, key string) (*auth.APIKeyPrincipal, error) {
+	prefix, secretHash, ok := auth.ParseAPIKey(key)
+	if !ok {
+		return nil, errInvalidAPIKey
+	}
+	stored, err := u.apiKeyRepository.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	now := time.Now()
+	if stored == nil || subtle.ConstantTimeCompare([]byte(stored.KeyHash), []byte(secretHash)) != 1 ||
+		stored.RevokedAt != nil || now.After(stored.ExpiresAt) {
+		return nil, errInvalidAPIKey
+	}
+
+	// owners removed from the organization lose their keys there
+	membership, err := u.organizationRepository.GetMembership(ctx, stored.OrganizationID, stored.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key owner: %w", err)
+	}
+	if membership == nil {
+		return nil, errInvalidAPIKey
+	}
+
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) >= apiKeyTouchInterval {
+		// usage tracking must not fail the request
+		if err := u.apiKeyRepository.Touch(ctx, stored.ID, now); err != nil {
+			log.Printf("failed to record use of api key %s: %v", stored.ID, err)
+		}
+	}
+
+	return &auth.APIKeyPrincipal{
+		KeyID:          stored.ID,
+		UserID:         stored.UserID,
+		OrganizationID: stored.OrganizationID,
+		Position:       membership.Role,
+		Scopes:         stored.Scopes,
+	}, nil
+}
+
+// resolveOwner defaults userID to the caller and checks user:manage for other users
+func (u *APIKeyUseCase) resolveOwner(ctx context.Context, userID string) (string, error) {
+	callerID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	if userID == "" || userID == callerID {
+		return callerID, nil
+	}
+	position, err := customContext.GetPositionFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !rbac.HasPermission(position, rbac.PermUserManage) {
+		return "", apperror.Forbidden("permission_denied", "you do not have permission to perform this action")
+	}
+	return userID, nil
+}
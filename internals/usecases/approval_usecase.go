@@ -0,0 +1,707 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	customContext "e-procurement/pkg/context"
+	"e-procurement/pkg/rbac"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	errApprovalNotPending     = apperror.Conflict("approval_not_pending", "the approval is already completed")
+	errApprovalStatusChanged  = apperror.Conflict("approval_status_changed", "the approval has changed, reload it and try again")
+	errApprovalNotAssigned    = apperror.Forbidden("approval_not_assigned", "no pending step of this approval is assigned to you")
+	errCannotApproveOwn       = apperror.Forbidden("cannot_approve_own_request", "you cannot act on the approval of your own document")
+	errApprovalCommentMissing = apperror.Validation("comment_required", "a comment is required to reject or return a document")
+	errApprovalAlreadyDecided = apperror.Forbidden("approval_already_decided", "you already decided a step of this approval, another approver has to decide the rest")
+	errApprovalNotCompleted   = apperror.Conflict("approval_not_completed", "only approved, rejected or returned approvals can be applied to their document")
+	errApprovalSuperseded     = apperror.Conflict("approval_superseded", "the document went through a newer approval")
+)
+
+// ApprovalHandler is implemented by the usecases whose documents go through the approval
+// engine. ApprovalCompleted is called once a request is approved, rejected or returned for
+// revision, decision is the step that completed it.
+type ApprovalHandler interface {
+	ApprovalCompleted(ctx context.Context, request *models.ApprovalRequest, decision *models.ApprovalStep) error
+}
+
+// ApprovalUseCase is the approval engine shared by requisitions, purchase orders and vendor
+// onboarding. Rules stored per organization decide who signs off a document: every active
+// rule matching the amount, category and department of the document contributes its steps,
+// rules run one after another by priority and the steps of one level run in parallel.
+type ApprovalUseCase struct {
+	ruleRepository         repository.ApprovalRuleRepository
+	approvalRepository     repository.ApprovalRepository
+	delegationRepository   repository.ApprovalDelegationRepository
+	organizationRepository repository.OrganizationRepository
+	departments            *DepartmentUseCase
+	// document type -> usecase notified about completed approvals
+	handlers map[string]ApprovalHandler
+}
+
+func NewApprovalUseCase(
+	ruleRepo repository.ApprovalRuleRepository,
+	approvalRepo repository.ApprovalRepository,
+	delegationRepo repository.ApprovalDelegationRepository,
+	organizationRepo repository.OrganizationRepository,
+	departments *DepartmentUseCase,
+) *ApprovalUseCase {
+	return &ApprovalUseCase{
+		ruleRepository:         ruleRepo,
+		approvalRepository:     approvalRepo,
+		delegationRepository:   delegationRepo,
+		organizationRepository: organizationRepo,
+		departments:            departments,
+		handlers:               map[string]ApprovalHandler{},
+	}
+}
+
+// Register makes handler receive the completed approvals of a document type, it is
+// called while wiring the application, before any request is served
+func (u *ApprovalUseCase) Register(documentType string, handler ApprovalHandler) {
+	u.handlers[documentType] = handler
+}
+
+// CreateRule stores a new approval rule, active unless the request says otherwise
+func (u *ApprovalUseCase) CreateRule(ctx context.Context, req *models.ApprovalRuleRequest) (*models.ApprovalRule, error) {
+	rule, err := u.toRule(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	rule.Active = req.Active == nil || *req.Active
+	return u.ruleRepository.Create(ctx, rule)
+}
+
+// ListRules returns the rules of a document type, every type when empty
+func (u *ApprovalUseCase) ListRules(ctx context.Context, documentType string) ([]*models.ApprovalRule, error) {
+	rules, err := u.ruleRepository.List(ctx, documentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approval rules: %w", err)
+	}
+	return rules, nil
+}
+
+// GetRule returns an approval rule of the organization
+func (u *ApprovalUseCase) GetRule(ctx context.Context, id string) (*models.ApprovalRule, error) {
+	rule, err := u.ruleRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval rule: %w", err)
+	}
+	if rule == nil {
+		return nil, apperror.NotFound("approval_rule_not_found", fmt.Sprintf("approval rule with ID %s not found", id))
+	}
+	return rule, nil
+}
+
+// UpdateRule replaces an approval rule, running approvals keep the steps they started with
+func (u *ApprovalUseCase) UpdateRule(ctx context.Context, id string, req *models.ApprovalRuleRequest) (*models.ApprovalRule, error) {
+	existing, err := u.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := u.toRule(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = id
+	rule.Active = existing.Active
+	if req.Active != nil {
+		rule.Active = *req.Active
+	}
+	return u.ruleRepository.Update(ctx, rule)
+}
+
+// DeleteRule removes an approval rule
+func (u *ApprovalUseCase) DeleteRule(ctx context.Context, id string) error {
+	return u.ruleRepository.Delete(ctx, id)
+}
+
+// Start opens the approval of a document. The steps are copied from the matching rules;
+// when no rule matches, a single step for the approver role is used.
+func (u *ApprovalUseCase) Start(ctx context.Context, subject *models.ApprovalSubject) (*models.ApprovalRequest, error) {
+	rules, err := u.ruleRepository.List(ctx, subject.DocumentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approval rules: %w", err)
+	}
+	departments, err := u.departments.byID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &models.ApprovalRequest{
+		DocumentType: subject.DocumentType,
+		DocumentID:   subject.DocumentID,
+		Title:        subject.Title,
+		Amount:       subject.Amount,
+		DepartmentID: subject.DepartmentID,
+		RequestedBy:  subject.RequestedBy,
+		Status:       models.ApprovalStatusPending,
+		CurrentStage: 1,
+	}
+	stage := 0
+	for _, rule := range rules {
+		if !rule.Active || !ruleMatches(rule, subject, departments) {
+			continue
+		}
+		level := 0
+		for _, ruleStep := range rule.Steps {
+			// steps are ordered by level, every new level of every rule is a new stage
+			if ruleStep.Level != level {
+				level = ruleStep.Level
+				stage++
+			}
+			step := &models.ApprovalStep{
+				Stage:          stage,
+				RuleID:         &rule.ID,
+				Name:           ruleStep.Name,
+				ApproverRole:   ruleStep.ApproverRole,
+				ApproverUserID: ruleStep.ApproverUserID,
+			}
+			if ruleStep.ApproverType == models.ApproverTypeDepartmentManager {
+				step.ApproverRole = ""
+				if step.ApproverUserID = departmentManager(subject.DepartmentID, departments); step.ApproverUserID == nil {
+					return nil, apperror.Validation("approver_unresolved",
+						fmt.Sprintf("approval step %q needs a department manager but the document's department has none", ruleStep.Name))
+				}
+			}
+			request.Steps = append(request.Steps, step)
+		}
+	}
+	if len(request.Steps) == 0 {
+		request.Steps = []*models.ApprovalStep{{Stage: 1, Name: "Approval", ApproverRole: rbac.RoleApprover}}
+	}
+	for _, step := range request.Steps {
+		step.Status = models.ApprovalStepWaiting
+		if step.Stage == request.CurrentStage {
+			step.Status = models.ApprovalStepPending
+		}
+	}
+
+	created, err := u.approvalRepository.Create(ctx, request)
+	if err != nil {
+		if apperror.IsKind(err, apperror.KindConflict) {
+			return nil, apperror.Conflict("approval_already_pending", "the document is already waiting for approval")
+		}
+		return nil, err
+	}
+	return created, nil
+}
+
+// Cancel withdraws the pending approval of a document, documents without one are ignored
+func (u *ApprovalUseCase) Cancel(ctx context.Context, documentType, documentID string) error {
+	request, err := u.approvalRepository.GetPending(ctx, documentType, documentID)
+	if err != nil {
+		return fmt.Errorf("failed to get pending approval: %w", err)
+	}
+	if request == nil {
+		return nil
+	}
+	now := time.Now()
+	_, err = u.approvalRepository.Advance(ctx, request.ID, request.CurrentStage, &models.ApprovalTransition{
+		Status:      models.ApprovalStatusCancelled,
+		Stage:       request.CurrentStage,
+		CompletedAt: &now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel approval: %w", err)
+	}
+	return nil
+}
+
+// Get returns an approval request with its steps
+func (u *ApprovalUseCase) Get(ctx context.Context, id string) (*models.ApprovalRequest, error) {
+	request, err := u.approvalRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval: %w", err)
+	}
+	if request == nil {
+		return nil, apperror.NotFound("approval_not_found", fmt.Sprintf("approval with ID %s not found", id))
+	}
+	return request, nil
+}
+
+// List returns a page of approval requests, newest first, with the total count
+func (u *ApprovalUseCase) List(ctx context.Context, filter models.ApprovalFilter, limit, page int) ([]*models.ApprovalRequest, int, error) {
+	limit, offset, err := pageOffset(limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	requests, count, err := u.approvalRepository.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list approvals: %w", err)
+	}
+	return requests, count, nil
+}
+
+// Pending returns a page of the steps waiting for the caller: steps assigned to them or to
+// a member who delegated to them, and steps for their role or the role of such a member,
+// except on their own documents and on requests they (or the delegating member) already
+// decided a step of
+func (u *ApprovalUseCase) Pending(ctx context.Context, limit, page int) ([]*models.PendingApproval, int, error) {
+	limit, offset, err := pageOffset(limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	userID, position, err := u.caller(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	delegators, delegatedRoles, err := u.delegations(ctx, userID, time.Now())
+	if err != nil {
+		return nil, 0, err
+	}
+	filter := models.PendingApprovalFilter{
+		UserIDs:          append([]string{userID}, delegators...),
+		Role:             position,
+		DelegatedRoles:   delegatedRoles,
+		ExcludeRequester: userID,
+		ExcludeActor:     userID,
+	}
+	pending, count, err := u.approvalRepository.ListPending(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list pending approvals: %w", err)
+	}
+	return pending, count, nil
+}
+
+// Approve signs off the caller's step, the next stage starts once every step of the current one is approved
+func (u *ApprovalUseCase) Approve(ctx context.Context, id string, req *models.ApprovalDecisionRequest) (*models.ApprovalRequest, error) {
+	return u.act(ctx, id, models.ApprovalStepApproved, req.Comment)
+}
+
+// Reject declines the document, the remaining steps are skipped
+func (u *ApprovalUseCase) Reject(ctx context.Context, id string, req *models.ApprovalDecisionRequest) (*models.ApprovalRequest, error) {
+	if strings.TrimSpace(req.Comment) == "" {
+		return nil, errApprovalCommentMissing
+	}
+	return u.act(ctx, id, models.ApprovalStepRejected, req.Comment)
+}
+
+// Return sends the document back to its requester for revision, it is approved anew once resubmitted
+func (u *ApprovalUseCase) Return(ctx context.Context, id string, req *models.ApprovalDecisionRequest) (*models.ApprovalRequest, error) {
+	if strings.TrimSpace(req.Comment) == "" {
+		return nil, errApprovalCommentMissing
+	}
+	return u.act(ctx, id, models.ApprovalStepReturned, req.Comment)
+}
+
+// Delegate hands the caller's approvals to another member for a period, the steps for the
+// caller's role included
+func (u *ApprovalUseCase) Delegate(ctx context.Context, req *models.ApprovalDelegationRequest) (*models.ApprovalDelegation, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.DelegateID == userID {
+		return nil, apperror.Validation("cannot_delegate_to_self", "you cannot delegate your approvals to yourself")
+	}
+	if !req.EndsAt.After(req.StartsAt) || !req.EndsAt.After(time.Now()) {
+		return nil, apperror.Validation("invalid_delegation_period", "ends_at must be after starts_at and in the future")
+	}
+	orgID, err := customContext.GetTenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	membership, err := u.organizationRepository.GetMembership(ctx, orgID, req.DelegateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delegate membership: %w", err)
+	}
+	if membership == nil {
+		return nil, apperror.Validation("delegate_not_member",
+			fmt.Sprintf("delegate %s is not a member of the organization", req.DelegateID))
+	}
+	return u.delegationRepository.Create(ctx, &models.ApprovalDelegation{
+		DelegatorID: userID,
+		DelegateID:  req.DelegateID,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		Reason:      req.Reason,
+	})
+}
+
+// ListDelegations returns the delegations given or received by the caller
+func (u *ApprovalUseCase) ListDelegations(ctx context.Context) ([]*models.ApprovalDelegation, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	delegations, err := u.delegationRepository.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approval delegations: %w", err)
+	}
+	return delegations, nil
+}
+
+// RevokeDelegation ends a delegation given by the caller
+func (u *ApprovalUseCase) RevokeDelegation(ctx context.Context, id string) error {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	return u.delegationRepository.Delete(ctx, id, userID)
+}
+
+// act records the caller's decision on a pending step of the current stage and moves the
+// request on. Nobody decides more than one step of a request, directly or as a delegate.
+// The handler of the document type is notified when the request completes.
+func (u *ApprovalUseCase) act(ctx context.Context, id, status, comment string) (*models.ApprovalRequest, error) {
+	request, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != models.ApprovalStatusPending {
+		return nil, errApprovalNotPending
+	}
+	userID, position, err := u.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if request.RequestedBy == userID {
+		return nil, errCannotApproveOwn
+	}
+
+	now := time.Now()
+	delegators, delegatedRoles, err := u.delegations(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	step, onBehalfOf := assignedStep(request, userID, position, delegators, delegatedRoles)
+	if step == nil {
+		return nil, errApprovalNotAssigned
+	}
+	// one person signs off one step: holding several roles, or acting for a delegator,
+	// must not let the same person approve a whole chain
+	if alreadyDecided(request, userID) || (onBehalfOf != nil && alreadyDecided(request, *onBehalfOf)) {
+		return nil, errApprovalAlreadyDecided
+	}
+
+	decided, err := u.approvalRepository.Decide(ctx, step.ID, &models.ApprovalStepDecision{
+		Status:     status,
+		ActedBy:    userID,
+		OnBehalfOf: onBehalfOf,
+		Comment:    comment,
+		ActedAt:    now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record approval decision: %w", err)
+	}
+	if !decided {
+		// the repository refuses the step as well when the same person decided another step concurrently
+		if request, err = u.Get(ctx, id); err != nil {
+			return nil, err
+		}
+		if alreadyDecided(request, userID) || (onBehalfOf != nil && alreadyDecided(request, *onBehalfOf)) {
+			return nil, errApprovalAlreadyDecided
+		}
+		return nil, errApprovalStatusChanged
+	}
+
+	// reload after our own write so parallel approvers see each other's decisions
+	request, err = u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	transition := nextTransition(request, status, now)
+	if transition == nil {
+		return request, nil
+	}
+	advanced, err := u.approvalRepository.Advance(ctx, id, request.CurrentStage, transition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to advance approval: %w", err)
+	}
+	if request, err = u.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	// a parallel approver advanced the request first and took care of the rest
+	if !advanced || request.Status == models.ApprovalStatusPending {
+		return request, nil
+	}
+
+	for _, decision := range request.Steps {
+		if decision.ID == step.ID {
+			if err := u.complete(ctx, request, decision); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return request, nil
+}
+
+// Complete applies a completed approval to its document again, for when notifying the
+// handler failed after the request was already completed. Only the latest approval of a
+// document is applied, an older one would overwrite the outcome of a resubmission.
+func (u *ApprovalUseCase) Complete(ctx context.Context, id string) (*models.ApprovalRequest, error) {
+	request, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status == models.ApprovalStatusPending || request.Status == models.ApprovalStatusCancelled {
+		return nil, errApprovalNotCompleted
+	}
+	latest, _, err := u.approvalRepository.List(ctx, models.ApprovalFilter{
+		DocumentType: request.DocumentType,
+		DocumentID:   request.DocumentID,
+	}, 1, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approvals: %w", err)
+	}
+	if len(latest) == 0 || latest[0].ID != request.ID {
+		return nil, errApprovalSuperseded
+	}
+	decision := lastDecision(request)
+	if decision == nil {
+		return nil, errApprovalNotCompleted
+	}
+	if err := u.complete(ctx, request, decision); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// complete notifies the handler of the document type about a completed request, decision
+// is the step that completed it. Handlers treat a document already in the resulting status
+// as done, so a completion can be applied again.
+func (u *ApprovalUseCase) complete(ctx context.Context, request *models.ApprovalRequest, decision *models.ApprovalStep) error {
+	handler, ok := u.handlers[request.DocumentType]
+	if !ok {
+		return nil
+	}
+	if err := handler.ApprovalCompleted(ctx, request, decision); err != nil {
+		return fmt.Errorf("failed to complete %s approval: %w", request.DocumentType, err)
+	}
+	return nil
+}
+
+// caller returns the user ID and role of the authenticated user
+func (u *ApprovalUseCase) caller(ctx context.Context) (string, string, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	position, err := customContext.GetPositionFromContext(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return userID, position, nil
+}
+
+// delegations returns the members who delegated their approvals to userID at the given time
+// and the roles they hold, delegators who left the organization keep no role
+func (u *ApprovalUseCase) delegations(ctx context.Context, userID string, at time.Time) ([]string, []models.DelegatedRole, error) {
+	delegators, err := u.delegationRepository.ActiveDelegators(ctx, userID, at)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get delegations: %w", err)
+	}
+	if len(delegators) == 0 {
+		return nil, nil, nil
+	}
+	orgID, err := customContext.GetTenantFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var roles []models.DelegatedRole
+	for _, delegatorID := range delegators {
+		membership, err := u.organizationRepository.GetMembership(ctx, orgID, delegatorID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get delegator membership: %w", err)
+		}
+		if membership != nil {
+			roles = append(roles, models.DelegatedRole{DelegatorID: delegatorID, Role: membership.Role})
+		}
+	}
+	return delegators, roles, nil
+}
+
+// toRule checks the request and turns it into a rule, named approvers must be members
+func (u *ApprovalUseCase) toRule(ctx context.Context, req *models.ApprovalRuleRequest) (*models.ApprovalRule, error) {
+	if req.AmountAbove != nil && req.AmountUpTo != nil && *req.AmountUpTo <= *req.AmountAbove {
+		return nil, apperror.Validation("invalid_amount_range", "amount_up_to must be greater than amount_above")
+	}
+	orgID, err := customContext.GetTenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &models.ApprovalRule{
+		DocumentType: req.DocumentType,
+		Name:         req.Name,
+		Priority:     req.Priority,
+		AmountAbove:  req.AmountAbove,
+		AmountUpTo:   req.AmountUpTo,
+		CategoryID:   req.CategoryID,
+		DepartmentID: req.DepartmentID,
+	}
+	for _, stepReq := range req.Steps {
+		step := &models.ApprovalRuleStep{
+			Level:        stepReq.Level,
+			Name:         stepReq.Name,
+			ApproverType: stepReq.ApproverType,
+		}
+		switch stepReq.ApproverType {
+		case models.ApproverTypeRole:
+			step.ApproverRole = stepReq.ApproverRole
+		case models.ApproverTypeUser:
+			membership, err := u.organizationRepository.GetMembership(ctx, orgID, *stepReq.ApproverUserID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get approver membership: %w", err)
+			}
+			if membership == nil {
+				return nil, apperror.Validation("approver_not_member",
+					fmt.Sprintf("approver %s is not a member of the organization", *stepReq.ApproverUserID))
+			}
+			step.ApproverUserID = stepReq.ApproverUserID
+		}
+		rule.Steps = append(rule.Steps, step)
+	}
+	sort.SliceStable(rule.Steps, func(i, j int) bool { return rule.Steps[i].Level < rule.Steps[j].Level })
+	return rule, nil
+}
+
+// ruleMatches reports whether the document meets every condition of the rule. A department
+// condition also covers the sub departments of that department.
+func ruleMatches(rule *models.ApprovalRule, subject *models.ApprovalSubject, departments map[string]*models.Department) bool {
+	if rule.AmountAbove != nil && subject.Amount <= *rule.AmountAbove {
+		return false
+	}
+	if rule.AmountUpTo != nil && subject.Amount > *rule.AmountUpTo {
+		return false
+	}
+	if rule.CategoryID != nil {
+		found := false
+		for _, categoryID := range subject.CategoryIDs {
+			if categoryID == *rule.CategoryID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if rule.DepartmentID != nil {
+		for id := subject.DepartmentID; ; {
+			if id == nil {
+				return false
+			}
+			if *id == *rule.DepartmentID {
+				break
+			}
+			department, ok := departments[*id]
+			if !ok {
+				return false
+			}
+			id = department.ParentID
+		}
+	}
+	return true
+}
+
+// departmentManager returns the manager of the department, or of its nearest ancestor with one
+func departmentManager(departmentID *string, departments map[string]*models.Department) *string {
+	for id := departmentID; id != nil; {
+		department, ok := departments[*id]
+		if !ok {
+			return nil
+		}
+		if department.ManagerID != nil {
+			return department.ManagerID
+		}
+		id = department.ParentID
+	}
+	return nil
+}
+
+// assignedStep picks the pending step of the current stage the user may act on: their own
+// step first, then one of a member who delegated to them, then a step for their role and
+// last a step for the role of a delegating member who may still act on the request
+func assignedStep(request *models.ApprovalRequest, userID, position string, delegators []string, delegatedRoles []models.DelegatedRole) (*models.ApprovalStep, *string) {
+	var delegated, byRole, byDelegatedRole *models.ApprovalStep
+	var onBehalfOf, roleOnBehalfOf *string
+	for _, step := range request.Steps {
+		if step.Status != models.ApprovalStepPending || step.Stage != request.CurrentStage {
+			continue
+		}
+		if step.ApproverUserID == nil {
+			if byRole == nil && step.ApproverRole == position {
+				byRole = step
+			}
+			for _, role := range delegatedRoles {
+				if byDelegatedRole == nil && step.ApproverRole == role.Role &&
+					role.DelegatorID != request.RequestedBy && !alreadyDecided(request, role.DelegatorID) {
+					delegatorID := role.DelegatorID
+					byDelegatedRole, roleOnBehalfOf = step, &delegatorID
+				}
+			}
+			continue
+		}
+		if *step.ApproverUserID == userID {
+			return step, nil
+		}
+		for _, delegatorID := range delegators {
+			if delegated == nil && *step.ApproverUserID == delegatorID {
+				delegated = step
+				onBehalfOf = step.ApproverUserID
+			}
+		}
+	}
+	if delegated != nil {
+		return delegated, onBehalfOf
+	}
+	if byRole != nil {
+		return byRole, nil
+	}
+	return byDelegatedRole, roleOnBehalfOf
+}
+
+// alreadyDecided reports whether the user decided a step of the request, themselves or through a delegate
+func alreadyDecided(request *models.ApprovalRequest, userID string) bool {
+	for _, step := range request.Steps {
+		if (step.ActedBy != nil && *step.ActedBy == userID) || (step.OnBehalfOf != nil && *step.OnBehalfOf == userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastDecision returns the step decided last, the one that completed a completed request
+func lastDecision(request *models.ApprovalRequest) *models.ApprovalStep {
+	var last *models.ApprovalStep
+	for _, step := range request.Steps {
+		if step.ActedAt != nil && (last == nil || step.ActedAt.After(*last.ActedAt)) {
+			last = step
+		}
+	}
+	return last
+}
+
+// nextTransition returns how the request moves after a step got status, nil while other
+// steps of the current stage are still pending
+func nextTransition(request *models.ApprovalRequest, status string, now time.Time) *models.ApprovalTransition {
+	switch status {
+	case models.ApprovalStepRejected:
+		return &models.ApprovalTransition{Status: models.ApprovalStatusRejected, Stage: request.CurrentStage, CompletedAt: &now}
+	case models.ApprovalStepReturned:
+		return &models.ApprovalTransition{Status: models.ApprovalStatusReturned, Stage: request.CurrentStage, CompletedAt: &now}
+	}
+
+	next := 0
+	for _, step := range request.Steps {
+		if step.Stage == request.CurrentStage && step.Status == models.ApprovalStepPending {
+			return nil
+		}
+		if step.Stage > request.CurrentStage && (next == 0 || step.Stage < next) {
+			next = step.Stage
+		}
+	}
+	if next == 0 {
+		return &models.ApprovalTransition{Status: models.ApprovalStatusApproved, Stage: request.CurrentStage, CompletedAt: &now}
+	}
+	return &models.ApprovalTransition{Status: models.ApprovalStatusPending, Stage: next}
+}
@@ -0,0 +1,471 @@
+package usecases_test
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/repositories/memory"
+	"e-procurement/internals/usecases"
+	"e-procurement/pkg/apperror"
+	"e-procurement/pkg/rbac"
+	"errors"
+	"testing"
+	"time"
+)
+
+// recordingHandler remembers the approvals completed for its document type
+type recordingHandler struct {
+	completed []*models.ApprovalStep
+}
+
+func (h *recordingHandler) ApprovalCompleted(ctx context.Context, request *models.ApprovalRequest, decision *models.ApprovalStep) error {
+	h.completed = append(h.completed, decision)
+	return nil
+}
+
+// racingApprovals runs race right before the next decision is written, like a concurrent
+// request of the same person that passed the usecase checks at the same time
+type racingApprovals struct {
+	*memory.ApprovalRepository
+	race func(ctx context.Context)
+}
+
+func (r *racingApprovals) Decide(ctx context.Context, stepID string, decision *models.ApprovalStepDecision) (bool, error) {
+	if race := r.race; race != nil {
+		r.race = nil
+		race(ctx)
+	}
+	return r.ApprovalRepository.Decide(ctx, stepID, decision)
+}
+
+// approvalFixture is an organization with a requester, two approvers, an auditor and a director
+type approvalFixture struct {
+	org       *testOrg
+	admin     string
+	requester string
+	approver  string
+	approver2 string
+	auditor   string
+	director  string
+	approvals *usecases.ApprovalUseCase
+	handler   *recordingHandler
+}
+
+func newApprovalFixture(t *testing.T) *approvalFixture {
+	t.Helper()
+	org := newTestOrg(t)
+	f := &approvalFixture{
+		org:       org,
+		admin:     org.addUser("admin", rbac.RoleAdmin),
+		requester: org.addUser("requester", rbac.RoleProcurementOfficer),
+		approver:  org.addUser("approver", rbac.RoleApprover),
+		approver2: org.addUser("approver2", rbac.RoleApprover),
+		auditor:   org.addUser("auditor", rbac.RoleAuditor),
+		director:  org.addUser("director", rbac.RoleAdmin),
+		approvals: org.approvals(),
+		handler:   &recordingHandler{},
+	}
+	f.approvals.Register(models.ApprovalDocumentRequisition, f.handler)
+	return f
+}
+
+func roleStep(level int, name, role string) *models.ApprovalRuleStepRequest {
+	return &models.ApprovalRuleStepRequest{Level: level, Name: name, ApproverType: models.ApproverTypeRole, ApproverRole: role}
+}
+
+func userStep(level int, name, userID string) *models.ApprovalRuleStepRequest {
+	return &models.ApprovalRuleStepRequest{Level: level, Name: name, ApproverType: models.ApproverTypeUser, ApproverUserID: &userID}
+}
+
+// start creates a rule with the steps and submits a requisition through it
+func (f *approvalFixture) start(t *testing.T, steps ...*models.ApprovalRuleStepRequest) *models.ApprovalRequest {
+	t.Helper()
+	_, err := f.approvals.CreateRule(f.org.as(f.admin), &models.ApprovalRuleRequest{
+		DocumentType: models.ApprovalDocumentRequisition,
+		Name:         "Requisitions",
+		Steps:        steps,
+	})
+	if err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+	return f.submit(t, "requisition-1")
+}
+
+func (f *approvalFixture) submit(t *testing.T, documentID string) *models.ApprovalRequest {
+	t.Helper()
+	request, err := f.approvals.Start(f.org.as(f.requester), &models.ApprovalSubject{
+		DocumentType: models.ApprovalDocumentRequisition,
+		DocumentID:   documentID,
+		Title:        "Office chairs",
+		Amount:       60000000,
+		RequestedBy:  f.requester,
+	})
+	if err != nil {
+		t.Fatalf("start approval: %v", err)
+	}
+	return request
+}
+
+func (f *approvalFixture) approve(userID, requestID string) (*models.ApprovalRequest, error) {
+	return f.approvals.Approve(f.org.as(userID), requestID, &models.ApprovalDecisionRequest{Comment: "ok"})
+}
+
+func (f *approvalFixture) delegate(t *testing.T, delegatorID, delegateID string) {
+	t.Helper()
+	now := time.Now()
+	_, err := f.approvals.Delegate(f.org.as(delegatorID), &models.ApprovalDelegationRequest{
+		DelegateID: delegateID,
+		StartsAt:   now.Add(-time.Hour),
+		EndsAt:     now.Add(time.Hour),
+		Reason:     "leave",
+	})
+	if err != nil {
+		t.Fatalf("delegate: %v", err)
+	}
+}
+
+// pendingCount returns how many steps wait for the user
+func (f *approvalFixture) pendingCount(t *testing.T, userID string) int {
+	t.Helper()
+	_, count, err := f.approvals.Pending(f.org.as(userID), 10, 1)
+	if err != nil {
+		t.Fatalf("pending approvals: %v", err)
+	}
+	return count
+}
+
+func assertApproval(t *testing.T, request *models.ApprovalRequest, status string, stage int) {
+	t.Helper()
+	if request.Status != status || request.CurrentStage != stage {
+		t.Fatalf("approval is %s at stage %d, want %s at stage %d", request.Status, request.CurrentStage, status, stage)
+	}
+}
+
+func TestApprovalSequentialAdvance(t *testing.T) {
+	f := newApprovalFixture(t)
+	request := f.start(t, roleStep(1, "Manager", rbac.RoleApprover), userStep(2, "Finance director", f.director))
+	assertApproval(t, request, models.ApprovalStatusPending, 1)
+	if got := f.pendingCount(t, f.director); got != 0 {
+		t.Fatalf("director sees %d pending steps before stage 2 started", got)
+	}
+
+	request, err := f.approve(f.approver, request.ID)
+	assertAppError(t, err, 0, "")
+	assertApproval(t, request, models.ApprovalStatusPending, 2)
+	if len(f.handler.completed) != 0 {
+		t.Fatal("handler notified before the approval completed")
+	}
+	if got := f.pendingCount(t, f.director); got != 1 {
+		t.Fatalf("director sees %d pending steps, want 1", got)
+	}
+
+	request, err = f.approve(f.director, request.ID)
+	assertAppError(t, err, 0, "")
+	assertApproval(t, request, models.ApprovalStatusApproved, 2)
+	if len(f.handler.completed) != 1 || f.handler.completed[0].Status != models.ApprovalStepApproved {
+		t.Fatalf("handler notified with %v, want one approved decision", f.handler.completed)
+	}
+
+	_, err = f.approve(f.approver2, request.ID)
+	assertAppError(t, err, apperror.KindConflict, "approval_not_pending")
+}
+
+func TestApprovalParallelCompletion(t *testing.T) {
+	f := newApprovalFixture(t)
+	request := f.start(t, roleStep(1, "Manager", rbac.RoleApprover), roleStep(1, "Audit", rbac.RoleAuditor))
+
+	request, err := f.approve(f.auditor, request.ID)
+	assertAppError(t, err, 0, "")
+	assertApproval(t, request, models.ApprovalStatusPending, 1)
+
+	_, err = f.approve(f.auditor, request.ID)
+	assertAppError(t, err, apperror.KindForbidden, "approval_not_assigned")
+
+	request, err = f.approve(f.approver, request.ID)
+	assertAppError(t, err, 0, "")
+	assertApproval(t, request, models.ApprovalStatusApproved, 1)
+	if len(f.handler.completed) != 1 {
+		t.Fatalf("handler notified %d times, want once", len(f.handler.completed))
+	}
+}
+
+func TestApprovalDelegation(t *testing.T) {
+	f := newApprovalFixture(t)
+	request := f.start(t, userStep(1, "Finance director", f.director))
+	f.delegate(t, f.director, f.approver2)
+
+	if got := f.pendingCount(t, f.approver2); got != 1 {
+		t.Fatalf("delegate sees %d pending steps, want 1", got)
+	}
+	_, err := f.approve(f.approver, request.ID)
+	assertAppError(t, err, apperror.KindForbidden, "approval_not_assigned")
+
+	request, err = f.approve(f.approver2, request.ID)
+	assertAppError(t, err, 0, "")
+	assertApproval(t, request, models.ApprovalStatusApproved, 1)
+	step := request.Steps[0]
+	if step.ActedBy == nil || *step.ActedBy != f.approver2 || step.OnBehalfOf == nil || *step.OnBehalfOf != f.director {
+		t.Fatalf("step acted by %v on behalf of %v, want the delegate for the director", step.ActedBy, step.OnBehalfOf)
+	}
+}
+
+func TestApprovalDelegationOfRoleStep(t *testing.T) {
+	f := newApprovalFixture(t)
+	finance := f.org.addUser("finance", rbac.RoleFinance)
+	request := f.start(t, roleStep(1, "Finance review", rbac.RoleFinance), roleStep(1, "Procurement review", rbac.RoleProcurementOfficer))
+	f.delegate(t, finance, f.auditor)
+
+	// the requester holds the procurement role, their delegate does not act for them on their own document
+	f.delegate(t, f.requester, f.approver)
+	_, err := f.approve(f.approver, request.ID)
+	assertAppError(t, err, apperror.KindForbidden, "approval_not_assigned")
+
+	if got := f.pendingCount(t, f.auditor); got != 1 {
+		t.Fatalf("delegate sees %d pending steps, want 1", got)
+	}
+	request, err = f.approve(f.auditor, request.ID)
+	assertAppError(t, err, 0, "")
+	assertApproval(t, request, models.ApprovalStatusPending, 1)
+	for _, step := range request.Steps {
+		if step.Name != "Finance review" {
+			continue
+		}
+		if step.ActedBy == nil || *step.ActedBy != f.auditor || step.OnBehalfOf == nil || *step.OnBehalfOf != finance {
+			t.Fatalf("step acted by %v on behalf of %v, want the delegate for the finance member", step.ActedBy, step.OnBehalfOf)
+		}
+	}
+}
+
+func TestApprovalDelegationChecks(t *testing.T) {
+	f := newApprovalFixture(t)
+	now := time.Now()
+	tests := []struct {
+		name       string
+		delegateID string
+		startsAt   time.Time
+		endsAt     time.Time
+		wantCode   string
+	}{
+		{name: "member", delegateID: f.approver2, startsAt: now, endsAt: now.Add(time.Hour)},
+		{name: "not a member", delegateID: "00000000-0000-4000-8000-000000000000", startsAt: now, endsAt: now.Add(time.Hour), wantCode: "delegate_not_member"},
+		{name: "ends before it starts", delegateID: f.approver2, startsAt: now.Add(time.Hour), endsAt: now, wantCode: "invalid_delegation_period"},
+		{name: "already over", delegateID: f.approver2, startsAt: now.Add(-2 * time.Hour), endsAt: now.Add(-time.Hour), wantCode: "invalid_delegation_period"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.approvals.Delegate(f.org.as(f.approver), &models.ApprovalDelegationRequest{
+				DelegateID: tt.delegateID,
+				StartsAt:   tt.startsAt,
+				EndsAt:     tt.endsAt,
+			})
+			assertAppError(t, err, apperror.KindValidation, tt.wantCode)
+		})
+	}
+}
+
+func TestApprovalReturnForRevision(t *testing.T) {
+	f := newApprovalFixture(t)
+	request := f.start(t, roleStep(1, "Manager", rbac.RoleApprover), userStep(2, "Finance director", f.director))
+
+	_, err := f.approvals.Return(f.org.as(f.approver), request.ID, &models.ApprovalDecisionRequest{})
+	assertAppError(t, err, apperror.KindValidation, "comment_required")
+
+	request, err = f.approvals.Return(f.org.as(f.approver), request.ID, &models.ApprovalDecisionRequest{Comment: "add quotes"})
+	assertAppError(t, err, 0, "")
+	assertApproval(t, request, models.ApprovalStatusReturned, 1)
+	for _, step := range request.Steps {
+		if step.Stage == 2 && step.Status != models.ApprovalStepSkipped {
+			t.Fatalf("step of stage 2 is %s, want skipped", step.Status)
+		}
+	}
+	if len(f.handler.completed) != 1 || f.handler.completed[0].Status != models.ApprovalStepReturned {
+		t.Fatalf("handler notified with %v, want one returned decision", f.handler.completed)
+	}
+
+	// the revised document starts a new round in which the same approver decides again
+	resubmitted := f.submit(t, "requisition-1")
+	if resubmitted.ID == request.ID {
+		t.Fatal("resubmission reused the returned approval")
+	}
+	resubmitted, err = f.approve(f.approver, resubmitted.ID)
+	assertAppError(t, err, 0, "")
+	assertApproval(t, resubmitted, models.ApprovalStatusPending, 2)
+}
+
+func TestApprovalOnePersonDecidesOneStep(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps func(f *approvalFixture) []*models.ApprovalRuleStepRequest
+		// setup runs before the first decision, e.g. to delegate
+		setup func(t *testing.T, f *approvalFixture)
+		// first decides stage 1, second then tries stage 2
+		first, second func(f *approvalFixture) string
+	}{
+		{
+			name: "named approver of a later level already approved by role",
+			steps: func(f *approvalFixture) []*models.ApprovalRuleStepRequest {
+				return []*models.ApprovalRuleStepRequest{roleStep(1, "Manager", rbac.RoleApprover), userStep(2, "Second signature", f.approver)}
+			},
+			first:  func(f *approvalFixture) string { return f.approver },
+			second: func(f *approvalFixture) string { return f.approver },
+		},
+		{
+			name: "delegate acting for a director after approving themselves",
+			steps: func(f *approvalFixture) []*models.ApprovalRuleStepRequest {
+				return []*models.ApprovalRuleStepRequest{roleStep(1, "Manager", rbac.RoleApprover), userStep(2, "Finance director", f.director)}
+			},
+			setup:  func(t *testing.T, f *approvalFixture) { f.delegate(t, f.director, f.approver) },
+			first:  func(f *approvalFixture) string { return f.approver },
+			second: func(f *approvalFixture) string { return f.approver },
+		},
+		{
+			name: "director after their delegate approved for them",
+			steps: func(f *approvalFixture) []*models.ApprovalRuleStepRequest {
+				return []*models.ApprovalRuleStepRequest{userStep(1, "Finance director", f.director), roleStep(2, "Admin", rbac.RoleAdmin)}
+			},
+			setup:  func(t *testing.T, f *approvalFixture) { f.delegate(t, f.director, f.approver) },
+			first:  func(f *approvalFixture) string { return f.approver },
+			second: func(f *approvalFixture) string { return f.director },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newApprovalFixture(t)
+			request := f.start(t, tt.steps(f)...)
+			if tt.setup != nil {
+				tt.setup(t, f)
+			}
+
+			request, err := f.approve(tt.first(f), request.ID)
+			assertAppError(t, err, 0, "")
+			assertApproval(t, request, models.ApprovalStatusPending, 2)
+
+			if got := f.pendingCount(t, tt.second(f)); got != 0 {
+				t.Fatalf("%d pending steps listed for a user who cannot decide them", got)
+			}
+			_, err = f.approve(tt.second(f), request.ID)
+			assertAppError(t, err, apperror.KindForbidden, "approval_already_decided")
+		})
+	}
+}
+
+func TestApprovalConcurrentDecisionsOfOnePerson(t *testing.T) {
+	f := newApprovalFixture(t)
+	approvals := &racingApprovals{ApprovalRepository: memory.NewApprovalRepository(f.org.store)}
+	f.approvals = usecases.NewApprovalUseCase(
+		memory.NewApprovalRuleRepository(f.org.store),
+		approvals,
+		memory.NewApprovalDelegationRepository(f.org.store),
+		memory.NewOrganizationRepository(f.org.store),
+		usecases.NewDepartmentUseCase(memory.NewDepartmentRepository(f.org.store), memory.NewCostCenterRepository(f.org.store)),
+	)
+	f.approvals.Register(models.ApprovalDocumentRequisition, f.handler)
+	request := f.start(t, roleStep(1, "Manager", rbac.RoleApprover), userStep(1, "Second signature", f.approver))
+
+	// the approver signs the role step in another request while this one signs their named step
+	approvals.race = func(ctx context.Context) {
+		for _, step := range request.Steps {
+			if step.Name != "Manager" {
+				continue
+			}
+			decided, err := approvals.ApprovalRepository.Decide(ctx, step.ID, &models.ApprovalStepDecision{
+				Status:  models.ApprovalStepApproved,
+				ActedBy: f.approver,
+				ActedAt: time.Now(),
+			})
+			if err != nil || !decided {
+				t.Errorf("concurrent decision: %v, %v", decided, err)
+			}
+		}
+	}
+	_, err := f.approve(f.approver, request.ID)
+	assertAppError(t, err, apperror.KindForbidden, "approval_already_decided")
+
+	request, err = f.approvals.Get(f.org.as(f.admin), request.ID)
+	assertAppError(t, err, 0, "")
+	assertApproval(t, request, models.ApprovalStatusPending, 1)
+	for _, step := range request.Steps {
+		if step.Name == "Second signature" && step.Status != models.ApprovalStepPending {
+			t.Fatalf("second signature is %s, want pending", step.Status)
+		}
+	}
+}
+
+// flakyHandler fails the first completions it receives, like a database hiccup right after
+// the approval was completed
+type flakyHandler struct {
+	usecases.ApprovalHandler
+	failures int
+}
+
+func (h *flakyHandler) ApprovalCompleted(ctx context.Context, request *models.ApprovalRequest, decision *models.ApprovalStep) error {
+	if h.failures > 0 {
+		h.failures--
+		return errors.New("connection reset")
+	}
+	return h.ApprovalHandler.ApprovalCompleted(ctx, request, decision)
+}
+
+func TestApprovalCompletionRetry(t *testing.T) {
+	f := newApprovalFixture(t)
+	departments := usecases.NewDepartmentUseCase(memory.NewDepartmentRepository(f.org.store), memory.NewCostCenterRepository(f.org.store))
+	requisitions := usecases.NewRequisitionUseCase(memory.NewRequisitionRepository(f.org.store), memory.NewProductRepository(f.org.store), departments, f.approvals)
+	f.approvals.Register(models.ApprovalDocumentRequisition, &flakyHandler{ApprovalHandler: requisitions, failures: 1})
+	_, err := f.approvals.CreateRule(f.org.as(f.admin), &models.ApprovalRuleRequest{
+		DocumentType: models.ApprovalDocumentRequisition,
+		Name:         "Requisitions",
+		Steps:        []*models.ApprovalRuleStepRequest{roleStep(1, "Manager", rbac.RoleApprover)},
+	})
+	assertAppError(t, err, 0, "")
+
+	requisition, err := requisitions.Create(f.org.as(f.requester), &models.RequisitionRequest{
+		Title:         "Office chairs",
+		Justification: "new hires",
+		Lines:         []*models.RequisitionLineRequest{{Description: "Chair", Quantity: 3, UnitPrice: 1000, NeededBy: "2026-12-01"}},
+	})
+	assertAppError(t, err, 0, "")
+	_, err = requisitions.Submit(f.org.as(f.requester), requisition.ID)
+	assertAppError(t, err, 0, "")
+	requests, _, err := f.approvals.List(f.org.as(f.admin), models.ApprovalFilter{DocumentID: requisition.ID}, 10, 1)
+	if err != nil || len(requests) != 1 {
+		t.Fatalf("requisition has %d approvals, %v", len(requests), err)
+	}
+	request := requests[0]
+	_, err = f.approvals.Complete(f.org.as(f.admin), request.ID)
+	assertAppError(t, err, apperror.KindConflict, "approval_not_completed")
+
+	if _, err := f.approve(f.approver, request.ID); err == nil {
+		t.Fatal("approve succeeded although the requisition was not updated")
+	}
+	request, err = f.approvals.Get(f.org.as(f.admin), request.ID)
+	assertAppError(t, err, 0, "")
+	assertApproval(t, request, models.ApprovalStatusApproved, 1)
+	requisition, err = requisitions.Get(f.org.as(f.admin), requisition.ID)
+	if err != nil || requisition.Status != models.RequisitionStatusSubmitted {
+		t.Fatalf("requisition is %s, %v, want submitted until the completion is retried", requisition.Status, err)
+	}
+
+	// applying the approval twice leaves the requisition approved
+	for range 2 {
+		_, err = f.approvals.Complete(f.org.as(f.admin), request.ID)
+		assertAppError(t, err, 0, "")
+		requisition, err = requisitions.Get(f.org.as(f.admin), requisition.ID)
+		if err != nil || requisition.Status != models.RequisitionStatusApproved {
+			t.Fatalf("requisition is %s, %v, want approved", requisition.Status, err)
+		}
+	}
+}
+
+func TestApprovalCompletionOfSupersededApproval(t *testing.T) {
+	f := newApprovalFixture(t)
+	returned := f.start(t, roleStep(1, "Manager", rbac.RoleApprover))
+	_, err := f.approvals.Return(f.org.as(f.approver), returned.ID, &models.ApprovalDecisionRequest{Comment: "add a quote"})
+	assertAppError(t, err, 0, "")
+	f.submit(t, "requisition-1")
+
+	// the returned approval must not move the resubmitted requisition back to draft
+	_, err = f.approvals.Complete(f.org.as(f.admin), returned.ID)
+	assertAppError(t, err, apperror.KindConflict, "approval_superseded")
+	if len(f.handler.completed) != 1 {
+		t.Fatalf("handler completed %d approvals, want 1", len(f.handler.completed))
+	}
+}
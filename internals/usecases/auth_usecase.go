@@ -3,92 +3,385 @@ package usecases
 import (
 	"context"
 	"e-procurement/internals/domain/models"
-	"e-procurement/internals/repositories"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
 	"e-procurement/pkg/auth"
-	"e-procurement/pkg/encripted"
+	customContext "e-procurement/pkg/context"
+	"e-procurement/pkg/identifier"
+	"e-procurement/pkg/rbac"
 	"errors"
 	"fmt"
+	"log"
+	"time"
+)
+
+var (
+	errInvalidCredentials = apperror.Unauthorized("invalid_credentials", "invalid email or password")
+	errInvalidRefreshToken = apperror.Unauthorized("invalid_refresh_token", "refresh token is invalid or expired")
+	// returned for logged out or already rotated tokens, the latter also revokes the family
+	errRefreshTokenRevoked = apperror.Unauthorized("refresh_token_revoked", "refresh token has been revoked, please log in again")
+	errNoOrganization = apperror.Forbidden("no_organization", "user is not a member of any organization")
+	errOrganizationAccessDenied = apperror.Forbidden("organization_access_denied", "user is not a member of the organization")
 )
 
 type AuthUseCase struct {
-	repo *repositories.UserRepository
-	encripted *encripted.Encripted
+	repo repository.UserRepository
+	organizationRepo repository.OrganizationRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	mfaRepo repository.MFARepository
+	throttle *LoginThrottle
+	revocations *TokenRevocationUseCase
+	verification *EmailVerificationUseCase
+	passwords *PasswordManager
+	// hash checked for unknown emails so they take as long as a wrong password
+	dummyHash string
 	jwt *auth.JWT
+	refreshTokenTTL time.Duration
+	mfaChallengeTTL time.Duration
+	// code of the organization joined on registration when the request names none
+	defaultOrganization string
 }
 
 func NewAuthUseCase(
-	repo *repositories.UserRepository,
+	repo repository.UserRepository,
+	organizationRepo repository.OrganizationRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	mfaRepo repository.MFARepository,
+	throttle *LoginThrottle,
+	passwords *PasswordManager,
+	revocations *TokenRevocationUseCase,
+	verification *EmailVerificationUseCase,
 	JWT *auth.JWT,
+	refreshTokenTTL time.Duration,
+	mfaChallengeTTL time.Duration,
+	defaultOrganization string,
 	) *AuthUseCase {
+	dummyHash, _ := passwords.Hash("dummy password for unknown users")
 	return &AuthUseCase{
 		repo: repo,
+		organizationRepo: organizationRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		mfaRepo: mfaRepo,
+		throttle: throttle,
+		revocations: revocations,
+		verification: verification,
 		jwt: JWT,
-		encripted: encripted.NewEncripted(),
+		passwords: passwords,
+		dummyHash: dummyHash,
+		refreshTokenTTL: refreshTokenTTL,
+		mfaChallengeTTL: mfaChallengeTTL,
+		defaultOrganization: defaultOrganization,
 	}
 }
 
-func(u *AuthUseCase) Authenticate(ctx context.Context, data *models.LoginRequest)(*models.UserResponse,string, error) {
+// method to log in with email and password into data.OrganizationID, or the oldest
+// organization of the user when empty. users with two-factor authentication enabled
+// get a short-lived challenge token instead, exchanged for tokens through MFAUseCase.Verify.
+// attempts go through the login throttle, unknown emails and wrong passwords are
+// indistinguishable to the caller.
+func(u *AuthUseCase) Authenticate(ctx context.Context, data *models.LoginRequest, clientIP string)(*models.LoginResult, error) {
+	if err := u.throttle.Check(ctx, data.Email, clientIP); err != nil {
+		return nil, err
+	}
 	
 	user, err := u.repo.Authenticate(ctx, data.Email)
 	if err != nil {
-		return nil,"", errors.New("invalid email or password")
+		return nil, fmt.Errorf("failed to authenticate user: %w", err)
 	}
 
 	if user == nil {
-		return nil,"", errors.New("invalid email or password")
+		// spend the time of a password check so response times do not reveal unknown emails
+		u.passwords.hasher.CheckPasswordHash(u.dummyHash, data.Password)
+		return nil, u.loginFailed(ctx, data.Email, clientIP, "")
 	}
 
-	isValid, err := u.encripted.CheckPasswordHash(user.Password, data.Password)
+	// upgrades the stored hash when the hashing algorithm or cost changed
+	isValid, err := u.passwords.Verify(ctx, user.ID, user.Password, data.Password)
 	if err != nil {
-		return nil,"", errors.New("error checking password")
+		return nil, errors.New("error checking password")
 	}
 
 	if !isValid {
-		return nil,"",errors.New("invalid email or password")
+		return nil, u.loginFailed(ctx, data.Email, clientIP, user.ID)
+	}
+	if err := u.throttle.Record(ctx, data.Email, clientIP, user.ID, models.LoginOutcomeSuccess); err != nil {
+		return nil, err
+	}
+	membership, err := u.resolveMembership(ctx, user.ID, data.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	mfaEnabled, err := u.mfaEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfaEnabled {
+		challenge, err := u.jwt.GenerateChallengeToken(user.ID, membership.OrganizationID, u.mfaChallengeTTL)
+		if err != nil {
+			return nil, errors.New("error generating token")
+		}
+		return &models.LoginResult{
+			Challenge: &models.MFAChallengeResponse{
+				MFARequired: true,
+				MFAToken:    challenge,
+				ExpiresIn:   int64(u.mfaChallengeTTL.Seconds()),
+			},
+		}, nil
+	}
+
+	return u.login(ctx, user, membership)
+}
+
+// resolveMembership returns the membership the user logs in with, organizationID
+// empty picks the organization the user joined first
+func (u *AuthUseCase) resolveMembership(ctx context.Context, userID, organizationID string) (*models.OrganizationMembership, error) {
+	if organizationID == "" {
+		memberships, err := u.organizationRepo.ListForUser(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list organizations: %w", err)
+		}
+		if len(memberships) == 0 {
+			return nil, errNoOrganization
+		}
+		return memberships[0], nil
+	}
+
+	membership, err := u.organizationRepo.GetMembership(ctx, organizationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get membership: %w", err)
 	}
+	if membership == nil {
+		return nil, errOrganizationAccessDenied
+	}
+	return membership, nil
+}
+
+// loginFailed records a wrong password or unknown email and returns the error for the caller
+func (u *AuthUseCase) loginFailed(ctx context.Context, email, clientIP, userID string) error {
+	if err := u.throttle.Record(ctx, email, clientIP, userID, models.LoginOutcomeInvalidCredentials); err != nil {
+		return err
+	}
+	return errInvalidCredentials
+}
 
-	token, err := u.jwt.GenerateToken(user.ID, user.Role)
+// login starts a new refresh token family for a user whose credentials were checked,
+// the tokens act in the organization of the membership
+func (u *AuthUseCase) login(ctx context.Context, user *models.User, membership *models.OrganizationMembership) (*models.LoginResult, error) {
+	tokens, err := u.issueTokens(ctx, user, membership, identifier.NewUUID())
 	if err != nil {
-		return nil, "", errors.New("error generating token")
+		return nil, err
 	}
 	userResponse := &models.UserResponse{
 		ID:        	user.ID,
 		UserName: 	user.UserName,
 		Email:     	user.Email,
-		Role: 		user.Role,
+		Role: 		membership.Role,
+		OrganizationID: membership.OrganizationID,
+		EmailVerifiedAt: user.EmailVerifiedAt,
 		CreatedAt: 	user.CreatedAt,
 		UpdatedAt: 	user.UpdatedAt,
 	}
-	return userResponse,token, nil
+	return &models.LoginResult{User: userResponse, Tokens: tokens}, nil
 }
 
+// mfaEnabled reports whether the user confirmed a TOTP enrollment
+func (u *AuthUseCase) mfaEnabled(ctx context.Context, userID string) (bool, error) {
+	mfa, err := u.mfaRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get mfa enrollment: %w", err)
+	}
+	return mfa != nil && mfa.EnabledAt != nil, nil
+}
 
+
+// method to register a user. the user joins the organization named by OrganizationCode,
+// or the configured default organization, with the default role.
 func (u *AuthUseCase) Create(ctx context.Context, user *models.CreateUserRequest) (*models.UserResponse, error) {
 	exists, err := u.repo.IsUserExists(ctx, user.Email)
 	if err != nil {
 		return nil, fmt.Errorf("error checking user existence: %v", err)
 	}
 	if exists {
-		return nil, errors.New("user already exists")
+		return nil, apperror.Conflict("user_already_exists", "user already exists")
 	}
 
-	user.Password, err = u.encripted.HashPassword(user.Password)
+	code := user.OrganizationCode
+	if code == "" {
+		code = u.defaultOrganization
+	}
+	org, err := u.organizationRepo.GetByCode(ctx, code)
 	if err != nil {
-		return nil, fmt.Errorf("error hashing password: %v", err)
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	if org == nil {
+		return nil, apperror.Validation("unknown_organization", fmt.Sprintf("organization %q does not exist", code))
+	}
+
+	user.Password, err = u.passwords.Prepare(ctx, "", user.Password, user.UserName, user.Email)
+	if err != nil {
+		return nil, err
 	}
 
 	resp, err := u.repo.Create(ctx, user)
 	if err != nil {
 		return nil, fmt.Errorf("error creating user: %v", err)
 	}
+	if err := u.passwords.Remember(ctx, resp.ID, user.Password); err != nil {
+		return nil, err
+	}
+	if err := u.organizationRepo.AddMember(ctx, org.ID, resp.ID, rbac.DefaultRole); err != nil {
+		return nil, fmt.Errorf("error joining organization: %w", err)
+	}
+	// the account exists at this point, a lost email can be sent again through /auth/verify/resend
+	if err := u.verification.SendVerification(ctx, resp.ID, resp.UserName, resp.Email); err != nil {
+		log.Printf("registration of user %s: %v", resp.ID, err)
+	}
 	userResponse := &models.UserResponse{
 		ID:        resp.ID,
 		UserName:  resp.UserName,
 		Email:     resp.Email,
-		Role:      resp.Role,
+		Role:      rbac.DefaultRole,
+		OrganizationID: org.ID,
 		CreatedAt: resp.CreatedAt,
 		UpdatedAt: resp.UpdatedAt,
 	}
 
 	return userResponse, nil
 }
+
+
+// issueTokens generates an access token and a new refresh token in the given family,
+// both bound to the organization of the membership
+func (u *AuthUseCase) issueTokens(ctx context.Context, user *models.User, membership *models.OrganizationMembership, familyID string) (*models.TokenPair, error) {
+	mfaEnabled, err := u.mfaEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := u.jwt.GenerateToken(user.ID, membership.Role, membership.OrganizationID, mfaEnabled)
+	if err != nil {
+		return nil, errors.New("error generating token")
+	}
+
+	refreshToken, refreshTokenHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("error generating refresh token: %w", err)
+	}
+	_, err = u.refreshTokenRepo.Create(ctx, &models.RefreshToken{
+		UserID:    user.ID,
+		OrganizationID: membership.OrganizationID,
+		FamilyID:  familyID,
+		TokenHash: refreshTokenHash,
+		ExpiresAt: time.Now().Add(u.refreshTokenTTL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error storing refresh token: %w", err)
+	}
+
+	return &models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(u.jwt.AccessTokenTTL().Seconds()),
+	}, nil
+}
+
+// method to rotate a refresh token: the presented token is revoked and a new pair is issued
+// in the same family. presenting an already rotated token revokes the whole family.
+func (u *AuthUseCase) Refresh(ctx context.Context, req *models.RefreshTokenRequest) (*models.TokenPair, error) {
+	stored, err := u.refreshTokenRepo.GetByHash(ctx, auth.HashOpaqueToken(req.RefreshToken))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	if stored == nil {
+		return nil, errInvalidRefreshToken
+	}
+
+	if stored.RevokedAt != nil {
+		// reuse of a rotated token means it leaked, kill every session of that login
+		if err := u.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return nil, fmt.Errorf("failed to revoke refresh token family: %w", err)
+		}
+		return nil, errRefreshTokenRevoked
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, errInvalidRefreshToken
+	}
+
+	revoked, err := u.refreshTokenRepo.Revoke(ctx, stored.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	if !revoked {
+		// a concurrent request rotated the same token first
+		if err := u.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return nil, fmt.Errorf("failed to revoke refresh token family: %w", err)
+		}
+		return nil, errRefreshTokenRevoked
+	}
+
+	// reload the user and membership so role changes, removals and deletions are picked up
+	user, err := u.repo.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+	if user == nil {
+		return nil, errInvalidRefreshToken
+	}
+	membership, err := u.organizationRepo.GetMembership(ctx, stored.OrganizationID, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get membership: %w", err)
+	}
+	if membership == nil {
+		return nil, errInvalidRefreshToken
+	}
+
+	return u.issueTokens(ctx, user, membership, stored.FamilyID)
+}
+
+// method to move the caller to another organization it is a member of. a new token pair
+// in a new family is issued, the tokens of the current organization stay valid.
+func (u *AuthUseCase) SwitchOrganization(ctx context.Context, req *models.SwitchOrganizationRequest) (*models.LoginResult, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := u.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+	if user == nil {
+		return nil, errUserNotFound(userID)
+	}
+	membership, err := u.resolveMembership(ctx, user.ID, req.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	return u.login(ctx, user, membership)
+}
+
+// method to log out: revokes the whole family of the presented refresh token and,
+// when the request carries a valid access token, that access token as well.
+// unknown tokens are ignored so logout is idempotent.
+func (u *AuthUseCase) Logout(ctx context.Context, req *models.RefreshTokenRequest) error {
+	if tokenID, expiresAt, err := customContext.GetTokenFromContext(ctx); err == nil {
+		userID, err := customContext.GetUserIDFromContext(ctx)
+		if err != nil {
+			return err
+		}
+		if err := u.revocations.RevokeToken(ctx, tokenID, userID, expiresAt); err != nil {
+			return err
+		}
+	}
+
+	stored, err := u.refreshTokenRepo.GetByHash(ctx, auth.HashOpaqueToken(req.RefreshToken))
+	if err != nil {
+		return fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	if stored == nil {
+		return nil
+	}
+	if err := u.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	return nil
+}
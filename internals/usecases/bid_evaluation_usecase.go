@@ -0,0 +1,444 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	customContext "e-procurement/pkg/context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// maxVendorRating is the top of the vendor rating scale, ratings are normalized against it
+const maxVendorRating = 5
+
+var (
+	errEvaluationClosed = apperror.Conflict("rfq_evaluation_closed", "the RFQ is awarded or cancelled, its evaluation can no longer change")
+	errRFQAwarded       = apperror.Conflict("rfq_already_awarded", "the RFQ is already awarded")
+)
+
+// BidEvaluationUseCase scores the quotations of an RFQ against weighted criteria and records
+// the award decision. Prices and lead times are normalized against the best offer, vendor
+// rating and technical score are entered by the buyer per quotation.
+type BidEvaluationUseCase struct {
+	rfqs                    *RFQUseCase
+	purchaseOrders          *PurchaseOrderUseCase
+	bidEvaluationRepository repository.BidEvaluationRepository
+	awardRepository         repository.RFQAwardRepository
+}
+
+func NewBidEvaluationUseCase(rfqs *RFQUseCase, purchaseOrders *PurchaseOrderUseCase, bidEvaluationRepo repository.BidEvaluationRepository, awardRepo repository.RFQAwardRepository) *BidEvaluationUseCase {
+	return &BidEvaluationUseCase{
+		rfqs:                    rfqs,
+		purchaseOrders:          purchaseOrders,
+		bidEvaluationRepository: bidEvaluationRepo,
+		awardRepository:         awardRepo,
+	}
+}
+
+// Criteria returns the evaluation criteria of an RFQ, price only when none were set
+func (u *BidEvaluationUseCase) Criteria(ctx context.Context, rfqID string) (*models.EvaluationCriteria, error) {
+	if _, err := u.rfqs.Get(ctx, rfqID); err != nil {
+		return nil, err
+	}
+	return u.criteria(ctx, rfqID)
+}
+
+// SetCriteria replaces the evaluation criteria of an RFQ not yet awarded or cancelled.
+// Weights are kept to two decimals and must add up to 100.
+func (u *BidEvaluationUseCase) SetCriteria(ctx context.Context, rfqID string, req *models.EvaluationCriteriaRequest) (*models.EvaluationCriteria, error) {
+	rfq, err := u.rfqs.Get(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+	if rfq.Status != models.RFQStatusDraft && rfq.Status != models.RFQStatusOpen {
+		return nil, errEvaluationClosed
+	}
+	criteria := &models.EvaluationCriteria{
+		RFQID:           rfqID,
+		PriceWeight:     round2(req.PriceWeight),
+		LeadTimeWeight:  round2(req.LeadTimeWeight),
+		RatingWeight:    round2(req.RatingWeight),
+		TechnicalWeight: round2(req.TechnicalWeight),
+	}
+	total := round2(criteria.PriceWeight + criteria.LeadTimeWeight + criteria.RatingWeight + criteria.TechnicalWeight)
+	if total != 100 {
+		return nil, apperror.Validation("invalid_criteria_weights", fmt.Sprintf("the criteria weights must add up to 100, got %g", total))
+	}
+	return u.bidEvaluationRepository.SaveCriteria(ctx, criteria)
+}
+
+// ScoreQuotation stores the vendor rating and technical score of a quotation. Quotations
+// can be scored once they are unsealed and until the RFQ is awarded.
+func (u *BidEvaluationUseCase) ScoreQuotation(ctx context.Context, rfqID, quotationID string, req *models.QuotationScoreRequest) (*models.QuotationScore, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rfq, err := u.rfqs.Get(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+	if rfq.Status != models.RFQStatusOpen {
+		return nil, errEvaluationClosed
+	}
+	quotations, err := u.rfqs.Quotations(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+	if findQuotation(quotations, quotationID) == nil {
+		return nil, apperror.NotFound("quotation_not_found", fmt.Sprintf("quotation with ID %s not found", quotationID))
+	}
+	return u.bidEvaluationRepository.SaveScore(ctx, &models.QuotationScore{
+		QuotationID:    quotationID,
+		VendorRating:   req.VendorRating,
+		TechnicalScore: req.TechnicalScore,
+		Notes:          req.Notes,
+		ScoredBy:       &userID,
+	})
+}
+
+// Evaluate ranks the quotations of an RFQ once they are unsealed. Mode rfq ranks the vendors
+// that quoted every line on their whole quotation, mode line ranks the offers of each line.
+func (u *BidEvaluationUseCase) Evaluate(ctx context.Context, rfqID, mode string) (*models.BidEvaluation, error) {
+	if mode == "" {
+		mode = models.AwardModeRFQ
+	}
+	if mode != models.AwardModeRFQ && mode != models.AwardModeLine {
+		return nil, apperror.Validation("invalid_evaluation_mode", "mode must be rfq or line")
+	}
+	rfq, err := u.rfqs.Get(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+	quotations, err := u.rfqs.Quotations(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+	return u.evaluate(ctx, rfq, quotations, mode)
+}
+
+// Award records the winners of an open RFQ whose quotations are unsealed and closes it.
+// The whole RFQ goes to one vendor that quoted every line, or each line to a vendor that
+// quoted it; the awarded offers must still be valid. The prices and scores are kept on the
+// award, and with create_purchase_orders a draft order is created per winning vendor.
+func (u *BidEvaluationUseCase) Award(ctx context.Context, rfqID string, req *models.AwardRequest) (*models.RFQAward, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rfq, err := u.rfqs.Get(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case rfq.Status == models.RFQStatusAwarded:
+		return nil, errRFQAwarded
+	case rfq.Status != models.RFQStatusOpen:
+		return nil, apperror.Conflict("rfq_not_open", "only open RFQs can be awarded")
+	case rfq.Sealed:
+		return nil, errRFQSealed
+	}
+	quotations, err := u.rfqs.Quotations(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+	evaluation, err := u.evaluate(ctx, rfq, quotations, req.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	award := &models.RFQAward{
+		RFQID:         rfqID,
+		Mode:          req.Mode,
+		Justification: req.Justification,
+		AwardedBy:     userID,
+	}
+	if req.Mode == models.AwardModeRFQ {
+		award.Lines, err = awardRFQ(rfq, quotations, evaluation, req.VendorID)
+	} else {
+		award.Lines, err = awardLines(rfq, quotations, evaluation, req.Lines)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range award.Lines {
+		award.TotalAmount += line.Amount
+	}
+
+	created, err := u.awardRepository.Create(ctx, award)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, errRFQStatusChange
+	}
+	if req.CreatePurchaseOrders {
+		return u.CreatePurchaseOrders(ctx, rfqID)
+	}
+	return u.GetAward(ctx, rfqID)
+}
+
+// GetAward returns the award of an RFQ with the purchase orders drafted from it
+func (u *BidEvaluationUseCase) GetAward(ctx context.Context, rfqID string) (*models.RFQAward, error) {
+	if _, err := u.rfqs.Get(ctx, rfqID); err != nil {
+		return nil, err
+	}
+	award, err := u.awardRepository.GetByRFQ(ctx, rfqID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rfq award: %w", err)
+	}
+	if award == nil {
+		return nil, apperror.NotFound("rfq_award_not_found", "the RFQ has not been awarded")
+	}
+	if award.PurchaseOrders, err = u.purchaseOrders.ListByAward(ctx, award.ID); err != nil {
+		return nil, err
+	}
+	return award, nil
+}
+
+// CreatePurchaseOrders drafts the purchase orders of an award for the winning vendors
+// that have none yet
+func (u *BidEvaluationUseCase) CreatePurchaseOrders(ctx context.Context, rfqID string) (*models.RFQAward, error) {
+	rfq, err := u.rfqs.Get(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+	award, err := u.GetAward(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+	created, err := u.purchaseOrders.DraftFromAward(ctx, rfq, award)
+	if err != nil {
+		return nil, err
+	}
+	if len(created) == 0 {
+		return nil, apperror.Conflict("purchase_orders_already_created", "every winning vendor already has a purchase order for this award")
+	}
+	return u.GetAward(ctx, rfqID)
+}
+
+// criteria returns the stored criteria of an RFQ, price only when none were set
+func (u *BidEvaluationUseCase) criteria(ctx context.Context, rfqID string) (*models.EvaluationCriteria, error) {
+	criteria, err := u.bidEvaluationRepository.GetCriteria(ctx, rfqID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get evaluation criteria: %w", err)
+	}
+	if criteria == nil {
+		criteria = &models.EvaluationCriteria{RFQID: rfqID, PriceWeight: 100}
+	}
+	return criteria, nil
+}
+
+// evaluate scores the quotations with the criteria and buyer scores of the RFQ
+func (u *BidEvaluationUseCase) evaluate(ctx context.Context, rfq *models.RFQ, quotations []*models.Quotation, mode string) (*models.BidEvaluation, error) {
+	criteria, err := u.criteria(ctx, rfq.ID)
+	if err != nil {
+		return nil, err
+	}
+	scores, err := u.bidEvaluationRepository.ListScores(ctx, rfq.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quotation scores: %w", err)
+	}
+	scoreOf := make(map[string]*models.QuotationScore, len(scores))
+	for _, score := range scores {
+		scoreOf[score.QuotationID] = score
+	}
+	// offer builds the evaluation of a quotation before price and lead time are normalized
+	offer := func(quotation *models.Quotation, amount float64, leadTimeDays int) *models.OfferEvaluation {
+		evaluation := &models.OfferEvaluation{
+			QuotationID:  quotation.ID,
+			VendorID:     quotation.VendorID,
+			VendorName:   quotation.VendorName,
+			Amount:       amount,
+			LeadTimeDays: leadTimeDays,
+		}
+		if score, ok := scoreOf[quotation.ID]; ok {
+			evaluation.RatingScore = round2(score.VendorRating / maxVendorRating * 100)
+			evaluation.TechnicalScore = round2(score.TechnicalScore)
+		}
+		return evaluation
+	}
+
+	evaluation := &models.BidEvaluation{RFQID: rfq.ID, Mode: mode, Criteria: criteria}
+	if mode == models.AwardModeRFQ {
+		evaluation.Vendors = []*models.OfferEvaluation{}
+		for _, quotation := range quotations {
+			if len(quotation.Lines) != len(rfq.Lines) {
+				continue
+			}
+			// the order arrives with the slowest line
+			leadTimeDays := 0
+			for _, line := range quotation.Lines {
+				leadTimeDays = max(leadTimeDays, line.LeadTimeDays)
+			}
+			evaluation.Vendors = append(evaluation.Vendors, offer(quotation, quotation.TotalAmount, leadTimeDays))
+		}
+		rankOffers(evaluation.Vendors, criteria)
+		return evaluation, nil
+	}
+
+	for _, rfqLine := range rfq.Lines {
+		offers := []*models.OfferEvaluation{}
+		for _, quotation := range quotations {
+			if line := quotedLine(quotation, rfqLine.ID); line != nil {
+				offers = append(offers, offer(quotation, line.Amount, line.LeadTimeDays))
+			}
+		}
+		rankOffers(offers, criteria)
+		evaluation.Lines = append(evaluation.Lines, &models.LineEvaluation{Line: rfqLine, Offers: offers})
+	}
+	return evaluation, nil
+}
+
+// rankOffers normalizes price and lead time against the best offer, computes the weighted
+// score and sorts the offers best first, the cheaper offer wins a tie
+func rankOffers(offers []*models.OfferEvaluation, criteria *models.EvaluationCriteria) {
+	if len(offers) == 0 {
+		return
+	}
+	lowest, fastest := offers[0].Amount, offers[0].LeadTimeDays
+	for _, offer := range offers {
+		lowest = math.Min(lowest, offer.Amount)
+		fastest = min(fastest, offer.LeadTimeDays)
+	}
+	for _, offer := range offers {
+		offer.PriceScore = 100
+		if offer.Amount > 0 {
+			offer.PriceScore = round2(lowest / offer.Amount * 100)
+		}
+		// shifted by a day so a same-day offer does not divide by zero
+		offer.LeadTimeScore = round2(float64(fastest+1) / float64(offer.LeadTimeDays+1) * 100)
+		offer.Score = round2((offer.PriceScore*criteria.PriceWeight +
+			offer.LeadTimeScore*criteria.LeadTimeWeight +
+			offer.RatingScore*criteria.RatingWeight +
+			offer.TechnicalScore*criteria.TechnicalWeight) / 100)
+	}
+	sort.SliceStable(offers, func(i, j int) bool {
+		if offers[i].Score != offers[j].Score {
+			return offers[i].Score > offers[j].Score
+		}
+		return offers[i].Amount < offers[j].Amount
+	})
+	for i, offer := range offers {
+		offer.Rank = i + 1
+	}
+}
+
+// awardRFQ awards every line to the vendor, which must have quoted every line
+func awardRFQ(rfq *models.RFQ, quotations []*models.Quotation, evaluation *models.BidEvaluation, vendorID string) ([]*models.RFQAwardLine, error) {
+	var winner *models.OfferEvaluation
+	for _, offer := range evaluation.Vendors {
+		if offer.VendorID == vendorID {
+			winner = offer
+		}
+	}
+	if winner == nil {
+		for _, quotation := range quotations {
+			if quotation.VendorID == vendorID {
+				return nil, apperror.Validation("quotation_incomplete", "the vendor did not quote every line of the RFQ, award it per line instead")
+			}
+		}
+		return nil, apperror.Validation("vendor_not_quoted", fmt.Sprintf("vendor with ID %s has no quotation for this RFQ", vendorID))
+	}
+
+	quotation := findQuotation(quotations, winner.QuotationID)
+	var lines []*models.RFQAwardLine
+	for _, rfqLine := range rfq.Lines {
+		line, err := awardLine(rfqLine, quotation, winner.Score)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// awardLines awards each requested line to a vendor that quoted it
+func awardLines(rfq *models.RFQ, quotations []*models.Quotation, evaluation *models.BidEvaluation, reqs []*models.AwardLineRequest) ([]*models.RFQAwardLine, error) {
+	lineEvaluations := make(map[string]*models.LineEvaluation, len(evaluation.Lines))
+	for _, lineEvaluation := range evaluation.Lines {
+		lineEvaluations[lineEvaluation.Line.ID] = lineEvaluation
+	}
+	awarded := map[string]*models.RFQAwardLine{}
+	for i, req := range reqs {
+		lineEvaluation, ok := lineEvaluations[req.RFQLineID]
+		if !ok {
+			return nil, apperror.Validation("unknown_rfq_line", fmt.Sprintf("line %d: RFQ line %s is not part of the RFQ", i+1, req.RFQLineID))
+		}
+		if awarded[req.RFQLineID] != nil {
+			return nil, apperror.Validation("duplicate_rfq_line", fmt.Sprintf("line %d: RFQ line %d is awarded twice", i+1, lineEvaluation.Line.LineNo))
+		}
+		var winner *models.OfferEvaluation
+		for _, offer := range lineEvaluation.Offers {
+			if offer.VendorID == req.VendorID {
+				winner = offer
+			}
+		}
+		if winner == nil {
+			return nil, apperror.Validation("vendor_not_quoted", fmt.Sprintf("line %d: the vendor did not quote RFQ line %d", i+1, lineEvaluation.Line.LineNo))
+		}
+		line, err := awardLine(lineEvaluation.Line, findQuotation(quotations, winner.QuotationID), winner.Score)
+		if err != nil {
+			return nil, err
+		}
+		awarded[req.RFQLineID] = line
+	}
+
+	// keep the award in RFQ line order
+	var lines []*models.RFQAwardLine
+	for _, rfqLine := range rfq.Lines {
+		if line, ok := awarded[rfqLine.ID]; ok {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// awardLine builds the award of an RFQ line from the quotation, an expired offer cannot be awarded
+func awardLine(rfqLine *models.RFQLine, quotation *models.Quotation, score float64) (*models.RFQAwardLine, error) {
+	line := quotedLine(quotation, rfqLine.ID)
+	today := time.Now().Truncate(24 * time.Hour)
+	if line.ValidUntil.Before(today) {
+		return nil, apperror.Validation("quotation_expired",
+			fmt.Sprintf("the offer of %s for RFQ line %d expired on %s", quotation.VendorName, rfqLine.LineNo, line.ValidUntil.Format(time.DateOnly)))
+	}
+	return &models.RFQAwardLine{
+		RFQLineID:   rfqLine.ID,
+		QuotationID: quotation.ID,
+		VendorID:    quotation.VendorID,
+		VendorName:  quotation.VendorName,
+		Quantity:    rfqLine.Quantity,
+		UnitPrice:   line.UnitPrice,
+		Amount:      line.Amount,
+		Score:       score,
+	}, nil
+}
+
+// findQuotation returns the quotation with the ID, nil when it is not in the list
+func findQuotation(quotations []*models.Quotation, id string) *models.Quotation {
+	for _, quotation := range quotations {
+		if quotation.ID == id {
+			return quotation
+		}
+	}
+	return nil
+}
+
+// quotedLine returns the offer of the quotation for an RFQ line, nil when the line was not quoted
+func quotedLine(quotation *models.Quotation, rfqLineID string) *models.QuotationLine {
+	for _, line := range quotation.Lines {
+		if line.RFQLineID == rfqLineID {
+			return line
+		}
+	}
+	return nil
+}
+
+// round2 rounds to two decimals, the precision scores and weights are stored with
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -3,15 +3,16 @@ package usecases
 import (
 	"context"
 	"e-procurement/internals/domain/models"
-	"e-procurement/internals/repositories"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
 	"fmt"
 )
 
 type CategoryUsecase struct {
-	CategoryRepository *repositories.CategoryRepository
+	CategoryRepository repository.CategoryRepository
 }
 
-func NewCategoryUsecase(categoryRepository *repositories.CategoryRepository,) *CategoryUsecase{
+func NewCategoryUsecase(categoryRepository repository.CategoryRepository,) *CategoryUsecase{
 	return&CategoryUsecase{
 		CategoryRepository: categoryRepository,
 	}
@@ -69,6 +70,9 @@ func(u *CategoryUsecase) GetCategoryByIDUsecase(ctx context.Context, id string)
 	if err != nil {
 		return nil, err
 	}
+	if category == nil {
+		return nil, apperror.NotFound("category_not_found", fmt.Sprintf("category with id %s not found", id))
+	}
 	categoryResponse := &models.CategoryResponse{
 		ID:          category.ID,
 		Name:        category.Name,
@@ -86,6 +90,10 @@ func(u *CategoryUsecase) UpdateCategoryUsecase(ctx context.Context, id string, c
 	if err != nil {
 		return nil, err
 	}
+	// if the category does not exist, return not found
+	if exists == nil {
+		return nil,apperror.NotFound("category_not_found", fmt.Sprintf("category with id %s not found", id))
+	}
 	// check if the category exists
 	if category.Name == "" {
 		category.Name = exists.Name
@@ -93,14 +101,13 @@ func(u *CategoryUsecase) UpdateCategoryUsecase(ctx context.Context, id string, c
 	if category.Description == "" {
 		category.Description = exists.Description
 	}
-	// if the category does not exist, return nil
-	if exists == nil {
-		return nil,fmt.Errorf("category with id %s not found", id)
-	}
 	updatedCategory, err := u.CategoryRepository.UpdateCategory(ctx,id, category)
 	if err != nil {
 		return nil, err
 	}
+	if updatedCategory == nil {
+		return nil,apperror.NotFound("category_not_found", fmt.Sprintf("category with id %s not found", id))
+	}
 	categoryResponse := &models.CategoryResponse{
 		ID:          updatedCategory.ID,
 		Name:        updatedCategory.Name,
@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	"fmt"
+)
+
+// CostCenterUseCase manages the cost centers purchases of the organization are charged to
+type CostCenterUseCase struct {
+	costCenterRepository repository.CostCenterRepository
+}
+
+func NewCostCenterUseCase(costCenterRepo repository.CostCenterRepository) *CostCenterUseCase {
+	return &CostCenterUseCase{costCenterRepository: costCenterRepo}
+}
+
+// Create stores a new, active cost center
+func (u *CostCenterUseCase) Create(ctx context.Context, req *models.CreateCostCenterRequest) (*models.CostCenter, error) {
+	return u.costCenterRepository.Create(ctx, req)
+}
+
+// List returns a page of cost centers ordered by code with the total count
+func (u *CostCenterUseCase) List(ctx context.Context, limit, page int) ([]*models.CostCenter, int, error) {
+	limit, offset, err := pageOffset(limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	count, err := u.costCenterRepository.Count(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count cost centers: %w", err)
+	}
+	costCenters, err := u.costCenterRepository.List(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list cost centers: %w", err)
+	}
+	return costCenters, count, nil
+}
+
+// Get returns a cost center of the organization
+func (u *CostCenterUseCase) Get(ctx context.Context, id string) (*models.CostCenter, error) {
+	costCenter, err := u.costCenterRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cost center: %w", err)
+	}
+	if costCenter == nil {
+		return nil, apperror.NotFound("cost_center_not_found", fmt.Sprintf("cost center with ID %s not found", id))
+	}
+	return costCenter, nil
+}
+
+// Update changes a cost center, deactivated cost centers keep their history but
+// can no longer be assigned or charged
+func (u *CostCenterUseCase) Update(ctx context.Context, id string, req *models.UpdateCostCenterRequest) (*models.CostCenter, error) {
+	return u.costCenterRepository.Update(ctx, id, req)
+}
+
+// Delete removes a cost center that is not referenced by any department or member
+func (u *CostCenterUseCase) Delete(ctx context.Context, id string) error {
+	return u.costCenterRepository.Delete(ctx, id)
+}
@@ -0,0 +1,246 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	customContext "e-procurement/pkg/context"
+	"fmt"
+)
+
+var errCostCenterInactive = apperror.Validation("cost_center_inactive", "cost center is inactive")
+
+// DepartmentUseCase manages the department tree of the organization, the department of
+// each member and the department and cost center a member's purchases default to
+type DepartmentUseCase struct {
+	departmentRepository repository.DepartmentRepository
+	costCenterRepository repository.CostCenterRepository
+}
+
+func NewDepartmentUseCase(departmentRepo repository.DepartmentRepository, costCenterRepo repository.CostCenterRepository) *DepartmentUseCase {
+	return &DepartmentUseCase{
+		departmentRepository: departmentRepo,
+		costCenterRepository: costCenterRepo,
+	}
+}
+
+// Create stores a new department, optionally below a parent department
+func (u *DepartmentUseCase) Create(ctx context.Context, req *models.DepartmentRequest) (*models.Department, error) {
+	if err := u.activeCostCenter(ctx, req.DefaultCostCenterID); err != nil {
+		return nil, err
+	}
+	return u.departmentRepository.Create(ctx, req)
+}
+
+// List returns every department of the organization ordered by code
+func (u *DepartmentUseCase) List(ctx context.Context) ([]*models.Department, error) {
+	return u.departmentRepository.List(ctx)
+}
+
+// Tree returns the departments of the organization nested below their parents
+func (u *DepartmentUseCase) Tree(ctx context.Context) ([]*models.DepartmentNode, error) {
+	departments, err := u.departmentRepository.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list departments: %w", err)
+	}
+
+	nodes := make(map[string]*models.DepartmentNode, len(departments))
+	for _, department := range departments {
+		nodes[department.ID] = &models.DepartmentNode{Department: department, Children: []*models.DepartmentNode{}}
+	}
+	roots := []*models.DepartmentNode{}
+	// departments are ordered by code, so children keep that order inside each parent
+	for _, department := range departments {
+		node := nodes[department.ID]
+		if department.ParentID == nil {
+			roots = append(roots, node)
+			continue
+		}
+		nodes[*department.ParentID].Children = append(nodes[*department.ParentID].Children, node)
+	}
+	return roots, nil
+}
+
+// Get returns a department of the organization
+func (u *DepartmentUseCase) Get(ctx context.Context, id string) (*models.Department, error) {
+	department, err := u.departmentRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get department: %w", err)
+	}
+	if department == nil {
+		return nil, apperror.NotFound("department_not_found", fmt.Sprintf("department with ID %s not found", id))
+	}
+	return department, nil
+}
+
+// Update replaces a department, moving it below one of its own descendants is refused
+func (u *DepartmentUseCase) Update(ctx context.Context, id string, req *models.DepartmentRequest) (*models.Department, error) {
+	if req.ParentID != nil {
+		departments, err := u.byID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for parentID := req.ParentID; parentID != nil; {
+			if *parentID == id {
+				return nil, apperror.Validation("department_cycle", "a department cannot be placed below itself or one of its sub departments")
+			}
+			parent, ok := departments[*parentID]
+			if !ok {
+				break
+			}
+			parentID = parent.ParentID
+		}
+	}
+	if err := u.activeCostCenter(ctx, req.DefaultCostCenterID); err != nil {
+		return nil, err
+	}
+	return u.departmentRepository.Update(ctx, id, req)
+}
+
+// Delete removes a department without sub departments or members
+func (u *DepartmentUseCase) Delete(ctx context.Context, id string) error {
+	return u.departmentRepository.Delete(ctx, id)
+}
+
+// GetAssignment returns the department and cost center override of a member
+func (u *DepartmentUseCase) GetAssignment(ctx context.Context, userID string) (*models.DepartmentAssignment, error) {
+	assignment, err := u.departmentRepository.GetAssignment(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get department assignment: %w", err)
+	}
+	if assignment == nil {
+		return nil, apperror.NotFound("user_not_found", fmt.Sprintf("user with ID %s not found", userID))
+	}
+	return assignment, nil
+}
+
+// Assign places a member in a department, the cost center overrides the department default
+func (u *DepartmentUseCase) Assign(ctx context.Context, userID string, req *models.AssignDepartmentRequest) (*models.DepartmentAssignment, error) {
+	if err := u.activeCostCenter(ctx, req.CostCenterID); err != nil {
+		return nil, err
+	}
+	assignment := &models.DepartmentAssignment{
+		UserID:       userID,
+		DepartmentID: req.DepartmentID,
+		CostCenterID: req.CostCenterID,
+	}
+	if err := u.departmentRepository.Assign(ctx, assignment); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// PurchaseDefaults returns the department and cost center a purchase raised by the user
+// is charged to when the request does not name them. The cost center is the member's own
+// override, otherwise the default cost center of the department or its nearest ancestor
+// with one; inactive cost centers are skipped.
+func (u *DepartmentUseCase) PurchaseDefaults(ctx context.Context, userID string) (*models.PurchaseDefaults, error) {
+	assignment, err := u.GetAssignment(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	defaults := &models.PurchaseDefaults{DepartmentID: assignment.DepartmentID}
+
+	candidates := []*string{assignment.CostCenterID}
+	if assignment.DepartmentID != nil {
+		departments, err := u.byID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if department, ok := departments[*assignment.DepartmentID]; ok {
+			defaults.DepartmentName = department.Name
+		}
+		for id := assignment.DepartmentID; id != nil; {
+			department, ok := departments[*id]
+			if !ok {
+				break
+			}
+			candidates = append(candidates, department.DefaultCostCenterID)
+			id = department.ParentID
+		}
+	}
+
+	for _, costCenterID := range candidates {
+		if costCenterID == nil {
+			continue
+		}
+		costCenter, err := u.costCenterRepository.GetByID(ctx, *costCenterID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cost center: %w", err)
+		}
+		if costCenter != nil && costCenter.Active {
+			defaults.CostCenterID = &costCenter.ID
+			defaults.CostCenterCode = costCenter.Code
+			break
+		}
+	}
+	return defaults, nil
+}
+
+// MyPurchaseDefaults returns the purchase defaults of the caller
+func (u *DepartmentUseCase) MyPurchaseDefaults(ctx context.Context) (*models.PurchaseDefaults, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return u.PurchaseDefaults(ctx, userID)
+}
+
+// byID returns the departments of the organization keyed by ID
+func (u *DepartmentUseCase) byID(ctx context.Context) (map[string]*models.Department, error) {
+	departments, err := u.departmentRepository.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list departments: %w", err)
+	}
+	byID := make(map[string]*models.Department, len(departments))
+	for _, department := range departments {
+		byID[department.ID] = department
+	}
+	return byID, nil
+}
+
+// requesterDepartment refuses a department the requester does not belong to. The department of a purchase
+// picks its approval rule, so requesters may only charge their own department or one below it
+func (u *DepartmentUseCase) requesterDepartment(ctx context.Context, userID, departmentID string) error {
+	departments, err := u.byID(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := departments[departmentID]; !ok {
+		return apperror.Validation("unknown_department", fmt.Sprintf("department with ID %s not found", departmentID))
+	}
+	assignment, err := u.GetAssignment(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if assignment.DepartmentID != nil {
+		for id := &departmentID; id != nil; {
+			if *id == *assignment.DepartmentID {
+				return nil
+			}
+			department, ok := departments[*id]
+			if !ok {
+				break
+			}
+			id = department.ParentID
+		}
+	}
+	return apperror.Validation("department_not_assigned", "you can only charge your own department or one below it")
+}
+
+// activeCostCenter refuses new references to a deactivated cost center, unknown
+// cost centers are left to the repository which reports an invalid reference
+func (u *DepartmentUseCase) activeCostCenter(ctx context.Context, id *string) error {
+	if id == nil {
+		return nil
+	}
+	costCenter, err := u.costCenterRepository.GetByID(ctx, *id)
+	if err != nil {
+		return fmt.Errorf("failed to get cost center: %w", err)
+	}
+	if costCenter != nil && !costCenter.Active {
+		return errCostCenterInactive
+	}
+	return nil
+}
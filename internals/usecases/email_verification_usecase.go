@@ -0,0 +1,132 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	"e-procurement/pkg/auth"
+	"e-procurement/pkg/mailer"
+	"fmt"
+	"time"
+)
+
+// RateLimitScopeEmailVerification limits verification email resends per email
+const RateLimitScopeEmailVerification = "email_verification"
+
+var (
+	errInvalidVerificationToken = apperror.Validation("invalid_verification_token", "verification token is invalid or expired")
+	errTooManyVerificationRequests = apperror.TooManyRequests("too_many_requests", "too many verification requests, please try again later")
+	errEmailNotVerified = apperror.Forbidden("email_not_verified", "verify your email address first")
+)
+
+type EmailVerificationUseCase struct {
+	userRepository repository.UserRepository
+	tokenRepository repository.UserTokenRepository
+	limiter *RateLimiter
+	mailer mailer.Mailer
+	verifyURL string
+	tokenTTL time.Duration
+}
+
+func NewEmailVerificationUseCase(
+	userRepo repository.UserRepository,
+	tokenRepo repository.UserTokenRepository,
+	limiter *RateLimiter,
+	mail mailer.Mailer,
+	verifyURL string,
+	tokenTTL time.Duration,
+	) *EmailVerificationUseCase {
+	return &EmailVerificationUseCase{
+		userRepository: userRepo,
+		tokenRepository: tokenRepo,
+		limiter: limiter,
+		mailer: mail,
+		verifyURL: verifyURL,
+		tokenTTL: tokenTTL,
+	}
+}
+
+// SendVerification mails a new verification link to the user, older links stop working
+func (u *EmailVerificationUseCase) SendVerification(ctx context.Context, userID, userName, email string) error {
+	if err := u.tokenRepository.InvalidateForUser(ctx, userID, models.UserTokenPurposeEmailVerification); err != nil {
+		return fmt.Errorf("failed to invalidate verification tokens: %w", err)
+	}
+	token, tokenHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("error generating verification token: %w", err)
+	}
+	_, err = u.tokenRepository.Create(ctx, &models.UserToken{
+		UserID:    userID,
+		Purpose:   models.UserTokenPurposeEmailVerification,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(u.tokenTTL),
+	})
+	if err != nil {
+		return fmt.Errorf("error storing verification token: %w", err)
+	}
+
+	err = u.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Verify your e-procurement email address",
+		Body: fmt.Sprintf("Hello %s,\n\nOpen the link below to verify your email address. It expires in %s.\n\n%s",
+			userName, u.tokenTTL, tokenLink(u.verifyURL, token)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+	return nil
+}
+
+// method to verify an email address with the token from the verification email
+func (u *EmailVerificationUseCase) Verify(ctx context.Context, token string) error {
+	stored, err := u.tokenRepository.GetByHash(ctx, models.UserTokenPurposeEmailVerification, auth.HashOpaqueToken(token))
+	if err != nil {
+		return fmt.Errorf("failed to get verification token: %w", err)
+	}
+	if stored == nil || stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return errInvalidVerificationToken
+	}
+	used, err := u.tokenRepository.MarkUsed(ctx, stored.ID)
+	if err != nil {
+		return fmt.Errorf("failed to consume verification token: %w", err)
+	}
+	if !used {
+		return errInvalidVerificationToken
+	}
+
+	if err := u.userRepository.MarkEmailVerified(ctx, stored.UserID); err != nil {
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+	return nil
+}
+
+// method to send the verification email again. like forgot-password it answers the same
+// for unknown and already verified emails.
+func (u *EmailVerificationUseCase) Resend(ctx context.Context, req *models.ResendVerificationRequest) error {
+	allowed, err := u.limiter.Allow(ctx, normalizeEmail(req.Email))
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errTooManyVerificationRequests
+	}
+
+	// Authenticate looks the user up by email
+	user, err := u.userRepository.Authenticate(ctx, req.Email)
+	if err != nil {
+		return fmt.Errorf("failed to get user by email: %w", err)
+	}
+	if user == nil || user.EmailVerifiedAt != nil {
+		return nil
+	}
+	return u.SendVerification(ctx, user.ID, user.UserName, user.Email)
+}
+
+// RequireVerified returns a forbidden error for users who did not verify their email yet
+func RequireVerified(user *models.User) error {
+	if user.EmailVerifiedAt == nil {
+		return errEmailNotVerified
+	}
+	return nil
+}
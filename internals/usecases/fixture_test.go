@@ -0,0 +1,118 @@
+package usecases_test
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/repositories/memory"
+	"e-procurement/internals/usecases"
+	"e-procurement/pkg/apperror"
+	"e-procurement/pkg/constans"
+	"testing"
+)
+
+// testOrg is an organization in a fresh in-memory store, usecases under test get their
+// repositories from store and act as one of the users added with addUser
+type testOrg struct {
+	t     *testing.T
+	store *memory.Store
+	id    string
+	roles map[string]string
+}
+
+func newTestOrg(t *testing.T) *testOrg {
+	t.Helper()
+	store := memory.NewStore()
+	org, err := memory.NewOrganizationRepository(store).Create(context.Background(), &models.CreateOrganizationRequest{
+		Code: "test",
+		Name: "Test Organization",
+	}, "")
+	if err != nil {
+		t.Fatalf("create organization: %v", err)
+	}
+	return &testOrg{t: t, store: store, id: org.ID, roles: map[string]string{}}
+}
+
+// addUser creates a verified user holding role in the organization and returns its ID
+func (o *testOrg) addUser(name, role string) string {
+	o.t.Helper()
+	users := memory.NewUserRepository(o.store)
+	user, err := users.Create(context.Background(), &models.CreateUserRequest{
+		UserName: name,
+		Email:    name + "@example.com",
+		Password: "hashed",
+	})
+	if err != nil {
+		o.t.Fatalf("create user %s: %v", name, err)
+	}
+	if err := users.MarkEmailVerified(context.Background(), user.ID); err != nil {
+		o.t.Fatalf("verify user %s: %v", name, err)
+	}
+	if err := memory.NewOrganizationRepository(o.store).AddMember(context.Background(), o.id, user.ID, role); err != nil {
+		o.t.Fatalf("add member %s: %v", name, err)
+	}
+	o.roles[user.ID] = role
+	return user.ID
+}
+
+// as returns the context of a request made by the user with their organization role,
+// the way the auth middleware builds it
+func (o *testOrg) as(userID string) context.Context {
+	ctx := context.WithValue(context.Background(), constans.ContextUserIDKey, userID)
+	ctx = context.WithValue(ctx, constans.ContextPositionKey, o.roles[userID])
+	return context.WithValue(ctx, constans.ContextTenantKey, o.id)
+}
+
+// addVendor creates the vendor profile owned by userID
+func (o *testOrg) addVendor(userID, name string) *models.Vendor {
+	o.t.Helper()
+	vendor, err := memory.NewVendorRepository(o.store).CreateVendor(o.as(userID), userID, &models.CreateVendorRequest{
+		VendorName:  name,
+		Description: name + " description",
+	})
+	if err != nil {
+		o.t.Fatalf("create vendor %s: %v", name, err)
+	}
+	return vendor
+}
+
+// addCategory creates a product category
+func (o *testOrg) addCategory(asUserID, name string) *models.Category {
+	o.t.Helper()
+	category, err := memory.NewCategoryRepository(o.store).CreateCategory(o.as(asUserID), &models.CreateCategoryRequest{
+		Name:        name,
+		Description: name + " description",
+	})
+	if err != nil {
+		o.t.Fatalf("create category %s: %v", name, err)
+	}
+	return category
+}
+
+// approvals returns an approval engine on the organization's store
+func (o *testOrg) approvals() *usecases.ApprovalUseCase {
+	return usecases.NewApprovalUseCase(
+		memory.NewApprovalRuleRepository(o.store),
+		memory.NewApprovalRepository(o.store),
+		memory.NewApprovalDelegationRepository(o.store),
+		memory.NewOrganizationRepository(o.store),
+		usecases.NewDepartmentUseCase(memory.NewDepartmentRepository(o.store), memory.NewCostCenterRepository(o.store)),
+	)
+}
+
+// assertAppError fails unless err is an apperror of the given kind and code, an empty wantCode means no error
+func assertAppError(t *testing.T, err error, wantKind apperror.Kind, wantCode string) {
+	t.Helper()
+	if wantCode == "" {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	appErr, ok := apperror.As(err)
+	if !ok {
+		t.Fatalf("expected %s error %q, got %v", wantKind, wantCode, err)
+	}
+	if appErr.Kind != wantKind || appErr.Code != wantCode {
+		t.Fatalf("expected %s error %q, got %s error %q (%s)", wantKind, wantCode, appErr.Kind, appErr.Code, appErr.Message)
+	}
+}
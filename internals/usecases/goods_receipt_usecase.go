@@ -0,0 +1,240 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	customContext "e-procurement/pkg/context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// quantityEpsilon absorbs float rounding when comparing quantities, the database keeps
+// three decimals so anything smaller than half of the last digit is noise
+const quantityEpsilon = 0.0005
+
+// GoodsReceiptUseCase records what arrived against purchase orders that were sent to the
+// vendor. Every receipt is inspected line by line, only the accepted quantity counts towards
+// the order and the order is marked partially received or closed as the receipts come in.
+type GoodsReceiptUseCase struct {
+	goodsReceiptRepository repository.GoodsReceiptRepository
+	purchaseOrders         *PurchaseOrderUseCase
+	// overReceiptTolerance is the percentage of the ordered quantity that may be accepted on top of it
+	overReceiptTolerance float64
+}
+
+func NewGoodsReceiptUseCase(goodsReceiptRepo repository.GoodsReceiptRepository, purchaseOrders *PurchaseOrderUseCase, overReceiptTolerance float64) *GoodsReceiptUseCase {
+	return &GoodsReceiptUseCase{
+		goodsReceiptRepository: goodsReceiptRepo,
+		purchaseOrders:         purchaseOrders,
+		overReceiptTolerance:   overReceiptTolerance,
+	}
+}
+
+// Record stores a receipt against a sent, acknowledged or partially received order. The order is
+// closed once every line is fully accepted, otherwise it stays partially received.
+func (u *GoodsReceiptUseCase) Record(ctx context.Context, purchaseOrderID string, req *models.GoodsReceiptRequest) (*models.GoodsReceipt, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	order, err := u.purchaseOrders.Get(ctx, purchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	switch order.Status {
+	case models.PurchaseOrderStatusSent, models.PurchaseOrderStatusAcknowledged, models.PurchaseOrderStatusPartiallyReceived:
+	default:
+		return nil, apperror.Conflict("purchase_order_not_receivable",
+			"goods can only be received on sent, acknowledged or partially received purchase orders")
+	}
+
+	receipt := &models.GoodsReceipt{
+		DeliveryNote: req.DeliveryNote,
+		Notes:        req.Notes,
+		ReceivedBy:   userID,
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	receipt.ReceivedAt = today
+	if req.ReceivedAt != "" {
+		receivedAt, err := time.Parse(time.DateOnly, req.ReceivedAt)
+		if err != nil {
+			return nil, apperror.Validation("invalid_received_at", "received_at must be formatted as YYYY-MM-DD")
+		}
+		if receivedAt.After(today) {
+			return nil, apperror.Validation("invalid_received_at", "received_at cannot be in the future")
+		}
+		receipt.ReceivedAt = receivedAt
+	}
+
+	summary, err := u.summary(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	lines := make(map[string]*models.ReceiptSummaryLine, len(summary))
+	for _, line := range summary {
+		lines[line.PurchaseOrderLineID] = line
+	}
+	seen := make(map[string]bool, len(req.Lines))
+	for _, lineReq := range req.Lines {
+		line, ok := lines[lineReq.PurchaseOrderLineID]
+		if !ok {
+			return nil, apperror.Validation("unknown_purchase_order_line",
+				fmt.Sprintf("line %s is not a line of purchase order %s", lineReq.PurchaseOrderLineID, order.Number))
+		}
+		if seen[lineReq.PurchaseOrderLineID] {
+			return nil, apperror.Validation("duplicate_purchase_order_line",
+				fmt.Sprintf("line %d is received more than once", line.LineNo))
+		}
+		seen[lineReq.PurchaseOrderLineID] = true
+		if lineReq.RejectedQuantity > 0 && lineReq.InspectionNotes == "" {
+			return nil, apperror.Validation("inspection_notes_required",
+				fmt.Sprintf("line %d: inspection_notes are required when goods are rejected", line.LineNo))
+		}
+
+		accepted := roundQuantity(lineReq.ReceivedQuantity - lineReq.RejectedQuantity)
+		allowed := line.OrderedQuantity * (1 + u.overReceiptTolerance/100)
+		if line.AcceptedQuantity+accepted > allowed+quantityEpsilon {
+			return nil, apperror.Validation("over_receipt",
+				fmt.Sprintf("line %d: accepting %.3f %s brings the line to %.3f of %.3f ordered, more than the %.2f%% tolerance",
+					line.LineNo, accepted, line.Unit, line.AcceptedQuantity+accepted, line.OrderedQuantity, u.overReceiptTolerance))
+		}
+		line.ReceivedQuantity += lineReq.ReceivedQuantity
+		line.AcceptedQuantity += accepted
+		line.RejectedQuantity += lineReq.RejectedQuantity
+
+		receipt.Lines = append(receipt.Lines, &models.GoodsReceiptLine{
+			PurchaseOrderLineID: lineReq.PurchaseOrderLineID,
+			ReceivedQuantity:    lineReq.ReceivedQuantity,
+			AcceptedQuantity:    accepted,
+			RejectedQuantity:    lineReq.RejectedQuantity,
+			InspectionNotes:     lineReq.InspectionNotes,
+		})
+	}
+
+	// an order is complete once every line is fully accepted, a receipt that was rejected
+	// as a whole leaves the status as it was
+	complete, anyAccepted := true, false
+	for _, line := range summary {
+		if line.AcceptedQuantity < line.OrderedQuantity-quantityEpsilon {
+			complete = false
+		}
+		if line.AcceptedQuantity > quantityEpsilon {
+			anyAccepted = true
+		}
+	}
+	transition := &models.PurchaseOrderTransition{Status: order.Status}
+	switch {
+	case complete:
+		now := time.Now()
+		transition.Status = models.PurchaseOrderStatusClosed
+		transition.ClosedAt = &now
+	case anyAccepted:
+		transition.Status = models.PurchaseOrderStatusPartiallyReceived
+	}
+
+	created, err := u.goodsReceiptRepository.Create(ctx, receipt, order, transition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create goods receipt: %w", err)
+	}
+	if !created {
+		return nil, errPurchaseOrderStatusChange
+	}
+	return u.Get(ctx, receipt.ID)
+}
+
+// Get returns a goods receipt of the organization with its lines
+func (u *GoodsReceiptUseCase) Get(ctx context.Context, id string) (*models.GoodsReceipt, error) {
+	receipt, err := u.goodsReceiptRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goods receipt: %w", err)
+	}
+	if receipt == nil {
+		return nil, apperror.NotFound("goods_receipt_not_found", fmt.Sprintf("goods receipt with ID %s not found", id))
+	}
+	return receipt, nil
+}
+
+// List returns a page of goods receipts, newest first, with the total count
+func (u *GoodsReceiptUseCase) List(ctx context.Context, filter models.GoodsReceiptFilter, limit, page int) ([]*models.GoodsReceipt, int, error) {
+	limit, offset, err := pageOffset(limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	receipts, count, err := u.goodsReceiptRepository.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list goods receipts: %w", err)
+	}
+	return receipts, count, nil
+}
+
+// ListByPurchaseOrder returns every receipt of an order with its lines, oldest first
+func (u *GoodsReceiptUseCase) ListByPurchaseOrder(ctx context.Context, purchaseOrderID string) ([]*models.GoodsReceipt, error) {
+	if _, err := u.purchaseOrders.Get(ctx, purchaseOrderID); err != nil {
+		return nil, err
+	}
+	receipts, err := u.goodsReceiptRepository.ListByPurchaseOrder(ctx, purchaseOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list goods receipts: %w", err)
+	}
+	if receipts == nil {
+		receipts = []*models.GoodsReceipt{}
+	}
+	return receipts, nil
+}
+
+// ReceiptSummary returns the ordered, received, accepted, rejected and outstanding quantity
+// of every line of an order over all its receipts
+func (u *GoodsReceiptUseCase) ReceiptSummary(ctx context.Context, purchaseOrderID string) ([]*models.ReceiptSummaryLine, error) {
+	order, err := u.purchaseOrders.Get(ctx, purchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	return u.summary(ctx, order)
+}
+
+// summary adds up the receipts of an order per line, in line order
+func (u *GoodsReceiptUseCase) summary(ctx context.Context, order *models.PurchaseOrder) ([]*models.ReceiptSummaryLine, error) {
+	receipts, err := u.goodsReceiptRepository.ListByPurchaseOrder(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list goods receipts: %w", err)
+	}
+	summary := make([]*models.ReceiptSummaryLine, 0, len(order.Lines))
+	lines := make(map[string]*models.ReceiptSummaryLine, len(order.Lines))
+	for _, line := range order.Lines {
+		summaryLine := &models.ReceiptSummaryLine{
+			PurchaseOrderLineID: line.ID,
+			LineNo:              line.LineNo,
+			Description:         line.Description,
+			Unit:                line.Unit,
+			OrderedQuantity:     line.Quantity,
+		}
+		summary = append(summary, summaryLine)
+		lines[line.ID] = summaryLine
+	}
+	for _, receipt := range receipts {
+		for _, receiptLine := range receipt.Lines {
+			line, ok := lines[receiptLine.PurchaseOrderLineID]
+			if !ok {
+				continue
+			}
+			line.ReceivedQuantity += receiptLine.ReceivedQuantity
+			line.AcceptedQuantity += receiptLine.AcceptedQuantity
+			line.RejectedQuantity += receiptLine.RejectedQuantity
+		}
+	}
+	for _, line := range summary {
+		line.ReceivedQuantity = roundQuantity(line.ReceivedQuantity)
+		line.AcceptedQuantity = roundQuantity(line.AcceptedQuantity)
+		line.RejectedQuantity = roundQuantity(line.RejectedQuantity)
+		line.OutstandingQuantity = math.Max(roundQuantity(line.OrderedQuantity-line.AcceptedQuantity), 0)
+	}
+	return summary, nil
+}
+
+// roundQuantity rounds a quantity to the three decimals the database keeps
+func roundQuantity(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
@@ -0,0 +1,417 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	customContext "e-procurement/pkg/context"
+	"fmt"
+	"math"
+	"time"
+)
+
+var (
+	errInvoiceClosed        = apperror.Conflict("invoice_closed", "approved, disputed or paid invoices cannot be changed")
+	errInvoiceStatusChanged = apperror.Conflict("invoice_status_changed", "the invoice status has changed, reload it and try again")
+	// another invoice of the order was stored between the match and the insert
+	errInvoicedQuantitiesChanged = apperror.Conflict("purchase_order_invoices_changed",
+		"another invoice of the purchase order was submitted at the same time, submit the invoice again")
+)
+
+// InvoiceUseCase takes the invoices vendors submit against their purchase orders and matches
+// every line against the PO price and the quantity accepted on goods receipts (three-way match).
+// Invoices that do not match wait in the exceptions queue, finance approves, holds or disputes them.
+// Approved invoices are scheduled for payment under the terms of their order or vendor.
+type InvoiceUseCase struct {
+	invoiceRepository repository.InvoiceRepository
+	vendorRepository  repository.VendorRepository
+	purchaseOrders    *PurchaseOrderUseCase
+	goodsReceipts     *GoodsReceiptUseCase
+	// percentages the invoiced unit price and quantity may exceed the PO price and accepted quantity
+	priceTolerance    float64
+	quantityTolerance float64
+	// terms of invoices whose order and vendor have none
+	defaultTerms *models.PaymentTerms
+}
+
+func NewInvoiceUseCase(invoiceRepo repository.InvoiceRepository, vendorRepo repository.VendorRepository, purchaseOrders *PurchaseOrderUseCase, goodsReceipts *GoodsReceiptUseCase, priceTolerance, quantityTolerance float64, defaultTerms *models.PaymentTerms) *InvoiceUseCase {
+	return &InvoiceUseCase{
+		invoiceRepository: invoiceRepo,
+		vendorRepository:  vendorRepo,
+		purchaseOrders:    purchaseOrders,
+		goodsReceipts:     goodsReceipts,
+		priceTolerance:    priceTolerance,
+		quantityTolerance: quantityTolerance,
+		defaultTerms:      defaultTerms,
+	}
+}
+
+// Submit stores an invoice of the caller's vendor against one of its sent orders and matches it right away
+func (u *InvoiceUseCase) Submit(ctx context.Context, req *models.InvoiceRequest) (*models.Invoice, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vendor, err := u.callerVendor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	order, err := u.purchaseOrders.vendorOrder(ctx, req.PurchaseOrderID, vendor.ID)
+	if err != nil {
+		return nil, err
+	}
+	switch order.Status {
+	case models.PurchaseOrderStatusSent, models.PurchaseOrderStatusAcknowledged,
+		models.PurchaseOrderStatusPartiallyReceived, models.PurchaseOrderStatusClosed:
+	default:
+		return nil, apperror.Conflict("purchase_order_not_invoiceable",
+			"only sent, acknowledged, partially received or closed purchase orders can be invoiced")
+	}
+	invoiceDate, err := time.Parse(time.DateOnly, req.InvoiceDate)
+	if err != nil {
+		return nil, apperror.Validation("invalid_invoice_date", "invoice_date must be formatted as YYYY-MM-DD")
+	}
+	// the due date and the early payment discount run from the invoice date, it has to lie
+	// between the day the order reached the vendor and the day the invoice is submitted
+	if invoiceDate.After(time.Now().UTC().Truncate(24 * time.Hour)) {
+		return nil, apperror.Validation("invalid_invoice_date", "invoice_date cannot be in the future")
+	}
+	if order.SentAt != nil && invoiceDate.Before(order.SentAt.UTC().Truncate(24*time.Hour)) {
+		return nil, apperror.Validation("invalid_invoice_date",
+			fmt.Sprintf("invoice_date cannot be before the purchase order was sent on %s", order.SentAt.UTC().Format(time.DateOnly)))
+	}
+
+	orderLines := make(map[string]*models.PurchaseOrderLine, len(order.Lines))
+	for _, line := range order.Lines {
+		orderLines[line.ID] = line
+	}
+	invoice := &models.Invoice{
+		VendorID:        vendor.ID,
+		PurchaseOrderID: order.ID,
+		InvoiceNumber:   req.InvoiceNumber,
+		InvoiceDate:     invoiceDate,
+		Notes:           req.Notes,
+		SubmittedBy:     userID,
+	}
+	for i, lineReq := range req.Lines {
+		orderLine, ok := orderLines[lineReq.PurchaseOrderLineID]
+		if !ok {
+			return nil, apperror.Validation("unknown_purchase_order_line",
+				fmt.Sprintf("line %s is not a line of purchase order %s", lineReq.PurchaseOrderLineID, order.Number))
+		}
+		for _, previous := range invoice.Lines {
+			if previous.PurchaseOrderLineID == lineReq.PurchaseOrderLineID {
+				return nil, apperror.Validation("duplicate_purchase_order_line",
+					fmt.Sprintf("line %d is invoiced more than once", orderLine.LineNo))
+			}
+		}
+		line := &models.InvoiceLine{
+			LineNo:              i + 1,
+			PurchaseOrderLineID: lineReq.PurchaseOrderLineID,
+			Quantity:            roundQuantity(lineReq.Quantity),
+			UnitPrice:           round2(lineReq.UnitPrice),
+			TaxRate:             lineReq.TaxRate,
+		}
+		line.Amount = round2(line.Quantity * line.UnitPrice)
+		line.TaxAmount = round2(line.Amount * line.TaxRate / 100)
+		invoice.Subtotal += line.Amount
+		invoice.TaxAmount += line.TaxAmount
+		invoice.Lines = append(invoice.Lines, line)
+	}
+	invoice.Subtotal = round2(invoice.Subtotal)
+	invoice.TaxAmount = round2(invoice.TaxAmount)
+	invoice.TotalAmount = round2(invoice.Subtotal + invoice.TaxAmount)
+
+	if err := u.match(ctx, invoice, order); err != nil {
+		return nil, err
+	}
+	invoice.Status = invoice.MatchStatus
+	created, err := u.invoiceRepository.Create(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, errInvoicedQuantitiesChanged
+	}
+	return u.Get(ctx, invoice.ID)
+}
+
+// Get returns an invoice of the organization with its lines
+func (u *InvoiceUseCase) Get(ctx context.Context, id string) (*models.Invoice, error) {
+	invoice, err := u.invoiceRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	if invoice == nil {
+		return nil, apperror.NotFound("invoice_not_found", fmt.Sprintf("invoice with ID %s not found", id))
+	}
+	return invoice, nil
+}
+
+// List returns a page of invoices, newest first, with the total count
+func (u *InvoiceUseCase) List(ctx context.Context, filter models.InvoiceFilter, limit, page int) ([]*models.Invoice, int, error) {
+	limit, offset, err := pageOffset(limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	invoices, count, err := u.invoiceRepository.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	return invoices, count, nil
+}
+
+// Exceptions returns the queue of invoices whose match failed and that nobody reviewed yet, oldest first
+func (u *InvoiceUseCase) Exceptions(ctx context.Context, limit, page int) ([]*models.Invoice, int, error) {
+	return u.List(ctx, models.InvoiceFilter{Status: models.InvoiceStatusException, OldestFirst: true}, limit, page)
+}
+
+// Match runs the three-way match of an invoice again, for instance after more goods were received.
+// A held invoice is released with the new result
+func (u *InvoiceUseCase) Match(ctx context.Context, id string) (*models.Invoice, error) {
+	invoice, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !reviewable(invoice.Status) {
+		return nil, errInvoiceClosed
+	}
+	order, err := u.purchaseOrders.Get(ctx, invoice.PurchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	from := invoice.Status
+	if err := u.match(ctx, invoice, order); err != nil {
+		return nil, err
+	}
+	invoice.Status = invoice.MatchStatus
+
+	updated, err := u.invoiceRepository.UpdateMatch(ctx, invoice, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update invoice match: %w", err)
+	}
+	if !updated {
+		return nil, errInvoiceStatusChanged
+	}
+	return u.Get(ctx, id)
+}
+
+// Approve releases an invoice for payment and sets its due date and early payment discount from
+// the payment terms. The invoice is matched again first, against the receipts and invoices of the
+// order as they are now. Approving an invoice that did not match overrides the exceptions and needs a note saying why
+func (u *InvoiceUseCase) Approve(ctx context.Context, id string, req *models.InvoiceApproveRequest) (*models.Invoice, error) {
+	invoice, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !reviewable(invoice.Status) {
+		return nil, errInvoiceClosed
+	}
+	order, err := u.purchaseOrders.Get(ctx, invoice.PurchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	from := invoice.Status
+	if err := u.match(ctx, invoice, order); err != nil {
+		return nil, err
+	}
+	// the new result is kept even when the approval is refused below, so an invoice that no
+	// longer matches shows up in the exceptions queue. A held invoice stays held until approved
+	if invoice.Status != models.InvoiceStatusOnHold {
+		invoice.Status = invoice.MatchStatus
+	}
+	updated, err := u.invoiceRepository.UpdateMatch(ctx, invoice, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update invoice match: %w", err)
+	}
+	if !updated {
+		return nil, errInvoiceStatusChanged
+	}
+	if invoice.MatchStatus == models.MatchStatusException && req.Note == "" {
+		return nil, apperror.Validation("override_note_required", "a note is required to approve an invoice with match exceptions")
+	}
+	terms, err := u.paymentTerms(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+	scheduleInvoice(invoice, terms)
+	return u.review(ctx, invoice, &models.InvoiceReview{
+		Status:         models.InvoiceStatusApproved,
+		Note:           req.Note,
+		PaymentTerms:   invoice.PaymentTerms,
+		DueDate:        invoice.DueDate,
+		DiscountDate:   invoice.DiscountDate,
+		DiscountAmount: invoice.DiscountAmount,
+	})
+}
+
+// Hold parks a matched or exception invoice, it is approved, disputed or matched again later
+func (u *InvoiceUseCase) Hold(ctx context.Context, id string, req *models.InvoiceReasonRequest) (*models.Invoice, error) {
+	invoice, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status == models.InvoiceStatusOnHold {
+		return nil, apperror.Conflict("invoice_already_on_hold", "the invoice is already on hold")
+	}
+	if !reviewable(invoice.Status) {
+		return nil, errInvoiceClosed
+	}
+	return u.review(ctx, invoice, &models.InvoiceReview{Status: models.InvoiceStatusOnHold, Note: req.Reason})
+}
+
+// Dispute sends an invoice back to the vendor with the reason, the vendor submits a corrected invoice
+// under a new number. Disputed invoices no longer count towards the invoiced quantity of the order
+func (u *InvoiceUseCase) Dispute(ctx context.Context, id string, req *models.InvoiceReasonRequest) (*models.Invoice, error) {
+	invoice, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !reviewable(invoice.Status) {
+		return nil, errInvoiceClosed
+	}
+	return u.review(ctx, invoice, &models.InvoiceReview{Status: models.InvoiceStatusDisputed, Note: req.Reason})
+}
+
+// VendorInvoices returns a page of the invoices of the caller's vendor, newest first
+func (u *InvoiceUseCase) VendorInvoices(ctx context.Context, status string, limit, page int) ([]*models.Invoice, int, error) {
+	vendor, err := u.callerVendor(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return u.List(ctx, models.InvoiceFilter{Status: status, VendorID: vendor.ID}, limit, page)
+}
+
+// VendorInvoice returns an invoice of the caller's vendor, invoices of other vendors are
+// reported as not found
+func (u *InvoiceUseCase) VendorInvoice(ctx context.Context, id string) (*models.Invoice, error) {
+	vendor, err := u.callerVendor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.VendorID != vendor.ID {
+		return nil, apperror.NotFound("invoice_not_found", fmt.Sprintf("invoice with ID %s not found", id))
+	}
+	return invoice, nil
+}
+
+// match compares every line of the invoice with the PO line price and tax rate and with the
+// quantity accepted on goods receipts, less what other invoices of the order already claim
+func (u *InvoiceUseCase) match(ctx context.Context, invoice *models.Invoice, order *models.PurchaseOrder) error {
+	summary, err := u.goodsReceipts.summary(ctx, order)
+	if err != nil {
+		return err
+	}
+	received := make(map[string]*models.ReceiptSummaryLine, len(summary))
+	for _, line := range summary {
+		received[line.PurchaseOrderLineID] = line
+	}
+	orderLines := make(map[string]*models.PurchaseOrderLine, len(order.Lines))
+	for _, line := range order.Lines {
+		orderLines[line.ID] = line
+	}
+	invoiced, err := u.invoiceRepository.InvoicedQuantities(ctx, order.ID, invoice.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get invoiced quantities: %w", err)
+	}
+
+	invoice.MatchStatus = models.MatchStatusMatched
+	for _, line := range invoice.Lines {
+		line.MatchExceptions = []string{}
+		line.AcceptedQuantity = 0
+		line.PreviouslyInvoiced = roundQuantity(invoiced[line.PurchaseOrderLineID])
+		if receipt, ok := received[line.PurchaseOrderLineID]; ok {
+			line.AcceptedQuantity = receipt.AcceptedQuantity
+		}
+		if orderLine, ok := orderLines[line.PurchaseOrderLineID]; ok {
+			line.OrderUnitPrice = orderLine.UnitPrice
+			line.OrderTaxRate = orderLine.TaxRate
+		}
+
+		// paying less than the PO price is never an exception
+		if line.UnitPrice > round2(line.OrderUnitPrice*(1+u.priceTolerance/100)) {
+			line.MatchExceptions = append(line.MatchExceptions, models.MatchExceptionPriceVariance)
+		}
+		if math.Abs(line.TaxRate-line.OrderTaxRate) > 0.005 {
+			line.MatchExceptions = append(line.MatchExceptions, models.MatchExceptionTaxRateVariance)
+		}
+		if line.PreviouslyInvoiced+line.Quantity > line.AcceptedQuantity*(1+u.quantityTolerance/100)+quantityEpsilon {
+			line.MatchExceptions = append(line.MatchExceptions, models.MatchExceptionQuantityNotReceived)
+		}
+
+		line.MatchStatus = models.MatchStatusMatched
+		if len(line.MatchExceptions) > 0 {
+			line.MatchStatus = models.MatchStatusException
+			invoice.MatchStatus = models.MatchStatusException
+		}
+	}
+	invoice.MatchedAt = time.Now()
+	return nil
+}
+
+// paymentTerms returns the terms an invoice is paid under: those of its order, else those of
+// its vendor, else the default terms. Terms written before they were checked are skipped
+func (u *InvoiceUseCase) paymentTerms(ctx context.Context, invoice *models.Invoice) (*models.PaymentTerms, error) {
+	order, err := u.purchaseOrders.Get(ctx, invoice.PurchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	vendor, err := u.vendorRepository.GetVendorByID(ctx, invoice.VendorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vendor: %w", err)
+	}
+	for _, terms := range []string{order.PaymentTerms, vendor.PaymentTerms} {
+		if parsed, err := ParsePaymentTerms(terms); err == nil {
+			return parsed, nil
+		}
+	}
+	return u.defaultTerms, nil
+}
+
+// review records the decision of the caller, a concurrent change is reported as a conflict
+func (u *InvoiceUseCase) review(ctx context.Context, invoice *models.Invoice, review *models.InvoiceReview) (*models.Invoice, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	review.ReviewedBy = userID
+	review.ReviewedAt = time.Now()
+	reviewed, err := u.invoiceRepository.Review(ctx, invoice.ID, invoice.Status, review)
+	if err != nil {
+		return nil, fmt.Errorf("failed to review invoice: %w", err)
+	}
+	if !reviewed {
+		return nil, errInvoiceStatusChanged
+	}
+	return u.Get(ctx, invoice.ID)
+}
+
+// callerVendor returns the vendor profile of the caller, invoices are submitted on its behalf
+func (u *InvoiceUseCase) callerVendor(ctx context.Context) (*models.Vendor, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vendor, err := u.vendorRepository.GetVendorByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user vendor: %w", err)
+	}
+	if vendor == nil {
+		return nil, apperror.Forbidden("vendor_profile_required", "only vendor users can submit invoices")
+	}
+	return vendor, nil
+}
+
+// reviewable reports whether finance can still act on an invoice in the status
+func reviewable(status string) bool {
+	switch status {
+	case models.InvoiceStatusMatched, models.InvoiceStatusException, models.InvoiceStatusOnHold:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package usecases_test
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/repositories/memory"
+	"e-procurement/internals/usecases"
+	"e-procurement/pkg/apperror"
+	"e-procurement/pkg/rbac"
+	"testing"
+	"time"
+)
+
+// invoiceFixture is a purchase order of one line over 10 units, sent to an approved vendor
+type invoiceFixture struct {
+	org       *testOrg
+	buyer     string
+	approver  string
+	warehouse string
+	finance   string
+	seller    string
+	order     *models.PurchaseOrder
+	approvals *usecases.ApprovalUseCase
+	orders    *usecases.PurchaseOrderUseCase
+	receipts  *usecases.GoodsReceiptUseCase
+	invoices  *usecases.InvoiceUseCase
+}
+
+func newInvoiceFixture(t *testing.T) *invoiceFixture {
+	t.Helper()
+	org := newTestOrg(t)
+	f := &invoiceFixture{
+		org:       org,
+		buyer:     org.addUser("buyer", rbac.RoleProcurementOfficer),
+		approver:  org.addUser("approver", rbac.RoleApprover),
+		warehouse: org.addUser("warehouse", rbac.RoleWarehouse),
+		finance:   org.addUser("finance", rbac.RoleFinance),
+		seller:    org.addUser("seller", rbac.RoleVendor),
+		approvals: org.approvals(),
+	}
+	vendors := memory.NewVendorRepository(org.store)
+	vendor := org.addVendor(f.seller, "Seller Supply")
+	if _, err := vendors.UpdateVendorStatus(org.as(f.seller), vendor.ID, models.VendorStatusPending, models.VendorStatusApproved); err != nil {
+		t.Fatalf("approve vendor: %v", err)
+	}
+	terms, err := usecases.ParsePaymentTerms("Net 30")
+	if err != nil {
+		t.Fatalf("parse payment terms: %v", err)
+	}
+	f.orders = usecases.NewPurchaseOrderUseCase(memory.NewPurchaseOrderRepository(org.store), memory.NewProductRepository(org.store),
+		vendors, memory.NewOrganizationRepository(org.store), f.approvals)
+	f.approvals.Register(models.ApprovalDocumentPurchaseOrder, f.orders)
+	f.receipts = usecases.NewGoodsReceiptUseCase(memory.NewGoodsReceiptRepository(org.store), f.orders, 0)
+	f.invoices = usecases.NewInvoiceUseCase(memory.NewInvoiceRepository(org.store), vendors, f.orders, f.receipts, 0, 0, terms)
+
+	order, err := f.orders.Create(org.as(f.buyer), &models.PurchaseOrderRequest{
+		VendorID: vendor.ID,
+		PurchaseOrderDetails: models.PurchaseOrderDetails{
+			ShipTo: "Warehouse Jakarta",
+			Lines:  []*models.PurchaseOrderLineRequest{{Description: "Bolt", Quantity: 10, UnitPrice: 1000}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("create purchase order: %v", err)
+	}
+	if _, err := f.orders.Submit(org.as(f.buyer), order.ID); err != nil {
+		t.Fatalf("submit purchase order: %v", err)
+	}
+	pending, _, err := f.approvals.Pending(org.as(f.approver), 10, 1)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("pending approvals: %d, %v", len(pending), err)
+	}
+	if _, err := f.approvals.Approve(org.as(f.approver), pending[0].Request.ID, &models.ApprovalDecisionRequest{}); err != nil {
+		t.Fatalf("approve purchase order: %v", err)
+	}
+	if f.order, err = f.orders.Send(org.as(f.buyer), order.ID); err != nil {
+		t.Fatalf("send purchase order: %v", err)
+	}
+	return f
+}
+
+func (f *invoiceFixture) receive(t *testing.T, quantity float64) {
+	t.Helper()
+	_, err := f.receipts.Record(f.org.as(f.warehouse), f.order.ID, &models.GoodsReceiptRequest{
+		Lines: []*models.GoodsReceiptLineRequest{{PurchaseOrderLineID: f.order.Lines[0].ID, ReceivedQuantity: quantity}},
+	})
+	if err != nil {
+		t.Fatalf("record goods receipt: %v", err)
+	}
+}
+
+func (f *invoiceFixture) submit(number, invoiceDate string, quantity float64) (*models.Invoice, error) {
+	return f.invoices.Submit(f.org.as(f.seller), &models.InvoiceRequest{
+		PurchaseOrderID: f.order.ID,
+		InvoiceNumber:   number,
+		InvoiceDate:     invoiceDate,
+		Lines:           []*models.InvoiceLineRequest{{PurchaseOrderLineID: f.order.Lines[0].ID, Quantity: quantity, UnitPrice: 1000}},
+	})
+}
+
+func (f *invoiceFixture) mustSubmit(t *testing.T, number string, quantity float64, wantMatch string) *models.Invoice {
+	t.Helper()
+	invoice, err := f.submit(number, time.Now().UTC().Format(time.DateOnly), quantity)
+	if err != nil {
+		t.Fatalf("submit invoice %s: %v", number, err)
+	}
+	if invoice.MatchStatus != wantMatch {
+		t.Fatalf("invoice %s is %s, want %s", number, invoice.MatchStatus, wantMatch)
+	}
+	return invoice
+}
+
+func TestInvoiceApproveMatchesAgain(t *testing.T) {
+	t.Run("goods received after submission", func(t *testing.T) {
+		f := newInvoiceFixture(t)
+		invoice := f.mustSubmit(t, "INV-1", 5, models.MatchStatusException)
+		f.receive(t, 5)
+
+		approved, err := f.invoices.Approve(f.org.as(f.finance), invoice.ID, &models.InvoiceApproveRequest{})
+		assertAppError(t, err, 0, "")
+		if approved.Status != models.InvoiceStatusApproved || approved.MatchStatus != models.MatchStatusMatched {
+			t.Fatalf("invoice is %s with match %s, want approved and matched", approved.Status, approved.MatchStatus)
+		}
+	})
+
+	t.Run("overlapping invoice disputed", func(t *testing.T) {
+		f := newInvoiceFixture(t)
+		f.receive(t, 10)
+		first := f.mustSubmit(t, "INV-1", 6, models.MatchStatusMatched)
+		second := f.mustSubmit(t, "INV-2", 6, models.MatchStatusException)
+		if _, err := f.invoices.Dispute(f.org.as(f.finance), first.ID, &models.InvoiceReasonRequest{Reason: "wrong price"}); err != nil {
+			t.Fatalf("dispute invoice: %v", err)
+		}
+
+		_, err := f.invoices.Approve(f.org.as(f.finance), second.ID, &models.InvoiceApproveRequest{})
+		assertAppError(t, err, 0, "")
+	})
+
+	t.Run("matched invoice overtaken by a later invoice", func(t *testing.T) {
+		f := newInvoiceFixture(t)
+		f.receive(t, 5)
+		first := f.mustSubmit(t, "INV-1", 5, models.MatchStatusMatched)
+		f.mustSubmit(t, "INV-2", 5, models.MatchStatusException)
+
+		_, err := f.invoices.Approve(f.org.as(f.finance), first.ID, &models.InvoiceApproveRequest{})
+		assertAppError(t, err, apperror.KindValidation, "override_note_required")
+		// the refused approval still records the new result, the invoice joins the exceptions queue
+		invoice, err := f.invoices.Get(f.org.as(f.finance), first.ID)
+		assertAppError(t, err, 0, "")
+		if invoice.Status != models.InvoiceStatusException || invoice.Lines[0].PreviouslyInvoiced != 5 {
+			t.Fatalf("invoice is %s with %v previously invoiced, want exception with 5", invoice.Status, invoice.Lines[0].PreviouslyInvoiced)
+		}
+
+		approved, err := f.invoices.Approve(f.org.as(f.finance), first.ID, &models.InvoiceApproveRequest{Note: "second invoice is a duplicate"})
+		assertAppError(t, err, 0, "")
+		if approved.Status != models.InvoiceStatusApproved {
+			t.Fatalf("invoice is %s, want approved", approved.Status)
+		}
+	})
+}
+
+func TestInvoiceDateBetweenSendingAndSubmission(t *testing.T) {
+	today := time.Now().UTC()
+	tests := []struct {
+		name        string
+		invoiceDate string
+		wantCode    string
+	}{
+		{name: "dated on the day the order was sent", invoiceDate: today.Format(time.DateOnly)},
+		{name: "backdated before the order was sent", invoiceDate: today.AddDate(0, 0, -1).Format(time.DateOnly), wantCode: "invalid_invoice_date"},
+		{name: "dated in the future", invoiceDate: today.AddDate(0, 0, 1).Format(time.DateOnly), wantCode: "invalid_invoice_date"},
+		{name: "not a date", invoiceDate: "10/10/2026", wantCode: "invalid_invoice_date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newInvoiceFixture(t)
+			f.receive(t, 10)
+			_, err := f.submit("INV-1", tt.invoiceDate, 10)
+			assertAppError(t, err, apperror.KindValidation, tt.wantCode)
+		})
+	}
+}
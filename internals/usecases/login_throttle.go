@@ -0,0 +1,131 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	errAccountLocked = apperror.Locked("account_locked", "too many failed login attempts, the account is temporarily locked")
+	errTooManyLoginAttempts = apperror.TooManyRequests("too_many_login_attempts", "too many failed login attempts, please try again later")
+)
+
+// LoginThrottlePolicy configures the login brute-force protection
+type LoginThrottlePolicy struct {
+	// failures older than Window are forgotten
+	Window time.Duration
+	// after BackoffAfter consecutive failures an account waits BackoffBase, doubled
+	// on every further failure up to BackoffMax
+	BackoffAfter int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+	// after LockoutAfter consecutive failures the account is locked for LockoutDuration
+	LockoutAfter    int
+	LockoutDuration time.Duration
+	// failures allowed from one IP address within Window, across all accounts
+	MaxIPFailures int
+}
+
+// LoginThrottle slows down and locks out password guessing per account and per client IP.
+// accounts are keyed by email whether they exist or not, so a lockout reveals nothing.
+type LoginThrottle struct {
+	repo repository.LoginAttemptRepository
+	policy LoginThrottlePolicy
+}
+
+func NewLoginThrottle(repo repository.LoginAttemptRepository, policy LoginThrottlePolicy) *LoginThrottle {
+	return &LoginThrottle{
+		repo: repo,
+		policy: policy,
+	}
+}
+
+// Check returns a locked or too many requests error when the attempt must be refused,
+// the refused attempt is recorded for audit without counting as a failure
+func (t *LoginThrottle) Check(ctx context.Context, email, ipAddress string) error {
+	now := time.Now()
+	email = normalizeEmail(email)
+
+	account, err := t.repo.AccountFailures(ctx, email, now.Add(-t.policy.Window))
+	if err != nil {
+		return fmt.Errorf("failed to count login failures: %w", err)
+	}
+	if account.Count >= t.policy.LockoutAfter {
+		if wait := account.LastFailureAt.Add(t.policy.LockoutDuration).Sub(now); wait > 0 {
+			return t.refuse(ctx, email, ipAddress, models.LoginOutcomeLocked, errAccountLocked.WithRetryAfter(wait))
+		}
+	} else if account.Count >= t.policy.BackoffAfter {
+		if wait := account.LastFailureAt.Add(t.backoff(account.Count)).Sub(now); wait > 0 {
+			return t.refuse(ctx, email, ipAddress, models.LoginOutcomeThrottled, errTooManyLoginAttempts.WithRetryAfter(wait))
+		}
+	}
+
+	ip, err := t.repo.IPFailures(ctx, ipAddress, now.Add(-t.policy.Window))
+	if err != nil {
+		return fmt.Errorf("failed to count login failures: %w", err)
+	}
+	if ip.Count >= t.policy.MaxIPFailures {
+		wait := ip.LastFailureAt.Add(t.policy.Window).Sub(now)
+		return t.refuse(ctx, email, ipAddress, models.LoginOutcomeThrottled, errTooManyLoginAttempts.WithRetryAfter(wait))
+	}
+	return nil
+}
+
+// Record stores the outcome of a checked attempt, userID is empty for unknown emails
+func (t *LoginThrottle) Record(ctx context.Context, email, ipAddress, userID, outcome string) error {
+	attempt := &models.LoginAttempt{
+		Email:     normalizeEmail(email),
+		IPAddress: ipAddress,
+		Outcome:   outcome,
+	}
+	if userID != "" {
+		attempt.UserID = &userID
+	}
+	if err := t.repo.Record(ctx, attempt); err != nil {
+		return fmt.Errorf("failed to record login attempt: %w", err)
+	}
+	return nil
+}
+
+// List returns the recorded attempts for audit
+func (t *LoginThrottle) List(ctx context.Context, filter models.LoginAttemptFilter, limit, page int) ([]*models.LoginAttempt, int, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if limit > 100 {
+		return nil, 0, apperror.Validation("invalid_pagination", "limit must be between 1 and 100")
+	}
+	filter.Email = normalizeEmail(filter.Email)
+	return t.repo.List(ctx, filter, limit, (page-1)*limit)
+}
+
+func (t *LoginThrottle) refuse(ctx context.Context, email, ipAddress, outcome string, refusal error) error {
+	if err := t.Record(ctx, email, ipAddress, "", outcome); err != nil {
+		return err
+	}
+	return refusal
+}
+
+// backoff returns the wait after `failures` consecutive failures
+func (t *LoginThrottle) backoff(failures int) time.Duration {
+	wait := t.policy.BackoffBase
+	for i := t.policy.BackoffAfter; i < failures && wait < t.policy.BackoffMax; i++ {
+		wait *= 2
+	}
+	if wait > t.policy.BackoffMax {
+		wait = t.policy.BackoffMax
+	}
+	return wait
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
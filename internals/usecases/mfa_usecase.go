@@ -0,0 +1,267 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	"e-procurement/pkg/auth"
+	customContext "e-procurement/pkg/context"
+	"e-procurement/pkg/encripted"
+	"e-procurement/pkg/totp"
+	"encoding/base32"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// RateLimitScopeMFA limits second step login attempts per user
+	RateLimitScopeMFA = "mfa_verify"
+	recoveryCodeCount = 10
+	// accept the previous and next code as well to allow for clock drift
+	totpSkew = 1
+)
+
+var (
+	errMFAAlreadyEnabled = apperror.Conflict("mfa_already_enabled", "two-factor authentication is already enabled")
+	errMFANotStarted = apperror.Validation("mfa_enrollment_not_started", "start the enrollment through /auth/mfa/enroll first")
+	errInvalidMFACode = apperror.Validation("invalid_mfa_code", "the code is invalid or was already used")
+	errInvalidMFAToken = apperror.Unauthorized("invalid_mfa_token", "mfa token is invalid or expired, please log in again")
+	errInvalidMFACredential = apperror.Unauthorized("invalid_mfa_code", "the code is invalid or was already used")
+	errTooManyMFAAttempts = apperror.TooManyRequests("too_many_requests", "too many two-factor attempts, please try again later")
+)
+
+type MFAUseCase struct {
+	mfaRepository repository.MFARepository
+	userRepository repository.UserRepository
+	auth *AuthUseCase
+	revocations *TokenRevocationUseCase
+	limiter *RateLimiter
+	cipher *encripted.Cipher
+	issuer string
+}
+
+func NewMFAUseCase(
+	mfaRepo repository.MFARepository,
+	userRepo repository.UserRepository,
+	authUseCase *AuthUseCase,
+	revocations *TokenRevocationUseCase,
+	limiter *RateLimiter,
+	cipher *encripted.Cipher,
+	issuer string,
+	) *MFAUseCase {
+	return &MFAUseCase{
+		mfaRepository: mfaRepo,
+		userRepository: userRepo,
+		auth: authUseCase,
+		revocations: revocations,
+		limiter: limiter,
+		cipher: cipher,
+		issuer: issuer,
+	}
+}
+
+// method to start a TOTP enrollment for the caller. the secret is shown once and only
+// becomes active after Confirm, starting again replaces a pending secret.
+func (u *MFAUseCase) Enroll(ctx context.Context) (*models.MFAEnrollResponse, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := u.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+	if user == nil {
+		return nil, apperror.NotFound("user_not_found", "user not found")
+	}
+
+	existing, err := u.mfaRepository.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mfa enrollment: %w", err)
+	}
+	if existing != nil && existing.EnabledAt != nil {
+		return nil, errMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("error generating totp secret: %w", err)
+	}
+	encrypted, err := u.cipher.Encrypt(secret)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting totp secret: %w", err)
+	}
+	if err := u.mfaRepository.SavePending(ctx, userID, encrypted); err != nil {
+		return nil, err
+	}
+
+	return &models.MFAEnrollResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(u.issuer, user.Email, secret),
+	}, nil
+}
+
+// method to confirm the enrollment with a first code. it returns the recovery codes, shown
+// only once, and a new token pair carrying the mfa claim. other sessions are signed out.
+func (u *MFAUseCase) Confirm(ctx context.Context, req *models.MFAConfirmRequest) (*models.MFAConfirmResponse, *models.TokenPair, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	mfa, err := u.mfaRepository.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get mfa enrollment: %w", err)
+	}
+	if mfa == nil {
+		return nil, nil, errMFANotStarted
+	}
+	if mfa.EnabledAt != nil {
+		return nil, nil, errMFAAlreadyEnabled
+	}
+
+	secret, err := u.cipher.Decrypt(mfa.Secret)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error decrypting totp secret: %w", err)
+	}
+	step, ok := totp.Validate(secret, req.Code, time.Now(), totpSkew)
+	if !ok {
+		return nil, nil, errInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := u.mfaRepository.Enable(ctx, userID, step, hashes); err != nil {
+		return nil, nil, fmt.Errorf("failed to enable mfa: %w", err)
+	}
+	// sessions opened with the password alone must not survive the enrollment
+	if err := u.revocations.RevokeAllForUser(ctx, userID); err != nil {
+		return nil, nil, err
+	}
+
+	user, err := u.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+	if user == nil {
+		return nil, nil, apperror.NotFound("user_not_found", "user not found")
+	}
+	// the new session stays in the organization of the current one
+	tenantID, err := customContext.GetTenantFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	membership, err := u.auth.resolveMembership(ctx, user.ID, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := u.auth.login(ctx, user, membership)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &models.MFAConfirmResponse{RecoveryCodes: codes}, result.Tokens, nil
+}
+
+// method for the second login step: exchanges the challenge token returned by
+// AuthUseCase.Authenticate and a TOTP or recovery code for a token pair
+func (u *MFAUseCase) Verify(ctx context.Context, req *models.MFAVerifyRequest) (*models.LoginResult, error) {
+	challenge, err := u.auth.jwt.ValidateChallengeToken(req.MFAToken)
+	if err != nil {
+		return nil, errInvalidMFAToken
+	}
+	revoked, err := u.revocations.IsRevoked(ctx, challenge.TokenID, challenge.UserID, challenge.IssuedAt)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, errInvalidMFAToken
+	}
+
+	allowed, err := u.limiter.Allow(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, errTooManyMFAAttempts
+	}
+
+	mfa, err := u.mfaRepository.GetByUserID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mfa enrollment: %w", err)
+	}
+	if mfa == nil || mfa.EnabledAt == nil {
+		return nil, errInvalidMFAToken
+	}
+
+	if req.Code != "" {
+		secret, err := u.cipher.Decrypt(mfa.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("error decrypting totp secret: %w", err)
+		}
+		step, ok := totp.Validate(secret, req.Code, time.Now(), totpSkew)
+		if !ok {
+			return nil, errInvalidMFACredential
+		}
+		// a code is accepted once, later steps only
+		fresh, err := u.mfaRepository.UseStep(ctx, challenge.UserID, step)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record totp step: %w", err)
+		}
+		if !fresh {
+			return nil, errInvalidMFACredential
+		}
+	} else {
+		used, err := u.mfaRepository.UseRecoveryCode(ctx, challenge.UserID, hashRecoveryCode(req.RecoveryCode))
+		if err != nil {
+			return nil, fmt.Errorf("failed to use recovery code: %w", err)
+		}
+		if !used {
+			return nil, errInvalidMFACredential
+		}
+	}
+
+	// the challenge is single use
+	if err := u.revocations.RevokeToken(ctx, challenge.TokenID, challenge.UserID, challenge.ExpiresAt); err != nil {
+		return nil, err
+	}
+	user, err := u.userRepository.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+	if user == nil {
+		return nil, errInvalidMFAToken
+	}
+	membership, err := u.auth.resolveMembership(ctx, user.ID, challenge.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	return u.auth.login(ctx, user, membership)
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateRecoveryCodes returns the plain codes, formatted xxxxx-xxxxx, and their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, fmt.Errorf("error generating recovery code: %w", err)
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf))[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashRecoveryCode(raw))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode ignores case, spaces and dashes so codes can be typed loosely
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return auth.HashOpaqueToken(normalized)
+}
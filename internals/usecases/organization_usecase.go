@@ -0,0 +1,118 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	customContext "e-procurement/pkg/context"
+	"fmt"
+	"regexp"
+)
+
+// organization codes are used in registration requests, keep them URL friendly
+var organizationCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// OrganizationUseCase manages buyer organizations (tenants) and their members
+type OrganizationUseCase struct {
+	organizationRepository repository.OrganizationRepository
+	userRepository         repository.UserRepository
+	revocations            *TokenRevocationUseCase
+}
+
+func NewOrganizationUseCase(
+	organizationRepo repository.OrganizationRepository,
+	userRepo repository.UserRepository,
+	revocations *TokenRevocationUseCase,
+) *OrganizationUseCase {
+	return &OrganizationUseCase{
+		organizationRepository: organizationRepo,
+		userRepository:         userRepo,
+		revocations:            revocations,
+	}
+}
+
+// Create stores a new organization, the caller joins it as admin
+func (u *OrganizationUseCase) Create(ctx context.Context, req *models.CreateOrganizationRequest) (*models.Organization, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !organizationCodePattern.MatchString(req.Code) {
+		return nil, apperror.Validation("invalid_organization_code", "code may only contain lowercase letters, digits and dashes")
+	}
+	return u.organizationRepository.Create(ctx, req, userID)
+}
+
+// ListMine returns the organizations of the caller with the caller's role in each
+func (u *OrganizationUseCase) ListMine(ctx context.Context) ([]*models.OrganizationMembership, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return u.organizationRepository.ListForUser(ctx, userID)
+}
+
+// ListMembers returns the members of the organization of the request
+func (u *OrganizationUseCase) ListMembers(ctx context.Context) ([]*models.OrganizationMember, error) {
+	tenantID, err := customContext.GetTenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return u.organizationRepository.ListMembers(ctx, tenantID)
+}
+
+// AddMember adds a registered user, found by email, to the organization of the request
+func (u *OrganizationUseCase) AddMember(ctx context.Context, req *models.AddOrganizationMemberRequest) (*models.OrganizationMembership, error) {
+	tenantID, err := customContext.GetTenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := u.userRepository.Authenticate(ctx, req.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+	if user == nil {
+		return nil, apperror.NotFound("user_not_found", fmt.Sprintf("no user registered with email %s", req.Email))
+	}
+	if err := u.organizationRepository.AddMember(ctx, tenantID, user.ID, req.Role); err != nil {
+		return nil, err
+	}
+	return u.organizationRepository.GetMembership(ctx, tenantID, user.ID)
+}
+
+// UpdateMember changes the role of a member of the organization of the request,
+// the new role applies from the next token refresh
+func (u *OrganizationUseCase) UpdateMember(ctx context.Context, userID string, req *models.UpdateOrganizationMemberRequest) error {
+	tenantID, err := u.otherMember(ctx, userID, "cannot_change_own_role", "cannot change your own role")
+	if err != nil {
+		return err
+	}
+	return u.organizationRepository.UpdateMemberRole(ctx, tenantID, userID, req.Role)
+}
+
+// RemoveMember removes a user from the organization of the request and ends the
+// sessions of that user, so tokens scoped to the organization stop working at once
+func (u *OrganizationUseCase) RemoveMember(ctx context.Context, userID string) error {
+	tenantID, err := u.otherMember(ctx, userID, "cannot_remove_self", "cannot remove yourself from the organization")
+	if err != nil {
+		return err
+	}
+	if err := u.organizationRepository.RemoveMember(ctx, tenantID, userID); err != nil {
+		return err
+	}
+	return u.revocations.RevokeAllForUser(ctx, userID)
+}
+
+// otherMember returns the tenant of the request, refusing changes of the caller's own
+// membership so the last admin cannot lock the organization out
+func (u *OrganizationUseCase) otherMember(ctx context.Context, userID, code, message string) (string, error) {
+	callerID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	if callerID == userID {
+		return "", apperror.Forbidden(code, message)
+	}
+	return customContext.GetTenantFromContext(ctx)
+}
@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	customContext "e-procurement/pkg/context"
+	"e-procurement/pkg/rbac"
+	"fmt"
+)
+
+// OwnershipPolicy decides whether the caller may mutate a vendor or one of its products.
+// A vendor user may only touch its own vendor profile and products, admins may touch any.
+type OwnershipPolicy struct {
+	vendorRepository repository.VendorRepository
+}
+
+func NewOwnershipPolicy(vendorRepo repository.VendorRepository) *OwnershipPolicy {
+	return &OwnershipPolicy{
+		vendorRepository: vendorRepo,
+	}
+}
+
+// caller returns the user ID and role of the authenticated user
+func (p *OwnershipPolicy) caller(ctx context.Context) (string, string, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	position, err := customContext.GetPositionFromContext(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return userID, position, nil
+}
+
+// method to check the caller owns the vendor or is an admin
+func (p *OwnershipPolicy) CanManageVendor(ctx context.Context, vendor *models.Vendor) error {
+	userID, position, err := p.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if position == rbac.RoleAdmin {
+		return nil
+	}
+	if vendor.UserID != userID {
+		return apperror.Forbidden("vendor_not_owned", "you can only manage your own vendor")
+	}
+	return nil
+}
+
+// method to check the caller owns the vendor of the product or is an admin
+func (p *OwnershipPolicy) CanManageProduct(ctx context.Context, product *models.Product) error {
+	vendor, err := p.vendorRepository.GetVendorByID(ctx, product.VendorID)
+	if err != nil {
+		return fmt.Errorf("failed to get product vendor: %w", err)
+	}
+	if err := p.CanManageVendor(ctx, vendor); err != nil {
+		if apperror.IsKind(err, apperror.KindForbidden) {
+			return apperror.Forbidden("product_not_owned", "you can only manage products of your own vendor")
+		}
+		return err
+	}
+	return nil
+}
+
+// method to check the caller may hand a vendor over to another user, only admins can
+func (p *OwnershipPolicy) CanTransferVendor(ctx context.Context, vendor *models.Vendor, newUserID string) error {
+	if newUserID == "" || newUserID == vendor.UserID {
+		return nil
+	}
+	_, position, err := p.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if position != rbac.RoleAdmin {
+		return apperror.Forbidden("vendor_transfer_forbidden", "only admins can change the owner of a vendor")
+	}
+	return nil
+}
+
+// method to check the caller may change the payment terms of a vendor from current to terms, only admins
+// can since the terms are agreed with the buyer
+func (p *OwnershipPolicy) CanSetPaymentTerms(ctx context.Context, current, terms string) error {
+	if terms == "" || terms == current {
+		return nil
+	}
+	_, position, err := p.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if position != rbac.RoleAdmin {
+		return apperror.Forbidden("payment_terms_forbidden", "only admins can change the payment terms of a vendor")
+	}
+	return nil
+}
+
+// method to check the caller may replace the bank account of a vendor, payments go to that account so
+// only admins and finance can change it, the owner only while the vendor is returned for revision since
+// the vendor then goes through onboarding approval again
+func (p *OwnershipPolicy) CanSetBankAccount(ctx context.Context, vendor *models.Vendor, code, number, holder string) error {
+	if code == vendor.BankCode && number == vendor.BankAccountNumber && holder == vendor.BankAccountHolder {
+		return nil
+	}
+	_, position, err := p.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if position == rbac.RoleAdmin || position == rbac.RoleFinance || vendor.Status == models.VendorStatusReturned {
+		return nil
+	}
+	return apperror.Forbidden("bank_account_forbidden", "only admins and finance can change the bank account of a vendor")
+}
+
+// method to check the caller may read the bank account of a vendor, the owner for checking it and
+// admins and finance for paying the vendor
+func (p *OwnershipPolicy) CanReadBankAccount(ctx context.Context, vendor *models.Vendor) (bool, error) {
+	userID, position, err := p.caller(ctx)
+	if err != nil {
+		return false, err
+	}
+	return vendor.UserID == userID || position == rbac.RoleAdmin || position == rbac.RoleFinance, nil
+}
@@ -0,0 +1,323 @@
+package usecases_test
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/repositories/memory"
+	"e-procurement/internals/usecases"
+	"e-procurement/pkg/apperror"
+	"e-procurement/pkg/rbac"
+	"testing"
+)
+
+// ownershipFixture is a vendor with one product, owned by owner, next to a vendor of someone else
+type ownershipFixture struct {
+	org       *testOrg
+	admin     string
+	finance   string
+	owner     string
+	other     string
+	noProfile string
+	newOwner  string
+	vendor    *models.Vendor
+	product   *models.Product
+	vendors   *usecases.VendorUseCase
+	products  *usecases.ProductUseCase
+}
+
+func newOwnershipFixture(t *testing.T) *ownershipFixture {
+	t.Helper()
+	org := newTestOrg(t)
+	f := &ownershipFixture{
+		org:       org,
+		admin:     org.addUser("admin", rbac.RoleAdmin),
+		finance:   org.addUser("finance", rbac.RoleFinance),
+		owner:     org.addUser("owner", rbac.RoleVendor),
+		other:     org.addUser("other", rbac.RoleVendor),
+		noProfile: org.addUser("noprofile", rbac.RoleVendor),
+		newOwner:  org.addUser("newowner", rbac.RoleVendor),
+		vendors:   usecases.NewVendorUseCase(memory.NewVendorRepository(org.store), memory.NewUserRepository(org.store), org.approvals()),
+		products:  usecases.NewProductUsecase(memory.NewProductRepository(org.store), memory.NewVendorRepository(org.store)),
+	}
+	f.vendor = org.addVendor(f.owner, "Owner Supply")
+	org.addVendor(f.other, "Other Supply")
+	category := org.addCategory(f.admin, "Hardware")
+	product, err := memory.NewProductRepository(org.store).CreateProduct(org.as(f.owner), &models.CreateProductRequest{
+		ProductName:        "Bolt",
+		ProductPrice:       1000,
+		ProductDescription: "M8 bolt",
+		ProductCategoryID:  category.ID,
+		VendorID:           f.vendor.ID,
+	})
+	if err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	f.product = product
+	return f
+}
+
+func (f *ownershipFixture) updateVendor(userID string, req models.UpdateVendorRequest) error {
+	if req.VendorName == "" {
+		req.VendorName = "Renamed Supply"
+	}
+	if req.Description == "" {
+		req.Description = "renamed"
+	}
+	_, err := f.vendors.UpdateVendor(f.org.as(userID), f.vendor.ID, &req)
+	return err
+}
+
+func TestOwnershipPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		caller   func(f *ownershipFixture) string
+		act      func(f *ownershipFixture, userID string) error
+		wantKind apperror.Kind
+		wantCode string
+	}{
+		{
+			name:   "owner updates vendor",
+			caller: func(f *ownershipFixture) string { return f.owner },
+			act: func(f *ownershipFixture, userID string) error {
+				return f.updateVendor(userID, models.UpdateVendorRequest{})
+			},
+		},
+		{
+			name:   "owner deletes vendor",
+			caller: func(f *ownershipFixture) string { return f.owner },
+			act: func(f *ownershipFixture, userID string) error {
+				if err := f.products.DeleteProduct(f.org.as(userID), f.product.ID); err != nil {
+					return err
+				}
+				return f.vendors.DeleteVendor(f.org.as(userID), f.vendor.ID)
+			},
+		},
+		{
+			name:   "non-owner updates vendor",
+			caller: func(f *ownershipFixture) string { return f.other },
+			act: func(f *ownershipFixture, userID string) error {
+				return f.updateVendor(userID, models.UpdateVendorRequest{})
+			},
+			wantKind: apperror.KindForbidden,
+			wantCode: "vendor_not_owned",
+		},
+		{
+			name:   "non-owner deletes vendor",
+			caller: func(f *ownershipFixture) string { return f.other },
+			act: func(f *ownershipFixture, userID string) error {
+				return f.vendors.DeleteVendor(f.org.as(userID), f.vendor.ID)
+			},
+			wantKind: apperror.KindForbidden,
+			wantCode: "vendor_not_owned",
+		},
+		{
+			name:   "admin updates any vendor",
+			caller: func(f *ownershipFixture) string { return f.admin },
+			act: func(f *ownershipFixture, userID string) error {
+				return f.updateVendor(userID, models.UpdateVendorRequest{})
+			},
+		},
+		{
+			name:   "admin deletes any vendor",
+			caller: func(f *ownershipFixture) string { return f.admin },
+			act: func(f *ownershipFixture, userID string) error {
+				if err := f.products.DeleteProduct(f.org.as(userID), f.product.ID); err != nil {
+					return err
+				}
+				return f.vendors.DeleteVendor(f.org.as(userID), f.vendor.ID)
+			},
+		},
+		{
+			name:   "caller without vendor profile updates vendor",
+			caller: func(f *ownershipFixture) string { return f.noProfile },
+			act: func(f *ownershipFixture, userID string) error {
+				return f.updateVendor(userID, models.UpdateVendorRequest{})
+			},
+			wantKind: apperror.KindForbidden,
+			wantCode: "vendor_not_owned",
+		},
+		{
+			name:   "owner updates product",
+			caller: func(f *ownershipFixture) string { return f.owner },
+			act: func(f *ownershipFixture, userID string) error {
+				_, err := f.products.UpdateProduct(f.org.as(userID), f.product.ID, &models.UpdateProductRequest{ProductPrice: 1200})
+				return err
+			},
+		},
+		{
+			name:   "owner deletes product",
+			caller: func(f *ownershipFixture) string { return f.owner },
+			act: func(f *ownershipFixture, userID string) error {
+				return f.products.DeleteProduct(f.org.as(userID), f.product.ID)
+			},
+		},
+		{
+			name:   "non-owner updates product",
+			caller: func(f *ownershipFixture) string { return f.other },
+			act: func(f *ownershipFixture, userID string) error {
+				_, err := f.products.UpdateProduct(f.org.as(userID), f.product.ID, &models.UpdateProductRequest{ProductPrice: 1200})
+				return err
+			},
+			wantKind: apperror.KindForbidden,
+			wantCode: "product_not_owned",
+		},
+		{
+			name:   "non-owner deletes product",
+			caller: func(f *ownershipFixture) string { return f.other },
+			act: func(f *ownershipFixture, userID string) error {
+				return f.products.DeleteProduct(f.org.as(userID), f.product.ID)
+			},
+			wantKind: apperror.KindForbidden,
+			wantCode: "product_not_owned",
+		},
+		{
+			name:   "admin updates any product",
+			caller: func(f *ownershipFixture) string { return f.admin },
+			act: func(f *ownershipFixture, userID string) error {
+				_, err := f.products.UpdateProduct(f.org.as(userID), f.product.ID, &models.UpdateProductRequest{ProductPrice: 1200})
+				return err
+			},
+		},
+		{
+			name:   "caller without vendor profile deletes product",
+			caller: func(f *ownershipFixture) string { return f.noProfile },
+			act: func(f *ownershipFixture, userID string) error {
+				return f.products.DeleteProduct(f.org.as(userID), f.product.ID)
+			},
+			wantKind: apperror.KindForbidden,
+			wantCode: "product_not_owned",
+		},
+		{
+			name:   "admin transfers vendor",
+			caller: func(f *ownershipFixture) string { return f.admin },
+			act: func(f *ownershipFixture, userID string) error {
+				return f.updateVendor(userID, models.UpdateVendorRequest{UserID: f.newOwner})
+			},
+		},
+		{
+			name:   "owner transfers vendor",
+			caller: func(f *ownershipFixture) string { return f.owner },
+			act: func(f *ownershipFixture, userID string) error {
+				return f.updateVendor(userID, models.UpdateVendorRequest{UserID: f.newOwner})
+			},
+			wantKind: apperror.KindForbidden,
+			wantCode: "vendor_transfer_forbidden",
+		},
+		{
+			name:   "admin sets payment terms",
+			caller: func(f *ownershipFixture) string { return f.admin },
+			act: func(f *ownershipFixture, userID string) error {
+				return f.updateVendor(userID, models.UpdateVendorRequest{PaymentTerms: "2/10 Net 30"})
+			},
+		},
+		{
+			name:   "owner sets payment terms",
+			caller: func(f *ownershipFixture) string { return f.owner },
+			act: func(f *ownershipFixture, userID string) error {
+				return f.updateVendor(userID, models.UpdateVendorRequest{PaymentTerms: "Net 90"})
+			},
+			wantKind: apperror.KindForbidden,
+			wantCode: "payment_terms_forbidden",
+		},
+		{
+			name:   "admin sets bank account",
+			caller: func(f *ownershipFixture) string { return f.admin },
+			act: func(f *ownershipFixture, userID string) error {
+				return f.updateVendor(userID, models.UpdateVendorRequest{BankCode: "014", BankAccountNumber: "1234567890", BankAccountHolder: "PT Owner Supply"})
+			},
+		},
+		{
+			name:   "finance sets bank account",
+			caller: func(f *ownershipFixture) string { return f.finance },
+			act: func(f *ownershipFixture, userID string) error {
+				_, err := f.vendors.ChangeBankAccount(f.org.as(userID), f.vendor.ID, &models.VendorBankAccountRequest{
+					BankCode: "014", BankAccountNumber: "1234567890", BankAccountHolder: "PT Owner Supply",
+				})
+				return err
+			},
+		},
+		{
+			name:   "owner sets bank account",
+			caller: func(f *ownershipFixture) string { return f.owner },
+			act: func(f *ownershipFixture, userID string) error {
+				return f.updateVendor(userID, models.UpdateVendorRequest{BankCode: "014", BankAccountNumber: "9999999999", BankAccountHolder: "Someone Else"})
+			},
+			wantKind: apperror.KindForbidden,
+			wantCode: "bank_account_forbidden",
+		},
+		{
+			name:   "owner sets bank account through the finance route",
+			caller: func(f *ownershipFixture) string { return f.owner },
+			act: func(f *ownershipFixture, userID string) error {
+				_, err := f.vendors.ChangeBankAccount(f.org.as(userID), f.vendor.ID, &models.VendorBankAccountRequest{
+					BankCode: "014", BankAccountNumber: "9999999999", BankAccountHolder: "Someone Else",
+				})
+				return err
+			},
+			wantKind: apperror.KindForbidden,
+			wantCode: "bank_account_forbidden",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOwnershipFixture(t)
+			assertAppError(t, tt.act(f, tt.caller(f)), tt.wantKind, tt.wantCode)
+		})
+	}
+}
+
+func TestVendorTransferChangesOwner(t *testing.T) {
+	f := newOwnershipFixture(t)
+	if err := f.updateVendor(f.admin, models.UpdateVendorRequest{UserID: f.newOwner}); err != nil {
+		t.Fatalf("transfer vendor: %v", err)
+	}
+
+	// the new owner manages the vendor from now on, the previous owner no longer can
+	assertAppError(t, f.updateVendor(f.newOwner, models.UpdateVendorRequest{}), 0, "")
+	assertAppError(t, f.updateVendor(f.owner, models.UpdateVendorRequest{}), apperror.KindForbidden, "vendor_not_owned")
+}
+
+func TestVendorBankAccountVisibility(t *testing.T) {
+	f := newOwnershipFixture(t)
+	_, err := f.vendors.ChangeBankAccount(f.org.as(f.finance), f.vendor.ID, &models.VendorBankAccountRequest{
+		BankCode:          "014",
+		BankAccountNumber: "1234567890",
+		BankAccountHolder: "PT Owner Supply",
+	})
+	assertAppError(t, err, 0, "")
+
+	tests := []struct {
+		name     string
+		callerID string
+		wantBank bool
+	}{
+		{name: "owner", callerID: f.owner, wantBank: true},
+		{name: "admin", callerID: f.admin, wantBank: true},
+		{name: "finance", callerID: f.finance, wantBank: true},
+		{name: "another vendor user", callerID: f.other},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendor, err := f.vendors.GetVendorByID(f.org.as(tt.callerID), f.vendor.ID)
+			assertAppError(t, err, 0, "")
+			if got := vendor.BankAccountNumber != ""; got != tt.wantBank {
+				t.Fatalf("get shows the bank account: %v, want %v", got, tt.wantBank)
+			}
+
+			vendors, _, err := f.vendors.GetAllVendors(f.org.as(tt.callerID), 10, 1)
+			assertAppError(t, err, 0, "")
+			found := false
+			for _, listed := range vendors {
+				if listed.ID != f.vendor.ID {
+					continue
+				}
+				found = true
+				if got := listed.BankCode != "" || listed.BankAccountNumber != "" || listed.BankAccountHolder != ""; got != tt.wantBank {
+					t.Fatalf("list shows the bank account: %v, want %v", got, tt.wantBank)
+				}
+			}
+			if !found {
+				t.Fatal("vendor missing from the list")
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package usecases
+
+import "e-procurement/pkg/apperror"
+
+// pageOffset turns the 1-based page of a list request into a repository offset,
+// applying the defaults and the upper bound shared by the list endpoints
+func pageOffset(limit, page int) (int, int, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if limit > 100 {
+		return 0, 0, apperror.Validation("invalid_pagination", "limit must be between 1 and 100")
+	}
+	return limit, (page - 1) * limit, nil
+}
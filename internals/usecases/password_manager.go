@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	"e-procurement/pkg/encripted"
+	"e-procurement/pkg/password"
+	"errors"
+	"fmt"
+	"log"
+)
+
+var errPasswordReused = apperror.Validation("password_reused", "password was used recently, choose another one")
+
+// PasswordManager applies the password policy and hashes passwords. every place that sets a
+// password (register, profile update, change and reset) goes through Prepare and Remember,
+// or Set when both steps can run together.
+type PasswordManager struct {
+	userRepository repository.UserRepository
+	historyRepository repository.PasswordHistoryRepository
+	hasher *encripted.Encripted
+	policy password.Policy
+	// number of previous passwords that cannot be reused, the current one included
+	historySize int
+}
+
+func NewPasswordManager(
+	userRepo repository.UserRepository,
+	historyRepo repository.PasswordHistoryRepository,
+	hasher *encripted.Encripted,
+	policy password.Policy,
+	historySize int,
+	) *PasswordManager {
+	return &PasswordManager{
+		userRepository: userRepo,
+		historyRepository: historyRepo,
+		hasher: hasher,
+		policy: policy,
+		historySize: historySize,
+	}
+}
+
+// Prepare checks a new password against the policy and, for existing users, their recent
+// passwords, then returns its hash. userID is empty on registration.
+func (m *PasswordManager) Prepare(ctx context.Context, userID, plain string, userInputs ...string) (string, error) {
+	if err := m.policy.Validate(plain, userInputs...); err != nil {
+		var policyErr *password.PolicyError
+		if errors.As(err, &policyErr) {
+			return "", apperror.Validation("weak_password", policyErr.Error())
+		}
+		return "", err
+	}
+
+	if userID != "" && m.historySize > 0 {
+		hashes, err := m.historyRepository.Recent(ctx, userID, m.historySize)
+		if err != nil {
+			return "", fmt.Errorf("failed to get password history: %w", err)
+		}
+		for _, hash := range hashes {
+			reused, err := m.hasher.CheckPasswordHash(hash, plain)
+			if err != nil {
+				return "", fmt.Errorf("error checking password history: %w", err)
+			}
+			if reused {
+				return "", errPasswordReused
+			}
+		}
+	}
+
+	hashed, err := m.hasher.HashPassword(plain)
+	if err != nil {
+		return "", fmt.Errorf("error hashing password: %w", err)
+	}
+	return hashed, nil
+}
+
+// Remember adds the hash returned by Prepare to the history once the password is stored
+func (m *PasswordManager) Remember(ctx context.Context, userID, hashed string) error {
+	if m.historySize <= 0 {
+		return nil
+	}
+	if err := m.historyRepository.Add(ctx, userID, hashed, m.historySize); err != nil {
+		return fmt.Errorf("failed to store password history: %w", err)
+	}
+	return nil
+}
+
+// Set validates, hashes and stores a new password of an existing user
+func (m *PasswordManager) Set(ctx context.Context, userID, plain string, userInputs ...string) error {
+	hashed, err := m.Prepare(ctx, userID, plain, userInputs...)
+	if err != nil {
+		return err
+	}
+	if err := m.userRepository.UpdatePassword(ctx, userID, hashed); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return m.Remember(ctx, userID, hashed)
+}
+
+// Verify checks a password against the stored hash. hashes made with an older algorithm or
+// cost are replaced after a successful check, the password itself does not change so the
+// history is left alone. the replacement only applies while the stored hash is still the
+// verified one, a password changed or reset meanwhile wins.
+func (m *PasswordManager) Verify(ctx context.Context, userID, hashed, plain string) (bool, error) {
+	valid, err := m.hasher.CheckPasswordHash(hashed, plain)
+	if err != nil || !valid {
+		return false, err
+	}
+	if m.hasher.NeedsRehash(hashed) {
+		rehashed, err := m.hasher.HashPassword(plain)
+		if err == nil {
+			_, err = m.userRepository.ReplacePasswordHash(ctx, userID, hashed, rehashed)
+		}
+		if err != nil {
+			// the login still succeeds, the upgrade is retried on the next one
+			log.Printf("rehash password of user %s: %v", userID, err)
+		}
+	}
+	return true, nil
+}
+
+// Hash hashes a password without any policy check, used for values that are never accepted
+func (m *PasswordManager) Hash(plain string) (string, error) {
+	return m.hasher.HashPassword(plain)
+}
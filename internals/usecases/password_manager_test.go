@@ -0,0 +1,69 @@
+package usecases_test
+
+import (
+	"context"
+	"e-procurement/internals/repositories/memory"
+	"e-procurement/internals/usecases"
+	"e-procurement/pkg/encripted"
+	"e-procurement/pkg/password"
+	"e-procurement/pkg/rbac"
+	"strings"
+	"testing"
+)
+
+func TestPasswordManagerVerifyRehash(t *testing.T) {
+	legacy := encripted.NewEncriptedWithOptions(encripted.Options{Algorithm: encripted.AlgorithmBcrypt, BcryptCost: 4})
+	current := encripted.NewEncriptedWithOptions(encripted.Options{
+		Algorithm: encripted.AlgorithmArgon2id,
+		Argon2:    encripted.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1},
+	})
+	oldHash, err := legacy.HashPassword("Old-Password-1")
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	newHash, err := current.HashPassword("New-Password-2")
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		// hash stored when the rehash is written, the login verified against oldHash
+		stored   string
+		wantHash func(stored string) bool
+	}{
+		{
+			name:     "legacy hash is upgraded",
+			stored:   oldHash,
+			wantHash: func(stored string) bool { return strings.HasPrefix(stored, "$argon2id$") },
+		},
+		{
+			name:     "password changed during login is kept",
+			stored:   newHash,
+			wantHash: func(stored string) bool { return stored == newHash },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org := newTestOrg(t)
+			userID := org.addUser("user", rbac.RoleVendor)
+			users := memory.NewUserRepository(org.store)
+			if err := users.UpdatePassword(context.Background(), userID, tt.stored); err != nil {
+				t.Fatalf("store password: %v", err)
+			}
+			manager := usecases.NewPasswordManager(users, memory.NewPasswordHistoryRepository(org.store), current, password.Policy{}, 5)
+
+			valid, err := manager.Verify(context.Background(), userID, oldHash, "Old-Password-1")
+			if err != nil || !valid {
+				t.Fatalf("verify: valid=%v err=%v", valid, err)
+			}
+			user, err := users.GetUserByID(context.Background(), userID)
+			if err != nil {
+				t.Fatalf("get user: %v", err)
+			}
+			if !tt.wantHash(user.Password) {
+				t.Fatalf("unexpected stored hash %q", user.Password)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	"e-procurement/pkg/auth"
+	"e-procurement/pkg/mailer"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RateLimitScopePasswordReset limits forgot-password requests per email
+const RateLimitScopePasswordReset = "password_reset"
+
+var (
+	errInvalidResetToken = apperror.Validation("invalid_reset_token", "reset token is invalid or expired")
+	errTooManyResetRequests = apperror.TooManyRequests("too_many_requests", "too many password reset requests, please try again later")
+)
+
+type PasswordResetUseCase struct {
+	userRepository repository.UserRepository
+	tokenRepository repository.UserTokenRepository
+	revocations *TokenRevocationUseCase
+	limiter *RateLimiter
+	mailer mailer.Mailer
+	passwords *PasswordManager
+	resetURL string
+	tokenTTL time.Duration
+}
+
+func NewPasswordResetUseCase(
+	userRepo repository.UserRepository,
+	tokenRepo repository.UserTokenRepository,
+	revocations *TokenRevocationUseCase,
+	limiter *RateLimiter,
+	passwords *PasswordManager,
+	mail mailer.Mailer,
+	resetURL string,
+	tokenTTL time.Duration,
+	) *PasswordResetUseCase {
+	return &PasswordResetUseCase{
+		userRepository: userRepo,
+		tokenRepository: tokenRepo,
+		revocations: revocations,
+		limiter: limiter,
+		mailer: mail,
+		passwords: passwords,
+		resetURL: resetURL,
+		tokenTTL: tokenTTL,
+	}
+}
+
+// method to mail a password reset link. the response is the same whether the email
+// is registered or not, so it cannot be used to find accounts.
+func (u *PasswordResetUseCase) ForgotPassword(ctx context.Context, req *models.ForgotPasswordRequest) error {
+	// the limit applies to unknown emails too, otherwise a 429 would reveal the account exists
+	allowed, err := u.limiter.Allow(ctx, normalizeEmail(req.Email))
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errTooManyResetRequests
+	}
+
+	// Authenticate looks the user up by email
+	user, err := u.userRepository.Authenticate(ctx, req.Email)
+	if err != nil {
+		return fmt.Errorf("failed to get user by email: %w", err)
+	}
+	if user == nil {
+		return nil
+	}
+
+	// only the latest link works
+	if err := u.tokenRepository.InvalidateForUser(ctx, user.ID, models.UserTokenPurposePasswordReset); err != nil {
+		return fmt.Errorf("failed to invalidate reset tokens: %w", err)
+	}
+	token, tokenHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("error generating reset token: %w", err)
+	}
+	_, err = u.tokenRepository.Create(ctx, &models.UserToken{
+		UserID:    user.ID,
+		Purpose:   models.UserTokenPurposePasswordReset,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(u.tokenTTL),
+	})
+	if err != nil {
+		return fmt.Errorf("error storing reset token: %w", err)
+	}
+
+	err = u.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your e-procurement password",
+		Body: fmt.Sprintf("Hello %s,\n\nOpen the link below to choose a new password. It expires in %s.\n\n%s\n\n"+
+			"If you did not ask for a password reset you can ignore this email.",
+			user.UserName, u.tokenTTL, tokenLink(u.resetURL, token)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send reset email: %w", err)
+	}
+	return nil
+}
+
+// tokenLink appends a mailed token to the configured link as the token query parameter
+func tokenLink(baseURL, token string) string {
+	separator := "?"
+	if strings.Contains(baseURL, "?") {
+		separator = "&"
+	}
+	return baseURL + separator + "token=" + url.QueryEscape(token)
+}
+
+// method to set a new password with a token from the reset email. the token is consumed
+// and every session of the user is revoked.
+func (u *PasswordResetUseCase) ResetPassword(ctx context.Context, req *models.ResetPasswordRequest) error {
+	stored, err := u.tokenRepository.GetByHash(ctx, models.UserTokenPurposePasswordReset, auth.HashOpaqueToken(req.Token))
+	if err != nil {
+		return fmt.Errorf("failed to get reset token: %w", err)
+	}
+	if stored == nil || stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return errInvalidResetToken
+	}
+	user, err := u.userRepository.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user by ID: %w", err)
+	}
+	if user == nil {
+		return errInvalidResetToken
+	}
+	// check the policy before consuming, a rejected password must not burn the token
+	hashedPassword, err := u.passwords.Prepare(ctx, user.ID, req.NewPassword, user.UserName, user.Email)
+	if err != nil {
+		return err
+	}
+	// consume first so the same token cannot reset the password twice
+	used, err := u.tokenRepository.MarkUsed(ctx, stored.ID)
+	if err != nil {
+		return fmt.Errorf("failed to consume reset token: %w", err)
+	}
+	if !used {
+		return errInvalidResetToken
+	}
+
+	if err := u.userRepository.UpdatePassword(ctx, stored.UserID, hashedPassword); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	if err := u.passwords.Remember(ctx, stored.UserID, hashedPassword); err != nil {
+		return err
+	}
+
+	return u.revocations.RevokeAllForUser(ctx, stored.UserID)
+}
@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/pkg/apperror"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// paymentTermsPattern accepts "Net 30" and early payment discount terms such as "2/10 Net 30"
+var paymentTermsPattern = regexp.MustCompile(`^(?:(\d{1,2}(?:\.\d{1,2})?)\s*/\s*(\d{1,3})\s+)?net\s*(\d{1,3})$`)
+
+// ParsePaymentTerms reads payment terms written as "Net 30" (pay within 30 days of the invoice date) or
+// "2/10 Net 30" (2% discount when paid within 10 days, otherwise within 30 days), case insensitive
+func ParsePaymentTerms(terms string) (*models.PaymentTerms, error) {
+	invalid := apperror.Validation("invalid_payment_terms",
+		fmt.Sprintf("payment terms %q must be written as \"Net 30\" or \"2/10 Net 30\"", terms))
+
+	match := paymentTermsPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(terms)))
+	if match == nil {
+		return nil, invalid
+	}
+	parsed := &models.PaymentTerms{}
+	parsed.NetDays, _ = strconv.Atoi(match[3])
+	if match[1] != "" {
+		parsed.DiscountRate, _ = strconv.ParseFloat(match[1], 64)
+		parsed.DiscountDays, _ = strconv.Atoi(match[2])
+		// a discount window as long as the net term would discount every payment
+		if parsed.DiscountRate <= 0 || parsed.DiscountDays >= parsed.NetDays {
+			return nil, invalid
+		}
+	}
+	return parsed, nil
+}
+
+// formatPaymentTerms writes the terms the way they are stored, "Net 30" or "2/10 Net 30"
+func formatPaymentTerms(terms *models.PaymentTerms) string {
+	if terms.DiscountRate == 0 {
+		return fmt.Sprintf("Net %d", terms.NetDays)
+	}
+	return fmt.Sprintf("%s/%d Net %d", strconv.FormatFloat(terms.DiscountRate, 'f', -1, 64), terms.DiscountDays, terms.NetDays)
+}
+
+// normalizePaymentTerms checks optional terms and returns them in their stored form, empty stays empty
+func normalizePaymentTerms(terms string) (string, error) {
+	if strings.TrimSpace(terms) == "" {
+		return "", nil
+	}
+	parsed, err := ParsePaymentTerms(terms)
+	if err != nil {
+		return "", err
+	}
+	return formatPaymentTerms(parsed), nil
+}
+
+// scheduleInvoice sets the due date of an invoice from its invoice date and the terms, with the
+// discount deadline and discount amount when the terms give an early payment discount
+func scheduleInvoice(invoice *models.Invoice, terms *models.PaymentTerms) {
+	dueDate := invoice.InvoiceDate.AddDate(0, 0, terms.NetDays)
+	invoice.PaymentTerms = formatPaymentTerms(terms)
+	invoice.DueDate = &dueDate
+	invoice.DiscountDate = nil
+	invoice.DiscountAmount = 0
+	if terms.DiscountRate > 0 {
+		discountDate := invoice.InvoiceDate.AddDate(0, 0, terms.DiscountDays)
+		invoice.DiscountDate = &discountDate
+		invoice.DiscountAmount = round2(invoice.TotalAmount * terms.DiscountRate / 100)
+	}
+}
+
+// discountAvailable reports whether paying the invoice on the date still earns its early payment discount
+func discountAvailable(invoice *models.Invoice, paymentDate time.Time) bool {
+	return invoice.DiscountDate != nil && invoice.DiscountAmount > 0 && !paymentDate.After(*invoice.DiscountDate)
+}
@@ -0,0 +1,383 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	"e-procurement/pkg/bankfile"
+	customContext "e-procurement/pkg/context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+var (
+	errPaymentBatchClosed        = apperror.Conflict("payment_batch_closed", "paid or cancelled payment batches cannot be changed")
+	errPaymentBatchStatusChanged = apperror.Conflict("payment_batch_status_changed", "the payment batch status has changed, reload it and try again")
+)
+
+// PaymentUseCase schedules approved invoices for payment. Finance collects them in payment batches,
+// taking early payment discounts that are still available on the payment date, exports each batch
+// as a bank file and confirms it once the bank paid. Vendors see paid batches as remittance advice.
+type PaymentUseCase struct {
+	paymentBatchRepository repository.PaymentBatchRepository
+	invoices               *InvoiceUseCase
+	exporter               bankfile.Exporter
+}
+
+func NewPaymentUseCase(paymentBatchRepo repository.PaymentBatchRepository, invoices *InvoiceUseCase, exporter bankfile.Exporter) *PaymentUseCase {
+	return &PaymentUseCase{
+		paymentBatchRepository: paymentBatchRepo,
+		invoices:               invoices,
+		exporter:               exporter,
+	}
+}
+
+// Schedule returns a page of the approved invoices waiting for payment, earliest due date first,
+// with the discount still available and the amount to pay on the as-of date of the filter, today when empty
+func (u *PaymentUseCase) Schedule(ctx context.Context, filter models.PaymentScheduleFilter, limit, page int) ([]*models.PaymentScheduleEntry, int, error) {
+	if filter.AsOf.IsZero() {
+		filter.AsOf = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+	invoices, count, err := u.invoices.List(ctx, models.InvoiceFilter{
+		Status:    models.InvoiceStatusApproved,
+		VendorID:  filter.VendorID,
+		DueBy:     filter.DueBy,
+		ByDueDate: true,
+	}, limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	entries := make([]*models.PaymentScheduleEntry, 0, len(invoices))
+	for _, invoice := range invoices {
+		entry := &models.PaymentScheduleEntry{
+			InvoiceID:           invoice.ID,
+			InvoiceNumber:       invoice.InvoiceNumber,
+			InvoiceDate:         invoice.InvoiceDate,
+			VendorID:            invoice.VendorID,
+			VendorName:          invoice.VendorName,
+			PurchaseOrderID:     invoice.PurchaseOrderID,
+			PurchaseOrderNumber: invoice.PurchaseOrderNumber,
+			PaymentTerms:        invoice.PaymentTerms,
+			TotalAmount:         invoice.TotalAmount,
+			DueDate:             invoice.DueDate,
+			DiscountDate:        invoice.DiscountDate,
+			DiscountAmount:      invoice.DiscountAmount,
+			AsOf:                filter.AsOf,
+			AmountPayable:       invoice.TotalAmount,
+		}
+		if discountAvailable(invoice, filter.AsOf) {
+			entry.DiscountAvailable = true
+			entry.AmountPayable = round2(invoice.TotalAmount - invoice.DiscountAmount)
+		}
+		if invoice.DueDate != nil {
+			entry.DaysUntilDue = int(math.Round(invoice.DueDate.Sub(filter.AsOf).Hours() / 24))
+			entry.Overdue = filter.AsOf.After(*invoice.DueDate)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, count, nil
+}
+
+// CreateBatch collects approved invoices in a draft payment batch paid on the payment date. The early
+// payment discount of an invoice is taken when its discount date is not past on the payment date
+func (u *PaymentUseCase) CreateBatch(ctx context.Context, req *models.PaymentBatchRequest) (*models.PaymentBatch, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	paymentDate, err := time.Parse(time.DateOnly, req.PaymentDate)
+	if err != nil {
+		return nil, apperror.Validation("invalid_payment_date", "payment_date must be formatted as YYYY-MM-DD")
+	}
+	if paymentDate.Before(time.Now().UTC().Truncate(24 * time.Hour)) {
+		return nil, apperror.Validation("invalid_payment_date", "payment_date cannot be in the past")
+	}
+
+	batch := &models.PaymentBatch{
+		Status:      models.PaymentBatchStatusDraft,
+		PaymentDate: paymentDate,
+		Notes:       req.Notes,
+		CreatedBy:   userID,
+	}
+	seen := map[string]bool{}
+	for _, invoiceID := range req.InvoiceIDs {
+		if seen[invoiceID] {
+			return nil, apperror.Validation("duplicate_invoice", fmt.Sprintf("invoice %s is listed more than once", invoiceID))
+		}
+		seen[invoiceID] = true
+
+		invoice, err := u.invoices.Get(ctx, invoiceID)
+		if err != nil {
+			return nil, err
+		}
+		if invoice.Status != models.InvoiceStatusApproved {
+			return nil, apperror.Conflict("invoice_not_payable",
+				fmt.Sprintf("invoice %s is %s, only approved invoices can be paid", invoice.InvoiceNumber, invoice.Status))
+		}
+		item := &models.PaymentBatchItem{
+			InvoiceID:     invoice.ID,
+			VendorID:      invoice.VendorID,
+			InvoiceAmount: invoice.TotalAmount,
+		}
+		if discountAvailable(invoice, paymentDate) {
+			item.DiscountAmount = invoice.DiscountAmount
+		}
+		item.PaymentAmount = round2(item.InvoiceAmount - item.DiscountAmount)
+		batch.TotalInvoiced += item.InvoiceAmount
+		batch.TotalDiscount += item.DiscountAmount
+		batch.Items = append(batch.Items, item)
+	}
+	batch.InvoiceCount = len(batch.Items)
+	batch.TotalInvoiced = round2(batch.TotalInvoiced)
+	batch.TotalDiscount = round2(batch.TotalDiscount)
+	batch.TotalAmount = round2(batch.TotalInvoiced - batch.TotalDiscount)
+
+	created, err := u.paymentBatchRepository.Create(ctx, batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment batch: %w", err)
+	}
+	if !created {
+		return nil, errInvoiceStatusChanged
+	}
+	return u.GetBatch(ctx, batch.ID)
+}
+
+// GetBatch returns a payment batch of the organization with its items
+func (u *PaymentUseCase) GetBatch(ctx context.Context, id string) (*models.PaymentBatch, error) {
+	batch, err := u.paymentBatchRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment batch: %w", err)
+	}
+	if batch == nil {
+		return nil, apperror.NotFound("payment_batch_not_found", fmt.Sprintf("payment batch with ID %s not found", id))
+	}
+	return batch, nil
+}
+
+// ListBatches returns a page of payment batches, newest first, with the total count
+func (u *PaymentUseCase) ListBatches(ctx context.Context, filter models.PaymentBatchFilter, limit, page int) ([]*models.PaymentBatch, int, error) {
+	limit, offset, err := pageOffset(limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	batches, count, err := u.paymentBatchRepository.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list payment batches: %w", err)
+	}
+	return batches, count, nil
+}
+
+// ExportBatch writes the bank file of a draft batch with one payment per vendor. An exported batch
+// can be exported again, for instance when the file was lost, the batch keeps the latest file
+func (u *PaymentUseCase) ExportBatch(ctx context.Context, id string) (*models.PaymentBatch, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	batch, err := u.GetBatch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status != models.PaymentBatchStatusDraft && batch.Status != models.PaymentBatchStatusExported {
+		return nil, errPaymentBatchClosed
+	}
+
+	vendors, err := u.payees(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	file, err := u.exporter.Export(ctx, bankBatch(batch, vendors))
+	if err != nil {
+		return nil, fmt.Errorf("failed to export payment batch: %w", err)
+	}
+	now := time.Now()
+	return u.transition(ctx, batch, &models.PaymentBatchTransition{
+		Status:     models.PaymentBatchStatusExported,
+		ExportFile: &file,
+		ExportedAt: &now,
+		ExportedBy: &userID,
+	})
+}
+
+// ConfirmBatch records that the bank paid an exported batch with the bank reference,
+// the invoices of the batch become paid
+func (u *PaymentUseCase) ConfirmBatch(ctx context.Context, id string, req *models.PaymentBatchConfirmRequest) (*models.PaymentBatch, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	batch, err := u.GetBatch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch batch.Status {
+	case models.PaymentBatchStatusExported:
+	case models.PaymentBatchStatusDraft:
+		return nil, apperror.Conflict("payment_batch_not_exported", "the payment batch must be exported before it is confirmed")
+	default:
+		return nil, errPaymentBatchClosed
+	}
+
+	now := time.Now()
+	return u.transition(ctx, batch, &models.PaymentBatchTransition{
+		Status:           models.PaymentBatchStatusPaid,
+		PaymentReference: &req.Reference,
+		PaidAt:           &now,
+		PaidBy:           &userID,
+		InvoiceStatus:    models.InvoiceStatusPaid,
+	})
+}
+
+// CancelBatch drops a batch that was not paid, its invoices are approved again and can be batched anew
+func (u *PaymentUseCase) CancelBatch(ctx context.Context, id string) (*models.PaymentBatch, error) {
+	batch, err := u.GetBatch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status != models.PaymentBatchStatusDraft && batch.Status != models.PaymentBatchStatusExported {
+		return nil, errPaymentBatchClosed
+	}
+
+	now := time.Now()
+	return u.transition(ctx, batch, &models.PaymentBatchTransition{
+		Status:        models.PaymentBatchStatusCancelled,
+		CancelledAt:   &now,
+		InvoiceStatus: models.InvoiceStatusApproved,
+	})
+}
+
+// VendorRemittances returns a page of the remittance advice of the caller's vendor, one per paid batch, newest first
+func (u *PaymentUseCase) VendorRemittances(ctx context.Context, limit, page int) ([]*models.Remittance, int, error) {
+	vendor, err := u.invoices.callerVendor(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	batches, count, err := u.ListBatches(ctx, models.PaymentBatchFilter{Status: models.PaymentBatchStatusPaid, VendorID: vendor.ID}, limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	remittances := make([]*models.Remittance, 0, len(batches))
+	for _, header := range batches {
+		batch, err := u.GetBatch(ctx, header.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		remittances = append(remittances, remittance(batch, vendor))
+	}
+	return remittances, count, nil
+}
+
+// VendorRemittance returns the remittance advice of a paid batch for the caller's vendor, batches that
+// are not paid or do not pay the vendor are reported as not found
+func (u *PaymentUseCase) VendorRemittance(ctx context.Context, batchID string) (*models.Remittance, error) {
+	vendor, err := u.invoices.callerVendor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	batch, err := u.paymentBatchRepository.GetByID(ctx, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment batch: %w", err)
+	}
+	if batch == nil || batch.Status != models.PaymentBatchStatusPaid {
+		return nil, apperror.NotFound("remittance_not_found", fmt.Sprintf("remittance advice %s not found", batchID))
+	}
+	advice := remittance(batch, vendor)
+	if len(advice.Items) == 0 {
+		return nil, apperror.NotFound("remittance_not_found", fmt.Sprintf("remittance advice %s not found", batchID))
+	}
+	return advice, nil
+}
+
+// payees loads the vendors the batch pays by their ID, refusing the export when one of them has no bank
+// account the bank could credit
+func (u *PaymentUseCase) payees(ctx context.Context, batch *models.PaymentBatch) (map[string]*models.Vendor, error) {
+	vendors := map[string]*models.Vendor{}
+	var missing []string
+	for _, item := range batch.Items {
+		if _, ok := vendors[item.VendorID]; ok {
+			continue
+		}
+		vendor, err := u.invoices.vendorRepository.GetVendorByID(ctx, item.VendorID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get vendor: %w", err)
+		}
+		vendors[item.VendorID] = vendor
+		if vendor.BankCode == "" || vendor.BankAccountNumber == "" || vendor.BankAccountHolder == "" {
+			missing = append(missing, vendor.VendorName)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, apperror.Validation("vendor_bank_details_missing",
+			fmt.Sprintf("vendors without bank account cannot be paid by bank file: %s", strings.Join(missing, ", ")))
+	}
+	return vendors, nil
+}
+
+// transition moves the batch out of its current status, a concurrent change is reported as a conflict
+func (u *PaymentUseCase) transition(ctx context.Context, batch *models.PaymentBatch, transition *models.PaymentBatchTransition) (*models.PaymentBatch, error) {
+	updated, err := u.paymentBatchRepository.Transition(ctx, batch.ID, batch.Status, transition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update payment batch: %w", err)
+	}
+	if !updated {
+		return nil, errPaymentBatchStatusChanged
+	}
+	return u.GetBatch(ctx, batch.ID)
+}
+
+// bankBatch turns a payment batch into the bank file batch, paying every vendor once for all its invoices
+func bankBatch(batch *models.PaymentBatch, vendors map[string]*models.Vendor) bankfile.Batch {
+	export := bankfile.Batch{Reference: batch.Number, PaymentDate: batch.PaymentDate}
+	payments := map[string]int{}
+	invoiceNumbers := map[string][]string{}
+	for _, item := range batch.Items {
+		i, ok := payments[item.VendorID]
+		if !ok {
+			i = len(export.Payments)
+			payments[item.VendorID] = i
+			vendor := vendors[item.VendorID]
+			export.Payments = append(export.Payments, bankfile.Payment{
+				PayeeID:       item.VendorID,
+				PayeeName:     item.VendorName,
+				BankCode:      vendor.BankCode,
+				AccountNumber: vendor.BankAccountNumber,
+				AccountHolder: vendor.BankAccountHolder,
+			})
+		}
+		export.Payments[i].Amount = round2(export.Payments[i].Amount + item.PaymentAmount)
+		invoiceNumbers[item.VendorID] = append(invoiceNumbers[item.VendorID], item.InvoiceNumber)
+	}
+	for i, payment := range export.Payments {
+		export.Payments[i].Description = fmt.Sprintf("%s %s", batch.Number, strings.Join(invoiceNumbers[payment.PayeeID], " "))
+	}
+	return export
+}
+
+// remittance returns the part of a paid batch that pays the vendor
+func remittance(batch *models.PaymentBatch, vendor *models.Vendor) *models.Remittance {
+	advice := &models.Remittance{
+		PaymentBatchID:   batch.ID,
+		Number:           batch.Number,
+		PaymentDate:      batch.PaymentDate,
+		PaidAt:           batch.PaidAt,
+		PaymentReference: batch.PaymentReference,
+		VendorID:         vendor.ID,
+		VendorName:       vendor.VendorName,
+		Items:            []*models.PaymentBatchItem{},
+	}
+	for _, item := range batch.Items {
+		if item.VendorID != vendor.ID {
+			continue
+		}
+		advice.TotalInvoiced += item.InvoiceAmount
+		advice.TotalDiscount += item.DiscountAmount
+		advice.Items = append(advice.Items, item)
+	}
+	advice.TotalInvoiced = round2(advice.TotalInvoiced)
+	advice.TotalDiscount = round2(advice.TotalDiscount)
+	advice.TotalAmount = round2(advice.TotalInvoiced - advice.TotalDiscount)
+	return advice
+}
@@ -0,0 +1,79 @@
+package usecases_test
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/repositories/memory"
+	"e-procurement/internals/usecases"
+	"e-procurement/pkg/apperror"
+	"e-procurement/pkg/bankfile"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newPaymentBatch approves a matched invoice of the invoice fixture and puts it in a draft payment batch
+func newPaymentBatch(t *testing.T, f *invoiceFixture, payments *usecases.PaymentUseCase) *models.PaymentBatch {
+	t.Helper()
+	f.receive(t, 10)
+	invoice := f.mustSubmit(t, "INV-1", 10, models.MatchStatusMatched)
+	if _, err := f.invoices.Approve(f.org.as(f.finance), invoice.ID, &models.InvoiceApproveRequest{}); err != nil {
+		t.Fatalf("approve invoice: %v", err)
+	}
+	batch, err := payments.CreateBatch(f.org.as(f.finance), &models.PaymentBatchRequest{
+		PaymentDate: time.Now().UTC().Format(time.DateOnly),
+		InvoiceIDs:  []string{invoice.ID},
+	})
+	if err != nil {
+		t.Fatalf("create payment batch: %v", err)
+	}
+	return batch
+}
+
+func TestPaymentExportPaysVendorBankAccount(t *testing.T) {
+	f := newInvoiceFixture(t)
+	dir := t.TempDir()
+	exporter, err := bankfile.NewFileExporter(dir)
+	if err != nil {
+		t.Fatalf("create exporter: %v", err)
+	}
+	payments := usecases.NewPaymentUseCase(memory.NewPaymentBatchRepository(f.org.store), f.invoices, exporter)
+	batch := newPaymentBatch(t, f, payments)
+
+	_, err = payments.ExportBatch(f.org.as(f.finance), batch.ID)
+	assertAppError(t, err, apperror.KindValidation, "vendor_bank_details_missing")
+
+	// the account holder is typed in by the vendor, a spreadsheet must not run it as a formula
+	changed, err := memory.NewVendorRepository(f.org.store).ChangeBankAccount(f.org.as(f.finance), &models.VendorBankAccountChange{
+		VendorID:          f.order.VendorID,
+		BankCode:          "014",
+		BankAccountNumber: "1234567890",
+		BankAccountHolder: "=HYPERLINK(\"http://example.com\")",
+	})
+	if err != nil || !changed {
+		t.Fatalf("change bank account: %v, %v", changed, err)
+	}
+	exported, err := payments.ExportBatch(f.org.as(f.finance), batch.ID)
+	assertAppError(t, err, 0, "")
+
+	file, err := os.Open(filepath.Join(dir, exported.ExportFile))
+	if err != nil {
+		t.Fatalf("open bank file: %v", err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil || len(records) != 2 {
+		t.Fatalf("bank file has %d records, %v", len(records), err)
+	}
+	row := map[string]string{}
+	for i, column := range records[0] {
+		row[column] = records[1][i]
+	}
+	if row["payee_bank_code"] != "014" || row["payee_account_number"] != "1234567890" {
+		t.Fatalf("payee account is %s %s, want 014 1234567890", row["payee_bank_code"], row["payee_account_number"])
+	}
+	if want := "'=HYPERLINK(\"http://example.com\")"; row["payee_account_holder"] != want {
+		t.Fatalf("payee account holder is %s, want %s", row["payee_account_holder"], want)
+	}
+}
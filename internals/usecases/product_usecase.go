@@ -3,43 +3,34 @@ package usecases
 import (
 	"context"
 	"e-procurement/internals/domain/models"
-	"e-procurement/internals/repositories"
-	customContext "e-procurement/pkg/context"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
 	"fmt"
 )
 
 type ProductUseCase struct {
-	productRepository *repositories.ProductRepository
-	vendorRepository *repositories.VendorRepository
-
+	productRepository repository.ProductRepository
+	vendorRepository repository.VendorRepository
+	policy *OwnershipPolicy
 }
 // NewProductUsecase instence 
-func NewProductUsecase(productRepo *repositories.ProductRepository, vendor *repositories.VendorRepository ) *ProductUseCase {
+func NewProductUsecase(productRepo repository.ProductRepository, vendor repository.VendorRepository ) *ProductUseCase {
 	return &ProductUseCase{
 		productRepository: productRepo,
 		vendorRepository: vendor,
+		policy: NewOwnershipPolicy(vendor),
 	}
 }
 
 // Method for creted new product
 func(u *ProductUseCase) CreateProducUsecase(ctx context.Context, productReq *models.CreateProductRequest)(*models.CreateProductResponse, error){
-	userID,err := customContext.GetUserIDFromContext(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user ID from context: %w", err)
-	}
-
-	userVendor,err := u.vendorRepository.GetVendorByUserID(ctx, userID)
-
+	vendor, err := u.vendorRepository.GetVendorByID(ctx, productReq.VendorID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get vendor by user ID: %w", err)
+		return nil, fmt.Errorf("failed to get vendor: %w", err)
 	}
-	
-	if userVendor == nil {
-		return nil, fmt.Errorf("vendor with user ID %s does not exist", userID)
-	}
-	// validate vendor ID and vendor body is user vendor
-	if productReq.VendorID != userVendor.ID {
-		return nil, fmt.Errorf("vendor ID Canot create product for other vendor")
+	// validate vendor in body belongs to the user, admins can create for any vendor
+	if err := u.policy.CanManageVendor(ctx, vendor); err != nil {
+		return nil, err
 	}
 	product, err := u.productRepository.CreateProduct(ctx, productReq)
 	if err != nil {
@@ -69,7 +60,7 @@ func(u *ProductUseCase) GetAllProducts(ctx context.Context, limit, page int) ([]
 	}
 	offset := (page - 1) * limit
 	if limit > 100  || page > 100 {
-		return nil, 0, fmt.Errorf("limit and offset must be between 1 and 100")
+		return nil, 0, apperror.Validation("invalid_pagination", "limit and offset must be between 1 and 100")
 	}
 	// Query total count
 	count, err := u.productRepository.CountProducts(ctx)
@@ -149,7 +140,7 @@ func(u *ProductUseCase) GetProductByID(ctx context.Context, id string) (*models.
 		return nil, fmt.Errorf("failed to get product by ID: %w", err)
 	}
 	if product == nil {
-		return nil, nil // Product not found
+		return nil, apperror.NotFound("product_not_found", fmt.Sprintf("product with ID %s not found", id))
 	}
 	productResponse := &models.ResponseProduct{
 		ID:                 	product.ID,
@@ -174,7 +165,10 @@ func(u *ProductUseCase) UpdateProduct(ctx context.Context, id string, productReq
 		return nil, fmt.Errorf("failed to get product by ID: %w", err)
 	}
 	if existingProduct == nil {
-		return nil, fmt.Errorf("product with ID %s not found", id)
+		return nil, apperror.NotFound("product_not_found", fmt.Sprintf("product with ID %s not found", id))
+	}
+	if err := u.policy.CanManageProduct(ctx, existingProduct); err != nil {
+		return nil, err
 	}
 	
 	if productReq.ProductName == "" {
@@ -217,7 +211,10 @@ func(u *ProductUseCase) DeleteProduct(ctx context.Context, id string) error {
 		return fmt.Errorf("failed to get product by ID: %w", err)
 	}
 	if existingProduct == nil {
-		return fmt.Errorf("product with ID %s not found", id)
+		return apperror.NotFound("product_not_found", fmt.Sprintf("product with ID %s not found", id))
+	}
+	if err := u.policy.CanManageProduct(ctx, existingProduct); err != nil {
+		return err
 	}
 
 	// Delete product
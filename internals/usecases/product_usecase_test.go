@@ -0,0 +1,49 @@
+package usecases_test
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/repositories/memory"
+	"e-procurement/internals/usecases"
+	"e-procurement/pkg/apperror"
+	"e-procurement/pkg/rbac"
+	"testing"
+)
+
+func TestCreateProducUsecaseVendorOwnership(t *testing.T) {
+	org := newTestOrg(t)
+	admin := org.addUser("admin", rbac.RoleAdmin)
+	owner := org.addUser("owner", rbac.RoleVendor)
+	other := org.addUser("other", rbac.RoleVendor)
+	noProfile := org.addUser("noprofile", rbac.RoleVendor)
+	ownVendor := org.addVendor(owner, "Owner Supply")
+	org.addVendor(other, "Other Supply")
+	category := org.addCategory(admin, "Hardware")
+	products := usecases.NewProductUsecase(memory.NewProductRepository(org.store), memory.NewVendorRepository(org.store))
+
+	tests := []struct {
+		name     string
+		userID   string
+		wantKind apperror.Kind
+		wantCode string
+	}{
+		{name: "owner creates for own vendor", userID: owner},
+		{name: "admin creates for any vendor", userID: admin},
+		{name: "vendor creates for another vendor", userID: other, wantKind: apperror.KindForbidden, wantCode: "vendor_not_owned"},
+		{name: "user without vendor profile", userID: noProfile, wantKind: apperror.KindForbidden, wantCode: "vendor_not_owned"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product, err := products.CreateProducUsecase(org.as(tt.userID), &models.CreateProductRequest{
+				ProductName:        "Bolt",
+				ProductPrice:       1000,
+				ProductDescription: "M8 bolt",
+				ProductCategoryID:  category.ID,
+				VendorID:           ownVendor.ID,
+			})
+			assertAppError(t, err, tt.wantKind, tt.wantCode)
+			if err == nil && product.VendorID != ownVendor.ID {
+				t.Fatalf("product created for vendor %s, want %s", product.VendorID, ownVendor.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,652 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	customContext "e-procurement/pkg/context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// maxAwardOrders bounds the orders listed for one award, an award has at most one order
+// per winning vendor and never more vendors than RFQ lines
+const maxAwardOrders = 200
+
+const defaultPurchaseOrderUnit = "pcs"
+
+var (
+	errPurchaseOrderNotEditable  = apperror.Conflict("purchase_order_not_editable", "only draft purchase orders can be changed")
+	errPurchaseOrderStatusChange = apperror.Conflict("purchase_order_status_changed", "the purchase order status has changed, reload it and try again")
+)
+
+// PurchaseOrderUseCase manages purchase orders from draft through approval, dispatch to the
+// vendor and the vendor's response. Orders are raised directly or drafted from RFQ awards,
+// approved orders are changed through change orders that keep the earlier revisions.
+type PurchaseOrderUseCase struct {
+	purchaseOrderRepository repository.PurchaseOrderRepository
+	productRepository       repository.ProductRepository
+	vendorRepository        repository.VendorRepository
+	organizationRepository  repository.OrganizationRepository
+	approvals               *ApprovalUseCase
+}
+
+var _ ApprovalHandler = (*PurchaseOrderUseCase)(nil)
+
+func NewPurchaseOrderUseCase(purchaseOrderRepo repository.PurchaseOrderRepository, productRepo repository.ProductRepository, vendorRepo repository.VendorRepository, organizationRepo repository.OrganizationRepository, approvals *ApprovalUseCase) *PurchaseOrderUseCase {
+	return &PurchaseOrderUseCase{
+		purchaseOrderRepository: purchaseOrderRepo,
+		productRepository:       productRepo,
+		vendorRepository:        vendorRepo,
+		organizationRepository:  organizationRepo,
+		approvals:               approvals,
+	}
+}
+
+// Create stores a new draft order to a vendor of the organization, raised by the caller
+func (u *PurchaseOrderUseCase) Create(ctx context.Context, req *models.PurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the vendor repositories report a missing vendor as a not found error
+	vendor, err := u.vendorRepository.GetVendorByID(ctx, req.VendorID)
+	if err != nil && !apperror.IsKind(err, apperror.KindNotFound) {
+		return nil, fmt.Errorf("failed to get vendor: %w", err)
+	}
+	if vendor == nil {
+		return nil, apperror.Validation("unknown_vendor", fmt.Sprintf("vendor with ID %s not found", req.VendorID))
+	}
+	if err := requireApproved(vendor); err != nil {
+		return nil, err
+	}
+	order, err := u.build(ctx, &req.PurchaseOrderDetails)
+	if err != nil {
+		return nil, err
+	}
+	order.VendorID = req.VendorID
+	// orders without their own terms follow the terms agreed with the vendor
+	if order.PaymentTerms == "" {
+		order.PaymentTerms = vendor.PaymentTerms
+	}
+	order.Status = models.PurchaseOrderStatusDraft
+	order.CreatedBy = userID
+	return u.purchaseOrderRepository.Create(ctx, order)
+}
+
+// Get returns a purchase order of the organization with its lines
+func (u *PurchaseOrderUseCase) Get(ctx context.Context, id string) (*models.PurchaseOrder, error) {
+	order, err := u.purchaseOrderRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase order: %w", err)
+	}
+	if order == nil {
+		return nil, apperror.NotFound("purchase_order_not_found", fmt.Sprintf("purchase order with ID %s not found", id))
+	}
+	return order, nil
+}
+
+// List returns a page of purchase orders, newest first, with the total count
+func (u *PurchaseOrderUseCase) List(ctx context.Context, filter models.PurchaseOrderFilter, limit, page int) ([]*models.PurchaseOrder, int, error) {
+	limit, offset, err := pageOffset(limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	orders, count, err := u.purchaseOrderRepository.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list purchase orders: %w", err)
+	}
+	return orders, count, nil
+}
+
+// ListByAward returns the orders drafted from an award
+func (u *PurchaseOrderUseCase) ListByAward(ctx context.Context, awardID string) ([]*models.PurchaseOrder, error) {
+	orders, _, err := u.purchaseOrderRepository.List(ctx, models.PurchaseOrderFilter{AwardID: awardID}, maxAwardOrders, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list purchase orders: %w", err)
+	}
+	return orders, nil
+}
+
+// Update replaces the header and lines of a draft order, the vendor stays the same
+func (u *PurchaseOrderUseCase) Update(ctx context.Context, id string, req *models.PurchaseOrderDetails) (*models.PurchaseOrder, error) {
+	existing, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status != models.PurchaseOrderStatusDraft {
+		return nil, errPurchaseOrderNotEditable
+	}
+	order, err := u.build(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	order.ID = id
+	updated, err := u.purchaseOrderRepository.UpdateDraft(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errPurchaseOrderNotEditable
+	}
+	return u.Get(ctx, id)
+}
+
+// Submit sends a draft order for approval, the approvers follow from the approval rules
+// matching its total and the categories of its catalog products
+func (u *PurchaseOrderUseCase) Submit(ctx context.Context, id string) (*models.PurchaseOrder, error) {
+	existing, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status != models.PurchaseOrderStatusDraft {
+		return nil, apperror.Conflict("purchase_order_not_draft", "only draft purchase orders can be submitted")
+	}
+	// orders drafted from an award have no ship-to address until the buyer fills it in
+	if existing.ShipTo == "" {
+		return nil, apperror.Validation("ship_to_required", "the purchase order needs a ship-to address before it is submitted")
+	}
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := u.approvalSubject(ctx, existing, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	submitted, err := u.transition(ctx, id, existing.Status, &models.PurchaseOrderTransition{
+		Status:      models.PurchaseOrderStatusSubmitted,
+		SubmittedAt: &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.approvals.Start(ctx, subject); err != nil {
+		// put the order back so it can be submitted again once the rules are fixed
+		if _, revertErr := u.purchaseOrderRepository.Transition(ctx, id, models.PurchaseOrderStatusSubmitted,
+			&models.PurchaseOrderTransition{Status: models.PurchaseOrderStatusDraft}); revertErr != nil {
+			return nil, fmt.Errorf("failed to revert purchase order submission: %w", revertErr)
+		}
+		return nil, err
+	}
+	return submitted, nil
+}
+
+// ApprovalCompleted applies the outcome of the approval engine, an order returned for
+// revision goes back to draft and a rejected order keeps the approver's comment as reason.
+// An order already in the resulting status was completed before and is left alone.
+func (u *PurchaseOrderUseCase) ApprovalCompleted(ctx context.Context, request *models.ApprovalRequest, decision *models.ApprovalStep) error {
+	transition := &models.PurchaseOrderTransition{
+		Status:     models.PurchaseOrderStatusApproved,
+		ApprovedAt: decision.ActedAt,
+		ApprovedBy: decision.ActedBy,
+	}
+	switch request.Status {
+	case models.ApprovalStatusRejected:
+		transition = &models.PurchaseOrderTransition{
+			Status:          models.PurchaseOrderStatusRejected,
+			RejectedAt:      decision.ActedAt,
+			RejectionReason: &decision.Comment,
+		}
+	case models.ApprovalStatusReturned:
+		transition = &models.PurchaseOrderTransition{Status: models.PurchaseOrderStatusDraft}
+	}
+	_, err := u.transition(ctx, request.DocumentID, models.PurchaseOrderStatusSubmitted, transition)
+	if errors.Is(err, errPurchaseOrderStatusChange) {
+		if current, getErr := u.Get(ctx, request.DocumentID); getErr == nil && current.Status == transition.Status {
+			return nil
+		}
+	}
+	return err
+}
+
+// Send issues an approved order to its vendor, from then on the vendor user can see it
+func (u *PurchaseOrderUseCase) Send(ctx context.Context, id string) (*models.PurchaseOrder, error) {
+	existing, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status != models.PurchaseOrderStatusApproved {
+		return nil, apperror.Conflict("purchase_order_not_approved", "only approved purchase orders can be sent")
+	}
+	now := time.Now()
+	return u.transition(ctx, id, existing.Status, &models.PurchaseOrderTransition{
+		Status: models.PurchaseOrderStatusSent,
+		SentAt: &now,
+	})
+}
+
+// ChangeOrder revises an order after approval. The order goes back to draft under the next
+// revision and has to be approved and sent again, the previous revision is kept as history.
+// Orders with goods receipts or invoices are not changed anymore.
+func (u *PurchaseOrderUseCase) ChangeOrder(ctx context.Context, id string, req *models.ChangeOrderRequest) (*models.PurchaseOrder, error) {
+	existing, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch existing.Status {
+	case models.PurchaseOrderStatusApproved, models.PurchaseOrderStatusSent,
+		models.PurchaseOrderStatusAcknowledged, models.PurchaseOrderStatusRejected:
+	default:
+		return nil, apperror.Conflict("purchase_order_not_changeable",
+			"only approved, sent, acknowledged or rejected purchase orders take change orders, drafts are edited directly")
+	}
+	// receipts and invoices refer to the lines a change order replaces
+	receipts, invoices, err := u.purchaseOrderRepository.CountDocuments(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count purchase order documents: %w", err)
+	}
+	if receipts > 0 {
+		return nil, apperror.Conflict("purchase_order_has_receipts", "goods were already received on this purchase order, it can no longer be changed")
+	}
+	if invoices > 0 {
+		return nil, apperror.Conflict("purchase_order_has_invoices", "the vendor already invoiced this purchase order, it can no longer be changed")
+	}
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	order, err := u.build(ctx, &req.PurchaseOrderDetails)
+	if err != nil {
+		return nil, err
+	}
+	order.ID = id
+
+	revised, err := u.purchaseOrderRepository.Revise(ctx, order, &models.PurchaseOrderRevision{
+		Reason:    req.Reason,
+		ChangedBy: &userID,
+		Snapshot:  existing,
+	}, existing.Status)
+	if err != nil {
+		return nil, err
+	}
+	if !revised {
+		return nil, errPurchaseOrderStatusChange
+	}
+	return u.Get(ctx, id)
+}
+
+// Revisions returns the change orders of an order, oldest first
+func (u *PurchaseOrderUseCase) Revisions(ctx context.Context, id string) ([]*models.PurchaseOrderRevision, error) {
+	if _, err := u.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	revisions, err := u.purchaseOrderRepository.ListRevisions(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list purchase order revisions: %w", err)
+	}
+	if revisions == nil {
+		revisions = []*models.PurchaseOrderRevision{}
+	}
+	return revisions, nil
+}
+
+// Close completes an acknowledged or partially received order, nothing more is expected on it
+func (u *PurchaseOrderUseCase) Close(ctx context.Context, id string) (*models.PurchaseOrder, error) {
+	existing, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status != models.PurchaseOrderStatusAcknowledged && existing.Status != models.PurchaseOrderStatusPartiallyReceived {
+		return nil, apperror.Conflict("purchase_order_not_closable", "only acknowledged or partially received purchase orders can be closed")
+	}
+	now := time.Now()
+	return u.transition(ctx, id, existing.Status, &models.PurchaseOrderTransition{
+		Status:   models.PurchaseOrderStatusClosed,
+		ClosedAt: &now,
+	})
+}
+
+// Cancel withdraws an order nothing has been received on yet, the pending approval of a
+// submitted order is cancelled with it
+func (u *PurchaseOrderUseCase) Cancel(ctx context.Context, id string) (*models.PurchaseOrder, error) {
+	existing, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch existing.Status {
+	case models.PurchaseOrderStatusPartiallyReceived, models.PurchaseOrderStatusClosed, models.PurchaseOrderStatusCancelled:
+		return nil, apperror.Conflict("purchase_order_not_cancellable", "received, closed or cancelled purchase orders cannot be cancelled")
+	}
+	now := time.Now()
+	cancelled, err := u.transition(ctx, id, existing.Status, &models.PurchaseOrderTransition{
+		Status:      models.PurchaseOrderStatusCancelled,
+		CancelledAt: &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status == models.PurchaseOrderStatusSubmitted {
+		if err := u.approvals.Cancel(ctx, models.ApprovalDocumentPurchaseOrder, id); err != nil {
+			return nil, err
+		}
+	}
+	return cancelled, nil
+}
+
+// Document returns the structured purchase order document of an order
+func (u *PurchaseOrderUseCase) Document(ctx context.Context, id string) (*models.PurchaseOrderDocument, error) {
+	order, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.document(ctx, order)
+}
+
+// VendorOrders returns a page of the orders sent to the caller's vendor, newest first
+func (u *PurchaseOrderUseCase) VendorOrders(ctx context.Context, status string, limit, page int) ([]*models.PurchaseOrder, int, error) {
+	vendor, err := u.callerVendor(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return u.List(ctx, models.PurchaseOrderFilter{Status: status, VendorID: vendor.ID, SentOnly: true}, limit, page)
+}
+
+// VendorOrder returns an order sent to the caller's vendor
+func (u *PurchaseOrderUseCase) VendorOrder(ctx context.Context, id string) (*models.PurchaseOrder, error) {
+	vendor, err := u.callerVendor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return u.vendorOrder(ctx, id, vendor.ID)
+}
+
+// VendorDocument returns the purchase order document of an order sent to the caller's vendor
+func (u *PurchaseOrderUseCase) VendorDocument(ctx context.Context, id string) (*models.PurchaseOrderDocument, error) {
+	order, err := u.VendorOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.document(ctx, order)
+}
+
+// Acknowledge records that the caller's vendor accepts a sent order
+func (u *PurchaseOrderUseCase) Acknowledge(ctx context.Context, id string, req *models.PurchaseOrderAcknowledgeRequest) (*models.PurchaseOrder, error) {
+	order, err := u.VendorOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.PurchaseOrderStatusSent {
+		return nil, apperror.Conflict("purchase_order_not_sent", "only purchase orders awaiting your response can be acknowledged")
+	}
+	now := time.Now()
+	return u.transition(ctx, id, order.Status, &models.PurchaseOrderTransition{
+		Status:         models.PurchaseOrderStatusAcknowledged,
+		AcknowledgedAt: &now,
+		VendorNote:     &req.Note,
+	})
+}
+
+// Reject records that the caller's vendor declines a sent order, the buyer may answer with a change order
+func (u *PurchaseOrderUseCase) Reject(ctx context.Context, id string, req *models.PurchaseOrderRejectRequest) (*models.PurchaseOrder, error) {
+	order, err := u.VendorOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.PurchaseOrderStatusSent {
+		return nil, apperror.Conflict("purchase_order_not_sent", "only purchase orders awaiting your response can be rejected")
+	}
+	now := time.Now()
+	return u.transition(ctx, id, order.Status, &models.PurchaseOrderTransition{
+		Status:          models.PurchaseOrderStatusRejected,
+		RejectedAt:      &now,
+		RejectionReason: &req.Reason,
+	})
+}
+
+// DraftFromAward drafts one order per winning vendor of the award at the awarded prices.
+// Every winning vendor has to be approved. Vendors that already have an order for the award
+// are skipped, also when a concurrent request drafted it first, the new orders are returned.
+func (u *PurchaseOrderUseCase) DraftFromAward(ctx context.Context, rfq *models.RFQ, award *models.RFQAward) ([]*models.PurchaseOrder, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := u.ListByAward(ctx, award.ID)
+	if err != nil {
+		return nil, err
+	}
+	ordered := map[string]bool{}
+	for _, order := range existing {
+		ordered[order.VendorID] = true
+	}
+	rfqLines := make(map[string]*models.RFQLine, len(rfq.Lines))
+	for _, line := range rfq.Lines {
+		rfqLines[line.ID] = line
+	}
+
+	// award lines come in RFQ line order, so the order lines keep it too
+	var vendors []string
+	orders := map[string]*models.PurchaseOrder{}
+	for _, awardLine := range award.Lines {
+		if ordered[awardLine.VendorID] {
+			continue
+		}
+		order, ok := orders[awardLine.VendorID]
+		if !ok {
+			vendor, err := u.vendorRepository.GetVendorByID(ctx, awardLine.VendorID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get vendor: %w", err)
+			}
+			if err := requireApproved(vendor); err != nil {
+				return nil, err
+			}
+			order = &models.PurchaseOrder{
+				VendorID:     awardLine.VendorID,
+				RFQID:        &award.RFQID,
+				AwardID:      &award.ID,
+				Status:       models.PurchaseOrderStatusDraft,
+				PaymentTerms: vendor.PaymentTerms,
+				CreatedBy:    userID,
+			}
+			orders[awardLine.VendorID] = order
+			vendors = append(vendors, awardLine.VendorID)
+		}
+		rfqLine := rfqLines[awardLine.RFQLineID]
+		order.Lines = append(order.Lines, &models.PurchaseOrderLine{
+			ProductID:   rfqLine.ProductID,
+			Description: rfqLine.Description,
+			Quantity:    awardLine.Quantity,
+			Unit:        rfqLine.Unit,
+			UnitPrice:   awardLine.UnitPrice,
+			Amount:      awardLine.Amount,
+		})
+		order.Subtotal += awardLine.Amount
+		order.TotalAmount += awardLine.Amount
+	}
+
+	created := []*models.PurchaseOrder{}
+	for _, vendorID := range vendors {
+		order, err := u.purchaseOrderRepository.Create(ctx, orders[vendorID])
+		// the award and vendor are unique among the orders, a conflict means the order exists already
+		if apperror.IsKind(err, apperror.KindConflict) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, order)
+	}
+	return created, nil
+}
+
+// transition moves the order out of status from, a concurrent change is reported as a conflict
+func (u *PurchaseOrderUseCase) transition(ctx context.Context, id, from string, transition *models.PurchaseOrderTransition) (*models.PurchaseOrder, error) {
+	changed, err := u.purchaseOrderRepository.Transition(ctx, id, from, transition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update purchase order status: %w", err)
+	}
+	if !changed {
+		return nil, errPurchaseOrderStatusChange
+	}
+	return u.Get(ctx, id)
+}
+
+// callerVendor returns the vendor profile of the caller, orders are answered on its behalf
+func (u *PurchaseOrderUseCase) callerVendor(ctx context.Context) (*models.Vendor, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vendor, err := u.vendorRepository.GetVendorByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user vendor: %w", err)
+	}
+	if vendor == nil {
+		return nil, apperror.Forbidden("vendor_profile_required", "only vendor users can respond to purchase orders")
+	}
+	return vendor, nil
+}
+
+// vendorOrder returns the order when it was sent to the vendor, other orders are
+// reported as not found so vendors cannot probe for them
+func (u *PurchaseOrderUseCase) vendorOrder(ctx context.Context, id, vendorID string) (*models.PurchaseOrder, error) {
+	order, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.VendorID != vendorID || order.SentAt == nil {
+		return nil, apperror.NotFound("purchase_order_not_found", fmt.Sprintf("purchase order with ID %s not found", id))
+	}
+	return order, nil
+}
+
+// approvalSubject describes the order to the approval engine, the categories are
+// those of the catalog products on its lines
+func (u *PurchaseOrderUseCase) approvalSubject(ctx context.Context, order *models.PurchaseOrder, requestedBy string) (*models.ApprovalSubject, error) {
+	subject := &models.ApprovalSubject{
+		DocumentType: models.ApprovalDocumentPurchaseOrder,
+		DocumentID:   order.ID,
+		Title:        fmt.Sprintf("%s %s", order.Number, order.VendorName),
+		Amount:       order.TotalAmount,
+		RequestedBy:  requestedBy,
+	}
+	seen := map[string]bool{}
+	for _, line := range order.Lines {
+		if line.ProductID == nil {
+			continue
+		}
+		product, err := u.productRepository.GetProductByID(ctx, *line.ProductID)
+		if err != nil && !apperror.IsKind(err, apperror.KindNotFound) {
+			return nil, fmt.Errorf("failed to get product: %w", err)
+		}
+		if product != nil && product.ProductCategoryID != "" && !seen[product.ProductCategoryID] {
+			seen[product.ProductCategoryID] = true
+			subject.CategoryIDs = append(subject.CategoryIDs, product.ProductCategoryID)
+		}
+	}
+	return subject, nil
+}
+
+// document lays the order out as a purchase order document with the buyer organization,
+// the vendor and the taxes summed per rate
+func (u *PurchaseOrderUseCase) document(ctx context.Context, order *models.PurchaseOrder) (*models.PurchaseOrderDocument, error) {
+	organization, err := u.organizationRepository.GetByID(ctx, order.OrganizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	if organization == nil {
+		return nil, apperror.NotFound("organization_not_found", fmt.Sprintf("organization with ID %s not found", order.OrganizationID))
+	}
+
+	issueDate := order.CreatedAt
+	if order.SentAt != nil {
+		issueDate = *order.SentAt
+	}
+	document := &models.PurchaseOrderDocument{
+		Number:       order.Number,
+		Revision:     order.Revision,
+		Status:       order.Status,
+		IssueDate:    issueDate,
+		Buyer:        models.PurchaseOrderParty{ID: organization.ID, Code: organization.Code, Name: organization.Name},
+		Vendor:       models.PurchaseOrderParty{ID: order.VendorID, Name: order.VendorName},
+		ShipTo:       order.ShipTo,
+		PaymentTerms: order.PaymentTerms,
+		DeliveryDate: order.DeliveryDate,
+		Notes:        order.Notes,
+		Lines:        order.Lines,
+		Taxes:        []*models.PurchaseOrderTaxTotal{},
+		Subtotal:     order.Subtotal,
+		TaxAmount:    order.TaxAmount,
+		TotalAmount:  order.TotalAmount,
+	}
+	taxes := map[float64]*models.PurchaseOrderTaxTotal{}
+	for _, line := range order.Lines {
+		tax, ok := taxes[line.TaxRate]
+		if !ok {
+			tax = &models.PurchaseOrderTaxTotal{TaxRate: line.TaxRate}
+			taxes[line.TaxRate] = tax
+			document.Taxes = append(document.Taxes, tax)
+		}
+		tax.TaxableAmount = round2(tax.TaxableAmount + line.Amount)
+		tax.TaxAmount = round2(tax.TaxAmount + line.TaxAmount)
+	}
+	sort.Slice(document.Taxes, func(i, j int) bool { return document.Taxes[i].TaxRate < document.Taxes[j].TaxRate })
+	return document, nil
+}
+
+// build turns the request into the changeable part of an order. Catalog lines take their
+// description and, when not given, their unit price from the product; the tax of each line
+// is rounded to cents before it is added to the totals.
+func (u *PurchaseOrderUseCase) build(ctx context.Context, req *models.PurchaseOrderDetails) (*models.PurchaseOrder, error) {
+	order := &models.PurchaseOrder{
+		ShipTo:       req.ShipTo,
+		PaymentTerms: req.PaymentTerms,
+		Notes:        req.Notes,
+	}
+	var err error
+	if order.PaymentTerms, err = normalizePaymentTerms(req.PaymentTerms); err != nil {
+		return nil, err
+	}
+	if req.DeliveryDate != "" {
+		deliveryDate, err := time.Parse(time.DateOnly, req.DeliveryDate)
+		if err != nil {
+			return nil, apperror.Validation("invalid_delivery_date", "delivery_date must be formatted as YYYY-MM-DD")
+		}
+		order.DeliveryDate = &deliveryDate
+	}
+
+	for i, lineReq := range req.Lines {
+		line := &models.PurchaseOrderLine{
+			ProductID:   lineReq.ProductID,
+			Description: lineReq.Description,
+			Quantity:    lineReq.Quantity,
+			Unit:        lineReq.Unit,
+			UnitPrice:   lineReq.UnitPrice,
+			TaxRate:     lineReq.TaxRate,
+		}
+		if line.Unit == "" {
+			line.Unit = defaultPurchaseOrderUnit
+		}
+		if line.ProductID != nil {
+			// the product repositories report a missing product as a not found error
+			product, err := u.productRepository.GetProductByID(ctx, *line.ProductID)
+			if err != nil && !apperror.IsKind(err, apperror.KindNotFound) {
+				return nil, fmt.Errorf("failed to get product: %w", err)
+			}
+			if product == nil {
+				return nil, apperror.Validation("unknown_product", fmt.Sprintf("line %d: product with ID %s not found", i+1, *line.ProductID))
+			}
+			if line.Description == "" {
+				line.Description = product.ProductName
+			}
+			if line.UnitPrice == 0 {
+				line.UnitPrice = product.ProductPrice
+			}
+		}
+		line.Amount = round2(line.Quantity * line.UnitPrice)
+		line.TaxAmount = round2(line.Amount * line.TaxRate / 100)
+		order.Subtotal += line.Amount
+		order.TaxAmount += line.TaxAmount
+		order.Lines = append(order.Lines, line)
+	}
+	order.Subtotal = round2(order.Subtotal)
+	order.TaxAmount = round2(order.TaxAmount)
+	order.TotalAmount = round2(order.Subtotal + order.TaxAmount)
+	return order, nil
+}
@@ -0,0 +1,177 @@
+package usecases_test
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/repositories/memory"
+	"e-procurement/internals/usecases"
+	"e-procurement/pkg/apperror"
+	"e-procurement/pkg/rbac"
+	"testing"
+	"time"
+)
+
+// racingOrders runs race right before the next order is stored, like a concurrent request
+// drafting the orders of the same award
+type racingOrders struct {
+	*memory.PurchaseOrderRepository
+	race func(ctx context.Context)
+}
+
+func (r *racingOrders) Create(ctx context.Context, order *models.PurchaseOrder) (*models.PurchaseOrder, error) {
+	if race := r.race; race != nil {
+		r.race = nil
+		race(ctx)
+	}
+	return r.PurchaseOrderRepository.Create(ctx, order)
+}
+
+// newAward awards the single line of an open RFQ to the quotation of the vendor
+func newAward(t *testing.T, org *testOrg, buyer string, vendor *models.Vendor) (*models.RFQ, *models.RFQAward) {
+	t.Helper()
+	rfqs := memory.NewRFQRepository(org.store)
+	rfq, err := rfqs.Create(org.as(buyer), &models.RFQ{
+		Title:            "Bolts",
+		Status:           models.RFQStatusOpen,
+		ResponseDeadline: time.Now().Add(time.Hour),
+		CreatedBy:        buyer,
+		Lines:            []*models.RFQLine{{Description: "Bolt", Quantity: 10, Unit: "pcs"}},
+		Vendors:          []*models.RFQVendor{{VendorID: vendor.ID}},
+	})
+	if err != nil {
+		t.Fatalf("create rfq: %v", err)
+	}
+	quotations := memory.NewQuotationRepository(org.store)
+	saved, err := quotations.Save(org.as(vendor.UserID), &models.Quotation{
+		RFQID:    rfq.ID,
+		VendorID: vendor.ID,
+		Lines: []*models.QuotationLine{{
+			RFQLineID:  rfq.Lines[0].ID,
+			UnitPrice:  1000,
+			Amount:     10000,
+			ValidUntil: time.Now().AddDate(0, 1, 0),
+		}},
+	})
+	if err != nil || !saved {
+		t.Fatalf("save quotation: %v, %v", saved, err)
+	}
+	quotation, err := quotations.GetByVendor(org.as(buyer), rfq.ID, vendor.ID)
+	if err != nil {
+		t.Fatalf("get quotation: %v", err)
+	}
+	awards := memory.NewRFQAwardRepository(org.store)
+	awarded, err := awards.Create(org.as(buyer), &models.RFQAward{
+		RFQID:     rfq.ID,
+		Mode:      models.AwardModeRFQ,
+		AwardedBy: buyer,
+		Lines: []*models.RFQAwardLine{{
+			RFQLineID:   rfq.Lines[0].ID,
+			QuotationID: quotation.ID,
+			VendorID:    vendor.ID,
+			Quantity:    10,
+			UnitPrice:   1000,
+			Amount:      10000,
+		}},
+	})
+	if err != nil || !awarded {
+		t.Fatalf("create award: %v, %v", awarded, err)
+	}
+	award, err := awards.GetByRFQ(org.as(buyer), rfq.ID)
+	if err != nil {
+		t.Fatalf("get award: %v", err)
+	}
+	return rfq, award
+}
+
+func TestPurchaseOrderChangeOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   func(t *testing.T, f *invoiceFixture)
+		wantCode string
+	}{
+		{name: "nothing recorded yet", record: func(t *testing.T, f *invoiceFixture) {}},
+		{
+			// a receipt rejected as a whole leaves the order sent
+			name: "goods receipt",
+			record: func(t *testing.T, f *invoiceFixture) {
+				_, err := f.receipts.Record(f.org.as(f.warehouse), f.order.ID, &models.GoodsReceiptRequest{
+					Lines: []*models.GoodsReceiptLineRequest{{
+						PurchaseOrderLineID: f.order.Lines[0].ID,
+						ReceivedQuantity:    10,
+						RejectedQuantity:    10,
+						InspectionNotes:     "rusted",
+					}},
+				})
+				assertAppError(t, err, 0, "")
+			},
+			wantCode: "purchase_order_has_receipts",
+		},
+		{
+			name: "invoice",
+			record: func(t *testing.T, f *invoiceFixture) {
+				f.mustSubmit(t, "INV-1", 10, models.MatchStatusException)
+			},
+			wantCode: "purchase_order_has_invoices",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newInvoiceFixture(t)
+			tt.record(t, f)
+
+			changed, err := f.orders.ChangeOrder(f.org.as(f.buyer), f.order.ID, &models.ChangeOrderRequest{
+				Reason: "more bolts",
+				PurchaseOrderDetails: models.PurchaseOrderDetails{
+					ShipTo: "Warehouse Jakarta",
+					Lines:  []*models.PurchaseOrderLineRequest{{Description: "Bolt", Quantity: 20, UnitPrice: 1000}},
+				},
+			})
+			assertAppError(t, err, apperror.KindConflict, tt.wantCode)
+			if err == nil && (changed.Status != models.PurchaseOrderStatusDraft || changed.Revision != 1) {
+				t.Fatalf("order is %s revision %d, want draft revision 1", changed.Status, changed.Revision)
+			}
+			if err != nil {
+				order, err := f.orders.Get(f.org.as(f.buyer), f.order.ID)
+				if err != nil || order.Status != models.PurchaseOrderStatusSent || order.Lines[0].ID != f.order.Lines[0].ID {
+					t.Fatalf("order changed to %s, %v", order.Status, err)
+				}
+			}
+		})
+	}
+}
+
+func TestPurchaseOrderDraftFromAward(t *testing.T) {
+	org := newTestOrg(t)
+	buyer := org.addUser("buyer", rbac.RoleProcurementOfficer)
+	seller := org.addUser("seller", rbac.RoleVendor)
+	vendor := org.addVendor(seller, "Seller Supply")
+	rfq, award := newAward(t, org, buyer, vendor)
+	vendors := memory.NewVendorRepository(org.store)
+	orderRepo := &racingOrders{PurchaseOrderRepository: memory.NewPurchaseOrderRepository(org.store)}
+	orders := usecases.NewPurchaseOrderUseCase(orderRepo, memory.NewProductRepository(org.store), vendors,
+		memory.NewOrganizationRepository(org.store), org.approvals())
+
+	_, err := orders.DraftFromAward(org.as(buyer), rfq, award)
+	assertAppError(t, err, apperror.KindConflict, "vendor_not_approved")
+	if existing, _ := orders.ListByAward(org.as(buyer), award.ID); len(existing) != 0 {
+		t.Fatalf("award has %d orders for a vendor still in onboarding, want none", len(existing))
+	}
+
+	if _, err := vendors.UpdateVendorStatus(org.as(seller), vendor.ID, models.VendorStatusPending, models.VendorStatusApproved); err != nil {
+		t.Fatalf("approve vendor: %v", err)
+	}
+	// another request drafts the order between the check for existing orders and the insert
+	orderRepo.race = func(ctx context.Context) {
+		if _, err := orders.DraftFromAward(ctx, rfq, award); err != nil {
+			t.Errorf("concurrent draft: %v", err)
+		}
+	}
+	created, err := orders.DraftFromAward(org.as(buyer), rfq, award)
+	assertAppError(t, err, 0, "")
+	if len(created) != 0 {
+		t.Fatalf("drafted %d orders, want none after the concurrent draft", len(created))
+	}
+	if existing, _ := orders.ListByAward(org.as(buyer), award.ID); len(existing) != 1 {
+		t.Fatalf("award has %d orders, want 1", len(existing))
+	}
+}
@@ -0,0 +1,41 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/repository"
+	"fmt"
+	"time"
+)
+
+// RateLimiter allows at most `limit` requests per subject within a sliding window.
+// counts are kept in the repository so every instance of the API shares them.
+type RateLimiter struct {
+	repo   repository.RateLimitRepository
+	scope  string
+	limit  int
+	window time.Duration
+}
+
+func NewRateLimiter(repo repository.RateLimitRepository, scope string, limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		repo:   repo,
+		scope:  scope,
+		limit:  limit,
+		window: window,
+	}
+}
+
+// Allow records the request when it is within the limit, rejected requests are not recorded
+func (l *RateLimiter) Allow(ctx context.Context, subject string) (bool, error) {
+	count, err := l.repo.CountSince(ctx, l.scope, subject, time.Now().Add(-l.window))
+	if err != nil {
+		return false, fmt.Errorf("failed to count %s requests: %w", l.scope, err)
+	}
+	if count >= l.limit {
+		return false, nil
+	}
+	if err := l.repo.Record(ctx, l.scope, subject); err != nil {
+		return false, fmt.Errorf("failed to record %s request: %w", l.scope, err)
+	}
+	return true, nil
+}
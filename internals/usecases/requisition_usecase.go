@@ -0,0 +1,329 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	customContext "e-procurement/pkg/context"
+	"e-procurement/pkg/rbac"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const defaultRequisitionUnit = "pcs"
+
+var (
+	errRequisitionNotOwned     = apperror.Forbidden("requisition_not_owned", "you can only change your own requisitions")
+	errRequisitionNotEditable  = apperror.Conflict("requisition_not_editable", "only draft requisitions can be changed")
+	errRequisitionStatusChange = apperror.Conflict("requisition_status_changed", "the requisition status has changed, reload it and try again")
+)
+
+// RequisitionUseCase manages purchase requisitions from draft to the approval decision.
+// Requisitions are charged to the requester's purchase defaults unless the request names
+// a department or cost center. Submitted requisitions are decided through the approval engine.
+type RequisitionUseCase struct {
+	requisitionRepository repository.RequisitionRepository
+	productRepository     repository.ProductRepository
+	departments           *DepartmentUseCase
+	approvals             *ApprovalUseCase
+}
+
+var _ ApprovalHandler = (*RequisitionUseCase)(nil)
+
+func NewRequisitionUseCase(requisitionRepo repository.RequisitionRepository, productRepo repository.ProductRepository, departments *DepartmentUseCase, approvals *ApprovalUseCase) *RequisitionUseCase {
+	return &RequisitionUseCase{
+		requisitionRepository: requisitionRepo,
+		productRepository:     productRepo,
+		departments:           departments,
+		approvals:             approvals,
+	}
+}
+
+// Create stores a new draft requisition raised by the caller
+func (u *RequisitionUseCase) Create(ctx context.Context, req *models.RequisitionRequest) (*models.PurchaseRequisition, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	requisition, err := u.build(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	requisition.Status = models.RequisitionStatusDraft
+	return u.requisitionRepository.Create(ctx, requisition)
+}
+
+// Get returns a requisition of the organization with its lines
+func (u *RequisitionUseCase) Get(ctx context.Context, id string) (*models.PurchaseRequisition, error) {
+	requisition, err := u.requisitionRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requisition: %w", err)
+	}
+	if requisition == nil {
+		return nil, apperror.NotFound("requisition_not_found", fmt.Sprintf("requisition with ID %s not found", id))
+	}
+	return requisition, nil
+}
+
+// List returns a page of requisitions, newest first, with the total count.
+// When mine is set only the caller's own requisitions are returned.
+func (u *RequisitionUseCase) List(ctx context.Context, filter models.RequisitionFilter, mine bool, limit, page int) ([]*models.PurchaseRequisition, int, error) {
+	limit, offset, err := pageOffset(limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	if mine {
+		if filter.RequesterID, err = customContext.GetUserIDFromContext(ctx); err != nil {
+			return nil, 0, err
+		}
+	}
+	requisitions, count, err := u.requisitionRepository.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list requisitions: %w", err)
+	}
+	return requisitions, count, nil
+}
+
+// Update replaces the header and lines of a draft requisition, only its requester may change it
+func (u *RequisitionUseCase) Update(ctx context.Context, id string, req *models.RequisitionRequest) (*models.PurchaseRequisition, error) {
+	existing, err := u.owned(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status != models.RequisitionStatusDraft {
+		return nil, errRequisitionNotEditable
+	}
+
+	requisition, err := u.build(ctx, existing.RequesterID, req)
+	if err != nil {
+		return nil, err
+	}
+	requisition.ID = id
+	updated, err := u.requisitionRepository.UpdateDraft(ctx, requisition)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errRequisitionNotEditable
+	}
+	return u.Get(ctx, id)
+}
+
+// Submit sends a draft requisition of the caller for approval, the approvers follow
+// from the approval rules matching its amount, categories and department
+func (u *RequisitionUseCase) Submit(ctx context.Context, id string) (*models.PurchaseRequisition, error) {
+	existing, err := u.owned(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status != models.RequisitionStatusDraft {
+		return nil, apperror.Conflict("requisition_not_draft", "only draft requisitions can be submitted")
+	}
+	subject, err := u.approvalSubject(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	submitted, err := u.transition(ctx, id, existing.Status, &models.RequisitionTransition{
+		Status:      models.RequisitionStatusSubmitted,
+		SubmittedAt: &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.approvals.Start(ctx, subject); err != nil {
+		// put the requisition back so it can be submitted again once the rules are fixed
+		if _, revertErr := u.requisitionRepository.Transition(ctx, id, models.RequisitionStatusSubmitted,
+			&models.RequisitionTransition{Status: models.RequisitionStatusDraft}); revertErr != nil {
+			return nil, fmt.Errorf("failed to revert requisition submission: %w", revertErr)
+		}
+		return nil, err
+	}
+	return submitted, nil
+}
+
+// ApprovalCompleted applies the outcome of the approval engine, a requisition returned for
+// revision goes back to draft with the approver's comment. A requisition already in the
+// resulting status was completed before and is left alone.
+func (u *RequisitionUseCase) ApprovalCompleted(ctx context.Context, request *models.ApprovalRequest, decision *models.ApprovalStep) error {
+	status := models.RequisitionStatusApproved
+	switch request.Status {
+	case models.ApprovalStatusRejected:
+		status = models.RequisitionStatusRejected
+	case models.ApprovalStatusReturned:
+		status = models.RequisitionStatusDraft
+	}
+	_, err := u.transition(ctx, request.DocumentID, models.RequisitionStatusSubmitted, &models.RequisitionTransition{
+		Status:          status,
+		DecidedAt:       decision.ActedAt,
+		DecidedBy:       decision.ActedBy,
+		DecisionComment: decision.Comment,
+	})
+	if errors.Is(err, errRequisitionStatusChange) {
+		if current, getErr := u.Get(ctx, request.DocumentID); getErr == nil && current.Status == status {
+			return nil
+		}
+	}
+	return err
+}
+
+// Cancel withdraws a draft or submitted requisition, by its requester or an admin
+func (u *RequisitionUseCase) Cancel(ctx context.Context, id string) (*models.PurchaseRequisition, error) {
+	existing, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	position, err := customContext.GetPositionFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if existing.RequesterID != userID && position != rbac.RoleAdmin {
+		return nil, errRequisitionNotOwned
+	}
+	if existing.Status != models.RequisitionStatusDraft && existing.Status != models.RequisitionStatusSubmitted {
+		return nil, apperror.Conflict("requisition_not_cancellable", "only draft or submitted requisitions can be cancelled")
+	}
+	cancelled, err := u.transition(ctx, id, existing.Status, &models.RequisitionTransition{Status: models.RequisitionStatusCancelled})
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status == models.RequisitionStatusSubmitted {
+		if err := u.approvals.Cancel(ctx, models.ApprovalDocumentRequisition, id); err != nil {
+			return nil, err
+		}
+	}
+	return cancelled, nil
+}
+
+// transition moves the requisition out of status from, a concurrent change is reported as a conflict
+func (u *RequisitionUseCase) transition(ctx context.Context, id, from string, transition *models.RequisitionTransition) (*models.PurchaseRequisition, error) {
+	changed, err := u.requisitionRepository.Transition(ctx, id, from, transition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update requisition status: %w", err)
+	}
+	if !changed {
+		return nil, errRequisitionStatusChange
+	}
+	return u.Get(ctx, id)
+}
+
+// owned returns the requisition when the caller raised it
+func (u *RequisitionUseCase) owned(ctx context.Context, id string) (*models.PurchaseRequisition, error) {
+	requisition, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if requisition.RequesterID != userID {
+		return nil, errRequisitionNotOwned
+	}
+	return requisition, nil
+}
+
+// approvalSubject describes the requisition to the approval engine, the categories are
+// those of the catalog products on its lines
+func (u *RequisitionUseCase) approvalSubject(ctx context.Context, requisition *models.PurchaseRequisition) (*models.ApprovalSubject, error) {
+	subject := &models.ApprovalSubject{
+		DocumentType: models.ApprovalDocumentRequisition,
+		DocumentID:   requisition.ID,
+		Title:        requisition.Title,
+		Amount:       requisition.TotalAmount,
+		DepartmentID: requisition.DepartmentID,
+		RequestedBy:  requisition.RequesterID,
+	}
+	seen := map[string]bool{}
+	for _, line := range requisition.Lines {
+		if line.ProductID == nil {
+			continue
+		}
+		product, err := u.productRepository.GetProductByID(ctx, *line.ProductID)
+		if err != nil && !apperror.IsKind(err, apperror.KindNotFound) {
+			return nil, fmt.Errorf("failed to get product: %w", err)
+		}
+		if product != nil && product.ProductCategoryID != "" && !seen[product.ProductCategoryID] {
+			seen[product.ProductCategoryID] = true
+			subject.CategoryIDs = append(subject.CategoryIDs, product.ProductCategoryID)
+		}
+	}
+	return subject, nil
+}
+
+// build turns the request into a requisition charged to the requester. Catalog lines take
+// their description and, when not given, their unit price from the product.
+func (u *RequisitionUseCase) build(ctx context.Context, requesterID string, req *models.RequisitionRequest) (*models.PurchaseRequisition, error) {
+	requisition := &models.PurchaseRequisition{
+		RequesterID:   requesterID,
+		Title:         req.Title,
+		Justification: req.Justification,
+		DepartmentID:  req.DepartmentID,
+		CostCenterID:  req.CostCenterID,
+	}
+	if err := u.departments.activeCostCenter(ctx, req.CostCenterID); err != nil {
+		return nil, err
+	}
+	if req.DepartmentID != nil {
+		if err := u.departments.requesterDepartment(ctx, requesterID, *req.DepartmentID); err != nil {
+			return nil, err
+		}
+	}
+	if requisition.DepartmentID == nil || requisition.CostCenterID == nil {
+		defaults, err := u.departments.PurchaseDefaults(ctx, requesterID)
+		if err != nil {
+			return nil, err
+		}
+		if requisition.DepartmentID == nil {
+			requisition.DepartmentID = defaults.DepartmentID
+		}
+		if requisition.CostCenterID == nil {
+			requisition.CostCenterID = defaults.CostCenterID
+		}
+	}
+
+	for i, lineReq := range req.Lines {
+		neededBy, err := time.Parse(time.DateOnly, lineReq.NeededBy)
+		if err != nil {
+			return nil, apperror.Validation("invalid_needed_by", fmt.Sprintf("line %d: needed_by must be formatted as YYYY-MM-DD", i+1))
+		}
+		line := &models.PurchaseRequisitionLine{
+			ProductID:   lineReq.ProductID,
+			Description: lineReq.Description,
+			Quantity:    lineReq.Quantity,
+			Unit:        lineReq.Unit,
+			UnitPrice:   lineReq.UnitPrice,
+			NeededBy:    neededBy,
+		}
+		if line.Unit == "" {
+			line.Unit = defaultRequisitionUnit
+		}
+		if line.ProductID != nil {
+			// the product repositories report a missing product as a not found error
+			product, err := u.productRepository.GetProductByID(ctx, *line.ProductID)
+			if err != nil && !apperror.IsKind(err, apperror.KindNotFound) {
+				return nil, fmt.Errorf("failed to get product: %w", err)
+			}
+			if product == nil {
+				return nil, apperror.Validation("unknown_product", fmt.Sprintf("line %d: product with ID %s not found", i+1, *line.ProductID))
+			}
+			if line.Description == "" {
+				line.Description = product.ProductName
+			}
+			if line.UnitPrice == 0 {
+				line.UnitPrice = product.ProductPrice
+			}
+		}
+		line.Amount = line.Quantity * line.UnitPrice
+		requisition.TotalAmount += line.Amount
+		requisition.Lines = append(requisition.Lines, line)
+	}
+	return requisition, nil
+}
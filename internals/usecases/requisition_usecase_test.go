@@ -0,0 +1,63 @@
+package usecases_test
+
+import (
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/repositories/memory"
+	"e-procurement/internals/usecases"
+	"e-procurement/pkg/apperror"
+	"e-procurement/pkg/rbac"
+	"testing"
+)
+
+func TestRequisitionDepartmentOfRequester(t *testing.T) {
+	org := newTestOrg(t)
+	admin := org.addUser("admin", rbac.RoleAdmin)
+	requester := org.addUser("requester", rbac.RoleProcurementOfficer)
+	unassigned := org.addUser("unassigned", rbac.RoleProcurementOfficer)
+	departments := usecases.NewDepartmentUseCase(memory.NewDepartmentRepository(org.store), memory.NewCostCenterRepository(org.store))
+	requisitions := usecases.NewRequisitionUseCase(memory.NewRequisitionRepository(org.store), memory.NewProductRepository(org.store), departments, org.approvals())
+
+	department := func(code string, parentID *string) string {
+		t.Helper()
+		created, err := departments.Create(org.as(admin), &models.DepartmentRequest{Code: code, Name: code, ParentID: parentID})
+		if err != nil {
+			t.Fatalf("create department %s: %v", code, err)
+		}
+		return created.ID
+	}
+	operations := department("ops", nil)
+	warehouse := department("ops-warehouse", &operations)
+	finance := department("finance", nil)
+	if _, err := departments.Assign(org.as(admin), requester, &models.AssignDepartmentRequest{DepartmentID: &operations}); err != nil {
+		t.Fatalf("assign department: %v", err)
+	}
+	unknown := "00000000-0000-4000-8000-000000000000"
+
+	tests := []struct {
+		name         string
+		requesterID  string
+		departmentID *string
+		wantCode     string
+	}{
+		{name: "default department", requesterID: requester},
+		{name: "own department", requesterID: requester, departmentID: &operations},
+		{name: "department below the own one", requesterID: requester, departmentID: &warehouse},
+		{name: "another department", requesterID: requester, departmentID: &finance, wantCode: "department_not_assigned"},
+		{name: "unknown department", requesterID: requester, departmentID: &unknown, wantCode: "unknown_department"},
+		{name: "requester without department", requesterID: unassigned, departmentID: &finance, wantCode: "department_not_assigned"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requisition, err := requisitions.Create(org.as(tt.requesterID), &models.RequisitionRequest{
+				Title:         "Office chairs",
+				Justification: "new hires",
+				DepartmentID:  tt.departmentID,
+				Lines:         []*models.RequisitionLineRequest{{Description: "Chair", Quantity: 3, UnitPrice: 1000, NeededBy: "2026-12-01"}},
+			})
+			assertAppError(t, err, apperror.KindValidation, tt.wantCode)
+			if err == nil && tt.departmentID != nil && *requisition.DepartmentID != *tt.departmentID {
+				t.Fatalf("requisition charged to %s, want %s", *requisition.DepartmentID, *tt.departmentID)
+			}
+		})
+	}
+}
@@ -0,0 +1,509 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
+	customContext "e-procurement/pkg/context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var (
+	errRFQNotEditable  = apperror.Conflict("rfq_not_editable", "only draft RFQs can be changed")
+	errRFQStatusChange = apperror.Conflict("rfq_status_changed", "the RFQ status has changed, reload it and try again")
+	errRFQClosed       = apperror.Conflict("rfq_closed", "the RFQ no longer accepts quotations")
+	errRFQSealed       = apperror.Forbidden("rfq_sealed", "quotations stay sealed until the response deadline has passed")
+)
+
+// RFQUseCase manages requests for quotation and the quotations of the invited vendors.
+// Buyers only see the quotations once the response deadline has passed, a vendor only
+// ever sees the published RFQs it is invited to and its own quotation.
+type RFQUseCase struct {
+	rfqRepository         repository.RFQRepository
+	quotationRepository   repository.QuotationRepository
+	requisitionRepository repository.RequisitionRepository
+	productRepository     repository.ProductRepository
+	vendorRepository      repository.VendorRepository
+}
+
+func NewRFQUseCase(rfqRepo repository.RFQRepository, quotationRepo repository.QuotationRepository, requisitionRepo repository.RequisitionRepository, productRepo repository.ProductRepository, vendorRepo repository.VendorRepository) *RFQUseCase {
+	return &RFQUseCase{
+		rfqRepository:         rfqRepo,
+		quotationRepository:   quotationRepo,
+		requisitionRepository: requisitionRepo,
+		productRepository:     productRepo,
+		vendorRepository:      vendorRepo,
+	}
+}
+
+// Create stores a new draft RFQ prepared by the caller
+func (u *RFQUseCase) Create(ctx context.Context, req *models.RFQRequest) (*models.RFQ, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rfq, err := u.build(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	rfq.CreatedBy = userID
+	rfq.Status = models.RFQStatusDraft
+	created, err := u.rfqRepository.Create(ctx, rfq)
+	if err != nil {
+		return nil, err
+	}
+	return seal(created), nil
+}
+
+// Get returns an RFQ of the organization with its lines and invited vendors
+func (u *RFQUseCase) Get(ctx context.Context, id string) (*models.RFQ, error) {
+	rfq, err := u.rfqRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rfq: %w", err)
+	}
+	if rfq == nil {
+		return nil, apperror.NotFound("rfq_not_found", fmt.Sprintf("rfq with ID %s not found", id))
+	}
+	return seal(rfq), nil
+}
+
+// List returns a page of RFQs, newest first, with the total count
+func (u *RFQUseCase) List(ctx context.Context, status string, limit, page int) ([]*models.RFQ, int, error) {
+	return u.list(ctx, models.RFQFilter{Status: status}, limit, page)
+}
+
+// Update replaces the header, lines and invited vendors of a draft RFQ
+func (u *RFQUseCase) Update(ctx context.Context, id string, req *models.RFQRequest) (*models.RFQ, error) {
+	existing, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status != models.RFQStatusDraft {
+		return nil, errRFQNotEditable
+	}
+
+	rfq, err := u.build(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	rfq.ID = id
+	updated, err := u.rfqRepository.UpdateDraft(ctx, rfq)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errRFQNotEditable
+	}
+	return u.Get(ctx, id)
+}
+
+// Publish opens a draft RFQ to its invited vendors until the response deadline
+func (u *RFQUseCase) Publish(ctx context.Context, id string) (*models.RFQ, error) {
+	existing, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status != models.RFQStatusDraft {
+		return nil, apperror.Conflict("rfq_not_draft", "only draft RFQs can be published")
+	}
+	now := time.Now()
+	if !existing.ResponseDeadline.After(now) {
+		return nil, apperror.Validation("invalid_response_deadline", "the response deadline must be in the future")
+	}
+	if len(existing.Vendors) == 0 {
+		return nil, apperror.Validation("rfq_no_vendors", "invite at least one vendor before publishing the RFQ")
+	}
+	return u.transition(ctx, id, existing.Status, &models.RFQTransition{
+		Status:      models.RFQStatusOpen,
+		PublishedAt: &now,
+	})
+}
+
+// Cancel withdraws a draft or open RFQ, quotations already received stay sealed until the deadline
+func (u *RFQUseCase) Cancel(ctx context.Context, id string) (*models.RFQ, error) {
+	existing, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status != models.RFQStatusDraft && existing.Status != models.RFQStatusOpen {
+		return nil, apperror.Conflict("rfq_not_cancellable", "only draft or open RFQs can be cancelled")
+	}
+	return u.transition(ctx, id, existing.Status, &models.RFQTransition{Status: models.RFQStatusCancelled})
+}
+
+// Invite adds vendors to a draft RFQ or to an open RFQ whose deadline has not passed
+func (u *RFQUseCase) Invite(ctx context.Context, id string, req *models.RFQInviteRequest) (*models.RFQ, error) {
+	existing, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status != models.RFQStatusDraft && (existing.Status != models.RFQStatusOpen || !existing.Sealed) {
+		return nil, errRFQClosed
+	}
+	if err := u.checkVendors(ctx, req.VendorIDs); err != nil {
+		return nil, err
+	}
+	if err := u.rfqRepository.InviteVendors(ctx, id, req.VendorIDs); err != nil {
+		return nil, err
+	}
+	return u.Get(ctx, id)
+}
+
+// Quotations returns the quotations of an RFQ once its deadline has passed
+func (u *RFQUseCase) Quotations(ctx context.Context, id string) ([]*models.Quotation, error) {
+	rfq, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rfq.Sealed {
+		return nil, errRFQSealed
+	}
+	quotations, err := u.quotationRepository.ListByRFQ(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quotations: %w", err)
+	}
+	return quotations, nil
+}
+
+// Compare lays the quotations of an RFQ side by side per line once its deadline has passed.
+// Vendors quoting every line come first, cheapest first.
+func (u *RFQUseCase) Compare(ctx context.Context, id string) (*models.QuotationComparison, error) {
+	rfq, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rfq.Sealed {
+		return nil, errRFQSealed
+	}
+	quotations, err := u.quotationRepository.ListByRFQ(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quotations: %w", err)
+	}
+
+	comparison := &models.QuotationComparison{RFQ: rfq, Vendors: []*models.QuotationSummary{}}
+	offers := map[string][]*models.ComparisonOffer{}
+	for _, quotation := range quotations {
+		comparison.Vendors = append(comparison.Vendors, &models.QuotationSummary{
+			QuotationID: quotation.ID,
+			VendorID:    quotation.VendorID,
+			VendorName:  quotation.VendorName,
+			TotalAmount: quotation.TotalAmount,
+			LinesQuoted: len(quotation.Lines),
+			Complete:    len(quotation.Lines) == len(rfq.Lines),
+			SubmittedAt: quotation.SubmittedAt,
+		})
+		for _, line := range quotation.Lines {
+			offers[line.RFQLineID] = append(offers[line.RFQLineID], &models.ComparisonOffer{
+				QuotationID:  quotation.ID,
+				VendorID:     quotation.VendorID,
+				VendorName:   quotation.VendorName,
+				UnitPrice:    line.UnitPrice,
+				Amount:       line.Amount,
+				LeadTimeDays: line.LeadTimeDays,
+				ValidUntil:   line.ValidUntil,
+			})
+		}
+	}
+	sort.SliceStable(comparison.Vendors, func(i, j int) bool {
+		a, b := comparison.Vendors[i], comparison.Vendors[j]
+		if a.Complete != b.Complete {
+			return a.Complete
+		}
+		return a.TotalAmount < b.TotalAmount
+	})
+
+	for _, line := range rfq.Lines {
+		lineOffers := offers[line.ID]
+		sort.SliceStable(lineOffers, func(i, j int) bool { return lineOffers[i].UnitPrice < lineOffers[j].UnitPrice })
+		for _, offer := range lineOffers {
+			offer.Lowest = offer.UnitPrice == lineOffers[0].UnitPrice
+			offer.Fastest = true
+			for _, other := range lineOffers {
+				if other.LeadTimeDays < offer.LeadTimeDays {
+					offer.Fastest = false
+				}
+			}
+		}
+		if lineOffers == nil {
+			lineOffers = []*models.ComparisonOffer{}
+		}
+		comparison.Lines = append(comparison.Lines, &models.ComparisonLine{Line: line, Offers: lineOffers})
+	}
+	return comparison, nil
+}
+
+// Invitations returns a page of the published RFQs the caller's vendor is invited to
+func (u *RFQUseCase) Invitations(ctx context.Context, status string, limit, page int) ([]*models.RFQ, int, error) {
+	vendor, err := u.callerVendor(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return u.list(ctx, models.RFQFilter{Status: status, VendorID: vendor.ID}, limit, page)
+}
+
+// Invitation returns a published RFQ the caller's vendor is invited to, without the other
+// invited vendors
+func (u *RFQUseCase) Invitation(ctx context.Context, id string) (*models.RFQ, error) {
+	vendor, err := u.callerVendor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rfq, err := u.invitation(ctx, id, vendor.ID)
+	if err != nil {
+		return nil, err
+	}
+	rfq.Vendors = nil
+	return rfq, nil
+}
+
+// SubmitQuotation stores the quotation of the caller's vendor, replacing an earlier one,
+// while the RFQ is open. Each line must be valid at least until the response deadline.
+func (u *RFQUseCase) SubmitQuotation(ctx context.Context, id string, req *models.QuotationRequest) (*models.Quotation, error) {
+	vendor, err := u.callerVendor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rfq, err := u.invitation(ctx, id, vendor.ID)
+	if err != nil {
+		return nil, err
+	}
+	if rfq.Status != models.RFQStatusOpen || !rfq.Sealed {
+		return nil, errRFQClosed
+	}
+
+	rfqLines := make(map[string]*models.RFQLine, len(rfq.Lines))
+	for _, line := range rfq.Lines {
+		rfqLines[line.ID] = line
+	}
+	deadline := rfq.ResponseDeadline.Truncate(24 * time.Hour)
+	quotation := &models.Quotation{RFQID: id, VendorID: vendor.ID, Notes: req.Notes}
+	quoted := map[string]bool{}
+	for i, lineReq := range req.Lines {
+		rfqLine, ok := rfqLines[lineReq.RFQLineID]
+		if !ok {
+			return nil, apperror.Validation("unknown_rfq_line", fmt.Sprintf("line %d: RFQ line %s is not part of the RFQ", i+1, lineReq.RFQLineID))
+		}
+		if quoted[lineReq.RFQLineID] {
+			return nil, apperror.Validation("duplicate_rfq_line", fmt.Sprintf("line %d: RFQ line %d is quoted twice", i+1, rfqLine.LineNo))
+		}
+		quoted[lineReq.RFQLineID] = true
+		validUntil, err := time.Parse(time.DateOnly, lineReq.ValidUntil)
+		if err != nil {
+			return nil, apperror.Validation("invalid_valid_until", fmt.Sprintf("line %d: valid_until must be formatted as YYYY-MM-DD", i+1))
+		}
+		if validUntil.Before(deadline) {
+			return nil, apperror.Validation("quotation_validity_too_short", fmt.Sprintf("line %d: the quotation must be valid at least until the response deadline", i+1))
+		}
+		line := &models.QuotationLine{
+			RFQLineID:    lineReq.RFQLineID,
+			UnitPrice:    lineReq.UnitPrice,
+			Amount:       rfqLine.Quantity * lineReq.UnitPrice,
+			LeadTimeDays: lineReq.LeadTimeDays,
+			ValidUntil:   validUntil,
+		}
+		quotation.TotalAmount += line.Amount
+		quotation.Lines = append(quotation.Lines, line)
+	}
+
+	saved, err := u.quotationRepository.Save(ctx, quotation)
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		return nil, errRFQClosed
+	}
+	return u.quotationRepository.GetByVendor(ctx, id, vendor.ID)
+}
+
+// MyQuotation returns the quotation the caller's vendor submitted for an RFQ
+func (u *RFQUseCase) MyQuotation(ctx context.Context, id string) (*models.Quotation, error) {
+	vendor, err := u.callerVendor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.invitation(ctx, id, vendor.ID); err != nil {
+		return nil, err
+	}
+	quotation, err := u.quotationRepository.GetByVendor(ctx, id, vendor.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quotation: %w", err)
+	}
+	if quotation == nil {
+		return nil, apperror.NotFound("quotation_not_found", "you have not submitted a quotation for this RFQ")
+	}
+	return quotation, nil
+}
+
+func (u *RFQUseCase) list(ctx context.Context, filter models.RFQFilter, limit, page int) ([]*models.RFQ, int, error) {
+	limit, offset, err := pageOffset(limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	rfqs, count, err := u.rfqRepository.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list rfqs: %w", err)
+	}
+	for _, rfq := range rfqs {
+		seal(rfq)
+	}
+	return rfqs, count, nil
+}
+
+// transition moves the RFQ out of status from, a concurrent change is reported as a conflict
+func (u *RFQUseCase) transition(ctx context.Context, id, from string, transition *models.RFQTransition) (*models.RFQ, error) {
+	changed, err := u.rfqRepository.Transition(ctx, id, from, transition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update rfq status: %w", err)
+	}
+	if !changed {
+		return nil, errRFQStatusChange
+	}
+	return u.Get(ctx, id)
+}
+
+// callerVendor returns the vendor profile of the caller, quotations are submitted on its behalf
+func (u *RFQUseCase) callerVendor(ctx context.Context) (*models.Vendor, error) {
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vendor, err := u.vendorRepository.GetVendorByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user vendor: %w", err)
+	}
+	if vendor == nil {
+		return nil, apperror.Forbidden("vendor_profile_required", "only vendor users can respond to RFQs")
+	}
+	return vendor, nil
+}
+
+// invitation returns the RFQ when it is published and the vendor is invited, other
+// RFQs are reported as not found so vendors cannot probe for them
+func (u *RFQUseCase) invitation(ctx context.Context, id, vendorID string) (*models.RFQ, error) {
+	rfq, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rfq.Status == models.RFQStatusDraft || !rfqInvites(rfq, vendorID) {
+		return nil, apperror.NotFound("rfq_not_found", fmt.Sprintf("rfq with ID %s not found", id))
+	}
+	return rfq, nil
+}
+
+// checkVendors reports the first vendor that does not exist in the organization or has not
+// passed onboarding approval
+func (u *RFQUseCase) checkVendors(ctx context.Context, vendorIDs []string) error {
+	for _, vendorID := range vendorIDs {
+		// the vendor repositories report a missing vendor as a not found error
+		vendor, err := u.vendorRepository.GetVendorByID(ctx, vendorID)
+		if err != nil && !apperror.IsKind(err, apperror.KindNotFound) {
+			return fmt.Errorf("failed to get vendor: %w", err)
+		}
+		if vendor == nil {
+			return apperror.Validation("unknown_vendor", fmt.Sprintf("vendor with ID %s not found", vendorID))
+		}
+		if err := requireApproved(vendor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// build turns the request into an RFQ. Without lines the lines are copied from the
+// approved requisition the RFQ sources.
+func (u *RFQUseCase) build(ctx context.Context, req *models.RFQRequest) (*models.RFQ, error) {
+	rfq := &models.RFQ{
+		RequisitionID:    req.RequisitionID,
+		Title:            req.Title,
+		Description:      req.Description,
+		ResponseDeadline: req.ResponseDeadline,
+	}
+	if err := u.checkVendors(ctx, req.VendorIDs); err != nil {
+		return nil, err
+	}
+	for _, vendorID := range req.VendorIDs {
+		rfq.Vendors = append(rfq.Vendors, &models.RFQVendor{VendorID: vendorID})
+	}
+
+	if req.RequisitionID != nil {
+		requisition, err := u.requisitionRepository.GetByID(ctx, *req.RequisitionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get requisition: %w", err)
+		}
+		if requisition == nil {
+			return nil, apperror.Validation("unknown_requisition", fmt.Sprintf("requisition with ID %s not found", *req.RequisitionID))
+		}
+		if requisition.Status != models.RequisitionStatusApproved {
+			return nil, apperror.Validation("requisition_not_approved", "only approved requisitions can be sourced through an RFQ")
+		}
+		if len(req.Lines) == 0 {
+			for _, line := range requisition.Lines {
+				neededBy := line.NeededBy
+				rfq.Lines = append(rfq.Lines, &models.RFQLine{
+					ProductID:   line.ProductID,
+					Description: line.Description,
+					Quantity:    line.Quantity,
+					Unit:        line.Unit,
+					NeededBy:    &neededBy,
+				})
+			}
+			return rfq, nil
+		}
+	}
+	if len(req.Lines) == 0 {
+		return nil, apperror.Validation("rfq_lines_required", "an RFQ needs lines or an approved requisition to copy them from")
+	}
+
+	for i, lineReq := range req.Lines {
+		line := &models.RFQLine{
+			ProductID:   lineReq.ProductID,
+			Description: lineReq.Description,
+			Quantity:    lineReq.Quantity,
+			Unit:        lineReq.Unit,
+		}
+		if line.Unit == "" {
+			line.Unit = defaultRequisitionUnit
+		}
+		if lineReq.NeededBy != "" {
+			neededBy, err := time.Parse(time.DateOnly, lineReq.NeededBy)
+			if err != nil {
+				return nil, apperror.Validation("invalid_needed_by", fmt.Sprintf("line %d: needed_by must be formatted as YYYY-MM-DD", i+1))
+			}
+			line.NeededBy = &neededBy
+		}
+		if line.ProductID != nil {
+			// the product repositories report a missing product as a not found error
+			product, err := u.productRepository.GetProductByID(ctx, *line.ProductID)
+			if err != nil && !apperror.IsKind(err, apperror.KindNotFound) {
+				return nil, fmt.Errorf("failed to get product: %w", err)
+			}
+			if product == nil {
+				return nil, apperror.Validation("unknown_product", fmt.Sprintf("line %d: product with ID %s not found", i+1, *line.ProductID))
+			}
+			if line.Description == "" {
+				line.Description = product.ProductName
+			}
+		}
+		rfq.Lines = append(rfq.Lines, line)
+	}
+	return rfq, nil
+}
+
+// seal marks the RFQ sealed while its response deadline has not passed
+func seal(rfq *models.RFQ) *models.RFQ {
+	rfq.Sealed = time.Now().Before(rfq.ResponseDeadline)
+	return rfq
+}
+
+// rfqInvites reports whether the vendor is invited to the RFQ
+func rfqInvites(rfq *models.RFQ, vendorID string) bool {
+	for _, vendor := range rfq.Vendors {
+		if vendor.VendorID == vendorID {
+			return true
+		}
+	}
+	return false
+}
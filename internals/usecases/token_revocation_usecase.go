@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/auth"
+	"e-procurement/pkg/cache"
+	"fmt"
+	"time"
+)
+
+// upper bound of cached tokens and users, the cache is cleared when it is full
+const revocationCacheSize = 10000
+
+// TokenRevocationUseCase decides whether an access token is still usable.
+// Lookups are cached in process for cacheTTL, so a revocation made by another
+// instance of the API takes up to cacheTTL to be seen here.
+type TokenRevocationUseCase struct {
+	repo             repository.TokenRevocationRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	cacheTTL         time.Duration
+	// jti -> revoked
+	tokens *cache.TTL[string, bool]
+	// user ID -> session cut-off, the zero time means never revoked
+	users *cache.TTL[string, time.Time]
+}
+
+var _ auth.RevocationChecker = (*TokenRevocationUseCase)(nil)
+
+func NewTokenRevocationUseCase(
+	repo repository.TokenRevocationRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	cacheTTL time.Duration,
+	) *TokenRevocationUseCase {
+	return &TokenRevocationUseCase{
+		repo:             repo,
+		refreshTokenRepo: refreshTokenRepo,
+		cacheTTL:         cacheTTL,
+		tokens:           cache.NewTTL[string, bool](revocationCacheSize),
+		users:            cache.NewTTL[string, time.Time](revocationCacheSize),
+	}
+}
+
+// IsRevoked reports whether the token was revoked on its own or through a
+// "revoke all sessions" of its user
+func (u *TokenRevocationUseCase) IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error) {
+	revokedBefore, err := u.revokedBefore(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	// iat has millisecond precision, a token issued in the same millisecond as the cut-off
+	// is kept so tokens issued right after revoking (e.g. on MFA enrollment) stay valid
+	if !revokedBefore.IsZero() && issuedAt.Before(revokedBefore.Truncate(time.Millisecond)) {
+		return true, nil
+	}
+
+	if revoked, ok := u.tokens.Get(tokenID); ok {
+		return revoked, nil
+	}
+	revoked, err := u.repo.IsTokenRevoked(ctx, tokenID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	u.tokens.Set(tokenID, revoked, u.cacheTTL)
+	return revoked, nil
+}
+
+func (u *TokenRevocationUseCase) revokedBefore(ctx context.Context, userID string) (time.Time, error) {
+	if revokedBefore, ok := u.users.Get(userID); ok {
+		return revokedBefore, nil
+	}
+	stored, err := u.repo.GetRevokedBefore(ctx, userID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get session revocation: %w", err)
+	}
+	var revokedBefore time.Time
+	if stored != nil {
+		revokedBefore = *stored
+	}
+	u.users.Set(userID, revokedBefore, u.cacheTTL)
+	return revokedBefore, nil
+}
+
+// RevokeToken revokes a single access token until it expires
+func (u *TokenRevocationUseCase) RevokeToken(ctx context.Context, tokenID, userID string, expiresAt time.Time) error {
+	err := u.repo.RevokeToken(ctx, &models.RevokedToken{
+		TokenID:   tokenID,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	// a revoked token never comes back, keep it cached for its remaining lifetime
+	u.tokens.Set(tokenID, true, time.Until(expiresAt))
+	return nil
+}
+
+// RevokeAllForUser logs the user out everywhere: every access token issued until now
+// is rejected and every refresh token is revoked
+func (u *TokenRevocationUseCase) RevokeAllForUser(ctx context.Context, userID string) error {
+	now := time.Now()
+	if err := u.repo.RevokeAllForUser(ctx, userID, now); err != nil {
+		return fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+	u.users.Delete(userID)
+
+	if err := u.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
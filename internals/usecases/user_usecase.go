@@ -3,40 +3,80 @@ package usecases
 import (
 	"context"
 	"e-procurement/internals/domain/models"
-	"e-procurement/internals/repositories"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
 	customContext "e-procurement/pkg/context"
-	"e-procurement/pkg/encripted"
+	"e-procurement/pkg/rbac"
 	"fmt"
 )
 
+func errUserNotFound(userID string) error {
+	return apperror.NotFound("user_not_found", fmt.Sprintf("user with ID %s does not exist", userID))
+}
+
 type UserUseCase struct {
-	userRepository *repositories.UserRepository
-	encripted *encripted.Encripted
+	userRepository repository.UserRepository
+	organizationRepository repository.OrganizationRepository
+	revocations *TokenRevocationUseCase
+	verification *EmailVerificationUseCase
+	passwords *PasswordManager
 }
 
-func NewUserUseCase(userRepo *repositories.UserRepository) *UserUseCase {
+func NewUserUseCase(
+	userRepo repository.UserRepository,
+	organizationRepo repository.OrganizationRepository,
+	revocations *TokenRevocationUseCase,
+	verification *EmailVerificationUseCase,
+	passwords *PasswordManager,
+	) *UserUseCase {
 	return &UserUseCase{
 		userRepository: userRepo,
-		encripted: encripted.NewEncripted(),
+		organizationRepository: organizationRepo,
+		revocations: revocations,
+		verification: verification,
+		passwords: passwords,
+	}
+}
+
+// member returns the membership of a user in the organization of the request,
+// users of other organizations are reported as not found
+func (u *UserUseCase) member(ctx context.Context, userID string) (*models.OrganizationMembership, error) {
+	tenantID, err := customContext.GetTenantFromContext(ctx)
+	if err != nil {
+		return nil, err
 	}
+	membership, err := u.organizationRepository.GetMembership(ctx, tenantID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get membership: %w", err)
+	}
+	if membership == nil {
+		return nil, errUserNotFound(userID)
+	}
+	return membership, nil
 }
 
-// Method to get user details by ID
+// Method to get user details by ID, the role is the one in the organization of the request
 func (u *UserUseCase) GetUserByID(ctx context.Context,userID string)(*models.UserResponse, error) {
+	membership, err := u.member(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
 	user, err := u.userRepository.GetUserByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by ID: %w", err)
 	}
 
 	if user == nil {
-		return nil, fmt.Errorf("user with ID %s does not exist", userID)
+		return nil, errUserNotFound(userID)
 	}
 
 	userResponse := &models.UserResponse{
 		ID:        		user.ID,
 		UserName:      	user.UserName,
 		Email:     		user.Email,
-		Role:      		user.Role,
+		Role:      		membership.Role,
+		OrganizationID: membership.OrganizationID,
+		EmailVerifiedAt: user.EmailVerifiedAt,
 		CreatedAt: 		user.CreatedAt,
 		UpdatedAt: 		user.UpdatedAt,
 	}
@@ -55,7 +95,7 @@ func (u *UserUseCase) UpdateUser(ctx context.Context, userReq *models.UpdateUser
 		return nil, fmt.Errorf("failed to get user by ID: %w", err)
 	}
 	if exitsUser == nil {
-		return nil, fmt.Errorf("user with ID %s does not exist", userID)
+		return nil, errUserNotFound(userID)
 	}
 
 	if userReq.UserName == "" {
@@ -64,7 +104,13 @@ func (u *UserUseCase) UpdateUser(ctx context.Context, userReq *models.UpdateUser
 	if userReq.Email == "" {
 		userReq.Email = exitsUser.Email
 	}
-	if userReq.Password == "" {
+	passwordChanged := userReq.Password != ""
+	if passwordChanged {
+		userReq.Password, err = u.passwords.Prepare(ctx, userID, userReq.Password, userReq.UserName, userReq.Email)
+		if err != nil {
+			return nil, err
+		}
+	} else {
 		userReq.Password = exitsUser.Password
 	}
 
@@ -72,6 +118,20 @@ func (u *UserUseCase) UpdateUser(ctx context.Context, userReq *models.UpdateUser
 	if err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
+	if passwordChanged {
+		if err := u.passwords.Remember(ctx, userID, userReq.Password); err != nil {
+			return nil, err
+		}
+		if err := u.revocations.RevokeAllForUser(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	// a changed email address has to be verified again
+	if user.Email != exitsUser.Email {
+		if err := u.verification.SendVerification(ctx, user.ID, user.UserName, user.Email); err != nil {
+			return nil, err
+		}
+	}
 
 	userUpdateResponse := &models.UpdateUserResponse{
 		ID:        		user.ID,
@@ -84,14 +144,35 @@ func (u *UserUseCase) UpdateUser(ctx context.Context, userReq *models.UpdateUser
 	return userUpdateResponse, nil
 }
 
-// methot to delete user by ID
+// methot to delete user by ID. accounts are shared between organizations, a user that
+// also belongs to another organization can only be removed from this one.
 func (u *UserUseCase) DeleteUser(ctx context.Context,userID string) error {
+	membership, err := u.member(ctx, userID)
+	if err != nil {
+		return err
+	}
+	memberships, err := u.organizationRepository.ListForUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list organizations: %w", err)
+	}
+	for _, other := range memberships {
+		if other.OrganizationID != membership.OrganizationID {
+			return apperror.Conflict("user_in_other_organization",
+				"user belongs to other organizations, remove the membership instead")
+		}
+	}
+
 	existingUser, err := u.userRepository.GetUserByID(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("failed to get user by ID: %w", err)
 	}
 	if existingUser == nil {
-		return fmt.Errorf("user with ID %s does not exist", userID)
+		return errUserNotFound(userID)
+	}
+
+	// revoke first, a failed revocation must not leave a deleted user with live tokens
+	if err := u.revocations.RevokeAllForUser(ctx, userID); err != nil {
+		return err
 	}
 
 	err = u.userRepository.Delete(ctx, userID)
@@ -114,31 +195,82 @@ func(u *UserUseCase) UpdatePassword(ctx context.Context, userReq *models.ChangeP
 		return fmt.Errorf("failed to get user by ID: %w", err)
 	}
 	if existingUser == nil {
-		return fmt.Errorf("user with ID %s does not exist", userID)
+		return errUserNotFound(userID)
 	}
 
 	// validate old password
-	isValidExistingPassword, err := u.encripted.CheckPasswordHash(existingUser.Password, userReq.OldPassword)
+	isValidExistingPassword, err := u.passwords.Verify(ctx, existingUser.ID, existingUser.Password, userReq.OldPassword)
 	if err != nil {
 		return fmt.Errorf("error checking existing password: %w", err)
 	}
 	if !isValidExistingPassword {
-		return fmt.Errorf("invalid old password")
+		return apperror.Validation("invalid_old_password", "invalid old password")
 	}
-	if userReq.NewPassword == "" {
-		userReq.NewPassword = existingUser.Password
+	// policy and reuse checks, then hash and store the new password
+	if err := u.passwords.Set(ctx, existingUser.ID, userReq.NewPassword, existingUser.UserName, existingUser.Email); err != nil {
+		return err
+	}
+
+	// sessions opened with the old password, including the current one, stop working
+	if err := u.revocations.RevokeAllForUser(ctx, existingUser.ID); err != nil {
+		return err
 	}
-	// hash new password
-	userReq.NewPassword, err = u.encripted.HashPassword(userReq.NewPassword)
+
+	return nil
+}
+
+// method to log a user out of every session. an empty userID revokes the caller's own
+// sessions, revoking another user's sessions requires the user:manage permission
+func (u *UserUseCase) RevokeSessions(ctx context.Context, userID string) error {
+	callerID, err := customContext.GetUserIDFromContext(ctx)
 	if err != nil {
-		return fmt.Errorf("error hashing new password: %w", err)
+		return fmt.Errorf("failed to get user ID from context: %w", err)
+	}
+	if userID == "" {
+		userID = callerID
+	}
+	if userID != callerID {
+		position, err := customContext.GetPositionFromContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get position from context: %w", err)
+		}
+		if !rbac.HasPermission(position, rbac.PermUserManage) {
+			return apperror.Forbidden("permission_denied", "you do not have permission to perform this action")
+		}
+		if _, err := u.member(ctx, userID); err != nil {
+			return err
+		}
 	}
 
-	// proceed to update password
-	err = u.userRepository.UpdatePassword(ctx, existingUser.ID, userReq.NewPassword)
+	existingUser, err := u.userRepository.GetUserByID(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("failed to update password: %w", err)
+		return fmt.Errorf("failed to get user by ID: %w", err)
+	}
+	if existingUser == nil {
+		return errUserNotFound(userID)
 	}
 
-	return nil
-}
\ No newline at end of file
+	return u.revocations.RevokeAllForUser(ctx, userID)
+}
+
+// method to change the role of another user in the organization of the request,
+// only reachable by admins
+func (u *UserUseCase) UpdateUserRole(ctx context.Context, req *models.UpdateUserRoleRequest) (*models.UserResponse, error) {
+	callerID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user ID from context: %w", err)
+	}
+	// prevent admins from locking themselves out
+	if callerID == req.UserID {
+		return nil, apperror.Forbidden("cannot_change_own_role", "cannot change your own role")
+	}
+
+	membership, err := u.member(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.organizationRepository.UpdateMemberRole(ctx, membership.OrganizationID, req.UserID, req.Role); err != nil {
+		return nil, fmt.Errorf("failed to update user role: %w", err)
+	}
+	return u.GetUserByID(ctx, req.UserID)
+}
@@ -3,21 +3,33 @@ package usecases
 import (
 	"context"
 	"e-procurement/internals/domain/models"
-	"e-procurement/internals/repositories"
+	"e-procurement/internals/domain/repository"
+	"e-procurement/pkg/apperror"
 	customContext "e-procurement/pkg/context"
 	"fmt"
 )
 
+// VendorUseCase manages vendor profiles. A new vendor goes through the vendor onboarding
+// approval before it can be invited to RFQs or receive purchase orders.
 type VendorUseCase struct {
-	vendorRepository *repositories.VendorRepository
-	userRepository	*repositories.UserRepository
+	vendorRepository repository.VendorRepository
+	userRepository	repository.UserRepository
+	policy *OwnershipPolicy
+	approvals *ApprovalUseCase
 }
 
+var _ ApprovalHandler = (*VendorUseCase)(nil)
 
-func NewVendorUseCase(vendorRepo *repositories.VendorRepository,userRepo *repositories.UserRepository) *VendorUseCase {
+var errVendorStatusChange = apperror.Conflict("vendor_status_changed", "the vendor status has changed, reload it and try again")
+
+var errBankAccountChange = apperror.Conflict("vendor_bank_account_changed", "the bank account of the vendor has changed, reload it and try again")
+
+func NewVendorUseCase(vendorRepo repository.VendorRepository,userRepo repository.UserRepository,approvals *ApprovalUseCase) *VendorUseCase {
 	return &VendorUseCase{
 		vendorRepository: vendorRepo,
 		userRepository: userRepo,
+		policy: NewOwnershipPolicy(vendorRepo),
+		approvals: approvals,
 	}
 }
 
@@ -37,7 +49,11 @@ func (v *VendorUseCase) CreateVendorUsecase(ctx context.Context, vendorReq *mode
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 	if exitsUser == nil {
-		return nil, fmt.Errorf("user with ID %s does not exist", exitsUser.ID)
+		return nil, errUserNotFound(userIdContext)
+	}
+	// fake accounts must not be able to register vendors
+	if err := RequireVerified(exitsUser); err != nil {
+		return nil, err
 	}
 	// validate user ready vendor
 	userVendorsExists, err := v.vendorRepository.GetVendorByUserID(ctx, exitsUser.ID)
@@ -45,16 +61,37 @@ func (v *VendorUseCase) CreateVendorUsecase(ctx context.Context, vendorReq *mode
 		return nil, fmt.Errorf("failed to get user vendors: %w", err)
 	}
 	if userVendorsExists != nil {	
-		return nil, fmt.Errorf("user already has a vendor")
+		return nil, apperror.Conflict("vendor_already_exists", "user already has a vendor")
+	}
+	if vendorReq.PaymentTerms, err = normalizePaymentTerms(vendorReq.PaymentTerms); err != nil {
+		return nil, err
+	}
+	if err := v.policy.CanSetPaymentTerms(ctx, "", vendorReq.PaymentTerms); err != nil {
+		return nil, err
+	}
+	if err := checkBankAccount(vendorReq.BankCode, vendorReq.BankAccountNumber, vendorReq.BankAccountHolder); err != nil {
+		return nil, err
 	}
 	vendor, err := v.vendorRepository.CreateVendor(ctx,exitsUser.ID, vendorReq)
 	if err != nil {
 		return nil, err
 	}
+	if _, err := v.approvals.Start(ctx, onboardingSubject(vendor)); err != nil {
+		// drop the vendor so the user can register again once the approval rules are fixed
+		if deleteErr := v.vendorRepository.DeleteVendor(ctx, vendor.ID); deleteErr != nil {
+			return nil, fmt.Errorf("failed to remove vendor without onboarding approval: %w", deleteErr)
+		}
+		return nil, err
+	}
 	vendorResponse := &models.CreateVendorResponse{
 	   ID:          vendor.ID,
 	   VendorName:  vendor.VendorName,
 	   Description: vendor.Description,
+	   PaymentTerms: vendor.PaymentTerms,
+	   BankCode: vendor.BankCode,
+	   BankAccountNumber: vendor.BankAccountNumber,
+	   BankAccountHolder: vendor.BankAccountHolder,
+	   Status:      vendor.Status,
 	   UserID:      vendor.UserID,
 	   CreatedAt:   vendor.CreatedAt,
 	   UpdatedAt:   vendor.UpdatedAt,
@@ -75,7 +112,7 @@ func (v *VendorUseCase) GetAllVendors(ctx context.Context, limit, page int) ([]*
 	Offset := (page - 1) * limit
 
 	if limit > 100  || Offset > 100 {
-		return nil, 0, fmt.Errorf("limit and offset must be less than or equal to 100")
+		return nil, 0, apperror.Validation("invalid_pagination", "limit and offset must be less than or equal to 100")
 	}
 	// Query total count
 	count, err := v.vendorRepository.CountVendors(ctx)
@@ -96,12 +133,19 @@ func (v *VendorUseCase) GetAllVendors(ctx context.Context, limit, page int) ([]*
 			ID:          vendor.ID,
 			VendorName:  vendor.VendorName,
 			Description: vendor.Description,
+			PaymentTerms: vendor.PaymentTerms,
+			BankCode: vendor.BankCode,
+			BankAccountNumber: vendor.BankAccountNumber,
+			BankAccountHolder: vendor.BankAccountHolder,
+			Status:      vendor.Status,
 			UserID:      vendor.UserID,
 			UserName:    vendor.UserName,
 			CreatedAt:   vendor.CreatedAt,
 			UpdatedAt:   vendor.UpdatedAt,
 		})
-		
+		if err := v.hideBankAccount(ctx, vendor, vendorResponses[len(vendorResponses)-1]); err != nil {
+			return nil, 0, err
+		}
 	}
 	return vendorResponses, count, nil
 }
@@ -109,7 +153,7 @@ func (v *VendorUseCase) GetAllVendors(ctx context.Context, limit, page int) ([]*
 // Method to Get Vendor by ID
 func (v *VendorUseCase) GetVendorByID(ctx context.Context, id string) (*models.VendorResponse, error) {
 	if id == "" {
-		return nil, fmt.Errorf("vendor ID is required")
+		return nil, apperror.Validation("vendor_id_required", "vendor ID is required")
 	}
 
 	vendor, err := v.vendorRepository.GetVendorByID(ctx, id)
@@ -121,15 +165,37 @@ func (v *VendorUseCase) GetVendorByID(ctx context.Context, id string) (*models.V
 		ID:          vendor.ID,
 		VendorName:  vendor.VendorName,
 		Description: vendor.Description,
+		PaymentTerms: vendor.PaymentTerms,
+		BankCode: vendor.BankCode,
+		BankAccountNumber: vendor.BankAccountNumber,
+		BankAccountHolder: vendor.BankAccountHolder,
+		Status:      vendor.Status,
 		UserID:      vendor.UserID,
 		UserName:    vendor.UserName,
 		CreatedAt:   vendor.CreatedAt,
 		UpdatedAt:   vendor.UpdatedAt,
 	}
+	if err := v.hideBankAccount(ctx, vendor, vendorResponse); err != nil {
+		return nil, err
+	}
 
 	return vendorResponse, nil
 }
 
+// hideBankAccount clears the bank account of the response unless the caller may read it
+func (v *VendorUseCase) hideBankAccount(ctx context.Context, vendor *models.Vendor, response *models.VendorResponse) error {
+	allowed, err := v.policy.CanReadBankAccount(ctx, vendor)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		response.BankCode = ""
+		response.BankAccountNumber = ""
+		response.BankAccountHolder = ""
+	}
+	return nil
+}
+
 // Method to Update Vendor
 func (v *VendorUseCase) UpdateVendor(ctx context.Context, id string, vendorReq *models.UpdateVendorRequest) (*models.UpdateVendorResponse, error) {
 	existingVendor, err := v.vendorRepository.GetVendorByID(ctx, id)
@@ -137,7 +203,13 @@ func (v *VendorUseCase) UpdateVendor(ctx context.Context, id string, vendorReq *
 		return nil, fmt.Errorf("vendor not found: %w", err)
 	}
 	if existingVendor == nil {
-		return nil, fmt.Errorf("vendor with ID %s does not exist", id)
+		return nil, apperror.NotFound("vendor_not_found", fmt.Sprintf("vendor with ID %s does not exist", id))
+	}
+	if err := v.policy.CanManageVendor(ctx, existingVendor); err != nil {
+		return nil, err
+	}
+	if err := v.policy.CanTransferVendor(ctx, existingVendor, vendorReq.UserID); err != nil {
+		return nil, err
 	}
 	if vendorReq.VendorName == "" {
 		vendorReq.VendorName = existingVendor.VendorName
@@ -148,16 +220,49 @@ func (v *VendorUseCase) UpdateVendor(ctx context.Context, id string, vendorReq *
 	if vendorReq.UserID == "" {
 		vendorReq.UserID = existingVendor.UserID
 	}
+	if vendorReq.PaymentTerms == "" {
+		vendorReq.PaymentTerms = existingVendor.PaymentTerms
+	} else if vendorReq.PaymentTerms, err = normalizePaymentTerms(vendorReq.PaymentTerms); err != nil {
+		return nil, err
+	}
+	if err := v.policy.CanSetPaymentTerms(ctx, existingVendor.PaymentTerms, vendorReq.PaymentTerms); err != nil {
+		return nil, err
+	}
+	// the bank account is replaced as a whole, leaving it out keeps the current one
+	if vendorReq.BankCode == "" && vendorReq.BankAccountNumber == "" && vendorReq.BankAccountHolder == "" {
+		vendorReq.BankCode = existingVendor.BankCode
+		vendorReq.BankAccountNumber = existingVendor.BankAccountNumber
+		vendorReq.BankAccountHolder = existingVendor.BankAccountHolder
+	} else if err := checkBankAccount(vendorReq.BankCode, vendorReq.BankAccountNumber, vendorReq.BankAccountHolder); err != nil {
+		return nil, err
+	}
+	if err := v.policy.CanSetBankAccount(ctx, existingVendor, vendorReq.BankCode, vendorReq.BankAccountNumber, vendorReq.BankAccountHolder); err != nil {
+		return nil, err
+	}
+	if _, err := v.changeBankAccount(ctx, existingVendor, vendorReq.BankCode, vendorReq.BankAccountNumber, vendorReq.BankAccountHolder); err != nil {
+		return nil, err
+	}
 
 	updatedVendor, err := v.vendorRepository.UpdateVendor(ctx, id, vendorReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update vendor: %w", err)
 	}
+	// a vendor returned for revision goes back to onboarding approval with the revised profile
+	if updatedVendor.Status == models.VendorStatusReturned {
+		if updatedVendor, err = v.resubmit(ctx, updatedVendor); err != nil {
+			return nil, err
+		}
+	}
 
 	vendorResponse := &models.UpdateVendorResponse{
 		ID:          updatedVendor.ID,
 		VendorName:  updatedVendor.VendorName,
 		Description: updatedVendor.Description,
+		PaymentTerms: updatedVendor.PaymentTerms,
+		BankCode: updatedVendor.BankCode,
+		BankAccountNumber: updatedVendor.BankAccountNumber,
+		BankAccountHolder: updatedVendor.BankAccountHolder,
+		Status:      updatedVendor.Status,
 		UserID:      updatedVendor.UserID,
 		CreatedAt:   updatedVendor.CreatedAt,
 		UpdatedAt:   updatedVendor.UpdatedAt,
@@ -165,12 +270,160 @@ func (v *VendorUseCase) UpdateVendor(ctx context.Context, id string, vendorReq *
 	return vendorResponse, nil
 }
 
+// method to replace the bank account of a vendor, for admins and finance who pay the vendor
+func (v *VendorUseCase) ChangeBankAccount(ctx context.Context, id string, req *models.VendorBankAccountRequest) (*models.VendorBankAccountChange, error) {
+	vendor, err := v.vendorRepository.GetVendorByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vendor: %w", err)
+	}
+	if err := v.policy.CanSetBankAccount(ctx, vendor, req.BankCode, req.BankAccountNumber, req.BankAccountHolder); err != nil {
+		return nil, err
+	}
+	return v.changeBankAccount(ctx, vendor, req.BankCode, req.BankAccountNumber, req.BankAccountHolder)
+}
+
+// changeBankAccount replaces the bank account of the vendor with the one in the request and records
+// the old and new account with the user changing it
+// the account stays as it is when it does not change, nothing is recorded then
+func (v *VendorUseCase) changeBankAccount(ctx context.Context, vendor *models.Vendor, code, number, holder string) (*models.VendorBankAccountChange, error) {
+	if code == vendor.BankCode && number == vendor.BankAccountNumber && holder == vendor.BankAccountHolder {
+		return nil, nil
+	}
+	userID, err := customContext.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	change := &models.VendorBankAccountChange{
+		VendorID:             vendor.ID,
+		OldBankCode:          vendor.BankCode,
+		OldBankAccountNumber: vendor.BankAccountNumber,
+		OldBankAccountHolder: vendor.BankAccountHolder,
+		BankCode:             code,
+		BankAccountNumber:    number,
+		BankAccountHolder:    holder,
+		ChangedBy:            &userID,
+	}
+	changed, err := v.vendorRepository.ChangeBankAccount(ctx, change)
+	if err != nil {
+		return nil, fmt.Errorf("failed to change bank account: %w", err)
+	}
+	if !changed {
+		return nil, errBankAccountChange
+	}
+	return change, nil
+}
+
+// method to list the bank account changes of a vendor, for those who may read its bank account
+func (v *VendorUseCase) ListBankAccountChanges(ctx context.Context, id string) ([]*models.VendorBankAccountChange, error) {
+	vendor, err := v.vendorRepository.GetVendorByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vendor: %w", err)
+	}
+	allowed, err := v.policy.CanReadBankAccount(ctx, vendor)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, apperror.Forbidden("bank_account_forbidden", "only the owner, admins and finance can see the bank account of a vendor")
+	}
+	changes, err := v.vendorRepository.ListBankAccountChanges(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bank account changes: %w", err)
+	}
+	return changes, nil
+}
+
 // method to delete a vendor
 func (v *VendorUseCase) DeleteVendor(ctx context.Context, id string) error {
-	err := v.vendorRepository.DeleteVendor(ctx, id)
+	existingVendor, err := v.vendorRepository.GetVendorByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get vendor: %w", err)
+	}
+	if err := v.policy.CanManageVendor(ctx, existingVendor); err != nil {
+		return err
+	}
+
+	err = v.vendorRepository.DeleteVendor(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete vendor: %w", err)
 	}
+	if existingVendor.Status == models.VendorStatusPending {
+		if err := v.approvals.Cancel(ctx, models.ApprovalDocumentVendorOnboarding, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApprovalCompleted applies the outcome of the vendor onboarding approval, a vendor returned
+// for revision is submitted again when its profile is updated. A vendor already in the
+// resulting status was completed before and is left alone.
+func (v *VendorUseCase) ApprovalCompleted(ctx context.Context, request *models.ApprovalRequest, decision *models.ApprovalStep) error {
+	status := models.VendorStatusApproved
+	switch request.Status {
+	case models.ApprovalStatusRejected:
+		status = models.VendorStatusRejected
+	case models.ApprovalStatusReturned:
+		status = models.VendorStatusReturned
+	}
+	changed, err := v.vendorRepository.UpdateVendorStatus(ctx, request.DocumentID, models.VendorStatusPending, status)
+	if err != nil {
+		return fmt.Errorf("failed to update vendor status: %w", err)
+	}
+	if !changed {
+		if current, err := v.vendorRepository.GetVendorByID(ctx, request.DocumentID); err == nil && current != nil && current.Status == status {
+			return nil
+		}
+		return errVendorStatusChange
+	}
+	return nil
+}
+
+// resubmit sends a vendor returned for revision to onboarding approval again
+func (v *VendorUseCase) resubmit(ctx context.Context, vendor *models.Vendor) (*models.Vendor, error) {
+	changed, err := v.vendorRepository.UpdateVendorStatus(ctx, vendor.ID, models.VendorStatusReturned, models.VendorStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update vendor status: %w", err)
+	}
+	if !changed {
+		return nil, errVendorStatusChange
+	}
+	if _, err := v.approvals.Start(ctx, onboardingSubject(vendor)); err != nil {
+		// keep the vendor returned so the update can be retried once the rules are fixed
+		if _, revertErr := v.vendorRepository.UpdateVendorStatus(ctx, vendor.ID, models.VendorStatusPending, models.VendorStatusReturned); revertErr != nil {
+			return nil, fmt.Errorf("failed to revert vendor submission: %w", revertErr)
+		}
+		return nil, err
+	}
+	vendor.Status = models.VendorStatusPending
+	return vendor, nil
+}
+
+// onboardingSubject describes the vendor to the approval engine, on behalf of its owner
+func onboardingSubject(vendor *models.Vendor) *models.ApprovalSubject {
+	return &models.ApprovalSubject{
+		DocumentType: models.ApprovalDocumentVendorOnboarding,
+		DocumentID:   vendor.ID,
+		Title:        vendor.VendorName,
+		RequestedBy:  vendor.UserID,
+	}
+}
+
+// requireApproved rejects trading with a vendor that has not passed onboarding approval
+func requireApproved(vendor *models.Vendor) error {
+	if vendor.Status != models.VendorStatusApproved {
+		return apperror.Conflict("vendor_not_approved", fmt.Sprintf("vendor %s has not passed onboarding approval", vendor.VendorName))
+	}
+	return nil
+}
+	
+// checkBankAccount accepts either no bank account or a complete one, a partial account cannot be paid
+func checkBankAccount(code, number, holder string) error {
+	if code == "" && number == "" && holder == "" {
+		return nil
+	}
+	if code == "" || number == "" || holder == "" {
+		return apperror.Validation("incomplete_bank_account", "bank_code, bank_account_number and bank_account_holder must be given together")
+	}
 	return nil
 }
-	
\ No newline at end of file
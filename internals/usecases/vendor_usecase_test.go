@@ -0,0 +1,172 @@
+package usecases_test
+
+import (
+	"context"
+	"e-procurement/internals/domain/models"
+	"e-procurement/internals/repositories/memory"
+	"e-procurement/internals/usecases"
+	"e-procurement/pkg/apperror"
+	"e-procurement/pkg/rbac"
+	"testing"
+)
+
+func TestCreateVendorUsecase(t *testing.T) {
+	org := newTestOrg(t)
+	owner := org.addUser("owner", rbac.RoleVendor)
+	org.addVendor(owner, "Owner Supply")
+	newcomer := org.addUser("newcomer", rbac.RoleVendor)
+	unverified, err := memory.NewUserRepository(org.store).Create(org.as(owner), &models.CreateUserRequest{
+		UserName: "unverified",
+		Email:    "unverified@example.com",
+		Password: "hashed",
+	})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	vendors := usecases.NewVendorUseCase(memory.NewVendorRepository(org.store), memory.NewUserRepository(org.store), org.approvals())
+
+	tests := []struct {
+		name     string
+		userID   string
+		wantKind apperror.Kind
+		wantCode string
+	}{
+		{name: "first vendor of the user", userID: newcomer},
+		{name: "user already has a vendor", userID: owner, wantKind: apperror.KindConflict, wantCode: "vendor_already_exists"},
+		{name: "second vendor after the first one", userID: newcomer, wantKind: apperror.KindConflict, wantCode: "vendor_already_exists"},
+		{name: "unverified email", userID: unverified.ID, wantKind: apperror.KindForbidden, wantCode: "email_not_verified"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendor, err := vendors.CreateVendorUsecase(org.as(tt.userID), &models.CreateVendorRequest{
+				VendorName:  "Vendor of " + tt.userID,
+				Description: "supplies",
+			})
+			assertAppError(t, err, tt.wantKind, tt.wantCode)
+			if err == nil && vendor.UserID != tt.userID {
+				t.Fatalf("vendor owned by %s, want %s", vendor.UserID, tt.userID)
+			}
+		})
+	}
+}
+
+func TestVendorOnboardingApproval(t *testing.T) {
+	tests := []struct {
+		name string
+		// decide acts on the onboarding approval as the approver
+		decide     func(approvals *usecases.ApprovalUseCase, ctx context.Context, id string) error
+		wantStatus string
+	}{
+		{
+			name: "approved",
+			decide: func(approvals *usecases.ApprovalUseCase, ctx context.Context, id string) error {
+				_, err := approvals.Approve(ctx, id, &models.ApprovalDecisionRequest{})
+				return err
+			},
+			wantStatus: models.VendorStatusApproved,
+		},
+		{
+			name: "rejected",
+			decide: func(approvals *usecases.ApprovalUseCase, ctx context.Context, id string) error {
+				_, err := approvals.Reject(ctx, id, &models.ApprovalDecisionRequest{Comment: "no tax ID"})
+				return err
+			},
+			wantStatus: models.VendorStatusRejected,
+		},
+		{
+			name: "returned for revision",
+			decide: func(approvals *usecases.ApprovalUseCase, ctx context.Context, id string) error {
+				_, err := approvals.Return(ctx, id, &models.ApprovalDecisionRequest{Comment: "add the company address"})
+				return err
+			},
+			wantStatus: models.VendorStatusReturned,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org := newTestOrg(t)
+			owner := org.addUser("owner", rbac.RoleVendor)
+			approver := org.addUser("approver", rbac.RoleApprover)
+			approvals := org.approvals()
+			vendors := usecases.NewVendorUseCase(memory.NewVendorRepository(org.store), memory.NewUserRepository(org.store), approvals)
+			approvals.Register(models.ApprovalDocumentVendorOnboarding, vendors)
+
+			created, err := vendors.CreateVendorUsecase(org.as(owner), &models.CreateVendorRequest{VendorName: "Owner Supply", Description: "supplies"})
+			assertAppError(t, err, 0, "")
+			if created.Status != models.VendorStatusPending {
+				t.Fatalf("new vendor is %s, want %s", created.Status, models.VendorStatusPending)
+			}
+			pending, _, err := approvals.Pending(org.as(approver), 10, 1)
+			assertAppError(t, err, 0, "")
+			if len(pending) != 1 || pending[0].Request.DocumentType != models.ApprovalDocumentVendorOnboarding || pending[0].Request.DocumentID != created.ID {
+				t.Fatalf("approver sees %d pending approvals, want the onboarding of the vendor", len(pending))
+			}
+
+			assertAppError(t, tt.decide(approvals, org.as(approver), pending[0].Request.ID), 0, "")
+			vendor, err := vendors.GetVendorByID(org.as(owner), created.ID)
+			assertAppError(t, err, 0, "")
+			if vendor.Status != tt.wantStatus {
+				t.Fatalf("vendor is %s, want %s", vendor.Status, tt.wantStatus)
+			}
+			if tt.wantStatus != models.VendorStatusReturned {
+				return
+			}
+
+			// the revised profile goes back to the approver, bank account included
+			updated, err := vendors.UpdateVendor(org.as(owner), created.ID, &models.UpdateVendorRequest{
+				VendorName:        "Owner Supply",
+				Description:       "supplies, Jl. Sudirman 1",
+				BankCode:          "014",
+				BankAccountNumber: "1234567890",
+				BankAccountHolder: "PT Owner Supply",
+			})
+			assertAppError(t, err, 0, "")
+			if updated.Status != models.VendorStatusPending || updated.BankAccountNumber != "1234567890" {
+				t.Fatalf("revised vendor is %s with account %q, want %s with the new account", updated.Status, updated.BankAccountNumber, models.VendorStatusPending)
+			}
+			if _, count, _ := approvals.Pending(org.as(approver), 10, 1); count != 1 {
+				t.Fatalf("approver sees %d pending approvals after the revision, want 1", count)
+			}
+		})
+	}
+}
+
+func TestVendorBankAccountChanges(t *testing.T) {
+	org := newTestOrg(t)
+	owner := org.addUser("owner", rbac.RoleVendor)
+	other := org.addUser("other", rbac.RoleVendor)
+	finance := org.addUser("finance", rbac.RoleFinance)
+	vendor := org.addVendor(owner, "Owner Supply")
+	vendors := usecases.NewVendorUseCase(memory.NewVendorRepository(org.store), memory.NewUserRepository(org.store), org.approvals())
+
+	accounts := []models.VendorBankAccountRequest{
+		{BankCode: "014", BankAccountNumber: "1234567890", BankAccountHolder: "PT Owner Supply"},
+		{BankCode: "008", BankAccountNumber: "5550001111", BankAccountHolder: "PT Owner Supply"},
+	}
+	for _, account := range accounts {
+		_, err := vendors.ChangeBankAccount(org.as(finance), vendor.ID, &account)
+		assertAppError(t, err, 0, "")
+	}
+	// the same account again is no change
+	_, err := vendors.ChangeBankAccount(org.as(finance), vendor.ID, &accounts[1])
+	assertAppError(t, err, 0, "")
+
+	changes, err := vendors.ListBankAccountChanges(org.as(owner), vendor.ID)
+	assertAppError(t, err, 0, "")
+	if len(changes) != 2 {
+		t.Fatalf("recorded %d changes, want 2", len(changes))
+	}
+	second := changes[1]
+	if second.OldBankCode != "014" || second.OldBankAccountNumber != "1234567890" ||
+		second.BankCode != "008" || second.BankAccountNumber != "5550001111" {
+		t.Fatalf("second change is %+v, want 014/1234567890 to 008/5550001111", second)
+	}
+	for _, change := range changes {
+		if change.ChangedBy == nil || *change.ChangedBy != finance {
+			t.Fatalf("change recorded by %v, want the finance user", change.ChangedBy)
+		}
+	}
+
+	_, err = vendors.ListBankAccountChanges(org.as(other), vendor.ID)
+	assertAppError(t, err, apperror.KindForbidden, "bank_account_forbidden")
+}
@@ -0,0 +1,132 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Kind classifies a domain error so the delivery layer can pick a status code
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindNotFound
+	KindConflict
+	KindForbidden
+	KindValidation
+	KindUnauthorized
+	KindTooManyRequests
+	KindLocked
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindNotFound:
+		return "not_found"
+	case KindConflict:
+		return "conflict"
+	case KindForbidden:
+		return "forbidden"
+	case KindValidation:
+		return "validation"
+	case KindUnauthorized:
+		return "unauthorized"
+	case KindTooManyRequests:
+		return "too_many_requests"
+	case KindLocked:
+		return "locked"
+	default:
+		return "internal"
+	}
+}
+
+// Error is a domain error carrying a machine readable code (e.g. "product_not_found")
+// and a message that is safe to show to API clients.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Err     error
+	// RetryAfter tells throttled clients when to try again, zero when unknown
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithCause attaches the underlying error, kept for logging only
+func (e *Error) WithCause(err error) *Error {
+	copied := *e
+	copied.Err = err
+	return &copied
+}
+
+// WithRetryAfter attaches how long the client should wait before retrying
+func (e *Error) WithRetryAfter(d time.Duration) *Error {
+	copied := *e
+	copied.RetryAfter = d
+	return &copied
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func NotFound(code, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+func Validation(code, message string) *Error {
+	return New(KindValidation, code, message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+func TooManyRequests(code, message string) *Error {
+	return New(KindTooManyRequests, code, message)
+}
+
+func Locked(code, message string) *Error {
+	return New(KindLocked, code, message)
+}
+
+// As returns the first domain error found in err's chain
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// KindOf returns the kind of the domain error in err's chain, KindInternal otherwise
+func KindOf(err error) Kind {
+	if appErr, ok := As(err); ok {
+		return appErr.Kind
+	}
+	return KindInternal
+}
+
+// IsKind reports whether err carries a domain error of the given kind
+func IsKind(err error, kind Kind) bool {
+	return err != nil && KindOf(err) == kind
+}
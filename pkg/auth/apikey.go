@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// API keys look like ep_<prefix>_<secret>. the prefix is stored in clear to find the key,
+// the secret only as its SHA-256 hash.
+const (
+	apiKeyMarker       = "ep_"
+	apiKeyPrefixLength = 12 // hex characters
+)
+
+// APIKeyPrincipal is the service principal behind a valid API key. it acts as the
+// user owning the key inside the organization the key was created in, limited to the key scopes.
+type APIKeyPrincipal struct {
+	KeyID          string
+	UserID         string
+	OrganizationID string
+	Position       string
+	Scopes         []string
+}
+
+// APIKeyAuthenticator resolves the principal of an `Authorization: ApiKey ...` header
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*APIKeyPrincipal, error)
+}
+
+// GenerateAPIKey returns a new key, its public prefix and the hash of its secret.
+// the key is handed to the client once and never stored.
+func GenerateAPIKey() (string, string, string, error) {
+	b := make([]byte, apiKeyPrefixLength/2)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+	prefix := hex.EncodeToString(b)
+	secret, secretHash, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", "", err
+	}
+	return apiKeyMarker + prefix + "_" + secret, prefix, secretHash, nil
+}
+
+// ParseAPIKey splits a key into its prefix and the hash of its secret
+func ParseAPIKey(key string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(key, apiKeyMarker)
+	if !ok || len(rest) <= apiKeyPrefixLength+1 || rest[apiKeyPrefixLength] != '_' {
+		return "", "", false
+	}
+	return rest[:apiKeyPrefixLength], HashOpaqueToken(rest[apiKeyPrefixLength+1:]), true
+}
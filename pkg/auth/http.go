@@ -4,53 +4,148 @@ import (
 	"context"
 	"e-procurement/pkg/constans"
 	response "e-procurement/pkg/responses"
+	"errors"
+	"math"
 	"net/http"
 	"strings"
 	"time"
 )
 
+// RevocationChecker reports whether an access token was revoked before it expired
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error)
+}
+
 type AuthHttp struct {
 	jwt    *JWT
+	revocations RevocationChecker
+	apiKeys APIKeyAuthenticator
 }
 
-func NewAuthMiddleware(jwt *JWT) *AuthHttp {
-	return &AuthHttp{jwt: jwt}
+// NewAuthMiddleware creates the bearer token middleware, revocations may be nil
+// to skip the revocation list and apiKeys nil to only accept bearer tokens
+func NewAuthMiddleware(jwt *JWT, revocations RevocationChecker, apiKeys APIKeyAuthenticator) *AuthHttp {
+	return &AuthHttp{jwt: jwt, revocations: revocations, apiKeys: apiKeys}
 }
 
 
 func (m *AuthHttp) VerifyToken(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		token, err := extractTokenFromHeader(r)
-		if err != nil {
-			response.Error(w, http.StatusUnauthorized, err.Error())
-			return
-		}
-
-		claims, err := m.jwt.ValidateToken(token)
+		ctx, err := m.authenticate(r)
 		if err != nil {
-			response.Error(w, http.StatusUnauthorized, "Invalid token: "+err.Error())
+			var authErr errorString
+			if errors.As(err, &authErr) {
+				response.Error(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+			// rejected API keys, or the revocation store is unreachable and we fail closed
+			response.FromError(w, err)
 			return
 		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
 
-		if err := validateTokenClaims(claims); err != nil {
-			
-			response.Error(w, http.StatusUnauthorized, err.Error())
+// OptionalToken puts the caller into the context when a valid bearer token is sent,
+// requests without one (or with an expired one) still reach the handler
+func (m *AuthHttp) OptionalToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
 			return
 		}
-
-		userID, position, err := extractUserIDAndPosition(claims)
+		ctx, err := m.authenticate(r)
 		if err != nil {
-
-			response.Error(w, http.StatusUnauthorized, err.Error())
+			next.ServeHTTP(w, r)
 			return
 		}
-		
-		ctx := context.WithValue(r.Context(), constans.ContextUserIDKey, userID)
-		ctx = context.WithValue(ctx, constans.ContextPositionKey, position)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// authenticate validates the bearer token or API key of the request and returns the
+// request context carrying the caller. token problems are returned as errorString.
+func (m *AuthHttp) authenticate(r *http.Request) (context.Context, error) {
+	if key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "ApiKey "); ok && m.apiKeys != nil {
+		return m.authenticateAPIKey(r, key)
+	}
+
+	token, err := extractTokenFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := m.jwt.ValidateToken(token)
+	if err != nil {
+		return nil, errorString("Invalid token: " + err.Error())
+	}
+
+	if err := validateTokenClaims(claims); err != nil {
+		return nil, err
+	}
+
+	// tokens issued before the claim existed carry no token_use and are access tokens
+	if use, ok := claims["token_use"].(string); ok && use != TokenUseAccess {
+		return nil, errorString("Invalid token: not an access token")
+	}
+
+	userID, position, err := extractUserIDAndPosition(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenID, issuedAt, err := extractTokenID(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// tokens issued before organizations existed carry no tenant and have to be renewed
+	tenantID, ok := claims["org_id"].(string)
+	if !ok || tenantID == "" {
+		return nil, errorString("Token missing 'org_id' claim")
+	}
+
+	if m.revocations != nil {
+		revoked, err := m.revocations.IsRevoked(r.Context(), tokenID, userID, issuedAt)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, errorString("Token has been revoked")
+		}
+	}
+
+	exp, _ := claims["exp"].(float64)
+	ctx := context.WithValue(r.Context(), constans.ContextUserIDKey, userID)
+	ctx = context.WithValue(ctx, constans.ContextPositionKey, position)
+	ctx = context.WithValue(ctx, constans.ContextTenantKey, tenantID)
+	ctx = context.WithValue(ctx, constans.ContextTokenIDKey, tokenID)
+	ctx = context.WithValue(ctx, constans.ContextTokenExpiresAtKey, time.Unix(int64(exp), 0))
+	mfa, _ := claims["mfa"].(bool)
+	ctx = context.WithValue(ctx, constans.ContextMFAKey, mfa)
+	ctx = context.WithValue(ctx, constans.ContextPrincipalKey, constans.PrincipalUser)
+	return ctx, nil
+}
+
+// authenticateAPIKey puts the service principal of an API key into the context.
+// the key owner and its role in the organization of the key are used as the caller,
+// the scopes further limit the permissions.
+func (m *AuthHttp) authenticateAPIKey(r *http.Request, key string) (context.Context, error) {
+	// rejected keys come back as apperror Unauthorized and keep their error code
+	principal, err := m.apiKeys.AuthenticateAPIKey(r.Context(), strings.TrimSpace(key))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.WithValue(r.Context(), constans.ContextUserIDKey, principal.UserID)
+	ctx = context.WithValue(ctx, constans.ContextPositionKey, principal.Position)
+	ctx = context.WithValue(ctx, constans.ContextTenantKey, principal.OrganizationID)
+	ctx = context.WithValue(ctx, constans.ContextPrincipalKey, constans.PrincipalService)
+	ctx = context.WithValue(ctx, constans.ContextAPIKeyIDKey, principal.KeyID)
+	ctx = context.WithValue(ctx, constans.ContextScopesKey, principal.Scopes)
+	return ctx, nil
+}
+
 func extractTokenFromHeader(r *http.Request) (string, error) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
@@ -58,7 +153,7 @@ func extractTokenFromHeader(r *http.Request) (string, error) {
 	}
 	token := strings.TrimPrefix(authHeader, "Bearer ")
 	if token == authHeader {
-		return "", errorString("Invalid token format: missing 'Bearer' prefix")
+		return "", errorString("Invalid token format: missing 'Bearer' or 'ApiKey' prefix")
 	}
 	return token, nil
 }
@@ -96,6 +191,19 @@ func extractUserIDAndPosition(claims map[string]interface{}) (string, string, er
 	return userID, position, nil
 }
 
+func extractTokenID(claims map[string]interface{}) (string, time.Time, error) {
+	tokenID, ok := claims["jti"].(string)
+	if !ok || tokenID == "" {
+		return "", time.Time{}, errorString("Token missing 'jti' claim")
+	}
+	iat, ok := claims["iat"].(float64)
+	if !ok {
+		return "", time.Time{}, errorString("Token missing 'iat' claim")
+	}
+	// round, iat*1000 is not exact in floating point
+	return tokenID, time.UnixMilli(int64(math.Round(iat * 1000))), nil
+}
+
 type errorString string
 
 func (e errorString) Error() string {
@@ -1,42 +1,160 @@
 package auth
 
 import (
+	"e-procurement/pkg/identifier"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type JWT struct {
-    jwtSecret []byte
+    signingKey *Key
+    // every key ValidateToken accepts, the signing key included, in configuration order
+    verificationKeys []*Key
+    keysByID map[string]*Key
+    accessTokenTTL time.Duration
 }
 
-// NewJWT initializes a new JWT instance with the provided secret key
-func NewJWT(secret string) *JWT {
-    return &JWT{
-        jwtSecret: []byte(secret),
+// NewJWT initializes a new JWT instance signing with a shared HMAC secret
+// and the lifetime of the access tokens it generates
+func NewJWT(secret string, accessTokenTTL time.Duration) *JWT {
+    j, _ := NewJWTWithKeys(NewHMACKey(secret), nil, accessTokenTTL)
+    return j
+}
+
+// NewJWTWithKeys initializes a JWT instance signing with signingKey. verificationKeys are
+// extra keys still accepted during a rotation, e.g. the previous signing key.
+func NewJWTWithKeys(signingKey *Key, verificationKeys []*Key, accessTokenTTL time.Duration) (*JWT, error) {
+    if signingKey == nil || !signingKey.CanSign() {
+        return nil, errors.New("signing key must hold a private key")
+    }
+    j := &JWT{
+        signingKey: signingKey,
+        keysByID: map[string]*Key{},
+        accessTokenTTL: accessTokenTTL,
+    }
+    for _, key := range append([]*Key{signingKey}, verificationKeys...) {
+        if _, ok := j.keysByID[key.ID]; ok {
+            return nil, fmt.Errorf("duplicate key id %q", key.ID)
+        }
+        j.keysByID[key.ID] = key
+        j.verificationKeys = append(j.verificationKeys, key)
     }
+    return j, nil
+}
+
+// AccessTokenTTL returns how long generated access tokens stay valid
+func (j *JWT) AccessTokenTTL() time.Duration {
+    return j.accessTokenTTL
 }
 
-// GenerateToken generates a JWT token with the given user ID
-func (j *JWT) GenerateToken(userID string,position string) (string, error) {
+// token_use claim values, a challenge token is never accepted as an access token
+const (
+    TokenUseAccess       = "access"
+    TokenUseMFAChallenge = "mfa_challenge"
+)
+
+// GenerateToken generates a JWT token with the given user ID.
+// position is the role of the user in organizationID, the tenant the token acts in.
+// mfa tells whether the user had two-factor authentication enabled when the token was issued.
+func (j *JWT) GenerateToken(userID string, position string, organizationID string, mfa bool) (string, error) {
+    now := time.Now()
     claims := jwt.MapClaims{
+        // jti identifies the token in the revocation list
+        "jti":     identifier.NewUUID(),
         "user_id": userID,
         "position": position,
-        // set expiration time to 3 days
-        "exp":     time.Now().Add(time.Hour * 24 * 3).Unix(),
+        "org_id":  organizationID,
+        "token_use": TokenUseAccess,
+        "mfa":     mfa,
+        // millisecond precision so a login right after "revoke all sessions" is not caught by it
+        "iat":     float64(now.UnixMilli()) / 1000,
+        // short lived, clients renew it with a refresh token
+        "exp":     now.Add(j.accessTokenTTL).Unix(),
+    }
+    return j.sign(claims)
+}
+
+// ChallengeClaims identifies a pending two-step login
+type ChallengeClaims struct {
+    TokenID   string
+    UserID    string
+    // organization the full token will be issued for
+    OrganizationID string
+    IssuedAt  time.Time
+    ExpiresAt time.Time
+}
+
+// GenerateChallengeToken generates the short-lived token returned by the first login step,
+// it only proves the password was checked and must be exchanged together with a TOTP code
+func (j *JWT) GenerateChallengeToken(userID string, organizationID string, ttl time.Duration) (string, error) {
+    now := time.Now()
+    claims := jwt.MapClaims{
+        "jti":       identifier.NewUUID(),
+        "user_id":   userID,
+        "org_id":    organizationID,
+        "token_use": TokenUseMFAChallenge,
+        "iat":       float64(now.UnixMilli()) / 1000,
+        "exp":       now.Add(ttl).Unix(),
     }
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims) // Use HS256 for HMAC
-    return token.SignedString(j.jwtSecret)
+    return j.sign(claims)
 }
 
-// ValidateToken validates a JWT token and returns the claims
+// ValidateChallengeToken validates a token generated by GenerateChallengeToken
+func (j *JWT) ValidateChallengeToken(tokenString string) (*ChallengeClaims, error) {
+    claims, err := j.ValidateToken(tokenString)
+    if err != nil {
+        return nil, err
+    }
+    if use, _ := claims["token_use"].(string); use != TokenUseMFAChallenge {
+        return nil, errors.New("not an mfa challenge token")
+    }
+    userID, _ := claims["user_id"].(string)
+    if userID == "" {
+        return nil, errors.New("token missing 'user_id' claim")
+    }
+    organizationID, _ := claims["org_id"].(string)
+    if organizationID == "" {
+        return nil, errors.New("token missing 'org_id' claim")
+    }
+    tokenID, issuedAt, err := extractTokenID(claims)
+    if err != nil {
+        return nil, err
+    }
+    exp, _ := claims["exp"].(float64)
+    return &ChallengeClaims{
+        TokenID:   tokenID,
+        UserID:    userID,
+        OrganizationID: organizationID,
+        IssuedAt:  issuedAt,
+        ExpiresAt: time.Unix(int64(exp), 0),
+    }, nil
+}
+
+func (j *JWT) sign(claims jwt.MapClaims) (string, error) {
+    token := jwt.NewWithClaims(j.signingKey.method, claims)
+    if j.signingKey.ID != "" {
+        token.Header["kid"] = j.signingKey.ID
+    }
+    return token.SignedString(j.signingKey.private)
+}
+
+// ValidateToken validates a JWT token and returns the claims.
+// the `kid` header selects the verification key, tokens signed with another
+// algorithm than the one of that key are rejected.
 func (j *JWT) ValidateToken(tokenString string) (jwt.MapClaims, error) {
     token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-        if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+        kid, _ := t.Header["kid"].(string)
+        key, ok := j.keysByID[kid]
+        if !ok {
+            return nil, errors.New("unknown signing key")
+        }
+        if t.Method.Alg() != key.Algorithm() {
             return nil, errors.New("unexpected signing method")
         }
-        return j.jwtSecret, nil
+        return key.public, nil
     })
 
     if err != nil {
@@ -50,5 +168,3 @@ func (j *JWT) ValidateToken(tokenString string) (jwt.MapClaims, error) {
 
     return claims, nil
 }
-
-func (j *JWT) RefreshToken()(string, error) {return "", nil}
\ No newline at end of file
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minimum RSA modulus size accepted for RS256 keys
+const minRSAKeyBits = 2048
+
+// Key signs and/or verifies access tokens. Asymmetric keys are identified by the
+// `kid` header of the tokens they sign; verification-only keys have no private part.
+type Key struct {
+	ID      string
+	method  jwt.SigningMethod
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+}
+
+// NewHMACKey wraps a shared secret, HMAC keys are never published in the JWKS
+func NewHMACKey(secret string) *Key {
+	return &Key{
+		method:  jwt.SigningMethodHS256,
+		private: []byte(secret),
+		public:  []byte(secret),
+	}
+}
+
+// Algorithm returns the JWS algorithm of the key (HS256, RS256 or EdDSA)
+func (k *Key) Algorithm() string {
+	return k.method.Alg()
+}
+
+// CanSign reports whether the key holds a private part
+func (k *Key) CanSign() bool {
+	return k.private != nil
+}
+
+// LoadKeyFile reads a PEM encoded RSA or Ed25519 key. Private keys (PKCS#8 or PKCS#1)
+// can sign and verify, public keys (PKIX or PKCS#1) can only verify.
+func LoadKeyFile(id, path string) (*Key, error) {
+	if id == "" {
+		return nil, errors.New("key id is required")
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key %s: %w", id, err)
+	}
+	key, err := parsePEMKey(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key %s (%s): %w", id, path, err)
+	}
+	key.ID = id
+	return key, nil
+}
+
+func parsePEMKey(content []byte) (*Key, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA keys must be at least %d bits", minRSAKeyBits)
+		}
+		return &Key{method: jwt.SigningMethodRS256, private: k, public: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA keys must be at least %d bits", minRSAKeyBits)
+		}
+		return &Key{method: jwt.SigningMethodRS256, public: k}, nil
+	case ed25519.PrivateKey:
+		return &Key{method: jwt.SigningMethodEdDSA, private: k, public: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &Key{method: jwt.SigningMethodEdDSA, public: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, expected RSA or Ed25519", parsed)
+	}
+}
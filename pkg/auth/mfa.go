@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"e-procurement/pkg/constans"
+	response "e-procurement/pkg/responses"
+	"net/http"
+)
+
+// RequireMFA rejects tokens of the given roles that were issued without two-factor
+// authentication, those users have to enroll first. it must run after VerifyToken.
+func RequireMFA(roles []string) func(http.Handler) http.Handler {
+	required := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		required[role] = true
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, _ := r.Context().Value(constans.ContextPositionKey).(string)
+			mfa, _ := r.Context().Value(constans.ContextMFAKey).(bool)
+			// API keys are created from a session that already passed this check
+			service := r.Context().Value(constans.ContextPrincipalKey) == constans.PrincipalService
+			if required[role] && !mfa && !service {
+				response.ErrorWithCode(w, http.StatusForbidden, "mfa_enrollment_required",
+					"role "+role+" must enable two-factor authentication through /auth/mfa/enroll")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
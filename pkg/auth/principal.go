@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"e-procurement/pkg/constans"
+	response "e-procurement/pkg/responses"
+	"net/http"
+)
+
+// RequireUser rejects requests authenticated by an API key, for routes that manage the
+// caller's own credentials (password, sessions, MFA, API keys). it must run after VerifyToken.
+func RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Context().Value(constans.ContextPrincipalKey) == constans.PrincipalService {
+			response.ErrorWithCode(w, http.StatusForbidden, "user_session_required", "this route cannot be called with an api key")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
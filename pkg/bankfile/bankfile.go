@@ -0,0 +1,103 @@
+package bankfile
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Payment is one credit transfer to a payee
+type Payment struct {
+	PayeeID   string
+	PayeeName string
+	// the payee's account the bank credits, all three are required
+	BankCode      string
+	AccountNumber string
+	AccountHolder string
+	Amount        float64
+	Description   string
+}
+
+// Batch is a set of payments the bank executes on the same date
+type Batch struct {
+	Reference   string
+	PaymentDate time.Time
+	Payments    []Payment
+}
+
+// Exporter hands payment batches over to the bank, implementations must be safe for concurrent use
+type Exporter interface {
+	// Export returns a reference to the exported file, for instance its name
+	Export(ctx context.Context, batch Batch) (string, error)
+}
+
+// FileExporter writes every batch as a CSV file into a directory, to be uploaded to online banking
+// or picked up by a bank connector. It keeps payment batches testable without a bank
+type FileExporter struct {
+	dir string
+}
+
+// NewFileExporter creates an exporter writing files into dir, the directory is created when missing
+func NewFileExporter(dir string) (*FileExporter, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create payment export directory: %w", err)
+	}
+	return &FileExporter{dir: dir}, nil
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (e *FileExporter) Export(ctx context.Context, batch Batch) (string, error) {
+	for _, payment := range batch.Payments {
+		if payment.BankCode == "" || payment.AccountNumber == "" || payment.AccountHolder == "" {
+			return "", fmt.Errorf("payee %s has no bank account", payment.PayeeName)
+		}
+	}
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.csv", unsafeFileChars.ReplaceAllString(batch.Reference, "_"), now.Format("20060102T150405.000000000"))
+	file, err := os.OpenFile(filepath.Join(e.dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return "", fmt.Errorf("failed to create payment file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"batch_reference", "payment_date", "payee_id", "payee_name",
+		"payee_bank_code", "payee_account_number", "payee_account_holder", "amount", "description"})
+	for _, payment := range batch.Payments {
+		writer.Write([]string{
+			escapeCell(batch.Reference),
+			batch.PaymentDate.Format(time.DateOnly),
+			escapeCell(payment.PayeeID),
+			escapeCell(payment.PayeeName),
+			escapeCell(payment.BankCode),
+			escapeCell(payment.AccountNumber),
+			escapeCell(payment.AccountHolder),
+			strconv.FormatFloat(payment.Amount, 'f', 2, 64),
+			escapeCell(payment.Description),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("failed to write payment file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write payment file: %w", err)
+	}
+	return name, nil
+}
+
+// escapeCell keeps spreadsheets from evaluating text as a formula when the file is opened for
+// review, a cell starting with one of the formula characters is prefixed with a quote
+func escapeCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// TTL is a small concurrency safe in-process cache whose entries expire
+// after a per-entry lifetime. It holds at most maxItems entries.
+type TTL[K comparable, V any] struct {
+	mu       sync.Mutex
+	items    map[K]entry[V]
+	maxItems int
+	now      func() time.Time
+}
+
+// NewTTL creates an empty cache holding at most maxItems entries
+func NewTTL[K comparable, V any](maxItems int) *TTL[K, V] {
+	return &TTL[K, V]{
+		items:    map[K]entry[V]{},
+		maxItems: maxItems,
+		now:      time.Now,
+	}
+}
+
+// Get returns the cached value, ok is false when the key is missing or expired
+func (c *TTL[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.items[key]
+	if !ok || !c.now().Before(item.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return item.value, true
+}
+
+// Set stores a value for ttl, a non positive ttl leaves the cache untouched
+func (c *TTL[K, V]) Set(key K, value V, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.items[key]; !ok && len(c.items) >= c.maxItems {
+		c.evict()
+	}
+	c.items[key] = entry[V]{value: value, expiresAt: c.now().Add(ttl)}
+}
+
+// Delete removes a key from the cache
+func (c *TTL[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+}
+
+// evict drops expired entries, and everything when the cache is still full
+func (c *TTL[K, V]) evict() {
+	now := c.now()
+	for key, item := range c.items {
+		if !now.Before(item.expiresAt) {
+			delete(c.items, key)
+		}
+	}
+	if len(c.items) >= c.maxItems {
+		c.items = map[K]entry[V]{}
+	}
+}
@@ -0,0 +1,721 @@
+package config
+
+import (
+	"e-procurement/pkg/rbac"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+
+	StoragePostgres = "postgres"
+	// StorageMemory keeps every repository in process memory (demo mode, no Postgres)
+	StorageMemory = "memory"
+
+	SSLModeDisable    = "disable"
+	SSLModeRequire    = "require"
+	SSLModeVerifyCA   = "verify-ca"
+	SSLModeVerifyFull = "verify-full"
+
+	JWTAlgorithmHS256 = "HS256"
+	JWTAlgorithmRS256 = "RS256"
+	JWTAlgorithmEdDSA = "EdDSA"
+
+	MailDriverLog  = "log"
+	MailDriverFile = "file"
+	MailDriverSMTP = "smtp"
+
+	PasswordHashArgon2id = "argon2id"
+	PasswordHashBcrypt   = "bcrypt"
+
+	// default secrets only meant for local development
+	defaultDBPassword = "1234"
+	defaultJWTSecret  = "secreate"
+	defaultMFAKey     = "local-mfa-encryption-key"
+)
+
+// Config holds every setting the application needs at startup.
+// values are resolved in order: defaults, optional YAML file, environment variables.
+type Config struct {
+	App         AppConfig         `yaml:"app"`
+	Server      ServerConfig      `yaml:"server"`
+	Database    DatabaseConfig    `yaml:"database"`
+	JWT         JWTConfig         `yaml:"jwt"`
+	Auth        AuthConfig        `yaml:"auth"`
+	Mail        MailConfig        `yaml:"mail"`
+	Password    PasswordConfig    `yaml:"password"`
+	Tenancy     TenancyConfig     `yaml:"tenancy"`
+	Procurement ProcurementConfig `yaml:"procurement"`
+	Payment     PaymentConfig     `yaml:"payment"`
+}
+
+type AppConfig struct {
+	Env     string `yaml:"env"`
+	Storage string `yaml:"storage"`
+}
+
+type ServerConfig struct {
+	Port string `yaml:"port"`
+	// take the client IP from X-Forwarded-For / X-Real-IP, only behind a proxy that sets them
+	TrustProxy bool `yaml:"trust_proxy"`
+}
+
+type DatabaseConfig struct {
+	Driver   string `yaml:"driver"`
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	Schema   string `yaml:"schema"`
+	// disable, require, verify-ca or verify-full, production needs TLS
+	SSLMode         string        `yaml:"sslmode"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+}
+
+type JWTConfig struct {
+	// HS256 signs with Secret, RS256 and EdDSA sign with the PEM private key in SigningKeyFile
+	Algorithm      string `yaml:"algorithm"`
+	Secret         string `yaml:"secret"`
+	SigningKeyID   string `yaml:"signing_key_id"`
+	SigningKeyFile string `yaml:"signing_key_file"`
+	// keys still accepted while rotating, e.g. the previous signing key
+	VerificationKeys []JWTKeyConfig `yaml:"verification_keys"`
+	AccessTokenTTL   time.Duration  `yaml:"access_token_ttl"`
+	RefreshTokenTTL  time.Duration  `yaml:"refresh_token_ttl"`
+	// how long revocation lookups are cached per instance, 0 disables the cache
+	RevocationCacheTTL time.Duration `yaml:"revocation_cache_ttl"`
+}
+
+// AuthConfig holds the account security settings
+type AuthConfig struct {
+	// link sent by email, the reset token is appended as ?token=
+	PasswordResetURL string        `yaml:"password_reset_url"`
+	PasswordResetTTL time.Duration `yaml:"password_reset_ttl"`
+	// at most PasswordResetMaxRequests forgot-password requests per email within PasswordResetWindow
+	PasswordResetMaxRequests int           `yaml:"password_reset_max_requests"`
+	PasswordResetWindow      time.Duration `yaml:"password_reset_window"`
+	// link mailed on registration, GET /api/v1/auth/verify unless a frontend handles it
+	EmailVerificationURL string        `yaml:"email_verification_url"`
+	EmailVerificationTTL time.Duration `yaml:"email_verification_ttl"`
+	// at most EmailVerificationMaxRequests resends per email within EmailVerificationWindow
+	EmailVerificationMaxRequests int           `yaml:"email_verification_max_requests"`
+	EmailVerificationWindow      time.Duration `yaml:"email_verification_window"`
+	// passphrase the TOTP secrets are encrypted with, changing it invalidates every enrollment
+	MFAEncryptionKey string `yaml:"mfa_encryption_key"`
+	// issuer shown by authenticator apps
+	MFAIssuer       string        `yaml:"mfa_issuer"`
+	MFAChallengeTTL time.Duration `yaml:"mfa_challenge_ttl"`
+	// at most MFAMaxAttempts second step attempts per user within MFAAttemptWindow
+	MFAMaxAttempts   int           `yaml:"mfa_max_attempts"`
+	MFAAttemptWindow time.Duration `yaml:"mfa_attempt_window"`
+	// roles that must enable two-factor authentication before calling protected routes
+	MFARequiredRoles []string `yaml:"mfa_required_roles"`
+	// failed logins older than LoginFailureWindow are forgotten
+	LoginFailureWindow time.Duration `yaml:"login_failure_window"`
+	// after LoginBackoffAfter consecutive failures an account waits LoginBackoffBase,
+	// doubled on every further failure up to LoginBackoffMax
+	LoginBackoffAfter int           `yaml:"login_backoff_after"`
+	LoginBackoffBase  time.Duration `yaml:"login_backoff_base"`
+	LoginBackoffMax   time.Duration `yaml:"login_backoff_max"`
+	// after LoginLockoutAfter consecutive failures the account is locked for LoginLockoutDuration
+	LoginLockoutAfter    int           `yaml:"login_lockout_after"`
+	LoginLockoutDuration time.Duration `yaml:"login_lockout_duration"`
+	// failed logins allowed from one IP address within LoginFailureWindow
+	LoginMaxIPFailures int `yaml:"login_max_ip_failures"`
+	// longest lifetime of an API key, keys created without expires_at get this lifetime
+	APIKeyMaxTTL time.Duration `yaml:"api_key_max_ttl"`
+}
+
+// MailConfig selects how emails are delivered, log and file are meant for local development
+type MailConfig struct {
+	Driver       string `yaml:"driver"`
+	From         string `yaml:"from"`
+	Dir          string `yaml:"dir"`
+	SMTPHost     string `yaml:"smtp_host"`
+	SMTPPort     string `yaml:"smtp_port"`
+	SMTPUsername string `yaml:"smtp_username"`
+	SMTPPassword string `yaml:"smtp_password"`
+}
+
+// PasswordConfig is the password policy and how new passwords are hashed. hashes made
+// with another algorithm or cost are upgraded on the next successful login
+type PasswordConfig struct {
+	MinLength     int  `yaml:"min_length"`
+	MaxLength     int  `yaml:"max_length"`
+	RequireUpper  bool `yaml:"require_upper"`
+	RequireLower  bool `yaml:"require_lower"`
+	RequireDigit  bool `yaml:"require_digit"`
+	RequireSymbol bool `yaml:"require_symbol"`
+	// number of previous passwords that cannot be reused, 0 disables the check
+	HistorySize int `yaml:"history_size"`
+	// argon2id or bcrypt
+	HashAlgorithm string `yaml:"hash_algorithm"`
+	BcryptCost    int    `yaml:"bcrypt_cost"`
+	// argon2id memory in KiB
+	Argon2Memory      int `yaml:"argon2_memory"`
+	Argon2Iterations  int `yaml:"argon2_iterations"`
+	Argon2Parallelism int `yaml:"argon2_parallelism"`
+}
+
+// TenancyConfig controls how users end up in organizations (tenants)
+type TenancyConfig struct {
+	// code of the organization self-registered users join when they name none,
+	// created at startup when missing
+	DefaultOrganization string `yaml:"default_organization"`
+	// name given to the default organization when it is created
+	DefaultOrganizationName string `yaml:"default_organization_name"`
+	// verified user created at startup as admin of the default organization, skipped when the email is empty.
+	// an existing verified user with this email is made admin instead, the password is then ignored
+	BootstrapAdminEmail    string `yaml:"bootstrap_admin_email"`
+	BootstrapAdminUserName string `yaml:"bootstrap_admin_user_name"`
+	BootstrapAdminPassword string `yaml:"bootstrap_admin_password"`
+}
+
+// ProcurementConfig holds the tolerances applied to purchase order fulfilment and invoice matching
+type ProcurementConfig struct {
+	// percentage above the ordered quantity that may still be accepted on a goods receipt
+	OverReceiptTolerance float64 `yaml:"over_receipt_tolerance"`
+	// percentage an invoiced unit price may exceed the PO price and still match
+	InvoicePriceTolerance float64 `yaml:"invoice_price_tolerance"`
+	// percentage the invoiced quantity may exceed the accepted quantity and still match
+	InvoiceQuantityTolerance float64 `yaml:"invoice_quantity_tolerance"`
+}
+
+// PaymentConfig holds the payment terms applied when neither the order nor the vendor has any
+// and where payment batches are exported for the bank
+type PaymentConfig struct {
+	// terms written as "Net 30" or "2/10 Net 30"
+	DefaultTerms string `yaml:"default_terms"`
+	// directory the bank files of payment batches are written to
+	ExportDir string `yaml:"export_dir"`
+}
+
+// JWTKeyConfig points to a PEM encoded public (or private) key and the kid it is published under
+type JWTKeyConfig struct {
+	ID   string `yaml:"id"`
+	File string `yaml:"file"`
+}
+
+// Default returns the configuration used for local development
+func Default() *Config {
+	return &Config{
+		App: AppConfig{
+			Env:     EnvDevelopment,
+			Storage: StoragePostgres,
+		},
+		Server: ServerConfig{
+			Port: "8080",
+		},
+		Database: DatabaseConfig{
+			Driver:          "postgres",
+			Host:            "localhost",
+			Port:            "5432",
+			User:            "postgres",
+			Password:        defaultDBPassword,
+			Name:            "e_procurement",
+			Schema:          "e_procurement",
+			SSLMode:         SSLModeDisable,
+			MaxOpenConns:    10,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 5 * time.Minute,
+		},
+		JWT: JWTConfig{
+			Algorithm:          JWTAlgorithmHS256,
+			Secret:             defaultJWTSecret,
+			AccessTokenTTL:     15 * time.Minute,
+			RefreshTokenTTL:    7 * 24 * time.Hour,
+			RevocationCacheTTL: 30 * time.Second,
+		},
+		Auth: AuthConfig{
+			PasswordResetURL:             "http://localhost:3000/reset-password",
+			PasswordResetTTL:             30 * time.Minute,
+			PasswordResetMaxRequests:     3,
+			PasswordResetWindow:          time.Hour,
+			EmailVerificationURL:         "http://localhost:8080/api/v1/auth/verify",
+			EmailVerificationTTL:         24 * time.Hour,
+			EmailVerificationMaxRequests: 3,
+			EmailVerificationWindow:      time.Hour,
+			MFAEncryptionKey:             defaultMFAKey,
+			MFAIssuer:                    "e-procurement",
+			MFAChallengeTTL:              5 * time.Minute,
+			MFAMaxAttempts:               5,
+			MFAAttemptWindow:             5 * time.Minute,
+			MFARequiredRoles:             []string{rbac.RoleAdmin, rbac.RoleApprover},
+			LoginFailureWindow:           time.Hour,
+			LoginBackoffAfter:            3,
+			LoginBackoffBase:             time.Second,
+			LoginBackoffMax:              time.Minute,
+			LoginLockoutAfter:            10,
+			LoginLockoutDuration:         15 * time.Minute,
+			LoginMaxIPFailures:           100,
+			APIKeyMaxTTL:                 365 * 24 * time.Hour,
+		},
+		Mail: MailConfig{
+			Driver:   MailDriverLog,
+			From:     "no-reply@e-procurement.local",
+			Dir:      "tmp/mail",
+			SMTPPort: "587",
+		},
+		Password: PasswordConfig{
+			MinLength:         8,
+			MaxLength:         128,
+			RequireUpper:      true,
+			RequireLower:      true,
+			RequireDigit:      true,
+			HistorySize:       5,
+			HashAlgorithm:     PasswordHashArgon2id,
+			BcryptCost:        10,
+			Argon2Memory:      19 * 1024,
+			Argon2Iterations:  2,
+			Argon2Parallelism: 1,
+		},
+		Tenancy: TenancyConfig{
+			DefaultOrganization:     "default",
+			DefaultOrganizationName: "Default Organization",
+			BootstrapAdminUserName:  "admin",
+		},
+		Procurement: ProcurementConfig{
+			OverReceiptTolerance:     5,
+			InvoicePriceTolerance:    2,
+			InvoiceQuantityTolerance: 0,
+		},
+		Payment: PaymentConfig{
+			DefaultTerms: "Net 30",
+			ExportDir:    "tmp/payments",
+		},
+	}
+}
+
+// Load builds the configuration from defaults, the file pointed by CONFIG_FILE (if any)
+// and environment variables, then validates the result.
+func Load() (*Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(content, c); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) loadEnv() error {
+	var errs []error
+
+	envString("APP_ENV", &c.App.Env)
+	envString("APP_STORAGE", &c.App.Storage)
+	envString("PORT", &c.Server.Port)
+	envString("SERVER_PORT", &c.Server.Port)
+	errs = append(errs, envBool("SERVER_TRUST_PROXY", &c.Server.TrustProxy))
+
+	envString("DB_DRIVER", &c.Database.Driver)
+	envString("DB_HOST", &c.Database.Host)
+	envString("DB_PORT", &c.Database.Port)
+	envString("DB_USER", &c.Database.User)
+	envString("DB_PASSWORD", &c.Database.Password)
+	envString("DB_NAME", &c.Database.Name)
+	envString("DB_SCHEMA", &c.Database.Schema)
+	envString("DB_SSLMODE", &c.Database.SSLMode)
+	errs = append(errs,
+		envInt("DB_MAX_OPEN_CONNS", &c.Database.MaxOpenConns),
+		envInt("DB_MAX_IDLE_CONNS", &c.Database.MaxIdleConns),
+		envDuration("DB_CONN_MAX_LIFETIME", &c.Database.ConnMaxLifetime),
+	)
+
+	envString("JWT_ALGORITHM", &c.JWT.Algorithm)
+	envString("JWT_SECRET", &c.JWT.Secret)
+	envString("JWT_SIGNING_KEY_ID", &c.JWT.SigningKeyID)
+	envString("JWT_SIGNING_KEY_FILE", &c.JWT.SigningKeyFile)
+	errs = append(errs,
+		envKeyList("JWT_VERIFICATION_KEYS", &c.JWT.VerificationKeys),
+		envDuration("JWT_ACCESS_TOKEN_TTL", &c.JWT.AccessTokenTTL),
+		envDuration("JWT_REFRESH_TOKEN_TTL", &c.JWT.RefreshTokenTTL),
+		envDuration("JWT_REVOCATION_CACHE_TTL", &c.JWT.RevocationCacheTTL),
+	)
+
+	envString("AUTH_PASSWORD_RESET_URL", &c.Auth.PasswordResetURL)
+	errs = append(errs,
+		envDuration("AUTH_PASSWORD_RESET_TTL", &c.Auth.PasswordResetTTL),
+		envInt("AUTH_PASSWORD_RESET_MAX_REQUESTS", &c.Auth.PasswordResetMaxRequests),
+		envDuration("AUTH_PASSWORD_RESET_WINDOW", &c.Auth.PasswordResetWindow),
+	)
+	envString("AUTH_EMAIL_VERIFICATION_URL", &c.Auth.EmailVerificationURL)
+	errs = append(errs,
+		envDuration("AUTH_EMAIL_VERIFICATION_TTL", &c.Auth.EmailVerificationTTL),
+		envInt("AUTH_EMAIL_VERIFICATION_MAX_REQUESTS", &c.Auth.EmailVerificationMaxRequests),
+		envDuration("AUTH_EMAIL_VERIFICATION_WINDOW", &c.Auth.EmailVerificationWindow),
+	)
+	envString("AUTH_MFA_ENCRYPTION_KEY", &c.Auth.MFAEncryptionKey)
+	envString("AUTH_MFA_ISSUER", &c.Auth.MFAIssuer)
+	envList("AUTH_MFA_REQUIRED_ROLES", &c.Auth.MFARequiredRoles)
+	errs = append(errs,
+		envDuration("AUTH_MFA_CHALLENGE_TTL", &c.Auth.MFAChallengeTTL),
+		envInt("AUTH_MFA_MAX_ATTEMPTS", &c.Auth.MFAMaxAttempts),
+		envDuration("AUTH_MFA_ATTEMPT_WINDOW", &c.Auth.MFAAttemptWindow),
+		envDuration("AUTH_LOGIN_FAILURE_WINDOW", &c.Auth.LoginFailureWindow),
+		envInt("AUTH_LOGIN_BACKOFF_AFTER", &c.Auth.LoginBackoffAfter),
+		envDuration("AUTH_LOGIN_BACKOFF_BASE", &c.Auth.LoginBackoffBase),
+		envDuration("AUTH_LOGIN_BACKOFF_MAX", &c.Auth.LoginBackoffMax),
+		envInt("AUTH_LOGIN_LOCKOUT_AFTER", &c.Auth.LoginLockoutAfter),
+		envDuration("AUTH_LOGIN_LOCKOUT_DURATION", &c.Auth.LoginLockoutDuration),
+		envInt("AUTH_LOGIN_MAX_IP_FAILURES", &c.Auth.LoginMaxIPFailures),
+		envDuration("AUTH_API_KEY_MAX_TTL", &c.Auth.APIKeyMaxTTL),
+	)
+
+	envString("MAIL_DRIVER", &c.Mail.Driver)
+	envString("MAIL_FROM", &c.Mail.From)
+	envString("MAIL_DIR", &c.Mail.Dir)
+	envString("SMTP_HOST", &c.Mail.SMTPHost)
+	envString("SMTP_PORT", &c.Mail.SMTPPort)
+	envString("SMTP_USERNAME", &c.Mail.SMTPUsername)
+	envString("SMTP_PASSWORD", &c.Mail.SMTPPassword)
+
+	envString("PASSWORD_HASH_ALGORITHM", &c.Password.HashAlgorithm)
+	errs = append(errs,
+		envInt("PASSWORD_MIN_LENGTH", &c.Password.MinLength),
+		envInt("PASSWORD_MAX_LENGTH", &c.Password.MaxLength),
+		envBool("PASSWORD_REQUIRE_UPPER", &c.Password.RequireUpper),
+		envBool("PASSWORD_REQUIRE_LOWER", &c.Password.RequireLower),
+		envBool("PASSWORD_REQUIRE_DIGIT", &c.Password.RequireDigit),
+		envBool("PASSWORD_REQUIRE_SYMBOL", &c.Password.RequireSymbol),
+		envInt("PASSWORD_HISTORY_SIZE", &c.Password.HistorySize),
+		envInt("PASSWORD_BCRYPT_COST", &c.Password.BcryptCost),
+		envInt("PASSWORD_ARGON2_MEMORY", &c.Password.Argon2Memory),
+		envInt("PASSWORD_ARGON2_ITERATIONS", &c.Password.Argon2Iterations),
+		envInt("PASSWORD_ARGON2_PARALLELISM", &c.Password.Argon2Parallelism),
+	)
+
+	envString("TENANCY_DEFAULT_ORGANIZATION", &c.Tenancy.DefaultOrganization)
+	envString("TENANCY_DEFAULT_ORGANIZATION_NAME", &c.Tenancy.DefaultOrganizationName)
+	envString("TENANCY_BOOTSTRAP_ADMIN_EMAIL", &c.Tenancy.BootstrapAdminEmail)
+	envString("TENANCY_BOOTSTRAP_ADMIN_USER_NAME", &c.Tenancy.BootstrapAdminUserName)
+	envString("TENANCY_BOOTSTRAP_ADMIN_PASSWORD", &c.Tenancy.BootstrapAdminPassword)
+
+	errs = append(errs,
+		envFloat("PROCUREMENT_OVER_RECEIPT_TOLERANCE", &c.Procurement.OverReceiptTolerance),
+		envFloat("PROCUREMENT_INVOICE_PRICE_TOLERANCE", &c.Procurement.InvoicePriceTolerance),
+		envFloat("PROCUREMENT_INVOICE_QUANTITY_TOLERANCE", &c.Procurement.InvoiceQuantityTolerance),
+	)
+
+	envString("PAYMENT_DEFAULT_TERMS", &c.Payment.DefaultTerms)
+	envString("PAYMENT_EXPORT_DIR", &c.Payment.ExportDir)
+
+	return errors.Join(errs...)
+}
+
+// Validate checks required values and refuses default secrets in production
+func (c *Config) Validate() error {
+	var errs []error
+
+	switch c.App.Env {
+	case EnvDevelopment, EnvStaging, EnvProduction:
+	default:
+		errs = append(errs, fmt.Errorf("app.env must be one of %s, %s, %s", EnvDevelopment, EnvStaging, EnvProduction))
+	}
+
+	switch c.App.Storage {
+	case StoragePostgres, StorageMemory:
+	default:
+		errs = append(errs, fmt.Errorf("app.storage must be %s or %s", StoragePostgres, StorageMemory))
+	}
+
+	if _, err := strconv.Atoi(c.Server.Port); err != nil {
+		errs = append(errs, fmt.Errorf("server.port must be a number"))
+	}
+
+	if c.Database.Driver == "" || c.Database.Host == "" || c.Database.Port == "" ||
+		c.Database.User == "" || c.Database.Name == "" || c.Database.Schema == "" {
+		errs = append(errs, errors.New("database driver, host, port, user, name and schema are required"))
+	}
+	switch c.Database.SSLMode {
+	case SSLModeDisable, SSLModeRequire, SSLModeVerifyCA, SSLModeVerifyFull:
+	default:
+		errs = append(errs, fmt.Errorf("database.sslmode must be one of %s, %s, %s, %s",
+			SSLModeDisable, SSLModeRequire, SSLModeVerifyCA, SSLModeVerifyFull))
+	}
+	if c.Database.MaxOpenConns <= 0 || c.Database.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("database pool sizes must be positive"))
+	}
+	if c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, errors.New("database.max_idle_conns cannot exceed database.max_open_conns"))
+	}
+
+	switch c.JWT.Algorithm {
+	case JWTAlgorithmHS256:
+		if c.JWT.Secret == "" {
+			errs = append(errs, errors.New("jwt.secret is required"))
+		}
+	case JWTAlgorithmRS256, JWTAlgorithmEdDSA:
+		if c.JWT.SigningKeyID == "" || c.JWT.SigningKeyFile == "" {
+			errs = append(errs, fmt.Errorf("jwt.signing_key_id and jwt.signing_key_file are required for %s", c.JWT.Algorithm))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("jwt.algorithm must be one of %s, %s, %s", JWTAlgorithmHS256, JWTAlgorithmRS256, JWTAlgorithmEdDSA))
+	}
+	for _, key := range c.JWT.VerificationKeys {
+		if key.ID == "" || key.File == "" {
+			errs = append(errs, errors.New("jwt.verification_keys entries need an id and a file"))
+			break
+		}
+	}
+	if c.JWT.AccessTokenTTL <= 0 || c.JWT.RefreshTokenTTL <= 0 {
+		errs = append(errs, errors.New("jwt token TTLs must be positive"))
+	}
+	if c.JWT.RevocationCacheTTL < 0 {
+		errs = append(errs, errors.New("jwt.revocation_cache_ttl cannot be negative"))
+	}
+	if c.JWT.AccessTokenTTL >= c.JWT.RefreshTokenTTL {
+		errs = append(errs, errors.New("jwt.access_token_ttl must be shorter than jwt.refresh_token_ttl"))
+	}
+
+	if c.Auth.PasswordResetURL == "" {
+		errs = append(errs, errors.New("auth.password_reset_url is required"))
+	}
+	if c.Auth.PasswordResetTTL <= 0 || c.Auth.PasswordResetWindow <= 0 || c.Auth.PasswordResetMaxRequests <= 0 {
+		errs = append(errs, errors.New("auth password reset TTL, window and max requests must be positive"))
+	}
+	if c.Auth.EmailVerificationURL == "" {
+		errs = append(errs, errors.New("auth.email_verification_url is required"))
+	}
+	if c.Auth.EmailVerificationTTL <= 0 || c.Auth.EmailVerificationWindow <= 0 || c.Auth.EmailVerificationMaxRequests <= 0 {
+		errs = append(errs, errors.New("auth email verification TTL, window and max requests must be positive"))
+	}
+	if c.Auth.MFAEncryptionKey == "" || c.Auth.MFAIssuer == "" {
+		errs = append(errs, errors.New("auth.mfa_encryption_key and auth.mfa_issuer are required"))
+	}
+	if c.Auth.MFAChallengeTTL <= 0 || c.Auth.MFAAttemptWindow <= 0 || c.Auth.MFAMaxAttempts <= 0 {
+		errs = append(errs, errors.New("auth mfa challenge TTL, attempt window and max attempts must be positive"))
+	}
+	if c.Auth.LoginFailureWindow <= 0 || c.Auth.LoginBackoffBase <= 0 || c.Auth.LoginLockoutDuration <= 0 ||
+		c.Auth.LoginBackoffAfter <= 0 || c.Auth.LoginLockoutAfter <= 0 || c.Auth.LoginMaxIPFailures <= 0 {
+		errs = append(errs, errors.New("auth login throttle durations and thresholds must be positive"))
+	}
+	if c.Auth.LoginBackoffMax < c.Auth.LoginBackoffBase {
+		errs = append(errs, errors.New("auth.login_backoff_max cannot be shorter than auth.login_backoff_base"))
+	}
+	if c.Auth.LoginLockoutAfter <= c.Auth.LoginBackoffAfter {
+		errs = append(errs, errors.New("auth.login_lockout_after must be greater than auth.login_backoff_after"))
+	}
+	if c.Auth.APIKeyMaxTTL <= 0 {
+		errs = append(errs, errors.New("auth.api_key_max_ttl must be positive"))
+	}
+	for _, role := range c.Auth.MFARequiredRoles {
+		if !rbac.IsValidRole(role) {
+			errs = append(errs, fmt.Errorf("auth.mfa_required_roles: unknown role %q", role))
+		}
+	}
+
+	switch c.Mail.Driver {
+	case MailDriverLog:
+	case MailDriverFile:
+		if c.Mail.Dir == "" {
+			errs = append(errs, errors.New("mail.dir is required for the file driver"))
+		}
+	case MailDriverSMTP:
+		if c.Mail.SMTPHost == "" || c.Mail.SMTPPort == "" {
+			errs = append(errs, errors.New("mail.smtp_host and mail.smtp_port are required for the smtp driver"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("mail.driver must be one of %s, %s, %s", MailDriverLog, MailDriverFile, MailDriverSMTP))
+	}
+	if c.Mail.From == "" {
+		errs = append(errs, errors.New("mail.from is required"))
+	}
+
+	if c.Password.MinLength <= 0 || c.Password.MaxLength < c.Password.MinLength || c.Password.MaxLength > 1024 {
+		errs = append(errs, errors.New("password.min_length must be positive and password.max_length between min_length and 1024"))
+	}
+	if c.Password.HistorySize < 0 {
+		errs = append(errs, errors.New("password.history_size cannot be negative"))
+	}
+	switch c.Password.HashAlgorithm {
+	case PasswordHashArgon2id:
+		if c.Password.Argon2Memory < 8*c.Password.Argon2Parallelism || c.Password.Argon2Iterations <= 0 ||
+			c.Password.Argon2Parallelism <= 0 || c.Password.Argon2Parallelism > 255 {
+			errs = append(errs, errors.New("password argon2 iterations and parallelism (1-255) must be positive and memory at least 8 KiB per thread"))
+		}
+	case PasswordHashBcrypt:
+		if c.Password.BcryptCost < 4 || c.Password.BcryptCost > 31 {
+			errs = append(errs, errors.New("password.bcrypt_cost must be between 4 and 31"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("password.hash_algorithm must be %s or %s", PasswordHashArgon2id, PasswordHashBcrypt))
+	}
+
+	if c.Tenancy.DefaultOrganization == "" || c.Tenancy.DefaultOrganizationName == "" {
+		errs = append(errs, errors.New("tenancy.default_organization and tenancy.default_organization_name are required"))
+	}
+	if c.Tenancy.BootstrapAdminEmail != "" && (c.Tenancy.BootstrapAdminUserName == "" || c.Tenancy.BootstrapAdminPassword == "") {
+		errs = append(errs, errors.New("tenancy.bootstrap_admin_user_name and tenancy.bootstrap_admin_password are required with tenancy.bootstrap_admin_email"))
+	}
+
+	if c.Procurement.OverReceiptTolerance < 0 || c.Procurement.OverReceiptTolerance > 100 {
+		errs = append(errs, errors.New("procurement.over_receipt_tolerance must be between 0 and 100"))
+	}
+	if c.Procurement.InvoicePriceTolerance < 0 || c.Procurement.InvoicePriceTolerance > 100 {
+		errs = append(errs, errors.New("procurement.invoice_price_tolerance must be between 0 and 100"))
+	}
+	if c.Procurement.InvoiceQuantityTolerance < 0 || c.Procurement.InvoiceQuantityTolerance > 100 {
+		errs = append(errs, errors.New("procurement.invoice_quantity_tolerance must be between 0 and 100"))
+	}
+	// the format of the terms is checked when the app starts
+	if c.Payment.DefaultTerms == "" || c.Payment.ExportDir == "" {
+		errs = append(errs, errors.New("payment.default_terms and payment.export_dir are required"))
+	}
+
+	if c.IsProduction() {
+		if c.Mail.Driver != MailDriverSMTP {
+			errs = append(errs, errors.New("mail.driver must be smtp in production"))
+		}
+		if c.JWT.Algorithm == JWTAlgorithmHS256 && (c.JWT.Secret == defaultJWTSecret || len(c.JWT.Secret) < 32) {
+			errs = append(errs, errors.New("jwt.secret must be changed and at least 32 characters in production"))
+		}
+		if c.Auth.MFAEncryptionKey == defaultMFAKey || len(c.Auth.MFAEncryptionKey) < 32 {
+			errs = append(errs, errors.New("auth.mfa_encryption_key must be changed and at least 32 characters in production"))
+		}
+		if c.App.Storage == StorageMemory {
+			errs = append(errs, errors.New("memory storage cannot be used in production"))
+		}
+		if c.Database.Password == defaultDBPassword || c.Database.Password == "" {
+			errs = append(errs, errors.New("database.password must be changed in production"))
+		}
+		if c.App.Storage == StoragePostgres && c.Database.SSLMode == SSLModeDisable {
+			errs = append(errs, errors.New("database.sslmode cannot be disable in production"))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return nil
+}
+
+func (c *Config) IsProduction() bool {
+	return c.App.Env == EnvProduction
+}
+
+func envString(name string, target *string) {
+	if value, ok := os.LookupEnv(name); ok {
+		*target = strings.TrimSpace(value)
+	}
+}
+
+func envBool(name string, target *bool) error {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("%s must be true or false: %w", name, err)
+	}
+	*target = parsed
+	return nil
+}
+
+func envInt(name string, target *int) error {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	parsed, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("%s must be an integer: %w", name, err)
+	}
+	*target = parsed
+	return nil
+}
+
+func envFloat(name string, target *float64) error {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return fmt.Errorf("%s must be a number: %w", name, err)
+	}
+	*target = parsed
+	return nil
+}
+
+// envList parses a comma separated list, empty items are dropped
+func envList(name string, target *[]string) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*target = items
+}
+
+// envKeyList parses "kid=path,kid=path"
+func envKeyList(name string, target *[]JWTKeyConfig) error {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	var keys []JWTKeyConfig
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, file, found := strings.Cut(item, "=")
+		if !found {
+			return fmt.Errorf("%s must be a comma separated list of kid=path", name)
+		}
+		keys = append(keys, JWTKeyConfig{ID: strings.TrimSpace(id), File: strings.TrimSpace(file)})
+	}
+	*target = keys
+	return nil
+}
+
+func envDuration(name string, target *time.Duration) error {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	parsed, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("%s must be a duration (e.g. 5m): %w", name, err)
+	}
+	*target = parsed
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 )
 
@@ -15,21 +16,24 @@ type DBConfig struct {
     Password       string
     DBName         string
     Schema         string // tambahan untuk schema
+    SSLMode        string // disable, require, verify-ca atau verify-full
     MaxOpenConns   int
     MaxIdleConns   int
     ConnMaxLifetime time.Duration
 }
 func ConnectDB(cfg DBConfig) (*sql.DB, error) {
 	dsn := fmt.Sprintf(
-        "host=%s port=%s user=%s password=%s dbname=%s sslmode=disable search_path=%s",
-        cfg.Host,
-        cfg.Port,
-        cfg.User,
-        cfg.Password,
-        cfg.DBName,
-        cfg.Schema,
+        "host=%s port=%s user=%s password=%s dbname=%s sslmode=%s search_path=%s",
+        dsnValue(cfg.Host),
+        dsnValue(cfg.Port),
+        dsnValue(cfg.User),
+        dsnValue(cfg.Password),
+        dsnValue(cfg.DBName),
+        dsnValue(cfg.SSLMode),
+        dsnValue(cfg.Schema),
     )
-    log.Printf("Connecting to database with DSN: %s", dsn)
+    // the DSN carries the password, only log where we connect to
+    log.Printf("Connecting to database %s at %s:%s (sslmode=%s)", cfg.DBName, cfg.Host, cfg.Port, cfg.SSLMode)
 	db, err := sql.Open(cfg.Driver, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open DB: %w", err)
@@ -42,4 +46,9 @@ func ConnectDB(cfg DBConfig) (*sql.DB, error) {
 	db.SetMaxIdleConns(cfg.MaxIdleConns)
 	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 	return db, nil
-}
\ No newline at end of file
+}
+
+// dsnValue quotes a key/value connection string value so spaces, quotes and backslashes survive
+func dsnValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
@@ -5,4 +5,21 @@ type contextKey string
 const (
 	ContextUserIDKey   contextKey = "user_id"
 	ContextPositionKey contextKey = "position"
-)
\ No newline at end of file
+	// organization (tenant) the request acts in, every tenant owned query is scoped by it
+	ContextTenantKey contextKey = "tenant_id"
+	// jti and expiry of the access token used for the request
+	ContextTokenIDKey        contextKey = "token_id"
+	ContextTokenExpiresAtKey contextKey = "token_expires_at"
+	// whether the access token was issued to a user with two-factor authentication enabled
+	ContextMFAKey contextKey = "mfa"
+	// PrincipalUser for access tokens, PrincipalService for API keys
+	ContextPrincipalKey contextKey = "principal"
+	// ID and scopes of the API key used for the request
+	ContextAPIKeyIDKey contextKey = "api_key_id"
+	ContextScopesKey   contextKey = "scopes"
+)
+
+const (
+	PrincipalUser    = "user"
+	PrincipalService = "service"
+)
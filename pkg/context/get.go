@@ -2,19 +2,61 @@ package context
 
 import (
 	"context"
+	"e-procurement/pkg/apperror"
 	"e-procurement/pkg/constans"
-	"errors"
+	"time"
 )
 
 func GetUserIDFromContext(ctx context.Context) (string, error) {
 	if ctx == nil {
-		return "", errors.New("context is nil")
+		return "", apperror.Unauthorized("unauthenticated", "context is nil")
 	}
 
 	userID, ok := ctx.Value(constans.ContextUserIDKey).(string)
 	if !ok || userID == "" {
-		return "", errors.New("user ID not found in context")
+		return "", apperror.Unauthorized("unauthenticated", "user ID not found in context")
 	}
 
 	return userID, nil
-}
\ No newline at end of file
+}
+func GetPositionFromContext(ctx context.Context) (string, error) {
+	if ctx == nil {
+		return "", apperror.Unauthorized("unauthenticated", "context is nil")
+	}
+
+	position, ok := ctx.Value(constans.ContextPositionKey).(string)
+	if !ok || position == "" {
+		return "", apperror.Unauthorized("unauthenticated", "position not found in context")
+	}
+
+	return position, nil
+}
+
+// GetTenantFromContext returns the organization the request acts in
+func GetTenantFromContext(ctx context.Context) (string, error) {
+	if ctx == nil {
+		return "", apperror.Unauthorized("unauthenticated", "context is nil")
+	}
+
+	tenantID, ok := ctx.Value(constans.ContextTenantKey).(string)
+	if !ok || tenantID == "" {
+		return "", apperror.Unauthorized("unauthenticated", "organization not found in context")
+	}
+
+	return tenantID, nil
+}
+
+// GetTokenFromContext returns the jti and expiry of the access token used for the request
+func GetTokenFromContext(ctx context.Context) (string, time.Time, error) {
+	if ctx == nil {
+		return "", time.Time{}, apperror.Unauthorized("unauthenticated", "context is nil")
+	}
+
+	tokenID, ok := ctx.Value(constans.ContextTokenIDKey).(string)
+	if !ok || tokenID == "" {
+		return "", time.Time{}, apperror.Unauthorized("unauthenticated", "token ID not found in context")
+	}
+	expiresAt, _ := ctx.Value(constans.ContextTokenExpiresAtKey).(time.Time)
+
+	return tokenID, expiresAt, nil
+}
@@ -1,24 +1,104 @@
 package encripted
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
 
-type Encripted struct {}
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
 
+// password hashing algorithms, argon2id is used for new hashes unless configured otherwise
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+// Argon2Params are the argon2id cost parameters, Memory is in KiB
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// Options select the algorithm and cost of new hashes, existing hashes of any
+// supported algorithm keep verifying
+type Options struct {
+	Algorithm  string
+	BcryptCost int
+	Argon2     Argon2Params
+}
+
+// DefaultOptions follow the OWASP password storage recommendations
+func DefaultOptions() Options {
+	return Options{
+		Algorithm:  AlgorithmArgon2id,
+		BcryptCost: 10,
+		Argon2: Argon2Params{
+			Memory:      19 * 1024,
+			Iterations:  2,
+			Parallelism: 1,
+		},
+	}
+}
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+	argon2Prefix     = "$argon2id$"
+)
+
+var errInvalidArgon2Hash = errors.New("invalid argon2id hash")
+
+type Encripted struct {
+	options Options
+}
 
 func NewEncripted() *Encripted {
-	return &Encripted{}
+	return NewEncriptedWithOptions(DefaultOptions())
+}
+
+// NewEncriptedWithOptions creates a hasher producing hashes with the given algorithm and cost
+func NewEncriptedWithOptions(options Options) *Encripted {
+	return &Encripted{options: options}
 }
 
-const bycryptCost = 8
 func (e *Encripted) HashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bycryptCost)
-	if err != nil {
+	if e.options.Algorithm == AlgorithmBcrypt {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), e.options.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hashedPassword), nil
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
 		return "", err
 	}
-	return string(hashedPassword), nil
+	params := e.options.Argon2
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, argon2KeyLength)
+	// PHC string format, the same as the reference implementation and libsodium
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2Prefix, argon2.Version,
+		params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
 }
 
+// CheckPasswordHash verifies a password against a bcrypt or argon2id hash
 func (e *Encripted) CheckPasswordHash(hashedPassword, password string) (bool,error) {
+	if strings.HasPrefix(hashedPassword, argon2Prefix) {
+		params, salt, key, err := decodeArgon2(hashedPassword)
+		if err != nil {
+			return false, err
+		}
+		computed := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(computed, key) == 1, nil
+	}
+
     err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
     if err != nil {
 		if err == bcrypt.ErrMismatchedHashAndPassword {
@@ -27,4 +107,47 @@ func (e *Encripted) CheckPasswordHash(hashedPassword, password string) (bool,err
 		return false, err
 	}
 	return true, nil
-}
\ No newline at end of file
+}
+
+// NeedsRehash reports whether a hash was made with another algorithm or cost than the
+// configured ones, callers rehash the password after a successful login
+func (e *Encripted) NeedsRehash(hashedPassword string) bool {
+	if strings.HasPrefix(hashedPassword, argon2Prefix) {
+		if e.options.Algorithm != AlgorithmArgon2id {
+			return true
+		}
+		params, _, _, err := decodeArgon2(hashedPassword)
+		return err != nil || params != e.options.Argon2
+	}
+
+	if e.options.Algorithm != AlgorithmBcrypt {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	return err != nil || cost != e.options.BcryptCost
+}
+
+// decodeArgon2 parses $argon2id$v=19$m=...,t=...,p=...$salt$key
+func decodeArgon2(hashedPassword string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, errInvalidArgon2Hash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errInvalidArgon2Hash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, errInvalidArgon2Hash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, errInvalidArgon2Hash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, errInvalidArgon2Hash
+	}
+	return params, salt, key, nil
+}
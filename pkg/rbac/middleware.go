@@ -0,0 +1,32 @@
+package rbac
+
+import (
+	"e-procurement/pkg/constans"
+	response "e-procurement/pkg/responses"
+	"net/http"
+	"slices"
+)
+
+// RequirePermission only lets the request through when the role stored in the
+// context by auth.VerifyToken grants the permission, it must run after VerifyToken.
+// requests authenticated by an API key also need the permission among the key scopes.
+func RequirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, _ := r.Context().Value(constans.ContextPositionKey).(string)
+			if role == "" {
+				response.ErrorWithCode(w, http.StatusUnauthorized, "unauthenticated", "missing role in request context")
+				return
+			}
+			if !HasPermission(role, permission) {
+				response.ErrorWithCode(w, http.StatusForbidden, "permission_denied", "role "+role+" is not allowed to perform "+permission)
+				return
+			}
+			if scopes, ok := r.Context().Value(constans.ContextScopesKey).([]string); ok && !slices.Contains(scopes, permission) {
+				response.ErrorWithCode(w, http.StatusForbidden, "insufficient_scope", "api key is missing the scope "+permission)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
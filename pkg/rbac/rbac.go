@@ -0,0 +1,175 @@
+package rbac
+
+// roles given per organization membership and carried by the JWT `position` claim
+const (
+	RoleAdmin              = "admin"
+	RoleProcurementOfficer = "procurement_officer"
+	RoleVendor             = "vendor"
+	RoleApprover           = "approver"
+	RoleAuditor            = "auditor"
+	RoleWarehouse          = "warehouse"
+	RoleFinance            = "finance"
+
+	// DefaultRole is assigned to newly registered users
+	DefaultRole = RoleVendor
+)
+
+// permissions checked by RequirePermission, formatted as "<resource>:<action>"
+const (
+	PermCategoryRead  = "category:read"
+	PermCategoryWrite = "category:write"
+	PermVendorRead    = "vendor:read"
+	PermVendorWrite   = "vendor:write"
+	PermProductRead   = "product:read"
+	PermProductWrite  = "product:write"
+	PermUserRead      = "user:read"
+	PermUserManage    = "user:manage"
+	PermAuditRead     = "audit:read"
+	PermAPIKeyManage  = "api_key:manage"
+	// create new organizations, the creator becomes their admin
+	PermOrganizationCreate = "organization:create"
+	// departments and the cost centers purchases are charged to
+	PermDepartmentRead  = "department:read"
+	PermDepartmentWrite = "department:write"
+	// purchase requisitions, they are decided through the approval engine
+	PermRequisitionRead  = "requisition:read"
+	PermRequisitionWrite = "requisition:write"
+	// approval rules, and reading the approval history of documents; acting on an
+	// approval only needs to be its assigned approver
+	PermApprovalRead      = "approval:read"
+	PermApprovalRuleWrite = "approval_rule:write"
+	// requests for quotation, and responding to them as an invited vendor
+	PermRFQRead         = "rfq:read"
+	PermRFQWrite        = "rfq:write"
+	PermQuotationSubmit = "quotation:submit"
+	// purchase orders, drafted from RFQ awards by rfq:write or raised directly, and
+	// acknowledging or rejecting them as their vendor
+	PermPurchaseOrderRead    = "purchase_order:read"
+	PermPurchaseOrderWrite   = "purchase_order:write"
+	PermPurchaseOrderRespond = "purchase_order:respond"
+	// goods received against sent purchase orders, with their inspection result
+	PermGoodsReceiptRead  = "goods_receipt:read"
+	PermGoodsReceiptWrite = "goods_receipt:write"
+	// vendor invoices matched against purchase orders and goods receipts, reviewing them,
+	// and submitting them as the vendor of the order
+	PermInvoiceRead   = "invoice:read"
+	PermInvoiceWrite  = "invoice:write"
+	PermInvoiceSubmit = "invoice:submit"
+	// payment schedule of approved invoices and payment batches, and the remittance advice
+	// of paid batches as the vendor paid
+	PermPaymentRead    = "payment:read"
+	PermPaymentWrite   = "payment:write"
+	PermRemittanceRead = "remittance:read"
+)
+
+var rolePermissions = map[string][]string{
+	RoleAdmin: {
+		PermCategoryRead, PermCategoryWrite,
+		PermVendorRead, PermVendorWrite,
+		PermProductRead, PermProductWrite,
+		PermUserRead, PermUserManage,
+		PermAuditRead,
+		PermAPIKeyManage,
+		PermOrganizationCreate,
+		PermDepartmentRead, PermDepartmentWrite,
+		PermRequisitionRead, PermRequisitionWrite,
+		PermApprovalRead, PermApprovalRuleWrite,
+		PermRFQRead, PermRFQWrite,
+		PermPurchaseOrderRead, PermPurchaseOrderWrite,
+		PermGoodsReceiptRead, PermGoodsReceiptWrite,
+		PermInvoiceRead, PermInvoiceWrite,
+		PermPaymentRead, PermPaymentWrite,
+	},
+	RoleProcurementOfficer: {
+		PermCategoryRead, PermVendorRead, PermProductRead, PermUserRead,
+		PermAPIKeyManage,
+		PermDepartmentRead,
+		PermRequisitionRead, PermRequisitionWrite,
+		PermApprovalRead,
+		PermRFQRead, PermRFQWrite,
+		PermPurchaseOrderRead, PermPurchaseOrderWrite,
+		PermGoodsReceiptRead, PermGoodsReceiptWrite,
+		PermInvoiceRead,
+	},
+	RoleVendor: {
+		PermCategoryRead,
+		PermVendorRead, PermVendorWrite,
+		PermProductRead, PermProductWrite,
+		PermAPIKeyManage,
+		PermQuotationSubmit,
+		PermPurchaseOrderRespond,
+		PermInvoiceSubmit,
+		PermRemittanceRead,
+	},
+	RoleApprover: {
+		PermCategoryRead, PermVendorRead, PermProductRead, PermUserRead,
+		PermDepartmentRead,
+		PermRequisitionRead, PermRequisitionWrite,
+		PermApprovalRead,
+		PermRFQRead,
+		PermPurchaseOrderRead,
+		PermGoodsReceiptRead,
+	},
+	RoleAuditor: {
+		PermCategoryRead, PermVendorRead, PermProductRead, PermUserRead,
+		PermAuditRead,
+		PermDepartmentRead,
+		PermRequisitionRead,
+		PermApprovalRead,
+		PermRFQRead,
+		PermPurchaseOrderRead,
+		PermGoodsReceiptRead,
+		PermInvoiceRead,
+		PermPaymentRead,
+	},
+	RoleWarehouse: {
+		PermCategoryRead, PermVendorRead, PermProductRead, PermUserRead,
+		PermPurchaseOrderRead,
+		PermGoodsReceiptRead, PermGoodsReceiptWrite,
+	},
+	RoleFinance: {
+		PermCategoryRead, PermVendorRead, PermProductRead, PermUserRead,
+		PermPurchaseOrderRead,
+		PermGoodsReceiptRead,
+		PermInvoiceRead, PermInvoiceWrite,
+		PermPaymentRead, PermPaymentWrite,
+	},
+}
+
+// Roles returns every known role
+func Roles() []string {
+	return []string{RoleAdmin, RoleProcurementOfficer, RoleVendor, RoleApprover, RoleAuditor, RoleWarehouse, RoleFinance}
+}
+
+// IsValidRole reports whether role is one of the known roles
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// HasPermission reports whether the role grants the permission
+func HasPermission(role, permission string) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidPermission reports whether any role grants the permission
+func IsValidPermission(permission string) bool {
+	for _, permissions := range rolePermissions {
+		for _, granted := range permissions {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Permissions returns the permissions granted to a role
+func Permissions(role string) []string {
+	return append([]string(nil), rolePermissions[role]...)
+}
@@ -1,15 +1,22 @@
 package response
 
 import (
+	"e-procurement/pkg/apperror"
 	"encoding/json"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 )
 
 type ApiResponse struct {
 	Status  string      `json:"status"`
 	Message string      `json:"message"`
+	Code    string      `json:"code,omitempty"` // machine readable error code
 	Data    interface{} `json:"data,omitempty"` // bisa nil untuk error
 	Token  string      	`json:"token,omitempty"` // optional token field for authentication responses
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"` // access token lifetime in seconds
 	Meta 	*Meta		`json:"meta,omitempty"`
 }
 
@@ -39,6 +46,21 @@ func Success(w http.ResponseWriter, message string, data interface{}, meta *Meta
     })
 }
 
+// function for sending authentication responses carrying an access and refresh token
+func SuccessWithTokens(w http.ResponseWriter, message string, data interface{}, accessToken, refreshToken string, expiresIn int64) {
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusOK)
+
+    json.NewEncoder(w).Encode(ApiResponse{
+        Status:       "success",
+        Message:      message,
+        Data:         data,
+        Token:        accessToken,
+        RefreshToken: refreshToken,
+        ExpiresIn:    expiresIn,
+    })
+}
+
 func Error(w http.ResponseWriter, code int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
@@ -49,3 +71,46 @@ func Error(w http.ResponseWriter, code int, message string) {
 		Meta:    nil, // 
 	})
 }
+
+// map of domain error kinds to HTTP status codes
+var statusByKind = map[apperror.Kind]int{
+	apperror.KindNotFound:        http.StatusNotFound,
+	apperror.KindConflict:        http.StatusConflict,
+	apperror.KindForbidden:       http.StatusForbidden,
+	apperror.KindValidation:      http.StatusUnprocessableEntity,
+	apperror.KindUnauthorized:    http.StatusUnauthorized,
+	apperror.KindTooManyRequests: http.StatusTooManyRequests,
+	apperror.KindLocked:          http.StatusLocked,
+}
+
+// function for sending an error returned by usecases,
+// domain errors are mapped to their status code and error code, anything else is a 500
+func FromError(w http.ResponseWriter, err error) {
+	appErr, ok := apperror.As(err)
+	if !ok {
+		log.Printf("internal error: %v", err)
+		ErrorWithCode(w, http.StatusInternalServerError, "internal_error", "internal server error")
+		return
+	}
+
+	status, ok := statusByKind[appErr.Kind]
+	if !ok {
+		log.Printf("internal error: %v", err)
+		status = http.StatusInternalServerError
+	}
+	if appErr.RetryAfter > 0 {
+		// whole seconds, rounded up so clients never retry too early
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(appErr.RetryAfter.Seconds()))))
+	}
+	ErrorWithCode(w, status, appErr.Code, appErr.Message)
+}
+
+func ErrorWithCode(w http.ResponseWriter, status int, code string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ApiResponse{
+		Status:  "error",
+		Code:    code,
+		Message: message,
+	})
+}